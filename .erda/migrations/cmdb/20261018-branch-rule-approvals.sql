@@ -0,0 +1,3 @@
+ALTER TABLE `dice_branch_rules` ADD `min_approvals` int(11) NOT NULL DEFAULT 0 COMMENT '合并请求合入前需要的最少评审通过数';
+ALTER TABLE `dice_branch_rules` ADD `require_code_owner_approval` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否需要 CODEOWNERS 负责人评审通过';
+ALTER TABLE `dice_branch_rules` ADD `dismiss_stale_approvals` tinyint(1) NOT NULL DEFAULT 0 COMMENT '源分支有新提交时评审通过是否失效';
@@ -0,0 +1,2 @@
+ALTER TABLE `dice_branch_rules` ADD `required_checks` varchar(255) NOT NULL DEFAULT '' COMMENT '合并请求合入前必须成功的检查，逗号分隔';
+ALTER TABLE `dice_branch_rules` ADD `strict_required_checks` tinyint(1) NOT NULL DEFAULT 0 COMMENT '必须检查是否需在源分支最新提交上运行';
@@ -0,0 +1,25 @@
+ALTER TABLE `dice_repos` ADD `lfs_quota` bigint(20) NOT NULL DEFAULT '0' COMMENT 'LFS 容量上限，0 表示使用全局默认值，小于0表示不限制';
+
+CREATE TABLE `dice_repo_lfs_objects`
+(
+    `id`         bigint(20) NOT NULL AUTO_INCREMENT,
+    `repo_id`    bigint(20) NOT NULL,
+    `oid`        varchar(64) NOT NULL COMMENT 'sha256',
+    `size`       bigint(20) NOT NULL DEFAULT '0',
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_repo_oid` (`repo_id`, `oid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Gittar 仓库引用的 LFS 对象';
+
+CREATE TABLE `dice_repo_lfs_locks`
+(
+    `id`         bigint(20) NOT NULL AUTO_INCREMENT,
+    `repo_id`    bigint(20) NOT NULL,
+    `path`       varchar(255) NOT NULL,
+    `ref`        varchar(255) NOT NULL DEFAULT '',
+    `owner_id`   varchar(255) NOT NULL DEFAULT '',
+    `owner_name` varchar(255) NOT NULL DEFAULT '',
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_repo_path` (`repo_id`, `path`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Gittar LFS 文件锁';
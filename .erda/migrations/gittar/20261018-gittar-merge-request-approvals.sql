@@ -0,0 +1,14 @@
+CREATE TABLE `dice_repo_merge_request_approvals`
+(
+    `id`         bigint(20)   NOT NULL AUTO_INCREMENT,
+    `created_at` datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '评审时间',
+    `repo_id`    bigint(20)   NOT NULL COMMENT '仓库 id',
+    `merge_id`   bigint(20)   NOT NULL COMMENT '合并请求 id',
+    `user_id`    varchar(64)  NOT NULL COMMENT '评审人 id',
+    `user_name`  varchar(255) NOT NULL DEFAULT '' COMMENT '评审人用户名',
+    `email`      varchar(255) NOT NULL DEFAULT '' COMMENT '评审人邮箱',
+    `commit_sha` varchar(64)  NOT NULL DEFAULT '' COMMENT '评审时源分支的提交',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_merge_user` (`merge_id`, `user_id`),
+    KEY `idx_repo_id` (`repo_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COMMENT ='合并请求评审通过记录';
@@ -0,0 +1,3 @@
+ALTER TABLE `dice_repos` ADD `merge_strategies` varchar(64) NOT NULL DEFAULT '' COMMENT '允许的合并方式，逗号分隔，为空表示全部允许';
+ALTER TABLE `dice_repos` ADD `default_merge_strategy` varchar(16) NOT NULL DEFAULT '' COMMENT '默认合并方式';
+ALTER TABLE `dice_repo_merge_requests` ADD `merge_strategy` varchar(16) NOT NULL DEFAULT '' COMMENT '合并方式: merge, squash, rebase, ff-only';
@@ -0,0 +1,24 @@
+CREATE TABLE `dice_repo_mirrors`
+(
+    `id`              bigint(20)   NOT NULL AUTO_INCREMENT,
+    `repo_id`         bigint(20)   NOT NULL COMMENT '仓库 id',
+    `direction`       varchar(16)  NOT NULL COMMENT 'pull: 外部仓库同步到 gittar，push: gittar 推送到外部仓库',
+    `url`             varchar(512) NOT NULL COMMENT '外部仓库地址',
+    `username`        varchar(255) NOT NULL DEFAULT '' COMMENT '外部仓库用户名',
+    `password`        varchar(512) NOT NULL DEFAULT '' COMMENT '加密后的外部仓库密码或 token',
+    `ref_filters`     varchar(1024) NOT NULL DEFAULT '' COMMENT '逗号分隔的分支或标签过滤规则，为空表示全部',
+    `sync_interval`   int(11)      NOT NULL DEFAULT '60' COMMENT '同步间隔，单位分钟',
+    `enabled`         tinyint(1)   NOT NULL DEFAULT '1' COMMENT '是否定时同步',
+    `status`          varchar(16)  NOT NULL DEFAULT 'pending' COMMENT '同步状态: pending, syncing, success, failed',
+    `last_error`      text COMMENT '最近一次同步失败的原因',
+    `last_sync_at`    datetime              DEFAULT NULL COMMENT '最近一次同步时间',
+    `last_success_at` datetime              DEFAULT NULL COMMENT '最近一次同步成功时间',
+    `next_sync_at`    datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次同步时间',
+    `sync_started_at` datetime              DEFAULT NULL COMMENT '认领同步的时间',
+    `creator_id`      varchar(64)  NOT NULL DEFAULT '' COMMENT '创建人',
+    `created_at`      datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`      datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_repo_id` (`repo_id`),
+    KEY `idx_next_sync_at` (`next_sync_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COMMENT ='Gittar 仓库的拉取和推送镜像';
@@ -0,0 +1 @@
+ALTER TABLE `dice_repo_mirrors` ADD `mirrored_refs` mediumtext COMMENT '镜像上次同步写入目标端的引用及提交，json 格式，只有这些引用会被镜像删除';
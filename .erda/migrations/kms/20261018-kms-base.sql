@@ -0,0 +1,36 @@
+CREATE TABLE `kms_keys`
+(
+    `id`                     VARCHAR(36)  NOT NULL COMMENT 'key id',
+    `created_at`             DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`             DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    `plugin_kind`            VARCHAR(32)  NOT NULL DEFAULT '' COMMENT 'kms 插件类型, DICE_KMS ...',
+    `primary_key_version_id` VARCHAR(36)  NOT NULL DEFAULT '' COMMENT '主密钥版本 id',
+    `key_spec`               VARCHAR(32)  NOT NULL DEFAULT '' COMMENT '密钥规格, SYMMETRIC_DEFAULT, RSA_2048 ...',
+    `key_usage`              VARCHAR(32)  NOT NULL DEFAULT '' COMMENT '密钥用途, ENCRYPT_DECRYPT, SIGN_VERIFY',
+    `key_state`              VARCHAR(32)  NOT NULL DEFAULT '' COMMENT '密钥状态, Enabled, Disabled, PendingDeletion ...',
+    `description`            VARCHAR(512) NOT NULL DEFAULT '' COMMENT '描述',
+    `rotation_interval_sec`  BIGINT       NOT NULL DEFAULT 0 COMMENT '自动轮转周期(秒), 0 表示不自动轮转',
+    `next_rotation_at`       DATETIME     NOT NULL DEFAULT '1970-01-01 00:00:00' COMMENT '下次自动轮转时间, 1970-01-01 00:00:00 表示无',
+    `deletion_at`            DATETIME     NOT NULL DEFAULT '1970-01-01 00:00:00' COMMENT '计划删除时间, 1970-01-01 00:00:00 表示无',
+    PRIMARY KEY (`id`),
+    INDEX `idx_plugin_kind` (`plugin_kind`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+    COMMENT 'KMS 用户主密钥 (CMK)';
+
+CREATE TABLE `kms_key_versions`
+(
+    `id`                   VARCHAR(36) NOT NULL COMMENT 'key version id',
+    `created_at`           DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`           DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    `key_id`               VARCHAR(36) NOT NULL DEFAULT '' COMMENT 'key id',
+    `symmetric_key_base64` TEXT        NOT NULL COMMENT '对称密钥, base64 编码',
+    `public_key_base64`    TEXT        NOT NULL COMMENT '非对称公钥, base64 编码',
+    `private_key_base64`   TEXT        NOT NULL COMMENT '非对称私钥, base64 编码',
+    PRIMARY KEY (`id`),
+    INDEX `idx_key_id` (`key_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+    COMMENT 'KMS 用户主密钥版本';
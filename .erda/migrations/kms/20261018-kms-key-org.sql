@@ -0,0 +1,2 @@
+ALTER TABLE `kms_keys`
+    ADD COLUMN `org_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '密钥所属企业 id, 为空表示不属于任何企业' AFTER `description`;
@@ -0,0 +1,2 @@
+ALTER TABLE `kms_keys`
+    ADD COLUMN `project_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '密钥所属项目 id, 为空表示不属于任何项目' AFTER `org_id`;
@@ -0,0 +1 @@
+ALTER TABLE `pipeline_crons` ADD COLUMN `cron_timezone` VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'IANA 时区，为空时使用服务端时区';
@@ -0,0 +1,22 @@
+ALTER TABLE `dice_pipeline_lifecycle_hook_clients` ADD COLUMN `secret` varchar(255) NOT NULL DEFAULT '' COMMENT 'HMAC 签名密钥，为空时不签名';
+ALTER TABLE `dice_pipeline_lifecycle_hook_clients` ADD COLUMN `max_retries` int(11) NOT NULL DEFAULT '0' COMMENT '失败重试次数';
+ALTER TABLE `dice_pipeline_lifecycle_hook_clients` ADD COLUMN `retry_interval_ms` int(11) NOT NULL DEFAULT '0' COMMENT '首次重试间隔（毫秒），之后指数退避';
+
+CREATE TABLE `pipeline_lifecycle_hook_deliveries` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `delivery_id` varchar(36) NOT NULL DEFAULT '' COMMENT '投递 id，同一次投递的多次重试共享',
+  `pipeline_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '流水线 id',
+  `client_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'hook client 名称',
+  `hook` varchar(64) NOT NULL DEFAULT '' COMMENT 'hook 类型',
+  `url` varchar(512) NOT NULL DEFAULT '' COMMENT '请求地址',
+  `attempt` int(11) NOT NULL DEFAULT '0' COMMENT '第几次尝试，从 1 开始',
+  `request` longtext NOT NULL COMMENT '请求体',
+  `response_status` int(11) NOT NULL DEFAULT '0' COMMENT '响应状态码',
+  `response` longtext NOT NULL COMMENT '响应体',
+  `error` text NOT NULL COMMENT '错误信息',
+  `latency_ms` bigint(20) NOT NULL DEFAULT '0' COMMENT '耗时（毫秒）',
+  `time_created` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_pipeline_id` (`pipeline_id`),
+  KEY `idx_delivery_id` (`delivery_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='pipeline lifecycle hook 投递记录';
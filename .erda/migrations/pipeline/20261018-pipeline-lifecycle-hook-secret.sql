@@ -0,0 +1,3 @@
+ALTER TABLE `dice_pipeline_lifecycle_hook_clients` MODIFY COLUMN `secret` varchar(1024) NOT NULL DEFAULT '' COMMENT 'HMAC 签名密钥，rsa 加密后 base64 编码，为空时不签名';
+ALTER TABLE `pipeline_lifecycle_hook_deliveries` MODIFY COLUMN `attempt` int(11) NOT NULL DEFAULT '0' COMMENT '尝试次数，只记录最终结果';
+ALTER TABLE `pipeline_lifecycle_hook_deliveries` ADD INDEX `idx_time_created` (`time_created`);
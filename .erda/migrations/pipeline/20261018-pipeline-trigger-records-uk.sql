@@ -0,0 +1,3 @@
+ALTER TABLE `pipeline_trigger_records`
+    MODIFY COLUMN `status` varchar(32) NOT NULL DEFAULT '' COMMENT '触发结果：Pending, Created, Failed',
+    ADD UNIQUE KEY `uk_downstream_definition_upstream_pipeline` (`downstream_definition_id`, `upstream_pipeline_id`);
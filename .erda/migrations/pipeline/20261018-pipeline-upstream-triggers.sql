@@ -0,0 +1,31 @@
+CREATE TABLE `pipeline_upstream_triggers` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `pipeline_definition_id` varchar(36) NOT NULL DEFAULT '' COMMENT '下游流水线定义 id',
+  `project_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '下游流水线所属项目 id，上游必须属于同一项目',
+  `upstream_source` varchar(32) NOT NULL DEFAULT '' COMMENT '上游流水线来源',
+  `upstream_yml_name` varchar(255) NOT NULL DEFAULT '' COMMENT '上游流水线 pipelineYmlName，支持后缀匹配',
+  `branches` text NOT NULL COMMENT '上游流水线分支，json 数组，为空不限制',
+  `statuses` varchar(255) NOT NULL DEFAULT '' COMMENT '触发的上游结束状态，json 数组',
+  `params` text NOT NULL COMMENT '下游参数名 -> 上游 output 名，json 对象',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_upstream` (`upstream_source`, `project_id`),
+  KEY `idx_pipeline_definition_id` (`pipeline_definition_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='流水线 on.pipeline 触发订阅';
+
+CREATE TABLE `pipeline_trigger_records` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `trigger_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'pipeline_upstream_triggers id',
+  `upstream_pipeline_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '上游流水线 id',
+  `downstream_pipeline_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '下游流水线 id，创建失败时为 0',
+  `downstream_definition_id` varchar(36) NOT NULL DEFAULT '' COMMENT '下游流水线定义 id',
+  `depth` int(11) NOT NULL DEFAULT '0' COMMENT '下游流水线在触发链中的深度，从 1 开始',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT '触发结果：Created, Failed',
+  `params` text NOT NULL COMMENT '传递给下游的运行参数，json',
+  `error` text NOT NULL COMMENT '错误信息',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_upstream_pipeline_id` (`upstream_pipeline_id`),
+  KEY `idx_downstream_pipeline_id` (`downstream_pipeline_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='流水线 on.pipeline 触发记录';
@@ -29,6 +29,16 @@ type BranchRule struct {
 	Workspace string `json:"workspace"`
 	// 制品可部署的环境
 	ArtifactWorkspace string `json:"artifactWorkspace"`
+	// 合并请求合入前需要的最少评审通过数
+	MinApprovals int `json:"minApprovals"`
+	// 合并请求需要 CODEOWNERS 中对应负责人评审通过
+	RequireCodeOwnerApproval bool `json:"requireCodeOwnerApproval"`
+	// 源分支有新的提交时，已有的评审通过失效
+	DismissStaleApprovals bool `json:"dismissStaleApprovals"`
+	// 合并请求合入前必须成功的检查，逗号分隔 eg:golang-lint,unit-test
+	RequiredChecks string `json:"requiredChecks"`
+	// 必须检查需在源分支最新提交上运行
+	StrictRequiredChecks bool `json:"strictRequiredChecks"`
 }
 type QueryBranchRuleRequest struct {
 	ProjectID int64 `query:"projectId"`
@@ -50,6 +60,12 @@ type CreateBranchRuleRequest struct {
 	Workspace         string    `json:"workspace"`
 	ArtifactWorkspace string    `json:"artifactWorkspace"`
 	Desc              string    `json:"desc"`
+
+	MinApprovals             int    `json:"minApprovals"`
+	RequireCodeOwnerApproval bool   `json:"requireCodeOwnerApproval"`
+	DismissStaleApprovals    bool   `json:"dismissStaleApprovals"`
+	RequiredChecks           string `json:"requiredChecks"`
+	StrictRequiredChecks     bool   `json:"strictRequiredChecks"`
 }
 
 type CreateBranchRuleResponse struct {
@@ -66,6 +82,12 @@ type UpdateBranchRuleRequest struct {
 	Desc              string `json:"desc"`
 	Workspace         string `json:"workspace"`
 	ArtifactWorkspace string `json:"artifactWorkspace"`
+
+	MinApprovals             int    `json:"minApprovals"`
+	RequireCodeOwnerApproval bool   `json:"requireCodeOwnerApproval"`
+	DismissStaleApprovals    bool   `json:"dismissStaleApprovals"`
+	RequiredChecks           string `json:"requiredChecks"`
+	StrictRequiredChecks     bool   `json:"strictRequiredChecks"`
 }
 
 type UpdateBranchRuleResponse struct {
@@ -17,6 +17,8 @@ package apistructs
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Request for API: `GET /api/deployments`
@@ -69,6 +71,8 @@ type Deployment struct {
 	CreatedAt      time.Time  `json:"createdAt"`
 	FinishedAt     *time.Time `json:"finishedAt"`
 	RollbackFrom   uint64     `json:"rollbackFrom"`
+
+	Canary *DeploymentCanaryProgress `json:"canary,omitempty"`
 }
 
 type DeploymentDetailListResponse struct {
@@ -123,3 +127,89 @@ type DeploymentStatusResponse struct {
 	Header
 	Data DeploymentStatusDTO `json:"data"`
 }
+
+// DeploymentCanaryStrategy the new version is deployed as canary workloads besides the stable ones,
+// and the traffic is shifted to them step by step, the deployment is rolled back if criteria fail
+type DeploymentCanaryStrategy struct {
+	// Weights traffic weight of canary in each step, in percent, e.g. [10, 30, 60]
+	Weights []int `json:"weights"`
+	// StepInterval seconds to observe each step before evaluating, 300 by default, at most MaxCanaryStepInterval
+	StepInterval int `json:"stepInterval,omitempty"`
+	// MaxErrorRate max error rate of requests, in percent, not checked if 0
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty"`
+	// MaxLatency max average latency of requests, in milliseconds, not checked if 0
+	MaxLatency float64 `json:"maxLatency,omitempty"`
+	// MinRequests requests the canary must receive before the step is evaluated, 100 by default,
+	// the step keeps observing until enough requests are collected
+	MinRequests int `json:"minRequests,omitempty"`
+}
+
+// MaxCanaryStepInterval max seconds of canary step interval,
+// it must be less than the timeout of deployment (1 hour) which is refreshed only when the progress is saved
+const MaxCanaryStepInterval = 1800
+
+// Validate .
+func (s *DeploymentCanaryStrategy) Validate() error {
+	if len(s.Weights) == 0 {
+		return errors.New("canary weights empty")
+	}
+	for i, w := range s.Weights {
+		if w <= 0 || w >= 100 {
+			return errors.Errorf("invalid canary weight %d, must be in (0, 100)", w)
+		}
+		if i > 0 && w <= s.Weights[i-1] {
+			return errors.New("canary weights must be ascending")
+		}
+	}
+	if s.StepInterval < 0 || s.MaxErrorRate < 0 || s.MaxLatency < 0 || s.MinRequests < 0 {
+		return errors.New("canary step interval and criteria must not be negative")
+	}
+	if s.StepInterval > MaxCanaryStepInterval {
+		return errors.Errorf("canary step interval must not exceed %d seconds", MaxCanaryStepInterval)
+	}
+	return nil
+}
+
+type DeploymentCanaryStepStatus string
+
+const (
+	DeploymentCanaryStepRunning   DeploymentCanaryStepStatus = "Running"
+	DeploymentCanaryStepSucceeded DeploymentCanaryStepStatus = "Succeeded"
+	DeploymentCanaryStepFailed    DeploymentCanaryStepStatus = "Failed"
+	DeploymentCanaryStepAborted   DeploymentCanaryStepStatus = "Aborted"
+)
+
+// DeploymentCanaryStep one step of canary rollout
+type DeploymentCanaryStep struct {
+	Weight int                        `json:"weight"`
+	Status DeploymentCanaryStepStatus `json:"status"`
+	// Requests, ErrorRate and Latency are metrics of the service during the step
+	Requests   float64    `json:"requests"`
+	ErrorRate  float64    `json:"errorRate"`
+	Latency    float64    `json:"latency"`
+	Message    string     `json:"message,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// DeploymentCanaryProgress progress of canary rollout recorded on the deployment
+type DeploymentCanaryProgress struct {
+	Strategy DeploymentCanaryStrategy `json:"strategy"`
+	Steps    []DeploymentCanaryStep   `json:"steps,omitempty"`
+	// Promoted all steps succeeded and the stable workloads are updated
+	Promoted bool `json:"promoted,omitempty"`
+	// AbortedBy operator who aborts the canary
+	AbortedBy string `json:"abortedBy,omitempty"`
+}
+
+// CurrentStep returns the last step, nil if not started
+func (p *DeploymentCanaryProgress) CurrentStep() *DeploymentCanaryStep {
+	if len(p.Steps) == 0 {
+		return nil
+	}
+	return &p.Steps[len(p.Steps)-1]
+}
+
+type DeploymentCanaryAbortResponse struct {
+	Header
+}
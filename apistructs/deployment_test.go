@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistructs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentCanaryStrategy_Validate(t *testing.T) {
+	assert.NoError(t, (&DeploymentCanaryStrategy{Weights: []int{10, 50}}).Validate())
+	assert.Error(t, (&DeploymentCanaryStrategy{}).Validate())
+	assert.Error(t, (&DeploymentCanaryStrategy{Weights: []int{10, 100}}).Validate())
+	assert.Error(t, (&DeploymentCanaryStrategy{Weights: []int{0, 50}}).Validate())
+	assert.Error(t, (&DeploymentCanaryStrategy{Weights: []int{50, 10}}).Validate())
+	assert.Error(t, (&DeploymentCanaryStrategy{Weights: []int{10}, MaxErrorRate: -1}).Validate())
+	assert.Error(t, (&DeploymentCanaryStrategy{Weights: []int{10}, MinRequests: -1}).Validate())
+	assert.NoError(t, (&DeploymentCanaryStrategy{Weights: []int{10}, StepInterval: MaxCanaryStepInterval}).Validate())
+	assert.Error(t, (&DeploymentCanaryStrategy{Weights: []int{10}, StepInterval: MaxCanaryStepInterval + 1}).Validate())
+}
+
+func TestDeploymentCanaryProgress_CurrentStep(t *testing.T) {
+	p := &DeploymentCanaryProgress{}
+	assert.Nil(t, p.CurrentStep())
+
+	p.Steps = []DeploymentCanaryStep{{Weight: 10}, {Weight: 50}}
+	p.CurrentStep().Status = DeploymentCanaryStepRunning
+	assert.Equal(t, 50, p.CurrentStep().Weight)
+	assert.Equal(t, DeploymentCanaryStepRunning, p.Steps[1].Status)
+}
@@ -222,6 +222,9 @@ type GittarMergeStatusRequest struct {
 
 	//将要合并到的目标分支
 	TargetBranch string `query:"targetBranch"`
+
+	//合并方式，为空时使用仓库默认方式
+	Strategy string `query:"strategy"`
 }
 
 // GittarMergeStatusResponse GET /<projectName>/<appName>/merge-stats merge状态检测
@@ -232,6 +235,7 @@ type GittarMergeStatusResponse struct {
 
 // GittarMergeStatusData mr状态响应数据
 type GittarMergeStatusData struct {
+	Strategy    string `json:"strategy"`
 	HasConflict bool   `json:"hasConflict"`
 	IsMerged    bool   `json:"isMerged"`
 	HasError    bool   `json:"hasError"`
@@ -246,6 +250,7 @@ type GittarCreateMergeRequest struct {
 	SourceBranch       string `json:"sourceBranch"`
 	TargetBranch       string `json:"targetBranch"`
 	RemoveSourceBranch bool   `json:"removeSourceBranch"`
+	MergeStrategy      string `json:"mergeStrategy"`
 }
 
 type MergeOperationTempBranchOperationType string
@@ -309,9 +314,11 @@ type MergeRequestInfo struct {
 	CheckRuns            CheckRuns    `json:"checkRuns,omitempty"`
 	JoinTempBranchStatus string       `json:"joinTempBranchStatus"`
 	IsJoinTempBranch     bool         `json:"isJoinTempBranch"`
+	MergeStrategy        string       `json:"mergeStrategy"` // merge, squash, rebase, ff-only，为空时使用仓库默认方式
 }
 
 type MergeStatusInfo struct {
+	Strategy    string `json:"strategy"`
 	HasConflict bool   `json:"hasConflict"`
 	IsMerged    bool   `json:"isMerged"`
 	HasError    bool   `json:"hasError"`
@@ -769,6 +776,9 @@ type CheckRuns struct {
 	CheckRun []*CheckRun    `json:"checkrun"`
 	Result   CheckRunResult `json:"result"`
 	Mergable bool           `json:"mergable"`
+	// 目标分支规则要求的检查，及其未满足的原因
+	RequiredChecks []string `json:"requiredChecks"`
+	Reasons        []string `json:"reasons"`
 }
 
 // CreateCheckRunResponse
@@ -820,6 +830,127 @@ type GittarMergeWithBranchRequest struct {
 	SourceBranch string `json:"sourceBranch"`
 	TargetBranch string `json:"targetBranch"`
 	AppID        uint64 `json:"appID"`
+	Strategy     string `json:"strategy"`
+}
+
+// GittarMergeSettings 仓库允许的合并方式
+type GittarMergeSettings struct {
+	// AllowedStrategies 允许的合并方式，为空表示全部允许
+	AllowedStrategies []string `json:"allowedStrategies"`
+	// DefaultStrategy 合并请求未指定方式时使用，为空时取 AllowedStrategies 中的第一个
+	DefaultStrategy string `json:"defaultStrategy"`
+}
+
+// GittarLFSStats 仓库的 LFS 使用情况
+type GittarLFSStats struct {
+	Objects int64 `json:"objects"`
+	// Usage 已使用容量，单位Byte
+	Usage int64 `json:"usage"`
+	// Quota 容量上限，单位Byte，小于0表示不限制
+	Quota int64 `json:"quota"`
+}
+
+// GittarUpdateLFSQuotaRequest PUT /<projectName>/<appName>/lfs-quota
+type GittarUpdateLFSQuotaRequest struct {
+	// Quota 0 表示使用全局默认值，小于0表示不限制
+	Quota int64 `json:"quota"`
+}
+
+// GittarLFSStatsResponse GET /<projectName>/<appName>/lfs-stats
+type GittarLFSStatsResponse struct {
+	Header
+	Data *GittarLFSStats `json:"data"`
+}
+
+// GittarRepoMirror 仓库镜像，认证信息只返回是否已配置密码
+type GittarRepoMirror struct {
+	ID int64 `json:"id"`
+	// Direction pull: 外部仓库同步到 gittar，push: gittar 推送到外部仓库
+	Direction   string `json:"direction"`
+	URL         string `json:"url"`
+	Username    string `json:"username"`
+	HasPassword bool   `json:"hasPassword"`
+	// RefFilters 需要同步的分支或标签，为空表示全部
+	RefFilters []string `json:"refFilters"`
+	// Interval 同步间隔，单位分钟
+	Interval int  `json:"interval"`
+	Enabled  bool `json:"enabled"`
+	// Status pending/syncing/success/failed
+	Status        string     `json:"status"`
+	LastError     string     `json:"lastError"`
+	LastSyncAt    *time.Time `json:"lastSyncAt"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt"`
+	NextSyncAt    time.Time  `json:"nextSyncAt"`
+	CreatorID     string     `json:"creatorId"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// GittarRepoMirrorRequest 创建或更新仓库镜像
+// 更新时 Password 为空表示保持不变，Username 为空时同时清除密码
+type GittarRepoMirrorRequest struct {
+	Direction  string   `json:"direction"`
+	URL        string   `json:"url"`
+	Username   string   `json:"username"`
+	Password   string   `json:"password"`
+	RefFilters []string `json:"refFilters"`
+	Interval   int      `json:"interval"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+// GittarRepoMirrorResponse POST/PUT /<projectName>/<appName>/mirrors
+type GittarRepoMirrorResponse struct {
+	Header
+	Data *GittarRepoMirror `json:"data"`
+}
+
+// GittarListRepoMirrorsResponse GET /<projectName>/<appName>/mirrors
+type GittarListRepoMirrorsResponse struct {
+	Header
+	Data []*GittarRepoMirror `json:"data"`
+}
+
+// GittarMergeSettingsResponse GET/PUT /<projectName>/<appName>/merge-settings 仓库合并方式配置
+type GittarMergeSettingsResponse struct {
+	Header
+	Data *GittarMergeSettings `json:"data"`
+}
+
+// MergeRequestApproval 合并请求的评审通过记录
+type MergeRequestApproval struct {
+	UserID    string `json:"userId"`
+	UserName  string `json:"userName"`
+	Email     string `json:"email"`
+	CommitSha string `json:"commitSha"`
+	// Stale 评审后源分支有新的提交
+	Stale     bool      `json:"stale"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CodeOwnerRule CODEOWNERS 中尚待负责人评审的规则
+type CodeOwnerRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+// MergeRequestApprovalStatus 合并请求的评审状态
+type MergeRequestApprovalStatus struct {
+	Approvals []*MergeRequestApproval `json:"approvals"`
+	// MinApprovals 目标分支规则要求的最少评审通过数
+	MinApprovals int `json:"minApprovals"`
+	// Approved 有效的评审通过数
+	Approved                 int  `json:"approved"`
+	RequireCodeOwnerApproval bool `json:"requireCodeOwnerApproval"`
+	// MissingCodeOwners 尚无负责人评审通过的 CODEOWNERS 规则
+	MissingCodeOwners []*CodeOwnerRule `json:"missingCodeOwners"`
+	// Satisfied 是否满足合入条件
+	Satisfied bool `json:"satisfied"`
+}
+
+// GittarMergeRequestApprovalResponse GET /<projectName>/<appName>/merge-requests/<id>/approvals
+type GittarMergeRequestApprovalResponse struct {
+	Header
+	Data *MergeRequestApprovalStatus `json:"data"`
 }
 
 type MergeWithBranchResponse struct {
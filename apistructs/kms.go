@@ -71,3 +71,93 @@ type KMSDescribeKeyResponse struct {
 	Header
 	Data *kmstypes.DescribeKeyResponse `json:"data,omitempty"`
 }
+
+// get public key
+type KMSGetPublicKeyRequest struct {
+	kmstypes.GetPublicKeyRequest
+}
+type KMSGetPublicKeyResponse struct {
+	Header
+	Data *kmstypes.PublicKey `json:"data,omitempty"`
+}
+
+// asymmetric decrypt
+type KMSAsymmetricDecryptRequest struct {
+	kmstypes.AsymmetricDecryptRequest
+}
+type KMSAsymmetricDecryptResponse struct {
+	Header
+	Data *kmstypes.AsymmetricDecryptResponse `json:"data,omitempty"`
+}
+
+// sign
+type KMSSignRequest struct {
+	kmstypes.SignRequest
+}
+type KMSSignResponse struct {
+	Header
+	Data *kmstypes.SignResponse `json:"data,omitempty"`
+}
+
+// verify
+type KMSVerifyRequest struct {
+	kmstypes.VerifyRequest
+}
+type KMSVerifyResponse struct {
+	Header
+	Data *kmstypes.VerifyResponse `json:"data,omitempty"`
+}
+
+// re-encrypt
+type KMSReEncryptRequest struct {
+	kmstypes.ReEncryptRequest
+}
+type KMSReEncryptResponse struct {
+	Header
+	Data *kmstypes.ReEncryptResponse `json:"data,omitempty"`
+}
+
+// enable key
+type KMSEnableKeyRequest struct {
+	kmstypes.EnableKeyRequest
+}
+type KMSEnableKeyResponse struct {
+	Header
+	Data *kmstypes.KeyLifecycleResponse `json:"data,omitempty"`
+}
+
+// disable key
+type KMSDisableKeyRequest struct {
+	kmstypes.DisableKeyRequest
+}
+type KMSDisableKeyResponse struct {
+	Header
+	Data *kmstypes.KeyLifecycleResponse `json:"data,omitempty"`
+}
+
+// schedule key deletion
+type KMSScheduleKeyDeletionRequest struct {
+	kmstypes.ScheduleKeyDeletionRequest
+}
+type KMSScheduleKeyDeletionResponse struct {
+	Header
+	Data *kmstypes.KeyLifecycleResponse `json:"data,omitempty"`
+}
+
+// cancel key deletion
+type KMSCancelKeyDeletionRequest struct {
+	kmstypes.CancelKeyDeletionRequest
+}
+type KMSCancelKeyDeletionResponse struct {
+	Header
+	Data *kmstypes.KeyLifecycleResponse `json:"data,omitempty"`
+}
+
+// update rotation policy
+type KMSUpdateRotationPolicyRequest struct {
+	kmstypes.UpdateRotationPolicyRequest
+}
+type KMSUpdateRotationPolicyResponse struct {
+	Header
+	Data *kmstypes.KeyLifecycleResponse `json:"data,omitempty"`
+}
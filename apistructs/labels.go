@@ -38,11 +38,14 @@ const (
 	LabelPipelineCronTriggerTime = "pipelineCronTriggerTime"
 	LabelPipelineCronID          = "pipelineCronID"
 	LabelPipelineCronCompensated = "cronCompensated"
+	LabelUpstreamPipelineID      = "upstreamPipelineID"
 
 	LabelBindPipelineQueueID               = "__bind_queue_id"
 	LabelBindPipelineQueueCustomPriority   = "__bind_queue_custom_priority"
 	LabelBindPipelineQueueEnqueueCondition = "__bind_queue_enqueue_condition"
 
+	LabelPipelineConcurrencyGroup = "__concurrency_group"
+
 	LabelUserID = "userID"
 
 	LabelRunUserID    = "runUserID"
@@ -65,3 +65,15 @@ type HostMetric struct {
 	Disk   float64 // 百分比
 	Load   float64
 }
+
+// MetricQueryDictResponse 以 format=dict 查询 influxql 的响应
+/*
+"data": [{
+	"sum.elapsed_count": 120,
+	"sum.errors_sum": 2
+}]
+*/
+type MetricQueryDictResponse struct {
+	Header
+	Data []map[string]interface{} `json:"data"`
+}
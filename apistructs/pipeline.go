@@ -840,6 +840,13 @@ type ValidBranch struct {
 	Workspace string `json:"workspace"`
 	// 制品可部署的环境
 	ArtifactWorkspace string `json:"artifactWorkspace"`
+	// 合并请求的评审规则
+	MinApprovals             int  `json:"minApprovals"`
+	RequireCodeOwnerApproval bool `json:"requireCodeOwnerApproval"`
+	DismissStaleApprovals    bool `json:"dismissStaleApprovals"`
+	// 合并请求合入前必须成功的检查，逗号分隔
+	RequiredChecks       string `json:"requiredChecks"`
+	StrictRequiredChecks bool   `json:"strictRequiredChecks"`
 }
 
 func (branch *ValidBranch) GetPermissionResource() string {
@@ -850,6 +857,21 @@ func (branch *ValidBranch) GetPermissionResource() string {
 	return resource
 }
 
+// GetRequiredChecks 返回去重后的必须检查名称
+func (branch *ValidBranch) GetRequiredChecks() []string {
+	var checks []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(branch.RequiredChecks, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		checks = append(checks, name)
+	}
+	return checks
+}
+
 type PipelineAppAllValidBranchWorkspaceResponse struct {
 	Header
 	Data []ValidBranch `json:"data"`
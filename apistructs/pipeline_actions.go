@@ -50,6 +50,7 @@ const (
 	ActionCallbackPublishItemID        = "publishItemID"
 	ActionCallbackPublishItemVersionID = "publishItemVersionID"
 	ActionCallbackQaID                 = "qaID"
+	ActionCallbackExitCode             = "exitCode"
 )
 
 // detail
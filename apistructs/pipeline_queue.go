@@ -35,12 +35,44 @@ type PipelineQueueMode string
 var (
 	PipelineQueueModeStrict PipelineQueueMode = "STRICT"
 	PipelineQueueModeLoose  PipelineQueueMode = "LOOSE"
+	// PipelineQueueModeFairShare pending pipelines are ordered by weighted fair share across projects or apps,
+	// and will check next pipeline if current one cannot pop, same as loose mode.
+	PipelineQueueModeFairShare PipelineQueueMode = "FAIR_SHARE"
 )
 
 func (m PipelineQueueMode) String() string { return string(m) }
 func (m PipelineQueueMode) IsValid() bool {
 	switch m {
-	case PipelineQueueModeStrict, PipelineQueueModeLoose:
+	case PipelineQueueModeStrict, PipelineQueueModeLoose, PipelineQueueModeFairShare:
+		return true
+	default:
+		return false
+	}
+}
+
+// fair share config of queue, declared by queue labels
+const (
+	// PipelineQueueLabelFairShareGroupBy group pipelines by project or app, default is project
+	PipelineQueueLabelFairShareGroupBy = "fairShare.groupBy"
+	// PipelineQueueLabelFairShareAgingFactor share decreased per waiting minute to avoid starvation
+	PipelineQueueLabelFairShareAgingFactor = "fairShare.agingFactor"
+	// PipelineQueueLabelFairShareDefaultWeight weight of project or app without declared weight
+	PipelineQueueLabelFairShareDefaultWeight = "fairShare.defaultWeight"
+	// PipelineQueueLabelFairShareWeightPrefix weight of specific project or app, e.g. fairShare.weight.1=3
+	PipelineQueueLabelFairShareWeightPrefix = "fairShare.weight."
+)
+
+type PipelineQueueFairShareGroupBy string
+
+var (
+	PipelineQueueFairShareGroupByProject PipelineQueueFairShareGroupBy = "project"
+	PipelineQueueFairShareGroupByApp     PipelineQueueFairShareGroupBy = "app"
+)
+
+func (g PipelineQueueFairShareGroupBy) String() string { return string(g) }
+func (g PipelineQueueFairShareGroupBy) IsValid() bool {
+	switch g {
+	case PipelineQueueFairShareGroupByProject, PipelineQueueFairShareGroupByApp:
 		return true
 	default:
 		return false
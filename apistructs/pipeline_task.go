@@ -15,6 +15,7 @@
 package apistructs
 
 import (
+	"fmt"
 	"time"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -77,11 +78,12 @@ type PipelineTaskDTO struct {
 }
 
 type PipelineTaskExtra struct {
-	UUID           string                   `json:"uuid"`
-	AllowFailure   bool                     `json:"allowFailure"`
-	TaskContainers []TaskContainer          `json:"taskContainers"`
-	Params         []*TaskParamDetail       `json:"params"`
-	Action         PipelineTaskActionDetail `json:"action"`
+	UUID           string                     `json:"uuid"`
+	AllowFailure   bool                       `json:"allowFailure"`
+	TaskContainers []TaskContainer            `json:"taskContainers"`
+	Params         []*TaskParamDetail         `json:"params"`
+	Action         PipelineTaskActionDetail   `json:"action"`
+	RetryAttempts  []PipelineTaskRetryAttempt `json:"retryAttempts,omitempty"`
 }
 
 type TaskContainer struct {
@@ -198,3 +200,126 @@ var PipelineTaskDefaultLoopStrategy = LoopStrategy{
 	DeclineLimitSec: 60, // 默认衰退最大值为 60s
 	IntervalSec:     2,  // 默认时间间隔为 5s
 }
+
+// TaskFailureClass is the classified reason of a failed task, used to match retry policy.
+type TaskFailureClass string
+
+const (
+	TaskFailureClassOOM       TaskFailureClass = "oom"
+	TaskFailureClassImagePull TaskFailureClass = "image-pull"
+	TaskFailureClassTimeout   TaskFailureClass = "timeout"
+	TaskFailureClassExitCode  TaskFailureClass = "exit-code"
+	TaskFailureClassUnknown   TaskFailureClass = "unknown"
+)
+
+func (c TaskFailureClass) IsValid() bool {
+	switch c {
+	case TaskFailureClassOOM, TaskFailureClassImagePull, TaskFailureClassTimeout, TaskFailureClassExitCode, TaskFailureClassUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaskRetryMaxAttemptsLimit is the max attempts allowed to be declared by retry policy
+const TaskRetryMaxAttemptsLimit = 10
+
+// TaskRetryPolicy declares how to retry a failed task.
+type TaskRetryPolicy struct {
+	MaxAttempts     int                `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`           // 最大执行次数（包含第一次执行）
+	IntervalSec     uint64             `json:"interval_sec,omitempty" yaml:"interval_sec,omitempty"`           // 重试间隔时间
+	DeclineRatio    float64            `json:"decline_ratio,omitempty" yaml:"decline_ratio,omitempty"`         // 重试衰退速率  2s - 4s - 8s - 16s
+	DeclineLimitSec int64              `json:"decline_limit_sec,omitempty" yaml:"decline_limit_sec,omitempty"` // 重试衰退最大值  2s - 4s - 8s - 8s - 8s
+	On              []TaskFailureClass `json:"on,omitempty" yaml:"on,omitempty"`                               // 匹配的失败类型，为空表示任意失败都重试
+	ExitCodes       []int              `json:"exit_codes,omitempty" yaml:"exit_codes,omitempty"`               // 匹配的退出码，仅在失败类型为 exit-code 时生效，为空表示任意非零退出码
+}
+
+var PipelineTaskDefaultRetryPolicy = TaskRetryPolicy{
+	MaxAttempts:     3,  // 默认最多执行 3 次
+	IntervalSec:     5,  // 默认时间间隔为 5s
+	DeclineRatio:    2,  // 默认衰退速率为 2
+	DeclineLimitSec: 60, // 默认衰退最大值为 60s
+}
+
+// Validate validates the declared retry policy.
+func (r *TaskRetryPolicy) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.MaxAttempts < 0 || r.MaxAttempts > TaskRetryMaxAttemptsLimit {
+		return fmt.Errorf("invalid retry max_attempts: %d, should be in [0, %d] and 0 means default", r.MaxAttempts, TaskRetryMaxAttemptsLimit)
+	}
+	if r.DeclineRatio < 0 {
+		return fmt.Errorf("invalid retry decline_ratio: %v", r.DeclineRatio)
+	}
+	if r.DeclineLimitSec < 0 {
+		return fmt.Errorf("invalid retry decline_limit_sec: %d", r.DeclineLimitSec)
+	}
+	for _, class := range r.On {
+		if !class.IsValid() {
+			return fmt.Errorf("invalid retry failure class: %s", class)
+		}
+	}
+	return nil
+}
+
+// WithDefaults returns a copy of retry policy whose unset fields are filled by default values.
+func (r *TaskRetryPolicy) WithDefaults() TaskRetryPolicy {
+	result := PipelineTaskDefaultRetryPolicy
+	if r == nil {
+		return result
+	}
+	if r.MaxAttempts > 0 {
+		result.MaxAttempts = r.MaxAttempts
+	}
+	if r.IntervalSec > 0 {
+		result.IntervalSec = r.IntervalSec
+	}
+	if r.DeclineRatio > 0 {
+		result.DeclineRatio = r.DeclineRatio
+	}
+	if r.DeclineLimitSec > 0 {
+		result.DeclineLimitSec = r.DeclineLimitSec
+	}
+	result.On = r.On
+	result.ExitCodes = r.ExitCodes
+	return result
+}
+
+// Match returns whether the failure should be retried.
+func (r TaskRetryPolicy) Match(class TaskFailureClass, exitCode int) bool {
+	if len(r.On) > 0 {
+		matched := false
+		for _, on := range r.On {
+			if on == class {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if class == TaskFailureClassExitCode && len(r.ExitCodes) > 0 {
+		for _, code := range r.ExitCodes {
+			if code == exitCode {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// PipelineTaskRetryAttempt is the history of one failed attempt of a retried task.
+type PipelineTaskRetryAttempt struct {
+	Attempt      int              `json:"attempt"`
+	UUID         string           `json:"uuid"`
+	Status       PipelineStatus   `json:"status"`
+	FailureClass TaskFailureClass `json:"failureClass"`
+	ExitCode     int              `json:"exitCode,omitempty"`
+	Errors       []string         `json:"errors,omitempty"`
+	TimeBegin    time.Time        `json:"timeBegin"`
+	TimeEnd      time.Time        `json:"timeEnd"`
+	CostTimeSec  int64            `json:"costTimeSec"`
+}
@@ -121,3 +121,69 @@ func Test_PipelineTaskSnippetDetail_Convert2PB(t *testing.T) {
 	assert.Equal(t, int64(1), pbDetail.RecursiveSnippetTasksNum)
 	assert.Equal(t, 1, len(pbDetail.Outputs))
 }
+
+func TestTaskRetryPolicy_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   TaskRetryPolicy
+		class    TaskFailureClass
+		exitCode int
+		want     bool
+	}{
+		{
+			name:   "match any failure",
+			policy: TaskRetryPolicy{},
+			class:  TaskFailureClassUnknown,
+			want:   true,
+		},
+		{
+			name:   "match declared class",
+			policy: TaskRetryPolicy{On: []TaskFailureClass{TaskFailureClassOOM, TaskFailureClassImagePull}},
+			class:  TaskFailureClassImagePull,
+			want:   true,
+		},
+		{
+			name:   "not match undeclared class",
+			policy: TaskRetryPolicy{On: []TaskFailureClass{TaskFailureClassOOM}},
+			class:  TaskFailureClassTimeout,
+			want:   false,
+		},
+		{
+			name:     "match exit code",
+			policy:   TaskRetryPolicy{On: []TaskFailureClass{TaskFailureClassExitCode}, ExitCodes: []int{137, 143}},
+			class:    TaskFailureClassExitCode,
+			exitCode: 143,
+			want:     true,
+		},
+		{
+			name:     "not match exit code",
+			policy:   TaskRetryPolicy{ExitCodes: []int{137}},
+			class:    TaskFailureClassExitCode,
+			exitCode: 1,
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Match(tt.class, tt.exitCode))
+		})
+	}
+}
+
+func TestTaskRetryPolicy_WithDefaults(t *testing.T) {
+	var r *TaskRetryPolicy
+	assert.Equal(t, PipelineTaskDefaultRetryPolicy, r.WithDefaults())
+
+	r = &TaskRetryPolicy{MaxAttempts: 5, On: []TaskFailureClass{TaskFailureClassOOM}}
+	got := r.WithDefaults()
+	assert.Equal(t, 5, got.MaxAttempts)
+	assert.Equal(t, PipelineTaskDefaultRetryPolicy.IntervalSec, got.IntervalSec)
+	assert.Equal(t, []TaskFailureClass{TaskFailureClassOOM}, got.On)
+}
+
+func TestTaskRetryPolicy_Validate(t *testing.T) {
+	assert.NoError(t, (*TaskRetryPolicy)(nil).Validate())
+	assert.NoError(t, (&TaskRetryPolicy{MaxAttempts: 3, On: []TaskFailureClass{TaskFailureClassTimeout}}).Validate())
+	assert.Error(t, (&TaskRetryPolicy{MaxAttempts: TaskRetryMaxAttemptsLimit + 1}).Validate())
+	assert.Error(t, (&TaskRetryPolicy{On: []TaskFailureClass{"disk"}}).Validate())
+}
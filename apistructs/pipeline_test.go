@@ -143,3 +143,9 @@ func TestPostHandlePBQueryString(t *testing.T) {
 	assert.Equal(t, 5, len(req.MustMatchLabelsJSON))
 	assert.Equal(t, 4, len(req.AnyMatchLabelsJSON))
 }
+
+func TestValidBranchGetRequiredChecks(t *testing.T) {
+	branch := &ValidBranch{RequiredChecks: " golang-lint, unit-test,,golang-lint "}
+	assert.Equal(t, []string{"golang-lint", "unit-test"}, branch.GetRequiredChecks())
+	assert.Empty(t, (&ValidBranch{}).GetRequiredChecks())
+}
@@ -27,10 +27,11 @@ import (
 const (
 	ActionSourceType = "action"
 
-	ActionTypeAPITest      = "api-test"
-	ActionTypeSnippet      = "snippet"
-	ActionTypeCustomScript = "custom-script"
-	ActionTypeWait         = "wait"
+	ActionTypeAPITest        = "api-test"
+	ActionTypeSnippet        = "snippet"
+	ActionTypeCustomScript   = "custom-script"
+	ActionTypeWait           = "wait"
+	ActionTypeManualApproval = "manual-approval"
 
 	SnippetSourceLocal = "local"
 )
@@ -47,6 +48,7 @@ type PipelineYml struct {
 	Name            string                 `json:"name"`
 	Envs            map[string]string      `json:"envs,omitempty"`                                             // 环境变量
 	Cron            string                 `json:"cron,omitempty"`                                             // 定时配置
+	CronTimezone    string                 `json:"cronTimezone,omitempty"`                                     // 定时配置时区
 	CronCompensator *pb.CronCompensator    `json:"cronCompensator,omitempty" yaml:"cronCompensator,omitempty"` // 定时补偿配置
 	Stages          [][]*PipelineYmlAction `json:"stages"`                                                     // 流水线
 	FlatActions     []*PipelineYmlAction   `json:"flatActions"`                                                // 展平了的流水线
@@ -113,6 +115,8 @@ type PipelineYmlAction struct {
 	Loop          *PipelineTaskLoop      `json:"loop,omitempty"`                                           // 循环执行
 	SnippetStages *SnippetStages         `json:"snippetStages,omitempty"`                                  // snippetStages snippet 展开
 	Policy        *Policy                `json:"policy,omitempty"`                                         // action execution strategy
+	Needs         []string               `json:"needs,omitempty"`                                          // 显式声明依赖的 actions
+	Strategy      *ActionStrategy        `json:"strategy,omitempty"`                                       // matrix 展开策略
 }
 
 func (p *PipelineYmlAction) Convert2StructValue() (*structpb.Value, error) {
@@ -139,10 +143,13 @@ type PolicyType string
 // new-run (default, can omit)
 //
 // run-once-from-root-pipeline
+//
+// retry (re-run the failed task with backoff)
 const (
 	NewRunPolicyType                 PolicyType = "new-run"
 	TryLatestSuccessResultPolicyType PolicyType = "try-latest-success-result"
 	TryLatestResultPolicyType        PolicyType = "try-latest-result"
+	RetryPolicyType                  PolicyType = "retry"
 )
 
 func (p PolicyType) GetZhName() string {
@@ -153,6 +160,8 @@ func (p PolicyType) GetZhName() string {
 		return "最近一次执行成功的结果"
 	case TryLatestResultPolicyType:
 		return "最近一次执行的结果"
+	case RetryPolicyType:
+		return "失败后自动重试"
 	default:
 		return ""
 	}
@@ -163,11 +172,21 @@ func (p PolicyType) ToString() string {
 }
 
 func (p PolicyType) IsValid() bool {
-	return p == "" || p == NewRunPolicyType || p == TryLatestSuccessResultPolicyType || p == TryLatestResultPolicyType
+	return p == "" || p == NewRunPolicyType || p == TryLatestSuccessResultPolicyType || p == TryLatestResultPolicyType || p == RetryPolicyType
 }
 
 type Policy struct {
-	Type PolicyType `json:"type,omitempty"`
+	Type  PolicyType       `json:"type,omitempty"`
+	Retry *TaskRetryPolicy `json:"retry,omitempty"` // only used by retry policy
+}
+
+// ActionStrategy is the matrix strategy of action.
+type ActionStrategy struct {
+	Matrix      map[string][]interface{} `json:"matrix,omitempty"`      // 展开前的 matrix 定义
+	MaxParallel int                      `json:"maxParallel,omitempty"` // 同一 matrix 下最大并行数，0 表示不限制
+	FailFast    *bool                    `json:"failFast,omitempty"`    // 同一 matrix 下某个 task 失败后，是否不再调度其他 task，默认为 true
+	Group       string                   `json:"group,omitempty"`       // 展开后的 task 所属的原始 action alias
+	Values      map[string]interface{}   `json:"values,omitempty"`      // 展开后的 task 对应的 matrix 取值
 }
 
 type SnippetStages struct {
@@ -259,6 +278,11 @@ type ActionCache struct {
 	// 缓存生成的 key 或者是用户指定的 key
 	// 用户指定的话 需要 {{basePath}}/路径/{{endPath}} 来自定义 key
 	// 用户没有指定 key 有一定的生成规则, 具体生成规则看 prepare.go 的 setActionCacheStorageAndBinds 方法
+	// 不以 {{basePath}} 开头的 key 按内容寻址存储，支持 ${{ hashFiles('go.sum', '**/package-lock.json') }} 表达式
 	Key  string `json:"key,omitempty"`
 	Path string `json:"path,omitempty"` // 指定那个目录被缓存, 只能是由 / 开始的绝对路径
+	// key 未精确命中时，按顺序使用前缀匹配最新的缓存
+	RestoreKeys []string `json:"restoreKeys,omitempty"`
+	MaxSizeMB   int64    `json:"maxSizeMB,omitempty"` // 单个 key 的缓存大小上限，超过则不保存
+	TTL         string   `json:"ttl,omitempty"`       // 缓存最后一次使用后的保留时间，例如 168h
 }
@@ -93,3 +93,10 @@ func TestSnippetConfigToString(t *testing.T) {
 		assert.Equal(t, data.snippetConfig.ToString(), data.sameSnippetConfig.ToString())
 	}
 }
+
+func TestPolicyTypeIsValid(t *testing.T) {
+	for _, policyType := range []PolicyType{"", NewRunPolicyType, TryLatestSuccessResultPolicyType, TryLatestResultPolicyType, RetryPolicyType} {
+		assert.Equal(t, true, policyType.IsValid())
+	}
+	assert.Equal(t, false, PolicyType("unknown").IsValid())
+}
@@ -81,6 +81,8 @@ type RuntimeCreateRequest struct {
 	DeploymentOrderId string                    `json:"deploymentOrderId,omitempty"`
 	ReleaseVersion    string                    `json:"releaseVersion,omitempty"`
 	ExtraParams       string                    `json:"extraParams,omitempty"`
+	// Canary rollout the new version progressively, only works when runtime is deployed
+	Canary *DeploymentCanaryStrategy `json:"canary,omitempty"`
 }
 
 type RuntimeKillPodRequest struct {
@@ -66,6 +66,7 @@ const (
 	DeploymentPhaseInit      DeploymentPhase = "INIT"
 	DeploymentPhaseAddon     DeploymentPhase = "ADDON_REQUESTING"
 	DeploymentPhaseScript    DeploymentPhase = "SCRIPT_APPLYING"
+	DeploymentPhaseCanary    DeploymentPhase = "CANARY_PROGRESSING"
 	DeploymentPhaseService   DeploymentPhase = "SERVICE_DEPLOYING"
 	DeploymentPhaseRegister  DeploymentPhase = "DISCOVERY_REGISTER"
 	DeploymentPhaseCompleted DeploymentPhase = "COMPLETED"
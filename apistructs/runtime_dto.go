@@ -127,6 +127,8 @@ type DeploymentStatusDTO struct {
 	// 模块错误信息
 	ModuleErrMsg map[string]string           `json:"lastMessage"`
 	Runtime      *DeploymentStatusRuntimeDTO `json:"runtime"`
+	// 金丝雀发布进度
+	Canary *DeploymentCanaryProgress `json:"canary,omitempty"`
 }
 
 // Deprecated: use RuntimeInspect api to get ServiceGroup Info
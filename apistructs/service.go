@@ -18,6 +18,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/erda-project/erda/pkg/parser/diceyml"
 )
@@ -360,6 +361,13 @@ type Service struct {
 	ProjectServiceName string `json:"projectServiceName,omitempty"`
 	// K8s Container Snippet
 	K8SSnippet *diceyml.K8SSnippet `json:"k8sSnippet,omitempty"`
+	// MaxSurge, MaxUnavailable rolling update strategy, see also diceyml.Deployments
+	MaxSurge       *intstr.IntOrString `json:"maxSurge,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MinAvailable 不为空时创建 PodDisruptionBudget
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// TopologySpread 按 zone 或 node 打散实例
+	TopologySpread []diceyml.TopologySpread `json:"topologySpread,omitempty"`
 
 	StatusDesc
 }
@@ -421,10 +429,44 @@ type ExecHealthCheck struct {
 	Duration int `json:"duration,omitempty"`
 }
 
-// 暂定支持"HTTP" 和 "COMMAND"两种方式
+type TCPHealthCheck struct {
+	Port int `json:"port,omitempty"`
+	//单位是秒
+	Duration int `json:"duration,omitempty"`
+}
+
+// GRPCHealthCheck 使用标准的 grpc.health.v1.Health/Check 检查
+type GRPCHealthCheck struct {
+	Port    int    `json:"port,omitempty"`
+	Service string `json:"service,omitempty"`
+	//单位是秒
+	Duration int `json:"duration,omitempty"`
+}
+
+// HealthCheckProbe 独立的 readiness/liveness/startup 探针，未设置检查方式时使用 NewHealthCheck 中的检查方式
+type HealthCheckProbe struct {
+	HttpHealthCheck *HttpHealthCheck `json:"http,omitempty"`
+	ExecHealthCheck *ExecHealthCheck `json:"exec,omitempty"`
+	TCPHealthCheck  *TCPHealthCheck  `json:"tcp,omitempty"`
+	GRPCHealthCheck *GRPCHealthCheck `json:"grpc,omitempty"`
+
+	InitialDelaySeconds int `json:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int `json:"periodSeconds,omitempty"`
+	TimeoutSeconds      int `json:"timeoutSeconds,omitempty"`
+	SuccessThreshold    int `json:"successThreshold,omitempty"`
+	FailureThreshold    int `json:"failureThreshold,omitempty"`
+}
+
+// 支持 "HTTP"、"COMMAND"、"TCP" 和 "GRPC" 四种方式
 type NewHealthCheck struct {
 	HttpHealthCheck *HttpHealthCheck `json:"http,omitempty"`
 	ExecHealthCheck *ExecHealthCheck `json:"exec,omitempty"`
+	TCPHealthCheck  *TCPHealthCheck  `json:"tcp,omitempty"`
+	GRPCHealthCheck *GRPCHealthCheck `json:"grpc,omitempty"`
+
+	Readiness *HealthCheckProbe `json:"readiness,omitempty"`
+	Liveness  *HealthCheckProbe `json:"liveness,omitempty"`
+	Startup   *HealthCheckProbe `json:"startup,omitempty"`
 }
 
 type Volume struct {
@@ -652,6 +694,75 @@ type ServiceGroupConfigUpdateResponse struct {
 	Header
 }
 
+// ServiceGroupCanaryAction action on canary workloads of service group
+type ServiceGroupCanaryAction string
+
+const (
+	// ServiceGroupCanaryActionApply create or update canary workloads and shift traffic weight to them
+	ServiceGroupCanaryActionApply ServiceGroupCanaryAction = "apply"
+	// ServiceGroupCanaryActionRemove remove canary workloads and their traffic
+	ServiceGroupCanaryActionRemove ServiceGroupCanaryAction = "remove"
+
+	// ServiceGroupCanaryLabelKey label set on service group to operate canary workloads by scale
+	ServiceGroupCanaryLabelKey = "erdaCanary"
+	// ServiceGroupCanaryWeightLabelKey traffic weight of canary workloads, in percent
+	ServiceGroupCanaryWeightLabelKey = "erdaCanaryWeight"
+)
+
+// CanaryServiceName name of service which the canary workloads of service report metrics as,
+// so that the traffic of canary is distinguished from the stable ones in the same runtime
+func CanaryServiceName(name string) string {
+	return name + "-canary"
+}
+
+// ServiceGroupCanaryRequest operates canary workloads of the service group, which are built from the new dice.yml
+type ServiceGroupCanaryRequest struct {
+	ServiceGroupCreateV2Request
+	Action ServiceGroupCanaryAction `json:"action"`
+	Weight int                      `json:"weight"`
+}
+
+// ServiceGroupRenderManifestsFormat format of rendered manifests
+type ServiceGroupRenderManifestsFormat string
+
+const (
+	// ServiceGroupRenderManifestsFormatYaml multi-document yaml
+	ServiceGroupRenderManifestsFormatYaml ServiceGroupRenderManifestsFormat = "yaml"
+	// ServiceGroupRenderManifestsFormatHelm files of helm chart
+	ServiceGroupRenderManifestsFormatHelm ServiceGroupRenderManifestsFormat = "helm"
+)
+
+// ServiceGroupRenderManifestsRequest renders dice.yml as kubernetes manifests without deploying
+type ServiceGroupRenderManifestsRequest struct {
+	DiceYml diceyml.Object `json:"diceyml"`
+	// Workspace DEV, TEST, STAGING or PROD, the environment in dice.yml is merged with it
+	Workspace        string `json:"workspace"`
+	ClusterName      string `json:"clusterName"`
+	ID               string `json:"name"`
+	Type             string `json:"namespace"`
+	ProjectNamespace string `json:"projectNamespace"`
+	// Format yaml by default
+	Format ServiceGroupRenderManifestsFormat `json:"format"`
+	// ChartVersion version of helm chart, 0.1.0 by default
+	ChartVersion string `json:"chartVersion"`
+	// ScaledConfigs hpa rules, key: servicename, value: json of ScaledConfig
+	ScaledConfigs map[string]string `json:"scaledConfigs"`
+	// ErdaSecretNames names of secrets in the "secret" namespace of the cluster, dice-* ones are mounted to workloads
+	ErdaSecretNames []string `json:"erdaSecretNames"`
+}
+
+type ServiceGroupRenderManifestsResponse struct {
+	Header
+	Data ServiceGroupRenderManifestsData `json:"data"`
+}
+
+type ServiceGroupRenderManifestsData struct {
+	// Manifests multi-document yaml, set when format is yaml
+	Manifests string `json:"manifests,omitempty"`
+	// Files key: relative path in chart directory, set when format is helm
+	Files map[string]string `json:"files,omitempty"`
+}
+
 // UpdateServiceGroupScaleRequest request body for update servicegroup
 type UpdateServiceGroupScaleRequest struct {
 	Namespace   string                      `json:"namespace"`
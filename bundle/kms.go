@@ -154,3 +154,215 @@ func (b *Bundle) KMSDescribeKey(req apistructs.KMSDescribeKeyRequest) (*kmstypes
 	}
 	return descResp.Data, nil
 }
+
+func (b *Bundle) KMSGetPublicKey(req apistructs.KMSGetPublicKeyRequest) (*kmstypes.PublicKey, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var getResp apistructs.KMSGetPublicKeyResponse
+	httpResp, err := hc.Get(host).Path("/api/kms/get-public-key").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&getResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !getResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), getResp.Error)
+	}
+	return getResp.Data, nil
+}
+
+func (b *Bundle) KMSAsymmetricDecrypt(req apistructs.KMSAsymmetricDecryptRequest) (*kmstypes.AsymmetricDecryptResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var decryptResp apistructs.KMSAsymmetricDecryptResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/asymmetric-decrypt").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&decryptResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !decryptResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), decryptResp.Error)
+	}
+	return decryptResp.Data, nil
+}
+
+// KMSSign sign message by key with SIGN_VERIFY usage, private key never leaves kms.
+// The returned keyVersionID should be stored with signature, to verify after key rotated.
+func (b *Bundle) KMSSign(req apistructs.KMSSignRequest) (*kmstypes.SignResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var signResp apistructs.KMSSignResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/sign").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&signResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !signResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), signResp.Error)
+	}
+	return signResp.Data, nil
+}
+
+func (b *Bundle) KMSVerify(req apistructs.KMSVerifyRequest) (*kmstypes.VerifyResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var verifyResp apistructs.KMSVerifyResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/verify").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&verifyResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !verifyResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), verifyResp.Error)
+	}
+	return verifyResp.Data, nil
+}
+
+func (b *Bundle) KMSReEncrypt(req apistructs.KMSReEncryptRequest) (*kmstypes.ReEncryptResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var reEncryptResp apistructs.KMSReEncryptResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/re-encrypt").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&reEncryptResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !reEncryptResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), reEncryptResp.Error)
+	}
+	return reEncryptResp.Data, nil
+}
+
+func (b *Bundle) KMSEnableKey(req apistructs.KMSEnableKeyRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var enableResp apistructs.KMSEnableKeyResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/enable-key").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&enableResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !enableResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), enableResp.Error)
+	}
+	return enableResp.Data, nil
+}
+
+func (b *Bundle) KMSDisableKey(req apistructs.KMSDisableKeyRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var disableResp apistructs.KMSDisableKeyResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/disable-key").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&disableResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !disableResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), disableResp.Error)
+	}
+	return disableResp.Data, nil
+}
+
+func (b *Bundle) KMSScheduleKeyDeletion(req apistructs.KMSScheduleKeyDeletionRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var scheduleResp apistructs.KMSScheduleKeyDeletionResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/schedule-key-deletion").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&scheduleResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !scheduleResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), scheduleResp.Error)
+	}
+	return scheduleResp.Data, nil
+}
+
+func (b *Bundle) KMSCancelKeyDeletion(req apistructs.KMSCancelKeyDeletionRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var cancelResp apistructs.KMSCancelKeyDeletionResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/cancel-key-deletion").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&cancelResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !cancelResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), cancelResp.Error)
+	}
+	return cancelResp.Data, nil
+}
+
+func (b *Bundle) KMSUpdateRotationPolicy(req apistructs.KMSUpdateRotationPolicyRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	host, err := b.urls.KMS()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	var updateResp apistructs.KMSUpdateRotationPolicyResponse
+	httpResp, err := hc.Post(host).Path("/api/kms/update-rotation-policy").
+		Header(httputil.InternalHeader, "bundle").
+		JSONBody(&req).
+		Do().JSON(&updateResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !httpResp.IsOK() || !updateResp.Success {
+		return nil, toAPIError(httpResp.StatusCode(), updateResp.Error)
+	}
+	return updateResp.Data, nil
+}
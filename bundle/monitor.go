@@ -42,3 +42,33 @@ func (b *Bundle) GetMonitorReportTasksByID(id int64) (*apistructs.ReportTask, er
 
 	return fetchResp.Data, nil
 }
+
+// QueryMetricDict 使用 influxql 查询监控指标, 结果的每一行以列名为 key 返回
+func (b *Bundle) QueryMetricDict(orgID uint64, statement string, params map[string]string) ([]map[string]interface{}, error) {
+	host, err := b.urls.Monitor()
+	if err != nil {
+		return nil, err
+	}
+	hc := b.hc
+
+	request := hc.Get(host).Path("/api/query").
+		Header(httputil.InternalHeader, "bundle").
+		Header(httputil.OrgHeader, strconv.FormatUint(orgID, 10)).
+		Param("ql", "influxql").
+		Param("format", "dict").
+		Param("q", statement)
+	for k, v := range params {
+		request = request.Param(k, v)
+	}
+
+	var queryResp apistructs.MetricQueryDictResponse
+	resp, err := request.Do().JSON(&queryResp)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	if !resp.IsOK() || !queryResp.Success {
+		return nil, toAPIError(resp.StatusCode(), queryResp.Error)
+	}
+
+	return queryResp.Data, nil
+}
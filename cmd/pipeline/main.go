@@ -48,6 +48,7 @@ import (
 	_ "github.com/erda-project/erda/internal/tools/pipeline/providers/lifecycle_hook_client"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/providers/permission"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/providers/pipeline"
+	_ "github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/providers/report"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/providers/resource"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/providers/resourcegc"
@@ -31,6 +31,12 @@ type BranchRule struct {
 	Desc              string //规则说明
 	Workspace         string `json:"workspace"`
 	ArtifactWorkspace string `json:"artifactWorkspace"`
+	// 合并请求的评审规则，仅应用级别生效
+	MinApprovals             int
+	RequireCodeOwnerApproval bool
+	DismissStaleApprovals    bool
+	RequiredChecks           string
+	StrictRequiredChecks     bool
 }
 
 // TableName 设置模型对应数据库表名称
@@ -40,15 +46,20 @@ func (BranchRule) TableName() string {
 
 func (rule *BranchRule) ToApiData() *apistructs.BranchRule {
 	return &apistructs.BranchRule{
-		ID:                int64(rule.ID),
-		Rule:              rule.Rule,
-		ScopeID:           rule.ScopeID,
-		ScopeType:         rule.ScopeType,
-		IsProtect:         rule.IsProtect,
-		NeedApproval:      rule.NeedApproval,
-		IsTriggerPipeline: rule.IsTriggerPipeline,
-		Desc:              rule.Desc,
-		Workspace:         rule.Workspace,
-		ArtifactWorkspace: rule.ArtifactWorkspace,
+		ID:                       int64(rule.ID),
+		Rule:                     rule.Rule,
+		ScopeID:                  rule.ScopeID,
+		ScopeType:                rule.ScopeType,
+		IsProtect:                rule.IsProtect,
+		NeedApproval:             rule.NeedApproval,
+		IsTriggerPipeline:        rule.IsTriggerPipeline,
+		Desc:                     rule.Desc,
+		Workspace:                rule.Workspace,
+		ArtifactWorkspace:        rule.ArtifactWorkspace,
+		MinApprovals:             rule.MinApprovals,
+		RequireCodeOwnerApproval: rule.RequireCodeOwnerApproval,
+		DismissStaleApprovals:    rule.DismissStaleApprovals,
+		RequiredChecks:           rule.RequiredChecks,
+		StrictRequiredChecks:     rule.StrictRequiredChecks,
 	}
 }
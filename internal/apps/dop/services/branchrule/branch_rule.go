@@ -175,6 +175,11 @@ func (branchRule *BranchRule) Update(request apistructs.UpdateBranchRuleRequest)
 	rule.Workspace = request.Workspace
 	rule.ArtifactWorkspace = request.ArtifactWorkspace
 	rule.NeedApproval = request.NeedApproval
+	rule.MinApprovals = request.MinApprovals
+	rule.RequireCodeOwnerApproval = request.RequireCodeOwnerApproval
+	rule.DismissStaleApprovals = request.DismissStaleApprovals
+	rule.RequiredChecks = request.RequiredChecks
+	rule.StrictRequiredChecks = request.StrictRequiredChecks
 	err = branchRule.CheckRuleValid(&rule)
 	if err != nil {
 		return nil, err
@@ -188,15 +193,20 @@ func (branchRule *BranchRule) Update(request apistructs.UpdateBranchRuleRequest)
 
 func (branchRule *BranchRule) Create(request apistructs.CreateBranchRuleRequest) (*apistructs.BranchRule, error) {
 	rule := model.BranchRule{
-		ScopeType:         request.ScopeType,
-		ScopeID:           request.ScopeID,
-		Rule:              request.Rule,
-		IsProtect:         request.IsProtect,
-		IsTriggerPipeline: request.IsTriggerPipeline,
-		Workspace:         request.Workspace,
-		ArtifactWorkspace: request.ArtifactWorkspace,
-		NeedApproval:      request.NeedApproval,
-		Desc:              request.Desc,
+		ScopeType:                request.ScopeType,
+		ScopeID:                  request.ScopeID,
+		Rule:                     request.Rule,
+		IsProtect:                request.IsProtect,
+		IsTriggerPipeline:        request.IsTriggerPipeline,
+		Workspace:                request.Workspace,
+		ArtifactWorkspace:        request.ArtifactWorkspace,
+		NeedApproval:             request.NeedApproval,
+		Desc:                     request.Desc,
+		MinApprovals:             request.MinApprovals,
+		RequireCodeOwnerApproval: request.RequireCodeOwnerApproval,
+		DismissStaleApprovals:    request.DismissStaleApprovals,
+		RequiredChecks:           request.RequiredChecks,
+		StrictRequiredChecks:     request.StrictRequiredChecks,
 	}
 	err := branchRule.CheckRuleValid(&rule)
 	if err != nil {
@@ -239,6 +249,9 @@ func isRuleMatch(sourceRule, targetRule string) bool {
 }
 
 func (branchRule *BranchRule) CheckRuleValid(newBranchRule *model.BranchRule) error {
+	if newBranchRule.MinApprovals < 0 {
+		return fmt.Errorf("invalid minApprovals %d", newBranchRule.MinApprovals)
+	}
 	// check duplicate
 	currentRules, err := branchRule.Query(newBranchRule.ScopeType, newBranchRule.ScopeID)
 	if err != nil {
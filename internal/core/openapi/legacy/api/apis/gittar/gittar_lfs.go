@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gittar
+
+import (
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/core/openapi/legacy/api/apis"
+)
+
+var GITTAR_LFS_STATS = apis.ApiSpec{
+	Path:         "/api/gittar/<org>/<repo>/lfs-stats",
+	BackendPath:  "/<org>/<repo>/lfs-stats",
+	Host:         "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:       "http",
+	Method:       "GET",
+	CheckLogin:   true,
+	IsOpenAPI:    true,
+	ResponseType: apistructs.GittarLFSStatsResponse{},
+	Doc:          `summary: 获取仓库的 LFS 使用情况`,
+}
+
+var GITTAR_LFS_QUOTA_UPDATE = apis.ApiSpec{
+	Path:         "/api/gittar/<org>/<repo>/lfs-quota",
+	BackendPath:  "/<org>/<repo>/lfs-quota",
+	Host:         "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:       "http",
+	Method:       "PUT",
+	CheckLogin:   true,
+	IsOpenAPI:    true,
+	RequestType:  apistructs.GittarUpdateLFSQuotaRequest{},
+	ResponseType: apistructs.GittarLFSStatsResponse{},
+	Doc:          `summary: 更新仓库的 LFS 容量上限`,
+}
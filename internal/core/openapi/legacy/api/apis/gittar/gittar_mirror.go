@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gittar
+
+import (
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/core/openapi/legacy/api/apis"
+)
+
+var GITTAR_MIRROR_LIST = apis.ApiSpec{
+	Path:         "/api/gittar/<org>/<repo>/mirrors",
+	BackendPath:  "/<org>/<repo>/mirrors",
+	Host:         "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:       "http",
+	Method:       "GET",
+	CheckLogin:   true,
+	IsOpenAPI:    true,
+	ResponseType: apistructs.GittarListRepoMirrorsResponse{},
+	Doc:          `summary: 查询仓库的镜像及同步状态`,
+}
+
+var GITTAR_MIRROR_CREATE = apis.ApiSpec{
+	Path:         "/api/gittar/<org>/<repo>/mirrors",
+	BackendPath:  "/<org>/<repo>/mirrors",
+	Host:         "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:       "http",
+	Method:       "POST",
+	CheckLogin:   true,
+	IsOpenAPI:    true,
+	RequestType:  apistructs.GittarRepoMirrorRequest{},
+	ResponseType: apistructs.GittarRepoMirrorResponse{},
+	Doc:          `summary: 创建仓库的拉取或推送镜像`,
+}
+
+var GITTAR_MIRROR_GET = apis.ApiSpec{
+	Path:         "/api/gittar/<org>/<repo>/mirrors/<id>",
+	BackendPath:  "/<org>/<repo>/mirrors/<id>",
+	Host:         "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:       "http",
+	Method:       "GET",
+	CheckLogin:   true,
+	IsOpenAPI:    true,
+	ResponseType: apistructs.GittarRepoMirrorResponse{},
+	Doc:          `summary: 查询镜像的同步状态`,
+}
+
+var GITTAR_MIRROR_UPDATE = apis.ApiSpec{
+	Path:         "/api/gittar/<org>/<repo>/mirrors/<id>",
+	BackendPath:  "/<org>/<repo>/mirrors/<id>",
+	Host:         "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:       "http",
+	Method:       "PUT",
+	CheckLogin:   true,
+	IsOpenAPI:    true,
+	RequestType:  apistructs.GittarRepoMirrorRequest{},
+	ResponseType: apistructs.GittarRepoMirrorResponse{},
+	Doc:          `summary: 更新镜像配置`,
+}
+
+var GITTAR_MIRROR_DELETE = apis.ApiSpec{
+	Path:        "/api/gittar/<org>/<repo>/mirrors/<id>",
+	BackendPath: "/<org>/<repo>/mirrors/<id>",
+	Host:        "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:      "http",
+	Method:      "DELETE",
+	CheckLogin:  true,
+	IsOpenAPI:   true,
+	Doc:         `summary: 删除镜像`,
+}
+
+var GITTAR_MIRROR_SYNC = apis.ApiSpec{
+	Path:         "/api/gittar/<org>/<repo>/mirrors/<id>/sync",
+	BackendPath:  "/<org>/<repo>/mirrors/<id>/sync",
+	Host:         "gittar.marathon.l4lb.thisdcos.directory:5566",
+	Scheme:       "http",
+	Method:       "POST",
+	CheckLogin:   true,
+	IsOpenAPI:    true,
+	ResponseType: apistructs.GittarRepoMirrorResponse{},
+	Doc:          `summary: 立即同步镜像`,
+}
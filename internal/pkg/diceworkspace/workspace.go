@@ -34,12 +34,17 @@ func GetValidBranchByGitReference(ref string, branchRules []*apistructs.BranchRu
 		for _, branchFilter := range branchFilters {
 			if IsRefPatternMatch(ref, []string{branchFilter}) {
 				return &apistructs.ValidBranch{
-					Name:              ref,
-					IsProtect:         branchRule.IsProtect,
-					NeedApproval:      branchRule.NeedApproval,
-					IsTriggerPipeline: branchRule.IsTriggerPipeline,
-					Workspace:         branchRule.Workspace,
-					ArtifactWorkspace: branchRule.ArtifactWorkspace,
+					Name:                     ref,
+					IsProtect:                branchRule.IsProtect,
+					NeedApproval:             branchRule.NeedApproval,
+					IsTriggerPipeline:        branchRule.IsTriggerPipeline,
+					Workspace:                branchRule.Workspace,
+					ArtifactWorkspace:        branchRule.ArtifactWorkspace,
+					MinApprovals:             branchRule.MinApprovals,
+					RequireCodeOwnerApproval: branchRule.RequireCodeOwnerApproval,
+					DismissStaleApprovals:    branchRule.DismissStaleApprovals,
+					RequiredChecks:           branchRule.RequiredChecks,
+					StrictRequiredChecks:     branchRule.StrictRequiredChecks,
 				}
 			}
 		}
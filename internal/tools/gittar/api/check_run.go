@@ -26,7 +26,7 @@ func CreateCheckRun(ctx *webcontext.Context) {
 		ctx.Abort(err)
 		return
 	}
-	result, err := ctx.Service.CreateOrUpdateCheckRun(ctx.Repository, &request)
+	result, err := ctx.Service.CreateOrUpdateCheckRun(ctx.Repository, ctx.User, &request)
 	if err != nil {
 		ctx.Abort(err)
 		return
@@ -0,0 +1,413 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/gittar/models"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/lfs"
+	"github.com/erda-project/erda/internal/tools/gittar/webcontext"
+)
+
+// LFSBatch git lfs 批量接口，告知客户端每个对象需要上传还是可以下载
+func LFSBatch(ctx *webcontext.Context) {
+	var req lfs.BatchRequest
+	if err := json.NewDecoder(ctx.GetRequestBody()).Decode(&req); err != nil {
+		lfsError(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if req.Operation != lfs.OperationUpload && req.Operation != lfs.OperationDownload {
+		lfsError(ctx, http.StatusUnprocessableEntity, "invalid operation: "+req.Operation)
+		return
+	}
+	if req.Operation == lfs.OperationUpload && !checkLFSUploadable(ctx) {
+		return
+	}
+
+	oids := make([]string, 0, len(req.Objects))
+	for _, object := range req.Objects {
+		oids = append(oids, object.Oid)
+	}
+	existed, err := ctx.Service.GetLFSObjects(ctx.Repository, oids)
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	baseURL := lfsBaseURL(ctx)
+	header := lfsActionHeader(ctx)
+	var uploadSize int64
+	resp := lfs.BatchResponse{Transfer: lfs.TransferBasic}
+	for _, object := range req.Objects {
+		result := &lfs.ObjectResponse{Pointer: object}
+		resp.Objects = append(resp.Objects, result)
+		if !object.Valid() {
+			result.Error = &lfs.ObjectError{Code: http.StatusUnprocessableEntity, Message: "invalid object"}
+			continue
+		}
+		stored, ok := existed[object.Oid]
+		if ok && stored.Size != object.Size {
+			result.Error = &lfs.ObjectError{Code: http.StatusUnprocessableEntity, Message: "object size mismatch"}
+			continue
+		}
+		switch req.Operation {
+		case lfs.OperationDownload:
+			if !ok {
+				result.Error = &lfs.ObjectError{Code: http.StatusNotFound, Message: "object not found"}
+				continue
+			}
+			result.Actions = map[string]*lfs.Link{
+				"download": {Href: baseURL + "/objects/" + object.Oid, Header: header},
+			}
+		case lfs.OperationUpload:
+			// 已存在的对象不返回 actions，客户端会跳过上传
+			if ok {
+				continue
+			}
+			uploadSize += object.Size
+			result.Actions = map[string]*lfs.Link{
+				"upload": {Href: baseURL + "/objects/" + object.Oid + "/" + strconv.FormatInt(object.Size, 10), Header: header},
+				"verify": {Href: baseURL + "/verify", Header: header},
+			}
+		}
+	}
+
+	if uploadSize > 0 {
+		if err = ctx.Service.CheckLFSQuota(ctx.Repository, uploadSize); err != nil {
+			lfsQuotaError(ctx, err)
+			return
+		}
+	}
+	lfsJSON(ctx, http.StatusOK, resp)
+}
+
+// LFSUpload 上传对象内容，内容与 oid 和 size 不一致时拒绝
+func LFSUpload(ctx *webcontext.Context) {
+	if !checkLFSUploadable(ctx) {
+		return
+	}
+	size, err := strconv.ParseInt(ctx.Param("size"), 10, 64)
+	if err != nil {
+		lfsError(ctx, http.StatusUnprocessableEntity, "invalid size")
+		return
+	}
+	pointer := lfs.Pointer{Oid: ctx.Param("oid"), Size: size}
+	if !pointer.Valid() {
+		lfsError(ctx, http.StatusUnprocessableEntity, "invalid object")
+		return
+	}
+	existed, err := ctx.Service.GetLFSObject(ctx.Repository, pointer.Oid)
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if existed != nil {
+		ctx.Status(http.StatusOK)
+		return
+	}
+	if err = ctx.Service.CheckLFSQuota(ctx.Repository, pointer.Size); err != nil {
+		lfsQuotaError(ctx, err)
+		return
+	}
+
+	err = ctx.LFSStore.Put(pointer, ctx.GetRequestBody())
+	if err == lfs.ErrHashMismatch || err == lfs.ErrSizeMismatch {
+		lfsError(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err = ctx.Service.CreateLFSObject(ctx.Repository, pointer); err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// LFSDownload 下载对象内容
+func LFSDownload(ctx *webcontext.Context) {
+	object, err := ctx.Service.GetLFSObject(ctx.Repository, ctx.Param("oid"))
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if object == nil {
+		lfsError(ctx, http.StatusNotFound, "object not found")
+		return
+	}
+	rc, err := ctx.LFSStore.Get(lfs.Pointer{Oid: object.Oid, Size: object.Size})
+	if err == lfs.ErrObjectNotExist {
+		lfsError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rc.Close()
+	ctx.Header("Content-Length", strconv.FormatInt(object.Size, 10))
+	ctx.EchoContext.Stream(http.StatusOK, "application/octet-stream", rc)
+}
+
+// LFSVerify 客户端上传完成后确认对象已存储
+func LFSVerify(ctx *webcontext.Context) {
+	var pointer lfs.Pointer
+	if err := json.NewDecoder(ctx.GetRequestBody()).Decode(&pointer); err != nil {
+		lfsError(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	object, err := ctx.Service.GetLFSObject(ctx.Repository, pointer.Oid)
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if object == nil || object.Size != pointer.Size {
+		lfsError(ctx, http.StatusNotFound, "object not found")
+		return
+	}
+	lfsJSON(ctx, http.StatusOK, pointer)
+}
+
+// LFSCreateLock 锁定文件
+func LFSCreateLock(ctx *webcontext.Context) {
+	if err := ctx.CheckPermission(models.PermissionPush); err != nil {
+		lfsError(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+	var req lfs.LockCreateRequest
+	if err := json.NewDecoder(ctx.GetRequestBody()).Decode(&req); err != nil || req.Path == "" {
+		lfsError(ctx, http.StatusUnprocessableEntity, "invalid lock request")
+		return
+	}
+	var ref string
+	if req.Ref != nil {
+		ref = req.Ref.Name
+	}
+	lock, err := ctx.Service.CreateLFSLock(ctx.Repository, ctx.User, req.Path, ref)
+	if err == models.ErrLFSLockExists {
+		lfsJSON(ctx, http.StatusConflict, lfs.LockResponse{Lock: lock.ToLock(), Message: err.Error()})
+		return
+	}
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lfsJSON(ctx, http.StatusCreated, lfs.LockResponse{Lock: lock.ToLock()})
+}
+
+// LFSListLocks 查询文件锁
+func LFSListLocks(ctx *webcontext.Context) {
+	locks, nextCursor, err := ctx.Service.ListLFSLocks(ctx.Repository, models.LFSLockQuery{
+		ID:     ctx.Query("id"),
+		Path:   ctx.Query("path"),
+		Cursor: ctx.Query("cursor"),
+		Limit:  ctx.GetQueryInt32("limit", 0),
+	})
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := lfs.LockListResponse{Locks: []*lfs.Lock{}, NextCursor: nextCursor}
+	for _, lock := range locks {
+		resp.Locks = append(resp.Locks, lock.ToLock())
+	}
+	lfsJSON(ctx, http.StatusOK, resp)
+}
+
+// LFSVerifyLocks push 前校验文件锁，区分当前用户与他人持有的锁
+func LFSVerifyLocks(ctx *webcontext.Context) {
+	if err := ctx.CheckPermission(models.PermissionPush); err != nil {
+		lfsError(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+	var req lfs.LockVerifyRequest
+	if err := json.NewDecoder(ctx.GetRequestBody()).Decode(&req); err != nil {
+		lfsError(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	locks, nextCursor, err := ctx.Service.ListLFSLocks(ctx.Repository, models.LFSLockQuery{
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := lfs.LockVerifyResponse{Ours: []*lfs.Lock{}, Theirs: []*lfs.Lock{}, NextCursor: nextCursor}
+	for _, lock := range locks {
+		if lock.OwnerId == ctx.User.Id {
+			resp.Ours = append(resp.Ours, lock.ToLock())
+		} else {
+			resp.Theirs = append(resp.Theirs, lock.ToLock())
+		}
+	}
+	lfsJSON(ctx, http.StatusOK, resp)
+}
+
+// LFSUnlock 解锁文件，强制解除他人的锁需要仓库管理权限
+func LFSUnlock(ctx *webcontext.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		lfsError(ctx, http.StatusUnprocessableEntity, "invalid lock id")
+		return
+	}
+	var req lfs.UnlockRequest
+	if err = json.NewDecoder(ctx.GetRequestBody()).Decode(&req); err != nil && err != io.EOF {
+		lfsError(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if req.Force {
+		if err = ctx.CheckPermission(models.PermissionRepoLocked); err != nil {
+			lfsError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+	}
+	lock, err := ctx.Service.DeleteLFSLock(ctx.Repository, ctx.User, id, req.Force)
+	if err == gorm.ErrRecordNotFound {
+		lfsError(ctx, http.StatusNotFound, "lock not found")
+		return
+	}
+	if err == models.ErrLFSLockNotOwner {
+		lfsError(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lfsJSON(ctx, http.StatusOK, lfs.LockResponse{Lock: lock.ToLock()})
+}
+
+// GetLFSStats 获取仓库的 LFS 使用情况
+func GetLFSStats(ctx *webcontext.Context) {
+	stats, err := ctx.Service.GetLFSStats(ctx.Repository)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(stats)
+}
+
+// UpdateLFSQuota 更新仓库的 LFS 容量上限
+func UpdateLFSQuota(ctx *webcontext.Context) {
+	var req apistructs.GittarUpdateLFSQuotaRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		ctx.Abort(err)
+		return
+	}
+	stats, err := ctx.Service.UpdateLFSQuota(ctx.Repository, ctx.User, req.Quota)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(stats)
+}
+
+// checkLFSUploadable 上传需要 push 权限且仓库未锁定
+func checkLFSUploadable(ctx *webcontext.Context) bool {
+	if err := ctx.CheckPermission(models.PermissionPush); err != nil {
+		lfsError(ctx, http.StatusForbidden, err.Error())
+		return false
+	}
+	isLocked, err := ctx.Service.GetRepoLocked(ctx.Repository.ProjectId, ctx.Repository.ApplicationId)
+	if err != nil {
+		lfsError(ctx, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	if isLocked {
+		lfsError(ctx, http.StatusForbidden, ERROR_REPO_LOCKED.Error())
+		return false
+	}
+	return true
+}
+
+// openLFSContent blob 是 LFS pointer 时打开对象的实际内容，head 需要包含 blob 的全部内容
+// 对象大小超过 maxSize 时不打开内容，只返回 pointer，maxSize 小于0表示不限制
+func openLFSContent(ctx *webcontext.Context, blobSize int64, head []byte, maxSize int64) (io.ReadCloser, *lfs.Pointer) {
+	if blobSize > lfs.MaxPointerSize || int64(len(head)) != blobSize {
+		return nil, nil
+	}
+	pointer, ok := lfs.ReadPointer(head)
+	if !ok {
+		return nil, nil
+	}
+	object, err := ctx.Service.GetLFSObject(ctx.Repository, pointer.Oid)
+	if err != nil || object == nil {
+		return nil, nil
+	}
+	if maxSize >= 0 && pointer.Size > maxSize {
+		return nil, &pointer
+	}
+	rc, err := ctx.LFSStore.Get(pointer)
+	if err != nil {
+		logrus.Errorf("failed to open lfs object %s, err: %v", pointer.Oid, err)
+		return nil, nil
+	}
+	return rc, &pointer
+}
+
+// lfsBaseURL 当前仓库的 LFS 接口地址，即请求路径中 info/lfs 及之前的部分
+func lfsBaseURL(ctx *webcontext.Context) string {
+	scheme := ctx.GetHeader("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = ctx.EchoContext.Scheme()
+	}
+	reqPath := ctx.HttpRequest().URL.Path
+	if i := strings.Index(reqPath, "/info/lfs"); i >= 0 {
+		reqPath = reqPath[:i+len("/info/lfs")]
+	}
+	return scheme + "://" + ctx.Host() + reqPath
+}
+
+// lfsActionHeader 传输请求沿用批量请求的认证信息
+func lfsActionHeader(ctx *webcontext.Context) map[string]string {
+	auth := ctx.GetHeader("Authorization")
+	if auth == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": auth}
+}
+
+func lfsJSON(ctx *webcontext.Context, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Data(status, lfs.MediaType, data)
+}
+
+func lfsError(ctx *webcontext.Context, status int, msg string) {
+	lfsJSON(ctx, status, lfs.ErrorResponse{Message: msg})
+}
+
+func lfsQuotaError(ctx *webcontext.Context, err error) {
+	if err == models.ErrLFSQuotaExceeded {
+		lfsError(ctx, http.StatusInsufficientStorage, err.Error())
+		return
+	}
+	lfsError(ctx, http.StatusInternalServerError, err.Error())
+}
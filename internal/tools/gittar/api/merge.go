@@ -39,7 +39,14 @@ func CheckMergeStatus(ctx *webcontext.Context) {
 	sourceBranch := ctx.Query("sourceBranch")
 	targetBranch := ctx.Query("targetBranch")
 
-	conflictInfo, err := ctx.Repository.GetMergeStatus(sourceBranch, targetBranch)
+	// 不同合并方式的冲突判断不同，未指定时按仓库默认方式检查
+	strategy, err := ctx.Service.ResolveMergeStrategy(ctx.Repository, ctx.Query("strategy"))
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+
+	conflictInfo, err := ctx.Repository.GetMergeStatusWithStrategy(sourceBranch, targetBranch, strategy)
 
 	if err != nil {
 		ctx.Abort(err)
@@ -50,6 +57,31 @@ func CheckMergeStatus(ctx *webcontext.Context) {
 
 }
 
+// GetMergeSettings 获取仓库允许的合并方式
+func GetMergeSettings(ctx *webcontext.Context) {
+	settings, err := ctx.Service.GetMergeSettings(ctx.Repository)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(settings)
+}
+
+// UpdateMergeSettings 更新仓库允许的合并方式
+func UpdateMergeSettings(ctx *webcontext.Context) {
+	var settings apistructs.GittarMergeSettings
+	if err := ctx.BindJSON(&settings); err != nil {
+		ctx.Abort(err)
+		return
+	}
+	result, err := ctx.Service.UpdateMergeSettings(ctx.Repository, ctx.User, &settings)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
 func GetMergeTemplates(ctx *webcontext.Context) {
 	branch, err := ctx.Repository.GetDefaultBranch()
 	if err != nil {
@@ -124,7 +156,12 @@ func CreateMergeRequest(ctx *webcontext.Context) {
 		request.EventName = apistructs.GitCreateMREvent
 		ctx.Service.TriggerEvent(ctx.Repository, apistructs.GitCreateMREvent, request)
 		// check-run
-		conflictInfo, err := ctx.Repository.GetMergeStatus(request.SourceBranch, request.TargetBranch)
+		strategy, err := ctx.Service.ResolveMergeStrategy(ctx.Repository, request.MergeStrategy)
+		if err != nil {
+			logrus.Errorf("failed to resolve merge strategy err:%s", err)
+			return
+		}
+		conflictInfo, err := ctx.Repository.GetMergeStatusWithStrategy(request.SourceBranch, request.TargetBranch, strategy)
 		if err != nil {
 			ctx.Abort(err)
 			return
@@ -234,7 +271,12 @@ func UpdateMergeRequest(ctx *webcontext.Context) {
 		}
 		// check-run
 		result.MergeUserId = ctx.User.Id
-		conflictInfo, err := ctx.Repository.GetMergeStatus(result.SourceBranch, result.TargetBranch)
+		strategy, err := ctx.Service.ResolveMergeStrategy(ctx.Repository, result.MergeStrategy)
+		if err != nil {
+			logrus.Errorf("failed to resolve merge strategy err:%s", err)
+			return
+		}
+		conflictInfo, err := ctx.Repository.GetMergeStatusWithStrategy(result.SourceBranch, result.TargetBranch, strategy)
 		if err != nil {
 			ctx.Abort(err)
 			return
@@ -354,6 +396,51 @@ func ReopenMR(ctx *webcontext.Context) {
 	ctx.Success(result)
 }
 
+// GetMergeRequestApprovals 查询合并请求的评审状态
+func GetMergeRequestApprovals(ctx *webcontext.Context) {
+	id := ctx.ParamInt32("id", 0)
+	if id == 0 {
+		ctx.Abort(ERROR_ARG_ID)
+		return
+	}
+	result, err := ctx.Service.GetMergeRequestApprovalStatus(ctx.Repository, id)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
+// ApproveMR 当前用户评审通过合并请求
+func ApproveMR(ctx *webcontext.Context) {
+	id := ctx.ParamInt32("id", 0)
+	if id == 0 {
+		ctx.Abort(ERROR_ARG_ID)
+		return
+	}
+	result, err := ctx.Service.ApproveMergeRequest(ctx.Repository, ctx.User, id)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
+// UnapproveMR 撤销当前用户的评审通过
+func UnapproveMR(ctx *webcontext.Context) {
+	id := ctx.ParamInt32("id", 0)
+	if id == 0 {
+		ctx.Abort(ERROR_ARG_ID)
+		return
+	}
+	result, err := ctx.Service.UnapproveMergeRequest(ctx.Repository, ctx.User, id)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
 func QueryNotes(ctx *webcontext.Context) {
 	id := ctx.ParamInt32("id", 0)
 	if id == 0 {
@@ -550,18 +637,35 @@ func MergeWithBranch(ctx *webcontext.Context) {
 		return
 	}
 
-	commitMessage := fmt.Sprintf("Merge branch '%s' into '%s'", req.SourceBranch, req.TargetBranch)
-	commit, err := ctx.Repository.Merge(req.SourceBranch, req.TargetBranch, ctx.User.ToGitSignature(), commitMessage)
-	if err != nil {
+	// 与合并请求使用同样的保护分支、评审和检查规则
+	if err = ctx.Service.CheckMergeWithBranch(ctx.Repository, ctx.User, req.TargetBranch); err != nil {
 		ctx.Abort(err)
 		return
 	}
 
+	// 未指定策略的调用方 (如 devflow) 保持原来的 merge commit 行为，不受仓库允许策略限制
+	strategy := gitmodule.MergeStrategyMerge
+	if req.Strategy != "" {
+		strategy, err = ctx.Service.ResolveMergeStrategy(ctx.Repository, req.Strategy)
+		if err != nil {
+			ctx.Abort(err)
+			return
+		}
+	}
+
+	// 合并后目标分支已移动，需要先记录合并前的提交
 	targetCommit, err := ctx.Repository.GetBranchCommit(req.TargetBranch)
 	if err != nil {
 		ctx.Abort(err)
 		return
 	}
+
+	commitMessage := fmt.Sprintf("Merge branch '%s' into '%s'", req.SourceBranch, req.TargetBranch)
+	commit, err := ctx.Repository.MergeWithStrategy(strategy, req.SourceBranch, req.TargetBranch, ctx.User.ToGitSignature(), commitMessage)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
 	pushEvent := &models.PayloadPushEvent{
 		Before: targetCommit.ID,
 		After:  commit.ID,
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/gittar/webcontext"
+)
+
+// ListMirrors 查询仓库的镜像及同步状态
+func ListMirrors(ctx *webcontext.Context) {
+	result, err := ctx.Service.ListMirrors(ctx.Repository)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
+// GetMirror 查询镜像的同步状态
+func GetMirror(ctx *webcontext.Context) {
+	id := ctx.ParamInt32("id", 0)
+	if id == 0 {
+		ctx.Abort(ERROR_ARG_ID)
+		return
+	}
+	result, err := ctx.Service.GetMirror(ctx.Repository, int64(id))
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
+// CreateMirror 创建拉取或推送镜像
+func CreateMirror(ctx *webcontext.Context) {
+	var request apistructs.GittarRepoMirrorRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.Abort(err)
+		return
+	}
+	result, err := ctx.Service.CreateMirror(ctx.Repository, ctx.User, &request)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
+// UpdateMirror 更新镜像配置
+func UpdateMirror(ctx *webcontext.Context) {
+	id := ctx.ParamInt32("id", 0)
+	if id == 0 {
+		ctx.Abort(ERROR_ARG_ID)
+		return
+	}
+	var request apistructs.GittarRepoMirrorRequest
+	if err := ctx.BindJSON(&request); err != nil {
+		ctx.Abort(err)
+		return
+	}
+	result, err := ctx.Service.UpdateMirror(ctx.Repository, ctx.User, int64(id), &request)
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
+
+// DeleteMirror 删除镜像
+func DeleteMirror(ctx *webcontext.Context) {
+	id := ctx.ParamInt32("id", 0)
+	if id == 0 {
+		ctx.Abort(ERROR_ARG_ID)
+		return
+	}
+	if err := ctx.Service.DeleteMirror(ctx.Repository, ctx.User, int64(id)); err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success("")
+}
+
+// SyncMirror 立即同步镜像，同步在后台进行，结果通过 GetMirror 查询
+func SyncMirror(ctx *webcontext.Context) {
+	id := ctx.ParamInt32("id", 0)
+	if id == 0 {
+		ctx.Abort(ERROR_ARG_ID)
+		return
+	}
+	result, err := ctx.Service.SyncMirrorNow(ctx.Repository, ctx.User, int64(id))
+	if err != nil {
+		ctx.Abort(err)
+		return
+	}
+	ctx.Success(result)
+}
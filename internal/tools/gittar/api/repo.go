@@ -573,6 +573,20 @@ func GetRepoBlob(context *webcontext.Context) {
 	buf := make([]byte, 1024)
 	n, _ := dataRc.Read(buf)
 	buf = buf[:n]
+	size := treeEntry.Size()
+	// LFS pointer 展示对象的实际内容，对象过大时只返回 pointer 信息
+	lfsContent, lfsPointer := openLFSContent(context, size, buf, conf.LFSMaxViewSize())
+	lfsTooLarge := lfsPointer != nil && lfsContent == nil
+	if lfsContent != nil {
+		defer lfsContent.Close()
+		dataRc = lfsContent
+		buf = make([]byte, 1024)
+		n, _ = io.ReadFull(dataRc, buf)
+		buf = buf[:n]
+	}
+	if lfsPointer != nil {
+		size = lfsPointer.Size
+	}
 	contentType := http.DetectContentType(buf)
 	isTextFile := isTextType(contentType)
 
@@ -584,6 +598,10 @@ func GetRepoBlob(context *webcontext.Context) {
 
 	//range 模式
 	if context.Query("mode") == "range" {
+		if lfsTooLarge {
+			context.AbortWithString(400, "lfs object is too large to view")
+			return
+		}
 		if !isTextFile {
 			context.AbortWithString(500, "not text file")
 		}
@@ -658,14 +676,23 @@ func GetRepoBlob(context *webcontext.Context) {
 			RefName string `json:"refName"`
 			Path    string `json:"path"`
 			Size    int64  `json:"size"`
+			LFS     bool   `json:"lfs"`
+			// LFSOid 和 TooLarge 在 LFS 对象超过展示上限时返回，此时不返回内容
+			LFSOid   string `json:"lfsOid,omitempty"`
+			TooLarge bool   `json:"tooLarge,omitempty"`
 		}{
 			Binary:  !isTextFile,
 			Content: "",
 			Path:    treePath,
 			RefName: refName,
-			Size:    treeEntry.Size(),
+			Size:    size,
+			LFS:     lfsPointer != nil,
 		}
-		if isTextFile {
+		if lfsTooLarge {
+			blobData.Binary = true
+			blobData.LFSOid = lfsPointer.Oid
+			blobData.TooLarge = true
+		} else if isTextFile {
 			d, _ := ioutil.ReadAll(dataRc)
 			buf = append(buf, d...)
 
@@ -768,6 +795,14 @@ func GetRepoRaw(context *webcontext.Context) {
 		context.AbortWithStatus(404, ERROR_PATH_NOT_FOUND)
 		return
 	}
+	// LFS pointer 返回对象的实际内容
+	if lfsContent, _ := openLFSContent(context, treeEntry.Size(), bufHead, -1); lfsContent != nil {
+		defer lfsContent.Close()
+		dataRc = lfsContent
+		n, _ = io.ReadFull(dataRc, buf)
+		bufHead = buf[:n]
+		contentType = http.DetectContentType(bufHead)
+	}
 	// 防止html渲染，强制转为text/plain
 	contentType = strings.Replace(contentType, "text/html", "text/plain", -1)
 	context.Header("Content-Type", contentType)
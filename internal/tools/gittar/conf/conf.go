@@ -15,7 +15,9 @@
 package conf
 
 import (
+	"path"
 	"strings"
+	"time"
 
 	"github.com/erda-project/erda/pkg/discover"
 	"github.com/erda-project/erda/pkg/envconf"
@@ -55,6 +57,23 @@ type Conf struct {
 	OryKratosAddr          string `default:"kratos-public" env:"ORY_KRATOS_ADDR"`
 	OryKratosPrivateAddr   string `default:"kratos-admin" env:"ORY_KRATOS_ADMIN_ADDR"`
 	GitRepoTreeSearchDepth int64  `default:"5" env:"GIT_REPO_TREE_SEARCH_DEPTH"`
+
+	// lfs config
+	LFSStorage           string `env:"GITTAR_LFS_STORAGE" default:"local"`
+	LFSContentPath       string `env:"GITTAR_LFS_CONTENT_PATH"`
+	LFSDefaultQuota      int64  `env:"GITTAR_LFS_DEFAULT_QUOTA" default:"10737418240"`
+	LFSStorageEndpoint   string `env:"GITTAR_LFS_STORAGE_ENDPOINT"`
+	LFSStorageAccessKey  string `env:"GITTAR_LFS_STORAGE_ACCESS_KEY"`
+	LFSStorageSecretKey  string `env:"GITTAR_LFS_STORAGE_SECRET_KEY"`
+	LFSStorageBucketName string `env:"GITTAR_LFS_STORAGE_BUCKET_NAME"`
+	LFSMaxViewSize       int64  `env:"GITTAR_LFS_MAX_VIEW_SIZE" default:"10485760"`
+
+	// mirror config
+	MirrorCredentialKey      string        `env:"GITTAR_MIRROR_CREDENTIAL_KEY"`
+	MirrorCheckInterval      time.Duration `env:"GITTAR_MIRROR_CHECK_INTERVAL" default:"1m"`
+	MirrorMaxConcurrency     int           `env:"GITTAR_MIRROR_MAX_CONCURRENCY" default:"5"`
+	MirrorSyncTimeout        time.Duration `env:"GITTAR_MIRROR_SYNC_TIMEOUT" default:"30m"`
+	MirrorMinIntervalMinutes int           `env:"GITTAR_MIRROR_MIN_INTERVAL_MINUTES" default:"5"`
 }
 
 var cfg Conf
@@ -200,3 +219,68 @@ func GitRepoTreeSearchDepth() int64 {
 func DiceProtocol() string {
 	return cfg.DiceProtocol
 }
+
+// LFSStorage LFS 对象存储方式，local 或 cloud
+func LFSStorage() string {
+	return cfg.LFSStorage
+}
+
+// LFSContentPath 本地存储 LFS 对象的目录，cloud 方式下用于暂存上传中的对象
+func LFSContentPath() string {
+	if cfg.LFSContentPath == "" {
+		return path.Join(cfg.RepoRoot, "_lfs")
+	}
+	return cfg.LFSContentPath
+}
+
+// LFSDefaultQuota 仓库未单独配置时的 LFS 容量上限，单位Byte，小于0表示不限制
+func LFSDefaultQuota() int64 {
+	return cfg.LFSDefaultQuota
+}
+
+// LFSStorageEndpoint oss 或 minio 地址
+func LFSStorageEndpoint() string {
+	return cfg.LFSStorageEndpoint
+}
+
+func LFSStorageAccessKey() string {
+	return cfg.LFSStorageAccessKey
+}
+
+func LFSStorageSecretKey() string {
+	return cfg.LFSStorageSecretKey
+}
+
+func LFSStorageBucketName() string {
+	return cfg.LFSStorageBucketName
+}
+
+// LFSMaxViewSize 页面展示 LFS 对象内容的最大大小，单位Byte，超过时只返回 pointer 信息
+func LFSMaxViewSize() int64 {
+	return cfg.LFSMaxViewSize
+}
+
+// MirrorCredentialKey 加密镜像认证信息的 AES 密钥，长度需为 16、24 或 32，未配置时不允许保存密码
+func MirrorCredentialKey() string {
+	return cfg.MirrorCredentialKey
+}
+
+// MirrorCheckInterval 检查到期镜像的间隔
+func MirrorCheckInterval() time.Duration {
+	return cfg.MirrorCheckInterval
+}
+
+// MirrorMaxConcurrency 单个实例同时同步的镜像数
+func MirrorMaxConcurrency() int {
+	return cfg.MirrorMaxConcurrency
+}
+
+// MirrorSyncTimeout 单次同步的超时时间，超时未结束的同步可被其他实例重新认领
+func MirrorSyncTimeout() time.Duration {
+	return cfg.MirrorSyncTimeout
+}
+
+// MirrorMinIntervalMinutes 镜像允许配置的最小同步间隔，单位分钟
+func MirrorMinIntervalMinutes() int {
+	return cfg.MirrorMinIntervalMinutes
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/bundle"
 	"github.com/erda-project/erda/internal/tools/gittar/event"
 	"github.com/erda-project/erda/internal/tools/gittar/models"
 	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
@@ -117,12 +118,14 @@ func preReceiveHook(pushEvents []*models.PayloadPushEvent, c *webcontext.Context
 
 // trigger event
 func PostReceiveHook(pushEvents []*models.PayloadPushEvent, c *webcontext.Context) {
-	pusher := c.User
-	repository := c.Repository
+	PostReceive(c.Service, c.Bundle, c.Repository, c.User, pushEvents)
+}
 
+// PostReceive 引用更新后触发推送事件、webhook 并同步合并请求，推送和拉取镜像共用
+func PostReceive(svc *models.Service, bdl *bundle.Bundle, repository *gitmodule.Repository, pusher *models.User, pushEvents []*models.PayloadPushEvent) {
 	size, err := repository.CalcRepoSize()
 	if err == nil {
-		c.Service.UpdateRepoSizeCache(repository.ID, size)
+		svc.UpdateRepoSizeCache(repository.ID, size)
 	}
 
 	repo, err := git.OpenRepository(repository.DiskPath())
@@ -169,7 +172,7 @@ func PostReceiveHook(pushEvents []*models.PayloadPushEvent, c *webcontext.Contex
 		logrus.Infof("%v", pushEvent)
 
 		//trigger eventbox event
-		err := c.Bundle.CreateEvent(&apistructs.EventCreateRequest{
+		err := bdl.CreateEvent(&apistructs.EventCreateRequest{
 			EventHeader: apistructs.EventHeader{
 				ApplicationID: strconv.FormatInt(repository.ApplicationId, 10),
 				ProjectID:     strconv.FormatInt(repository.ProjectId, 10),
@@ -186,13 +189,13 @@ func PostReceiveHook(pushEvents []*models.PayloadPushEvent, c *webcontext.Contex
 		}
 
 		//project system hook
-		projectHooks, err := c.Service.GetProjectHooksByEvent(repository, models.HOOK_EVENT_PUSH, true)
+		projectHooks, err := svc.GetProjectHooksByEvent(repository, models.HOOK_EVENT_PUSH, true)
 		if err != nil {
 			logrus.Error("error get project hooks")
 			continue
 		}
 
-		systemHooks, err := c.Service.GetSystemHooksByEvent(models.HOOK_EVENT_PUSH, true)
+		systemHooks, err := svc.GetSystemHooksByEvent(models.HOOK_EVENT_PUSH, true)
 		if err != nil {
 			logrus.Error("error get system hooks")
 			continue
@@ -215,7 +218,7 @@ func PostReceiveHook(pushEvents []*models.PayloadPushEvent, c *webcontext.Contex
 					Url:            hook.Url,
 					Event:          models.HOOK_EVENT_PUSH,
 				}
-				err := c.Service.CreateHookTask(task)
+				err := svc.CreateHookTask(task)
 				if err != nil {
 					logrus.Errorf("create hookTask error %v %v", err, task)
 					continue
@@ -227,7 +230,7 @@ func PostReceiveHook(pushEvents []*models.PayloadPushEvent, c *webcontext.Contex
 		//更新mr表
 		if !pushEvent.IsTag {
 			branch := strings.TrimPrefix(pushEvent.Ref, gitmodule.BRANCH_PREFIX)
-			err := c.Service.SyncMergeRequest(repository, branch, pushEvent.After, pusher.Id, flag)
+			err := svc.SyncMergeRequest(repository, branch, pushEvent.After, pusher.Id, flag)
 			if err != nil {
 				logrus.Errorf("error sync merge request repo:%s ref:%s err:%s",
 					repository.Path, pushEvent.Ref, err)
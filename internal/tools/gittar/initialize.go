@@ -16,6 +16,7 @@ package gittar
 
 import (
 	"os"
+	"path"
 
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
@@ -27,12 +28,15 @@ import (
 	"github.com/erda-project/erda/internal/tools/gittar/auth"
 	"github.com/erda-project/erda/internal/tools/gittar/cache"
 	"github.com/erda-project/erda/internal/tools/gittar/conf"
+	"github.com/erda-project/erda/internal/tools/gittar/helper"
 	"github.com/erda-project/erda/internal/tools/gittar/models"
 	"github.com/erda-project/erda/internal/tools/gittar/pkg/gc"
 	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/lfs"
 	"github.com/erda-project/erda/internal/tools/gittar/profiling"
 	"github.com/erda-project/erda/internal/tools/gittar/uc"
 	"github.com/erda-project/erda/internal/tools/gittar/webcontext"
+	"github.com/erda-project/erda/pkg/cloudstorage"
 	"github.com/erda-project/erda/pkg/discover"
 	// "terminus.io/dice/telemetry/promxp"
 )
@@ -82,6 +86,16 @@ func (p *provider) Initialize() error {
 	webcontext.WithTokenService(&p.TokenService)
 	webcontext.WithOrgClient(p.Org)
 
+	if err := models.CheckMirrorCredentialKey(); err != nil {
+		panic(err)
+	}
+
+	lfsStore, err := newLFSContentStore()
+	if err != nil {
+		panic(err)
+	}
+	webcontext.WithLFSContentStore(lfsStore)
+
 	e := echo.New()
 	systemGroup := e.Group("/_system")
 	{
@@ -151,9 +165,26 @@ func (p *provider) Initialize() error {
 	// start hook task consumer
 	models.Init(dbClient)
 
+	// sync pull and push mirrors
+	models.WithMirrorPostReceive(func(svc *models.Service, repo *gitmodule.Repository, pusher *models.User, pushEvents []*models.PayloadPushEvent) {
+		helper.PostReceive(svc, diceBundle, repo, pusher, pushEvents)
+	})
+	go models.StartMirrorWorker(dbClient, diceBundle)
+
 	return e.Start(":" + conf.ListenPort())
 }
 
+func newLFSContentStore() (lfs.ContentStore, error) {
+	if conf.LFSStorage() == lfs.StorageCloud {
+		client, err := cloudstorage.New(conf.LFSStorageEndpoint(), conf.LFSStorageAccessKey(), conf.LFSStorageSecretKey())
+		if err != nil {
+			return nil, err
+		}
+		return lfs.NewCloudContentStore(client, conf.LFSStorageBucketName(), path.Join(conf.LFSContentPath(), "tmp"))
+	}
+	return lfs.NewLocalContentStore(conf.LFSContentPath())
+}
+
 func addApiRoutes(g *echo.Group) {
 	g.DELETE("", webcontext.WrapHandler(api.DeleteRepo))
 
@@ -168,6 +199,26 @@ func addApiRoutes(g *echo.Group) {
 	// implements the service_rpc function
 	g.POST("/git-:service", webcontext.WrapHandler(api.ServiceRepoRPC))
 
+	// git lfs
+	g.POST("/info/lfs/objects/batch", webcontext.WrapHandler(api.LFSBatch))
+	g.GET("/info/lfs/objects/:oid", webcontext.WrapHandler(api.LFSDownload))
+	g.PUT("/info/lfs/objects/:oid/:size", webcontext.WrapHandler(api.LFSUpload))
+	g.POST("/info/lfs/verify", webcontext.WrapHandler(api.LFSVerify))
+	g.POST("/info/lfs/locks", webcontext.WrapHandler(api.LFSCreateLock))
+	g.GET("/info/lfs/locks", webcontext.WrapHandler(api.LFSListLocks))
+	g.POST("/info/lfs/locks/verify", webcontext.WrapHandler(api.LFSVerifyLocks))
+	g.POST("/info/lfs/locks/:id/unlock", webcontext.WrapHandler(api.LFSUnlock))
+	g.GET("/lfs-stats", webcontext.WrapHandler(api.GetLFSStats))
+	g.PUT("/lfs-quota", webcontext.WrapHandler(api.UpdateLFSQuota))
+
+	// pull and push mirrors
+	g.GET("/mirrors", webcontext.WrapHandler(api.ListMirrors))
+	g.POST("/mirrors", webcontext.WrapHandler(api.CreateMirror))
+	g.GET("/mirrors/:id", webcontext.WrapHandler(api.GetMirror))
+	g.PUT("/mirrors/:id", webcontext.WrapHandler(api.UpdateMirror))
+	g.DELETE("/mirrors/:id", webcontext.WrapHandler(api.DeleteMirror))
+	g.POST("/mirrors/:id/sync", webcontext.WrapHandler(api.SyncMirror))
+
 	g.GET("/commits/*", webcontext.WrapHandlerWithRepoCheck(api.GetRepoCommits))
 	g.POST("/commits", webcontext.WrapHandler(api.CreateCommit))
 
@@ -196,6 +247,8 @@ func addApiRoutes(g *echo.Group) {
 
 	//merge request
 	g.GET("/merge-stats", webcontext.WrapHandler(api.CheckMergeStatus))
+	g.GET("/merge-settings", webcontext.WrapHandler(api.GetMergeSettings))
+	g.PUT("/merge-settings", webcontext.WrapHandler(api.UpdateMergeSettings))
 	g.GET("/merge-templates", webcontext.WrapHandler(api.GetMergeTemplates))
 	g.GET("/merge-requests/:id", webcontext.WrapHandler(api.GetMergeRequestDetail))
 	g.GET("/merge-requests", webcontext.WrapHandler(api.GetMergeRequests))
@@ -205,6 +258,9 @@ func addApiRoutes(g *echo.Group) {
 	g.POST("/merge-requests/:id/merge", webcontext.WrapHandler(api.Merge))
 	g.POST("/merge-requests/:id/close", webcontext.WrapHandler(api.CloseMR))
 	g.POST("/merge-requests/:id/reopen", webcontext.WrapHandler(api.ReopenMR))
+	g.GET("/merge-requests/:id/approvals", webcontext.WrapHandler(api.GetMergeRequestApprovals))
+	g.POST("/merge-requests/:id/approve", webcontext.WrapHandler(api.ApproveMR))
+	g.POST("/merge-requests/:id/unapprove", webcontext.WrapHandler(api.UnapproveMR))
 	g.GET("/merge-requests/:id/notes", webcontext.WrapHandler(api.QueryNotes))
 	g.POST("/merge-requests/:id/notes", webcontext.WrapHandler(api.CreateNotes))
 	g.POST("/merge-requests/:id/operation-temp-branch", webcontext.WrapHandler(api.OperationTempBranch))
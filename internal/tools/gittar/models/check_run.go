@@ -16,7 +16,10 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jinzhu/gorm"
@@ -26,6 +29,11 @@ import (
 	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
 )
 
+var (
+	ErrRequiredCheckRunsNotPassed = errors.New("required check runs not passed")
+	ErrCheckRunMergeRequestAbsent = errors.New("merge request of check run not found in repo")
+)
+
 type CheckRun struct {
 	ID          int64                     `json:"id"`
 	MrID        int64                     `json:"mrId"`
@@ -46,12 +54,32 @@ type CheckRuns struct {
 	CheckRun []*CheckRun               `json:"checkrun"`
 	Result   apistructs.CheckRunResult `json:"result"`
 	Mergable bool                      `json:"mergable"`
+	// 目标分支规则要求的检查，及其未满足的原因
+	RequiredChecks []string `json:"requiredChecks"`
+	Reasons        []string `json:"reasons"`
 }
 
-func (svc *Service) CreateOrUpdateCheckRun(repo *gitmodule.Repository, request *apistructs.CheckRun) (*apistructs.CheckRun, error) {
+// CreateOrUpdateCheckRun 创建或更新检查结果，检查结果决定能否合并，只允许内部调用或有推送权限的用户写入本仓库合并请求的检查
+func (svc *Service) CreateOrUpdateCheckRun(repo *gitmodule.Repository, user *User, request *apistructs.CheckRun) (*apistructs.CheckRun, error) {
+	if !user.IsInnerUser() {
+		if err := svc.CheckPermission(repo, user, PermissionPush, nil); err != nil {
+			return nil, err
+		}
+	}
+	var count int64
+	err := svc.db.Model(&MergeRequest{}).
+		Where("repo_id = ? and repo_merge_id = ?", repo.ID, request.MrID).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrCheckRunMergeRequestAbsent
+	}
+
 	var checkRun CheckRun
-	err := svc.db.Model(&CheckRun{}).
-		Where("pipeline_id =?", request.PipelineID).
+	err = svc.db.Model(&CheckRun{}).
+		Where("repo_id = ? and mr_id = ? and pipeline_id = ?", repo.ID, request.MrID, request.PipelineID).
 		First(&checkRun).Error
 	if err == nil {
 		// 已存在,更新
@@ -123,6 +151,13 @@ func (svc *Service) QueryCheckRuns(repo *gitmodule.Repository, mrID string) (api
 			res.Result = apistructs.CheckRunResultFailure
 		}
 	}
+	res.RequiredChecks, res.Reasons, err = svc.requiredCheckRunReasons(repo, int64(mergeRequestInfo.RepoMergeId), mergeRequestInfo.TargetBranch, mergeRequestInfo.SourceSha)
+	if err != nil {
+		return apistructs.CheckRuns{}, err
+	}
+	if len(res.Reasons) > 0 {
+		res.Mergable = false
+	}
 	var state apistructs.CheckRuns
 	cont, err := json.Marshal(res)
 	if err != nil {
@@ -148,6 +183,70 @@ func (svc *Service) IsCheckRunsValid(repo *gitmodule.Repository, mrID int64) (bo
 	return count == 0, nil
 }
 
+// CheckRequiredCheckRuns 校验目标分支规则要求的检查是否都已成功
+func (svc *Service) CheckRequiredCheckRuns(repo *gitmodule.Repository, mergeRequest *MergeRequest) error {
+	sourceCommit, err := repo.GetBranchCommit(mergeRequest.SourceBranch)
+	if err != nil {
+		return err
+	}
+	_, reasons, err := svc.requiredCheckRunReasons(repo, int64(mergeRequest.RepoMergeId), mergeRequest.TargetBranch, sourceCommit.ID)
+	if err != nil {
+		return err
+	}
+	if len(reasons) > 0 {
+		return fmt.Errorf("%w: %s", ErrRequiredCheckRunsNotPassed, strings.Join(reasons, "; "))
+	}
+	return nil
+}
+
+// requiredCheckRunReasons 返回目标分支规则要求的检查及其未满足的原因
+// 严格模式下只认源分支最新提交上的检查，否则取合并请求中同名检查最近的一次
+func (svc *Service) requiredCheckRunReasons(repo *gitmodule.Repository, mrID int64, targetBranch, headSha string) ([]string, []string, error) {
+	rule, err := repo.GetValidBranch(targetBranch)
+	if err != nil {
+		return nil, nil, err
+	}
+	required := rule.GetRequiredChecks()
+	if len(required) == 0 {
+		return nil, nil, nil
+	}
+	query := svc.db.Model(&CheckRun{}).Where("mr_id = ? and repo_id = ? and name in (?)", mrID, repo.ID, required)
+	if !rule.StrictRequiredChecks {
+		headSha = ""
+	} else {
+		query = query.Where("commit = ?", headSha)
+	}
+	var checkRuns []*CheckRun
+	if err := query.Find(&checkRuns).Error; err != nil {
+		return nil, nil, err
+	}
+	return required, checkRunReasons(required, checkRuns, headSha), nil
+}
+
+func checkRunReasons(required []string, checkRuns []*CheckRun, headSha string) []string {
+	latest := make(map[string]*CheckRun)
+	for _, each := range checkRuns {
+		if current, ok := latest[each.Name]; !ok || each.ID > current.ID {
+			latest[each.Name] = each
+		}
+	}
+	var reasons []string
+	for _, name := range required {
+		each, ok := latest[name]
+		switch {
+		case !ok && headSha != "":
+			reasons = append(reasons, fmt.Sprintf("required check %s has not run on commit %s", name, headSha))
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("required check %s has not run", name))
+		case each.Status != apistructs.CheckRunStatusCompleted:
+			reasons = append(reasons, fmt.Sprintf("required check %s is still in progress", name))
+		case each.Result != apistructs.CheckRunResultSuccess:
+			reasons = append(reasons, fmt.Sprintf("required check %s finished with %s", name, each.Result))
+		}
+	}
+	return reasons
+}
+
 func (svc *Service) RemoveCheckRuns(mrID int64) error {
 	svc.db.Where("mr_id =? ", mrID).Delete(&CheckRun{})
 	return nil
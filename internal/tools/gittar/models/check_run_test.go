@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/bundle"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+	"github.com/erda-project/erda/pkg/database/dbengine"
+)
+
+func TestCheckRunReasons(t *testing.T) {
+	success := func(id int64, name string) *CheckRun {
+		return &CheckRun{ID: id, Name: name, Status: apistructs.CheckRunStatusCompleted, Result: apistructs.CheckRunResultSuccess}
+	}
+	failed := func(id int64, name string) *CheckRun {
+		return &CheckRun{ID: id, Name: name, Status: apistructs.CheckRunStatusCompleted, Result: apistructs.CheckRunResultFailure}
+	}
+	running := func(id int64, name string) *CheckRun {
+		return &CheckRun{ID: id, Name: name, Status: apistructs.CheckRunStatusInProgress}
+	}
+
+	tests := []struct {
+		name      string
+		required  []string
+		checkRuns []*CheckRun
+		headSha   string
+		want      []string
+	}{
+		{
+			name:      "no required checks",
+			checkRuns: []*CheckRun{failed(1, "lint")},
+		},
+		{
+			name:      "all passed",
+			required:  []string{"lint", "test"},
+			checkRuns: []*CheckRun{success(1, "lint"), success(2, "test")},
+		},
+		{
+			name:     "not run",
+			required: []string{"lint"},
+			want:     []string{"required check lint has not run"},
+		},
+		{
+			name:     "not run on head commit",
+			required: []string{"lint"},
+			headSha:  "abc",
+			want:     []string{"required check lint has not run on commit abc"},
+		},
+		{
+			name:      "in progress",
+			required:  []string{"lint"},
+			checkRuns: []*CheckRun{running(1, "lint")},
+			want:      []string{"required check lint is still in progress"},
+		},
+		{
+			name:      "failed",
+			required:  []string{"lint"},
+			checkRuns: []*CheckRun{failed(1, "lint")},
+			want:      []string{"required check lint finished with failure"},
+		},
+		{
+			name:      "latest run wins",
+			required:  []string{"lint", "test"},
+			checkRuns: []*CheckRun{success(3, "lint"), failed(1, "lint"), success(2, "test"), failed(4, "test")},
+			want:      []string{"required check test finished with failure"},
+		},
+		{
+			name:      "reasons follow required order",
+			required:  []string{"test", "lint", "build"},
+			checkRuns: []*CheckRun{failed(1, "lint"), running(2, "test")},
+			want: []string{
+				"required check test is still in progress",
+				"required check lint finished with failure",
+				"required check build has not run",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, checkRunReasons(tt.required, tt.checkRuns, tt.headSha))
+		})
+	}
+}
+
+func TestCreateOrUpdateCheckRun_Forged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	gormDB, err := gorm.Open("mysql", db)
+	assert.NoError(t, err)
+	bdl := bundle.New()
+	svc := NewService(&DBClient{DBEngine: &dbengine.DBEngine{DB: gormDB}}, bdl)
+	repo := &gitmodule.Repository{ID: 1, ApplicationId: 2}
+	forged := &apistructs.CheckRun{
+		MrID:       3,
+		Name:       "lint",
+		PipelineID: "4",
+		Status:     apistructs.CheckRunStatusCompleted,
+		Result:     apistructs.CheckRunResultSuccess,
+	}
+
+	t.Run("user without push permission", func(t *testing.T) {
+		monkey.PatchInstanceMethod(reflect.TypeOf(bdl), "CheckPermission",
+			func(_ *bundle.Bundle, req *apistructs.PermissionCheckRequest) (*apistructs.PermissionCheckResponseData, error) {
+				assert.Equal(t, string(PermissionPush), req.Action)
+				return &apistructs.PermissionCheckResponseData{Access: false}, nil
+			})
+		defer monkey.UnpatchAll()
+
+		_, err := svc.CreateOrUpdateCheckRun(repo, &User{Id: "5", NickName: "guest"}, forged)
+		assert.Error(t, err)
+	})
+
+	t.Run("merge request of another repo", func(t *testing.T) {
+		mock.ExpectQuery("SELECT count(.+) FROM `merge_requests`").
+			WithArgs(repo.ID, forged.MrID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		_, err := svc.CreateOrUpdateCheckRun(repo, NewInnerUser(), forged)
+		assert.Equal(t, ErrCheckRunMergeRequestAbsent, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
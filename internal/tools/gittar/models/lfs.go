@@ -0,0 +1,255 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/gittar/conf"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/lfs"
+)
+
+var (
+	ErrLFSQuotaExceeded = errors.New("lfs storage quota exceeded")
+	ErrLFSLockExists    = errors.New("lock already exists")
+	ErrLFSLockNotOwner  = errors.New("lock is owned by another user")
+)
+
+const defaultLFSLockListLimit = 100
+
+// LFSObject 仓库引用的 LFS 对象，对象内容在 ContentStore 中按 oid 去重存储
+type LFSObject struct {
+	ID        int64
+	RepoID    int64  `gorm:"unique_index:uk_repo_oid"`
+	Oid       string `gorm:"size:64;unique_index:uk_repo_oid"`
+	Size      int64
+	CreatedAt time.Time
+}
+
+// LFSLock LFS 文件锁
+type LFSLock struct {
+	ID        int64
+	RepoID    int64  `gorm:"unique_index:uk_repo_path"`
+	Path      string `gorm:"size:255;unique_index:uk_repo_path"`
+	Ref       string
+	OwnerId   string
+	OwnerName string
+	CreatedAt time.Time
+}
+
+func (l *LFSLock) ToLock() *lfs.Lock {
+	return &lfs.Lock{
+		ID:       strconv.FormatInt(l.ID, 10),
+		Path:     l.Path,
+		LockedAt: l.CreatedAt,
+		Owner:    &lfs.LockOwner{Name: l.OwnerName},
+	}
+}
+
+// GetLFSObjects 查询仓库已有的 LFS 对象，返回 oid 到对象的映射
+func (svc *Service) GetLFSObjects(repo *gitmodule.Repository, oids []string) (map[string]*LFSObject, error) {
+	result := make(map[string]*LFSObject)
+	if len(oids) == 0 {
+		return result, nil
+	}
+	var objects []*LFSObject
+	err := svc.db.Where("repo_id = ? and oid in (?)", repo.ID, oids).Find(&objects).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, object := range objects {
+		result[object.Oid] = object
+	}
+	return result, nil
+}
+
+// GetLFSObject 查询仓库的 LFS 对象，不存在时返回 nil
+func (svc *Service) GetLFSObject(repo *gitmodule.Repository, oid string) (*LFSObject, error) {
+	objects, err := svc.GetLFSObjects(repo, []string{oid})
+	if err != nil {
+		return nil, err
+	}
+	return objects[oid], nil
+}
+
+// CreateLFSObject 记录仓库引用了该对象，重复记录会被忽略
+func (svc *Service) CreateLFSObject(repo *gitmodule.Repository, p lfs.Pointer) error {
+	existed, err := svc.GetLFSObject(repo, p.Oid)
+	if err != nil {
+		return err
+	}
+	if existed != nil {
+		return nil
+	}
+	return svc.db.Create(&LFSObject{
+		RepoID:    repo.ID,
+		Oid:       p.Oid,
+		Size:      p.Size,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// GetLFSStats 统计仓库的 LFS 对象数量、占用容量和容量上限
+func (svc *Service) GetLFSStats(repo *gitmodule.Repository) (*apistructs.GittarLFSStats, error) {
+	var stats apistructs.GittarLFSStats
+	err := svc.db.Model(&LFSObject{}).Where("repo_id = ?", repo.ID).
+		Select("count(id) as objects, coalesce(sum(size), 0) as `usage`").Row().Scan(&stats.Objects, &stats.Usage)
+	if err != nil {
+		return nil, err
+	}
+	var currentRepo Repo
+	err = svc.db.Table("dice_repos").Where("id = ?", repo.ID).First(&currentRepo).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Quota = currentRepo.LFSQuota
+	if stats.Quota == 0 {
+		stats.Quota = conf.LFSDefaultQuota()
+	}
+	return &stats, nil
+}
+
+// CheckLFSQuota 检查新增 size 字节后是否超出仓库的 LFS 容量上限
+func (svc *Service) CheckLFSQuota(repo *gitmodule.Repository, size int64) error {
+	stats, err := svc.GetLFSStats(repo)
+	if err != nil {
+		return err
+	}
+	if stats.Quota >= 0 && stats.Usage+size > stats.Quota {
+		return ErrLFSQuotaExceeded
+	}
+	return nil
+}
+
+// UpdateLFSQuota 更新仓库的 LFS 容量上限，0 表示使用全局默认值，小于0表示不限制
+func (svc *Service) UpdateLFSQuota(repo *gitmodule.Repository, user *User, quota int64) (*apistructs.GittarLFSStats, error) {
+	// 容量上限属于仓库级配置，与锁定仓库使用同一权限
+	if err := svc.CheckPermission(repo, user, PermissionRepoLocked, nil); err != nil {
+		return nil, err
+	}
+	err := svc.db.Table("dice_repos").Where("id = ?", repo.ID).Update("lfs_quota", quota).Error
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetLFSStats(repo)
+}
+
+// mysqlErrDupEntry ER_DUP_ENTRY
+const mysqlErrDupEntry = 1062
+
+// CreateLFSLock 锁定文件，文件已被锁定时返回已有的锁和 ErrLFSLockExists
+func (svc *Service) CreateLFSLock(repo *gitmodule.Repository, user *User, path string, ref string) (*LFSLock, error) {
+	existed, err := svc.getLFSLockByPath(repo, path)
+	if err == nil {
+		return existed, ErrLFSLockExists
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	lock := LFSLock{
+		RepoID:    repo.ID,
+		Path:      path,
+		Ref:       ref,
+		OwnerId:   user.Id,
+		OwnerName: user.NickName,
+		CreatedAt: time.Now(),
+	}
+	if lock.OwnerName == "" {
+		lock.OwnerName = user.Name
+	}
+	if err = svc.db.Create(&lock).Error; err != nil {
+		// 并发锁定同一文件时由唯一索引 uk_repo_path 兜底
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry {
+			if existed, err = svc.getLFSLockByPath(repo, path); err == nil {
+				return existed, ErrLFSLockExists
+			}
+		}
+		return nil, err
+	}
+	return &lock, nil
+}
+
+func (svc *Service) getLFSLockByPath(repo *gitmodule.Repository, path string) (*LFSLock, error) {
+	var lock LFSLock
+	if err := svc.db.Where("repo_id = ? and path = ?", repo.ID, path).First(&lock).Error; err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// LFSLockQuery 文件锁查询条件，cursor 为上一页返回的 next_cursor
+type LFSLockQuery struct {
+	ID     string
+	Path   string
+	Cursor string
+	Limit  int
+}
+
+// ListLFSLocks 按 id 升序分页查询文件锁，返回下一页的游标
+func (svc *Service) ListLFSLocks(repo *gitmodule.Repository, query LFSLockQuery) ([]*LFSLock, string, error) {
+	db := svc.db.Where("repo_id = ?", repo.ID)
+	if query.ID != "" {
+		db = db.Where("id = ?", query.ID)
+	}
+	if query.Path != "" {
+		db = db.Where("path = ?", query.Path)
+	}
+	if query.Cursor != "" {
+		cursor, err := strconv.ParseInt(query.Cursor, 10, 64)
+		if err != nil {
+			return nil, "", errors.New("invalid cursor")
+		}
+		db = db.Where("id >= ?", cursor)
+	}
+	limit := query.Limit
+	if limit <= 0 || limit > defaultLFSLockListLimit {
+		limit = defaultLFSLockListLimit
+	}
+	var locks []*LFSLock
+	// 多取一条用于判断是否有下一页
+	if err := db.Order("id").Limit(limit + 1).Find(&locks).Error; err != nil {
+		return nil, "", err
+	}
+	var nextCursor string
+	if len(locks) > limit {
+		nextCursor = strconv.FormatInt(locks[limit].ID, 10)
+		locks = locks[:limit]
+	}
+	return locks, nextCursor, nil
+}
+
+// DeleteLFSLock 解锁文件，非持有者只能强制解锁，调用方负责校验强制解锁的权限
+func (svc *Service) DeleteLFSLock(repo *gitmodule.Repository, user *User, id int64, force bool) (*LFSLock, error) {
+	var lock LFSLock
+	err := svc.db.Where("repo_id = ? and id = ?", repo.ID, id).First(&lock).Error
+	if err != nil {
+		return nil, err
+	}
+	if lock.OwnerId != user.Id && !force {
+		return nil, ErrLFSLockNotOwner
+	}
+	if err = svc.db.Delete(&lock).Error; err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
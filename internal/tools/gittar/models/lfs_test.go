@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+	"github.com/erda-project/erda/pkg/database/dbengine"
+)
+
+func TestCreateLFSLock_DuplicateEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	gormDB, err := gorm.Open("mysql", db)
+	assert.NoError(t, err)
+	svc := &Service{db: &DBClient{DBEngine: &dbengine.DBEngine{DB: gormDB}}}
+
+	columns := []string{"id", "repo_id", "path", "ref", "owner_id", "owner_name"}
+	mock.ExpectQuery("SELECT (.+) FROM `lfs_locks`").WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `lfs_locks`").
+		WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry for key 'uk_repo_path'"})
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT (.+) FROM `lfs_locks`").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, "a.bin", "refs/heads/master", "2", "other"))
+
+	lock, err := svc.CreateLFSLock(&gitmodule.Repository{ID: 1}, &User{Id: "1", Name: "me"}, "a.bin", "refs/heads/master")
+	assert.Equal(t, ErrLFSLockExists, err)
+	if assert.NotNil(t, lock) {
+		assert.Equal(t, int64(1), lock.ID)
+		assert.Equal(t, "other", lock.OwnerName)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/codeowners"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+)
+
+var (
+	ErrApproveOwnMergeRequest  = errors.New("author can not approve own merge request")
+	ErrMergeRequestNotApproved = errors.New("merge request approval rules not satisfied")
+)
+
+// MergeRequestApproval 合并请求的评审通过记录，每个评审人只保留最近一次
+type MergeRequestApproval struct {
+	ID        int64
+	RepoID    int64  `gorm:"index:idx_repo_id"`
+	MergeID   int64  `gorm:"unique_index:uk_merge_user"`
+	UserId    string `gorm:"size:64;unique_index:uk_merge_user"`
+	UserName  string
+	Email     string
+	CommitSha string
+	CreatedAt time.Time
+}
+
+func (approval *MergeRequestApproval) ToInfo(stale bool) *apistructs.MergeRequestApproval {
+	return &apistructs.MergeRequestApproval{
+		UserID:    approval.UserId,
+		UserName:  approval.UserName,
+		Email:     approval.Email,
+		CommitSha: approval.CommitSha,
+		Stale:     stale,
+		CreatedAt: approval.CreatedAt,
+	}
+}
+
+// ApproveMergeRequest 评审通过合并请求，记录评审时源分支的提交
+func (svc *Service) ApproveMergeRequest(repo *gitmodule.Repository, user *User, mergeId int) (*apistructs.MergeRequestApprovalStatus, error) {
+	mergeRequest, err := svc.getOpenMergeRequest(repo, mergeId)
+	if err != nil {
+		return nil, err
+	}
+	if mergeRequest.AuthorId == user.Id {
+		return nil, ErrApproveOwnMergeRequest
+	}
+	err = svc.CheckPermission(repo, user, PermissionCreateMR, getMrUserRole(*mergeRequest, user.Id))
+	if err != nil {
+		return nil, err
+	}
+	sourceCommit, err := repo.GetBranchCommit(mergeRequest.SourceBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	approval := MergeRequestApproval{
+		RepoID:    repo.ID,
+		MergeID:   mergeRequest.ID,
+		UserId:    user.Id,
+		UserName:  user.Name,
+		Email:     user.Email,
+		CommitSha: sourceCommit.ID,
+	}
+	err = svc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("merge_id = ? and user_id = ?", mergeRequest.ID, user.Id).Delete(&MergeRequestApproval{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&approval).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return svc.evaluateApprovals(repo, mergeRequest)
+}
+
+// UnapproveMergeRequest 撤销当前用户的评审通过
+func (svc *Service) UnapproveMergeRequest(repo *gitmodule.Repository, user *User, mergeId int) (*apistructs.MergeRequestApprovalStatus, error) {
+	mergeRequest, err := svc.getOpenMergeRequest(repo, mergeId)
+	if err != nil {
+		return nil, err
+	}
+	err = svc.db.Where("merge_id = ? and user_id = ?", mergeRequest.ID, user.Id).Delete(&MergeRequestApproval{}).Error
+	if err != nil {
+		return nil, err
+	}
+	return svc.evaluateApprovals(repo, mergeRequest)
+}
+
+// GetMergeRequestApprovalStatus 查询合并请求的评审状态
+func (svc *Service) GetMergeRequestApprovalStatus(repo *gitmodule.Repository, mergeId int) (*apistructs.MergeRequestApprovalStatus, error) {
+	var mergeRequest MergeRequest
+	err := svc.db.Where("repo_id = ? and repo_merge_id = ?", repo.ID, mergeId).First(&mergeRequest).Error
+	if err != nil {
+		return nil, err
+	}
+	return svc.evaluateApprovals(repo, &mergeRequest)
+}
+
+// CheckMergeRequestApprovals 校验合并请求是否满足目标分支的评审规则
+func (svc *Service) CheckMergeRequestApprovals(repo *gitmodule.Repository, mergeRequest *MergeRequest) error {
+	status, err := svc.evaluateApprovals(repo, mergeRequest)
+	if err != nil {
+		return err
+	}
+	if status.Satisfied {
+		return nil
+	}
+	var reasons []string
+	if status.Approved < status.MinApprovals {
+		reasons = append(reasons, fmt.Sprintf("%d of %d required approvals", status.Approved, status.MinApprovals))
+	}
+	for _, rule := range status.MissingCodeOwners {
+		reasons = append(reasons, fmt.Sprintf("%s requires approval from %s", rule.Pattern, strings.Join(rule.Owners, " ")))
+	}
+	return fmt.Errorf("%w: %s", ErrMergeRequestNotApproved, strings.Join(reasons, "; "))
+}
+
+// DismissStaleApprovals 源分支有新提交时，删除目标分支规则要求失效的评审
+func (svc *Service) DismissStaleApprovals(repo *gitmodule.Repository, mergeRequest *MergeRequest) error {
+	rule, err := repo.GetValidBranch(mergeRequest.TargetBranch)
+	if err != nil {
+		return err
+	}
+	if !rule.DismissStaleApprovals {
+		return nil
+	}
+	return svc.db.Where("merge_id = ? and commit_sha <> ?", mergeRequest.ID, mergeRequest.SourceSha).
+		Delete(&MergeRequestApproval{}).Error
+}
+
+func (svc *Service) getOpenMergeRequest(repo *gitmodule.Repository, mergeId int) (*MergeRequest, error) {
+	var mergeRequest MergeRequest
+	err := svc.db.Where("repo_id = ? and repo_merge_id = ?", repo.ID, mergeId).First(&mergeRequest).Error
+	if err != nil {
+		return nil, err
+	}
+	if mergeRequest.State != MERGE_REQUEST_OPEN {
+		return nil, errors.New(mergeRequest.State + " 状态无法评审")
+	}
+	return &mergeRequest, nil
+}
+
+// evaluateApprovals 按目标分支规则计算评审状态
+// 作者本人的记录和失效的记录不计入，CODEOWNERS 取目标分支上的版本，避免合并请求修改自身的负责人
+func (svc *Service) evaluateApprovals(repo *gitmodule.Repository, mergeRequest *MergeRequest) (*apistructs.MergeRequestApprovalStatus, error) {
+	rule, err := repo.GetValidBranch(mergeRequest.TargetBranch)
+	if err != nil {
+		return nil, err
+	}
+	status := &apistructs.MergeRequestApprovalStatus{
+		Approvals:                []*apistructs.MergeRequestApproval{},
+		MinApprovals:             rule.MinApprovals,
+		RequireCodeOwnerApproval: rule.RequireCodeOwnerApproval,
+		MissingCodeOwners:        []*apistructs.CodeOwnerRule{},
+	}
+
+	var approvals []*MergeRequestApproval
+	err = svc.db.Where("merge_id = ?", mergeRequest.ID).Order("created_at").Find(&approvals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// 已合并或关闭的合并请求保留评审时的状态
+	sourceSha := mergeRequest.SourceSha
+	if mergeRequest.State == MERGE_REQUEST_OPEN {
+		sourceCommit, err := repo.GetBranchCommit(mergeRequest.SourceBranch)
+		if err != nil {
+			return nil, err
+		}
+		sourceSha = sourceCommit.ID
+	}
+
+	var approvers []codeowners.Approver
+	for _, approval := range approvals {
+		stale := rule.DismissStaleApprovals && approval.CommitSha != sourceSha
+		status.Approvals = append(status.Approvals, approval.ToInfo(stale))
+		if stale || approval.UserId == mergeRequest.AuthorId {
+			continue
+		}
+		status.Approved++
+		approvers = append(approvers, codeowners.Approver{Username: approval.UserName, Email: approval.Email})
+	}
+
+	if rule.RequireCodeOwnerApproval && mergeRequest.State == MERGE_REQUEST_OPEN {
+		targetCommit, err := repo.GetBranchCommit(mergeRequest.TargetBranch)
+		if err != nil {
+			return nil, err
+		}
+		owners, err := readCodeOwners(repo, targetCommit.ID)
+		if err != nil {
+			return nil, err
+		}
+		if owners != nil {
+			files, err := repo.FilesChangedBetween(targetCommit.ID, sourceSha)
+			if err != nil {
+				return nil, err
+			}
+			for _, missing := range owners.MissingApprovals(files, approvers) {
+				status.MissingCodeOwners = append(status.MissingCodeOwners, &apistructs.CodeOwnerRule{
+					Pattern: missing.Pattern,
+					Owners:  missing.Owners,
+				})
+			}
+		}
+	}
+
+	status.Satisfied = status.Approved >= status.MinApprovals && len(status.MissingCodeOwners) == 0
+	return status, nil
+}
+
+// readCodeOwners 读取指定提交中的 CODEOWNERS 文件，不存在时返回 nil
+func readCodeOwners(repo *gitmodule.Repository, commitID string) (*codeowners.File, error) {
+	for _, path := range codeowners.Paths {
+		// 提交已确认存在，此处的错误都是路径不存在
+		entry, err := repo.GetTreeEntryByPath(commitID, path)
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		reader, err := entry.Blob().Data()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		return codeowners.Parse(data)
+	}
+	return nil, nil
+}
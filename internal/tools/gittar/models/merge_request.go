@@ -63,7 +63,8 @@ type MergeQueryCondition struct {
 
 type MergeOptions struct {
 	RemoveSourceBranch bool   `json:"removeSourceBranch"`
-	CommitMessage      string `json:"CommitMessage"`
+	CommitMessage      string `json:"CommitMessage"` // merge 和 squash 方式的提交信息
+	Strategy           string `json:"strategy"`      // 为空时使用合并请求上选择的方式
 }
 
 // MergeRequest model
@@ -92,6 +93,7 @@ type MergeRequest struct {
 	ScoreNum             int    `gorm:"size:150;index:idx_score_num"`
 	JoinTempBranchStatus string `gorm:"join_temp_branch_status"`
 	IsJoinTempBranch     bool   `gorm:"is_join_temp_branch"`
+	MergeStrategy        string
 }
 
 type MrCheckRun struct {
@@ -127,6 +129,7 @@ func (mergeRequest *MergeRequest) ToInfo(repo *gitmodule.Repository) *apistructs
 	result.ScoreNum = mergeRequest.ScoreNum
 	result.JoinTempBranchStatus = mergeRequest.JoinTempBranchStatus
 	result.IsJoinTempBranch = mergeRequest.IsJoinTempBranch
+	result.MergeStrategy = mergeRequest.MergeStrategy
 
 	if mergeRequest.SourceBranch != "" && mergeRequest.TargetBranch != "" {
 		result.DefaultCommitMessage = fmt.Sprintf("Merge branch '%s' into '%s'", mergeRequest.SourceBranch, mergeRequest.TargetBranch)
@@ -183,6 +186,13 @@ func (svc *Service) CreateMergeRequest(repo *gitmodule.Repository, user *User, i
 		return nil, err
 	}
 
+	// 未指定合并方式时跟随仓库默认方式，合并时再确定
+	if info.MergeStrategy != "" {
+		if _, err = svc.ResolveMergeStrategy(repo, info.MergeStrategy); err != nil {
+			return nil, err
+		}
+	}
+
 	sourceCommit, err := repo.GetBranchCommit(info.SourceBranch)
 	if err != nil {
 		return nil, err
@@ -206,6 +216,7 @@ func (svc *Service) CreateMergeRequest(repo *gitmodule.Repository, user *User, i
 		TargetSha:          targetCommit.ID,
 		RemoveSourceBranch: info.RemoveSourceBranch,
 		RepoMergeId:        lastMr.RepoMergeId + 1,
+		MergeStrategy:      info.MergeStrategy,
 	}
 	err = svc.db.Create(&mergeRequest).Error
 	if err != nil {
@@ -247,6 +258,12 @@ func (svc *Service) UpdateMergeRequest(repo *gitmodule.Repository, user *User, i
 		mergeRequest.Description = info.Description
 		mergeRequest.RemoveSourceBranch = info.RemoveSourceBranch
 		mergeRequest.AssigneeId = info.AssigneeId
+		if info.MergeStrategy != "" {
+			if _, err = svc.ResolveMergeStrategy(repo, info.MergeStrategy); err != nil {
+				return nil, err
+			}
+		}
+		mergeRequest.MergeStrategy = info.MergeStrategy
 	}
 
 	if len(info.State) > 0 {
@@ -321,9 +338,17 @@ func (svc *Service) SyncMergeRequest(repo *gitmodule.Repository, branch string,
 		}
 		mrInfo := mergeRequest.ToInfo(repo)
 		if flag {
+			if err := svc.DismissStaleApprovals(repo, &mergeRequest); err != nil {
+				logrus.Errorf("failed to dismiss stale approvals of mr %d, err: %v", mergeRequest.ID, err)
+			}
 			go func(mergeRequest MergeRequest) {
 				// check-run
-				conflictInfo, err := repo.GetMergeStatus(mergeRequest.SourceBranch, mergeRequest.TargetBranch)
+				strategy, err := svc.ResolveMergeStrategy(repo, mergeRequest.MergeStrategy)
+				if err != nil {
+					logrus.Errorf("failed to resolve merge strategy, err: %v", err)
+					return
+				}
+				conflictInfo, err := repo.GetMergeStatusWithStrategy(mergeRequest.SourceBranch, mergeRequest.TargetBranch, strategy)
 				if err != nil {
 					logrus.Info("has conflict, err: ", err)
 					return
@@ -460,7 +485,16 @@ func (svc *Service) Merge(repo *gitmodule.Repository, user *User, mergeId int, m
 		return nil, err
 	}
 
-	mergeStatus, err := repo.GetMergeStatus(mergeRequest.SourceBranch, mergeRequest.TargetBranch)
+	strategyName := mergeOptions.Strategy
+	if strategyName == "" {
+		strategyName = mergeRequest.MergeStrategy
+	}
+	strategy, err := svc.ResolveMergeStrategy(repo, strategyName)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeStatus, err := repo.GetMergeStatusWithStrategy(mergeRequest.SourceBranch, mergeRequest.TargetBranch, strategy)
 	if err != nil {
 		return nil, err
 	}
@@ -469,10 +503,18 @@ func (svc *Service) Merge(repo *gitmodule.Repository, user *User, mergeId int, m
 	}
 
 	if mergeStatus.HasConflict {
-		return nil, errors.New("has conflict")
+		if strategy == gitmodule.MergeStrategyFastForward {
+			return nil, gitmodule.ErrNotFastForward
+		}
+		return nil, gitmodule.ErrMergeConflict
 	}
 
-	if repo.IsProtectBranch(mergeRequest.TargetBranch) ||
+	// 获取不到目标分支规则时不能合并，否则会跳过保护分支、评审和检查的校验
+	targetRule, err := repo.GetValidBranch(mergeRequest.TargetBranch)
+	if err != nil {
+		return nil, err
+	}
+	if targetRule.IsProtect ||
 		(repo.IsProtectBranch(mergeRequest.SourceBranch) && mergeRequest.RemoveSourceBranch) {
 		err = svc.CheckPermission(repo, user, PermissionPushProtectBranch, nil)
 		if err != nil {
@@ -480,15 +522,25 @@ func (svc *Service) Merge(repo *gitmodule.Repository, user *User, mergeId int, m
 		}
 	}
 
+	err = svc.CheckMergeRequestApprovals(repo, &mergeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.CheckRequiredCheckRuns(repo, &mergeRequest)
+	if err != nil {
+		return nil, err
+	}
+
 	if mergeOptions.CommitMessage == "" {
-		mergeOptions.CommitMessage = fmt.Sprintf("Merge branch '%s' into '%s'", mergeRequest.SourceBranch, mergeRequest.TargetBranch)
+		mergeOptions.CommitMessage = defaultMergeCommitMessage(mergeRequest, strategy)
 	}
 	_, err = repo.GetBranchCommit(mergeRequest.SourceBranch)
 	if err != nil {
 		return nil, err
 	}
 
-	commit, err := repo.Merge(mergeRequest.SourceBranch, mergeRequest.TargetBranch, user.ToGitSignature(), mergeOptions.CommitMessage)
+	commit, err := repo.MergeWithStrategy(strategy, mergeRequest.SourceBranch, mergeRequest.TargetBranch, user.ToGitSignature(), mergeOptions.CommitMessage)
 
 	now := time.Now()
 	if err == nil {
@@ -496,6 +548,7 @@ func (svc *Service) Merge(repo *gitmodule.Repository, user *User, mergeId int, m
 		mergeRequest.MergeCommitSha = commit.ID
 		mergeRequest.MergeAt = &now
 		mergeRequest.MergeUserId = user.Id
+		mergeRequest.MergeStrategy = string(strategy)
 		err := svc.db.Save(&mergeRequest).Error
 		if err != nil {
 			return nil, err
@@ -511,6 +564,46 @@ func (svc *Service) Merge(repo *gitmodule.Repository, user *User, mergeId int, m
 	return commit, nil
 }
 
+// ErrMergeWithBranchNeedsMR 目标分支要求评审或检查时，不能绕过合并请求直接合并
+var ErrMergeWithBranchNeedsMR = errors.New("target branch requires approvals or check runs, merge it with a merge request")
+
+// CheckMergeWithBranch 不经过合并请求直接合并分支前的校验，保护分支规则与 Merge 一致
+// 直接合并没有评审和检查记录，目标分支要求评审或检查时直接拒绝
+func (svc *Service) CheckMergeWithBranch(repo *gitmodule.Repository, user *User, targetBranch string) error {
+	targetRule, err := repo.GetValidBranch(targetBranch)
+	if err != nil {
+		return err
+	}
+	permission := PermissionPush
+	if targetRule.IsProtect {
+		permission = PermissionPushProtectBranch
+	}
+	if err = svc.CheckPermission(repo, user, permission, nil); err != nil {
+		return err
+	}
+	if requiresMergeRequest(targetRule) {
+		return ErrMergeWithBranchNeedsMR
+	}
+	return nil
+}
+
+// requiresMergeRequest 分支规则是否要求评审或检查
+func requiresMergeRequest(rule *apistructs.ValidBranch) bool {
+	return rule.MinApprovals > 0 || rule.RequireCodeOwnerApproval || len(rule.GetRequiredChecks()) > 0
+}
+
+// defaultMergeCommitMessage squash 方式默认使用合并请求的标题和描述作为提交信息
+func defaultMergeCommitMessage(mergeRequest MergeRequest, strategy gitmodule.MergeStrategy) string {
+	if strategy == gitmodule.MergeStrategySquash {
+		message := fmt.Sprintf("%s (!%d)", mergeRequest.Title, mergeRequest.RepoMergeId)
+		if mergeRequest.Description != "" {
+			message += "\n\n" + mergeRequest.Description
+		}
+		return message
+	}
+	return fmt.Sprintf("Merge branch '%s' into '%s'", mergeRequest.SourceBranch, mergeRequest.TargetBranch)
+}
+
 func (svc *Service) CloseMR(repo *gitmodule.Repository, user *User, mergeId int) (*apistructs.MergeRequestInfo, error) {
 	var mergeRequest MergeRequest
 	err := svc.db.Where("repo_id=? and repo_merge_id=?", repo.ID, mergeId).First(&mergeRequest).Error
@@ -0,0 +1,42 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+func TestRequiresMergeRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		rule apistructs.ValidBranch
+		want bool
+	}{
+		{name: "no rules", rule: apistructs.ValidBranch{IsProtect: true}},
+		{name: "min approvals", rule: apistructs.ValidBranch{MinApprovals: 1}, want: true},
+		{name: "code owner approval", rule: apistructs.ValidBranch{RequireCodeOwnerApproval: true}, want: true},
+		{name: "required checks", rule: apistructs.ValidBranch{RequiredChecks: "lint"}, want: true},
+		{name: "blank required checks", rule: apistructs.ValidBranch{RequiredChecks: " , "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, requiresMergeRequest(&tt.rule))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+)
+
+// GetMergeSettings 获取仓库允许的合并方式
+func (svc *Service) GetMergeSettings(repo *gitmodule.Repository) (*apistructs.GittarMergeSettings, error) {
+	allowed, defaultStrategy, err := svc.getMergeStrategies(repo)
+	if err != nil {
+		return nil, err
+	}
+	defaultStrategy, err = gitmodule.ResolveMergeStrategy(allowed, defaultStrategy, "")
+	if err != nil {
+		return nil, err
+	}
+	settings := &apistructs.GittarMergeSettings{
+		AllowedStrategies: make([]string, 0, len(allowed)),
+		DefaultStrategy:   string(defaultStrategy),
+	}
+	for _, strategy := range allowed {
+		settings.AllowedStrategies = append(settings.AllowedStrategies, string(strategy))
+	}
+	return settings, nil
+}
+
+// UpdateMergeSettings 更新仓库允许的合并方式，默认方式必须在允许列表中
+func (svc *Service) UpdateMergeSettings(repo *gitmodule.Repository, user *User, settings *apistructs.GittarMergeSettings) (*apistructs.GittarMergeSettings, error) {
+	// 合并方式属于仓库级配置，与锁定仓库使用同一权限
+	if err := svc.CheckPermission(repo, user, PermissionRepoLocked, nil); err != nil {
+		return nil, err
+	}
+	var strategies []gitmodule.MergeStrategy
+	for _, item := range settings.AllowedStrategies {
+		strategies = append(strategies, gitmodule.MergeStrategy(item))
+	}
+	allowed, err := gitmodule.ParseMergeStrategies(gitmodule.FormatMergeStrategies(strategies))
+	if err != nil {
+		return nil, err
+	}
+	defaultStrategy := gitmodule.MergeStrategy(settings.DefaultStrategy)
+	if defaultStrategy != "" {
+		if _, err := gitmodule.ResolveMergeStrategy(allowed, "", defaultStrategy); err != nil {
+			return nil, err
+		}
+	}
+
+	err = svc.db.Table("dice_repos").Where("id = ?", repo.ID).Updates(map[string]interface{}{
+		"merge_strategies":       gitmodule.FormatMergeStrategies(strategies),
+		"default_merge_strategy": string(defaultStrategy),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetMergeSettings(repo)
+}
+
+// ResolveMergeStrategy 确定合并请求使用的合并方式，requested 为空时使用仓库默认方式
+func (svc *Service) ResolveMergeStrategy(repo *gitmodule.Repository, requested string) (gitmodule.MergeStrategy, error) {
+	allowed, defaultStrategy, err := svc.getMergeStrategies(repo)
+	if err != nil {
+		return "", err
+	}
+	return gitmodule.ResolveMergeStrategy(allowed, defaultStrategy, gitmodule.MergeStrategy(requested))
+}
+
+func (svc *Service) getMergeStrategies(repo *gitmodule.Repository) ([]gitmodule.MergeStrategy, gitmodule.MergeStrategy, error) {
+	var currentRepo Repo
+	err := svc.db.Table("dice_repos").Where("id = ?", repo.ID).First(&currentRepo).Error
+	if err != nil {
+		return nil, "", err
+	}
+	allowed, err := gitmodule.ParseMergeStrategies(currentRepo.MergeStrategies)
+	if err != nil {
+		return nil, "", err
+	}
+	return allowed, gitmodule.MergeStrategy(currentRepo.DefaultMergeStrategy), nil
+}
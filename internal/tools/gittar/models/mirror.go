@@ -0,0 +1,583 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/bundle"
+	"github.com/erda-project/erda/internal/tools/gittar/conf"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+	"github.com/erda-project/erda/pkg/crypto/encrypt"
+)
+
+const (
+	MirrorStatusPending = "pending"
+	MirrorStatusSyncing = "syncing"
+	MirrorStatusSuccess = "success"
+	MirrorStatusFailed  = "failed"
+
+	defaultMirrorIntervalMinutes = 60
+	maxMirrorErrorLength         = 2000
+)
+
+var (
+	ErrMirrorSyncing             = errors.New("mirror is already syncing")
+	ErrMirrorCredentialKeyNotSet = errors.New("mirror credential key is not configured")
+	ErrMirrorRepoLocked          = errors.New("repository is locked, pull mirror is paused")
+	ErrMirrorRefsSkipped         = errors.New("mirror refs skipped by branch rules")
+)
+
+// MirrorPostReceiveFunc 拉取镜像更新引用后调用，与推送一样触发事件和同步合并请求
+type MirrorPostReceiveFunc func(svc *Service, repo *gitmodule.Repository, pusher *User, pushEvents []*PayloadPushEvent)
+
+var mirrorPostReceive MirrorPostReceiveFunc
+
+// WithMirrorPostReceive 设置拉取镜像更新引用后的回调，需在启动镜像同步前调用
+func WithMirrorPostReceive(postReceive MirrorPostReceiveFunc) {
+	mirrorPostReceive = postReceive
+}
+
+// Mirror 仓库镜像配置及最近一次同步状态
+type Mirror struct {
+	ID        int64
+	RepoID    int64 `gorm:"index:idx_repo_id"`
+	Direction string
+	URL       string
+	Username  string
+	// Password 使用 GITTAR_MIRROR_CREDENTIAL_KEY 加密存储
+	Password string
+	// RefFilters 逗号分隔的分支或标签过滤规则，为空表示全部
+	RefFilters string
+	// SyncInterval 同步间隔，单位分钟
+	SyncInterval  int
+	Enabled       bool
+	Status        string
+	LastError     string `gorm:"type:text"`
+	LastSyncAt    *time.Time
+	LastSuccessAt *time.Time
+	NextSyncAt    time.Time `gorm:"index:idx_next_sync_at"`
+	// SyncStartedAt 认领同步的时间，超过 GITTAR_MIRROR_SYNC_TIMEOUT 仍未结束的同步可被重新认领
+	SyncStartedAt *time.Time
+	// MirroredRefs 镜像上次同步写入目标端的引用及提交，json 格式，只有这些引用会被镜像删除
+	MirroredRefs string `gorm:"type:mediumtext"`
+	CreatorID    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (mirror *Mirror) ToInfo() *apistructs.GittarRepoMirror {
+	return &apistructs.GittarRepoMirror{
+		ID:            mirror.ID,
+		Direction:     mirror.Direction,
+		URL:           mirror.URL,
+		Username:      mirror.Username,
+		HasPassword:   mirror.Password != "",
+		RefFilters:    gitmodule.ParseMirrorRefFilters(mirror.RefFilters),
+		Interval:      mirror.SyncInterval,
+		Enabled:       mirror.Enabled,
+		Status:        mirror.Status,
+		LastError:     mirror.LastError,
+		LastSyncAt:    mirror.LastSyncAt,
+		LastSuccessAt: mirror.LastSuccessAt,
+		NextSyncAt:    mirror.NextSyncAt,
+		CreatorID:     mirror.CreatorID,
+		CreatedAt:     mirror.CreatedAt,
+		UpdatedAt:     mirror.UpdatedAt,
+	}
+}
+
+// CheckMirrorCredentialKey 校验镜像认证信息的加密密钥，未配置时只能使用无需认证的镜像
+func CheckMirrorCredentialKey() error {
+	key := conf.MirrorCredentialKey()
+	if key == "" {
+		return nil
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+	return fmt.Errorf("invalid GITTAR_MIRROR_CREDENTIAL_KEY length %d, must be 16, 24 or 32", len(key))
+}
+
+// ListMirrors 查询仓库的镜像
+func (svc *Service) ListMirrors(repo *gitmodule.Repository) ([]*apistructs.GittarRepoMirror, error) {
+	var mirrors []*Mirror
+	err := svc.db.Where("repo_id = ?", repo.ID).Order("id").Find(&mirrors).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*apistructs.GittarRepoMirror, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		result = append(result, mirror.ToInfo())
+	}
+	return result, nil
+}
+
+// GetMirror 查询镜像配置及同步状态
+func (svc *Service) GetMirror(repo *gitmodule.Repository, id int64) (*apistructs.GittarRepoMirror, error) {
+	mirror, err := svc.getMirror(repo, id)
+	if err != nil {
+		return nil, err
+	}
+	return mirror.ToInfo(), nil
+}
+
+// CreateMirror 创建镜像，创建后尽快同步一次
+func (svc *Service) CreateMirror(repo *gitmodule.Repository, user *User, request *apistructs.GittarRepoMirrorRequest) (*apistructs.GittarRepoMirror, error) {
+	// 镜像属于仓库级配置，与锁定仓库使用同一权限
+	if err := svc.CheckPermission(repo, user, PermissionRepoLocked, nil); err != nil {
+		return nil, err
+	}
+	mirror := &Mirror{
+		RepoID:     repo.ID,
+		Enabled:    true,
+		Status:     MirrorStatusPending,
+		NextSyncAt: time.Now(),
+		CreatorID:  user.Id,
+	}
+	if err := svc.applyMirrorRequest(repo, mirror, request); err != nil {
+		return nil, err
+	}
+	if err := svc.db.Create(mirror).Error; err != nil {
+		return nil, err
+	}
+	return mirror.ToInfo(), nil
+}
+
+// UpdateMirror 更新镜像配置，只更新配置字段，不影响进行中的同步
+func (svc *Service) UpdateMirror(repo *gitmodule.Repository, user *User, id int64, request *apistructs.GittarRepoMirrorRequest) (*apistructs.GittarRepoMirror, error) {
+	if err := svc.CheckPermission(repo, user, PermissionRepoLocked, nil); err != nil {
+		return nil, err
+	}
+	mirror, err := svc.getMirror(repo, id)
+	if err != nil {
+		return nil, err
+	}
+	direction, remote := mirror.Direction, mirror.URL
+	if err := svc.applyMirrorRequest(repo, mirror, request); err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{
+		"direction":     mirror.Direction,
+		"url":           mirror.URL,
+		"username":      mirror.Username,
+		"password":      mirror.Password,
+		"ref_filters":   mirror.RefFilters,
+		"sync_interval": mirror.SyncInterval,
+		"enabled":       mirror.Enabled,
+		"next_sync_at":  time.Now(),
+	}
+	// 换了同步对象后，之前同步的引用不再归镜像管理
+	if mirror.Direction != direction || mirror.URL != remote {
+		updates["mirrored_refs"] = ""
+	}
+	err = svc.db.Model(&Mirror{}).Where("id = ?", mirror.ID).Updates(updates).Error
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetMirror(repo, id)
+}
+
+// DeleteMirror 删除镜像，进行中的同步不会被中断
+func (svc *Service) DeleteMirror(repo *gitmodule.Repository, user *User, id int64) error {
+	if err := svc.CheckPermission(repo, user, PermissionRepoLocked, nil); err != nil {
+		return err
+	}
+	mirror, err := svc.getMirror(repo, id)
+	if err != nil {
+		return err
+	}
+	return svc.db.Delete(mirror).Error
+}
+
+// RemoveMirrors 删除仓库时清理镜像
+func (svc *Service) RemoveMirrors(repository *Repo) error {
+	return svc.db.Where("repo_id = ?", repository.ID).Delete(&Mirror{}).Error
+}
+
+// SyncMirrorNow 立即在后台同步镜像，镜像正在同步时返回 ErrMirrorSyncing
+func (svc *Service) SyncMirrorNow(repo *gitmodule.Repository, user *User, id int64) (*apistructs.GittarRepoMirror, error) {
+	if err := svc.CheckPermission(repo, user, PermissionRepoLocked, nil); err != nil {
+		return nil, err
+	}
+	mirror, err := svc.getMirror(repo, id)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := svc.claimMirror(mirror)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrMirrorSyncing
+	}
+	go svc.syncMirror(mirror)
+	return mirror.ToInfo(), nil
+}
+
+func (svc *Service) getMirror(repo *gitmodule.Repository, id int64) (*Mirror, error) {
+	var mirror Mirror
+	err := svc.db.Where("repo_id = ? and id = ?", repo.ID, id).First(&mirror).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mirror, nil
+}
+
+func (svc *Service) applyMirrorRequest(repo *gitmodule.Repository, mirror *Mirror, request *apistructs.GittarRepoMirrorRequest) error {
+	switch request.Direction {
+	case gitmodule.MirrorDirectionPull:
+		// 外置仓库本身从 origin 同步，多个拉取源之间会相互覆盖
+		if repo.IsExternal {
+			return errors.New("external repository can not have pull mirror")
+		}
+		var count int
+		err := svc.db.Model(&Mirror{}).Where("repo_id = ? and direction = ? and id <> ?", repo.ID, gitmodule.MirrorDirectionPull, mirror.ID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("repository already has a pull mirror")
+		}
+	case gitmodule.MirrorDirectionPush:
+	default:
+		return fmt.Errorf("invalid mirror direction: %s", request.Direction)
+	}
+
+	remote, err := url.Parse(request.URL)
+	if err != nil {
+		return err
+	}
+	if (remote.Scheme != "http" && remote.Scheme != "https") || remote.Host == "" {
+		return fmt.Errorf("invalid mirror url: %s, only http and https are supported", request.URL)
+	}
+	if remote.User != nil {
+		return errors.New("mirror url must not contain credentials, use username and password instead")
+	}
+
+	interval := request.Interval
+	if interval == 0 {
+		interval = defaultMirrorIntervalMinutes
+	}
+	if interval < conf.MirrorMinIntervalMinutes() {
+		return fmt.Errorf("mirror interval must be at least %d minutes", conf.MirrorMinIntervalMinutes())
+	}
+
+	var filters []string
+	for _, filter := range request.RefFilters {
+		filter = strings.TrimSpace(filter)
+		if filter == "" {
+			continue
+		}
+		if strings.Contains(filter, ",") {
+			return fmt.Errorf("invalid ref filter: %s", filter)
+		}
+		if _, err := path.Match(filter, ""); err != nil {
+			return fmt.Errorf("invalid ref filter: %s", filter)
+		}
+		filters = append(filters, filter)
+	}
+
+	if request.Password != "" {
+		if request.Username == "" {
+			return errors.New("username is required when password is set")
+		}
+		if conf.MirrorCredentialKey() == "" {
+			return ErrMirrorCredentialKeyNotSet
+		}
+		mirror.Password = encrypt.AesEncrypt(request.Password, conf.MirrorCredentialKey())
+	}
+	if request.Username == "" {
+		mirror.Password = ""
+	}
+
+	mirror.Direction = request.Direction
+	mirror.URL = remote.String()
+	mirror.Username = request.Username
+	mirror.RefFilters = strings.Join(filters, ",")
+	mirror.SyncInterval = interval
+	if request.Enabled != nil {
+		mirror.Enabled = *request.Enabled
+	}
+	return nil
+}
+
+// StartMirrorWorker 定期同步到期的镜像
+// 每个实例都会扫描到期镜像，通过数据库中的状态认领，保证同一镜像同时只在一个实例上同步
+func StartMirrorWorker(db *DBClient, bdl *bundle.Bundle) {
+	svc := NewService(db, bdl)
+	ticker := time.NewTicker(conf.MirrorCheckInterval())
+	defer ticker.Stop()
+	for range ticker.C {
+		svc.syncDueMirrors()
+	}
+}
+
+func (svc *Service) syncDueMirrors() {
+	now := time.Now()
+	var mirrors []*Mirror
+	err := svc.db.Where("enabled = ? and next_sync_at <= ? and (status <> ? or sync_started_at < ?)",
+		true, now, MirrorStatusSyncing, now.Add(-conf.MirrorSyncTimeout())).
+		Order("next_sync_at").Limit(conf.MirrorMaxConcurrency()).Find(&mirrors).Error
+	if err != nil {
+		logrus.Errorf("failed to query due mirrors, err: %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for _, mirror := range mirrors {
+		claimed, err := svc.claimMirror(mirror)
+		if err != nil {
+			logrus.Errorf("failed to claim mirror %d, err: %v", mirror.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		wg.Add(1)
+		go func(mirror *Mirror) {
+			defer wg.Done()
+			svc.syncMirror(mirror)
+		}(mirror)
+	}
+	wg.Wait()
+}
+
+// claimMirror 将镜像标记为同步中，只有一个实例能认领成功
+func (svc *Service) claimMirror(mirror *Mirror) (bool, error) {
+	now := time.Now()
+	result := svc.db.Model(&Mirror{}).
+		Where("id = ? and (status <> ? or sync_started_at < ?)", mirror.ID, MirrorStatusSyncing, now.Add(-conf.MirrorSyncTimeout())).
+		Updates(map[string]interface{}{
+			"status":          MirrorStatusSyncing,
+			"sync_started_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	mirror.Status = MirrorStatusSyncing
+	mirror.SyncStartedAt = &now
+	return true, nil
+}
+
+// syncMirror 执行同步并记录结果，调用前需先认领镜像
+func (svc *Service) syncMirror(mirror *Mirror) {
+	plan, err := svc.runMirror(mirror)
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_sync_at": now,
+		"next_sync_at": now.Add(time.Duration(mirror.SyncInterval) * time.Minute),
+	}
+	if err != nil {
+		logrus.Errorf("failed to sync %s mirror %d of repo %d, err: %v", mirror.Direction, mirror.ID, mirror.RepoID, err)
+		message := err.Error()
+		if len(message) > maxMirrorErrorLength {
+			message = message[:maxMirrorErrorLength]
+		}
+		updates["status"] = MirrorStatusFailed
+		updates["last_error"] = message
+	} else {
+		logrus.Infof("%s mirror %d of repo %d synced, updated %d refs, deleted %d refs",
+			mirror.Direction, mirror.ID, mirror.RepoID, len(plan.Updates), len(plan.Deletes))
+		updates["status"] = MirrorStatusSuccess
+		updates["last_error"] = ""
+		updates["last_success_at"] = now
+	}
+	// 部分引用被跳过时，已同步的引用仍需记录
+	if plan != nil {
+		mirrored, err := json.Marshal(plan.MirroredRefs())
+		if err != nil {
+			logrus.Errorf("failed to marshal mirrored refs of mirror %d, err: %v", mirror.ID, err)
+		} else {
+			updates["mirrored_refs"] = string(mirrored)
+		}
+	}
+	if err := svc.db.Model(&Mirror{}).Where("id = ?", mirror.ID).Updates(updates).Error; err != nil {
+		logrus.Errorf("failed to update mirror %d status, err: %v", mirror.ID, err)
+	}
+}
+
+// runMirror 执行一次同步，返回的计划非空时表示其中的引用已同步
+func (svc *Service) runMirror(mirror *Mirror) (*gitmodule.MirrorRefPlan, error) {
+	repoModel, err := svc.GetRepoById(mirror.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := gitmodule.OpenRepository(conf.RepoRoot(), repoModel.Path)
+	if err != nil {
+		return nil, err
+	}
+	repo.ID = repoModel.ID
+	repo.OrgId = repoModel.OrgID
+	repo.ProjectId = repoModel.ProjectID
+	repo.ApplicationId = repoModel.AppID
+	repo.Bundle = svc.bundle
+	remoteUrl, err := mirror.remoteURL()
+	if err != nil {
+		return nil, err
+	}
+	owned, err := mirror.mirroredRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.MirrorSyncTimeout())
+	defer cancel()
+	filters := gitmodule.ParseMirrorRefFilters(mirror.RefFilters)
+	if mirror.Direction == gitmodule.MirrorDirectionPull {
+		return svc.pullMirror(ctx, repo, mirror, remoteUrl, filters, owned)
+	}
+	return repo.PushMirror(ctx, remoteUrl, filters, owned)
+}
+
+// pullMirror 拉取镜像与推送一样受仓库锁定和保护分支规则限制，以镜像创建人作为推送人触发推送事件
+func (svc *Service) pullMirror(ctx context.Context, repo *gitmodule.Repository, mirror *Mirror, remoteUrl string, filters []string, owned map[string]string) (*gitmodule.MirrorRefPlan, error) {
+	locked, err := svc.GetRepoLocked(repo.ProjectId, repo.ApplicationId)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		return nil, ErrMirrorRepoLocked
+	}
+	plan, err := repo.PlanPullMirror(ctx, remoteUrl, filters, owned)
+	if err != nil {
+		return nil, err
+	}
+	pusher := &User{Id: mirror.CreatorID}
+	for _, ref := range append(append([]string{}, plan.Updates...), plan.Deletes...) {
+		skip, err := svc.skipMirrorRef(repo, pusher, plan, ref)
+		if err != nil {
+			return nil, err
+		}
+		if skip {
+			plan.Skip(ref)
+		}
+	}
+	if err = repo.ApplyPullMirror(ctx, remoteUrl, plan); err != nil {
+		return nil, err
+	}
+	svc.postReceiveMirror(repo, pusher, plan)
+	if len(plan.Skipped) > 0 {
+		return plan, fmt.Errorf("%w: %s", ErrMirrorRefsSkipped, strings.Join(plan.Skipped, ", "))
+	}
+	return plan, nil
+}
+
+// skipMirrorRef 保护分支只在未被本仓库修改、不要求评审和检查、且镜像创建人可以推送保护分支时同步，不删除保护分支
+func (svc *Service) skipMirrorRef(repo *gitmodule.Repository, pusher *User, plan *gitmodule.MirrorRefPlan, ref string) (bool, error) {
+	if !strings.HasPrefix(ref, gitmodule.BRANCH_PREFIX) {
+		return false, nil
+	}
+	rule, err := repo.GetValidBranch(strings.TrimPrefix(ref, gitmodule.BRANCH_PREFIX))
+	if err != nil {
+		return false, err
+	}
+	if !rule.IsProtect {
+		return false, nil
+	}
+	if _, ok := plan.Source[ref]; !ok || plan.Diverged(ref) || requiresMergeRequest(rule) {
+		return true, nil
+	}
+	if err := svc.CheckPermission(repo, pusher, PermissionPushProtectBranch, nil); err != nil {
+		logrus.Warnf("skip protected branch %s of repo %d in pull mirror, err: %v", ref, repo.ID, err)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (svc *Service) postReceiveMirror(repo *gitmodule.Repository, pusher *User, plan *gitmodule.MirrorRefPlan) {
+	if mirrorPostReceive == nil || plan.IsEmpty() {
+		return
+	}
+	var pushEvents []*PayloadPushEvent
+	for _, ref := range plan.Updates {
+		before, ok := plan.Target[ref]
+		if !ok {
+			before = gitmodule.INIT_COMMIT_ID
+		}
+		pushEvents = append(pushEvents, &PayloadPushEvent{
+			Before: before,
+			After:  plan.Source[ref],
+			Ref:    ref,
+			IsTag:  strings.HasPrefix(ref, gitmodule.TAG_PREFIX),
+			Pusher: pusher,
+		})
+	}
+	for _, ref := range plan.Deletes {
+		pushEvents = append(pushEvents, &PayloadPushEvent{
+			Before:   plan.Target[ref],
+			After:    gitmodule.INIT_COMMIT_ID,
+			Ref:      ref,
+			IsTag:    strings.HasPrefix(ref, gitmodule.TAG_PREFIX),
+			IsDelete: true,
+			Pusher:   pusher,
+		})
+	}
+	go mirrorPostReceive(svc, repo, pusher, pushEvents)
+}
+
+// remoteURL 返回携带认证信息的外部仓库地址
+func (mirror *Mirror) remoteURL() (string, error) {
+	if mirror.Username == "" {
+		return mirror.URL, nil
+	}
+	password, err := mirror.decryptPassword()
+	if err != nil {
+		return "", err
+	}
+	return gitmodule.GetUrlWithBasicAuth(mirror.URL, mirror.Username, password)
+}
+
+func (mirror *Mirror) mirroredRefs() (map[string]string, error) {
+	refs := make(map[string]string)
+	if mirror.MirroredRefs == "" {
+		return refs, nil
+	}
+	if err := json.Unmarshal([]byte(mirror.MirroredRefs), &refs); err != nil {
+		return nil, fmt.Errorf("failed to parse mirrored refs of mirror %d, err: %v", mirror.ID, err)
+	}
+	return refs, nil
+}
+
+func (mirror *Mirror) decryptPassword() (password string, err error) {
+	if mirror.Password == "" {
+		return "", nil
+	}
+	if conf.MirrorCredentialKey() == "" {
+		return "", ErrMirrorCredentialKeyNotSet
+	}
+	// 密钥变更后解密会 panic
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("failed to decrypt mirror password, the credential key may have changed")
+		}
+	}()
+	return encrypt.AesDecrypt(mirror.Password, conf.MirrorCredentialKey()), nil
+}
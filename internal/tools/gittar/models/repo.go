@@ -46,6 +46,11 @@ type Repo struct {
 	Size        int64
 	IsExternal  bool
 	Config      string
+	// MergeStrategies 逗号分隔的允许合并方式，为空表示全部允许
+	MergeStrategies      string
+	DefaultMergeStrategy string
+	// LFSQuota LFS 容量上限，0 表示使用全局默认值，小于0表示不限制
+	LFSQuota int64
 }
 
 func (Repo) TableName() string {
@@ -168,7 +173,10 @@ func (svc *Service) DeleteRepo(repo *Repo) error {
 		return err
 	}
 	err = svc.RemoveMR(repo)
-	return err
+	if err != nil {
+		return err
+	}
+	return svc.RemoveMirrors(repo)
 }
 
 func (svc *Service) UpdateRepoSizeCache(id int64, size int64) error {
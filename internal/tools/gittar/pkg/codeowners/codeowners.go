@@ -0,0 +1,183 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codeowners 解析 CODEOWNERS 文件，确定改动文件的代码负责人
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Paths CODEOWNERS 文件的查找位置，按顺序取第一个存在的文件
+var Paths = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule CODEOWNERS 中的一条规则
+type Rule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	// Line 规则所在行号，便于提示
+	Line int `json:"line"`
+
+	re *regexp.Regexp
+}
+
+// File 解析后的 CODEOWNERS 文件
+type File struct {
+	Rules []*Rule
+}
+
+// Approver 已评审通过的用户
+type Approver struct {
+	Username string
+	Email    string
+}
+
+// Parse 解析 CODEOWNERS 内容
+// 语法与 gitignore 一致，后出现的规则优先；owner 为 @用户名 或邮箱
+// GitLab 的 [Section] 行被忽略，其下规则按普通规则处理
+func Parse(data []byte) (*File, error) {
+	file := &File{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		fields := strings.Fields(line)
+		rule := &Rule{Pattern: fields[0], Line: lineNo}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		re, err := compilePattern(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q at line %d: %v", rule.Pattern, lineNo, err)
+		}
+		rule.re = re
+		file.Rules = append(file.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Match 返回匹配文件路径的最后一条规则，没有匹配时返回 nil
+func (f *File) Match(path string) *Rule {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].re.MatchString(path) {
+			return f.Rules[i]
+		}
+	}
+	return nil
+}
+
+// MissingApprovals 返回改动文件涉及、但尚无任一 owner 评审通过的规则
+// 没有 owner 的规则表示该路径无需负责人评审
+func (f *File) MissingApprovals(paths []string, approvers []Approver) []*Rule {
+	var missing []*Rule
+	seen := make(map[*Rule]bool)
+	for _, path := range paths {
+		rule := f.Match(path)
+		if rule == nil || len(rule.Owners) == 0 || seen[rule] {
+			continue
+		}
+		seen[rule] = true
+		if !rule.approvedBy(approvers) {
+			missing = append(missing, rule)
+		}
+	}
+	return missing
+}
+
+// IsOwner 判断用户是否是规则的 owner
+// 暂不支持 @org/team 形式的团队，团队 owner 不会匹配任何用户
+func (r *Rule) IsOwner(username, email string) bool {
+	for _, owner := range r.Owners {
+		if username != "" && strings.EqualFold(owner, "@"+username) {
+			return true
+		}
+		if email != "" && strings.EqualFold(owner, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) approvedBy(approvers []Approver) bool {
+	for _, approver := range approvers {
+		if r.IsOwner(approver.Username, approver.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePattern 将 gitignore 风格的模式转换为正则
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimPrefix(pattern, "/")
+	// 以 / 开头或中间包含 / 的模式相对仓库根目录匹配
+	anchored := p != pattern || strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				// **/ 匹配任意层目录，其余位置的 ** 匹配任意字符
+				if i+2 < len(p) && p[i+2] == '/' {
+					b.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					b.WriteString(".*")
+					i++
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '\\':
+			if i+1 < len(p) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(p[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// 目录模式匹配其下所有文件，文件模式也匹配同名目录下的文件
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
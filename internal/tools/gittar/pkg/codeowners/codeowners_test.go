@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testFile = `# 默认负责人
+*             @alice
+
+*.go          @bob dev@example.com # go 代码
+/docs/        @carol
+apps/api      @dave
+**/vendor/**
+[Section]
+build/logs/** @erin
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(testFile))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(f.Rules))
+	assert.Equal(t, "*.go", f.Rules[1].Pattern)
+	assert.Equal(t, []string{"@bob", "dev@example.com"}, f.Rules[1].Owners)
+	assert.Equal(t, 4, f.Rules[1].Line)
+	assert.Empty(t, f.Rules[4].Owners)
+}
+
+func TestMatch(t *testing.T) {
+	f, err := Parse([]byte(testFile))
+	assert.NoError(t, err)
+
+	cases := map[string]string{
+		"README.md":            "*",
+		"main.go":              "*.go",
+		"pkg/util/util.go":     "*.go",
+		"docs/index.md":        "/docs/",
+		"docs/api/main.go":     "/docs/",
+		"pkg/docs/index.md":    "*",
+		"apps/api/handler.go":  "apps/api",
+		"apps/api":             "apps/api",
+		"x/apps/api/main.go":   "*.go",
+		"a/vendor/lib/x.go":    "**/vendor/**",
+		"build/logs/today.log": "build/logs/**",
+	}
+	for path, want := range cases {
+		rule := f.Match(path)
+		if assert.NotNil(t, rule, path) {
+			assert.Equal(t, want, rule.Pattern, path)
+		}
+	}
+
+	empty, err := Parse([]byte("docs/ @carol\n"))
+	assert.NoError(t, err)
+	assert.Nil(t, empty.Match("main.go"))
+}
+
+func TestMissingApprovals(t *testing.T) {
+	f, err := Parse([]byte(testFile))
+	assert.NoError(t, err)
+
+	paths := []string{"main.go", "docs/index.md", "a/vendor/lib/x.go", "pkg/x.go"}
+	missing := f.MissingApprovals(paths, nil)
+	assert.Equal(t, 2, len(missing))
+
+	missing = f.MissingApprovals(paths, []Approver{{Username: "Bob"}})
+	assert.Equal(t, 1, len(missing))
+	assert.Equal(t, "/docs/", missing[0].Pattern)
+
+	missing = f.MissingApprovals(paths, []Approver{{Username: "x", Email: "DEV@example.com"}, {Username: "carol"}})
+	assert.Empty(t, missing)
+}
@@ -67,7 +67,8 @@ func repositoryClean() {
 	// initialize a waitGroup according to the number of concurrent
 	var wait = limit_sync_group.NewSemaphore(concurrentNum)
 	for _, projectFileInfo := range projectFileInfos {
-		if !projectFileInfo.IsDir() {
+		// directories prefixed with '_' are not repositories, e.g. the lfs content store
+		if !projectFileInfo.IsDir() || strings.HasPrefix(projectFileInfo.Name(), "_") {
 			continue
 		}
 		var projectPath = repositoryRootAddr + "/" + projectFileInfo.Name()
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitmodule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeStrategy 合并请求的合入方式
+type MergeStrategy string
+
+const (
+	// MergeStrategyMerge 创建双亲合并提交
+	MergeStrategyMerge MergeStrategy = "merge"
+	// MergeStrategySquash 将源分支的所有变更压缩为目标分支上的一个提交
+	MergeStrategySquash MergeStrategy = "squash"
+	// MergeStrategyRebase 将源分支的提交逐个重放到目标分支上
+	MergeStrategyRebase MergeStrategy = "rebase"
+	// MergeStrategyFastForward 仅允许快进，目标分支必须是源分支的祖先
+	MergeStrategyFastForward MergeStrategy = "ff-only"
+)
+
+// AllMergeStrategies 未配置时仓库允许的全部合并方式
+var AllMergeStrategies = []MergeStrategy{
+	MergeStrategyMerge,
+	MergeStrategySquash,
+	MergeStrategyRebase,
+	MergeStrategyFastForward,
+}
+
+func (s MergeStrategy) Valid() bool {
+	for _, strategy := range AllMergeStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMergeStrategies 解析逗号分隔的合并方式列表，空字符串表示允许全部
+func ParseMergeStrategies(s string) ([]MergeStrategy, error) {
+	if strings.TrimSpace(s) == "" {
+		return AllMergeStrategies, nil
+	}
+	var result []MergeStrategy
+	seen := make(map[MergeStrategy]bool)
+	for _, item := range strings.Split(s, ",") {
+		strategy := MergeStrategy(strings.TrimSpace(item))
+		if strategy == "" || seen[strategy] {
+			continue
+		}
+		if !strategy.Valid() {
+			return nil, fmt.Errorf("invalid merge strategy: %s", strategy)
+		}
+		seen[strategy] = true
+		result = append(result, strategy)
+	}
+	return result, nil
+}
+
+// FormatMergeStrategies 与 ParseMergeStrategies 相反，用于持久化
+func FormatMergeStrategies(strategies []MergeStrategy) string {
+	items := make([]string, 0, len(strategies))
+	for _, strategy := range strategies {
+		items = append(items, string(strategy))
+	}
+	return strings.Join(items, ",")
+}
+
+// ResolveMergeStrategy 根据仓库允许的合并方式确定本次合并使用的方式
+// requested 为空时使用仓库默认方式，默认方式也为空时取允许列表中的第一个
+func ResolveMergeStrategy(allowed []MergeStrategy, defaultStrategy, requested MergeStrategy) (MergeStrategy, error) {
+	if len(allowed) == 0 {
+		allowed = AllMergeStrategies
+	}
+	strategy := requested
+	if strategy == "" {
+		strategy = defaultStrategy
+	}
+	if strategy == "" {
+		strategy = allowed[0]
+	}
+	if !strategy.Valid() {
+		return "", fmt.Errorf("invalid merge strategy: %s", strategy)
+	}
+	for _, item := range allowed {
+		if item == strategy {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("merge strategy %s is not allowed in this repository", strategy)
+}
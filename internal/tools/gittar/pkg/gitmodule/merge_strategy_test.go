@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitmodule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMergeStrategies(t *testing.T) {
+	strategies, err := ParseMergeStrategies("")
+	assert.NoError(t, err)
+	assert.Equal(t, AllMergeStrategies, strategies)
+
+	strategies, err = ParseMergeStrategies("squash, rebase,squash,")
+	assert.NoError(t, err)
+	assert.Equal(t, []MergeStrategy{MergeStrategySquash, MergeStrategyRebase}, strategies)
+	assert.Equal(t, "squash,rebase", FormatMergeStrategies(strategies))
+
+	_, err = ParseMergeStrategies("squash,octopus")
+	assert.Error(t, err)
+}
+
+func TestResolveMergeStrategy(t *testing.T) {
+	linear := []MergeStrategy{MergeStrategySquash, MergeStrategyFastForward}
+
+	strategy, err := ResolveMergeStrategy(nil, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, MergeStrategyMerge, strategy)
+
+	strategy, err = ResolveMergeStrategy(linear, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, MergeStrategySquash, strategy)
+
+	strategy, err = ResolveMergeStrategy(linear, MergeStrategyFastForward, "")
+	assert.NoError(t, err)
+	assert.Equal(t, MergeStrategyFastForward, strategy)
+
+	strategy, err = ResolveMergeStrategy(linear, MergeStrategyFastForward, MergeStrategySquash)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeStrategySquash, strategy)
+
+	_, err = ResolveMergeStrategy(linear, "", MergeStrategyMerge)
+	assert.Error(t, err)
+
+	_, err = ResolveMergeStrategy(linear, "", "octopus")
+	assert.Error(t, err)
+}
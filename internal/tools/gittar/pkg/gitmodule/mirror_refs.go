@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitmodule
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// MirrorDirectionPull 从外部仓库同步到 gittar
+	MirrorDirectionPull = "pull"
+	// MirrorDirectionPush 从 gittar 推送到外部仓库
+	MirrorDirectionPush = "push"
+)
+
+// MirrorRefPlan 镜像一次同步需要更新和删除的引用
+type MirrorRefPlan struct {
+	Updates []string `json:"updates"`
+	Deletes []string `json:"deletes"`
+	// Skipped 因目标端被修改或分支规则限制而本次未同步的引用
+	Skipped []string `json:"skipped"`
+	// Source 和 Target 为计划时源端与目标端的引用
+	Source map[string]string `json:"-"`
+	Target map[string]string `json:"-"`
+
+	owned   map[string]string
+	filters []string
+}
+
+// IsEmpty 没有需要更新和删除的引用
+func (p *MirrorRefPlan) IsEmpty() bool {
+	return len(p.Updates) == 0 && len(p.Deletes) == 0
+}
+
+// Diverged 目标端的引用不是镜像上次写入的提交，即在镜像之外被修改过或由目标端自己创建
+func (p *MirrorRefPlan) Diverged(ref string) bool {
+	sha, ok := p.Target[ref]
+	return ok && p.owned[ref] != sha
+}
+
+// Skip 本次不更新也不删除该引用
+func (p *MirrorRefPlan) Skip(ref string) {
+	p.Updates = removeMirrorRef(p.Updates, ref)
+	p.Deletes = removeMirrorRef(p.Deletes, ref)
+	p.Skipped = append(p.Skipped, ref)
+	sort.Strings(p.Skipped)
+}
+
+// MirroredRefs 按计划同步后由镜像写入目标端的引用，作为下次计划的 owned
+// 跳过的引用保留上次的记录，源端已不存在的引用不再归镜像管理
+func (p *MirrorRefPlan) MirroredRefs() map[string]string {
+	skipped := make(map[string]bool, len(p.Skipped))
+	for _, ref := range p.Skipped {
+		skipped[ref] = true
+	}
+	mirrored := make(map[string]string)
+	for ref, sha := range p.Source {
+		if !MatchMirrorRef(ref, p.filters) {
+			continue
+		}
+		if !skipped[ref] {
+			mirrored[ref] = sha
+		} else if owned, ok := p.owned[ref]; ok {
+			mirrored[ref] = owned
+		}
+	}
+	return mirrored
+}
+
+func removeMirrorRef(refs []string, ref string) []string {
+	result := refs[:0]
+	for _, r := range refs {
+		if r != ref {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// ParseMirrorRefFilters 解析逗号或换行分隔的引用过滤规则
+func ParseMirrorRefFilters(s string) []string {
+	var filters []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			filters = append(filters, item)
+		}
+	}
+	return filters
+}
+
+// MatchMirrorRef 判断引用是否需要镜像，只同步分支和标签
+// 过滤规则为空时匹配全部；以 refs/ 开头的规则匹配完整引用名，否则匹配分支或标签名，支持 path.Match 通配符
+func MatchMirrorRef(ref string, filters []string) bool {
+	if !strings.HasPrefix(ref, BRANCH_PREFIX) && !strings.HasPrefix(ref, TAG_PREFIX) {
+		return false
+	}
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		name := RefEndName(ref)
+		if strings.HasPrefix(filter, "refs/") {
+			name = ref
+		}
+		if matched, _ := path.Match(filter, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanMirrorRefs 对比源端与目标端的引用，owned 为镜像上次同步写入目标端的引用
+// 只删除源端已不存在、由镜像写入且之后未被修改的引用，目标端自己的引用不会被删除
+func PlanMirrorRefs(source, target, owned map[string]string, filters []string) *MirrorRefPlan {
+	plan := &MirrorRefPlan{Source: source, Target: target, owned: owned, filters: filters}
+	for ref, sha := range source {
+		if MatchMirrorRef(ref, filters) && target[ref] != sha {
+			plan.Updates = append(plan.Updates, ref)
+		}
+	}
+	for ref, sha := range owned {
+		if _, ok := source[ref]; ok || !MatchMirrorRef(ref, filters) {
+			continue
+		}
+		if current, ok := target[ref]; ok && current == sha {
+			plan.Deletes = append(plan.Deletes, ref)
+		}
+	}
+	sort.Strings(plan.Updates)
+	sort.Strings(plan.Deletes)
+	return plan
+}
+
+// ParseMirrorRefList 解析 ls-remote 或 for-each-ref 输出的 "<sha> <ref>" 列表
+// 忽略标签的 peeled 行及分支、标签以外的引用
+func ParseMirrorRefList(output []byte) map[string]string {
+	refs := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.HasSuffix(fields[1], "^{}") {
+			continue
+		}
+		if MatchMirrorRef(fields[1], nil) {
+			refs[fields[1]] = fields[0]
+		}
+	}
+	return refs
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitmodule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMirrorRefFilters(t *testing.T) {
+	assert.Equal(t, []string{"master", "release/*", "refs/tags/v*"}, ParseMirrorRefFilters(" master,release/*\nrefs/tags/v*, "))
+	assert.Empty(t, ParseMirrorRefFilters(""))
+}
+
+func TestMatchMirrorRef(t *testing.T) {
+	assert.True(t, MatchMirrorRef("refs/heads/master", nil))
+	assert.True(t, MatchMirrorRef("refs/tags/v1.0", nil))
+	assert.False(t, MatchMirrorRef("refs/merge-requests/1/head", nil))
+	assert.False(t, MatchMirrorRef("HEAD", nil))
+
+	filters := []string{"master", "release/*", "refs/tags/v*"}
+	assert.True(t, MatchMirrorRef("refs/heads/master", filters))
+	assert.True(t, MatchMirrorRef("refs/heads/release/1.0", filters))
+	assert.False(t, MatchMirrorRef("refs/heads/release/1.0/hotfix", filters))
+	assert.True(t, MatchMirrorRef("refs/tags/v1.0", filters))
+	assert.False(t, MatchMirrorRef("refs/tags/1.0", filters))
+	assert.False(t, MatchMirrorRef("refs/heads/feature/a", filters))
+}
+
+func TestPlanMirrorRefs(t *testing.T) {
+	source := map[string]string{
+		"refs/heads/master":  "a",
+		"refs/heads/develop": "b",
+		"refs/heads/feature": "c",
+		"refs/tags/v1.0":     "d",
+	}
+	target := map[string]string{
+		"refs/heads/master":  "a",
+		"refs/heads/develop": "x",
+		"refs/heads/old":     "y",
+		"refs/heads/changed": "w",
+		"refs/heads/local":   "z",
+	}
+	owned := map[string]string{
+		"refs/heads/master":  "a",
+		"refs/heads/develop": "x",
+		"refs/heads/old":     "y",
+		"refs/heads/changed": "v",
+	}
+
+	// 只删除镜像写入且未被修改的引用，目标端自己的 local 和修改过的 changed 保留
+	plan := PlanMirrorRefs(source, target, owned, nil)
+	assert.Equal(t, []string{"refs/heads/develop", "refs/heads/feature", "refs/tags/v1.0"}, plan.Updates)
+	assert.Equal(t, []string{"refs/heads/old"}, plan.Deletes)
+
+	plan = PlanMirrorRefs(source, target, owned, []string{"master", "develop", "old", "local"})
+	assert.Equal(t, []string{"refs/heads/develop"}, plan.Updates)
+	assert.Equal(t, []string{"refs/heads/old"}, plan.Deletes)
+
+	// 没有同步记录时不删除任何引用
+	plan = PlanMirrorRefs(source, target, nil, nil)
+	assert.Empty(t, plan.Deletes)
+
+	assert.True(t, PlanMirrorRefs(source, source, nil, nil).IsEmpty())
+}
+
+func TestMirrorRefPlan_Skip(t *testing.T) {
+	source := map[string]string{
+		"refs/heads/master":  "a",
+		"refs/heads/develop": "b",
+		"refs/heads/feature": "c",
+	}
+	target := map[string]string{
+		"refs/heads/master":  "m",
+		"refs/heads/develop": "x",
+		"refs/heads/old":     "y",
+	}
+	owned := map[string]string{
+		"refs/heads/master":  "o",
+		"refs/heads/develop": "x",
+		"refs/heads/old":     "y",
+	}
+
+	plan := PlanMirrorRefs(source, target, owned, nil)
+	assert.True(t, plan.Diverged("refs/heads/master"))
+	assert.False(t, plan.Diverged("refs/heads/develop"))
+	assert.False(t, plan.Diverged("refs/heads/feature"))
+
+	plan.Skip("refs/heads/master")
+	plan.Skip("refs/heads/old")
+	assert.Equal(t, []string{"refs/heads/develop", "refs/heads/feature"}, plan.Updates)
+	assert.Empty(t, plan.Deletes)
+	assert.Equal(t, []string{"refs/heads/master", "refs/heads/old"}, plan.Skipped)
+
+	// 跳过的引用保留上次的记录，下次同步仍视为已被修改
+	assert.Equal(t, map[string]string{
+		"refs/heads/master":  "o",
+		"refs/heads/develop": "b",
+		"refs/heads/feature": "c",
+	}, plan.MirroredRefs())
+}
+
+func TestParseMirrorRefList(t *testing.T) {
+	output := []byte("a1\tHEAD\nb2\trefs/heads/master\nc3\trefs/tags/v1.0\nd4\trefs/tags/v1.0^{}\ne5 refs/pull/1/head\n")
+	assert.Equal(t, map[string]string{
+		"refs/heads/master": "b2",
+		"refs/tags/v1.0":    "c3",
+	}, ParseMirrorRefList(output))
+}
@@ -332,6 +332,22 @@ func (repo *Repository) FilesCountBetween(startCommitID, endCommitID string) (in
 	return len(strings.Split(stdout, "\n")) - 1, nil
 }
 
+// FilesChangedBetween 返回 endCommitID 相对于两者 merge base 改动的文件路径
+// 关闭重命名检测，重命名文件的新旧路径都会被返回
+func (repo *Repository) FilesChangedBetween(startCommitID, endCommitID string) ([]string, error) {
+	stdout, err := NewCommand("diff", "--name-only", "--no-renames", "-z", startCommitID+"..."+endCommitID).RunInDirBytes(repo.DiskPath())
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(string(stdout), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
 // CommitsBetween returns a list that contains commits between [last, before).
 func (repo *Repository) CommitsBetweenLimit(last *Commit, before *Commit, skip int, limit int) ([]*Commit, error) {
 	if before == nil {
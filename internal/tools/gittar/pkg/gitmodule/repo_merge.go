@@ -19,11 +19,18 @@ package gitmodule
 
 import (
 	"errors"
+	"fmt"
 
 	git "github.com/libgit2/git2go/v33"
 )
 
+var (
+	ErrMergeConflict  = errors.New("has conflict")
+	ErrNotFastForward = errors.New("target branch has diverged, cannot fast-forward")
+)
+
 type MergeStatusInfo struct {
+	Strategy    string `json:"strategy"`
 	HasConflict bool   `json:"hasConflict"`
 	IsMerged    bool   `json:"isMerged"`
 	HasError    bool   `json:"hasError"`
@@ -125,17 +132,26 @@ func (repo *Repository) getMergeInfo(ourBranch string, theirBranch string) (*Mer
 	}, nil
 
 }
+
+// GetMergeStatus 按默认的合并提交方式检查合并状态
 func (repo *Repository) GetMergeStatus(ourBranch string, theirBranch string) (*MergeStatusInfo, error) {
+	return repo.GetMergeStatusWithStrategy(ourBranch, theirBranch, MergeStrategyMerge)
+}
+
+// GetMergeStatusWithStrategy 按指定的合并方式检查 ourBranch 能否合入 theirBranch
+func (repo *Repository) GetMergeStatusWithStrategy(ourBranch string, theirBranch string, strategy MergeStrategy) (*MergeStatusInfo, error) {
 
 	info, err := repo.getMergeInfo(ourBranch, theirBranch)
 	if err != nil {
 		return &MergeStatusInfo{
+			Strategy: string(strategy),
 			HasError: true,
 			ErrorMsg: err.Error(),
 		}, nil
 	}
 
 	result := &MergeStatusInfo{
+		Strategy:    string(strategy),
 		HasConflict: false,
 	}
 	//没有commits差异 认已经合并
@@ -153,17 +169,38 @@ func (repo *Repository) GetMergeStatus(ourBranch string, theirBranch string) (*M
 		return nil, err
 	}
 
-	options, _ := git.DefaultMergeOptions()
-	index, err := rawRepo.MergeTrees(info.BaseTree, info.OurTree, info.TheirTree, &options)
-	if err != nil {
-		return nil, err
+	switch strategy {
+	case MergeStrategyFastForward:
+		// 目标分支有源分支之外的提交时无法快进
+		result.HasConflict = info.BaseCommit.ID != info.TheirCommit.ID
+	case MergeStrategyRebase:
+		// 逐个重放源分支提交，任一提交冲突即视为冲突
+		_, err = repo.replayCommits(rawRepo, info, nil)
+		if err == ErrMergeConflict {
+			result.HasConflict = true
+		} else if err != nil {
+			return nil, err
+		}
+	default:
+		_, err = repo.mergeTree(rawRepo, info)
+		if err == ErrMergeConflict {
+			result.HasConflict = true
+		} else if err != nil {
+			return nil, err
+		}
 	}
-	result.HasConflict = index.HasConflicts()
 
 	return result, nil
 }
 
+// Merge 以合并提交方式将 ourBranch 合入 theirBranch
 func (repo *Repository) Merge(ourBranch string, theirBranch string, signature *Signature, message string) (*Commit, error) {
+	return repo.MergeWithStrategy(MergeStrategyMerge, ourBranch, theirBranch, signature, message)
+}
+
+// MergeWithStrategy 按指定的合并方式将 ourBranch 合入 theirBranch，返回 theirBranch 合入后的最新提交
+// rebase 和 ff-only 方式会保留源分支提交的原始信息，忽略 message
+func (repo *Repository) MergeWithStrategy(strategy MergeStrategy, ourBranch string, theirBranch string, signature *Signature, message string) (*Commit, error) {
 
 	info, err := repo.getMergeInfo(ourBranch, theirBranch)
 	if err != nil {
@@ -175,56 +212,209 @@ func (repo *Repository) Merge(ourBranch string, theirBranch string, signature *S
 		return nil, err
 	}
 
+	sig := &git.Signature{
+		Name:  signature.Name,
+		Email: signature.Email,
+		When:  signature.When,
+	}
+
+	parentOid, err := git.NewOid(info.TheirCommit.ID)
+	if err != nil {
+		return nil, err
+	}
+	parentCommit, err := rawRepo.LookupCommit(parentOid)
+	if err != nil {
+		return nil, err
+	}
+
+	var newOid *git.Oid
+	switch strategy {
+	case MergeStrategyFastForward:
+		if info.BaseCommit.ID != info.TheirCommit.ID {
+			return nil, ErrNotFastForward
+		}
+		newOid, err = git.NewOid(info.OurCommit.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = repo.moveBranch(rawRepo, theirBranch, parentOid, newOid, "fast-forward merge "+ourBranch)
+	case MergeStrategyRebase:
+		newOid, err = repo.replayCommits(rawRepo, info, sig)
+		if err != nil {
+			return nil, err
+		}
+		err = repo.moveBranch(rawRepo, theirBranch, parentOid, newOid, "rebase merge "+ourBranch)
+	case MergeStrategySquash:
+		var newTree *git.Tree
+		if newTree, err = repo.mergeTree(rawRepo, info); err != nil {
+			return nil, err
+		}
+		// 只保留目标分支作为父提交，源分支的提交历史不进入目标分支
+		newOid, err = rawRepo.CreateCommit(BRANCH_PREFIX+theirBranch, sig, sig, message, newTree, parentCommit)
+	case MergeStrategyMerge:
+		var newTree *git.Tree
+		if newTree, err = repo.mergeTree(rawRepo, info); err != nil {
+			return nil, err
+		}
+		var parentOid2 *git.Oid
+		if parentOid2, err = git.NewOid(info.OurCommit.ID); err != nil {
+			return nil, err
+		}
+		var parentCommit2 *git.Commit
+		if parentCommit2, err = rawRepo.LookupCommit(parentOid2); err != nil {
+			return nil, err
+		}
+		newOid, err = rawRepo.CreateCommit(BRANCH_PREFIX+theirBranch, sig, sig, message, newTree, parentCommit, parentCommit2)
+	default:
+		return nil, fmt.Errorf("invalid merge strategy: %s", strategy)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetCommit(newOid.String())
+}
+
+// mergeTree 三方合并源分支与目标分支，返回合并后的树
+func (repo *Repository) mergeTree(rawRepo *git.Repository, info *MergeInfo) (*git.Tree, error) {
 	options, err := git.DefaultMergeOptions()
 	if err != nil {
 		return nil, err
 	}
 	index, err := rawRepo.MergeTrees(info.BaseTree, info.OurTree, info.TheirTree, &options)
-
 	if err != nil {
 		return nil, err
 	}
+	defer index.Free()
 
 	if index.HasConflicts() {
-		return nil, errors.New("has conflict")
+		return nil, ErrMergeConflict
 	}
 	newTreeOid, err := index.WriteTreeTo(rawRepo)
 	if err != nil {
 		return nil, err
 	}
+	return rawRepo.LookupTree(newTreeOid)
+}
 
-	newTree, err := rawRepo.LookupTree(newTreeOid)
+// replayCommits 将源分支独有的提交按拓扑顺序逐个 cherry-pick 到目标分支之上，返回新的末端提交
+// 合并提交会被跳过，与 git rebase 的默认行为一致；重放后没有变更的提交也会被跳过
+// committer 为空时只检查冲突，不创建提交，返回值为 nil
+func (repo *Repository) replayCommits(rawRepo *git.Repository, info *MergeInfo, committer *git.Signature) (*git.Oid, error) {
+	ourOid, err := git.NewOid(info.OurCommit.ID)
 	if err != nil {
 		return nil, err
 	}
-
-	sig := &git.Signature{
-		Name:  signature.Name,
-		Email: signature.Email,
-		When:  signature.When,
+	theirOid, err := git.NewOid(info.TheirCommit.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	parentOid, err := git.NewOid(info.TheirCommit.ID)
+	walker, err := rawRepo.Walk()
 	if err != nil {
 		return nil, err
 	}
-	parentCommit, err := rawRepo.LookupCommit(parentOid)
-	if err != nil {
+	defer walker.Free()
+	walker.Sorting(git.SortTopological | git.SortReverse)
+	if err = walker.Push(ourOid); err != nil {
 		return nil, err
 	}
-	parentOid2, err := git.NewOid(info.OurCommit.ID)
+	if err = walker.Hide(theirOid); err != nil {
+		return nil, err
+	}
+	var picks []*git.Oid
+	err = walker.Iterate(func(commit *git.Commit) bool {
+		if commit.ParentCount() <= 1 {
+			picks = append(picks, commit.Id())
+		}
+		return true
+	})
 	if err != nil {
 		return nil, err
 	}
-	parentCommit2, err := rawRepo.LookupCommit(parentOid2)
 
+	options, err := git.DefaultMergeOptions()
 	if err != nil {
 		return nil, err
 	}
-	newOid, err := rawRepo.CreateCommit(BRANCH_PREFIX+theirBranch, sig, sig, message, newTree, parentCommit, parentCommit2)
-
+	tip, err := rawRepo.LookupCommit(theirOid)
 	if err != nil {
 		return nil, err
 	}
-	return repo.GetCommit(newOid.String())
+	tipTree, err := tip.Tree()
+	if err != nil {
+		return nil, err
+	}
+	for _, oid := range picks {
+		pick, err := rawRepo.LookupCommit(oid)
+		if err != nil {
+			return nil, err
+		}
+		pickTree, err := pick.Tree()
+		if err != nil {
+			return nil, err
+		}
+		var baseTree *git.Tree
+		if pick.ParentCount() > 0 {
+			baseTree, err = pick.Parent(0).Tree()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		index, err := rawRepo.MergeTrees(baseTree, tipTree, pickTree, &options)
+		if err != nil {
+			return nil, err
+		}
+		if index.HasConflicts() {
+			index.Free()
+			return nil, ErrMergeConflict
+		}
+		newTreeOid, err := index.WriteTreeTo(rawRepo)
+		index.Free()
+		if err != nil {
+			return nil, err
+		}
+		if newTreeOid.Equal(tipTree.Id()) {
+			continue
+		}
+		tipTree, err = rawRepo.LookupTree(newTreeOid)
+		if err != nil {
+			return nil, err
+		}
+		if committer == nil {
+			continue
+		}
+
+		newOid, err := rawRepo.CreateCommit("", pick.Author(), committer, pick.Message(), tipTree, tip)
+		if err != nil {
+			return nil, err
+		}
+		tip, err = rawRepo.LookupCommit(newOid)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if committer == nil {
+		return nil, nil
+	}
+	return tip.Id(), nil
+}
+
+// moveBranch 将分支从 oldOid 移动到 newOid，分支在合并期间被更新时返回错误
+func (repo *Repository) moveBranch(rawRepo *git.Repository, branch string, oldOid, newOid *git.Oid, msg string) error {
+	ref, err := rawRepo.References.Lookup(BRANCH_PREFIX + branch)
+	if err != nil {
+		return err
+	}
+	defer ref.Free()
+	if !ref.Target().Equal(oldOid) {
+		return errors.New("branch [" + branch + "] has been updated, please retry")
+	}
+	newRef, err := ref.SetTarget(newOid, msg)
+	if err != nil {
+		return err
+	}
+	newRef.Free()
+	return nil
 }
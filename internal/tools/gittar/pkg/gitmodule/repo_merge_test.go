@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitmodule
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/libgit2/git2go/v33"
+	"github.com/stretchr/testify/assert"
+)
+
+type mergeTestRepo struct {
+	t    *testing.T
+	repo *Repository
+	raw  *git.Repository
+}
+
+// newMergeTestRepo creates a bare repo with master branch of file base.txt
+func newMergeTestRepo(t *testing.T) *mergeTestRepo {
+	root, err := ioutil.TempDir("", "merge")
+	checkFatal(t, err)
+	raw, err := git.InitRepository(filepath.Join(root, "test.git"), true)
+	checkFatal(t, err)
+	repo, err := OpenRepository(root, "test.git")
+	checkFatal(t, err)
+	r := &mergeTestRepo{t: t, repo: repo, raw: raw}
+	r.commit("master", newTestSignature("alice"), "init\n", map[string]string{"base.txt": "base\n"})
+	return r
+}
+
+func (r *mergeTestRepo) cleanup() {
+	r.raw.Free()
+	checkFatal(r.t, os.RemoveAll(r.repo.RootPath))
+}
+
+func newTestSignature(name string) *git.Signature {
+	return &git.Signature{
+		Name:  name,
+		Email: name + "@erda.cloud",
+		When:  time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC),
+	}
+}
+
+// commit adds files on the tree of first parent and moves branch to the new commit,
+// parents default to the tip of branch
+func (r *mergeTestRepo) commit(branch string, author *git.Signature, msg string, files map[string]string, parents ...*git.Oid) *git.Oid {
+	if len(parents) == 0 {
+		if tip := r.tip(branch); tip != nil {
+			parents = append(parents, tip)
+		}
+	}
+	var (
+		parentCommits []*git.Commit
+		builder       *git.TreeBuilder
+		err           error
+	)
+	for _, oid := range parents {
+		parent, err := r.raw.LookupCommit(oid)
+		checkFatal(r.t, err)
+		parentCommits = append(parentCommits, parent)
+	}
+	if len(parentCommits) > 0 {
+		var tree *git.Tree
+		tree, err = parentCommits[0].Tree()
+		checkFatal(r.t, err)
+		builder, err = r.raw.TreeBuilderFromTree(tree)
+	} else {
+		builder, err = r.raw.TreeBuilder()
+	}
+	checkFatal(r.t, err)
+	defer builder.Free()
+	for name, content := range files {
+		blob, err := r.raw.CreateBlobFromBuffer([]byte(content))
+		checkFatal(r.t, err)
+		checkFatal(r.t, builder.Insert(name, blob, git.FilemodeBlob))
+	}
+	treeOid, err := builder.Write()
+	checkFatal(r.t, err)
+	tree, err := r.raw.LookupTree(treeOid)
+	checkFatal(r.t, err)
+	oid, err := r.raw.CreateCommit("", author, author, msg, tree, parentCommits...)
+	checkFatal(r.t, err)
+	r.branch(branch, oid)
+	return oid
+}
+
+func (r *mergeTestRepo) branch(name string, oid *git.Oid) {
+	ref, err := r.raw.References.Create(BRANCH_PREFIX+name, oid, true, "")
+	checkFatal(r.t, err)
+	ref.Free()
+}
+
+func (r *mergeTestRepo) tip(branch string) *git.Oid {
+	ref, err := r.raw.References.Lookup(BRANCH_PREFIX + branch)
+	if err != nil {
+		return nil
+	}
+	defer ref.Free()
+	return ref.Target()
+}
+
+func (r *mergeTestRepo) files(commit *Commit) []string {
+	oid, err := git.NewOid(commit.TreeSha)
+	checkFatal(r.t, err)
+	tree, err := r.raw.LookupTree(oid)
+	checkFatal(r.t, err)
+	var names []string
+	for i := uint64(0); i < tree.EntryCount(); i++ {
+		names = append(names, tree.EntryByIndex(i).Name)
+	}
+	return names
+}
+
+var mergeTestCommitter = &Signature{Name: "erda", Email: "erda@erda.cloud", When: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}
+
+func TestMergeWithStrategy_Squash(t *testing.T) {
+	r := newMergeTestRepo(t)
+	defer r.cleanup()
+
+	r.branch("feature", r.tip("master"))
+	r.commit("feature", newTestSignature("alice"), "feature 1\n", map[string]string{"a.txt": "a\n"})
+	r.commit("feature", newTestSignature("bob"), "feature 2\n", map[string]string{"b.txt": "b\n"})
+	masterTip := r.commit("master", newTestSignature("alice"), "master\n", map[string]string{"m.txt": "m\n"})
+
+	commit, err := r.repo.MergeWithStrategy(MergeStrategySquash, "feature", "master", mergeTestCommitter, "squash feature\n")
+	assert.NoError(t, err)
+	// only target branch is the parent, history of source branch is dropped
+	assert.Equal(t, []string{masterTip.String()}, commit.Parents)
+	assert.Equal(t, "squash feature\n", commit.CommitMessage)
+	assert.Equal(t, "erda", commit.Author.Name)
+	assert.Equal(t, []string{"a.txt", "b.txt", "base.txt", "m.txt"}, r.files(commit))
+	assert.Equal(t, commit.ID, r.tip("master").String())
+}
+
+func TestMergeWithStrategy_Rebase(t *testing.T) {
+	r := newMergeTestRepo(t)
+	defer r.cleanup()
+
+	r.branch("feature", r.tip("master"))
+	masterTip := r.commit("master", newTestSignature("carol"), "master\n", map[string]string{"m.txt": "m\n"})
+	f1 := r.commit("feature", newTestSignature("alice"), "feature 1\n", map[string]string{"a.txt": "a\n"})
+	// master is merged into feature, the merge commit is skipped when replaying
+	r.commit("feature", newTestSignature("alice"), "Merge branch 'master' into 'feature'\n", map[string]string{"m.txt": "m\n"}, f1, masterTip)
+	r.commit("feature", newTestSignature("bob"), "feature 2\n", map[string]string{"b.txt": "b\n"})
+
+	commit, err := r.repo.MergeWithStrategy(MergeStrategyRebase, "feature", "master", mergeTestCommitter, "ignored\n")
+	assert.NoError(t, err)
+	assert.Equal(t, commit.ID, r.tip("master").String())
+	assert.Equal(t, []string{"a.txt", "b.txt", "base.txt", "m.txt"}, r.files(commit))
+
+	// linear history on master in the original order, authors are kept and committer is the merger
+	var (
+		messages []string
+		authors  []string
+	)
+	for c := commit; c.ID != masterTip.String(); {
+		if !assert.Len(t, c.Parents, 1) {
+			return
+		}
+		messages = append([]string{c.CommitMessage}, messages...)
+		authors = append([]string{c.Author.Name}, authors...)
+		assert.Equal(t, newTestSignature(c.Author.Name).When.Unix(), c.Author.When.Unix())
+		assert.Equal(t, "erda", c.Committer.Name)
+		c, err = r.repo.GetCommit(c.Parents[0])
+		checkFatal(t, err)
+	}
+	assert.Equal(t, []string{"feature 1\n", "feature 2\n"}, messages)
+	assert.Equal(t, []string{"alice", "bob"}, authors)
+}
+
+func TestMergeWithStrategy_FastForward(t *testing.T) {
+	r := newMergeTestRepo(t)
+	defer r.cleanup()
+
+	r.branch("feature", r.tip("master"))
+	featureTip := r.commit("feature", newTestSignature("alice"), "feature\n", map[string]string{"a.txt": "a\n"})
+
+	// master has diverged
+	r.branch("diverged", r.tip("master"))
+	divergedTip := r.commit("diverged", newTestSignature("bob"), "diverged\n", map[string]string{"d.txt": "d\n"})
+	_, err := r.repo.MergeWithStrategy(MergeStrategyFastForward, "feature", "diverged", mergeTestCommitter, "")
+	assert.Equal(t, ErrNotFastForward, err)
+	assert.Equal(t, divergedTip.String(), r.tip("diverged").String())
+
+	commit, err := r.repo.MergeWithStrategy(MergeStrategyFastForward, "feature", "master", mergeTestCommitter, "")
+	assert.NoError(t, err)
+	assert.Equal(t, featureTip.String(), commit.ID)
+	assert.Equal(t, featureTip.String(), r.tip("master").String())
+}
+
+func TestMoveBranch(t *testing.T) {
+	r := newMergeTestRepo(t)
+	defer r.cleanup()
+
+	oldTip := r.tip("master")
+	r.branch("feature", oldTip)
+	featureTip := r.commit("feature", newTestSignature("alice"), "feature\n", map[string]string{"a.txt": "a\n"})
+	// master is moved by others during merge
+	r.branch("other", oldTip)
+	movedTip := r.commit("other", newTestSignature("bob"), "other\n", map[string]string{"o.txt": "o\n"})
+	r.branch("master", movedTip)
+
+	err := r.repo.moveBranch(r.raw, "master", oldTip, featureTip, "fast-forward merge feature")
+	assert.Error(t, err)
+	assert.Equal(t, movedTip.String(), r.tip("master").String())
+
+	assert.NoError(t, r.repo.moveBranch(r.raw, "master", movedTip, featureTip, "fast-forward merge feature"))
+	assert.Equal(t, featureTip.String(), r.tip("master").String())
+}
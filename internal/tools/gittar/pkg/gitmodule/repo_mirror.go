@@ -0,0 +1,212 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !codeanalysis
+// +build !codeanalysis
+
+package gitmodule
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// mirrorRefBatchSize 单条 fetch/push 命令携带的 refspec 上限，避免命令行过长
+	mirrorRefBatchSize = 100
+	// mirrorFetchRefPrefix 拉取镜像时先将外部引用取到该前缀下，再通过事务更新到分支和标签
+	mirrorFetchRefPrefix = "refs/mirror-fetch/"
+)
+
+// ListRemoteRefs 列出外部仓库的分支和标签，remoteUrl 可以携带认证信息
+func ListRemoteRefs(ctx context.Context, remoteUrl string) (map[string]string, error) {
+	output, err := runMirrorCommand(ctx, "", remoteUrl, "ls-remote", "--heads", "--tags", remoteUrl)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMirrorRefList(output), nil
+}
+
+// ListMirrorRefs 列出仓库的分支和标签
+func (repo *Repository) ListMirrorRefs() (map[string]string, error) {
+	stdout, err := NewCommand("for-each-ref", "--format=%(objectname) %(refname)", "refs/heads", "refs/tags").RunInDirBytes(repo.DiskPath())
+	if err != nil {
+		return nil, err
+	}
+	return ParseMirrorRefList(stdout), nil
+}
+
+// PlanPullMirror 对比外部仓库与本仓库的引用，owned 为镜像上次同步写入本仓库的引用
+func (repo *Repository) PlanPullMirror(ctx context.Context, remoteUrl string, filters []string, owned map[string]string) (*MirrorRefPlan, error) {
+	remoteRefs, err := ListRemoteRefs(ctx, remoteUrl)
+	if err != nil {
+		return nil, err
+	}
+	localRefs, err := repo.ListMirrorRefs()
+	if err != nil {
+		return nil, err
+	}
+	return PlanMirrorRefs(remoteRefs, localRefs, owned, filters), nil
+}
+
+// ApplyPullMirror 拉取计划中的引用，在一个事务中更新和删除本仓库的分支和标签
+// 计划之后本仓库的引用被修改时整个事务失败，不会覆盖新推送的提交；plan.Source 更新为实际拉取到的提交
+func (repo *Repository) ApplyPullMirror(ctx context.Context, remoteUrl string, plan *MirrorRefPlan) error {
+	if plan.IsEmpty() {
+		return nil
+	}
+	defer repo.cleanMirrorFetchRefs()
+
+	var refspecs []string
+	for _, ref := range plan.Updates {
+		refspecs = append(refspecs, "+"+ref+":"+mirrorFetchRef(ref))
+	}
+	for _, batch := range batchRefspecs(refspecs) {
+		args := append([]string{"fetch", "--no-tags", remoteUrl}, batch...)
+		if _, err := runMirrorCommand(ctx, repo.DiskPath(), remoteUrl, args...); err != nil {
+			return err
+		}
+	}
+	fetched, err := repo.listMirrorFetchRefs()
+	if err != nil {
+		return err
+	}
+
+	stdin := new(bytes.Buffer)
+	for _, ref := range plan.Updates {
+		sha, ok := fetched[ref]
+		if !ok {
+			return fmt.Errorf("failed to fetch %s from mirror", ref)
+		}
+		plan.Source[ref] = sha
+		before, ok := plan.Target[ref]
+		if !ok {
+			before = INIT_COMMIT_ID
+		}
+		fmt.Fprintf(stdin, "update %s %s %s\n", ref, sha, before)
+	}
+	for _, ref := range plan.Deletes {
+		fmt.Fprintf(stdin, "delete %s %s\n", ref, plan.Target[ref])
+	}
+	return runUpdateRefs(ctx, repo.DiskPath(), stdin)
+}
+
+// PushMirror 将本仓库中匹配的分支和标签强制推送到外部仓库，owned 为镜像上次推送到外部仓库的引用
+// 只删除镜像推送过、外部仓库未修改且本仓库已删除的引用
+func (repo *Repository) PushMirror(ctx context.Context, remoteUrl string, filters []string, owned map[string]string) (*MirrorRefPlan, error) {
+	remoteRefs, err := ListRemoteRefs(ctx, remoteUrl)
+	if err != nil {
+		return nil, err
+	}
+	localRefs, err := repo.ListMirrorRefs()
+	if err != nil {
+		return nil, err
+	}
+	plan := PlanMirrorRefs(localRefs, remoteRefs, owned, filters)
+
+	var refspecs []string
+	for _, ref := range plan.Updates {
+		refspecs = append(refspecs, "+"+ref+":"+ref)
+	}
+	for _, ref := range plan.Deletes {
+		refspecs = append(refspecs, ":"+ref)
+	}
+	for _, batch := range batchRefspecs(refspecs) {
+		args := append([]string{"push", "--porcelain", remoteUrl}, batch...)
+		if _, err := runMirrorCommand(ctx, repo.DiskPath(), remoteUrl, args...); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+func mirrorFetchRef(ref string) string {
+	return mirrorFetchRefPrefix + strings.TrimPrefix(ref, "refs/")
+}
+
+// listMirrorFetchRefs 列出拉取到临时前缀下的引用，返回对应的分支或标签
+func (repo *Repository) listMirrorFetchRefs() (map[string]string, error) {
+	stdout, err := NewCommand("for-each-ref", "--format=%(objectname) %(refname)", mirrorFetchRefPrefix).RunInDirBytes(repo.DiskPath())
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			refs["refs/"+strings.TrimPrefix(fields[1], mirrorFetchRefPrefix)] = fields[0]
+		}
+	}
+	return refs, nil
+}
+
+func (repo *Repository) cleanMirrorFetchRefs() {
+	fetched, err := repo.listMirrorFetchRefs()
+	if err != nil || len(fetched) == 0 {
+		return
+	}
+	stdin := new(bytes.Buffer)
+	for ref := range fetched {
+		fmt.Fprintf(stdin, "delete %s\n", mirrorFetchRef(ref))
+	}
+	runUpdateRefs(context.Background(), repo.DiskPath(), stdin)
+}
+
+// runUpdateRefs 通过 update-ref --stdin 在一个事务中更新引用，任一引用的旧值不匹配时全部不更新
+func runUpdateRefs(ctx context.Context, dir string, stdin io.Reader) error {
+	cmd := exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("err:%s output:%s", err, stderr.String())
+	}
+	return nil
+}
+
+func batchRefspecs(refspecs []string) [][]string {
+	var batches [][]string
+	for len(refspecs) > mirrorRefBatchSize {
+		batches = append(batches, refspecs[:mirrorRefBatchSize])
+		refspecs = refspecs[mirrorRefBatchSize:]
+	}
+	if len(refspecs) > 0 {
+		batches = append(batches, refspecs)
+	}
+	return batches
+}
+
+// runMirrorCommand 执行与外部仓库交互的 git 命令，输出中的认证信息会被移除
+func runMirrorCommand(ctx context.Context, dir string, remoteUrl string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// 认证失败时直接报错，不等待终端输入
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("err:%s output:%s", err, RemoveAuthInfo(stderr.String(), remoteUrl))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/erda-project/erda/pkg/cloudstorage"
+)
+
+const (
+	StorageLocal = "local"
+	StorageCloud = "cloud"
+)
+
+var (
+	ErrObjectNotExist = errors.New("lfs object not exist")
+	ErrHashMismatch   = errors.New("lfs object content hash mismatch")
+	ErrSizeMismatch   = errors.New("lfs object content size mismatch")
+)
+
+// ContentStore LFS 对象的内容存储，对象按 oid 全局去重，仓库与对象的关联由数据库维护
+type ContentStore interface {
+	// Get 读取对象内容，对象不存在时返回 ErrObjectNotExist
+	Get(p Pointer) (io.ReadCloser, error)
+	// Put 写入对象内容，内容的 sha256 或大小与 pointer 不一致时不会写入
+	Put(p Pointer, r io.Reader) error
+}
+
+// verifyCopy 拷贝内容的同时校验 sha256 与大小
+func verifyCopy(dst io.Writer, p Pointer, r io.Reader) error {
+	hash := sha256.New()
+	// 多读一个字节用于判断内容是否超过声明的大小
+	written, err := io.Copy(io.MultiWriter(dst, hash), io.LimitReader(r, p.Size+1))
+	if err != nil {
+		return err
+	}
+	if written != p.Size {
+		return ErrSizeMismatch
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != p.Oid {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+type localContentStore struct {
+	root string
+}
+
+// NewLocalContentStore 使用本地文件系统存储 LFS 对象
+func NewLocalContentStore(root string) (ContentStore, error) {
+	if err := os.MkdirAll(filepath.Join(root, "tmp"), 0755); err != nil {
+		return nil, err
+	}
+	return &localContentStore{root: root}, nil
+}
+
+func (s *localContentStore) Get(p Pointer) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.root, p.RelativePath()))
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotExist
+	}
+	return f, err
+}
+
+func (s *localContentStore) Put(p Pointer, r io.Reader) error {
+	// 先写入临时文件，校验通过后再移动到最终位置，避免读到写了一半的对象
+	tmp, err := ioutil.TempFile(filepath.Join(s.root, "tmp"), p.Oid+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = verifyCopy(tmp, p, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(s.root, p.RelativePath())
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+type cloudContentStore struct {
+	client cloudstorage.Client
+	bucket string
+	tmpDir string
+}
+
+// NewCloudContentStore 使用 oss 或 minio 存储 LFS 对象
+// cloudstorage.Client 只支持按文件上传，tmpDir 用于暂存校验中的对象
+func NewCloudContentStore(client cloudstorage.Client, bucket, tmpDir string) (ContentStore, error) {
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return nil, err
+	}
+	return &cloudContentStore{client: client, bucket: bucket, tmpDir: tmpDir}, nil
+}
+
+func (s *cloudContentStore) objectName(p Pointer) string {
+	return "lfs/objects/" + p.RelativePath()
+}
+
+func (s *cloudContentStore) Get(p Pointer) (io.ReadCloser, error) {
+	rc, err := s.client.GetObject(s.bucket, s.objectName(p))
+	if cloudstorage.IsObjectNotExist(err) {
+		return nil, ErrObjectNotExist
+	}
+	return rc, err
+}
+
+func (s *cloudContentStore) Put(p Pointer, r io.Reader) error {
+	tmp, err := ioutil.TempFile(s.tmpDir, p.Oid+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = verifyCopy(tmp, p, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.client.UploadFile(s.bucket, s.objectName(p), tmp.Name())
+	return err
+}
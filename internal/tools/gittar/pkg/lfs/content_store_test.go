@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPointer(content string) Pointer {
+	sum := sha256.Sum256([]byte(content))
+	return Pointer{Oid: hex.EncodeToString(sum[:]), Size: int64(len(content))}
+}
+
+func TestLocalContentStore(t *testing.T) {
+	store, err := NewLocalContentStore(t.TempDir())
+	assert.NoError(t, err)
+
+	content := "large binary content"
+	p := newTestPointer(content)
+
+	_, err = store.Get(p)
+	assert.Equal(t, ErrObjectNotExist, err)
+
+	assert.NoError(t, store.Put(p, strings.NewReader(content)))
+	rc, err := store.Get(p)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.NoError(t, rc.Close())
+	assert.Equal(t, content, string(data))
+}
+
+func TestLocalContentStoreVerify(t *testing.T) {
+	store, err := NewLocalContentStore(t.TempDir())
+	assert.NoError(t, err)
+
+	p := newTestPointer("large binary content")
+	assert.Equal(t, ErrSizeMismatch, store.Put(p, strings.NewReader("short")))
+	assert.Equal(t, ErrSizeMismatch, store.Put(p, strings.NewReader("large binary content, longer")))
+	assert.Equal(t, ErrHashMismatch, store.Put(p, strings.NewReader("LARGE BINARY CONTENT")))
+
+	// 校验失败的对象不会落盘
+	_, err = store.Get(p)
+	assert.Equal(t, ErrObjectNotExist, err)
+}
+
+// fakeCloudClient 内存中的 cloudstorage.Client，DownloadFile 会整体读入内存，不允许使用
+type fakeCloudClient struct {
+	objects map[string][]byte
+}
+
+func (c *fakeCloudClient) UploadFile(bucketName, objectName, file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	c.objects[bucketName+"/"+objectName] = data
+	return objectName, nil
+}
+
+func (c *fakeCloudClient) DownloadFile(bucketName, objectName string) ([]byte, error) {
+	return nil, errors.New("lfs object should not be buffered in memory")
+}
+
+func (c *fakeCloudClient) GetObject(bucketName, objectName string) (io.ReadCloser, error) {
+	data, ok := c.objects[bucketName+"/"+objectName]
+	if !ok {
+		return nil, minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}
+	}
+	return ioutil.NopCloser(strings.NewReader(string(data))), nil
+}
+
+func (c *fakeCloudClient) GetFileUrl(bucketName, objectName string) (string, error) {
+	return objectName, nil
+}
+
+func (c *fakeCloudClient) HealthCheck() error { return nil }
+
+func TestCloudContentStore(t *testing.T) {
+	client := &fakeCloudClient{objects: map[string][]byte{}}
+	store, err := NewCloudContentStore(client, "gittar", t.TempDir())
+	assert.NoError(t, err)
+
+	content := "large binary content"
+	p := newTestPointer(content)
+
+	_, err = store.Get(p)
+	assert.Equal(t, ErrObjectNotExist, err)
+
+	assert.Equal(t, ErrHashMismatch, store.Put(p, strings.NewReader("LARGE BINARY CONTENT")))
+	_, err = store.Get(p)
+	assert.Equal(t, ErrObjectNotExist, err)
+	assert.NoError(t, store.Put(p, strings.NewReader(content)))
+	rc, err := store.Get(p)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.NoError(t, rc.Close())
+	assert.Equal(t, content, string(data))
+}
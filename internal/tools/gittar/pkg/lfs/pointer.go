@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MediaType LFS API 要求的请求与响应类型
+	MediaType = "application/vnd.git-lfs+json"
+	// SpecVersion pointer 文件首行的版本标识
+	SpecVersion = "https://git-lfs.github.com/spec/v1"
+	// MaxPointerSize pointer 文件的最大长度，超过该长度的 blob 不可能是 pointer
+	MaxPointerSize = 1024
+)
+
+var oidPattern = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// Pointer 仓库中代替大文件存储的 LFS 指针
+type Pointer struct {
+	Oid  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// Valid oid 必须是 sha256 的十六进制表示
+func (p Pointer) Valid() bool {
+	return oidPattern.MatchString(p.Oid) && p.Size >= 0
+}
+
+// RelativePath 对象在存储中的相对路径，按 oid 前两级目录打散
+func (p Pointer) RelativePath() string {
+	return path.Join(p.Oid[0:2], p.Oid[2:4], p.Oid)
+}
+
+// String 生成 pointer 文件内容
+func (p Pointer) String() string {
+	return fmt.Sprintf("version %s\noid sha256:%s\nsize %d\n", SpecVersion, p.Oid, p.Size)
+}
+
+// ReadPointer 尝试将 blob 内容解析为 LFS pointer，不是 pointer 时返回 false
+func ReadPointer(data []byte) (Pointer, bool) {
+	var p Pointer
+	if len(data) > MaxPointerSize || !bytes.HasPrefix(data, []byte("version "+SpecVersion)) {
+		return p, false
+	}
+	var hasOid, hasSize bool
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			continue
+		}
+		switch key {
+		case "oid":
+			if !strings.HasPrefix(value, "sha256:") {
+				return p, false
+			}
+			p.Oid = strings.TrimPrefix(value, "sha256:")
+			hasOid = true
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return p, false
+			}
+			p.Size = size
+			hasSize = true
+		}
+	}
+	if !hasOid || !hasSize || !p.Valid() {
+		return Pointer{}, false
+	}
+	return p, true
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testOid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+func TestReadPointer(t *testing.T) {
+	p, ok := ReadPointer([]byte("version https://git-lfs.github.com/spec/v1\noid sha256:" + testOid + "\nsize 12345\n"))
+	assert.True(t, ok)
+	assert.Equal(t, Pointer{Oid: testOid, Size: 12345}, p)
+
+	// 往返
+	p2, ok := ReadPointer([]byte(p.String()))
+	assert.True(t, ok)
+	assert.Equal(t, p, p2)
+
+	// 扩展字段不影响解析
+	_, ok = ReadPointer([]byte("version https://git-lfs.github.com/spec/v1\next-0-foo sha256:" + testOid + "\noid sha256:" + testOid + "\nsize 1\n"))
+	assert.True(t, ok)
+}
+
+func TestReadPointerInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"hello world",
+		"version https://git-lfs.github.com/spec/v1\nsize 12\n",
+		"version https://git-lfs.github.com/spec/v1\noid sha256:" + testOid + "\n",
+		"version https://git-lfs.github.com/spec/v1\noid md5:" + testOid + "\nsize 12\n",
+		"version https://git-lfs.github.com/spec/v1\noid sha256:xyz\nsize 12\n",
+		"version https://git-lfs.github.com/spec/v1\noid sha256:" + testOid + "\nsize abc\n",
+		"version https://git-lfs.github.com/spec/v1\noid sha256:" + testOid + "\nsize 12\n" + strings.Repeat("x", MaxPointerSize),
+	}
+	for _, c := range cases {
+		_, ok := ReadPointer([]byte(c))
+		assert.False(t, ok, c)
+	}
+}
+
+func TestPointerRelativePath(t *testing.T) {
+	p := Pointer{Oid: testOid, Size: 1}
+	assert.Equal(t, "4d/7a/"+testOid, p.RelativePath())
+}
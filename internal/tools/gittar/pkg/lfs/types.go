@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import "time"
+
+const (
+	OperationUpload   = "upload"
+	OperationDownload = "download"
+
+	// TransferBasic 仅支持基础传输方式
+	TransferBasic = "basic"
+)
+
+// BatchRequest POST info/lfs/objects/batch
+type BatchRequest struct {
+	Operation string    `json:"operation"`
+	Transfers []string  `json:"transfers,omitempty"`
+	Ref       *Ref      `json:"ref,omitempty"`
+	Objects   []Pointer `json:"objects"`
+}
+
+type Ref struct {
+	Name string `json:"name"`
+}
+
+// BatchResponse 批量接口的响应
+type BatchResponse struct {
+	Transfer string            `json:"transfer,omitempty"`
+	Objects  []*ObjectResponse `json:"objects"`
+}
+
+// ObjectResponse 单个对象的处理结果，Actions 为空表示客户端无需操作
+type ObjectResponse struct {
+	Pointer
+	Authenticated bool             `json:"authenticated,omitempty"`
+	Actions       map[string]*Link `json:"actions,omitempty"`
+	Error         *ObjectError     `json:"error,omitempty"`
+}
+
+type Link struct {
+	Href      string            `json:"href"`
+	Header    map[string]string `json:"header,omitempty"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+}
+
+type ObjectError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse LFS 接口的错误响应
+type ErrorResponse struct {
+	Message          string `json:"message"`
+	DocumentationURL string `json:"documentation_url,omitempty"`
+}
+
+// Lock 文件锁
+type Lock struct {
+	ID       string     `json:"id"`
+	Path     string     `json:"path"`
+	LockedAt time.Time  `json:"locked_at"`
+	Owner    *LockOwner `json:"owner,omitempty"`
+}
+
+type LockOwner struct {
+	Name string `json:"name"`
+}
+
+// LockCreateRequest POST info/lfs/locks
+type LockCreateRequest struct {
+	Path string `json:"path"`
+	Ref  *Ref   `json:"ref,omitempty"`
+}
+
+type LockResponse struct {
+	Lock    *Lock  `json:"lock"`
+	Message string `json:"message,omitempty"`
+}
+
+// LockListResponse GET info/lfs/locks
+type LockListResponse struct {
+	Locks      []*Lock `json:"locks"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// LockVerifyRequest POST info/lfs/locks/verify
+type LockVerifyRequest struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Ref    *Ref   `json:"ref,omitempty"`
+}
+
+// LockVerifyResponse 区分当前用户持有的锁与他人持有的锁
+type LockVerifyResponse struct {
+	Ours       []*Lock `json:"ours"`
+	Theirs     []*Lock `json:"theirs"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// UnlockRequest POST info/lfs/locks/:id/unlock
+type UnlockRequest struct {
+	Force bool `json:"force,omitempty"`
+	Ref   *Ref `json:"ref,omitempty"`
+}
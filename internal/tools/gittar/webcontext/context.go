@@ -36,6 +36,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/gittar/models"
 	"github.com/erda-project/erda/internal/tools/gittar/pkg/errorx"
 	"github.com/erda-project/erda/internal/tools/gittar/pkg/gitmodule"
+	"github.com/erda-project/erda/internal/tools/gittar/pkg/lfs"
 	"github.com/erda-project/erda/pkg/common/apis"
 	"github.com/erda-project/erda/pkg/discover"
 	"github.com/erda-project/erda/pkg/strutil"
@@ -52,6 +53,7 @@ type Context struct {
 	next         bool
 	EtcdClient   *clientv3.Client
 	TokenService tokenpb.TokenServiceServer
+	LFSStore     lfs.ContentStore
 	orgClient    org.ClientInterface
 }
 
@@ -63,6 +65,7 @@ var ucAuthInstance *uc.UCUserAuth
 var etcdClientInstance *clientv3.Client
 var tokenServiceInstance *tokenpb.TokenServiceServer
 var orgClient org.ClientInterface
+var lfsStoreInstance lfs.ContentStore
 
 func WithDB(db *models.DBClient) {
 	dbClientInstance = db
@@ -88,6 +91,10 @@ func WithOrgClient(org org.ClientInterface) {
 	orgClient = org
 }
 
+func WithLFSContentStore(store lfs.ContentStore) {
+	lfsStoreInstance = store
+}
+
 func WrapHandler(handlerFunc ContextHandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := NewEchoContext(c, dbClientInstance)
@@ -162,6 +169,7 @@ func NewEchoContext(c echo.Context, db *models.DBClient) *Context {
 		Bundle:       diceBundleInstance,
 		EtcdClient:   etcdClientInstance,
 		TokenService: *tokenServiceInstance,
+		LFSStore:     lfsStoreInstance,
 		orgClient:    orgClient,
 	}
 }
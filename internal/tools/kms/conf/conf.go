@@ -16,6 +16,8 @@
 package conf
 
 import (
+	"time"
+
 	"github.com/erda-project/erda/pkg/envconf"
 	"github.com/erda-project/erda/pkg/kms/kmstypes"
 )
@@ -26,6 +28,9 @@ type Conf struct {
 	Debug         bool               `env:"DEBUG" default:"false"`
 	KmsStoreKind  kmstypes.StoreKind `env:"KMS_STORE_KIND" default:"ETCD"`
 	EtcdEndpoints string             `env:"ETCD_ENDPOINTS" required:"false"`
+
+	// KeyLifecycleInterval is the interval of background worker which rotates and deletes keys
+	KeyLifecycleInterval time.Duration `env:"KEY_LIFECYCLE_INTERVAL" default:"1h"`
 }
 
 var cfg Conf
@@ -58,3 +63,7 @@ func KmsStoreKind() kmstypes.StoreKind {
 func EtcdEndpoints() string {
 	return cfg.EtcdEndpoints
 }
+
+func KeyLifecycleInterval() time.Duration {
+	return cfg.KeyLifecycleInterval
+}
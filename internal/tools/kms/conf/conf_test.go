@@ -17,6 +17,7 @@ package conf
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -55,4 +56,5 @@ func normalLoad(t *testing.T) {
 	assert.Equal(t, EtcdEndpoints(), "fake")
 	assert.Equal(t, ListenAddr(), ":3082")
 	assert.False(t, Debug())
+	assert.Equal(t, time.Hour, KeyLifecycleInterval())
 }
@@ -58,5 +58,19 @@ func (e *Endpoints) Routes() []httpserver.Endpoint {
 		{Path: "/api/kms/generate-data-key", Method: http.MethodPost, Handler: e.KmsGenerateDataKey},
 		{Path: "/api/kms/rotate-key-version", Method: http.MethodPost, Handler: e.KmsRotateKeyVersion},
 		{Path: "/api/kms/describe-key", Method: http.MethodGet, Handler: e.KmsDescribeKey},
+		{Path: "/api/kms/re-encrypt", Method: http.MethodPost, Handler: e.KmsReEncrypt},
+
+		// kms key lifecycle
+		{Path: "/api/kms/enable-key", Method: http.MethodPost, Handler: e.KmsEnableKey},
+		{Path: "/api/kms/disable-key", Method: http.MethodPost, Handler: e.KmsDisableKey},
+		{Path: "/api/kms/schedule-key-deletion", Method: http.MethodPost, Handler: e.KmsScheduleKeyDeletion},
+		{Path: "/api/kms/cancel-key-deletion", Method: http.MethodPost, Handler: e.KmsCancelKeyDeletion},
+		{Path: "/api/kms/update-rotation-policy", Method: http.MethodPost, Handler: e.KmsUpdateRotationPolicy},
+
+		// kms asymmetric
+		{Path: "/api/kms/get-public-key", Method: http.MethodGet, Handler: e.KmsGetPublicKey},
+		{Path: "/api/kms/asymmetric-decrypt", Method: http.MethodPost, Handler: e.KmsAsymmetricDecrypt},
+		{Path: "/api/kms/sign", Method: http.MethodPost, Handler: e.KmsSign},
+		{Path: "/api/kms/verify", Method: http.MethodPost, Handler: e.KmsVerify},
 	}
 }
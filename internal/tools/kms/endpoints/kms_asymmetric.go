@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoints
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/erda-project/erda/internal/tools/kms/endpoints/apierrors"
+	"github.com/erda-project/erda/pkg/http/httpserver"
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+func (e *Endpoints) KmsGetPublicKey(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.GetPublicKeyRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrGetPublicKey.InvalidParameter(err).ToResp(), nil
+	}
+	publicKey, err := plugin.GetPublicKey(ctx, &req)
+	if err != nil {
+		return apierrors.ErrGetPublicKey.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(publicKey)
+}
+
+func (e *Endpoints) KmsAsymmetricDecrypt(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.AsymmetricDecryptRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrAsymmetricDecrypt.InvalidParameter(err).ToResp(), nil
+	}
+	decryptResp, err := plugin.AsymmetricDecrypt(ctx, &req)
+	if err != nil {
+		return apierrors.ErrAsymmetricDecrypt.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(decryptResp)
+}
+
+func (e *Endpoints) KmsSign(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.SignRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrSign.InvalidParameter(err).ToResp(), nil
+	}
+	signResp, err := plugin.Sign(ctx, &req)
+	if err != nil {
+		return apierrors.ErrSign.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(signResp)
+}
+
+func (e *Endpoints) KmsVerify(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.VerifyRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrVerify.InvalidParameter(err).ToResp(), nil
+	}
+	verifyResp, err := plugin.Verify(ctx, &req)
+	if err != nil {
+		return apierrors.ErrVerify.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(verifyResp)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoints
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/erda-project/erda/internal/tools/kms/endpoints/apierrors"
+	"github.com/erda-project/erda/pkg/http/httpserver"
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+func (e *Endpoints) KmsEnableKey(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.EnableKeyRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrEnableKey.InvalidParameter(err).ToResp(), nil
+	}
+	enableResp, err := plugin.EnableKey(ctx, &req)
+	if err != nil {
+		return apierrors.ErrEnableKey.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(enableResp)
+}
+
+func (e *Endpoints) KmsDisableKey(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.DisableKeyRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrDisableKey.InvalidParameter(err).ToResp(), nil
+	}
+	disableResp, err := plugin.DisableKey(ctx, &req)
+	if err != nil {
+		return apierrors.ErrDisableKey.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(disableResp)
+}
+
+func (e *Endpoints) KmsScheduleKeyDeletion(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.ScheduleKeyDeletionRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrScheduleKeyDeletion.InvalidParameter(err).ToResp(), nil
+	}
+	scheduleResp, err := plugin.ScheduleKeyDeletion(ctx, &req)
+	if err != nil {
+		return apierrors.ErrScheduleKeyDeletion.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(scheduleResp)
+}
+
+func (e *Endpoints) KmsCancelKeyDeletion(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.CancelKeyDeletionRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrCancelKeyDeletion.InvalidParameter(err).ToResp(), nil
+	}
+	cancelResp, err := plugin.CancelKeyDeletion(ctx, &req)
+	if err != nil {
+		return apierrors.ErrCancelKeyDeletion.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(cancelResp)
+}
+
+func (e *Endpoints) KmsUpdateRotationPolicy(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req kmstypes.UpdateRotationPolicyRequest
+	if err := e.parseRequestBody(r, &req); err != nil {
+		return err.ToResp(), nil
+	}
+
+	plugin, err := e.getPluginByKeyID(req.KeyID)
+	if err != nil {
+		return apierrors.ErrUpdateRotationPolicy.InvalidParameter(err).ToResp(), nil
+	}
+	updateResp, err := plugin.UpdateRotationPolicy(ctx, &req)
+	if err != nil {
+		return apierrors.ErrUpdateRotationPolicy.InternalError(err).ToResp(), nil
+	}
+
+	return httpserver.OkResp(updateResp)
+}
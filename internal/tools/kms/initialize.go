@@ -16,6 +16,8 @@
 package kms
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/erda-project/erda/internal/tools/kms/conf"
@@ -41,6 +43,9 @@ func Initialize() error {
 		return err
 	}
 
+	// rotate and delete keys in background
+	go kmsMgr.RunKeyLifecycleWorker(context.Background(), conf.KmsStoreKind(), conf.KeyLifecycleInterval())
+
 	ep := endpoints.New(endpoints.WithKmsManager(kmsMgr))
 
 	server := httpserver.New(conf.ListenAddr())
@@ -35,6 +35,20 @@ func (m *MockServiceGroup) EXPECT() *MockServiceGroupMockRecorder {
 	return m.recorder
 }
 
+// Canary mocks base method.
+func (m *MockServiceGroup) Canary(arg0 apistructs.ServiceGroupCanaryRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Canary", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Canary indicates an expected call of Canary.
+func (mr *MockServiceGroupMockRecorder) Canary(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Canary", reflect.TypeOf((*MockServiceGroup)(nil).Canary), arg0)
+}
+
 // Cancel mocks base method.
 func (m *MockServiceGroup) Cancel(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -166,6 +180,21 @@ func (mr *MockServiceGroupMockRecorder) Precheck(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Precheck", reflect.TypeOf((*MockServiceGroup)(nil).Precheck), arg0)
 }
 
+// RenderManifests mocks base method.
+func (m *MockServiceGroup) RenderManifests(arg0 apistructs.ServiceGroupRenderManifestsRequest) (apistructs.ServiceGroupRenderManifestsData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderManifests", arg0)
+	ret0, _ := ret[0].(apistructs.ServiceGroupRenderManifestsData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderManifests indicates an expected call of RenderManifests.
+func (mr *MockServiceGroupMockRecorder) RenderManifests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderManifests", reflect.TypeOf((*MockServiceGroup)(nil).RenderManifests), arg0)
+}
+
 // Restart mocks base method.
 func (m *MockServiceGroup) Restart(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
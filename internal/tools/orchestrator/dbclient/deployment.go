@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 
 	"github.com/erda-project/erda/apistructs"
@@ -78,6 +79,8 @@ type DeploymentExtra struct {
 	CancelEndAt         *time.Time `json:"cancelEndAt,omitempty"`
 	ForceCanceled       bool       `json:"forceCanceled,omitempty"`
 	AutoTimeout         bool       `json:"autoTimeout,omitempty"`
+	// canary rollout progress, nil if the deployment is not rolled out progressively
+	Canary *apistructs.DeploymentCanaryProgress `json:"canary,omitempty"`
 }
 
 func (ex DeploymentExtra) Value() (driver.Value, error) {
@@ -135,6 +138,19 @@ func (db *DBClient) UpdateDeployment(deployment *Deployment) error {
 	return nil
 }
 
+// AbortDeploymentCanary set abortedBy of the canary progress in extra of the deployment in canary phase,
+// other fields are not written so that the progress saved by deploy fsm concurrently is not overwritten,
+// returns false if the deployment is not in canary phase
+func (db *DBClient) AbortDeploymentCanary(id uint64, operator string) (bool, error) {
+	result := db.Model(&Deployment{}).
+		Where("id = ? AND status = ? AND step = ?", id, apistructs.DeploymentStatusDeploying, apistructs.DeploymentPhaseCanary).
+		Update("extra", gorm.Expr("JSON_SET(extra, '$.canary.abortedBy', ?)", operator))
+	if result.Error != nil {
+		return false, errors.Wrapf(result.Error, "failed to abort canary of deployment, id: %v", id)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (db *DBClient) GetDeployment(id uint64) (*Deployment, error) {
 	var deployment Deployment
 	if err := db.
@@ -432,5 +448,6 @@ func (d *Deployment) Convert() *apistructs.Deployment {
 		ApprovedAt:     d.ApprovedAt,
 		ApprovalStatus: d.ApprovalStatus,
 		ApprovalReason: d.ApprovalReason,
+		Canary:         d.Extra.Canary,
 	}
 }
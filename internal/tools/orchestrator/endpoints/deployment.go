@@ -55,6 +55,23 @@ func (e *Endpoints) CancelDeployment(ctx context.Context, r *http.Request, vars
 	return httpserver.OkResp(nil)
 }
 
+// AbortCanary 中止金丝雀发布并回滚
+func (e *Endpoints) AbortCanary(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	userID, err := user.GetUserID(r)
+	if err != nil {
+		return apierrors.ErrAbortCanary.NotLogin().ToResp(), nil
+	}
+	v := vars["deploymentID"]
+	deploymentID, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return apierrors.ErrAbortCanary.InvalidParameter(strutil.Concat("deploymentID: ", v)).ToResp(), nil
+	}
+	if err := e.deployment.AbortCanary(deploymentID, userID.String()); err != nil {
+		return errorresp.ErrResp(err)
+	}
+	return httpserver.OkResp(nil)
+}
+
 // ListLaunchedApprovedDeployments 列出'user-id'用户发起审批的 deployments
 func (e *Endpoints) ListLaunchedApprovalDeployments(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
 	userID, err := user.GetUserID(r)
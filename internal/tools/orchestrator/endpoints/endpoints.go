@@ -253,6 +253,7 @@ func (e *Endpoints) Routes() []httpserver.Endpoint {
 
 		// kill pod (only k8s)
 		{Path: "/api/runtimes/actions/killpod", Method: http.MethodPost, Handler: e.KillPod},
+		{Path: "/api/runtimes/actions/render-manifests", Method: http.MethodPost, Handler: e.RenderManifests},
 
 		// deployment endpoints
 		{Path: "/api/deployments", Method: http.MethodGet, Handler: e.ListDeployments},
@@ -264,6 +265,7 @@ func (e *Endpoints) Routes() []httpserver.Endpoint {
 		// TODO: do not returns runtime info, use /api/runtimes/{runtimeId} instead
 		{Path: "/api/deployments/{deploymentID}/status", Method: http.MethodGet, Handler: e.GetDeploymentStatus},
 		{Path: "/api/deployments/{deploymentID}/actions/cancel", Method: http.MethodPost, Handler: e.CancelDeployment},
+		{Path: "/api/deployments/{deploymentID}/actions/abort-canary", Method: http.MethodPost, Handler: e.AbortCanary},
 
 		{Path: "/api/deployments/{deploymentID}/actions/deploy-addons", Method: http.MethodPost, Handler: e.DeployStagesAddons},
 		{Path: "/api/deployments/{deploymentID}/actions/deploy-services", Method: http.MethodPost, Handler: e.DeployStagesServices},
@@ -737,3 +737,16 @@ func (e *Endpoints) KillPod(ctx context.Context, r *http.Request, vars map[strin
 	}
 	return httpserver.OkResp(nil)
 }
+
+// RenderManifests renders dice.yml as the kubernetes manifests or helm chart without deploying
+func (e *Endpoints) RenderManifests(ctx context.Context, r *http.Request, vars map[string]string) (httpserver.Responser, error) {
+	var req apistructs.ServiceGroupRenderManifestsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierrors.ErrRenderManifests.InvalidParameter("req body").ToResp(), nil
+	}
+	data, err := e.scheduler.Httpendpoints.ServiceGroupImpl.RenderManifests(req)
+	if err != nil {
+		return apierrors.ErrRenderManifests.InternalError(err).ToResp(), nil
+	}
+	return httpserver.OkResp(data)
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mohae/deepcopy"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/k8serror"
+)
+
+const (
+	// LabelCanary marks the objects created for canary rollout
+	LabelCanary = "erda.cloud/canary"
+
+	canarySuffix = "-canary"
+
+	// envDiceService and envDiceServiceName are reported by agents as the target_service_name tag of metrics
+	envDiceService     = "DICE_SERVICE"
+	envDiceServiceName = "DICE_SERVICE_NAME"
+
+	annotationCanary       = "nginx.ingress.kubernetes.io/canary"
+	annotationCanaryWeight = "nginx.ingress.kubernetes.io/canary-weight"
+)
+
+func canaryName(name string) string {
+	return name + canarySuffix
+}
+
+// canaryReplicas replicas of canary workload in proportion to the traffic weight, at least 1
+func canaryReplicas(scale, weight int) int {
+	replicas := int(math.Ceil(float64(scale*weight) / 100))
+	if replicas < 1 {
+		return 1
+	}
+	return replicas
+}
+
+// isCanaryable only long-running services with ports receive traffic from ingress
+func isCanaryable(service *apistructs.Service) bool {
+	return len(service.Ports) > 0 && service.WorkLoad != ServicePerNode && service.WorkLoad != ServiceJob
+}
+
+// newCanaryService Build the canary service from the service of new version,
+// its workload and k8s service are named with canary suffix so that stable ones are untouched,
+// and its pods report metrics as apistructs.CanaryServiceName to be analysed apart from the stable ones
+func newCanaryService(service *apistructs.Service, weight int) *apistructs.Service {
+	canary := deepcopy.Copy(service).(*apistructs.Service)
+	canary.Name = canaryName(getDeployName(service))
+	canary.ProjectServiceName = ""
+	canary.Scale = canaryReplicas(service.Scale, weight)
+	canary.MinAvailable = nil
+	if canary.Env == nil {
+		canary.Env = make(map[string]string)
+	}
+	canary.Env[envDiceService] = apistructs.CanaryServiceName(service.Name)
+	canary.Env[envDiceServiceName] = apistructs.CanaryServiceName(service.Name)
+	return canary
+}
+
+// newCanaryIngress Build the canary ingress of ing, whose backends are replaced with canary services,
+// return nil if ing routes to none of the services in backends (key: stable service, value: canary service)
+func newCanaryIngress(ing *networkingv1.Ingress, backends map[string]string, weight int) *networkingv1.Ingress {
+	var rules []networkingv1.IngressRule
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		var paths []networkingv1.HTTPIngressPath
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service == nil {
+				continue
+			}
+			canary, ok := backends[path.Backend.Service.Name]
+			if !ok {
+				continue
+			}
+			p := *path.DeepCopy()
+			p.Backend.Service.Name = canary
+			paths = append(paths, p)
+		}
+		if len(paths) > 0 {
+			rules = append(rules, networkingv1.IngressRule{
+				Host:             rule.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths}},
+			})
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	annotations := make(map[string]string, len(ing.Annotations)+2)
+	for k, v := range ing.Annotations {
+		annotations[k] = v
+	}
+	annotations[annotationCanary] = "true"
+	annotations[annotationCanaryWeight] = strconv.Itoa(weight)
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        canaryName(ing.Name),
+			Namespace:   ing.Namespace,
+			Labels:      map[string]string{LabelCanary: "true"},
+			Annotations: annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ing.Spec.IngressClassName,
+			TLS:              ing.Spec.TLS,
+			Rules:            rules,
+		},
+	}
+}
+
+// canary Create, update or remove the canary workloads of service group, see apistructs.ServiceGroupCanaryAction
+func (k *Kubernetes) canary(sg *apistructs.ServiceGroup) (interface{}, error) {
+	ns := MakeNamespace(sg)
+	if sg.ProjectNamespace != "" {
+		ns = sg.ProjectNamespace
+		k.setProjectServiceName(sg)
+	}
+
+	var services []*apistructs.Service
+	for i := range sg.Services {
+		sg.Services[i].Namespace = ns
+		if isCanaryable(&sg.Services[i]) {
+			services = append(services, &sg.Services[i])
+		}
+	}
+
+	switch action := apistructs.ServiceGroupCanaryAction(sg.Labels[apistructs.ServiceGroupCanaryLabelKey]); action {
+	case apistructs.ServiceGroupCanaryActionApply:
+		weight, err := strconv.Atoi(sg.Labels[apistructs.ServiceGroupCanaryWeightLabelKey])
+		if err != nil || weight <= 0 || weight > 100 {
+			return sg, errors.Errorf("invalid canary weight %q", sg.Labels[apistructs.ServiceGroupCanaryWeightLabelKey])
+		}
+		backends := make(map[string]string)
+		for _, service := range services {
+			canary, err := k.applyCanaryWorkload(service, sg, weight)
+			if err != nil {
+				return sg, err
+			}
+			backends[service.Name] = canary.Name
+			if service.ProjectServiceName != "" {
+				backends[service.ProjectServiceName] = canary.Name
+			}
+		}
+		return sg, k.applyCanaryIngresses(ns, backends, weight)
+	case apistructs.ServiceGroupCanaryActionRemove:
+		canaries := make(map[string]bool)
+		for _, service := range services {
+			name := canaryName(getDeployName(service))
+			canaries[name] = true
+			if err := k.deploy.Delete(ns, name); err != nil && !k8serror.NotFound(err) {
+				return sg, errors.Errorf("failed to delete canary deployment, name: %s, (%v)", name, err)
+			}
+			if err := k.DeleteService(ns, name); err != nil {
+				return sg, errors.Errorf("failed to delete canary service, name: %s, (%v)", name, err)
+			}
+		}
+		return sg, k.removeCanaryIngresses(ns, canaries)
+	default:
+		return sg, errors.Errorf("unknown canary action %q", action)
+	}
+}
+
+func (k *Kubernetes) applyCanaryWorkload(service *apistructs.Service, sg *apistructs.ServiceGroup, weight int) (*apistructs.Service, error) {
+	canary := newCanaryService(service, weight)
+	deployment, err := k.newDeployment(canary, sg)
+	if err != nil {
+		return nil, errors.Errorf("failed to generate canary deployment struct, name: %s, (%v)", canary.Name, err)
+	}
+	deployment.Labels[LabelCanary] = "true"
+
+	old, err := k.deploy.Get(deployment.Namespace, deployment.Name)
+	switch {
+	case err == nil:
+		deployment.ResourceVersion = old.ResourceVersion
+		err = k.deploy.Put(deployment)
+	case k8serror.NotFound(err):
+		err = k.deploy.Create(deployment)
+	}
+	if err != nil {
+		return nil, errors.Errorf("failed to apply canary deployment, name: %s, (%v)", canary.Name, err)
+	}
+	if err := k.CreateOrPutService(canary, nil); err != nil {
+		return nil, errors.Errorf("failed to apply canary service, name: %s, (%v)", canary.Name, err)
+	}
+	return canary, nil
+}
+
+// applyCanaryIngresses Shift weight of traffic to canary services by canary ingresses,
+// which are cloned from the ingresses of stable services (created by hepa)
+func (k *Kubernetes) applyCanaryIngresses(namespace string, backends map[string]string, weight int) error {
+	client := k.k8sClient.ClientSet.NetworkingV1().Ingresses(namespace)
+	ingresses, err := client.List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Errorf("failed to list ingresses, namespace: %s, (%v)", namespace, err)
+	}
+	for i := range ingresses.Items {
+		ing := &ingresses.Items[i]
+		if ing.Labels[LabelCanary] == "true" {
+			continue
+		}
+		canary := newCanaryIngress(ing, backends, weight)
+		if canary == nil {
+			continue
+		}
+		old, err := client.Get(context.Background(), canary.Name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			canary.ResourceVersion = old.ResourceVersion
+			_, err = client.Update(context.Background(), canary, metav1.UpdateOptions{})
+		case k8serrors.IsNotFound(err):
+			_, err = client.Create(context.Background(), canary, metav1.CreateOptions{})
+		}
+		if err != nil {
+			return errors.Errorf("failed to apply canary ingress, name: %s, (%v)", canary.Name, err)
+		}
+		logrus.Infof("canary ingress %s/%s shifted to weight %d", namespace, canary.Name, weight)
+	}
+	return nil
+}
+
+// removeCanaryIngresses Remove the canary ingresses which route to the canary services
+func (k *Kubernetes) removeCanaryIngresses(namespace string, canaries map[string]bool) error {
+	client := k.k8sClient.ClientSet.NetworkingV1().Ingresses(namespace)
+	ingresses, err := client.List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", LabelCanary),
+	})
+	if err != nil {
+		return errors.Errorf("failed to list canary ingresses, namespace: %s, (%v)", namespace, err)
+	}
+	for _, ing := range ingresses.Items {
+		routed := false
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil && canaries[path.Backend.Service.Name] {
+					routed = true
+				}
+			}
+		}
+		if !routed {
+			continue
+		}
+		if err := client.Delete(context.Background(), ing.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Errorf("failed to delete canary ingress, name: %s, (%v)", ing.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCanaryReplicas(t *testing.T) {
+	assert.Equal(t, 1, canaryReplicas(2, 10))
+	assert.Equal(t, 1, canaryReplicas(0, 50))
+	assert.Equal(t, 3, canaryReplicas(10, 25))
+	assert.Equal(t, 4, canaryReplicas(4, 100))
+}
+
+func TestNewCanaryService(t *testing.T) {
+	service := newManifestServiceGroup().Services[0]
+	service.ProjectServiceName = "web-1-web"
+
+	canary := newCanaryService(&service, 50)
+	assert.Equal(t, "web-1-web-canary", canary.Name)
+	assert.Equal(t, "", canary.ProjectServiceName)
+	assert.Equal(t, 1, canary.Scale)
+	assert.Nil(t, canary.MinAvailable)
+	assert.Equal(t, "web-canary", canary.Env["DICE_SERVICE"])
+	assert.Equal(t, "web-canary", canary.Env["DICE_SERVICE_NAME"])
+	assert.Equal(t, "{{ hello }}", canary.Env["GREETING"])
+	assert.Equal(t, "web-1-web", service.ProjectServiceName, "origin service should be untouched")
+	assert.NotNil(t, service.MinAvailable)
+	assert.NotContains(t, service.Env, "DICE_SERVICE")
+}
+
+func TestIsCanaryable(t *testing.T) {
+	service := newManifestServiceGroup().Services[0]
+	assert.True(t, isCanaryable(&service))
+
+	service.WorkLoad = ServicePerNode
+	assert.False(t, isCanaryable(&service))
+
+	service.WorkLoad = ""
+	service.Ports = nil
+	assert.False(t, isCanaryable(&service))
+}
+
+func TestNewCanaryIngress(t *testing.T) {
+	className := "nginx"
+	pathType := networkingv1.PathTypePrefix
+	backend := func(name string) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+			Name: name, Port: networkingv1.ServiceBackendPort{Number: 80},
+		}}
+	}
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-ing",
+			Namespace:   "demo",
+			Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "10m"},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &className,
+			TLS:              []networkingv1.IngressTLS{{Hosts: []string{"web.example.com"}}},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "web.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/", PathType: &pathType, Backend: backend("web")},
+							{Path: "/api", PathType: &pathType, Backend: backend("api")},
+						},
+					}},
+				},
+				{
+					Host: "admin.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{Path: "/", PathType: &pathType, Backend: backend("admin")}},
+					}},
+				},
+			},
+		},
+	}
+
+	canary := newCanaryIngress(ing, map[string]string{"web": "web-canary"}, 20)
+	assert.NotNil(t, canary)
+	assert.Equal(t, "web-ing-canary", canary.Name)
+	assert.Equal(t, "demo", canary.Namespace)
+	assert.Equal(t, "true", canary.Labels[LabelCanary])
+	assert.Equal(t, "true", canary.Annotations[annotationCanary])
+	assert.Equal(t, "20", canary.Annotations[annotationCanaryWeight])
+	assert.Equal(t, "10m", canary.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+	assert.Equal(t, &className, canary.Spec.IngressClassName)
+	assert.Len(t, canary.Spec.TLS, 1)
+	assert.Len(t, canary.Spec.Rules, 1)
+	assert.Equal(t, "web.example.com", canary.Spec.Rules[0].Host)
+	assert.Len(t, canary.Spec.Rules[0].HTTP.Paths, 1)
+	assert.Equal(t, "web-canary", canary.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+
+	// the stable ingress is left as it is
+	assert.Equal(t, "web", ing.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+	assert.Len(t, ing.Annotations, 1)
+
+	assert.Nil(t, newCanaryIngress(ing, map[string]string{"other": "other-canary"}, 20))
+}
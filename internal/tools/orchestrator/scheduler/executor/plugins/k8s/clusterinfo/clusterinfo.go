@@ -137,6 +137,20 @@ func New(clusterName string, options ...Option) (*ClusterInfo, error) {
 	return cm, nil
 }
 
+// NewStatic news an ClusterInfo with fixed data, which never loads from cluster,
+// used when rendering manifests offline
+func NewStatic(clusterName string, data map[string]string) *ClusterInfo {
+	ci := &ClusterInfo{clusterName: clusterName, data: make(map[string]string)}
+	for k, v := range clusterInfoDefaultMap {
+		ci.data[k] = v
+	}
+	for k, v := range data {
+		ci.data[k] = v
+	}
+	ci.data[DiceClusterName] = clusterName
+	return ci
+}
+
 // WithCompleteParams provides an Option
 func WithCompleteParams(addr string, client *httpclient.HTTPClient) Option {
 	return func(ci *ClusterInfo) {
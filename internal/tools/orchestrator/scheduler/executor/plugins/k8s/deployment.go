@@ -79,6 +79,9 @@ func (k *Kubernetes) createDeployment(ctx context.Context, service *apistructs.S
 	if err != nil {
 		return errors.Errorf("failed to create deployment, name: %s, (%v)", service.Name, err)
 	}
+	if err = k.syncPodDisruptionBudget(deployment.Namespace, deployment.Name, service, deployment.Spec.Selector); err != nil {
+		return errors.Errorf("failed to create pod disruption budget, name: %s, (%v)", service.Name, err)
+	}
 	if service.K8SSnippet == nil || service.K8SSnippet.Container == nil {
 		return nil
 	}
@@ -167,6 +170,9 @@ func (k *Kubernetes) putDeployment(ctx context.Context, deployment *appsv1.Deplo
 	if err != nil {
 		return errors.Errorf("failed to update deployment, name: %s, (%v)", service.Name, err)
 	}
+	if err = k.syncPodDisruptionBudget(deployment.Namespace, deployment.Name, service, deployment.Spec.Selector); err != nil {
+		return errors.Errorf("failed to update pod disruption budget, name: %s, (%v)", service.Name, err)
+	}
 	if service.K8SSnippet == nil || service.K8SSnippet.Container == nil {
 		return nil
 	}
@@ -200,6 +206,9 @@ func (k *Kubernetes) getDeploymentDeltaResource(ctx context.Context, deploy *app
 
 func (k *Kubernetes) deleteDeployment(namespace, name string) error {
 	logrus.Debugf("delete deployment %s on namespace %s", name, namespace)
+	if err := k.pdb.Delete(namespace, name); err != nil {
+		logrus.Errorf("failed to delete pod disruption budget %s on namespace %s, (%v)", name, namespace, err)
+	}
 	return k.deploy.Delete(namespace, name)
 }
 
@@ -545,6 +554,8 @@ func (k *Kubernetes) newDeployment(service *apistructs.Service, serviceGroup *ap
 			strutil.ToUpper(service.Env[DiceWorkSpace]) == apistructs.TestWorkspace.String()) {
 		deployment.Spec.Strategy = appsv1.DeploymentStrategy{Type: "Recreate"}
 	}
+	// rolling update strategy configured in dice.yml takes precedence
+	SetRollingUpdateStrategy(deployment, service)
 
 	affinity := constraintbuilders.K8S(&serviceGroup.ScheduleInfo2, service, []constraints.PodLabelsForAffinity{
 		{PodLabels: map[string]string{"app": service.Name}}}, k).Affinity
@@ -556,6 +567,7 @@ func (k *Kubernetes) newDeployment(service *apistructs.Service, serviceGroup *ap
 	}
 
 	deployment.Spec.Template.Spec.Affinity = &affinity
+	deployment.Spec.Template.Spec.TopologySpreadConstraints = NewTopologySpreadConstraints(service.TopologySpread, deployment.Spec.Selector)
 	// inject hosts
 	deployment.Spec.Template.Spec.HostAliases = ConvertToHostAlias(service.Hosts)
 
@@ -824,7 +836,7 @@ func (k *Kubernetes) AddPodMountVolume(service *apistructs.Service, podSpec *cor
 			//hostPath = strutil.Concat("/mnt/k8s/", hostPath)
 			pvcName := strings.Replace(hostPath, "_", "-", -1)
 			sc := "dice-local-volume"
-			if err := k.pvc.CreateIfNotExists(&corev1.PersistentVolumeClaim{
+			if err := k.createPVCIfNotExists(&corev1.PersistentVolumeClaim{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      fmt.Sprintf("%s-%s", service.Name, pvcName),
 					Namespace: service.Namespace,
@@ -998,7 +1010,7 @@ func (k *Kubernetes) setStatelessServiceVolumes(service *apistructs.Service, pod
 		}
 
 		// 校验 sc 是否存在（volume 的 type + vendor 的组合可能会产生当前不支持的 sc）
-		_, err := k.storageClass.Get(sc)
+		_, err := k.getStorageClass(sc)
 		if err != nil {
 			if err.Error() == "not found" {
 				logrus.Errorf("failed to set volume for sevice %s: storageclass %s not found.", service.Name, sc)
@@ -1053,7 +1065,7 @@ func (k *Kubernetes) setStatelessServiceVolumes(service *apistructs.Service, pod
 			}
 		}
 
-		if err := k.pvc.CreateIfNotExists(&pvc); err != nil {
+		if err := k.createPVCIfNotExists(&pvc); err != nil {
 			return err
 		}
 
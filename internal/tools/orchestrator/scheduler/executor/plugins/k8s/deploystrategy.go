@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/k8serror"
+	"github.com/erda-project/erda/pkg/parser/diceyml"
+)
+
+const (
+	// LabelManagedBy marks the objects created by erda which are not owned by workload, such as pod disruption budget
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	managedByErda  = "erda"
+)
+
+// topologyKeys Mapping from dice.yml topology to k8s well-known topology label
+var topologyKeys = map[string]string{
+	diceyml.TopologyZone: corev1.LabelTopologyZone,
+	diceyml.TopologyNode: corev1.LabelHostname,
+}
+
+// SetRollingUpdateStrategy Set max surge and max unavailable of deployment if configured
+func SetRollingUpdateStrategy(deployment *appsv1.Deployment, service *apistructs.Service) {
+	if service.MaxSurge == nil && service.MaxUnavailable == nil {
+		return
+	}
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       service.MaxSurge,
+			MaxUnavailable: service.MaxUnavailable,
+		},
+	}
+}
+
+// NewTopologySpreadConstraints Convert topology spread of dice.yml to k8s topology spread constraints,
+// pods matching the selector are spread
+func NewTopologySpreadConstraints(spreads []diceyml.TopologySpread, selector *metav1.LabelSelector) []corev1.TopologySpreadConstraint {
+	if len(spreads) == 0 {
+		return nil
+	}
+
+	constraints := make([]corev1.TopologySpreadConstraint, 0, len(spreads))
+	for _, spread := range spreads {
+		topologyKey, ok := topologyKeys[spread.Topology]
+		if !ok {
+			logrus.Warnf("unsupported topology %s, skip it", spread.Topology)
+			continue
+		}
+		maxSkew := int32(spread.MaxSkew)
+		if maxSkew <= 0 {
+			maxSkew = 1
+		}
+		whenUnsatisfiable := corev1.ScheduleAnyway
+		if spread.WhenUnsatisfiable == diceyml.WhenUnsatisfiableDoNotSchedule {
+			whenUnsatisfiable = corev1.DoNotSchedule
+		}
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           maxSkew,
+			TopologyKey:       topologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector:     selector.DeepCopy(),
+		})
+	}
+	return constraints
+}
+
+// NewPodDisruptionBudget Create pod disruption budget for pods matching the selector,
+// return nil if min available is not configured
+func NewPodDisruptionBudget(namespace, name string, service *apistructs.Service, selector *metav1.LabelSelector) *policyv1.PodDisruptionBudget {
+	if service.MinAvailable == nil {
+		return nil
+	}
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": service.Name, LabelManagedBy: managedByErda},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: service.MinAvailable,
+			Selector:     selector.DeepCopy(),
+		},
+	}
+}
+
+// syncPodDisruptionBudget Create or update the pod disruption budget with the same name of workload,
+// delete it if min available is removed from dice.yml
+func (k *Kubernetes) syncPodDisruptionBudget(namespace, name string, service *apistructs.Service, selector *metav1.LabelSelector) error {
+	pdb := NewPodDisruptionBudget(namespace, name, service, selector)
+	if pdb != nil {
+		return k.pdb.CreateOrUpdate(pdb)
+	}
+	// Only delete the pdb created by us, clusters which never use pdb may not grant the permission of policy/v1
+	old, err := k.pdb.Get(namespace, name)
+	if err != nil {
+		if err == k8serror.ErrNotFound || k8serrors.IsForbidden(err) {
+			return nil
+		}
+		return err
+	}
+	if old.Labels[LabelManagedBy] != managedByErda {
+		return nil
+	}
+	return k.pdb.Delete(namespace, name)
+}
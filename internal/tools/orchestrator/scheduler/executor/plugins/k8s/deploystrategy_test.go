@@ -0,0 +1,114 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/k8serror"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/poddisruptionbudget"
+	"github.com/erda-project/erda/pkg/parser/diceyml"
+)
+
+func TestSetRollingUpdateStrategy(t *testing.T) {
+	deployment := &appsv1.Deployment{}
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+
+	// not configured
+	SetRollingUpdateStrategy(deployment, &apistructs.Service{})
+	assert.Equal(t, appsv1.RecreateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+
+	maxSurge, maxUnavailable := intstr.FromString("25%"), intstr.FromInt(0)
+	SetRollingUpdateStrategy(deployment, &apistructs.Service{MaxSurge: &maxSurge, MaxUnavailable: &maxUnavailable})
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	assert.Equal(t, "25%", deployment.Spec.Strategy.RollingUpdate.MaxSurge.String())
+	assert.Equal(t, 0, deployment.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue())
+}
+
+func TestNewTopologySpreadConstraints(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	assert.Nil(t, NewTopologySpreadConstraints(nil, selector))
+
+	constraints := NewTopologySpreadConstraints([]diceyml.TopologySpread{
+		{Topology: diceyml.TopologyZone, MaxSkew: 2, WhenUnsatisfiable: diceyml.WhenUnsatisfiableDoNotSchedule},
+		{Topology: diceyml.TopologyNode},
+		{Topology: "rack"},
+	}, selector)
+	assert.Equal(t, 2, len(constraints))
+	assert.Equal(t, corev1.LabelTopologyZone, constraints[0].TopologyKey)
+	assert.Equal(t, int32(2), constraints[0].MaxSkew)
+	assert.Equal(t, corev1.DoNotSchedule, constraints[0].WhenUnsatisfiable)
+	assert.Equal(t, corev1.LabelHostname, constraints[1].TopologyKey)
+	assert.Equal(t, int32(1), constraints[1].MaxSkew)
+	assert.Equal(t, corev1.ScheduleAnyway, constraints[1].WhenUnsatisfiable)
+	assert.Equal(t, selector.MatchLabels, constraints[1].LabelSelector.MatchLabels)
+}
+
+func TestNewPodDisruptionBudget(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	service := &apistructs.Service{Name: "web"}
+	assert.Nil(t, NewPodDisruptionBudget("default", "web", service, selector))
+
+	minAvailable := intstr.FromInt(1)
+	service.MinAvailable = &minAvailable
+	pdb := NewPodDisruptionBudget("default", "web-abc", service, selector)
+	assert.Equal(t, "web-abc", pdb.Name)
+	assert.Equal(t, "default", pdb.Namespace)
+	assert.Equal(t, 1, pdb.Spec.MinAvailable.IntValue())
+	assert.Equal(t, selector.MatchLabels, pdb.Spec.Selector.MatchLabels)
+}
+
+func TestSyncPodDisruptionBudget(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	minAvailable := intstr.FromInt(1)
+	cs := fakeclientset.NewSimpleClientset(&policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-pdb", Namespace: "default"},
+	})
+	k := &Kubernetes{pdb: poddisruptionbudget.New(poddisruptionbudget.WithClientSet(cs))}
+
+	// created by erda, deleted after min_available is removed
+	assert.NoError(t, k.syncPodDisruptionBudget("default", "web", &apistructs.Service{Name: "web", MinAvailable: &minAvailable}, selector))
+	pdb, err := k.pdb.Get("default", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, "erda", pdb.Labels[LabelManagedBy])
+	assert.NoError(t, k.syncPodDisruptionBudget("default", "web", &apistructs.Service{Name: "web"}, selector))
+	_, err = k.pdb.Get("default", "web")
+	assert.Equal(t, k8serror.ErrNotFound, err)
+
+	// not exist or not created by erda
+	assert.NoError(t, k.syncPodDisruptionBudget("default", "web", &apistructs.Service{Name: "web"}, selector))
+	assert.NoError(t, k.syncPodDisruptionBudget("default", "user-pdb", &apistructs.Service{Name: "user-pdb"}, selector))
+	_, err = k.pdb.Get("default", "user-pdb")
+	assert.NoError(t, err)
+
+	// no permission of policy/v1
+	cs.PrependReactor("*", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(policyv1.Resource("poddisruptionbudgets"), "web", fmt.Errorf("rbac"))
+	})
+	assert.NoError(t, k.syncPodDisruptionBudget("default", "web", &apistructs.Service{Name: "web"}, selector))
+}
@@ -15,10 +15,13 @@
 package k8s
 
 import (
+	"fmt"
+
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/pkg/parser/diceyml"
 )
 
 func (k *Kubernetes) NewHealthcheckProbe(service *apistructs.Service) *apiv1.Probe {
@@ -38,6 +41,48 @@ func SetHealthCheck(container *apiv1.Container, service *apistructs.Service) {
 	}
 	container.ReadinessProbe = readinessprobe
 
+	// Independent probes configured in dice.yml override the shared one
+	hc := service.NewHealthCheck
+	if hc == nil {
+		return
+	}
+	if hc.Liveness != nil {
+		container.LivenessProbe = CustomHealthCheckProbe(probe, hc.Liveness)
+	}
+	if hc.Readiness != nil {
+		container.ReadinessProbe = CustomHealthCheckProbe(readinessprobe, hc.Readiness)
+	}
+	if hc.Startup != nil {
+		container.StartupProbe = CustomHealthCheckProbe(probe, hc.Startup)
+	}
+}
+
+// CustomHealthCheckProbe Build an independent probe, the check type of base probe is used if none is configured
+func CustomHealthCheckProbe(base *apiv1.Probe, hp *apistructs.HealthCheckProbe) *apiv1.Probe {
+	probe := NewCheckProbe()
+	if !setProbeHandler(probe, hp.HttpHealthCheck, hp.ExecHealthCheck, hp.TCPHealthCheck, hp.GRPCHealthCheck) {
+		if base == nil {
+			return nil
+		}
+		probe = base.DeepCopy()
+	}
+
+	if hp.InitialDelaySeconds > 0 {
+		probe.InitialDelaySeconds = int32(hp.InitialDelaySeconds)
+	}
+	if hp.PeriodSeconds > 0 {
+		probe.PeriodSeconds = int32(hp.PeriodSeconds)
+	}
+	if hp.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = int32(hp.TimeoutSeconds)
+	}
+	if hp.SuccessThreshold > 0 {
+		probe.SuccessThreshold = int32(hp.SuccessThreshold)
+	}
+	if hp.FailureThreshold > 0 {
+		probe.FailureThreshold = int32(hp.FailureThreshold)
+	}
+	return probe
 }
 
 // FillHealthCheckProbe Fill out k8s probe based on service
@@ -48,7 +93,8 @@ func FillHealthCheckProbe(service *apistructs.Service) *apiv1.Probe {
 		oldHC = service.HealthCheck
 	)
 
-	if newHC != nil && (newHC.ExecHealthCheck != nil || newHC.HttpHealthCheck != nil) {
+	if newHC != nil && (newHC.ExecHealthCheck != nil || newHC.HttpHealthCheck != nil ||
+		newHC.TCPHealthCheck != nil || newHC.GRPCHealthCheck != nil) {
 		probe = NewHealthCheck(newHC)
 	} else if oldHC != nil {
 		probe = OldHealthCheck(oldHC)
@@ -87,32 +133,58 @@ func DefaultHealthCheck(service *apistructs.Service) *apiv1.Probe {
 
 // NewHealthCheck Configure the new version of Dice health check
 func NewHealthCheck(hc *apistructs.NewHealthCheck) *apiv1.Probe {
-	if hc == nil || (hc.HttpHealthCheck == nil && hc.ExecHealthCheck == nil) {
+	if hc == nil {
 		return nil
 	}
 
 	probe := NewCheckProbe()
-	if hc.HttpHealthCheck != nil {
-		httpCheck := hc.HttpHealthCheck
+	if !setProbeHandler(probe, hc.HttpHealthCheck, hc.ExecHealthCheck, hc.TCPHealthCheck, hc.GRPCHealthCheck) {
+		return nil
+	}
+	return probe
+}
+
+// setProbeHandler Set the check action of probe in the order http, exec, tcp, grpc, return false if none is configured
+func setProbeHandler(probe *apiv1.Probe, httpCheck *apistructs.HttpHealthCheck, execCheck *apistructs.ExecHealthCheck,
+	tcpCheck *apistructs.TCPHealthCheck, grpcCheck *apistructs.GRPCHealthCheck) bool {
+	var duration int
+	switch {
+	case httpCheck != nil:
 		probe.HTTPGet = &apiv1.HTTPGetAction{
 			Path:   httpCheck.Path,
 			Port:   intstr.FromInt(httpCheck.Port),
 			Scheme: apiv1.URIScheme("HTTP"),
 		}
-
-		if times := int32(httpCheck.Duration) / 15; times > probe.FailureThreshold {
-			probe.FailureThreshold = times
-		}
-	} else if hc.ExecHealthCheck != nil {
-		execCheck := hc.ExecHealthCheck
+		duration = httpCheck.Duration
+	case execCheck != nil:
 		probe.Exec = &apiv1.ExecAction{
 			Command: []string{"sh", "-c", execCheck.Cmd},
 		}
-		if times := int32(execCheck.Duration) / 15; times > probe.FailureThreshold {
-			probe.FailureThreshold = times
+		duration = execCheck.Duration
+	case tcpCheck != nil:
+		probe.TCPSocket = &apiv1.TCPSocketAction{
+			Port: intstr.FromInt(tcpCheck.Port),
+		}
+		duration = tcpCheck.Duration
+	case grpcCheck != nil:
+		// The current k8s api has no native grpc probe, use grpc_health_probe in the image instead,
+		// dice.yml validation warns about it, see diceyml.GRPCCheck
+		command := []string{diceyml.GRPCHealthProbeBin, fmt.Sprintf("-addr=127.0.0.1:%d", grpcCheck.Port)}
+		if grpcCheck.Service != "" {
+			command = append(command, "-service="+grpcCheck.Service)
+		}
+		probe.Exec = &apiv1.ExecAction{
+			Command: command,
 		}
+		duration = grpcCheck.Duration
+	default:
+		return false
 	}
-	return probe
+
+	if times := int32(duration) / 15; times > probe.FailureThreshold {
+		probe.FailureThreshold = times
+	}
+	return true
 }
 
 // OldHealthCheck Compatible with Dice old version health detection
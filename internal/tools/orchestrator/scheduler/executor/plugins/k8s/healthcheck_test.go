@@ -87,3 +87,69 @@ func TestFillHealthCheckProbe(t *testing.T) {
 	assert.Equal(t, []string{"sh", "-c", service.NewHealthCheck.ExecHealthCheck.Cmd}, probe.Exec.Command)
 	assert.Equal(t, int32(service.NewHealthCheck.ExecHealthCheck.Duration/15), probe.FailureThreshold)
 }
+
+func TestFillHealthCheckProbe_TCPAndGRPC(t *testing.T) {
+	service := &apistructs.Service{
+		NewHealthCheck: &apistructs.NewHealthCheck{
+			TCPHealthCheck: &apistructs.TCPHealthCheck{
+				Port:     8080,
+				Duration: 600,
+			},
+		},
+	}
+	probe := FillHealthCheckProbe(service)
+	assert.NotNil(t, probe)
+	assert.Equal(t, 8080, probe.TCPSocket.Port.IntValue())
+	assert.Equal(t, int32(600/15), probe.FailureThreshold)
+
+	service.NewHealthCheck = &apistructs.NewHealthCheck{
+		GRPCHealthCheck: &apistructs.GRPCHealthCheck{
+			Port:    9090,
+			Service: "erda.Health",
+		},
+	}
+	probe = FillHealthCheckProbe(service)
+	assert.NotNil(t, probe)
+	assert.Equal(t, []string{"grpc_health_probe", "-addr=127.0.0.1:9090", "-service=erda.Health"}, probe.Exec.Command)
+}
+
+func TestSetHealthCheck_IndependentProbes(t *testing.T) {
+	service := &apistructs.Service{
+		NewHealthCheck: &apistructs.NewHealthCheck{
+			HttpHealthCheck: &apistructs.HttpHealthCheck{
+				Port: 80,
+				Path: "/health",
+			},
+			Readiness: &apistructs.HealthCheckProbe{
+				HttpHealthCheck: &apistructs.HttpHealthCheck{
+					Port: 80,
+					Path: "/ready",
+				},
+				PeriodSeconds:    5,
+				SuccessThreshold: 2,
+			},
+			Startup: &apistructs.HealthCheckProbe{
+				FailureThreshold: 60,
+			},
+		},
+	}
+	container := &corev1.Container{}
+	SetHealthCheck(container, service)
+
+	assert.Equal(t, "/health", container.LivenessProbe.HTTPGet.Path)
+
+	assert.Equal(t, "/ready", container.ReadinessProbe.HTTPGet.Path)
+	assert.Equal(t, int32(5), container.ReadinessProbe.PeriodSeconds)
+	assert.Equal(t, int32(2), container.ReadinessProbe.SuccessThreshold)
+
+	// startup inherits the check type of liveness
+	assert.NotNil(t, container.StartupProbe)
+	assert.Equal(t, "/health", container.StartupProbe.HTTPGet.Path)
+	assert.Equal(t, int32(60), container.StartupProbe.FailureThreshold)
+
+	// no startup probe when not configured
+	service.NewHealthCheck.Startup = nil
+	container = &corev1.Container{}
+	SetHealthCheck(container, service)
+	assert.Nil(t, container.StartupProbe)
+}
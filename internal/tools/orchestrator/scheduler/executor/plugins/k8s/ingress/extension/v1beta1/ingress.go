@@ -42,7 +42,7 @@ func (i *Ingress) CreateIfNotExists(svc *apistructs.Service) error {
 		return errors.New("service is nil")
 	}
 
-	ing, err := buildIngress(svc)
+	ing, err := BuildIngress(svc)
 	if err != nil || ing == nil {
 		return err
 	}
@@ -60,7 +60,8 @@ func (i *Ingress) CreateIfNotExists(svc *apistructs.Service) error {
 	return nil
 }
 
-func buildIngress(svc *apistructs.Service) (*extensionsv1beta1.Ingress, error) {
+// BuildIngress build ingress for public hosts of service, return nil if no public host
+func BuildIngress(svc *apistructs.Service) (*extensionsv1beta1.Ingress, error) {
 	publicHosts := common.ParsePublicHostsFromLabel(svc.Labels)
 	if len(publicHosts) == 0 {
 		return nil, nil
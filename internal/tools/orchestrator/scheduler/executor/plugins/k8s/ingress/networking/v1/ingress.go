@@ -41,7 +41,7 @@ func (i *Ingress) CreateIfNotExists(svc *apistructs.Service) error {
 		return errors.New("service is nil")
 	}
 
-	ing, err := buildIngress(svc)
+	ing, err := BuildIngress(svc)
 	if err != nil || ing == nil {
 		return err
 	}
@@ -59,7 +59,8 @@ func (i *Ingress) CreateIfNotExists(svc *apistructs.Service) error {
 	return nil
 }
 
-func buildIngress(svc *apistructs.Service) (*networkingv1.Ingress, error) {
+// BuildIngress build ingress for public hosts of service, return nil if no public host
+func BuildIngress(svc *apistructs.Service) (*networkingv1.Ingress, error) {
 	publicHosts := common.ParsePublicHostsFromLabel(svc.Labels)
 	if len(publicHosts) == 0 {
 		return nil, nil
@@ -32,6 +32,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	vpatypes "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	vpa_clientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
 
@@ -64,6 +65,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/persistentvolume"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/persistentvolumeclaim"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/pod"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/poddisruptionbudget"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/resourceinfo"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/scaledobject"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/secret"
@@ -201,6 +203,7 @@ type Kubernetes struct {
 	pv           *persistentvolume.PersistentVolume
 	scaledObject *scaledobject.ErdaScaledObject
 	hpa          *erdahpa.ErdaHPA
+	pdb          *poddisruptionbudget.PodDisruptionBudget
 	sts          *statefulset.StatefulSet
 	pod          *pod.Pod
 	secret       *secret.Secret
@@ -237,6 +240,13 @@ type Kubernetes struct {
 	dbclient *instanceinfo.Client
 
 	istioEngine istioctl.IstioEngine
+
+	// dryRun renders objects without accessing the cluster, objects which should be created
+	// along with workloads (such as pvc) are recorded in dryRunObjects, see RenderManifests
+	dryRun        bool
+	dryRunObjects []k8sruntime.Object
+	// dryRunErdaSecretNames names of secrets in the "secret" namespace, referenced by CopyErdaSecrets in dry run
+	dryRunErdaSecretNames []string
 }
 
 func (k *Kubernetes) SetCpuQuota(quota float64) {
@@ -373,6 +383,7 @@ func New(name executortypes.Name, clusterName string, options map[string]string)
 	scaleObj := scaledobject.New(scaledobject.WithCompleteParams(addr, client), scaledobject.WithVPAClient(vpaClient))
 	hpa := erdahpa.New(erdahpa.WithCompleteParams(addr, client))
 	sts := statefulset.New(statefulset.WithCompleteParams(addr, client))
+	pdb := poddisruptionbudget.New(poddisruptionbudget.WithClientSet(k8sClient.ClientSet))
 	k8spod := pod.New(pod.WithK8sClient(k8sClient.ClientSet))
 	k8ssecret := secret.New(secret.WithCompleteParams(addr, client))
 	k8sstorageclass := storageclass.New(storageclass.WithCompleteParams(addr, client))
@@ -427,6 +438,7 @@ func New(name executortypes.Name, clusterName string, options map[string]string)
 		pv:                       pv,
 		scaledObject:             scaleObj,
 		hpa:                      hpa,
+		pdb:                      pdb,
 		sts:                      sts,
 		pod:                      k8spod,
 		secret:                   k8ssecret,
@@ -560,6 +572,11 @@ func (k *Kubernetes) Destroy(ctx context.Context, specObj interface{}) error {
 		ns = runtime.ProjectNamespace
 		k.setProjectServiceName(runtime)
 	}
+	if IsGroupStateful(runtime) && len(runtime.Services) > 0 {
+		if err := k.deleteStatefulSetPodDisruptionBudgets(runtime); err != nil {
+			logrus.Errorf("failed to delete pod disruption budgets of statefulsets, namespace: %s, name: %s, (%v)", ns, runtime.ID, err)
+		}
+	}
 	if runtime.ProjectNamespace == "" {
 		logrus.Infof("delete runtime %s on namespace %s", runtime.ID, runtime.Type)
 		if err := k.destroyRuntime(ns); err != nil {
@@ -1398,6 +1415,10 @@ func (k *Kubernetes) Scale(ctx context.Context, spec interface{}) (interface{},
 		return nil, errors.Errorf("invalid servicegroup spec: %#v", spec)
 	}
 
+	if _, ok := sg.Labels[apistructs.ServiceGroupCanaryLabelKey]; ok {
+		return k.canary(&sg)
+	}
+
 	value, ok := sg.Labels[pstypes.ErdaPALabelKey]
 	if !ok {
 		return k.manualScale(ctx, spec)
@@ -1989,6 +2010,11 @@ func (k *Kubernetes) setImagePullSecrets(namespace string) ([]apiv1.LocalObjectR
 	secrets := make([]apiv1.LocalObjectReference, 0, 1)
 	secretName := conf.CustomRegCredSecret()
 
+	// the image pull secret is expected to be prepared in the target cluster
+	if k.dryRun {
+		return append(secrets, apiv1.LocalObjectReference{Name: secretName}), nil
+	}
+
 	_, err := k.secret.Get(namespace, secretName)
 	if err == nil {
 		secrets = append(secrets,
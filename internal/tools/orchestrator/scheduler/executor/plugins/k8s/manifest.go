@@ -0,0 +1,232 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	papb "github.com/erda-project/erda-proto-go/orchestrator/podscaler/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/clusterinfo"
+	ingv1 "github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/ingress/networking/v1"
+)
+
+// ManifestOptions options to render kubernetes manifests of service group offline
+type ManifestOptions struct {
+	// ClusterName name of the target cluster
+	ClusterName string
+	// ClusterInfo data of the dice-cluster-info configmap in the target cluster
+	ClusterInfo map[string]string
+	// Namespace overrides the namespace which executor would choose
+	Namespace string
+	// ScaledConfigs hpa rules keyed by service name, in the same json format as ServiceGroup.Extra
+	ScaledConfigs map[string]string
+	// ErdaSecretNames names of secrets in the "secret" namespace of the target cluster,
+	// the dice-* ones are mounted to every workload like a real deploy
+	ErdaSecretNames []string
+}
+
+// newDryRunKubernetes news a Kubernetes which never accesses the cluster
+func newDryRunKubernetes(opts ManifestOptions) *Kubernetes {
+	return &Kubernetes{
+		clusterName:              opts.ClusterName,
+		options:                  map[string]string{},
+		ClusterInfo:              clusterinfo.NewStatic(opts.ClusterName, opts.ClusterInfo),
+		cpuSubscribeRatio:        1,
+		memSubscribeRatio:        1,
+		devCpuSubscribeRatio:     1,
+		devMemSubscribeRatio:     1,
+		testCpuSubscribeRatio:    1,
+		testMemSubscribeRatio:    1,
+		stagingCpuSubscribeRatio: 1,
+		stagingMemSubscribeRatio: 1,
+		dryRun:                   true,
+		dryRunErdaSecretNames:    opts.ErdaSecretNames,
+	}
+}
+
+// RenderManifests renders the objects which executor would create for the stateless service group,
+// including services, deployments, daemonsets, pvcs, pod disruption budgets, ingresses and scaled objects.
+//
+// No ConfigMap is rendered because executor never creates one for stateless services: the envs of
+// service are inlined into the pod template. Secrets are only referenced by name and must be prepared
+// in the target cluster: the image pull secret, and the dice-* secrets in ManifestOptions.ErdaSecretNames
+// which executor would copy from the "secret" namespace and mount at /{name}.
+func RenderManifests(sg *apistructs.ServiceGroup, opts ManifestOptions) ([]k8sruntime.Object, error) {
+	if sg == nil {
+		return nil, errors.New("service group empty")
+	}
+	if IsGroupStateful(sg) {
+		return nil, errors.Errorf("rendering manifests of stateful service group is not supported, name: %s", sg.ID)
+	}
+
+	k := newDryRunKubernetes(opts)
+	if sg.ProjectNamespace != "" {
+		k.setProjectServiceName(sg)
+	}
+	ns := opts.Namespace
+	if ns == "" {
+		ns = MakeNamespace(sg)
+		if sg.ProjectNamespace != "" {
+			ns = sg.ProjectNamespace
+		}
+	}
+
+	var services, workloads, others []k8sruntime.Object
+	for i := range sg.Services {
+		service := &sg.Services[i]
+		service.Namespace = ns
+
+		if len(service.Ports) > 0 {
+			services = append(services, newService(service, nil))
+			if service.ProjectServiceName != "" {
+				projectService := *service
+				projectService.Name = service.ProjectServiceName
+				services = append(services, newService(&projectService, map[string]string{
+					LabelServiceGroupID: sg.ID,
+					"app":               service.Name,
+				}))
+			}
+			ing, err := ingv1.BuildIngress(service)
+			if err != nil {
+				return nil, errors.Errorf("failed to build ingress, name: %s, (%v)", service.Name, err)
+			}
+			if ing != nil {
+				ing.APIVersion = "networking.k8s.io/v1"
+				ing.Kind = "Ingress"
+				others = append(others, ing)
+			}
+		}
+
+		switch service.WorkLoad {
+		case ServicePerNode:
+			ds, err := k.newDaemonSet(service, sg)
+			if err != nil {
+				return nil, errors.Errorf("failed to generate daemonset struct, name: %s, (%v)", service.Name, err)
+			}
+			ds.APIVersion = "apps/v1"
+			ds.Kind = "DaemonSet"
+			workloads = append(workloads, ds)
+		case ServiceJob:
+			return nil, errors.Errorf("rendering manifests of job service is not supported, name: %s", service.Name)
+		default:
+			deployment, err := k.newDeployment(service, sg)
+			if err != nil {
+				return nil, errors.Errorf("failed to generate deployment struct, name: %s, (%v)", service.Name, err)
+			}
+			deployment.APIVersion = "apps/v1"
+			deployment.Kind = "Deployment"
+			workloads = append(workloads, deployment)
+			if pdb := NewPodDisruptionBudget(deployment.Namespace, deployment.Name, service, deployment.Spec.Selector); pdb != nil {
+				pdb.APIVersion = "policy/v1"
+				pdb.Kind = "PodDisruptionBudget"
+				others = append(others, pdb)
+			}
+			sc, ok := opts.ScaledConfigs[service.Name]
+			if !ok {
+				continue
+			}
+			scaledConfig := papb.ScaledConfig{}
+			if err := json.Unmarshal([]byte(sc), &scaledConfig); err != nil {
+				return nil, errors.Errorf("failed to parse hpa rule of service %s: %v", service.Name, err)
+			}
+			if scaledConfig.ServiceName == "" {
+				scaledConfig.ServiceName = service.Name
+			}
+			if scaledConfig.RuleNameSpace == "" {
+				scaledConfig.RuleNameSpace = deployment.Namespace
+			}
+			if scaledConfig.ScaleTargetRef == nil {
+				scaledConfig.ScaleTargetRef = &papb.ScaleTargetRef{}
+			}
+			if scaledConfig.ScaleTargetRef.Name == "" {
+				scaledConfig.ScaleTargetRef.Kind = "Deployment"
+				scaledConfig.ScaleTargetRef.ApiVersion = "apps/v1"
+				scaledConfig.ScaleTargetRef.Name = deployment.Name
+			}
+			if scaledConfig.RuleName == "" {
+				return nil, errors.Errorf("failed to render hpa rule of service %s: rule name not set", service.Name)
+			}
+			others = append(others, convertToKedaScaledObject(scaledConfig))
+		}
+	}
+
+	objs := append(services, k.dryRunObjects...)
+	objs = append(objs, workloads...)
+	return append(objs, others...), nil
+}
+
+// EncodeManifests encodes objects as multi-document yaml
+func EncodeManifests(objs []k8sruntime.Object) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, errors.Errorf("failed to marshal manifest, (%v)", err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderHelmChart packs objects as files of a helm chart, keyed by the relative path in chart directory,
+// the template delimiters in manifests are escaped so that helm renders them as they are
+func RenderHelmChart(name, version string, objs []k8sruntime.Object) (map[string][]byte, error) {
+	if name == "" {
+		return nil, errors.New("chart name empty")
+	}
+	if version == "" {
+		version = "0.1.0"
+	}
+	chart, err := yaml.Marshal(map[string]string{
+		"apiVersion":  "v2",
+		"name":        name,
+		"description": fmt.Sprintf("Rendered from dice.yml of %s", name),
+		"type":        "application",
+		"version":     version,
+		"appVersion":  version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{
+		"Chart.yaml":  chart,
+		"values.yaml": []byte("{}\n"),
+	}
+	for i, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, errors.Errorf("failed to marshal manifest, (%v)", err)
+		}
+		kind := strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind)
+		file := path.Join("templates", fmt.Sprintf("%02d-%s.yaml", i, kind))
+		if named, ok := obj.(interface{ GetName() string }); ok {
+			file = path.Join("templates", fmt.Sprintf("%02d-%s-%s.yaml", i, kind, named.GetName()))
+		}
+		files[file] = []byte(strings.ReplaceAll(string(b), "{{", `{{ "{{" }}`))
+	}
+	return files, nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/conf"
+	"github.com/erda-project/erda/pkg/parser/diceyml"
+)
+
+func newManifestServiceGroup() *apistructs.ServiceGroup {
+	minAvailable := intstr.FromInt(1)
+	return &apistructs.ServiceGroup{
+		Dice: apistructs.Dice{
+			ID:     "web-1",
+			Type:   "services",
+			Labels: map[string]string{},
+			Services: []apistructs.Service{
+				{
+					Name:         "web",
+					Image:        "nginx:latest",
+					Scale:        2,
+					Resources:    apistructs.Resources{Cpu: 0.5, Mem: 512},
+					Ports:        []diceyml.ServicePort{{Port: 80, Protocol: "TCP"}},
+					Env:          map[string]string{"GREETING": "{{ hello }}"},
+					Labels:       map[string]string{"HAPROXY_0_VHOST": "web.example.com"},
+					MinAvailable: &minAvailable,
+				},
+			},
+		},
+	}
+}
+
+func TestRenderManifests(t *testing.T) {
+	objs, err := RenderManifests(newManifestServiceGroup(), ManifestOptions{ClusterName: "test", Namespace: "demo"})
+	assert.NoError(t, err)
+
+	var kinds []string
+	for _, obj := range objs {
+		kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	assert.Equal(t, []string{"Service", "Deployment", "Ingress", "PodDisruptionBudget"}, kinds)
+
+	sg := newManifestServiceGroup()
+	sg.Labels[ServiceType] = ServiceAddon
+	_, err = RenderManifests(sg, ManifestOptions{ClusterName: "test"})
+	assert.Error(t, err)
+}
+
+func TestRenderManifests_EnvsInlined(t *testing.T) {
+	objs, err := RenderManifests(newManifestServiceGroup(), ManifestOptions{ClusterName: "test", Namespace: "demo"})
+	assert.NoError(t, err)
+
+	var deployment *appsv1.Deployment
+	for _, obj := range objs {
+		// executor creates no configmap for stateless services
+		assert.NotEqual(t, "ConfigMap", obj.GetObjectKind().GroupVersionKind().Kind)
+		if d, ok := obj.(*appsv1.Deployment); ok {
+			deployment = d
+		}
+	}
+	if !assert.NotNil(t, deployment) {
+		return
+	}
+
+	var greeting *corev1.EnvVar
+	for i, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == "GREETING" {
+			greeting = &deployment.Spec.Template.Spec.Containers[0].Env[i]
+		}
+	}
+	if assert.NotNil(t, greeting) {
+		assert.Equal(t, "{{ hello }}", greeting.Value)
+		assert.Nil(t, greeting.ValueFrom)
+	}
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: conf.CustomRegCredSecret()}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+}
+
+func TestRenderManifests_ErdaSecrets(t *testing.T) {
+	objs, err := RenderManifests(newManifestServiceGroup(), ManifestOptions{ClusterName: "test", Namespace: "demo",
+		ErdaSecretNames: []string{"dice-ca", "default-token-abc"}})
+	assert.NoError(t, err)
+
+	var deployment *appsv1.Deployment
+	for _, obj := range objs {
+		// secrets are referenced only, never rendered
+		assert.NotEqual(t, "Secret", obj.GetObjectKind().GroupVersionKind().Kind)
+		if d, ok := obj.(*appsv1.Deployment); ok {
+			deployment = d
+		}
+	}
+	if !assert.NotNil(t, deployment) {
+		return
+	}
+	podSpec := deployment.Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{Name: "dice-ca",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "dice-ca"}}})
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "dice-ca", MountPath: "/dice-ca", ReadOnly: true})
+	for _, v := range podSpec.Volumes {
+		assert.NotEqual(t, "default-token-abc", v.Name)
+	}
+}
+
+func TestEncodeManifests(t *testing.T) {
+	objs, err := RenderManifests(newManifestServiceGroup(), ManifestOptions{ClusterName: "test", Namespace: "demo"})
+	assert.NoError(t, err)
+
+	b, err := EncodeManifests(objs)
+	assert.NoError(t, err)
+	docs := strings.Split(string(b), "---\n")
+	assert.Equal(t, len(objs), len(docs))
+	assert.Contains(t, docs[0], "kind: Service")
+	assert.Contains(t, docs[1], "namespace: demo")
+}
+
+func TestRenderHelmChart(t *testing.T) {
+	objs, err := RenderManifests(newManifestServiceGroup(), ManifestOptions{ClusterName: "test", Namespace: "demo"})
+	assert.NoError(t, err)
+
+	_, err = RenderHelmChart("", "", objs)
+	assert.Error(t, err)
+
+	files, err := RenderHelmChart("web", "", objs)
+	assert.NoError(t, err)
+	assert.Contains(t, string(files["Chart.yaml"]), "version: 0.1.0")
+	assert.Contains(t, files, "values.yaml")
+	deployment, ok := files["templates/01-deployment-web.yaml"]
+	assert.True(t, ok)
+	assert.Contains(t, string(deployment), `{{ "{{" }} hello }}`)
+}
@@ -29,6 +29,10 @@ import (
 )
 
 func (k *Kubernetes) IPToHostname(ip string) string {
+	if k.dryRun {
+		logrus.Warnf("can not convert ip %s to hostname in dry run mode", ip)
+		return ""
+	}
 	nodes, err := k.k8sClient.ClientSet.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		logrus.Errorf("failed to list node, %v", err)
@@ -16,10 +16,30 @@ package k8s
 
 import (
 	apiv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 )
 
 func (k *Kubernetes) newPVC(pvc *apiv1.PersistentVolumeClaim) error {
 	return k.pvc.Create(pvc)
 }
 
+// createPVCIfNotExists creates pvc if not exists, in dry-run mode the pvc is only recorded
+func (k *Kubernetes) createPVCIfNotExists(pvc *apiv1.PersistentVolumeClaim) error {
+	if k.dryRun {
+		pvc.TypeMeta.APIVersion = "v1"
+		pvc.TypeMeta.Kind = "PersistentVolumeClaim"
+		k.dryRunObjects = append(k.dryRunObjects, pvc.DeepCopy())
+		return nil
+	}
+	return k.pvc.CreateIfNotExists(pvc)
+}
+
+// getStorageClass gets storage class by name, the check is skipped in dry-run mode
+func (k *Kubernetes) getStorageClass(name string) (*storagev1.StorageClass, error) {
+	if k.dryRun {
+		return nil, nil
+	}
+	return k.storageClass.Get(name)
+}
+
 // todo: deletePVC
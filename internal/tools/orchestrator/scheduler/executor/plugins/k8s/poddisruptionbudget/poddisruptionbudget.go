@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package poddisruptionbudget manipulates the k8s api of poddisruptionbudget object
+package poddisruptionbudget
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/k8serror"
+)
+
+// PodDisruptionBudget is the object to manipulate k8s api of poddisruptionbudget
+type PodDisruptionBudget struct {
+	cs kubernetes.Interface
+}
+
+// Option configures a PodDisruptionBudget
+type Option func(*PodDisruptionBudget)
+
+// New news a PodDisruptionBudget
+func New(options ...Option) *PodDisruptionBudget {
+	pdb := &PodDisruptionBudget{}
+
+	for _, op := range options {
+		op(pdb)
+	}
+
+	return pdb
+}
+
+// WithClientSet with kubernetes clientSet
+func WithClientSet(c kubernetes.Interface) Option {
+	return func(p *PodDisruptionBudget) {
+		p.cs = c
+	}
+}
+
+// Get gets a k8s poddisruptionbudget object
+func (p *PodDisruptionBudget) Get(namespace, name string) (*policyv1.PodDisruptionBudget, error) {
+	pdb, err := p.cs.PolicyV1().PodDisruptionBudgets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, k8serror.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return pdb, nil
+}
+
+// CreateOrUpdate creates the poddisruptionbudget if not exists, otherwise updates its spec
+func (p *PodDisruptionBudget) CreateOrUpdate(pdb *policyv1.PodDisruptionBudget) error {
+	old, err := p.Get(pdb.Namespace, pdb.Name)
+	if err != nil {
+		if err != k8serror.ErrNotFound {
+			return err
+		}
+		_, err = p.cs.PolicyV1().PodDisruptionBudgets(pdb.Namespace).
+			Create(context.Background(), pdb, metav1.CreateOptions{})
+		return err
+	}
+
+	old.Labels = pdb.Labels
+	old.Spec = pdb.Spec
+	_, err = p.cs.PolicyV1().PodDisruptionBudgets(pdb.Namespace).
+		Update(context.Background(), old, metav1.UpdateOptions{})
+	return err
+}
+
+// Delete deletes a k8s poddisruptionbudget, it's ok if not exists.
+// Forbidden is also ignored, the pdb can't be created without the permission.
+func (p *PodDisruptionBudget) Delete(namespace, name string) error {
+	if err := p.cs.PolicyV1().PodDisruptionBudgets(namespace).
+		Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsForbidden(err) {
+		return err
+	}
+
+	return nil
+}
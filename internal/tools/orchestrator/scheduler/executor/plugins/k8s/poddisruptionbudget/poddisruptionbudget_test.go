@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poddisruptionbudget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/k8serror"
+)
+
+func TestPodDisruptionBudget(t *testing.T) {
+	p := New(WithClientSet(fakeclientset.NewSimpleClientset()))
+
+	minAvailable := intstr.FromInt(1)
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test-namespace"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	assert.NoError(t, p.CreateOrUpdate(pdb))
+
+	minAvailable = intstr.FromString("50%")
+	assert.NoError(t, p.CreateOrUpdate(pdb))
+	got, err := p.Get("test-namespace", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, "50%", got.Spec.MinAvailable.String())
+
+	assert.NoError(t, p.Delete("test-namespace", "web"))
+	_, err = p.Get("test-namespace", "web")
+	assert.Equal(t, k8serror.ErrNotFound, err)
+	// delete again is ok
+	assert.NoError(t, p.Delete("test-namespace", "web"))
+}
@@ -154,6 +154,17 @@ func (k *Kubernetes) UpdateImageSecret(namespace string, infos []apistructs.Regi
 
 // CopyErdaSecrets Copy the secret under orignns namespace to dstns
 func (k *Kubernetes) CopyErdaSecrets(originns, dstns string) ([]apiv1.Secret, error) {
+	if k.dryRun {
+		// nothing is copied, return placeholders of the known names so that the same volumes are rendered
+		result := []apiv1.Secret{}
+		for _, name := range k.dryRunErdaSecretNames {
+			if !strutil.HasPrefixes(name, "dice-") {
+				continue
+			}
+			result = append(result, apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: dstns}})
+		}
+		return result, nil
+	}
 	secrets, err := k.secret.List(originns)
 	if err != nil {
 		return nil, err
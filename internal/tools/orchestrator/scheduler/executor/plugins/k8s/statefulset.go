@@ -129,6 +129,7 @@ func (k *Kubernetes) createStatefulSet(ctx context.Context, info StatefulsetInfo
 	setPodAnnotationsFromLabels(service, set.Spec.Template.Annotations)
 
 	set.Spec.Template.Spec.Affinity = &affinity
+	set.Spec.Template.Spec.TopologySpreadConstraints = NewTopologySpreadConstraints(service.TopologySpread, set.Spec.Selector)
 
 	imagePullSecrets, err := k.setImagePullSecrets(service.Namespace)
 	if err != nil {
@@ -222,7 +223,11 @@ func (k *Kubernetes) createStatefulSet(ctx context.Context, info StatefulsetInfo
 
 	SetPodAnnotationsBaseContainerEnvs(set.Spec.Template.Spec.Containers[0], set.Spec.Template.Annotations)
 
-	return k.sts.Create(set)
+	if err := k.sts.Create(set); err != nil {
+		return err
+	}
+	// max_surge and max_unavailable are not supported by statefulset, only pod disruption budget is created
+	return k.syncPodDisruptionBudget(set.Namespace, set.Name, service, set.Spec.Selector)
 }
 
 func extractContainerEnvs(containers []corev1.Container) (addonID, projectID, workspace, runtimeID string) {
@@ -718,6 +723,32 @@ func (k *Kubernetes) scaleStatefulSet(ctx context.Context, sg *apistructs.Servic
 		updateErr := fmt.Errorf("failed to update the statefulset %s in namespace %s, err is: %s", sts.Name, sts.Namespace, err.Error())
 		return updateErr
 	}
+	if err = k.syncPodDisruptionBudget(sts.Namespace, sts.Name, &scalingService, sts.Spec.Selector); err != nil {
+		return fmt.Errorf("failed to update pod disruption budget of statefulset %s in namespace %s, err is: %s", sts.Name, sts.Namespace, err.Error())
+	}
+	return nil
+}
+
+// deleteStatefulSetPodDisruptionBudgets delete pod disruption budgets of all statefulsets in the group
+func (k *Kubernetes) deleteStatefulSetPodDisruptionBudgets(sg *apistructs.ServiceGroup) error {
+	groups, err := groupStatefulset(sg)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if len(group.Services) == 0 {
+			continue
+		}
+		ns := group.Services[0].Namespace
+		if ns == "" {
+			ns = MakeNamespace(sg)
+		}
+		name := statefulsetName(group)
+		logrus.Debugf("delete pod disruption budget %s on namespace %s", name, ns)
+		if err := k.pdb.Delete(ns, name); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
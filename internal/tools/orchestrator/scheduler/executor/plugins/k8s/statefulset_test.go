@@ -29,12 +29,16 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/deployment"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/k8serror"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/k8sservice"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/namespace"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/persistentvolumeclaim"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/poddisruptionbudget"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/secret"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/statefulset"
 	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s/toleration"
@@ -297,6 +301,7 @@ func TestParseJobSpecTemplate(t *testing.T) {
 func TestCreateStatefulSet(t *testing.T) {
 	kubernetes := &Kubernetes{
 		secret: &secret.Secret{},
+		pdb:    poddisruptionbudget.New(poddisruptionbudget.WithClientSet(fakeclientset.NewSimpleClientset())),
 	}
 
 	defer monkey.UnpatchAll()
@@ -368,8 +373,10 @@ func TestCreateStatefulSet(t *testing.T) {
 }
 
 func Test_scaleStatefulSet(t *testing.T) {
+	minAvailable := intstr.FromInt(1)
 	k := &Kubernetes{
 		sts: &statefulset.StatefulSet{},
+		pdb: poddisruptionbudget.New(poddisruptionbudget.WithClientSet(fakeclientset.NewSimpleClientset())),
 	}
 
 	sg := &apistructs.ServiceGroup{
@@ -416,6 +423,7 @@ func Test_scaleStatefulSet(t *testing.T) {
 					ProjectServiceName: "",
 					K8SSnippet:         nil,
 					StatusDesc:         apistructs.StatusDesc{},
+					MinAvailable:       &minAvailable,
 				},
 			},
 			ServiceDiscoveryKind: "",
@@ -483,4 +491,15 @@ func Test_scaleStatefulSet(t *testing.T) {
 
 	err := k.scaleStatefulSet(context.Background(), sg)
 	assert.Nil(t, err)
+
+	// pod disruption budget is synced after scale
+	pdb, err := k.pdb.Get("fake-test", "11111111")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pdb.Spec.MinAvailable.IntValue())
+
+	// and deleted with the stateful group
+	err = k.deleteStatefulSetPodDisruptionBudgets(sg)
+	assert.Nil(t, err)
+	_, err = k.pdb.Get("fake-test", "11111111")
+	assert.Equal(t, k8serror.ErrNotFound, err)
 }
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegroup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/task"
+)
+
+// Canary Apply or remove the canary workloads of the new version of service group,
+// the stable service group in jsonstore is left untouched until the canary is promoted
+func (s ServiceGroupImpl) Canary(req apistructs.ServiceGroupCanaryRequest) error {
+	switch req.Action {
+	case apistructs.ServiceGroupCanaryActionApply:
+		if req.Weight <= 0 || req.Weight > 100 {
+			return errors.Errorf("invalid canary weight %d", req.Weight)
+		}
+	case apistructs.ServiceGroupCanaryActionRemove:
+	default:
+		return errors.Errorf("invalid canary action %q", req.Action)
+	}
+
+	sg, err := convertServiceGroup(req.ServiceGroupCreateV2Request, s.Clusterinfo)
+	if err != nil {
+		return errors.Errorf("failed to convert sg canary request, err: %v", err)
+	}
+
+	oldSg := apistructs.ServiceGroup{}
+	if err := s.Js.Get(context.Background(), mkServiceGroupKey(sg.Type, sg.ID), &oldSg); err != nil {
+		return fmt.Errorf("Cannot get servicegroup(%s/%s) from etcd, err: %v", sg.Type, sg.ID, err)
+	}
+	if oldSg.ProjectNamespace != "" {
+		sg.ProjectNamespace = oldSg.ProjectNamespace
+	}
+
+	sg.Labels = appendServiceTags(sg.Labels, sg.Executor)
+	sg.Labels[apistructs.ServiceGroupCanaryLabelKey] = string(req.Action)
+	sg.Labels[apistructs.ServiceGroupCanaryWeightLabelKey] = strconv.Itoa(req.Weight)
+	if _, err := s.handleServiceGroup(context.Background(), &sg, task.TaskCanary); err != nil {
+		logrus.Errorf("failed to %s canary of servicegroup %s/%s, err: %v", req.Action, sg.Type, sg.ID, err)
+		return err
+	}
+	return nil
+}
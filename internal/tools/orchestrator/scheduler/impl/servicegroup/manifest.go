@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegroup
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/executor/plugins/k8s"
+	"github.com/erda-project/erda/internal/tools/orchestrator/scheduler/impl/clusterinfo"
+	"github.com/erda-project/erda/pkg/parser/diceyml"
+)
+
+func (s ServiceGroupImpl) RenderManifests(req apistructs.ServiceGroupRenderManifestsRequest) (apistructs.ServiceGroupRenderManifestsData, error) {
+	return RenderManifests(req, s.Clusterinfo)
+}
+
+// RenderManifests renders dice.yml of the workspace as the kubernetes manifests which k8s executor would create,
+// nothing is created in the cluster
+func RenderManifests(req apistructs.ServiceGroupRenderManifestsRequest, clusterinfo clusterinfo.ClusterInfo) (apistructs.ServiceGroupRenderManifestsData, error) {
+	var data apistructs.ServiceGroupRenderManifestsData
+
+	yml, err := mergeWorkspace(req.DiceYml, req.Workspace, req.ClusterName)
+	if err != nil {
+		return data, err
+	}
+	sg, err := convertServiceGroup(apistructs.ServiceGroupCreateV2Request{
+		DiceYml:          *yml,
+		ClusterName:      req.ClusterName,
+		ID:               req.ID,
+		Type:             req.Type,
+		ProjectNamespace: req.ProjectNamespace,
+	}, clusterinfo)
+	if err != nil {
+		return data, err
+	}
+
+	info, err := clusterinfo.Info(req.ClusterName)
+	if err != nil {
+		return data, errors.Errorf("failed to get cluster info, clusterName: %s, (%v)", req.ClusterName, err)
+	}
+	infoData := make(map[string]string, len(info))
+	for k, v := range info {
+		infoData[k.String()] = v
+	}
+
+	objs, err := k8s.RenderManifests(&sg, k8s.ManifestOptions{
+		ClusterName:     req.ClusterName,
+		ClusterInfo:     infoData,
+		ScaledConfigs:   req.ScaledConfigs,
+		ErdaSecretNames: req.ErdaSecretNames,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	switch req.Format {
+	case "", apistructs.ServiceGroupRenderManifestsFormatYaml:
+		b, err := k8s.EncodeManifests(objs)
+		if err != nil {
+			return data, err
+		}
+		data.Manifests = string(b)
+	case apistructs.ServiceGroupRenderManifestsFormatHelm:
+		files, err := k8s.RenderHelmChart(req.ID, req.ChartVersion, objs)
+		if err != nil {
+			return data, err
+		}
+		data.Files = make(map[string]string, len(files))
+		for name, b := range files {
+			data.Files[name] = string(b)
+		}
+	default:
+		return data, errors.Errorf("invalid manifests format %s", req.Format)
+	}
+	return data, nil
+}
+
+// mergeWorkspace merges the environment of workspace into dice.yml,
+// and injects the envs which are set by deployment for every runtime
+func mergeWorkspace(obj diceyml.Object, workspace, clusterName string) (*diceyml.Object, error) {
+	if !apistructs.DiceWorkspace(strings.ToUpper(workspace)).Deployable() {
+		return nil, errors.Errorf("invalid workspace %s", workspace)
+	}
+	b, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	d, err := diceyml.NewDeployable(b, workspace, false)
+	if err != nil {
+		return nil, err
+	}
+	yml := d.Obj()
+	if yml.Envs == nil {
+		yml.Envs = diceyml.EnvMap{}
+	}
+	yml.Envs["DICE_WORKSPACE"] = strings.ToLower(workspace)
+	yml.Envs["DICE_CLUSTER_NAME"] = clusterName
+	return yml, nil
+}
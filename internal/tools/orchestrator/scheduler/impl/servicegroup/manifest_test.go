@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegroup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/pkg/parser/diceyml"
+)
+
+func TestMergeWorkspace(t *testing.T) {
+	obj := diceyml.Object{
+		Version: "2.0",
+		Services: diceyml.Services{
+			"web": &diceyml.Service{
+				Image:     "nginx:latest",
+				Resources: diceyml.Resources{CPU: 0.1, Mem: 128},
+				Deployments: diceyml.Deployments{
+					Replicas: 1,
+				},
+			},
+		},
+		Environments: diceyml.EnvObjects{
+			"production": &diceyml.EnvObject{
+				Services: diceyml.Services{
+					"web": &diceyml.Service{
+						Deployments: diceyml.Deployments{Replicas: 3},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := mergeWorkspace(obj, "unknown", "test")
+	assert.Error(t, err)
+
+	yml, err := mergeWorkspace(obj, "PROD", "test")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, yml.Services["web"].Deployments.Replicas)
+	assert.Equal(t, "prod", yml.Envs["DICE_WORKSPACE"])
+	assert.Equal(t, "test", yml.Envs["DICE_CLUSTER_NAME"])
+}
@@ -62,6 +62,8 @@ type ServiceGroup interface {
 	Scale(sg *apistructs.ServiceGroup) (interface{}, error)
 	InspectServiceGroupWithTimeout(namespace, name string) (*apistructs.ServiceGroup, error)
 	InspectRuntimeServicePods(namespace, name, serviceName, runtimeID string) (*apistructs.ServiceGroup, error)
+	RenderManifests(req apistructs.ServiceGroupRenderManifestsRequest) (apistructs.ServiceGroupRenderManifestsData, error)
+	Canary(req apistructs.ServiceGroupCanaryRequest) error
 }
 
 type ServiceGroupImpl struct {
@@ -350,6 +352,10 @@ func convertServiceGroup(req apistructs.ServiceGroupCreateV2Request, clusterinfo
 			Selectors:        service.Deployments.Selectors,
 			WorkLoad:         service.Deployments.Workload,
 			DeploymentLabels: service.Deployments.Labels,
+			MaxSurge:         service.Deployments.MaxSurge,
+			MaxUnavailable:   service.Deployments.MaxUnavailable,
+			MinAvailable:     service.Deployments.MinAvailable,
+			TopologySpread:   service.Deployments.TopologySpread,
 			Binds:            binds,
 			Volumes:          volumes,
 			Hosts:            service.Hosts,
@@ -470,20 +476,65 @@ func convertServiceGroup(req apistructs.ServiceGroupCreateV2Request, clusterinfo
 }
 func convertHealthcheck(hc diceyml.HealthCheck) *apistructs.NewHealthCheck {
 	nhc := apistructs.NewHealthCheck{}
-	if hc.HTTP != nil && hc.HTTP.Port != 0 && hc.HTTP.Path != "" {
-		nhc.HttpHealthCheck = &apistructs.HttpHealthCheck{
-			Port:     hc.HTTP.Port,
-			Path:     hc.HTTP.Path,
-			Duration: hc.HTTP.Duration,
+	nhc.HttpHealthCheck, nhc.ExecHealthCheck, nhc.TCPHealthCheck, nhc.GRPCHealthCheck =
+		convertHealthcheckTypes(hc.HTTP, hc.Exec, hc.TCP, hc.GRPC)
+	nhc.Readiness = convertHealthcheckProbe(hc.Readiness)
+	nhc.Liveness = convertHealthcheckProbe(hc.Liveness)
+	nhc.Startup = convertHealthcheckProbe(hc.Startup)
+	return &nhc
+}
+
+func convertHealthcheckProbe(probe *diceyml.Probe) *apistructs.HealthCheckProbe {
+	if probe == nil {
+		return nil
+	}
+	p := apistructs.HealthCheckProbe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		SuccessThreshold:    probe.SuccessThreshold,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+	p.HttpHealthCheck, p.ExecHealthCheck, p.TCPHealthCheck, p.GRPCHealthCheck =
+		convertHealthcheckTypes(probe.HTTP, probe.Exec, probe.TCP, probe.GRPC)
+	return &p
+}
+
+func convertHealthcheckTypes(http *diceyml.HTTPCheck, exec *diceyml.ExecCheck, tcp *diceyml.TCPCheck, grpc *diceyml.GRPCCheck) (
+	*apistructs.HttpHealthCheck, *apistructs.ExecHealthCheck, *apistructs.TCPHealthCheck, *apistructs.GRPCHealthCheck) {
+	var (
+		httpCheck *apistructs.HttpHealthCheck
+		execCheck *apistructs.ExecHealthCheck
+		tcpCheck  *apistructs.TCPHealthCheck
+		grpcCheck *apistructs.GRPCHealthCheck
+	)
+	if http != nil && http.Port != 0 && http.Path != "" {
+		httpCheck = &apistructs.HttpHealthCheck{
+			Port:     http.Port,
+			Path:     http.Path,
+			Duration: http.Duration,
 		}
 	}
-	if hc.Exec != nil && hc.Exec.Cmd != "" {
-		nhc.ExecHealthCheck = &apistructs.ExecHealthCheck{
-			Cmd:      hc.Exec.Cmd,
-			Duration: hc.Exec.Duration,
+	if exec != nil && exec.Cmd != "" {
+		execCheck = &apistructs.ExecHealthCheck{
+			Cmd:      exec.Cmd,
+			Duration: exec.Duration,
 		}
 	}
-	return &nhc
+	if tcp != nil && tcp.Port != 0 {
+		tcpCheck = &apistructs.TCPHealthCheck{
+			Port:     tcp.Port,
+			Duration: tcp.Duration,
+		}
+	}
+	if grpc != nil && grpc.Port != 0 {
+		grpcCheck = &apistructs.GRPCHealthCheck{
+			Port:     grpc.Port,
+			Service:  grpc.Service,
+			Duration: grpc.Duration,
+		}
+	}
+	return httpCheck, execCheck, tcpCheck, grpcCheck
 }
 
 func appendServiceTags(labels map[string]string, executor string) map[string]string {
@@ -47,6 +47,7 @@ const (
 	TaskVPAObjectApply
 	TaskVPAObjectCancel
 	TaskVPAObjectReApply
+	TaskCanary
 )
 
 var (
@@ -232,7 +233,7 @@ func (t *Task) Run(ctx context.Context) TaskResponse {
 			err: err,
 		}
 	case TaskScale, TaskKedaScaledObjectCreate, TaskKedaScaledObjectApply, TaskKedaScaledObjectCancel,
-		TaskKedaScaledObjectReApply, TaskVPAObjectApply, TaskVPAObjectCancel, TaskVPAObjectReApply:
+		TaskKedaScaledObjectReApply, TaskVPAObjectApply, TaskVPAObjectCancel, TaskVPAObjectReApply, TaskCanary:
 		r, err := executor.Scale(ctx, t.Spec)
 		return TaskResponse{
 			err:   err,
@@ -302,6 +303,8 @@ func (a *Action) String() string {
 		return "TaskScale"
 	case TaskKedaScaledObjectCreate:
 		return "TaskKedaScaledObjectCreate"
+	case TaskCanary:
+		return "TaskCanary"
 	}
 	panic("unreachable")
 }
@@ -30,6 +30,7 @@ var (
 	ErrUpdateRuntime   = err("ErrUpdateRuntime", "更新应用实例失败")
 	ErrReferRuntime    = err("ErrReferRuntime", "查询应用实例引用集群失败")
 	ErrKillPod         = err("ErrKillPod", "kill pod 失败")
+	ErrRenderManifests = err("ErrRenderManifests", "生成 kubernetes 资源清单失败")
 )
 
 var (
@@ -45,6 +46,7 @@ var (
 	ErrDeployStagesAddons   = err("ErrDeployStagesAddons", "部署addon失败")
 	ErrDeployStagesServices = err("ErrDeployStagesServices", "部署service失败")
 	ErrDeployStagesDomains  = err("ErrDeployStagesDomains", "部署domain失败")
+	ErrAbortCanary          = err("ErrAbortCanary", "中止金丝雀发布失败")
 )
 
 // deployment order errors
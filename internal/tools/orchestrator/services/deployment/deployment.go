@@ -212,6 +212,43 @@ func (d *Deployment) CancelLastDeploy(runtimeID uint64, operator string, force b
 	return fsm.doCancelDeploy(operator, force)
 }
 
+// AbortCanary 中止金丝雀发布, 金丝雀实例在部署流程的下一轮被移除, 流量全部回到稳定版本
+func (d *Deployment) AbortCanary(deploymentID uint64, operator string) error {
+	fsm := NewFSMContext(deploymentID, d.db, d.evMgr, d.bdl, d.addon, d.migration, d.encrypt, d.resource, d.releaseSvc, d.serviceGroupImpl, d.scheduler, d.envConfig, d.clusterSvc)
+	if err := fsm.Load(); err != nil {
+		return apierrors.ErrAbortCanary.InternalError(err)
+	}
+	perm, err := d.bdl.CheckPermission(&apistructs.PermissionCheckRequest{
+		UserID:   operator,
+		Scope:    apistructs.AppScope,
+		ScopeID:  fsm.Runtime.ApplicationID,
+		Resource: "runtime-" + strutil.ToLower(fsm.Runtime.Workspace),
+		Action:   apistructs.OperateAction,
+	})
+	if err != nil {
+		return apierrors.ErrAbortCanary.InternalError(err)
+	}
+	if !perm.Access {
+		return apierrors.ErrAbortCanary.AccessDenied()
+	}
+	if fsm.Deployment.Status != apistructs.DeploymentStatusDeploying ||
+		fsm.Deployment.Phase != apistructs.DeploymentPhaseCanary || fsm.Deployment.Extra.Canary == nil {
+		return apierrors.ErrAbortCanary.InvalidState(fmt.Sprintf("deployment(%d) is not in canary", deploymentID))
+	}
+	if fsm.Deployment.Extra.Canary.AbortedBy != "" {
+		return nil
+	}
+	// 只更新 extra 中的 abortedBy, 避免覆盖部署流程并发写入的进度
+	ok, err := d.db.AbortDeploymentCanary(deploymentID, operator)
+	if err != nil {
+		return apierrors.ErrAbortCanary.InternalError(err)
+	}
+	if !ok {
+		return apierrors.ErrAbortCanary.InvalidState(fmt.Sprintf("deployment(%d) is not in canary", deploymentID))
+	}
+	return nil
+}
+
 // ListOrg 查询部署记录(列出orgid下所有有权限的deployments)
 func (d *Deployment) ListOrg(ctx context.Context, userID user.ID, orgID uint64, needFilterProjectRole bool,
 	needApproval *bool, approvedBy *user.ID, operateUsers []string, approved *bool,
@@ -461,6 +498,7 @@ func (d *Deployment) GetStatus(deploymentID uint64) (*apistructs.DeploymentStatu
 		FailCause:    deployment.FailCause,
 		ModuleErrMsg: statusMap,
 		Runtime:      rt,
+		Canary:       deployment.Extra.Canary,
 	}, nil
 }
 
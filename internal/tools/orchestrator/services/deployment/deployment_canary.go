@@ -0,0 +1,346 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/dbclient"
+)
+
+const (
+	defaultCanaryStepInterval = 300 * time.Second
+	defaultCanaryMinRequests  = 100
+	// canaryMaxObserveDuration a step which can't be evaluated after observing so long is rolled back,
+	// e.g. metrics are unavailable or the canary receives too little traffic
+	canaryMaxObserveDuration = time.Hour
+
+	// canaryMetricStatement requests, errors and elapsed(ns) of the http service during the canary step,
+	// the canary pods report metrics as apistructs.CanaryServiceName, so that only the traffic of canary is counted
+	canaryMetricStatement = `SELECT sum(elapsed_count::field), sum(errors_sum::field), sum(elapsed_sum::field) ` +
+		`FROM application_http_service WHERE target_runtime_id::tag='%d' AND target_service_name::tag='%s'`
+)
+
+// canaryMetrics metrics of services during a canary step
+type canaryMetrics struct {
+	Requests float64
+	Errors   float64
+	// Elapsed total elapsed of requests in nanoseconds
+	Elapsed float64
+}
+
+// isCanary the deployment is rolled out progressively only when there are stable workloads to shift traffic from
+func (fsm *DeployFSMContext) isCanary() bool {
+	return fsm.Deployment.Extra.Canary != nil && fsm.Runtime.Deployed
+}
+
+// startCanary deploy the canary workloads with the first weight
+func (fsm *DeployFSMContext) startCanary() error {
+	canary := fsm.Deployment.Extra.Canary
+	weight := canary.Strategy.Weights[0]
+	if err := fsm.applyCanary(weight); err != nil {
+		return err
+	}
+	canary.Steps = append(canary.Steps, apistructs.DeploymentCanaryStep{
+		Weight:    weight,
+		Status:    apistructs.DeploymentCanaryStepRunning,
+		StartedAt: time.Now(),
+	})
+	fsm.pushLog(fmt.Sprintf("canary started, %d%% traffic is shifted to the new version", weight))
+	return nil
+}
+
+func (fsm *DeployFSMContext) continuePhaseCanary() error {
+	if fsm.Deployment.Status != apistructs.DeploymentStatusDeploying ||
+		fsm.Deployment.Phase != apistructs.DeploymentPhaseCanary {
+		return nil
+	}
+	canary := fsm.Deployment.Extra.Canary
+	if canary == nil || canary.CurrentStep() == nil {
+		return fsm.failDeploy(errors.New("canary progress not found"))
+	}
+	step := canary.CurrentStep()
+	if canary.AbortedBy != "" {
+		return fsm.rollbackCanary(apistructs.DeploymentCanaryStepAborted, fmt.Sprintf("canary aborted by %s", canary.AbortedBy))
+	}
+	if time.Since(step.StartedAt) < canaryStepInterval(canary.Strategy) {
+		return nil
+	}
+
+	fsm.pushLog(fmt.Sprintf(" * analysing canary of weight %d%%...", step.Weight))
+	metrics, err := fsm.queryCanaryMetrics(step.StartedAt, time.Now())
+	if err != nil {
+		// metrics may be delayed, analyse again next round
+		step.Message = fmt.Sprintf("failed to query canary metrics, (%v)", err)
+		fsm.pushLog(step.Message)
+		return fsm.keepObservingCanary(step)
+	}
+	step.Requests, step.ErrorRate, step.Latency = metrics.Requests, metrics.errorRate(), metrics.latency()
+	if minRequests := canaryMinRequests(canary.Strategy); step.Requests < float64(minRequests) {
+		// not enough traffic to judge the canary, keep observing
+		step.Message = fmt.Sprintf("canary of weight %d%% received %.0f requests, less than %d, keep observing",
+			step.Weight, step.Requests, minRequests)
+		fsm.pushLog(step.Message)
+		return fsm.keepObservingCanary(step)
+	}
+	step.Message = ""
+	// the canary may be aborted during the analysis
+	if err := fsm.reloadCanaryAbort(); err != nil {
+		fsm.pushLog(fmt.Sprintf("failed to reload canary progress, (%v)", err))
+		return nil
+	}
+	if canary.AbortedBy != "" {
+		return fsm.rollbackCanary(apistructs.DeploymentCanaryStepAborted, fmt.Sprintf("canary aborted by %s", canary.AbortedBy))
+	}
+	if reason, ok := evaluateCanaryStep(canary.Strategy, step); !ok {
+		return fsm.rollbackCanary(apistructs.DeploymentCanaryStepFailed, reason)
+	}
+	now := time.Now()
+	step.Status = apistructs.DeploymentCanaryStepSucceeded
+	step.FinishedAt = &now
+	fsm.pushLog(fmt.Sprintf("canary of weight %d%% succeeded, requests: %.0f, error rate: %.2f%%, latency: %.2fms",
+		step.Weight, step.Requests, step.ErrorRate, step.Latency))
+
+	if weight, ok := nextCanaryWeight(canary); ok {
+		if err := fsm.applyCanary(weight); err != nil {
+			return fsm.rollbackCanary(apistructs.DeploymentCanaryStepFailed, err.Error())
+		}
+		canary.Steps = append(canary.Steps, apistructs.DeploymentCanaryStep{
+			Weight:    weight,
+			Status:    apistructs.DeploymentCanaryStepRunning,
+			StartedAt: time.Now(),
+		})
+		fsm.pushLog(fmt.Sprintf("%d%% traffic is shifted to the new version", weight))
+		return fsm.db.UpdateDeployment(fsm.Deployment)
+	}
+
+	// all steps passed, promote the new version to stable workloads,
+	// the canary workloads are removed after the stable ones are ready
+	canary.Promoted = true
+	fsm.pushLog(`canary promoted, service deploying...`)
+	if err := fsm.deployService(); err != nil {
+		return fsm.failDeploy(err)
+	}
+	return fsm.pushOnPhase(apistructs.DeploymentPhaseService)
+}
+
+// keepObservingCanary save the progress of step which can't be evaluated yet, so that the deployment is not
+// considered as timeout, the step is rolled back if it can't be evaluated in canaryMaxObserveDuration
+func (fsm *DeployFSMContext) keepObservingCanary(step *apistructs.DeploymentCanaryStep) error {
+	canary := fsm.Deployment.Extra.Canary
+	if time.Since(step.StartedAt) > canaryStepInterval(canary.Strategy)+canaryMaxObserveDuration {
+		return fsm.rollbackCanary(apistructs.DeploymentCanaryStepFailed,
+			fmt.Sprintf("canary of weight %d%% can not be evaluated in time, (%s)", step.Weight, step.Message))
+	}
+	// do not overwrite the abort written concurrently
+	if err := fsm.reloadCanaryAbort(); err != nil {
+		fsm.pushLog(fmt.Sprintf("failed to reload canary progress, (%v)", err))
+		return nil
+	}
+	if canary.AbortedBy != "" {
+		return fsm.rollbackCanary(apistructs.DeploymentCanaryStepAborted, fmt.Sprintf("canary aborted by %s", canary.AbortedBy))
+	}
+	return fsm.db.UpdateDeployment(fsm.Deployment)
+}
+
+// reloadCanaryAbort reload abortedBy of canary from db, which is written by AbortCanary apart from deploy fsm
+func (fsm *DeployFSMContext) reloadCanaryAbort() error {
+	deployment, err := fsm.db.GetDeployment(fsm.deploymentID)
+	if err != nil {
+		return err
+	}
+	if deployment.Extra.Canary != nil && deployment.Extra.Canary.AbortedBy != "" {
+		fsm.Deployment.Extra.Canary.AbortedBy = deployment.Extra.Canary.AbortedBy
+	}
+	return nil
+}
+
+// rollbackCanary fail the deployment, the canary workloads are removed by failDeploy,
+// all traffic goes back to the stable ones
+func (fsm *DeployFSMContext) rollbackCanary(status apistructs.DeploymentCanaryStepStatus, reason string) error {
+	step := fsm.Deployment.Extra.Canary.CurrentStep()
+	now := time.Now()
+	step.Status = status
+	step.Message = reason
+	step.FinishedAt = &now
+	fsm.pushLog(fmt.Sprintf("rolling back canary, (%s)", reason))
+	return fsm.failDeploy(errors.New(reason))
+}
+
+func (fsm *DeployFSMContext) applyCanary(weight int) error {
+	group, _, _, err := fsm.generateServiceGroup()
+	if err != nil {
+		return err
+	}
+	return fsm.serviceGroupImpl.Canary(apistructs.ServiceGroupCanaryRequest{
+		ServiceGroupCreateV2Request: group,
+		Action:                      apistructs.ServiceGroupCanaryActionApply,
+		Weight:                      weight,
+	})
+}
+
+func (fsm *DeployFSMContext) removeCanary() error {
+	group, _, _, err := fsm.generateServiceGroup()
+	if err != nil {
+		return err
+	}
+	return fsm.serviceGroupImpl.Canary(apistructs.ServiceGroupCanaryRequest{
+		ServiceGroupCreateV2Request: group,
+		Action:                      apistructs.ServiceGroupCanaryActionRemove,
+	})
+}
+
+// shouldRemoveCanaryOnFail the canary workloads may still serve traffic when the deployment fails
+// in canary phase or after promotion
+func shouldRemoveCanaryOnFail(deployment *dbclient.Deployment) bool {
+	canary := deployment.Extra.Canary
+	return canary != nil && (deployment.Phase == apistructs.DeploymentPhaseCanary || canary.Promoted)
+}
+
+// removeCanaryOnFail remove the canary workloads of failed deployment, the weighted ingress of canary
+// keeps shifting production traffic to the new version otherwise
+func (fsm *DeployFSMContext) removeCanaryOnFail() {
+	if !shouldRemoveCanaryOnFail(fsm.Deployment) {
+		return
+	}
+	if err := fsm.removeCanary(); err != nil {
+		logrus.Errorf("failed to remove canary of deployment %d, err: %v", fsm.deploymentID, err)
+		fsm.pushLog(fmt.Sprintf("failed to remove canary, (%v)", err))
+		return
+	}
+	fsm.pushLog("canary removed")
+}
+
+// cleanCanary remove the canary workloads once the promoted stable ones are ready
+func (fsm *DeployFSMContext) cleanCanary() {
+	if canary := fsm.Deployment.Extra.Canary; canary == nil || !canary.Promoted {
+		return
+	}
+	if err := fsm.removeCanary(); err != nil {
+		// stable workloads are ready, do not fail the deployment
+		logrus.Errorf("failed to remove canary of deployment %d, err: %v", fsm.deploymentID, err)
+		fsm.pushLog(fmt.Sprintf("failed to remove canary, (%v)", err))
+	}
+}
+
+// queryCanaryMetrics sum the metrics of canary of all http services in runtime during [start, end)
+func (fsm *DeployFSMContext) queryCanaryMetrics(start, end time.Time) (canaryMetrics, error) {
+	var metrics canaryMetrics
+	params := map[string]string{
+		"start": strconv.FormatInt(start.UnixNano()/int64(time.Millisecond), 10),
+		"end":   strconv.FormatInt(end.UnixNano()/int64(time.Millisecond), 10),
+	}
+	for name := range fsm.Spec.Services {
+		rows, err := fsm.bdl.QueryMetricDict(fsm.Runtime.OrgID, canaryMetricQuery(fsm.Runtime.ID, name), params)
+		if err != nil {
+			return metrics, err
+		}
+		metrics.add(parseCanaryMetrics(rows))
+	}
+	return metrics, nil
+}
+
+// canaryMetricQuery statement to query the metrics of canary of service
+func canaryMetricQuery(runtimeID uint64, service string) string {
+	return fmt.Sprintf(canaryMetricStatement, runtimeID, apistructs.CanaryServiceName(service))
+}
+
+func (m *canaryMetrics) add(o canaryMetrics) {
+	m.Requests += o.Requests
+	m.Errors += o.Errors
+	m.Elapsed += o.Elapsed
+}
+
+// errorRate in percent
+func (m canaryMetrics) errorRate() float64 {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.Errors / m.Requests * 100
+}
+
+// latency average in milliseconds
+func (m canaryMetrics) latency() float64 {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.Elapsed / m.Requests / float64(time.Millisecond)
+}
+
+func parseCanaryMetrics(rows []map[string]interface{}) canaryMetrics {
+	var metrics canaryMetrics
+	for _, row := range rows {
+		metrics.Requests += toFloat64(row["sum(elapsed_count::field)"])
+		metrics.Errors += toFloat64(row["sum(errors_sum::field)"])
+		metrics.Elapsed += toFloat64(row["sum(elapsed_sum::field)"])
+	}
+	return metrics
+}
+
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+func canaryStepInterval(strategy apistructs.DeploymentCanaryStrategy) time.Duration {
+	if strategy.StepInterval <= 0 {
+		return defaultCanaryStepInterval
+	}
+	return time.Duration(strategy.StepInterval) * time.Second
+}
+
+func canaryMinRequests(strategy apistructs.DeploymentCanaryStrategy) int {
+	if strategy.MinRequests <= 0 {
+		return defaultCanaryMinRequests
+	}
+	return strategy.MinRequests
+}
+
+// evaluateCanaryStep check the metrics of step against the criteria of strategy,
+// the step is evaluated only after canaryMinRequests requests are received
+func evaluateCanaryStep(strategy apistructs.DeploymentCanaryStrategy, step *apistructs.DeploymentCanaryStep) (string, bool) {
+	if strategy.MaxErrorRate > 0 && step.ErrorRate > strategy.MaxErrorRate {
+		return fmt.Sprintf("canary error rate %.2f%% exceeds %.2f%%", step.ErrorRate, strategy.MaxErrorRate), false
+	}
+	if strategy.MaxLatency > 0 && step.Latency > strategy.MaxLatency {
+		return fmt.Sprintf("canary latency %.2fms exceeds %.2fms", step.Latency, strategy.MaxLatency), false
+	}
+	return "", true
+}
+
+// nextCanaryWeight returns the weight of next step, false if all steps are done
+func nextCanaryWeight(canary *apistructs.DeploymentCanaryProgress) (int, bool) {
+	if len(canary.Steps) >= len(canary.Strategy.Weights) {
+		return 0, false
+	}
+	return canary.Strategy.Weights[len(canary.Steps)], true
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/orchestrator/dbclient"
+)
+
+func TestEvaluateCanaryStep(t *testing.T) {
+	strategy := apistructs.DeploymentCanaryStrategy{Weights: []int{10}, MaxErrorRate: 1, MaxLatency: 200}
+
+	_, ok := evaluateCanaryStep(strategy, &apistructs.DeploymentCanaryStep{Requests: 100, ErrorRate: 0.5, Latency: 100})
+	assert.True(t, ok)
+
+	reason, ok := evaluateCanaryStep(strategy, &apistructs.DeploymentCanaryStep{Requests: 100, ErrorRate: 2})
+	assert.False(t, ok)
+	assert.Contains(t, reason, "error rate")
+
+	reason, ok = evaluateCanaryStep(strategy, &apistructs.DeploymentCanaryStep{Requests: 100, Latency: 300})
+	assert.False(t, ok)
+	assert.Contains(t, reason, "latency")
+
+	_, ok = evaluateCanaryStep(apistructs.DeploymentCanaryStrategy{}, &apistructs.DeploymentCanaryStep{Requests: 100, ErrorRate: 90, Latency: 3000})
+	assert.True(t, ok, "criteria not set")
+}
+
+func TestNextCanaryWeight(t *testing.T) {
+	canary := &apistructs.DeploymentCanaryProgress{
+		Strategy: apistructs.DeploymentCanaryStrategy{Weights: []int{10, 50}},
+		Steps:    []apistructs.DeploymentCanaryStep{{Weight: 10}},
+	}
+	weight, ok := nextCanaryWeight(canary)
+	assert.True(t, ok)
+	assert.Equal(t, 50, weight)
+
+	canary.Steps = append(canary.Steps, apistructs.DeploymentCanaryStep{Weight: 50})
+	_, ok = nextCanaryWeight(canary)
+	assert.False(t, ok)
+}
+
+func TestCanaryStepInterval(t *testing.T) {
+	assert.Equal(t, defaultCanaryStepInterval, canaryStepInterval(apistructs.DeploymentCanaryStrategy{}))
+	assert.Equal(t, time.Minute, canaryStepInterval(apistructs.DeploymentCanaryStrategy{StepInterval: 60}))
+}
+
+func TestCanaryMinRequests(t *testing.T) {
+	assert.Equal(t, defaultCanaryMinRequests, canaryMinRequests(apistructs.DeploymentCanaryStrategy{}))
+	assert.Equal(t, 10, canaryMinRequests(apistructs.DeploymentCanaryStrategy{MinRequests: 10}))
+}
+
+func TestCanaryMetricQuery(t *testing.T) {
+	assert.Equal(t, "SELECT sum(elapsed_count::field), sum(errors_sum::field), sum(elapsed_sum::field) "+
+		"FROM application_http_service WHERE target_runtime_id::tag='1' AND target_service_name::tag='web-canary'",
+		canaryMetricQuery(1, "web"))
+}
+
+func TestParseCanaryMetrics(t *testing.T) {
+	metrics := parseCanaryMetrics([]map[string]interface{}{
+		{
+			"sum(elapsed_count::field)": float64(200),
+			"sum(errors_sum::field)":    float64(4),
+			"sum(elapsed_sum::field)":   "20000000000",
+		},
+	})
+	assert.Equal(t, float64(200), metrics.Requests)
+	assert.Equal(t, float64(2), metrics.errorRate())
+	assert.Equal(t, float64(100), metrics.latency())
+
+	assert.Equal(t, float64(0), canaryMetrics{}.errorRate())
+	assert.Equal(t, float64(0), canaryMetrics{}.latency())
+}
+
+func TestShouldRemoveCanaryOnFail(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment *dbclient.Deployment
+		want       bool
+	}{
+		{"not canary", &dbclient.Deployment{Phase: apistructs.DeploymentPhaseService}, false},
+		{"canary phase", &dbclient.Deployment{Phase: apistructs.DeploymentPhaseCanary,
+			Extra: dbclient.DeploymentExtra{Canary: &apistructs.DeploymentCanaryProgress{}}}, true},
+		{"promoted", &dbclient.Deployment{Phase: apistructs.DeploymentPhaseService,
+			Extra: dbclient.DeploymentExtra{Canary: &apistructs.DeploymentCanaryProgress{Promoted: true}}}, true},
+		{"canary not started", &dbclient.Deployment{Phase: apistructs.DeploymentPhaseAddon,
+			Extra: dbclient.DeploymentExtra{Canary: &apistructs.DeploymentCanaryProgress{}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, shouldRemoveCanaryOnFail(tt.deployment))
+		})
+	}
+}
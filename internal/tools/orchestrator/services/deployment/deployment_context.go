@@ -268,6 +268,8 @@ func (fsm *DeployFSMContext) continueDeploying() error {
 		return fsm.continuePhaseAddon()
 	case apistructs.DeploymentPhaseScript:
 		return fsm.continuePhasePreService()
+	case apistructs.DeploymentPhaseCanary:
+		return fsm.continuePhaseCanary()
 	case apistructs.DeploymentPhaseService:
 		return fsm.continuePhaseService()
 	case apistructs.DeploymentPhaseRegister:
@@ -427,6 +429,19 @@ func (fsm *DeployFSMContext) continuePhasePreService() error {
 		}
 	}
 
+	// roll out progressively, the stable workloads are updated after canary promoted
+	if fsm.isCanary() {
+		fsm.pushLog(`canary deploying...`)
+		if err := fsm.startCanary(); err != nil {
+			return fsm.failDeploy(err)
+		}
+		return fsm.pushOnPhase(apistructs.DeploymentPhaseCanary)
+	}
+	if fsm.Deployment.Extra.Canary != nil {
+		fsm.pushLog(`no stable service to roll out canary from, deploy directly`)
+		fsm.Deployment.Extra.Canary = nil
+	}
+
 	// do start deploying
 	fsm.pushLog(`service deploying...`)
 	if err := fsm.deployService(); err != nil {
@@ -674,6 +689,7 @@ func (fsm *DeployFSMContext) continuePhaseRegister() error {
 		fsm.Deployment.Phase != apistructs.DeploymentPhaseRegister {
 		return nil
 	}
+	fsm.cleanCanary()
 	// pushOn Phase
 	if err := fsm.pushOnPhase(apistructs.DeploymentPhaseCompleted); err != nil {
 		return err
@@ -784,6 +800,7 @@ func (fsm *DeployFSMContext) failDeploy(oriErr error) error {
 	app := fsm.App
 	fsm.pushLog(fmt.Sprintf("deployment is fail, status: %v, phase: %v, (%v)",
 		deployment.Status, deployment.Phase, oriErr))
+	fsm.removeCanaryOnFail()
 	deployment.FailCause = oriErr.Error()
 	deployment.Status = apistructs.DeploymentStatusFailed
 	now := time.Now()
@@ -892,19 +909,6 @@ func (fsm *DeployFSMContext) deployService() error {
 		return err
 	}
 
-	// prepare env context
-
-	projectAddons, err := fsm.db.GetAliveProjectAddons(strconv.FormatUint(fsm.Runtime.ProjectID, 10), fsm.Runtime.ClusterName, fsm.Runtime.Workspace)
-	if err != nil {
-		return err
-	}
-
-	projectAddonTenants, err := fsm.db.ListAddonInstanceTenantByProjectIDs([]uint64{fsm.Runtime.ProjectID}, fsm.Runtime.Workspace)
-	if err != nil {
-		return err
-	}
-
-	projectECI := utils.IsProjectECIEnable(fsm.bdl, fsm.Runtime.ProjectID, fsm.Runtime.Workspace, fsm.Runtime.OrgID, fsm.Runtime.Creator)
 	// make sure runtime must have scheduleName
 	if fsm.Runtime.ScheduleName.Name == "" {
 		// if no scheduleName, we set it
@@ -921,15 +925,10 @@ func (fsm *DeployFSMContext) deployService() error {
 	}
 
 	// generate request
-	group := apistructs.ServiceGroupCreateV2Request{}
-	usedAddonInsMap, usedAddonTenantMap, err := fsm.generateDeployServiceRequest(&group, *projectAddons, projectAddonTenants, projectECI)
+	group, usedAddonInsMap, usedAddonTenantMap, err := fsm.generateServiceGroup()
 	if err != nil {
 		return err
 	}
-	if projectECI {
-		// TODO: vendor need get by cluster
-		utils.AddECIConfigToServiceGroupCreateV2Request(&group, apistructs.ECIVendorAlibaba)
-	}
 
 	// precheck，检查标签匹配，如果没有机器能匹配上，走下去也是pending的
 	if len(group.DiceYml.Services) > 0 {
@@ -1039,6 +1038,34 @@ func (fsm *DeployFSMContext) deployService() error {
 	return nil
 }
 
+// generateServiceGroup generate the service group request of runtime with project addons
+func (fsm *DeployFSMContext) generateServiceGroup() (apistructs.ServiceGroupCreateV2Request,
+	map[string]dbclient.AddonInstanceRouting, map[string]dbclient.AddonInstanceTenant, error) {
+	group := apistructs.ServiceGroupCreateV2Request{}
+
+	// prepare env context
+	projectAddons, err := fsm.db.GetAliveProjectAddons(strconv.FormatUint(fsm.Runtime.ProjectID, 10), fsm.Runtime.ClusterName, fsm.Runtime.Workspace)
+	if err != nil {
+		return group, nil, nil, err
+	}
+
+	projectAddonTenants, err := fsm.db.ListAddonInstanceTenantByProjectIDs([]uint64{fsm.Runtime.ProjectID}, fsm.Runtime.Workspace)
+	if err != nil {
+		return group, nil, nil, err
+	}
+
+	projectECI := utils.IsProjectECIEnable(fsm.bdl, fsm.Runtime.ProjectID, fsm.Runtime.Workspace, fsm.Runtime.OrgID, fsm.Runtime.Creator)
+	usedAddonInsMap, usedAddonTenantMap, err := fsm.generateDeployServiceRequest(&group, *projectAddons, projectAddonTenants, projectECI)
+	if err != nil {
+		return group, nil, nil, err
+	}
+	if projectECI {
+		// TODO: vendor need get by cluster
+		utils.AddECIConfigToServiceGroupCreateV2Request(&group, apistructs.ECIVendorAlibaba)
+	}
+	return group, usedAddonInsMap, usedAddonTenantMap, nil
+}
+
 func (fsm *DeployFSMContext) UpdateServiceGroupWithLoop(group apistructs.ServiceGroupCreateV2Request) error {
 	if err := loop.New(loop.WithInterval(time.Second), loop.WithMaxTimes(3)).Do(func() (bool, error) {
 		if _, err := fsm.serviceGroupImpl.Update(apistructs.ServiceGroupUpdateV2Request(group)); err != nil {
@@ -1886,6 +1913,11 @@ func (fsm *DeployFSMContext) doCancelDeploy(operator string, force bool) error {
 		}
 	case apistructs.DeploymentStatusInit, apistructs.DeploymentStatusWaiting, apistructs.DeploymentStatusDeploying:
 		// normal cancel
+		if fsm.Deployment.Phase == apistructs.DeploymentPhaseCanary {
+			if err := fsm.removeCanary(); err != nil {
+				return errors.Wrapf(err, "failed to remove canary, operator: %v", operator)
+			}
+		}
 		fsm.Deployment.Extra.ForceCanceled = true
 		fsm.pushOnCanceled()
 	case apistructs.DeploymentStatusCanceling:
@@ -74,6 +74,12 @@ func (d *Deployment) DeployStageServices(deploymentID uint64) (*apistructs.Deplo
 				break
 			}
 			fallthrough
+		case apistructs.DeploymentPhaseCanary:
+			err = fsm.continuePhaseCanary()
+			if err != nil {
+				break
+			}
+			fallthrough
 		case apistructs.DeploymentPhaseService:
 			err = fsm.continuePhaseService()
 			if err != nil {
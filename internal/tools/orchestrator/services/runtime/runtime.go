@@ -409,6 +409,7 @@ func (r *Runtime) Create(operator user.ID, req *apistructs.RuntimeCreateRequest)
 		SkipPushByOrch:    req.SkipPushByOrch,
 		Param:             req.Param,
 		DeploymentOrderId: req.DeploymentOrderId,
+		Canary:            req.Canary,
 	}
 
 	return r.doDeployRuntime(&deployContext)
@@ -719,6 +720,9 @@ func (r *Runtime) doDeployRuntime(ctx *DeployContext) (*apistructs.DeploymentCre
 		Param:             ctx.Param,
 		DeploymentOrderId: ctx.DeploymentOrderId,
 	}
+	if ctx.Canary != nil {
+		deployment.Extra.Canary = &apistructs.DeploymentCanaryProgress{Strategy: *ctx.Canary}
+	}
 	if err := r.db.CreateDeployment(&deployment); err != nil {
 		return nil, apierrors.ErrDeployRuntime.InternalError(err)
 	}
@@ -2002,6 +2006,11 @@ func checkRuntimeCreateReq(req *apistructs.RuntimeCreateRequest) error {
 			return errors.New("extra.applicationId is not specified, for pipeline")
 		}
 	}
+	if req.Canary != nil {
+		if err := req.Canary.Validate(); err != nil {
+			return err
+		}
+	}
 	if req.Source == apistructs.RUNTIMEADDON {
 		if len(req.Extra.InstanceID) == 0 {
 			return errors.New("extra.instanceId is not specified, for runtimeaddon")
@@ -45,4 +45,7 @@ type DeployContext struct {
 	// deployment order
 	DeploymentOrderId string
 	Param             string
+
+	// canary rollout strategy
+	Canary *apistructs.DeploymentCanaryStrategy
 }
@@ -45,6 +45,10 @@ func (agent *Agent) Callback() {
 	cb := &Callback{}
 	defer func() {
 		cb.Errors = append(cb.Errors, agent.MergeErrors()...)
+		// report exit code for retry policy matching
+		if agent.ExitCode != 0 {
+			cb.AppendMetadataFields([]*metadata.MetadataField{{Name: apistructs.ActionCallbackExitCode, Value: strconv.Itoa(agent.ExitCode)}})
+		}
 		if err := agent.callbackToPipelinePlatform(cb); err != nil {
 			for _, err := range cb.Errors {
 				logrus.Println(err.Msg)
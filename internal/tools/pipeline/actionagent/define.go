@@ -62,6 +62,8 @@ type Agent struct {
 
 	StdErrRegexpList   []*regexp.Regexp
 	MaxCacheFileSizeMB datasize.ByteSize
+	// keyedCaches store rendered keyed caches when restore, key: storage name
+	keyedCaches map[string]*keyedCache
 
 	CallbackReporter
 
@@ -0,0 +1,186 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actionagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/actionagent/agenttool"
+	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/pvolumes"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/actioncache"
+	"github.com/erda-project/erda/pkg/metadata"
+)
+
+// keyedCache is the action cache stored by rendered key, see pvolumes.TaskCacheKey
+type keyedCache struct {
+	cacheDir    string
+	cachePath   string
+	key         string
+	restoreKeys []string
+	maxSize     datasize.ByteSize
+	ttl         time.Duration
+	// exactHit means key exactly hit when restore, store can be skipped
+	exactHit bool
+}
+
+func (agent *Agent) parseKeyedCache(storage metadata.MetadataField) (*keyedCache, error) {
+	labels := storage.Labels
+	key, err := actioncache.RenderKey(labels[pvolumes.TaskCacheKey], agent.EasyUse.ContainerContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render cache key: %v", err)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("cache key %q is rendered to empty", labels[pvolumes.TaskCacheKey])
+	}
+	c := &keyedCache{
+		cacheDir:  storage.Value,
+		cachePath: labels[pvolumes.TaskCachePath],
+		key:       key,
+		maxSize:   agent.MaxCacheFileSizeMB,
+	}
+	if s := labels[pvolumes.TaskCacheRestoreKeys]; s != "" {
+		var restoreKeys []string
+		if err := json.Unmarshal([]byte(s), &restoreKeys); err != nil {
+			return nil, fmt.Errorf("invalid restore keys: %v", err)
+		}
+		for _, restoreKey := range restoreKeys {
+			rendered, err := actioncache.RenderKey(restoreKey, agent.EasyUse.ContainerContext)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render restore key: %v", err)
+			}
+			c.restoreKeys = append(c.restoreKeys, rendered)
+		}
+	}
+	if s := labels[pvolumes.TaskCacheMaxSizeMB]; s != "" {
+		maxSizeMB, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max size: %v", err)
+		}
+		if maxSize := datasize.ByteSize(maxSizeMB) * datasize.MB; maxSize > 0 && maxSize < c.maxSize {
+			c.maxSize = maxSize
+		}
+	}
+	if s := labels[pvolumes.TaskCacheTTL]; s != "" {
+		ttl, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl: %v", err)
+		}
+		c.ttl = ttl
+	}
+	return c, nil
+}
+
+func (agent *Agent) restoreKeyedCache(in metadata.MetadataField) {
+	c, err := agent.parseKeyedCache(in)
+	if err != nil {
+		logrus.Printf("skip restore action cache: %s, err: %v", in.Labels[pvolumes.TaskCachePath], err)
+		return
+	}
+	if agent.keyedCaches == nil {
+		agent.keyedCaches = make(map[string]*keyedCache)
+	}
+	agent.keyedCaches[in.Name] = c
+
+	now := time.Now()
+	entry, exact, err := actioncache.Lookup(c.cacheDir, c.key, c.restoreKeys, now)
+	if err != nil {
+		logrus.Printf("failed to lookup action cache: %s, key: %s, err: %v", c.cachePath, c.key, err)
+		return
+	}
+	if entry == nil {
+		logrus.Printf("not get action cache: %s, key: %s", c.cachePath, c.key)
+		return
+	}
+	if err := agent.restoreCache(actioncache.TarPath(c.cacheDir, entry.Key), c.cachePath); err != nil {
+		logrus.Printf("failed to untar action cache: %s, key: %s, err: %v", c.cachePath, entry.Key, err)
+		return
+	}
+	c.exactHit = exact
+	if err := actioncache.Touch(c.cacheDir, *entry, now); err != nil {
+		logrus.Debugf("failed to touch action cache key: %s, err: %v", entry.Key, err)
+	}
+	logrus.Printf("get action cache: %s success, key: %s", c.cachePath, entry.Key)
+}
+
+func (agent *Agent) storeKeyedCache(out metadata.MetadataField) {
+	c, ok := agent.keyedCaches[out.Name]
+	if !ok {
+		var err error
+		if c, err = agent.parseKeyedCache(out); err != nil {
+			logrus.Printf("skip upload action cache: %s, err: %v", out.Labels[pvolumes.TaskCachePath], err)
+			return
+		}
+	}
+	// cache entry is immutable, key hit means nothing changed
+	if c.exactHit {
+		logrus.Printf("action cache: %s hit key: %s, skip upload", c.cachePath, c.key)
+		return
+	}
+	if size, err := agenttool.GetDiskSize(c.cachePath); err == nil && size.Bytes() > c.maxSize.Bytes() {
+		logrus.Printf("skip upload action cache: %s, size: %d bytes exceed limit size: %d bytes",
+			c.cachePath, size.Bytes(), c.maxSize.Bytes())
+		return
+	}
+	if err := os.MkdirAll(actioncache.EntriesDir(c.cacheDir), 0755); err != nil {
+		logrus.Printf("failed to create action cache dir: %s, err: %v", c.cacheDir, err)
+		return
+	}
+	tarFile := actioncache.TarPath(c.cacheDir, c.key)
+	if err := storeKeyedCacheTar(tarFile, c.cachePath); err != nil {
+		logrus.Printf("failed to tar cache path: %s to file: %s, err: %v", c.cachePath, tarFile, err)
+		return
+	}
+	now := time.Now()
+	entry := actioncache.Entry{
+		Key:        c.key,
+		TTLSeconds: int64(c.ttl.Seconds()),
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	if fi, err := os.Stat(tarFile); err == nil {
+		entry.SizeBytes = fi.Size()
+	}
+	if err := actioncache.WriteEntry(c.cacheDir, entry); err != nil {
+		logrus.Printf("failed to write action cache meta, key: %s, err: %v", c.key, err)
+		return
+	}
+	logrus.Printf("upload action cache %s success, key: %s", c.cachePath, c.key)
+}
+
+// storeKeyedCacheTar 先写入同目录下的临时文件，完成后再 rename 到最终位置，
+// 避免并发 restore 或 agent 中途退出时留下不完整的 tar 被后续任务恢复
+func storeKeyedCacheTar(tarFile, cachePath string) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(tarFile), filepath.Base(tarFile)+"-*"+actioncache.TmpSuffix)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := agenttool.Tar(tmpFile.Name(), cachePath); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), tarFile)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actionagent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/pvolumes"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/actioncache"
+	"github.com/erda-project/erda/pkg/metadata"
+)
+
+func TestStoreAndRestoreKeyedCache(t *testing.T) {
+	root, err := ioutil.TempDir("", "keyed-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	contextDir := filepath.Join(root, "context")
+	cacheDir := filepath.Join(root, "caches")
+	cachePath := filepath.Join(root, "gomod")
+	assert.NoError(t, os.MkdirAll(filepath.Join(contextDir, "repo"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(contextDir, "repo", "go.sum"), []byte("sum"), 0644))
+	assert.NoError(t, os.MkdirAll(cachePath, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cachePath, "mod"), []byte("mod"), 0644))
+
+	storage := metadata.MetadataField{
+		Name:  "action_cache_1",
+		Value: cacheDir,
+		Labels: map[string]string{
+			pvolumes.TaskCachePath:        cachePath,
+			pvolumes.TaskCacheKey:         "go-${{ hashFiles('**/go.sum') }}",
+			pvolumes.TaskCacheRestoreKeys: `["go-"]`,
+			pvolumes.TaskCacheTTL:         "24h",
+		},
+	}
+
+	agent := &Agent{MaxCacheFileSizeMB: 10 * datasize.MB, EasyUse: EasyUse{ContainerContext: contextDir}}
+	agent.storeKeyedCache(storage)
+	hash, err := actioncache.HashFiles(contextDir, "**/go.sum")
+	assert.NoError(t, err)
+	entry, err := actioncache.ReadEntry(cacheDir, "go-"+hash)
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+	assert.Equal(t, int64(24*3600), entry.TTLSeconds)
+	// tar is written to temp file and renamed, nothing left behind
+	files, err := ioutil.ReadDir(actioncache.EntriesDir(cacheDir))
+	assert.NoError(t, err)
+	for _, f := range files {
+		assert.False(t, strings.HasSuffix(f.Name(), actioncache.TmpSuffix), f.Name())
+	}
+
+	// go.sum changed, restore by restore key prefix and upload new key
+	assert.NoError(t, os.RemoveAll(cachePath))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(contextDir, "repo", "go.sum"), []byte("sum2"), 0644))
+	agent = &Agent{MaxCacheFileSizeMB: 10 * datasize.MB, EasyUse: EasyUse{ContainerContext: contextDir}}
+	agent.restoreKeyedCache(storage)
+	b, err := ioutil.ReadFile(filepath.Join(cachePath, "mod"))
+	assert.NoError(t, err)
+	assert.Equal(t, "mod", string(b))
+	assert.False(t, agent.keyedCaches[storage.Name].exactHit)
+	agent.storeKeyedCache(storage)
+	entries, err := actioncache.ListEntries(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	// exact hit skips upload
+	agent = &Agent{MaxCacheFileSizeMB: 10 * datasize.MB, EasyUse: EasyUse{ContainerContext: contextDir}}
+	agent.restoreKeyedCache(storage)
+	assert.True(t, agent.keyedCaches[storage.Name].exactHit)
+}
+
+func TestStoreKeyedCacheExceedMaxSize(t *testing.T) {
+	root, err := ioutil.TempDir("", "keyed-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	cachePath := filepath.Join(root, "npm")
+	assert.NoError(t, os.MkdirAll(cachePath, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cachePath, "big"), make([]byte, 2*1024*1024), 0644))
+
+	storage := metadata.MetadataField{
+		Name:  "action_cache_1",
+		Value: filepath.Join(root, "caches"),
+		Labels: map[string]string{
+			pvolumes.TaskCachePath:      cachePath,
+			pvolumes.TaskCacheKey:       "npm-v1",
+			pvolumes.TaskCacheMaxSizeMB: "1",
+		},
+	}
+	agent := &Agent{MaxCacheFileSizeMB: 10 * datasize.MB, EasyUse: EasyUse{ContainerContext: root}}
+	agent.storeKeyedCache(storage)
+	entries, err := actioncache.ListEntries(storage.Value)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 0)
+}
@@ -71,6 +71,10 @@ func (agent *Agent) restore() {
 				agent.AppendError(err)
 			}
 		case string(spec.StoreTypeDiceCacheNFS):
+			if in.Labels[pvolumes.TaskCacheKey] != "" {
+				agent.restoreKeyedCache(in)
+				continue
+			}
 			tarExecPath := in.Labels[pvolumes.TaskCachePath]
 			tarFile := in.Value + "/" + in.Labels[pvolumes.TaskCacheHashName] + pvolumes.TaskCacheCompressionSuffix
 			if filehelper.CheckExist(tarFile, false) != nil {
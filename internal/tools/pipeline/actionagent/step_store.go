@@ -59,6 +59,10 @@ func (agent *Agent) store() {
 				logrus.Printf("upload action cache error: %s is not dir", out.Labels[pvolumes.TaskCachePath])
 				continue
 			}
+			if out.Labels[pvolumes.TaskCacheKey] != "" {
+				agent.storeKeyedCache(out)
+				continue
+			}
 			if err := agent.storeCache(tarFile, out.Labels[pvolumes.TaskCachePath]); err != nil {
 				logrus.Debugf("failed to tar cache path: %s to file: %s, err: %v", out.Labels[pvolumes.TaskCachePath], tarFile, err)
 				continue
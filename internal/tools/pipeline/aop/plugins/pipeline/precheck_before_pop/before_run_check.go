@@ -16,11 +16,14 @@ package precheck_before_pop
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/bundle"
 	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/lifecycle_hook_client"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
 	"github.com/erda-project/erda/pkg/parser/pipelineyml"
 )
 
@@ -29,6 +32,11 @@ const CheckResultFailed = "failed"
 const CheckResultEnd = "end"
 const HookType = "before-run-check"
 
+// CheckRunTimeout is the time budget of all hooks in one precheck, including retries,
+// it is kept well below the queue loop interval (QUEUE_LOOP_HANDLE_INTERVAL_SEC, default 10s)
+// because precheck blocks the queue loop
+const CheckRunTimeout = 3 * time.Second
+
 type HttpBeforeCheckRun struct {
 	PipelineID      uint64
 	Bdl             *bundle.Bundle
@@ -45,6 +53,8 @@ type CheckRunResult struct {
 	CheckResult string      `json:"checkResult"`
 	RetryOption RetryOption `json:"retryOption"`
 	Message     string      `json:"message"`
+	// Annotations will be saved as pipeline labels, existing labels are not overwritten
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type CheckRunResultRequest struct {
@@ -110,7 +120,14 @@ func (beforeCheckRun HttpBeforeCheckRun) CheckRun() (result *CheckRunResult, err
 	}
 	pipeline := pipelineWithTasks.Pipeline
 
+	annotations := map[string]string{}
+	deadline := time.Now().Add(CheckRunTimeout)
 	for _, info := range matchInfo {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return nil, fmt.Errorf("before run check hooks timeout after %s", CheckRunTimeout)
+		}
+
 		var checkRunResultRequest CheckRunResultRequest
 
 		checkRunResultRequest.Hook = info.Hook
@@ -123,7 +140,11 @@ func (beforeCheckRun HttpBeforeCheckRun) CheckRun() (result *CheckRunResult, err
 		checkRunResultRequest.Labels["pipelineLabels"] = info.Labels
 
 		var response CheckRunResultResponse
-		err := beforeCheckRun.LifeCycleClient.PostLifecycleHookHttpClient(info.Client, checkRunResultRequest, &response)
+		err := beforeCheckRun.LifeCycleClient.PostLifecycleHookHttpClient(info.Client, lifecycle_hook_client.Delivery{
+			PipelineID: pipeline.ID,
+			Hook:       info.Hook,
+			Timeout:    timeout,
+		}, checkRunResultRequest, &response)
 		if err != nil {
 			return nil, err
 		}
@@ -132,13 +153,64 @@ func (beforeCheckRun HttpBeforeCheckRun) CheckRun() (result *CheckRunResult, err
 			return nil, fmt.Errorf("response is empty or response not success")
 		}
 
+		for k, v := range response.CheckRunResult.Annotations {
+			annotations[k] = v
+		}
+
 		// return directly if there is an failed
 		if response.CheckRunResult.CheckResult == CheckResultFailed {
+			if err := beforeCheckRun.annotate(pipeline, annotations); err != nil {
+				return nil, err
+			}
 			return &response.CheckRunResult, nil
 		}
 	}
 
+	if err := beforeCheckRun.annotate(pipeline, annotations); err != nil {
+		return nil, err
+	}
 	return &CheckRunResult{
 		CheckResult: CheckResultSuccess,
+		Annotations: annotations,
 	}, nil
 }
+
+// annotate save annotations returned by hooks as pipeline labels,
+// precheck may be retried many times, so existing labels are skipped
+func (beforeCheckRun HttpBeforeCheckRun) annotate(pipeline *spec.Pipeline, annotations map[string]string) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	existLabels, err := beforeCheckRun.DBClient.ListLabelsByPipelineID(pipeline.ID)
+	if err != nil {
+		return err
+	}
+	exist := make(map[string]struct{}, len(existLabels))
+	for _, label := range existLabels {
+		exist[label.Key] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		if _, ok := exist[k]; ok {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	labels := make([]spec.PipelineLabel, 0, len(keys))
+	for _, k := range keys {
+		labels = append(labels, spec.PipelineLabel{
+			Type:            apistructs.PipelineLabelTypeInstance,
+			TargetID:        pipeline.ID,
+			PipelineSource:  pipeline.PipelineSource,
+			PipelineYmlName: pipeline.PipelineYmlName,
+			Key:             k,
+			Value:           annotations[k],
+		})
+	}
+	return beforeCheckRun.DBClient.BatchInsertLabels(labels)
+}
@@ -269,7 +269,8 @@ lifecycle:
 		guard1 := monkey.PatchInstanceMethod(reflect.TypeOf(&e), "ListLabelsByPipelineID", func(client *dbclient.Client, pipelineID uint64, ops ...dbclient.SessionOption) ([]spec.PipelineLabel, error) {
 			return nil, nil
 		})
-		guard2 := monkey.PatchInstanceMethod(reflect.TypeOf(v.httpBeforeCheckRun.LifeCycleClient), "PostLifecycleHookHttpClient", func(_ *lifecycle_hook_client.LifeCycleService, source string, req interface{}, resp interface{}) error {
+		guard2 := monkey.PatchInstanceMethod(reflect.TypeOf(v.httpBeforeCheckRun.LifeCycleClient), "PostLifecycleHookHttpClient", func(_ *lifecycle_hook_client.LifeCycleService, source string, delivery lifecycle_hook_client.Delivery, req interface{}, resp interface{}) error {
+			assert.Equal(t, HookType, delivery.Hook)
 			checkRunResultRequest := req.(CheckRunResultRequest)
 			if checkRunResultRequest.Labels != nil {
 				pipelineLabels := checkRunResultRequest.Labels["pipelineLabels"].(map[string]interface{})
@@ -302,3 +303,30 @@ lifecycle:
 	}
 
 }
+
+func TestAnnotate(t *testing.T) {
+	var e dbclient.Client
+	guard := monkey.PatchInstanceMethod(reflect.TypeOf(&e), "ListLabelsByPipelineID", func(client *dbclient.Client, pipelineID uint64, ops ...dbclient.SessionOption) ([]spec.PipelineLabel, error) {
+		return []spec.PipelineLabel{{Key: "exist", Value: "old"}}, nil
+	})
+	defer guard.Unpatch()
+	var inserted []spec.PipelineLabel
+	guard1 := monkey.PatchInstanceMethod(reflect.TypeOf(&e), "BatchInsertLabels", func(client *dbclient.Client, labels []spec.PipelineLabel, ops ...dbclient.SessionOption) error {
+		inserted = labels
+		return nil
+	})
+	defer guard1.Unpatch()
+
+	beforeCheckRun := HttpBeforeCheckRun{PipelineID: 1, DBClient: &e}
+	pipeline := &spec.Pipeline{PipelineBase: spec.PipelineBase{ID: 1, PipelineSource: "FDP", PipelineYmlName: "230"}}
+
+	assert.NoError(t, beforeCheckRun.annotate(pipeline, nil))
+	assert.Nil(t, inserted)
+
+	assert.NoError(t, beforeCheckRun.annotate(pipeline, map[string]string{"exist": "new", "b": "2", "a": "1"}))
+	assert.Len(t, inserted, 2)
+	assert.Equal(t, "a", inserted[0].Key)
+	assert.Equal(t, "b", inserted[1].Key)
+	assert.Equal(t, apistructs.PipelineLabelTypeInstance, inserted[1].Type)
+	assert.Equal(t, uint64(1), inserted[1].TargetID)
+}
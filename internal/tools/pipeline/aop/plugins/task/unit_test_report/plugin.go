@@ -64,6 +64,10 @@ func (p *provider) Handle(ctx *aoptypes.TuneContext) error {
 			var suites []apistructs.TestSuite
 			err = json.Unmarshal([]byte(v.Value), &suites)
 			meta["suites"] = suites
+		case "coverage":
+			var coverage []*apistructs.CodeCoverageNode
+			err = json.Unmarshal([]byte(v.Value), &coverage)
+			meta["coverage"] = coverage
 		}
 		if err != nil {
 			return fmt.Errorf("unmarshal unit-test report error: %v", err)
@@ -72,8 +76,11 @@ func (p *provider) Handle(ctx *aoptypes.TuneContext) error {
 
 	meta["taskId"] = ctx.SDK.Task.ID
 
-	pbMeta, _ := structpb.NewStruct(meta)
-	_, err := ctx.SDK.Report.Create(&pb.PipelineReportCreateRequest{
+	pbMeta, err := toStruct(meta)
+	if err != nil {
+		return fmt.Errorf("convert unit-test report error: %v", err)
+	}
+	_, err = ctx.SDK.Report.Create(&pb.PipelineReportCreateRequest{
 		PipelineID: ctx.SDK.Pipeline.ID,
 		Type:       actionTypeUnitTest,
 		Meta:       pbMeta,
@@ -85,6 +92,20 @@ func (p *provider) Handle(ctx *aoptypes.TuneContext) error {
 	return nil
 }
 
+// toStruct converts meta to structpb.Struct, which only accepts json values,
+// so the typed reports are converted by json first
+func toStruct(meta map[string]interface{}) (*structpb.Struct, error) {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(values)
+}
+
 func (p *provider) Init(ctx servicehub.Context) error {
 	err := aop.RegisterTunePoint(p)
 	if err != nil {
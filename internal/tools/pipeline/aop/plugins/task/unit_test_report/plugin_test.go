@@ -0,0 +1,34 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit_test_report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+func TestToStruct(t *testing.T) {
+	s, err := toStruct(map[string]interface{}{
+		"totals": apistructs.TestTotals{Tests: 2, Statuses: map[apistructs.TestStatus]int{apistructs.TestStatusPassed: 2}},
+		"taskId": uint64(1),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), s.Fields["totals"].GetStructValue().Fields["tests"].GetNumberValue())
+	assert.Equal(t, float64(2), s.Fields["totals"].GetStructValue().Fields["statuses"].GetStructValue().Fields["passed"].GetNumberValue())
+	assert.Equal(t, float64(1), s.Fields["taskId"].GetNumberValue())
+}
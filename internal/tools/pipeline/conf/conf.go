@@ -115,6 +115,12 @@ type Conf struct {
 
 	// k8s type executor max timeout second
 	K8SExecutorMaxInitializationSec uint64 `env:"K8S_EXECUTOR_MAX_INITIALIZATION_SEC" default:"5"`
+
+	// local executor, run action agent as subprocess on bare host
+	LocalExecutorAsDefault       bool          `env:"LOCAL_EXECUTOR_AS_DEFAULT" default:"false"`
+	LocalExecutorWorkDir         string        `env:"LOCAL_EXECUTOR_WORKDIR" default:"/tmp/erda-pipeline-local"`
+	LocalExecutorAgentBin        string        `env:"LOCAL_EXECUTOR_AGENT_BIN" default:"/opt/action/agent"`
+	LocalExecutorKillGracePeriod time.Duration `env:"LOCAL_EXECUTOR_KILL_GRACE_PERIOD" default:"10s"`
 }
 
 var cfg Conf
@@ -375,3 +381,23 @@ func K8SExecutorPoolSize() int {
 func K8SExecutorMaxInitializationSec() uint64 {
 	return cfg.K8SExecutorMaxInitializationSec
 }
+
+// LocalExecutorAsDefault return if use local executor as default executor of tasks.
+func LocalExecutorAsDefault() bool {
+	return cfg.LocalExecutorAsDefault
+}
+
+// LocalExecutorWorkDir return the root dir of task workdirs of local executor.
+func LocalExecutorWorkDir() string {
+	return cfg.LocalExecutorWorkDir
+}
+
+// LocalExecutorAgentBin return the action agent binary path on host.
+func LocalExecutorAgentBin() string {
+	return cfg.LocalExecutorAgentBin
+}
+
+// LocalExecutorKillGracePeriod return the period between SIGTERM and SIGKILL when cancel.
+func LocalExecutorKillGracePeriod() time.Duration {
+	return cfg.LocalExecutorKillGracePeriod
+}
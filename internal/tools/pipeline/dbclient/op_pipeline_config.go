@@ -75,13 +75,22 @@ var defaultDockerActionExecutor = spec.PipelineConfig{
 	},
 }
 
+var defaultLocalActionExecutor = spec.PipelineConfig{
+	Type: spec.PipelineConfigTypeActionExecutor,
+	Value: spec.ActionExecutorConfig{
+		Kind:    string(spec.PipelineTaskExecutorKindLocal),
+		Name:    spec.PipelineTaskExecutorNameLocalDefault.String(),
+		Options: nil,
+	},
+}
+
 func (client *Client) ListPipelineConfigsOfActionExecutor() (configs []spec.PipelineConfig, cfgChan chan spec.ActionExecutorConfig, err error) {
 	if err := client.Find(&configs, spec.PipelineConfig{Type: spec.PipelineConfigTypeActionExecutor}); err != nil {
 		return nil, nil, err
 	}
-	// add default api-test wait k8sjob k8sflink k8sspark action executor
+	// add default api-test wait k8sjob k8sflink k8sspark local action executor
 	configs = append(configs, defaultAPITestActionExecutor, defaultWaitActionExecutor,
-		defaultK8sJobActionExecutor, defaultK8sFlinkActionExecutor, defaultK8sSparkActionExecutor)
+		defaultK8sJobActionExecutor, defaultK8sFlinkActionExecutor, defaultK8sSparkActionExecutor, defaultLocalActionExecutor)
 	cfgChan = make(chan spec.ActionExecutorConfig, 100)
 	for _, c := range configs {
 		var r spec.ActionExecutorConfig
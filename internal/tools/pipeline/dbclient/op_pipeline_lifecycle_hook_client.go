@@ -14,11 +14,18 @@
 
 package dbclient
 
+import "time"
+
 type PipelineLifecycleHookClient struct {
 	ID     uint64 `json:"id" xorm:"pk autoincr"`
 	Name   string `json:"name"`
 	Host   string `json:"host"`
 	Prefix string `json:"prefix"`
+	// Secret is used to sign requests with HMAC-SHA256, empty means not signed,
+	// it is rsa encrypted and base64 encoded in db
+	Secret          string `json:"-"`
+	MaxRetries      int    `json:"maxRetries"`
+	RetryIntervalMs int    `json:"retryIntervalMs"`
 }
 
 func (ps *PipelineLifecycleHookClient) TableName() string {
@@ -41,16 +48,19 @@ func (client *Client) InsertOrUpdateLifeCycleClient(hookClient *PipelineLifecycl
 	session := client.NewSession(ops...)
 	defer session.Close()
 
-	exist, err := session.Where("name = ?", hookClient.Name).Get(&PipelineLifecycleHookClient{})
+	var existClient PipelineLifecycleHookClient
+	exist, err := session.Where("name = ?", hookClient.Name).Get(&existClient)
 	if err != nil {
 		return err
 	}
 
 	// already exist, try to update client info
+	// register is a full replacement, so zero values like empty secret must be updated too
 	if exist {
-		if _, err := session.Where("name = ?", hookClient.Name).Update(hookClient); err != nil {
+		if _, err := session.ID(existClient.ID).Cols("host", "prefix", "secret", "max_retries", "retry_interval_ms").Update(hookClient); err != nil {
 			return err
 		}
+		hookClient.ID = existClient.ID
 		return nil
 	}
 
@@ -59,3 +69,53 @@ func (client *Client) InsertOrUpdateLifeCycleClient(hookClient *PipelineLifecycl
 	}
 	return nil
 }
+
+// PipelineLifecycleHookDelivery records the final outcome of posting a lifecycle hook to client,
+// Attempt is the number of attempts made
+type PipelineLifecycleHookDelivery struct {
+	ID             uint64    `json:"id" xorm:"pk autoincr"`
+	DeliveryID     string    `json:"deliveryID"`
+	PipelineID     uint64    `json:"pipelineID"`
+	ClientName     string    `json:"clientName"`
+	Hook           string    `json:"hook"`
+	URL            string    `json:"url" xorm:"url"`
+	Attempt        int       `json:"attempt"`
+	Request        string    `json:"request"`
+	ResponseStatus int       `json:"responseStatus"`
+	Response       string    `json:"response"`
+	Error          string    `json:"error"`
+	LatencyMs      int64     `json:"latencyMs"`
+	TimeCreated    time.Time `json:"timeCreated" xorm:"created"`
+}
+
+func (d *PipelineLifecycleHookDelivery) TableName() string {
+	return "pipeline_lifecycle_hook_deliveries"
+}
+
+func (client *Client) CreateLifecycleHookDelivery(delivery *PipelineLifecycleHookDelivery, ops ...SessionOption) error {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	_, err := session.InsertOne(delivery)
+	return err
+}
+
+// ListLifecycleHookDeliveries return deliveries of the pipeline, latest first
+func (client *Client) ListLifecycleHookDeliveries(pipelineID uint64, ops ...SessionOption) (deliveries []*PipelineLifecycleHookDelivery, err error) {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	err = session.Where("pipeline_id = ?", pipelineID).Desc("id").Find(&deliveries)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// DeleteLifecycleHookDeliveriesBefore deletes deliveries created before t, return the number deleted
+func (client *Client) DeleteLifecycleHookDeliveriesBefore(t time.Time, ops ...SessionOption) (int64, error) {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	return session.Where("time_created < ?", t).Delete(&PipelineLifecycleHookDelivery{})
+}
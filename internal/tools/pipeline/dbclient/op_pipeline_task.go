@@ -236,6 +236,39 @@ func (client *Client) UpdatePipelineTaskExtra(id uint64, extra spec.PipelineTask
 	return err
 }
 
+// UpdatePipelineTaskExtraWithLock read task with row lock and update extra by updateFunc,
+// avoid concurrent updates (such as several users approve at the same time) overwriting each other.
+func (client *Client) UpdatePipelineTaskExtraWithLock(id uint64, updateFunc func(task *spec.PipelineTask) error) (*spec.PipelineTask, error) {
+	session := client.NewSession()
+	defer session.Close()
+
+	if err := session.Begin(); err != nil {
+		return nil, err
+	}
+	var task spec.PipelineTask
+	exist, err := session.ID(id).ForUpdate().Get(&task)
+	if err != nil {
+		_ = session.Rollback()
+		return nil, errors.Wrapf(err, "failed to get pipeline task by id [%v]", id)
+	}
+	if !exist {
+		_ = session.Rollback()
+		return nil, errors.Errorf("not found pipeline task by id [%v]", id)
+	}
+	if err := updateFunc(&task); err != nil {
+		_ = session.Rollback()
+		return nil, err
+	}
+	if _, err := session.ID(id).Cols("extra").Update(&spec.PipelineTask{Extra: task.Extra}); err != nil {
+		_ = session.Rollback()
+		return nil, err
+	}
+	if err := session.Commit(); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
 func (client *Client) RefreshPipelineTask(task *spec.PipelineTask) error {
 	r, err := client.GetPipelineTask(task.ID)
 	if err != nil {
@@ -17,6 +17,7 @@ package events
 import (
 	basepb "github.com/erda-project/erda-proto-go/core/pipeline/base/pb"
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/approval"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
 )
 
@@ -82,6 +83,34 @@ func EmitTaskEvent(task *spec.PipelineTask, p *spec.Pipeline) {
 	mgr.ch <- event
 }
 
+func EmitTaskApprovalEvent(task *spec.PipelineTask, p *spec.Pipeline, cfg *approval.Config) {
+	event := &PipelineTaskApprovalEvent{DefaultEvent: defaultEvent}
+
+	// EventHeader
+	event.EventHeader.Event = string(EventKindPipelineTaskApproval)
+	event.EventHeader.Action = string(approval.OutcomePending)
+
+	event.EventHeader.ApplicationID = p.Labels[apistructs.LabelAppID]
+	event.EventHeader.ProjectID = p.Labels[apistructs.LabelProjectID]
+	event.EventHeader.OrgID = p.Labels[apistructs.LabelOrgID]
+	event.EventHeader.Env = p.Extra.DiceWorkspace.String()
+
+	// Identity
+	event.UserID = p.GetRunUserID()
+	event.InternalClient = p.Extra.InternalClient
+
+	// Task
+	event.Task = task
+
+	// Pipeline
+	event.Pipeline = p
+
+	// Approval
+	event.Config = cfg
+
+	mgr.ch <- event
+}
+
 func EmitTaskRuntimeEvent(task *spec.PipelineTask, p *spec.Pipeline) {
 	event := &PipelineTaskRuntimeEvent{DefaultEvent: defaultEvent}
 
@@ -66,8 +66,9 @@ const (
 type EventKind string
 
 const (
-	EventKindPipeline            EventKind = "pipeline"
-	EventKindPipelineTask        EventKind = "pipeline_task"
-	EventKindPipelineTaskRuntime EventKind = "pipeline_task_runtime"
-	EventKindPipelineStream      EventKind = "pipeline_stream"
+	EventKindPipeline             EventKind = "pipeline"
+	EventKindPipelineTask         EventKind = "pipeline_task"
+	EventKindPipelineTaskRuntime  EventKind = "pipeline_task_runtime"
+	EventKindPipelineStream       EventKind = "pipeline_stream"
+	EventKindPipelineTaskApproval EventKind = "pipeline_task_approval"
 )
@@ -0,0 +1,166 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/commonutil/linkutil"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/approval"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+// PipelineTaskApprovalEvent is sent when a manual-approval task starts waiting for approvers.
+type PipelineTaskApprovalEvent struct {
+	DefaultEvent
+	IdentityInfo
+	EventHeader apistructs.EventHeader
+	Task        *spec.PipelineTask
+	Pipeline    *spec.Pipeline
+	Config      *approval.Config
+}
+
+type PipelineTaskApprovalEventData struct {
+	PipelineID        uint64   `json:"pipelineID"`
+	PipelineTaskID    uint64   `json:"pipelineTaskID"`
+	TaskName          string   `json:"taskName"`
+	OrgName           string   `json:"orgName"`
+	ProjectName       string   `json:"projectName"`
+	ApplicationName   string   `json:"applicationName"`
+	Branch            string   `json:"branch"`
+	Approvers         []string `json:"approvers"`
+	ApproverRoles     []string `json:"approverRoles"`
+	RequiredApprovals int      `json:"requiredApprovals"`
+	TimeoutSec        int64    `json:"timeoutSec"`
+	TimeoutOutcome    string   `json:"timeoutOutcome"`
+	Message           string   `json:"message"`
+	Link              string   `json:"link"`
+	UserID            string   `json:"userID"`
+}
+
+func (e *PipelineTaskApprovalEvent) Kind() EventKind {
+	return EventKindPipelineTaskApproval
+}
+
+func (e *PipelineTaskApprovalEvent) Header() apistructs.EventHeader {
+	return e.EventHeader
+}
+
+func (e *PipelineTaskApprovalEvent) Sender() string {
+	return SenderPipeline
+}
+
+func (e *PipelineTaskApprovalEvent) Content() interface{} {
+	_, link := linkutil.GetPipelineLink(e.org, *e.Pipeline)
+	return PipelineTaskApprovalEventData{
+		PipelineID:        e.Pipeline.ID,
+		PipelineTaskID:    e.Task.ID,
+		TaskName:          e.Task.Name,
+		OrgName:           e.Pipeline.GetOrgName(),
+		ProjectName:       e.Pipeline.GetLabel(apistructs.LabelProjectName),
+		ApplicationName:   e.Pipeline.GetLabel(apistructs.LabelAppName),
+		Branch:            e.Pipeline.GetLabel(apistructs.LabelBranch),
+		Approvers:         e.Config.Approvers,
+		ApproverRoles:     e.Config.ApproverRoles,
+		RequiredApprovals: e.Config.RequiredApprovals,
+		TimeoutSec:        int64(e.Config.Timeout.Seconds()),
+		TimeoutOutcome:    string(e.Config.TimeoutOutcome),
+		Message:           e.Config.Message,
+		Link:              link,
+		UserID:            e.UserID,
+	}
+}
+
+func (e *PipelineTaskApprovalEvent) String() string {
+	return fmt.Sprintf("event: %s, action: %s, pipelineID: %d, pipelineTaskID: %d",
+		e.EventHeader.Event, e.EventHeader.Action, e.Pipeline.ID, e.Task.ID)
+}
+
+// HandleWebhook send event to subscribed webhooks and notify groups,
+// and send station message to designated approvers.
+func (e *PipelineTaskApprovalEvent) HandleWebhook() error {
+	req := &apistructs.EventCreateRequest{}
+	req.Sender = SenderPipeline
+	req.EventHeader = e.Header()
+	req.Content = e.Content()
+	if err := e.DefaultEvent.CreateEvent(req); err != nil {
+		return err
+	}
+
+	if len(e.Config.Approvers) == 0 {
+		return nil
+	}
+	orgID, _ := strconv.ParseInt(e.Pipeline.Labels[apistructs.LabelOrgID], 10, 64)
+	return e.DefaultEvent.bdl.CreateMessage(&apistructs.MessageCreateRequest{
+		Sender: SenderPipeline,
+		Labels: map[string]interface{}{
+			"MBOX": e.Config.Approvers,
+		},
+		Content: map[string]interface{}{
+			"template": makeApprovalMsg(e),
+			"type":     "markdown",
+			"params":   map[string]string{},
+			"orgID":    orgID,
+		},
+	})
+}
+
+func (e *PipelineTaskApprovalEvent) HandleDingDing() error {
+	// dingding 地址目前是在应用维度，如果没有应用信息，则不发送
+	if e.Pipeline.Labels[apistructs.LabelAppID] == "" {
+		return nil
+	}
+	appID, err := strconv.ParseUint(e.Pipeline.Labels[apistructs.LabelAppID], 10, 64)
+	if err != nil {
+		return err
+	}
+	app, err := e.DefaultEvent.bdl.GetApp(appID)
+	if err != nil {
+		return err
+	}
+	ddHookURL, ok := app.Config["ddHookUrl"].(string)
+	if !ok || ddHookURL == "" {
+		return nil
+	}
+	return e.DefaultEvent.bdl.CreateMessage(&apistructs.MessageCreateRequest{
+		Sender: SenderPipeline,
+		Labels: map[string]interface{}{
+			"DINGDING": []string{ddHookURL},
+		},
+		Content: makeApprovalMsg(e),
+	})
+}
+
+func makeApprovalMsg(e *PipelineTaskApprovalEvent) string {
+	data, ok := e.Content().(PipelineTaskApprovalEventData)
+	if !ok {
+		return ""
+	}
+	msg := fmt.Sprintf("应用 %s/%s 的流水线任务 [%s] 等待人工审批（需 %d 人同意）。分支: %s，环境: %s。",
+		data.ProjectName, data.ApplicationName, data.TaskName, data.RequiredApprovals,
+		data.Branch, e.Pipeline.Extra.DiceWorkspace)
+	if data.Message != "" {
+		msg += fmt.Sprintf("说明: %s。", data.Message)
+	}
+	if data.TimeoutSec > 0 {
+		msg += fmt.Sprintf("%d 秒内未审批将自动 %s。", data.TimeoutSec, data.TimeoutOutcome)
+	}
+	if data.Link != "" {
+		msg += fmt.Sprintf("链接: %s", data.Link)
+	}
+	return msg
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/reconciler"
 
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/cron/compensator"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 
 	"github.com/erda-project/erda-infra/base/servicehub"
 	"github.com/erda-project/erda-infra/base/version"
@@ -135,6 +136,7 @@ func (p *provider) do() error {
 
 	p.CronDaemon.WithPipelineFunc(p.PipelineSvc.CreateV2)
 	p.CronCompensate.WithPipelineFunc(compensator.PipelineFunc{CreatePipeline: p.PipelineSvc.CreateV2, RunPipeline: p.PipelineRun.RunOnePipeline})
+	p.Trigger.WithPipelineFunc(pipelinetrigger.PipelineFunc{CreatePipeline: p.PipelineSvc.CreateV2, RunPipeline: p.PipelineRun.RunOnePipeline})
 
 	//server.Router().Path("/metrics").Methods(http.MethodGet).Handler(promxp.Handler("pipeline"))
 	server := httpserver.New("")
@@ -20,5 +20,6 @@ import (
 	_ "github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/plugins/k8sflink"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/plugins/k8sjob"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/plugins/k8sspark"
+	_ "github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/plugins/local"
 	_ "github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/plugins/wait"
 )
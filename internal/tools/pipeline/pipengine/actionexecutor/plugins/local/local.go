@@ -0,0 +1,448 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/actionagent"
+	"github.com/erda-project/erda/internal/tools/pipeline/conf"
+	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/logic"
+	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/types"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+var Kind = types.Kind(spec.PipelineTaskExecutorKindLocal)
+
+const (
+	pidFileName      = "pid"
+	exitCodeFileName = "exitcode"
+	stdoutFileName   = "stdout.log"
+	stderrFileName   = "stderr.log"
+	canceledFileName = "canceled"
+
+	// inspectTailBytes is the max bytes of each log file returned by Inspect
+	inspectTailBytes = 4096
+)
+
+func init() {
+	types.MustRegister(Kind, func(name types.Name, options map[string]string) (types.ActionExecutor, error) {
+		return New(name, conf.LocalExecutorWorkDir(), conf.LocalExecutorAgentBin(), conf.LocalExecutorKillGracePeriod()), nil
+	})
+}
+
+type Local struct {
+	name        types.Name
+	rootDir     string
+	agentBin    string
+	gracePeriod time.Duration
+	errWrapper  *logic.ErrorWrapper
+
+	lock      sync.Mutex
+	processes map[string]*process
+}
+
+// process is a started subprocess of task
+type process struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+func New(name types.Name, rootDir, agentBin string, gracePeriod time.Duration) *Local {
+	return &Local{
+		name:        name,
+		rootDir:     rootDir,
+		agentBin:    agentBin,
+		gracePeriod: gracePeriod,
+		errWrapper:  logic.NewErrorWrapper(name.String()),
+		processes:   make(map[string]*process),
+	}
+}
+
+func (l *Local) Kind() types.Kind {
+	return Kind
+}
+
+func (l *Local) Name() types.Name {
+	return l.name
+}
+
+// workDir return the isolated workdir of task, like: <root>/<namespace>/<jobID>
+func (l *Local) workDir(task *spec.PipelineTask) string {
+	return filepath.Join(l.rootDir, logic.MakeJobName(task))
+}
+
+func (l *Local) Exist(ctx context.Context, task *spec.PipelineTask) (created, started bool, err error) {
+	if err := logic.ValidateAction(task); err != nil {
+		return false, false, err
+	}
+	workDir := l.workDir(task)
+	if _, err := os.Stat(workDir); err != nil {
+		if os.IsNotExist(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	created = true
+	if _, err := os.Stat(filepath.Join(workDir, pidFileName)); err == nil {
+		started = true
+	}
+	return created, started, nil
+}
+
+func (l *Local) Create(ctx context.Context, task *spec.PipelineTask) (data interface{}, err error) {
+	defer l.errWrapper.WrapTaskError(&err, "create job", task)
+	if err := logic.ValidateAction(task); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(l.workDir(task), 0755); err != nil {
+		return nil, errors.Errorf("failed to create workdir, err: %v", err)
+	}
+	return nil, nil
+}
+
+func (l *Local) Start(ctx context.Context, task *spec.PipelineTask) (data interface{}, err error) {
+	defer l.errWrapper.WrapTaskError(&err, "start job", task)
+	if err := logic.ValidateAction(task); err != nil {
+		return nil, err
+	}
+	created, started, err := l.Exist(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		logrus.Warnf("%s: task not created(auto try to create), taskInfo: %s", l.Kind().String(), logic.PrintTaskInfo(task))
+		if _, err := l.Create(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+	if started {
+		logrus.Warnf("%s: task already started, taskInfo: %s", l.Kind().String(), logic.PrintTaskInfo(task))
+		return nil, nil
+	}
+	job, err := logic.TransferToSchedulerJob(task)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir := l.workDir(task)
+	stdout, err := os.OpenFile(filepath.Join(workDir, stdoutFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := os.OpenFile(filepath.Join(workDir, stderrFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		stdout.Close()
+		return nil, err
+	}
+
+	cmd := l.makeCommand(task, job)
+	cmd.Dir = workDir
+	cmd.Env = l.makeEnvs(job, workDir)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// 独立进程组，取消时可以向整个进程组发送信号
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		stdout.Close()
+		stderr.Close()
+		return nil, errors.Errorf("failed to start process, err: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, pidFileName), []byte(strconv.Itoa(cmd.Process.Pid)), 0644); err != nil {
+		logrus.Errorf("%s: failed to write pid file, taskInfo: %s, err: %v", l.Kind().String(), logic.PrintTaskInfo(task), err)
+	}
+
+	proc := &process{cmd: cmd, done: make(chan struct{})}
+	jobName := logic.MakeJobName(task)
+	l.lock.Lock()
+	l.processes[jobName] = proc
+	l.lock.Unlock()
+
+	executorDoneCh, hasDoneCh := ctx.Value(spec.MakeTaskExecutorCtxKey(task)).(chan spec.ExecutorDoneChanData)
+	doneChanDataVersion := task.GenerateExecutorDoneChanDataVersion()
+	go func() {
+		defer close(proc.done)
+		defer stdout.Close()
+		defer stderr.Close()
+		_ = cmd.Wait()
+		exitCode := cmd.ProcessState.ExitCode()
+		if err := os.WriteFile(filepath.Join(workDir, exitCodeFileName), []byte(strconv.Itoa(exitCode)), 0644); err != nil {
+			logrus.Errorf("%s: failed to write exit code file, taskInfo: %s, err: %v", l.Kind().String(), logic.PrintTaskInfo(task), err)
+		}
+		if !hasDoneCh {
+			return
+		}
+		select {
+		case executorDoneCh <- spec.ExecutorDoneChanData{Data: l.statusOfWorkDir(workDir), Version: doneChanDataVersion}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return apistructs.Job{JobFromUser: job}, nil
+}
+
+// makeCommand run the local agent binary with agent args directly, otherwise run cmd by shell.
+// resource limits are set by shell ulimit before exec, so the process never runs without limits.
+func (l *Local) makeCommand(task *spec.PipelineTask, job apistructs.JobFromUser) *exec.Cmd {
+	name, args := "sh", []string{"-c", job.Cmd}
+	if task.Extra.Cmd == conf.AgentContainerPathWhenExecute() {
+		name, args = l.agentBin, task.Extra.CmdArgs
+	}
+	script := makeUlimitScript(job, task.Extra.Timeout)
+	if script == "" {
+		return exec.Command(name, args...)
+	}
+	return exec.Command("sh", append([]string{"-c", script + `exec "$0" "$@"`, name}, args...)...)
+}
+
+// makeEnvs only pass through the necessary envs of host, the process should not see envs of pipeline itself
+func (l *Local) makeEnvs(job apistructs.JobFromUser, workDir string) []string {
+	envs := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + workDir,
+		"TMPDIR=" + workDir,
+	}
+	for k, v := range job.Env {
+		envs = append(envs, fmt.Sprintf("%s=%s", k, v))
+	}
+	envs = append(envs,
+		fmt.Sprintf("%s=%s", apistructs.JobEnvNamespace, job.Namespace),
+		fmt.Sprintf("%s=%f", apistructs.JobEnvOriginCPU, job.CPU),
+		fmt.Sprintf("%s=%f", apistructs.JobEnvOriginMEM, job.Memory),
+		fmt.Sprintf("%s=%f", apistructs.JobEnvRequestCPU, job.CPU),
+		fmt.Sprintf("%s=%f", apistructs.JobEnvRequestMEM, job.Memory),
+		fmt.Sprintf("%s=%f", apistructs.JobEnvLimitCPU, job.MaxCPU),
+		fmt.Sprintf("%s=%f", apistructs.JobENvLimitMEM, job.MaxMemory),
+		fmt.Sprintf("%s=%s", actionagent.EnvEnablePushLog2Collector, "true"),
+	)
+	return envs
+}
+
+func (l *Local) Update(ctx context.Context, task *spec.PipelineTask) (interface{}, error) {
+	return nil, errors.Errorf("%s not support update operation", l.Kind().String())
+}
+
+func (l *Local) Status(ctx context.Context, task *spec.PipelineTask) (desc apistructs.PipelineStatusDesc, err error) {
+	if err := logic.ValidateAction(task); err != nil {
+		return desc, err
+	}
+	created, started, err := l.Exist(ctx, task)
+	if err != nil {
+		return desc, err
+	}
+	if !created {
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusAnalyzed}, nil
+	}
+	if !started {
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusCreated}, nil
+	}
+	return l.statusOfWorkDir(l.workDir(task)), nil
+}
+
+// statusOfWorkDir judge status by files in workdir, so status is still available after pipeline restart
+func (l *Local) statusOfWorkDir(workDir string) apistructs.PipelineStatusDesc {
+	if _, err := os.Stat(filepath.Join(workDir, canceledFileName)); err == nil {
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusStopByUser}
+	}
+	if b, err := os.ReadFile(filepath.Join(workDir, exitCodeFileName)); err == nil {
+		exitCode, err := strconv.Atoi(strings.TrimSpace(string(b)))
+		if err != nil {
+			return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusFailed, Desc: fmt.Sprintf("invalid exit code: %s", string(b))}
+		}
+		if exitCode == 0 {
+			return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusSuccess}
+		}
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusFailed, Desc: fmt.Sprintf("process exited with code %d", exitCode)}
+	}
+	pid, err := readPid(workDir)
+	if err == nil && processAlive(pid) {
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusRunning}
+	}
+	// 进程已不存在且没有退出码，可能是 pipeline 重启期间进程丢失
+	return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusFailed, Desc: "process lost"}
+}
+
+func (l *Local) Inspect(ctx context.Context, task *spec.PipelineTask) (apistructs.TaskInspect, error) {
+	if err := logic.ValidateAction(task); err != nil {
+		return apistructs.TaskInspect{}, err
+	}
+	workDir := l.workDir(task)
+	var sb strings.Builder
+	for _, name := range []string{stdoutFileName, stderrFileName} {
+		tail, err := tailFile(filepath.Join(workDir, name), inspectTailBytes)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return apistructs.TaskInspect{}, err
+		}
+		sb.WriteString(fmt.Sprintf("==> %s <==\n%s\n", name, tail))
+	}
+	return apistructs.TaskInspect{Desc: sb.String()}, nil
+}
+
+func (l *Local) Cancel(ctx context.Context, task *spec.PipelineTask) (data interface{}, err error) {
+	defer l.errWrapper.WrapTaskError(&err, "cancel job", task)
+	if err := logic.ValidateAction(task); err != nil {
+		return nil, err
+	}
+	workDir := l.workDir(task)
+	created, started, err := l.Exist(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	if !created || !started {
+		logrus.Warnf("%s: task not started, skip cancel, taskInfo: %s", l.Kind().String(), logic.PrintTaskInfo(task))
+		return nil, nil
+	}
+	if _, err := os.Stat(filepath.Join(workDir, exitCodeFileName)); err == nil {
+		return nil, nil
+	}
+	if err := os.WriteFile(filepath.Join(workDir, canceledFileName), nil, 0644); err != nil {
+		return nil, err
+	}
+	return nil, l.killProcessGroup(task)
+}
+
+// killProcessGroup send SIGTERM to the process group, and SIGKILL after grace period
+func (l *Local) killProcessGroup(task *spec.PipelineTask) error {
+	pid, err := readPid(l.workDir(task))
+	if err != nil {
+		return err
+	}
+	if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	l.lock.Lock()
+	proc, tracked := l.processes[logic.MakeJobName(task)]
+	l.lock.Unlock()
+	if tracked {
+		select {
+		case <-proc.done:
+			return nil
+		case <-time.After(l.gracePeriod):
+		}
+	} else {
+		deadline := time.Now().Add(l.gracePeriod)
+		for processAlive(pid) && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	if tracked {
+		<-proc.done
+	}
+	return nil
+}
+
+func (l *Local) Remove(ctx context.Context, task *spec.PipelineTask) (data interface{}, err error) {
+	defer l.errWrapper.WrapTaskError(&err, "remove job", task)
+	if err := logic.ValidateAction(task); err != nil {
+		return nil, err
+	}
+	return l.delete(ctx, task)
+}
+
+func (l *Local) BatchDelete(ctx context.Context, tasks []*spec.PipelineTask) (data interface{}, err error) {
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	task := tasks[0]
+	defer l.errWrapper.WrapTaskError(&err, "batch delete job", task)
+	for _, task := range tasks {
+		if len(task.Extra.UUID) <= 0 {
+			continue
+		}
+		if _, err := l.delete(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (l *Local) delete(ctx context.Context, task *spec.PipelineTask) (interface{}, error) {
+	workDir := l.workDir(task)
+	if _, err := os.Stat(workDir); os.IsNotExist(err) {
+		logrus.Warnf("%s: task not exist, taskInfo: %s", l.Kind().String(), logic.PrintTaskInfo(task))
+		return nil, nil
+	}
+	if pid, err := readPid(workDir); err == nil && processAlive(pid) {
+		if err := l.killProcessGroup(task); err != nil {
+			return nil, err
+		}
+	}
+	l.lock.Lock()
+	delete(l.processes, logic.MakeJobName(task))
+	l.lock.Unlock()
+	if err := os.RemoveAll(workDir); err != nil {
+		return nil, err
+	}
+	return task.Extra.UUID, nil
+}
+
+func readPid(workDir string) (int, error) {
+	b, err := os.ReadFile(filepath.Join(workDir, pidFileName))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// processAlive use signal 0 to check whether process exists
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	return syscall.Kill(pid, 0) == nil
+}
+
+func tailFile(path string, maxBytes int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	offset := info.Size() - maxBytes
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+func newTestTask(cmd string) *spec.PipelineTask {
+	return &spec.PipelineTask{
+		ID:         1,
+		PipelineID: 1,
+		Extra: spec.PipelineTaskExtra{
+			Namespace:   "pipeline-1",
+			UUID:        "pipeline-task-1",
+			ClusterName: "local",
+			Cmd:         cmd,
+		},
+	}
+}
+
+func waitDone(t *testing.T, l *Local, task *spec.PipelineTask) apistructs.PipelineStatusDesc {
+	for i := 0; i < 100; i++ {
+		desc, err := l.Status(context.Background(), task)
+		assert.NoError(t, err)
+		if desc.Status.IsEndStatus() {
+			return desc
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatal("wait task done timeout")
+	return apistructs.PipelineStatusDesc{}
+}
+
+func TestLocalRunAndInspect(t *testing.T) {
+	l := New("local", t.TempDir(), "", time.Second)
+	task := newTestTask("echo hello-local && exit 3")
+
+	created, started, err := l.Exist(context.Background(), task)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.False(t, started)
+
+	_, err = l.Start(context.Background(), task)
+	assert.NoError(t, err)
+	desc := waitDone(t, l, task)
+	assert.Equal(t, apistructs.PipelineStatusFailed, desc.Status)
+	assert.Equal(t, "process exited with code 3", desc.Desc)
+
+	inspect, err := l.Inspect(context.Background(), task)
+	assert.NoError(t, err)
+	assert.Contains(t, inspect.Desc, "hello-local")
+
+	_, err = l.Remove(context.Background(), task)
+	assert.NoError(t, err)
+	created, _, err = l.Exist(context.Background(), task)
+	assert.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestLocalCancel(t *testing.T) {
+	l := New("local", t.TempDir(), "", time.Second)
+	task := newTestTask("sleep 30")
+
+	_, err := l.Start(context.Background(), task)
+	assert.NoError(t, err)
+	desc, err := l.Status(context.Background(), task)
+	assert.NoError(t, err)
+	assert.Equal(t, apistructs.PipelineStatusRunning, desc.Status)
+
+	_, err = l.Cancel(context.Background(), task)
+	assert.NoError(t, err)
+	desc = waitDone(t, l, task)
+	assert.Equal(t, apistructs.PipelineStatusStopByUser, desc.Status)
+}
+
+func TestLocalDoneChan(t *testing.T) {
+	l := New("local", t.TempDir(), "", time.Second)
+	task := newTestTask("true")
+	doneCh := make(chan spec.ExecutorDoneChanData, 1)
+	ctx := context.WithValue(context.Background(), spec.MakeTaskExecutorCtxKey(task), doneCh)
+
+	_, err := l.Start(ctx, task)
+	assert.NoError(t, err)
+	select {
+	case data := <-doneCh:
+		assert.Equal(t, task.GenerateExecutorDoneChanDataVersion(), data.Version)
+		assert.Equal(t, apistructs.PipelineStatusSuccess, data.Data.(apistructs.PipelineStatusDesc).Status)
+	case <-time.After(10 * time.Second):
+		t.Fatal("wait done chan timeout")
+	}
+}
+
+func TestMakeUlimitScript(t *testing.T) {
+	assert.Equal(t, "", makeUlimitScript(apistructs.JobFromUser{}, time.Minute))
+	assert.Equal(t, "ulimit -d 1048576 || exit 1; ulimit -t 120 || exit 1; ",
+		makeUlimitScript(apistructs.JobFromUser{CPU: 1, MaxCPU: 2, Memory: 512, MaxMemory: 1024}, time.Minute))
+	// no timeout, no cpu limit
+	assert.Equal(t, "ulimit -d 524288 || exit 1; ", makeUlimitScript(apistructs.JobFromUser{CPU: 1, Memory: 512}, -1))
+}
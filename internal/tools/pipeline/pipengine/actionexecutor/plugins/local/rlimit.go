@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package local
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+// makeUlimitScript generate shell ulimit commands to limit process without cgroup:
+// data segment size by memory(MB), cpu time by timeout * cpu cores.
+// limits are set in the shell before exec, and inherited by children of the process.
+func makeUlimitScript(job apistructs.JobFromUser, timeout time.Duration) string {
+	var sb strings.Builder
+	memMB := math.Max(job.MaxMemory, job.Memory)
+	if memMB > 0 {
+		sb.WriteString(fmt.Sprintf("ulimit -d %d || exit 1; ", uint64(memMB*1024)))
+	}
+	cpu := math.Max(job.MaxCPU, job.CPU)
+	if timeout > 0 && cpu > 0 {
+		sb.WriteString(fmt.Sprintf("ulimit -t %d || exit 1; ", uint64(math.Ceil(timeout.Seconds()*cpu))))
+	}
+	return sb.String()
+}
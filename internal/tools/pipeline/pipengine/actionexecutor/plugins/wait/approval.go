@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/events"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/approval"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+// startApproval send approval request to approvers, the decision is made by approve api.
+func (w *Wait) startApproval(task *spec.PipelineTask) error {
+	cfg, err := approval.ParseConfig(task.Extra.Action.Params)
+	if err != nil {
+		return err
+	}
+	p, err := w.dbClient.GetPipeline(task.PipelineID)
+	if err != nil {
+		return err
+	}
+	events.EmitTaskApprovalEvent(task, &p, cfg)
+	logrus.Infof("wait: manual approval requested, pipelineID: %d, taskID: %d, approvers: %v, approverRoles: %v",
+		task.PipelineID, task.ID, cfg.Approvers, cfg.ApproverRoles)
+	return nil
+}
+
+// approvalStatus evaluate approval by the latest comment trail in db.
+func (w *Wait) approvalStatus(task *spec.PipelineTask) (apistructs.PipelineStatusDesc, error) {
+	cfg, err := approval.ParseConfig(task.Extra.Action.Params)
+	if err != nil {
+		return apistructs.PipelineStatusDesc{
+			Status: apistructs.PipelineStatusFailed,
+			Desc:   err.Error(),
+		}, nil
+	}
+
+	// records are appended by approve api, refresh them into task in memory,
+	// so that they will not be overwritten when reconciler updates the task
+	latest, err := w.dbClient.GetPipelineTask(task.ID)
+	if err != nil {
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusUnknown}, nil
+	}
+	task.Extra.ManualApproval = latest.Extra.ManualApproval
+
+	outcome, reason := approval.Evaluate(cfg, task.Extra.ManualApproval, task.TimeBegin, time.Now())
+	switch outcome {
+	case approval.OutcomeApproved:
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusSuccess, Desc: reason}, nil
+	case approval.OutcomeRejected:
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusFailed, Desc: reason}, nil
+	default:
+		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusRunning}, nil
+	}
+}
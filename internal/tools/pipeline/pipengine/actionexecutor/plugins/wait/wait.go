@@ -23,6 +23,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
 	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/types"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
 	"github.com/erda-project/erda/pkg/envconf"
@@ -32,16 +33,22 @@ var Kind = types.Kind(spec.PipelineTaskExecutorKindWait)
 
 func init() {
 	types.MustRegister(Kind, func(name types.Name, options map[string]string) (types.ActionExecutor, error) {
+		dbClient, err := dbclient.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to init dbclient, err: %v", err)
+		}
 		return &Wait{
-			name:    name,
-			options: options,
+			name:     name,
+			options:  options,
+			dbClient: dbClient,
 		}, nil
 	})
 }
 
 type Wait struct {
-	name    types.Name
-	options map[string]string
+	name     types.Name
+	options  map[string]string
+	dbClient *dbclient.Client
 }
 
 func (w *Wait) Kind() types.Kind {
@@ -92,6 +99,11 @@ func (w *Wait) Start(ctx context.Context, task *spec.PipelineTask) (interface{},
 		return nil, nil
 	}
 
+	// manual approval is decided by approvers through api, status is polled by reconciler
+	if task.Type == apistructs.ActionTypeManualApproval {
+		return nil, w.startApproval(task)
+	}
+
 	executorDoneCh := ctx.Value(spec.MakeTaskExecutorCtxKey(task)).(chan spec.ExecutorDoneChanData)
 	if executorDoneCh == nil {
 		return nil, errors.Errorf("wait: failed to get exector channel, pipelineID: %d, taskID: %d", task.PipelineID, task.ID)
@@ -136,6 +148,9 @@ func (w *Wait) Status(ctx context.Context, task *spec.PipelineTask) (apistructs.
 	if task.TimeBegin.IsZero() {
 		return apistructs.PipelineStatusDesc{Status: apistructs.PipelineStatusRunning}, nil
 	}
+	if task.Type == apistructs.ActionTypeManualApproval {
+		return w.approvalStatus(task)
+	}
 
 	waitSec, err := w.getWaitSec(task)
 	if err != nil {
@@ -17,13 +17,17 @@ package pvolumes
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/actioncache"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
 	"github.com/erda-project/erda/pkg/metadata"
 	"github.com/erda-project/erda/pkg/parser/diceyml"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
 )
 
 const (
@@ -34,6 +38,12 @@ const (
 	TaskCacheCompressionSuffix = ".tar"
 	TaskCachePathBasePath      = "{{basePath}}"
 	TaskCachePathEndPath       = "{{endPath}}"
+
+	// 按 key 寻址的缓存，由 agent 在运行时计算 key 并读写缓存目录下的 keys/<key>.tar
+	TaskCacheKey         = "action_cache_key"
+	TaskCacheRestoreKeys = "action_cache_restore_keys"
+	TaskCacheMaxSizeMB   = "action_cache_max_size_mb"
+	TaskCacheTTL         = "action_cache_ttl"
 )
 
 func HandleTaskCacheVolumes(p *spec.Pipeline, task *spec.PipelineTask, diceYmlJob *diceyml.Job, mountPoint string) {
@@ -54,9 +64,10 @@ func HandleTaskCacheVolumes(p *spec.Pipeline, task *spec.PipelineTask, diceYmlJo
 		hasher.Write([]byte(cache.Path))
 		hash := hex.EncodeToString(hasher.Sum(nil))
 
-		// key 为空就根据 hash 值和一些前缀生成一个固定的挂载目录
+		// key 为空或按 key 寻址时，根据 hash 值和一些前缀生成一个固定的挂载目录
 		key := cache.Key
-		if key == "" {
+		keyed := actioncache.IsKeyed(key)
+		if key == "" || keyed {
 			key = filepath.Join(mountPoint, TaskCacheBasePath, projectID, appID, hash)
 		} else {
 			// key 不为空就需要根据占位符替换成固定的挂载目录，其中只有非占位符之间是用户可自定义的一部分
@@ -76,6 +87,9 @@ func HandleTaskCacheVolumes(p *spec.Pipeline, task *spec.PipelineTask, diceYmlJo
 		labels[VoLabelKeyContextPath] = key
 		labels[TaskCacheHashName] = hash
 		labels[TaskCachePath] = cache.Path
+		if keyed {
+			setKeyedCacheLabels(labels, cache)
+		}
 		var storage = metadata.MetadataField{
 			Name:   TaskCacheMame + "_" + hash,
 			Type:   string(spec.StoreTypeDiceCacheNFS),
@@ -92,3 +106,17 @@ func HandleTaskCacheVolumes(p *spec.Pipeline, task *spec.PipelineTask, diceYmlJo
 	// add binds
 	diceYmlJob.Binds = append(diceYmlJob.Binds, binds...)
 }
+
+func setKeyedCacheLabels(labels map[string]string, cache pipelineyml.ActionCache) {
+	labels[TaskCacheKey] = cache.Key
+	if len(cache.RestoreKeys) > 0 {
+		restoreKeys, _ := json.Marshal(cache.RestoreKeys)
+		labels[TaskCacheRestoreKeys] = string(restoreKeys)
+	}
+	if cache.MaxSizeMB > 0 {
+		labels[TaskCacheMaxSizeMB] = strconv.FormatInt(cache.MaxSizeMB, 10)
+	}
+	if cache.TTL != "" {
+		labels[TaskCacheTTL] = cache.TTL
+	}
+}
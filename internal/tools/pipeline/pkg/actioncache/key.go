@@ -0,0 +1,223 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actioncache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+const legacyKeyPrefix = "{{basePath}}"
+
+// hashFilesRe matches ${{ hashFiles('go.sum', '**/package-lock.json') }}
+var hashFilesRe = regexp.MustCompile(`\$\{\{\s*hashFiles\(([^)]*)\)\s*\}\}`)
+
+var invalidKeyCharRe = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// IsKeyed return whether cache is stored by key instead of legacy {{basePath}}/xxx/{{endPath}} mount dir.
+func IsKeyed(key string) bool {
+	key = strings.TrimSpace(key)
+	return key != "" && !strings.HasPrefix(strings.ReplaceAll(key, " ", ""), legacyKeyPrefix)
+}
+
+// ValidateKey check all hashFiles expressions in key can be parsed.
+func ValidateKey(key string) error {
+	for _, match := range hashFilesRe.FindAllStringSubmatch(key, -1) {
+		patterns, err := parseHashFilesArgs(match[1])
+		if err != nil {
+			return err
+		}
+		for _, pattern := range patterns {
+			if _, err := compilePattern(pattern); err != nil {
+				return fmt.Errorf("invalid hashFiles pattern %q: %v", pattern, err)
+			}
+		}
+	}
+	if strings.Contains(hashFilesRe.ReplaceAllString(key, ""), "hashFiles(") {
+		return fmt.Errorf("invalid hashFiles expression in key %q, should be like ${{ hashFiles('go.sum') }}", key)
+	}
+	return nil
+}
+
+// RenderKey render hashFiles expressions in key by files under workDir, and convert result to a safe file name.
+func RenderKey(key, workDir string) (string, error) {
+	var renderErr error
+	rendered := hashFilesRe.ReplaceAllStringFunc(key, func(expr string) string {
+		if renderErr != nil {
+			return ""
+		}
+		patterns, err := parseHashFilesArgs(hashFilesRe.FindStringSubmatch(expr)[1])
+		if err != nil {
+			renderErr = err
+			return ""
+		}
+		hash, err := HashFiles(workDir, patterns...)
+		if err != nil {
+			renderErr = err
+			return ""
+		}
+		return hash
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+	return SanitizeKey(rendered), nil
+}
+
+// SanitizeKey replace chars which can not be used in file name.
+func SanitizeKey(key string) string {
+	return invalidKeyCharRe.ReplaceAllString(strings.TrimSpace(key), "-")
+}
+
+// HashFiles return sha256 of all regular files under workDir matched by patterns.
+// Pattern begin with ! excludes files, absolute pattern under workDir is also supported.
+// Return empty string if no file matched.
+func HashFiles(workDir string, patterns ...string) (string, error) {
+	var includes, excludes []glob.Glob
+	for _, pattern := range patterns {
+		exclude := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		if filepath.IsAbs(pattern) {
+			rel, err := filepath.Rel(workDir, pattern)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				return "", fmt.Errorf("hashFiles pattern %q is not under %s", pattern, workDir)
+			}
+			pattern = rel
+		}
+		g, err := compilePattern(pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid hashFiles pattern %q: %v", pattern, err)
+		}
+		if exclude {
+			excludes = append(excludes, g)
+		} else {
+			includes = append(includes, g)
+		}
+	}
+
+	var files []string
+	err := filepath.Walk(workDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if matchAny(includes, rel) && !matchAny(excludes, rel) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	sort.Strings(files)
+
+	hasher := sha256.New()
+	for _, file := range files {
+		fileHash, err := hashFile(file)
+		if err != nil {
+			return "", err
+		}
+		hasher.Write(fileHash)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
+
+// compilePattern compile glob pattern, **/ also matches files in root dir.
+func compilePattern(pattern string) (glob.Glob, error) {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	if strings.HasPrefix(pattern, "**/") {
+		pattern = "{" + strings.TrimPrefix(pattern, "**/") + "," + pattern + "}"
+	}
+	return glob.Compile(pattern, '/')
+}
+
+func matchAny(globs []glob.Glob, path string) bool {
+	for _, g := range globs {
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHashFilesArgs parse 'a', "b" into [a b]
+func parseHashFilesArgs(s string) ([]string, error) {
+	var args []string
+	s = strings.TrimSpace(s)
+	for len(s) > 0 {
+		quote := s[0]
+		if quote != '\'' && quote != '"' {
+			return nil, fmt.Errorf("hashFiles argument should be quoted: %s", s)
+		}
+		end := strings.IndexByte(s[1:], quote)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated hashFiles argument: %s", s)
+		}
+		args = append(args, s[1:end+1])
+		s = strings.TrimSpace(s[end+2:])
+		if len(s) == 0 {
+			break
+		}
+		if s[0] != ',' {
+			return nil, fmt.Errorf("hashFiles arguments should be separated by comma: %s", s)
+		}
+		s = strings.TrimSpace(s[1:])
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("hashFiles needs at least one pattern")
+	}
+	return args, nil
+}
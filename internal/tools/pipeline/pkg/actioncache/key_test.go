@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actioncache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestIsKeyed(t *testing.T) {
+	assert.False(t, IsKeyed(""))
+	assert.False(t, IsKeyed("{{basePath}}/go/{{endPath}}"))
+	assert.False(t, IsKeyed(" {{ basePath }}/go/{{endPath}}"))
+	assert.True(t, IsKeyed("go-mod-${{ hashFiles('go.sum') }}"))
+	assert.True(t, IsKeyed("go-mod-v1"))
+}
+
+func TestParseHashFilesArgs(t *testing.T) {
+	args, err := parseHashFilesArgs(`'go.sum', "**/package-lock.json"`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go.sum", "**/package-lock.json"}, args)
+
+	_, err = parseHashFilesArgs(`go.sum`)
+	assert.Error(t, err)
+	_, err = parseHashFilesArgs(`'go.sum`)
+	assert.Error(t, err)
+	_, err = parseHashFilesArgs(`'a' 'b'`)
+	assert.Error(t, err)
+	_, err = parseHashFilesArgs(``)
+	assert.Error(t, err)
+}
+
+func TestValidateKey(t *testing.T) {
+	assert.NoError(t, ValidateKey("go-${{ hashFiles('go.sum', '**/go.sum') }}"))
+	assert.NoError(t, ValidateKey("static"))
+	assert.Error(t, ValidateKey("go-${{ hashFiles(go.sum) }}"))
+	assert.Error(t, ValidateKey("go-hashFiles('go.sum')"))
+}
+
+func TestHashFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hashfiles")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"go.sum":                         "a",
+		"web/package-lock.json":          "b",
+		"web/sub/package-lock.json":      "c",
+		"vendor/x/package-lock.json":     "d",
+		".git/objects/package-lock.json": "e",
+	})
+
+	h1, err := HashFiles(dir, "go.sum")
+	assert.NoError(t, err)
+	assert.Len(t, h1, 64)
+
+	all, err := HashFiles(dir, "**/package-lock.json")
+	assert.NoError(t, err)
+	excluded, err := HashFiles(dir, "**/package-lock.json", "!vendor/**")
+	assert.NoError(t, err)
+	assert.NotEqual(t, all, excluded)
+
+	abs, err := HashFiles(dir, filepath.Join(dir, "go.sum"))
+	assert.NoError(t, err)
+	assert.Equal(t, h1, abs)
+
+	none, err := HashFiles(dir, "not-exist.lock")
+	assert.NoError(t, err)
+	assert.Equal(t, "", none)
+
+	_, err = HashFiles(dir, "/other/go.sum")
+	assert.Error(t, err)
+
+	// content changed, hash changed
+	writeFiles(t, dir, map[string]string{"go.sum": "changed"})
+	h2, err := HashFiles(dir, "go.sum")
+	assert.NoError(t, err)
+	assert.NotEqual(t, h1, h2)
+}
+
+func TestRenderKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "renderkey")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{"go.sum": "a"})
+
+	hash, err := HashFiles(dir, "go.sum")
+	assert.NoError(t, err)
+	key, err := RenderKey("go mod/${{ hashFiles('go.sum') }}", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "go-mod-"+hash, key)
+
+	_, err = RenderKey("go-${{ hashFiles(go.sum) }}", dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actioncache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// EntriesDirName is the sub dir of task cache dir where keyed entries placed
+	EntriesDirName = "keys"
+	TarSuffix      = ".tar"
+	MetaSuffix     = ".meta.json"
+	// TmpSuffix is the suffix of files being written, they are renamed to final path after finished
+	TmpSuffix = ".tmp"
+)
+
+// Entry is the metadata of a keyed cache, stored beside the tar file.
+type Entry struct {
+	Key        string    `json:"key"`
+	SizeBytes  int64     `json:"sizeBytes"`
+	TTLSeconds int64     `json:"ttlSeconds,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// Expired return whether entry is not used within ttl.
+// Entry without ttl uses defaultTTL, non-positive ttl means never expire.
+func (e Entry) Expired(now time.Time, defaultTTL time.Duration) bool {
+	ttl := time.Duration(e.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	if ttl <= 0 {
+		return false
+	}
+	return now.Sub(e.LastUsedAt) > ttl
+}
+
+func EntriesDir(cacheDir string) string {
+	return filepath.Join(cacheDir, EntriesDirName)
+}
+
+func TarPath(cacheDir, key string) string {
+	return filepath.Join(EntriesDir(cacheDir), key+TarSuffix)
+}
+
+func MetaPath(cacheDir, key string) string {
+	return filepath.Join(EntriesDir(cacheDir), key+MetaSuffix)
+}
+
+// ReadEntry read entry metadata of key, return nil if not exist.
+func ReadEntry(cacheDir, key string) (*Entry, error) {
+	b, err := ioutil.ReadFile(MetaPath(cacheDir, key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// WriteEntry write entry metadata atomically.
+func WriteEntry(cacheDir string, e Entry) error {
+	if err := os.MkdirAll(EntriesDir(cacheDir), 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	metaPath := MetaPath(cacheDir, e.Key)
+	tmp := metaPath + TmpSuffix
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, metaPath)
+}
+
+// ListEntries list all entries which have both metadata and tar file.
+func ListEntries(cacheDir string) ([]Entry, error) {
+	files, err := ioutil.ReadDir(EntriesDir(cacheDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []Entry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), MetaSuffix) {
+			continue
+		}
+		e, err := ReadEntry(cacheDir, strings.TrimSuffix(f.Name(), MetaSuffix))
+		if err != nil || e == nil {
+			continue
+		}
+		if _, err := os.Stat(TarPath(cacheDir, e.Key)); err != nil {
+			continue
+		}
+		entries = append(entries, *e)
+	}
+	return entries, nil
+}
+
+// Lookup find the entry to restore.
+// Exact key hit first, otherwise the newest entry matched by restore key prefixes in order.
+func Lookup(cacheDir, key string, restoreKeys []string, now time.Time) (entry *Entry, exact bool, err error) {
+	e, err := ReadEntry(cacheDir, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if e != nil && !e.Expired(now, 0) {
+		if _, err := os.Stat(TarPath(cacheDir, key)); err == nil {
+			return e, true, nil
+		}
+	}
+	if len(restoreKeys) == 0 {
+		return nil, false, nil
+	}
+	entries, err := ListEntries(cacheDir)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, prefix := range restoreKeys {
+		if prefix == "" {
+			continue
+		}
+		var newest *Entry
+		for i := range entries {
+			candidate := entries[i]
+			if !strings.HasPrefix(candidate.Key, prefix) || candidate.Expired(now, 0) {
+				continue
+			}
+			if newest == nil || candidate.CreatedAt.After(newest.CreatedAt) {
+				newest = &candidate
+			}
+		}
+		if newest != nil {
+			return newest, false, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// Touch update last used time of entry to delay expiration.
+func Touch(cacheDir string, e Entry, now time.Time) error {
+	e.LastUsedAt = now
+	return WriteEntry(cacheDir, e)
+}
+
+// RemoveEntry remove tar and metadata of key.
+func RemoveEntry(cacheDir, key string) error {
+	if err := os.Remove(TarPath(cacheDir, key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(MetaPath(cacheDir, key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GC remove expired entries under cacheDir, and tar or temp files without metadata not modified within defaultTTL.
+func GC(cacheDir string, now time.Time, defaultTTL time.Duration) ([]string, error) {
+	files, err := ioutil.ReadDir(EntriesDir(cacheDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var removed []string
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		var key string
+		switch {
+		case strings.HasSuffix(f.Name(), MetaSuffix):
+			key = strings.TrimSuffix(f.Name(), MetaSuffix)
+			e, err := ReadEntry(cacheDir, key)
+			if err == nil && e != nil && !e.Expired(now, defaultTTL) {
+				continue
+			}
+		case strings.HasSuffix(f.Name(), TarSuffix):
+			key = strings.TrimSuffix(f.Name(), TarSuffix)
+			if _, err := os.Stat(MetaPath(cacheDir, key)); err == nil {
+				continue
+			}
+			if defaultTTL <= 0 || now.Sub(f.ModTime()) <= defaultTTL {
+				continue
+			}
+		case strings.HasSuffix(f.Name(), TmpSuffix):
+			// 写了一半的临时文件，例如 agent 在写入过程中退出
+			if defaultTTL <= 0 || now.Sub(f.ModTime()) <= defaultTTL {
+				continue
+			}
+			tmpPath := filepath.Join(EntriesDir(cacheDir), f.Name())
+			if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+				return removed, err
+			}
+			removed = append(removed, tmpPath)
+			continue
+		default:
+			continue
+		}
+		if err := RemoveEntry(cacheDir, key); err != nil {
+			return removed, err
+		}
+		removed = append(removed, filepath.Join(EntriesDir(cacheDir), key))
+	}
+	return removed, nil
+}
+
+// GCRoot walk rootDir and gc every keyed cache dir under it.
+func GCRoot(rootDir string, now time.Time, defaultTTL time.Duration) ([]string, error) {
+	var removed []string
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() || info.Name() != EntriesDirName {
+			return nil
+		}
+		r, err := GC(filepath.Dir(path), now, defaultTTL)
+		removed = append(removed, r...)
+		if err != nil {
+			return err
+		}
+		return filepath.SkipDir
+	})
+	return removed, err
+}
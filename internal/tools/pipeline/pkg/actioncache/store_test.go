@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actioncache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func putEntry(t *testing.T, cacheDir string, e Entry) {
+	assert.NoError(t, os.MkdirAll(EntriesDir(cacheDir), 0755))
+	assert.NoError(t, ioutil.WriteFile(TarPath(cacheDir, e.Key), []byte("tar"), 0644))
+	assert.NoError(t, WriteEntry(cacheDir, e))
+}
+
+func TestLookup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	putEntry(t, dir, Entry{Key: "go-linux-old", CreatedAt: now.Add(-2 * time.Hour), LastUsedAt: now.Add(-2 * time.Hour)})
+	putEntry(t, dir, Entry{Key: "go-linux-new", CreatedAt: now.Add(-time.Hour), LastUsedAt: now.Add(-time.Hour)})
+	putEntry(t, dir, Entry{Key: "go-linux-expired", TTLSeconds: 60, CreatedAt: now, LastUsedAt: now.Add(-time.Hour)})
+
+	// exact hit
+	e, exact, err := Lookup(dir, "go-linux-old", []string{"go-"}, now)
+	assert.NoError(t, err)
+	assert.True(t, exact)
+	assert.Equal(t, "go-linux-old", e.Key)
+
+	// fallback to newest matched by prefix, expired ignored
+	e, exact, err = Lookup(dir, "go-linux-abc", []string{"npm-", "go-linux-"}, now)
+	assert.NoError(t, err)
+	assert.False(t, exact)
+	assert.Equal(t, "go-linux-new", e.Key)
+
+	// expired exact key is a miss
+	e, exact, err = Lookup(dir, "go-linux-expired", nil, now)
+	assert.NoError(t, err)
+	assert.False(t, exact)
+	assert.Nil(t, e)
+
+	e, _, err = Lookup(dir, "npm-abc", []string{"npm-"}, now)
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+}
+
+func TestTouch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "touch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	putEntry(t, dir, Entry{Key: "k", TTLSeconds: 60, CreatedAt: now.Add(-time.Hour), LastUsedAt: now.Add(-50 * time.Second)})
+	e, _, err := Lookup(dir, "k", nil, now)
+	assert.NoError(t, err)
+	assert.NoError(t, Touch(dir, *e, now))
+	_, exact, err := Lookup(dir, "k", nil, now.Add(30*time.Second))
+	assert.NoError(t, err)
+	assert.True(t, exact)
+}
+
+func TestGCRoot(t *testing.T) {
+	root, err := ioutil.TempDir("", "gc")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	now := time.Now()
+	cacheDir := filepath.Join(root, "1", "2", "hash")
+	putEntry(t, cacheDir, Entry{Key: "keep", CreatedAt: now, LastUsedAt: now})
+	putEntry(t, cacheDir, Entry{Key: "expired-default", CreatedAt: now, LastUsedAt: now.Add(-48 * time.Hour)})
+	putEntry(t, cacheDir, Entry{Key: "expired-own", TTLSeconds: 60, CreatedAt: now, LastUsedAt: now.Add(-time.Hour)})
+	orphan := TarPath(cacheDir, "orphan")
+	assert.NoError(t, ioutil.WriteFile(orphan, []byte("tar"), 0644))
+	assert.NoError(t, os.Chtimes(orphan, now.Add(-48*time.Hour), now.Add(-48*time.Hour)))
+	staleTmp := filepath.Join(EntriesDir(cacheDir), "stale-123"+TmpSuffix)
+	assert.NoError(t, ioutil.WriteFile(staleTmp, []byte("ta"), 0644))
+	assert.NoError(t, os.Chtimes(staleTmp, now.Add(-48*time.Hour), now.Add(-48*time.Hour)))
+	writingTmp := filepath.Join(EntriesDir(cacheDir), "writing-123"+TmpSuffix)
+	assert.NoError(t, ioutil.WriteFile(writingTmp, []byte("ta"), 0644))
+
+	removed, err := GCRoot(root, now, 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, removed, 4)
+	_, err = os.Stat(staleTmp)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(writingTmp)
+	assert.NoError(t, err)
+
+	entries, err := ListEntries(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "keep", entries[0].Key)
+	_, err = os.Stat(orphan)
+	assert.True(t, os.IsNotExist(err))
+
+	removed, err = GCRoot(filepath.Join(root, "not-exist"), now, time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, removed, 0)
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package approval implements the manual approval gate, which pauses a pipeline
+// until designated users or roles approve or reject it.
+package approval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Outcome string
+
+const (
+	OutcomePending  Outcome = "pending"
+	OutcomeApproved Outcome = "approved"
+	OutcomeRejected Outcome = "rejected"
+)
+
+// action params of manual-approval
+const (
+	ParamApprovers         = "approvers"
+	ParamApproverRoles     = "approver_roles"
+	ParamRequiredApprovals = "required_approvals"
+	ParamTimeoutSec        = "timeout_sec"
+	ParamTimeoutOutcome    = "timeout_outcome"
+	ParamMessage           = "message"
+)
+
+// Config is the approval config declared by action params.
+type Config struct {
+	Approvers         []string
+	ApproverRoles     []string
+	RequiredApprovals int
+	// Timeout <= 0 means wait until decided or task timeout
+	Timeout        time.Duration
+	TimeoutOutcome Outcome
+	Message        string
+}
+
+// Record is one approve or reject decision with comment.
+type Record struct {
+	UserID   string    `json:"userID"`
+	Decision Outcome   `json:"decision"`
+	Comment  string    `json:"comment,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// State is stored on task extra, keeps the comment trail of approval.
+type State struct {
+	Records []Record `json:"records,omitempty"`
+}
+
+func (o Outcome) Valid() bool {
+	return o == OutcomeApproved || o == OutcomeRejected
+}
+
+// ParseConfig parse approval config from action params.
+func ParseConfig(params map[string]interface{}) (*Config, error) {
+	cfg := &Config{
+		Approvers:         parseList(params[ParamApprovers]),
+		ApproverRoles:     parseList(params[ParamApproverRoles]),
+		RequiredApprovals: 1,
+		TimeoutOutcome:    OutcomeRejected,
+	}
+	if len(cfg.Approvers) == 0 && len(cfg.ApproverRoles) == 0 {
+		return nil, fmt.Errorf("at least one of %s and %s should be specified", ParamApprovers, ParamApproverRoles)
+	}
+	if v, ok := params[ParamRequiredApprovals]; ok {
+		n, err := parseInt(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid %s: %v", ParamRequiredApprovals, v)
+		}
+		cfg.RequiredApprovals = n
+	}
+	if len(cfg.ApproverRoles) == 0 && cfg.RequiredApprovals > len(cfg.Approvers) {
+		return nil, fmt.Errorf("%s (%d) is greater than the number of approvers (%d)",
+			ParamRequiredApprovals, cfg.RequiredApprovals, len(cfg.Approvers))
+	}
+	if v, ok := params[ParamTimeoutSec]; ok {
+		sec, err := parseInt(v)
+		if err != nil || sec < 0 {
+			return nil, fmt.Errorf("invalid %s: %v", ParamTimeoutSec, v)
+		}
+		cfg.Timeout = time.Duration(sec) * time.Second
+	}
+	if v, ok := params[ParamTimeoutOutcome]; ok && fmt.Sprintf("%v", v) != "" {
+		outcome := Outcome(strings.ToLower(fmt.Sprintf("%v", v)))
+		if !outcome.Valid() {
+			return nil, fmt.Errorf("invalid %s: %v, should be %s or %s", ParamTimeoutOutcome, v, OutcomeApproved, OutcomeRejected)
+		}
+		cfg.TimeoutOutcome = outcome
+	}
+	if v, ok := params[ParamMessage]; ok {
+		cfg.Message = fmt.Sprintf("%v", v)
+	}
+	return cfg, nil
+}
+
+// CanApprove return whether user is a designated approver or has one of approver roles.
+func (cfg *Config) CanApprove(userID string, roles []string) bool {
+	for _, approver := range cfg.Approvers {
+		if approver == userID {
+			return true
+		}
+	}
+	for _, role := range roles {
+		for _, approverRole := range cfg.ApproverRoles {
+			if strings.EqualFold(role, approverRole) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Add append a decision to comment trail, each user can only decide once.
+func (s *State) Add(r Record) error {
+	if !r.Decision.Valid() {
+		return fmt.Errorf("invalid decision: %s, should be %s or %s", r.Decision, OutcomeApproved, OutcomeRejected)
+	}
+	for _, record := range s.Records {
+		if record.UserID == r.UserID {
+			return fmt.Errorf("user %s already %s", r.UserID, record.Decision)
+		}
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	s.Records = append(s.Records, r)
+	return nil
+}
+
+// Evaluate return the outcome of approval and the reason when decided.
+// Any rejection rejects the approval; otherwise it is approved once enough users approved.
+// If nothing decided before timeout, the default outcome is used.
+func Evaluate(cfg *Config, state *State, begin, now time.Time) (Outcome, string) {
+	var approvals int
+	if state != nil {
+		for _, record := range state.Records {
+			switch record.Decision {
+			case OutcomeRejected:
+				return OutcomeRejected, withComment(fmt.Sprintf("rejected by %s", record.UserID), record.Comment)
+			case OutcomeApproved:
+				approvals++
+			}
+		}
+	}
+	if approvals >= cfg.RequiredApprovals {
+		return OutcomeApproved, fmt.Sprintf("approved by %d user(s)", approvals)
+	}
+	if cfg.Timeout > 0 && !begin.IsZero() && !now.Before(begin.Add(cfg.Timeout)) {
+		return cfg.TimeoutOutcome, fmt.Sprintf("approval timed out after %s (%d/%d approvals), default outcome: %s",
+			cfg.Timeout, approvals, cfg.RequiredApprovals, cfg.TimeoutOutcome)
+	}
+	return OutcomePending, ""
+}
+
+func withComment(msg, comment string) string {
+	if comment == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", msg, comment)
+}
+
+// parseList support both yaml list and comma separated string
+func parseList(v interface{}) []string {
+	var items []string
+	switch vv := v.(type) {
+	case nil:
+		return nil
+	case []string:
+		items = vv
+	case []interface{}:
+		for _, item := range vv {
+			items = append(items, toString(item))
+		}
+	default:
+		items = strings.Split(fmt.Sprintf("%v", vv), ",")
+	}
+	var result []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// toString avoid user id like 1000001 being formatted as 1e+06
+func toString(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func parseInt(v interface{}) (int, error) {
+	switch vv := v.(type) {
+	case int:
+		return vv, nil
+	case int64:
+		return int(vv), nil
+	case uint64:
+		return int(vv), nil
+	case float64:
+		return int(vv), nil
+	default:
+		return strconv.Atoi(strings.TrimSpace(fmt.Sprintf("%v", vv)))
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(map[string]interface{}{
+		ParamApprovers:         []interface{}{1000001, "u2", float64(1000003)},
+		ParamRequiredApprovals: "2",
+		ParamTimeoutSec:        3600,
+		ParamTimeoutOutcome:    "Approved",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1000001", "u2", "1000003"}, cfg.Approvers)
+	assert.Equal(t, 2, cfg.RequiredApprovals)
+	assert.Equal(t, time.Hour, cfg.Timeout)
+	assert.Equal(t, OutcomeApproved, cfg.TimeoutOutcome)
+
+	cfg, err = ParseConfig(map[string]interface{}{ParamApproverRoles: "Owner, Lead"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Owner", "Lead"}, cfg.ApproverRoles)
+	assert.Equal(t, 1, cfg.RequiredApprovals)
+	assert.Equal(t, OutcomeRejected, cfg.TimeoutOutcome)
+
+	invalids := []map[string]interface{}{
+		{},
+		{ParamApprovers: "u1", ParamRequiredApprovals: 2},
+		{ParamApprovers: "u1", ParamRequiredApprovals: 0},
+		{ParamApprovers: "u1", ParamTimeoutSec: "-1"},
+		{ParamApprovers: "u1", ParamTimeoutOutcome: "skip"},
+	}
+	for _, params := range invalids {
+		_, err := ParseConfig(params)
+		assert.Error(t, err, params)
+	}
+}
+
+func TestConfig_CanApprove(t *testing.T) {
+	cfg := &Config{Approvers: []string{"u1"}, ApproverRoles: []string{"Owner"}}
+	assert.True(t, cfg.CanApprove("u1", nil))
+	assert.True(t, cfg.CanApprove("u2", []string{"Dev", "owner"}))
+	assert.False(t, cfg.CanApprove("u2", []string{"Dev"}))
+}
+
+func TestState_Add(t *testing.T) {
+	var s State
+	assert.NoError(t, s.Add(Record{UserID: "u1", Decision: OutcomeApproved}))
+	assert.False(t, s.Records[0].Time.IsZero())
+	assert.Error(t, s.Add(Record{UserID: "u1", Decision: OutcomeRejected}))
+	assert.Error(t, s.Add(Record{UserID: "u2", Decision: OutcomePending}))
+	assert.Len(t, s.Records, 1)
+}
+
+func TestEvaluate(t *testing.T) {
+	begin := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	cfg := &Config{Approvers: []string{"u1", "u2", "u3"}, RequiredApprovals: 2, Timeout: time.Hour, TimeoutOutcome: OutcomeRejected}
+
+	outcome, _ := Evaluate(cfg, nil, begin, begin.Add(time.Minute))
+	assert.Equal(t, OutcomePending, outcome)
+
+	state := &State{Records: []Record{{UserID: "u1", Decision: OutcomeApproved}}}
+	outcome, _ = Evaluate(cfg, state, begin, begin.Add(time.Minute))
+	assert.Equal(t, OutcomePending, outcome)
+
+	state.Records = append(state.Records, Record{UserID: "u2", Decision: OutcomeApproved})
+	outcome, reason := Evaluate(cfg, state, begin, begin.Add(time.Minute))
+	assert.Equal(t, OutcomeApproved, outcome)
+	assert.Equal(t, "approved by 2 user(s)", reason)
+
+	// any rejection wins
+	state.Records = append(state.Records, Record{UserID: "u3", Decision: OutcomeRejected, Comment: "not now"})
+	outcome, reason = Evaluate(cfg, state, begin, begin.Add(time.Minute))
+	assert.Equal(t, OutcomeRejected, outcome)
+	assert.Equal(t, "rejected by u3: not now", reason)
+
+	// timeout uses default outcome
+	state = &State{Records: []Record{{UserID: "u1", Decision: OutcomeApproved}}}
+	outcome, _ = Evaluate(cfg, state, begin, begin.Add(time.Hour))
+	assert.Equal(t, OutcomeRejected, outcome)
+	cfg.TimeoutOutcome = OutcomeApproved
+	outcome, _ = Evaluate(cfg, state, begin, begin.Add(2*time.Hour))
+	assert.Equal(t, OutcomeApproved, outcome)
+
+	// no approval timeout
+	cfg.Timeout = 0
+	outcome, _ = Evaluate(cfg, state, begin, begin.Add(24*time.Hour))
+	assert.Equal(t, OutcomePending, outcome)
+}
@@ -15,6 +15,8 @@
 package env
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 )
 
@@ -29,7 +31,8 @@ const (
 )
 
 const (
-	EnvPipelineSecretPrefix = "PIPELINE_SECRET_"
+	EnvPipelineSecretPrefix         = "PIPELINE_SECRET_"
+	EnvPipelineExternalSecretPrefix = "PIPELINE_EXTERNAL_SECRET_"
 )
 
 func GenEnvKeyWithPrefix(envPrefix string, key string) string {
@@ -39,3 +42,10 @@ func GenEnvKeyWithPrefix(envPrefix string, key string) string {
 func GenEnvKey(key string) string {
 	return GenEnvKeyWithPrefix("", key)
 }
+
+// GenExternalSecretEnvKey generate env key for external secret reference, like vault://path#key.
+// Reference contains characters not allowed in env key, so use its hash instead.
+func GenExternalSecretEnvKey(ref string) string {
+	sum := sha256.Sum256([]byte(ref))
+	return EnvPipelineExternalSecretPrefix + strings.ToUpper(hex.EncodeToString(sum[:8]))
+}
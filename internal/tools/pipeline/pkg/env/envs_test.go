@@ -49,3 +49,16 @@ func TestGenPipelineEnvKey(t *testing.T) {
 		})
 	}
 }
+
+func TestGenExternalSecretEnvKey(t *testing.T) {
+	key := GenExternalSecretEnvKey("vault://kv/app#password")
+	if key != GenExternalSecretEnvKey("vault://kv/app#password") {
+		t.Errorf("GenExternalSecretEnvKey() should be stable")
+	}
+	if key == GenExternalSecretEnvKey("vault://kv/app#username") {
+		t.Errorf("GenExternalSecretEnvKey() should be different for different reference")
+	}
+	if len(key) != len(EnvPipelineExternalSecretPrefix)+16 {
+		t.Errorf("GenExternalSecretEnvKey() = %v, unexpected length", key)
+	}
+}
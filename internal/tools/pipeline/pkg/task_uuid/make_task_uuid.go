@@ -19,31 +19,55 @@ import (
 
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/strutil"
 )
 
 func MakeJobIDSliceWithLoopedTimes(action *spec.PipelineTask) []string {
 	var JobIDSlice []string
+	// jobs of failed attempts retried by retry policy
+	for _, attempt := range action.Extra.RetryAttempts {
+		if attempt.UUID != "" {
+			JobIDSlice = append(JobIDSlice, attempt.UUID)
+		}
+	}
 	if isLoop(action) {
 		for i := apistructs.TaskLoopTimeBegin; i <= int(action.Extra.LoopOptions.LoopedTimes); i++ {
-			JobIDSlice = append(JobIDSlice, parseUUID(action.Extra.UUID, i))
+			loopUUID := parseUUID(action.Extra.UUID, i)
+			JobIDSlice = append(JobIDSlice, loopUUID)
+			for j := 1; j <= len(action.Extra.RetryAttempts); j++ {
+				JobIDSlice = append(JobIDSlice, parseRetryUUID(loopUUID, j))
+			}
 		}
-		return JobIDSlice
+		return strutil.DedupSlice(JobIDSlice)
 	}
-	JobIDSlice = append(JobIDSlice, action.Extra.UUID)
-	return JobIDSlice
+	JobIDSlice = append(JobIDSlice, MakeJobID(action))
+	return strutil.DedupSlice(JobIDSlice)
 }
 
 func parseUUID(uuid string, index int) string {
 	return fmt.Sprintf("%s-loop-%d", uuid, index)
 }
 
+func parseRetryUUID(uuid string, retriedTimes int) string {
+	return fmt.Sprintf("%s-retry-%d", uuid, retriedTimes)
+}
+
 func MakeJobID(action *spec.PipelineTask) string {
+	uuid := action.Extra.UUID
 	if isLoop(action) {
-		return parseUUID(action.Extra.UUID, int(action.Extra.LoopOptions.LoopedTimes))
+		uuid = parseUUID(uuid, int(action.Extra.LoopOptions.LoopedTimes))
 	}
-	return action.Extra.UUID
+	// each retry uses a new job to avoid conflicting with the failed one
+	if isRetried(action) {
+		uuid = parseRetryUUID(uuid, len(action.Extra.RetryAttempts))
+	}
+	return uuid
 }
 
 func isLoop(action *spec.PipelineTask) bool {
 	return action.Extra.LoopOptions != nil && action.Extra.LoopOptions.CalculatedLoop != nil && action.Extra.LoopOptions.CalculatedLoop.Strategy.MaxTimes > 0
 }
+
+func isRetried(action *spec.PipelineTask) bool {
+	return len(action.Extra.RetryAttempts) > 0
+}
@@ -75,6 +75,12 @@ func (r *release) Check(ctx context.Context, data interface{}, itemsForCheck pre
 		messages = append(messages, fmt.Sprintf("failed to parse dice.yml, err: %v", err))
 		return
 	}
+	if checkDiceYml {
+		// warnings don't abort pipeline
+		for _, warning := range d.Warnings() {
+			messages = append(messages, fmt.Sprintf("dice.yml warning: %s", warning))
+		}
+	}
 	// check images
 	if actualAction.Params != nil {
 		// param: image
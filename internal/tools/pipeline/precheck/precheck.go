@@ -21,12 +21,14 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	basepb "github.com/erda-project/erda-proto-go/core/pipeline/base/pb"
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/pvolumes"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/actioncache"
 	"github.com/erda-project/erda/internal/tools/pipeline/precheck/checkers/actionchecker/api_register"
 	"github.com/erda-project/erda/internal/tools/pipeline/precheck/checkers/actionchecker/buildpack"
 	"github.com/erda-project/erda/internal/tools/pipeline/precheck/checkers/actionchecker/release"
@@ -211,6 +213,11 @@ func CheckCaches(actualAction *pipelineyml.Action, labels map[string]string) []s
 			continue
 		}
 
+		if actioncache.IsKeyed(v.Key) {
+			checkResults = append(checkResults, checkKeyedCache(actualAction, v)...)
+			continue
+		}
+
 		if v.Key != "" {
 
 			v.Key = strings.ReplaceAll(v.Key, " ", "")
@@ -231,6 +238,28 @@ func CheckCaches(actualAction *pipelineyml.Action, labels map[string]string) []s
 	return checkResults
 }
 
+// checkKeyedCache check key expression, restore keys, max size and ttl of keyed cache
+func checkKeyedCache(actualAction *pipelineyml.Action, cache pipelineyml.ActionCache) []string {
+	var checkResults []string
+	for _, key := range append([]string{cache.Key}, cache.RestoreKeys...) {
+		if err := actioncache.ValidateKey(key); err != nil {
+			checkResults = append(checkResults, fmt.Sprintf("taskName: %s, cache key: %s error: %v ",
+				actualAction.Alias, key, err))
+		}
+	}
+	if cache.MaxSizeMB < 0 {
+		checkResults = append(checkResults, fmt.Sprintf("taskName: %s, cache path: %s error: %s ",
+			actualAction.Alias, cache.Path, "max_size_mb can not be negative"))
+	}
+	if cache.TTL != "" {
+		if ttl, err := time.ParseDuration(cache.TTL); err != nil || ttl <= 0 {
+			checkResults = append(checkResults, fmt.Sprintf("taskName: %s, cache path: %s error: %s ",
+				actualAction.Alias, cache.Path, "invalid ttl "+cache.TTL+", should be positive duration like 168h"))
+		}
+	}
+	return checkResults
+}
+
 func checkRequiredParams(actualAction pipelineyml.Action, actionSpec apistructs.ActionSpec) []string {
 	checkResults := make([]string, 0)
 
@@ -21,6 +21,7 @@ import (
 
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/precheck/prechecktype"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
 )
 
 func TestPrecheck(t *testing.T) {
@@ -66,3 +67,20 @@ services:
 	_, _ = PreCheck(ctx, yamlByte, items)
 	assert.False(t, prechecktype.GetContextResult(ctx, prechecktype.CtxResultKeyCrossCluster).(bool))
 }
+
+func TestCheckCaches(t *testing.T) {
+	labels := map[string]string{apistructs.LabelProjectID: "1", apistructs.LabelAppID: "2"}
+	action := &pipelineyml.Action{
+		Alias: "build",
+		Caches: []pipelineyml.ActionCache{
+			{Path: "/root/go/pkg/mod", Key: "go-${{ hashFiles('**/go.sum') }}", RestoreKeys: []string{"go-"}, TTL: "168h"},
+			{Path: "/root/.m2", Key: "{{basePath}}/m2/{{endPath}}"},
+		},
+	}
+	assert.Empty(t, CheckCaches(action, labels))
+
+	action.Caches = []pipelineyml.ActionCache{
+		{Path: "/root/go/pkg/mod", Key: "go-${{ hashFiles(go.sum) }}", MaxSizeMB: -1, TTL: "7d"},
+	}
+	assert.Len(t, CheckCaches(action, labels), 3)
+}
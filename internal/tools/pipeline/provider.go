@@ -45,6 +45,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/leaderworker"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/permission"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipeline"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/reconciler"
 	reportsvc "github.com/erda-project/erda/internal/tools/pipeline/providers/report"
@@ -87,6 +88,7 @@ type provider struct {
 	ActionAgent  actionagent.Interface
 	Permission   permission.Interface
 	PipelineSvc  pipeline.Interface
+	Trigger      pipelinetrigger.Interface
 }
 
 func (p *provider) Run(ctx context.Context) error {
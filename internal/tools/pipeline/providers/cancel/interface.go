@@ -28,6 +28,7 @@ import (
 type Interface interface {
 	CancelOnePipeline(ctx context.Context, req *pb.PipelineCancelRequest) error
 	StopRelatedRunningPipelinesOfOnePipeline(ctx context.Context, p *spec.Pipeline, identityInfo *commonpb.IdentityInfo) error
+	CancelInProgressPipelinesOfConcurrencyGroup(ctx context.Context, p *spec.Pipeline, identityInfo *commonpb.IdentityInfo) error
 }
 
 func (s *provider) CancelOnePipeline(ctx context.Context, req *pb.PipelineCancelRequest) error {
@@ -80,3 +81,46 @@ func (s *provider) StopRelatedRunningPipelinesOfOnePipeline(ctx context.Context,
 	}
 	return nil
 }
+
+// CancelInProgressPipelinesOfConcurrencyGroup cancel other running pipelines in the same concurrency group
+func (s *provider) CancelInProgressPipelinesOfConcurrencyGroup(ctx context.Context, p *spec.Pipeline, identityInfo *commonpb.IdentityInfo) error {
+	groupKey, ok := p.GetConcurrencyGroupKey()
+	if !ok {
+		return nil
+	}
+	groupPipelineIDs, err := s.dbClient.SelectTargetIDsByLabels(apistructs.TargetIDSelectByLabelRequest{
+		Type:            apistructs.PipelineLabelTypeInstance,
+		PipelineSources: []apistructs.PipelineSource{p.PipelineSource},
+		MustMatchLabels: map[string][]string{apistructs.LabelPipelineConcurrencyGroup: {groupKey}},
+	})
+	if err != nil {
+		return apierrors.ErrParallelRunPipeline.InternalError(err)
+	}
+	var otherPipelineIDs []uint64
+	for _, pipelineID := range groupPipelineIDs {
+		if pipelineID != p.ID {
+			otherPipelineIDs = append(otherPipelineIDs, pipelineID)
+		}
+	}
+	if len(otherPipelineIDs) == 0 {
+		return nil
+	}
+	var runningPipelineIDs []uint64
+	err = s.dbClient.Table(&spec.PipelineBase{}).
+		Select("id").In("id", otherPipelineIDs).In("status", apistructs.ReconcilerRunningStatuses()).
+		Where("is_snippet = ?", false).
+		Find(&runningPipelineIDs)
+	if err != nil {
+		return apierrors.ErrParallelRunPipeline.InternalError(err)
+	}
+	for _, runningPipelineID := range runningPipelineIDs {
+		if err := s.CancelOnePipeline(ctx, &pb.PipelineCancelRequest{
+			PipelineID:     runningPipelineID,
+			UserID:         identityInfo.UserID,
+			InternalClient: identityInfo.InternalClient,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -237,6 +237,7 @@ func (p *provider) cronInterruptCompensate(ctx context.Context, pc db.PipelineCr
 	needTriggerTimes, err := pipelineyml.ListNextCronTime(pc.CronExpr,
 		pipelineyml.WithCronStartEndTime(&beforeCompensateFromTime, &thisCompensateFromTime),
 		pipelineyml.WithListNextScheduleCount(100),
+		pipelineyml.WithCronTimezone(pc.CronTimezone),
 	)
 	if err != nil {
 		return errors.Errorf("[alert] failed to list next crontimes, cronID: %d, err: %v", pc.ID, err)
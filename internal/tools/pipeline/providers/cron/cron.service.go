@@ -30,6 +30,7 @@ import (
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/cron/db"
 	"github.com/erda-project/erda/internal/tools/pipeline/services/apierrors"
+	pkgcron "github.com/erda-project/erda/pkg/cron"
 	"github.com/erda-project/erda/pkg/parser/pipelineyml"
 	"github.com/erda-project/erda/pkg/strutil"
 )
@@ -61,12 +62,21 @@ func (s *provider) CronCreate(ctx context.Context, req *pb.CronCreateRequest) (*
 	if req.CronExpr == "" {
 		req.CronExpr = pipelineYml.Spec().Cron
 	}
+	if req.CronTimezone == "" {
+		req.CronTimezone = pipelineYml.Spec().CronTimezone
+	}
+	if req.CronTimezone != "" {
+		if _, err := pkgcron.LoadLocation(req.CronTimezone); err != nil {
+			return nil, apierrors.ErrCreatePipelineCron.InvalidParameter(err)
+		}
+	}
 
 	createCron := &db.PipelineCron{
 		ID:              req.ID,
 		PipelineSource:  apistructs.PipelineSource(req.PipelineSource),
 		PipelineYmlName: req.PipelineYmlName,
 		CronExpr:        req.CronExpr,
+		CronTimezone:    req.CronTimezone,
 		Enable:          &[]bool{req.Enable.Value}[0],
 		Extra: db.PipelineCronExtra{
 			PipelineYml:            req.PipelineYml,
@@ -346,10 +356,16 @@ func (s *provider) CronUpdate(ctx context.Context, req *pb.CronUpdateRequest) (*
 		}
 	}
 	cron.CronExpr = req.CronExpr
+	cron.CronTimezone = getUpdatedCronTimezone(cron.CronTimezone, req.CronTimezone, pipeline.Spec().CronTimezone)
+	if cron.CronTimezone != "" {
+		if _, err := pkgcron.LoadLocation(cron.CronTimezone); err != nil {
+			return nil, apierrors.ErrUpdatePipelineCron.InvalidParameter(err)
+		}
+	}
 	cron.Extra.PipelineYml = req.PipelineYml
 	cron.Extra.ConfigManageNamespaces = strutil.DedupSlice(append(cron.Extra.ConfigManageNamespaces, req.ConfigManageNamespaces...), true)
 	cron.Extra.IncomingSecrets = req.Secrets
-	var fields = []string{db.PipelineCronCronExpr, db.Extra}
+	var fields = []string{db.PipelineCronCronExpr, db.PipelineCronTimezone, db.Extra}
 	if req.PipelineDefinitionID != "" {
 		cron.PipelineDefinitionID = req.PipelineDefinitionID
 		fields = append(fields, db.PipelineDefinitionID)
@@ -364,6 +380,18 @@ func (s *provider) CronUpdate(ctx context.Context, req *pb.CronUpdateRequest) (*
 	return &pb.CronUpdateResponse{}, nil
 }
 
+// getUpdatedCronTimezone return timezone by priority: request > pipeline yml > stored value,
+// so the stored timezone is kept when it's omitted in update request.
+func getUpdatedCronTimezone(stored, fromReq, fromYml string) string {
+	if fromReq != "" {
+		return fromReq
+	}
+	if fromYml != "" {
+		return fromYml
+	}
+	return stored
+}
+
 func (s *provider) update(req *pb.CronUpdateRequest, cron db.PipelineCron, fields []string, option mysqlxorm.SessionOption) error {
 	toEdge := s.EdgePipelineRegister.CanProxyToEdge(cron.PipelineSource, cron.Extra.ClusterName)
 
@@ -529,6 +557,7 @@ func (p *provider) EdgePipelineEventHandler(ctx context.Context, eventDetail api
 		}
 		_, err := p.CronCreate(ctx, &pb.CronCreateRequest{
 			CronExpr:               cron.CronExpr,
+			CronTimezone:           cron.CronTimezone,
 			PipelineYml:            cron.PipelineYml,
 			PipelineYmlName:        cron.PipelineYmlName,
 			PipelineSource:         cron.PipelineSource,
@@ -563,6 +563,13 @@ func Test_provider_CronUpdate(t *testing.T) {
 	}
 }
 
+func Test_getUpdatedCronTimezone(t *testing.T) {
+	assert.Equal(t, "Asia/Shanghai", getUpdatedCronTimezone("Asia/Shanghai", "", ""))
+	assert.Equal(t, "UTC", getUpdatedCronTimezone("Asia/Shanghai", "", "UTC"))
+	assert.Equal(t, "America/New_York", getUpdatedCronTimezone("Asia/Shanghai", "America/New_York", "UTC"))
+	assert.Equal(t, "", getUpdatedCronTimezone("", "", ""))
+}
+
 func Test_provider_cronDelete(t *testing.T) {
 	type args struct {
 		req    *pb.CronDeleteRequest
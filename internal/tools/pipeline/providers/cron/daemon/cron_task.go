@@ -306,13 +306,13 @@ func (s *provider) reloadCrond(ctx context.Context) ([]string, error) {
 				continue
 			}
 
-			if err = s.crond.AddFunc(pc.CronExpr, func() { s.runCronPipelineFunc(ctx, pc.ID) }, makePipelineCronName(pc.ID)); err != nil {
-				l := fmt.Sprintf("failed to load pipeline cron item: %s, cronExpr: %v, err: %v", makePipelineCronName(pc.ID), pc.CronExpr, err)
+			if err = s.crond.AddFunc(pc.GetCronSpec(), func() { s.runCronPipelineFunc(ctx, pc.ID) }, makePipelineCronName(pc.ID)); err != nil {
+				l := fmt.Sprintf("failed to load pipeline cron item: %s, cronExpr: %v, err: %v", makePipelineCronName(pc.ID), pc.GetCronSpec(), err)
 				logs = append(logs, l)
 				logrus.Errorln("[alert]", l)
 				continue
 			}
-			logs = append(logs, fmt.Sprintf("loaded pipeline cron item: %s, cronExpr: %v", makePipelineCronName(pc.ID), pc.CronExpr))
+			logs = append(logs, fmt.Sprintf("loaded pipeline cron item: %s, cronExpr: %v", makePipelineCronName(pc.ID), pc.GetCronSpec()))
 		}
 	}
 
@@ -348,6 +348,7 @@ func (s *provider) syncCronToEdge(dbCron db.PipelineCron) error {
 		_, err := bdl.CronCreate(&cronpb.CronCreateRequest{
 			ID:                     pc.ID,
 			CronExpr:               pc.CronExpr,
+			CronTimezone:           pc.CronTimezone,
 			PipelineYmlName:        pc.PipelineYmlName,
 			PipelineSource:         pc.PipelineSource.String(),
 			Enable:                 wrapperspb.Bool(*pc.Enable),
@@ -23,6 +23,7 @@ import (
 
 	"github.com/erda-project/erda-proto-go/core/pipeline/pb"
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/pkg/cron"
 )
 
 const (
@@ -31,6 +32,7 @@ const (
 	PipelineCronEnable   = "enable"
 	Extra                = "extra"
 	PipelineCronIsEdge   = "is_edge"
+	PipelineCronTimezone = "cron_timezone"
 )
 
 type PipelineCron struct {
@@ -42,6 +44,8 @@ type PipelineCron struct {
 	PipelineYmlName string                    `json:"pipelineYmlName"`
 
 	CronExpr string `json:"cronExpr"`
+	// CronTimezone IANA 时区，例如 Asia/Shanghai，为空时使用服务端时区
+	CronTimezone string `json:"cronTimezone" xorm:"cron_timezone"`
 	//PipelineSource  string            `json:"pipelineSource"`
 	Enable *bool             `json:"enable"` // 1 true, 0 false
 	Extra  PipelineCronExtra `json:"extra,omitempty" xorm:"json"`
@@ -60,6 +64,11 @@ type PipelineCron struct {
 	IsEdge *bool `json:"is_edge"`
 }
 
+// GetCronSpec return cron expr with timezone, used by crond
+func (pc PipelineCron) GetCronSpec() string {
+	return cron.SpecWithTimezone(pc.CronExpr, pc.CronTimezone)
+}
+
 func (pc PipelineCron) GetIsEdge() bool {
 	if pc.IsEdge == nil {
 		return false
@@ -124,6 +133,7 @@ func (pc *PipelineCron) Convert2DTO() *pb.Cron {
 		ApplicationID:          pc.ApplicationID,
 		Branch:                 pc.Branch,
 		CronExpr:               pc.CronExpr,
+		CronTimezone:           pc.CronTimezone,
 		PipelineYmlName:        pc.PipelineYmlName,
 		BasePipelineID:         pc.BasePipelineID,
 		PipelineYml:            pc.Extra.PipelineYml,
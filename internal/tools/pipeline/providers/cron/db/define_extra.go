@@ -125,7 +125,8 @@ func (client *Client) UpdatePipelineCron(id interface{}, cron *PipelineCron, ops
 	session := client.NewSession(ops...)
 	defer session.Close()
 
-	_, err := session.ID(id).Update(cron)
+	// cron_timezone can be cleared to use server time zone
+	_, err := session.ID(id).MustCols(PipelineCronTimezone).Update(cron)
 	return errors.Wrapf(err, "failed to update pipeline cron, id [%v]", id)
 }
 
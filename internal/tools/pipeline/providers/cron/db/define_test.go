@@ -163,3 +163,12 @@ func TestPipelineCron_Convert2DTO(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCronSpec(t *testing.T) {
+	pc := PipelineCron{CronExpr: "0 2 * * *"}
+	assert.Equal(t, "0 2 * * *", pc.GetCronSpec())
+
+	pc.CronTimezone = "Asia/Shanghai"
+	assert.Equal(t, "CRON_TZ=Asia/Shanghai 0 2 * * *", pc.GetCronSpec())
+	assert.Equal(t, "Asia/Shanghai", pc.Convert2DTO().CronTimezone)
+}
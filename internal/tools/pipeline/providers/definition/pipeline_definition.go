@@ -28,6 +28,7 @@ import (
 	"github.com/erda-project/erda-proto-go/core/pipeline/definition/pb"
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/definition/db"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/services/apierrors"
 	"github.com/erda-project/erda/pkg/crypto/uuid"
 	"github.com/erda-project/erda/pkg/encoding/jsonparse"
@@ -36,6 +37,28 @@ import (
 
 type pipelineDefinition struct {
 	dbClient *db.Client
+	trigger  pipelinetrigger.Interface
+}
+
+// syncTriggers refresh on.pipeline triggers of the definition, failure doesn't block changes of the definition
+func (p pipelineDefinition) syncTriggers(ctx context.Context, definitionID string) {
+	if p.trigger == nil {
+		return
+	}
+	if err := p.trigger.SyncDefinitionTriggers(ctx, definitionID); err != nil {
+		logrus.Errorf("failed to sync on.pipeline triggers, definitionID: %s, err: %v", definitionID, err)
+	}
+}
+
+func (p pipelineDefinition) removeTriggers(ctx context.Context, definitionIDs ...string) {
+	if p.trigger == nil {
+		return
+	}
+	for _, definitionID := range definitionIDs {
+		if err := p.trigger.RemoveDefinitionTriggers(ctx, definitionID); err != nil {
+			logrus.Errorf("failed to remove on.pipeline triggers, definitionID: %s, err: %v", definitionID, err)
+		}
+	}
 }
 
 func GetExtraValue(definition *pb.PipelineDefinition) (*apistructs.PipelineDefinitionExtraValue, error) {
@@ -96,6 +119,8 @@ func (p pipelineDefinition) Create(ctx context.Context, request *pb.PipelineDefi
 		}
 	}
 
+	p.syncTriggers(ctx, pipelineDefinition.ID)
+
 	pbPipelineDefinition := PipelineDefinitionToPb(&pipelineDefinition)
 	pbPipelineDefinitionExtra := PipelineDefinitionExtraToPb(&pipelineDefinitionExtra)
 	pbPipelineDefinition.Extra = pbPipelineDefinitionExtra
@@ -151,6 +176,7 @@ func (p pipelineDefinition) Update(ctx context.Context, request *pb.PipelineDefi
 	if err != nil {
 		return nil, err
 	}
+	p.syncTriggers(ctx, pipelineDefinition.ID)
 
 	pbPipelineDefinition := PipelineDefinitionToPb(pipelineDefinition)
 	return &pb.PipelineDefinitionUpdateResponse{
@@ -163,6 +189,7 @@ func (p pipelineDefinition) Delete(ctx context.Context, request *pb.PipelineDefi
 	if err != nil {
 		return nil, err
 	}
+	p.removeTriggers(ctx, request.PipelineDefinitionID)
 
 	return &pb.PipelineDefinitionDeleteResponse{}, nil
 }
@@ -171,7 +198,10 @@ func (p pipelineDefinition) DeleteByRemote(ctx context.Context, request *pb.Pipe
 	if request.Remote == "" {
 		return nil, fmt.Errorf("the remote is empty")
 	}
-	var err error
+	definitions, err := p.dbClient.ListPipelineDefinitionByRemote(request.Remote)
+	if err != nil {
+		return nil, err
+	}
 
 	session := p.dbClient.NewSession()
 	defer session.Close()
@@ -190,6 +220,10 @@ func (p pipelineDefinition) DeleteByRemote(ctx context.Context, request *pb.Pipe
 		if cmErr := session.Commit(); cmErr != nil {
 			logrus.Errorf("failed to commit when delete by remote, remote: %s, rollbackErr: %v",
 				request.Remote, cmErr)
+			return
+		}
+		for _, definition := range definitions {
+			p.removeTriggers(ctx, definition.ID)
 		}
 	}()
 	err = p.dbClient.DeletePipelineDefinitionByRemote(request.Remote, mysqlxorm.WithSession(session))
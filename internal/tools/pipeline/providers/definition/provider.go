@@ -20,6 +20,7 @@ import (
 	"github.com/erda-project/erda-infra/providers/mysqlxorm"
 	"github.com/erda-project/erda-proto-go/core/pipeline/definition/pb"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/definition/db"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/pkg/common/apis"
 )
 
@@ -29,8 +30,9 @@ type config struct {
 // +provider
 type provider struct {
 	Cfg      *config
-	MySQL    mysqlxorm.Interface `autowired:"mysql-xorm"`
-	Register transport.Register  `autowired:"service-register" required:"true"`
+	MySQL    mysqlxorm.Interface       `autowired:"mysql-xorm"`
+	Register transport.Register        `autowired:"service-register" required:"true"`
+	Trigger  pipelinetrigger.Interface `autowired:"pipeline-trigger" optional:"true"`
 	// implements
 	pipelineDefinition *pipelineDefinition
 }
@@ -38,6 +40,7 @@ type provider struct {
 func (p *provider) Init(ctx servicehub.Context) error {
 	p.pipelineDefinition = &pipelineDefinition{
 		dbClient: &db.Client{Interface: p.MySQL},
+		trigger:  p.Trigger,
 	}
 	if p.Register != nil {
 		pb.RegisterDefinitionServiceImp(p.Register, p.pipelineDefinition, apis.Options())
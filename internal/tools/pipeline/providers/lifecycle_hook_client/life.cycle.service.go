@@ -17,24 +17,65 @@ package lifecycle_hook_client
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	"github.com/erda-project/erda-infra/base/logs"
 	"github.com/erda-project/erda-proto-go/core/pipeline/lifecycle_hook_client/pb"
 	"github.com/erda-project/erda/bundle/apierrors"
 	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
+	"github.com/erda-project/erda/pkg/crypto/encryption"
+	"github.com/erda-project/erda/pkg/crypto/uuid"
 	"github.com/erda-project/erda/pkg/http/httpclient"
 	"github.com/erda-project/erda/pkg/http/httputil"
 )
 
+const (
+	// HeaderDeliveryID is shared by all attempts of one delivery, clients can use it to deduplicate
+	HeaderDeliveryID = "X-Erda-Lifecycle-Delivery"
+	// HeaderTimestamp is the unix seconds when request is signed, clients should reject stale requests
+	HeaderTimestamp = "X-Erda-Lifecycle-Timestamp"
+	// HeaderSignature is "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+	HeaderSignature = "X-Erda-Lifecycle-Signature"
+
+	signaturePrefix = "sha256="
+
+	maxRetries           = 5
+	defaultRetryInterval = 500 * time.Millisecond
+	maxRetryInterval     = 10 * time.Second
+	// maxSecretLength keeps the rsa encrypted secret in the secret column
+	maxSecretLength = 128
+
+	// defaultDeliveryTimeout is the time budget of all attempts when Delivery.Timeout not set
+	defaultDeliveryTimeout = 30 * time.Second
+	maxAttemptTimeout      = 5 * time.Second
+	// maxRecordedBodySize request and response longer than it are truncated in delivery record
+	maxRecordedBodySize = 64 * 1024
+)
+
+// Delivery describes which pipeline and hook a request belongs to, used to record delivery log
+type Delivery struct {
+	PipelineID uint64
+	Hook       string
+	// Timeout is the time budget of all attempts including retry intervals, default 30s
+	Timeout time.Duration
+}
+
 type LifeCycleService struct {
 	sync.Mutex
 	hookClientMap map[string]*pb.LifeCycleClient
 	logger        logs.Logger
 	dbClient      *dbclient.Client
+	// rsaCrypt encrypts client secret in db, the same key pair as cms
+	rsaCrypt *encryption.RsaCrypt
 }
 
 func (s *LifeCycleService) LifeCycleRegister(ctx context.Context, req *pb.LifeCycleClientRegisterRequest) (*pb.LifeCycleClientRegisterResponse, error) {
@@ -44,26 +85,100 @@ func (s *LifeCycleService) LifeCycleRegister(ctx context.Context, req *pb.LifeCy
 	if req.Host == "" {
 		return nil, apierrors.ErrInvalidParameter.InternalError(fmt.Errorf("client host is required"))
 	}
+	if req.MaxRetries < 0 || req.MaxRetries > maxRetries {
+		return nil, apierrors.ErrInvalidParameter.InternalError(fmt.Errorf("maxRetries must between 0 and %d", maxRetries))
+	}
+	if req.RetryIntervalMs < 0 || time.Duration(req.RetryIntervalMs)*time.Millisecond > maxRetryInterval {
+		return nil, apierrors.ErrInvalidParameter.InternalError(fmt.Errorf("retryIntervalMs must between 0 and %d", maxRetryInterval.Milliseconds()))
+	}
+	if len(req.Secret) > maxSecretLength {
+		return nil, apierrors.ErrInvalidParameter.InternalError(fmt.Errorf("secret must not be longer than %d", maxSecretLength))
+	}
+	encryptedSecret, err := s.encryptSecret(req.Secret)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(fmt.Errorf("failed to encrypt secret: %v", err))
+	}
 	hookClient := &dbclient.PipelineLifecycleHookClient{
-		Name:   req.Name,
-		Host:   req.Host,
-		Prefix: req.Prefix,
+		Name:            req.Name,
+		Host:            req.Host,
+		Prefix:          req.Prefix,
+		Secret:          encryptedSecret,
+		MaxRetries:      int(req.MaxRetries),
+		RetryIntervalMs: int(req.RetryIntervalMs),
 	}
-	err := s.dbClient.InsertOrUpdateLifeCycleClient(hookClient)
+	err = s.dbClient.InsertOrUpdateLifeCycleClient(hookClient)
 	if err != nil {
 		return nil, err
 	}
+	client := toPbClient(hookClient)
+	client.Secret = req.Secret
 	s.Lock()
 	defer s.Unlock()
-	s.hookClientMap[hookClient.Name] = &pb.LifeCycleClient{
-		ID:     hookClient.ID,
-		Name:   hookClient.Name,
-		Host:   hookClient.Host,
-		Prefix: hookClient.Prefix,
-	}
+	s.hookClientMap[hookClient.Name] = client
 	return &pb.LifeCycleClientRegisterResponse{Data: hookClient.ID}, nil
 }
 
+func (s *LifeCycleService) ListLifecycleHookDeliveries(ctx context.Context, req *pb.LifecycleHookDeliveryListRequest) (*pb.LifecycleHookDeliveryListResponse, error) {
+	if req.PipelineID == 0 {
+		return nil, apierrors.ErrInvalidParameter.InternalError(fmt.Errorf("pipelineID is required"))
+	}
+	deliveries, err := s.dbClient.ListLifecycleHookDeliveries(req.PipelineID)
+	if err != nil {
+		return nil, apierrors.ErrInvoke.InternalError(err)
+	}
+	data := make([]*pb.LifecycleHookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		data = append(data, &pb.LifecycleHookDelivery{
+			ID:             d.ID,
+			DeliveryID:     d.DeliveryID,
+			PipelineID:     d.PipelineID,
+			ClientName:     d.ClientName,
+			Hook:           d.Hook,
+			Url:            d.URL,
+			Attempt:        int64(d.Attempt),
+			Request:        d.Request,
+			ResponseStatus: int64(d.ResponseStatus),
+			Response:       d.Response,
+			Error:          d.Error,
+			LatencyMs:      d.LatencyMs,
+			TimeCreated:    timestamppb.New(d.TimeCreated),
+		})
+	}
+	return &pb.LifecycleHookDeliveryListResponse{Data: data}, nil
+}
+
+// toPbClient converts db client without secret, which is encrypted in db
+func toPbClient(client *dbclient.PipelineLifecycleHookClient) *pb.LifeCycleClient {
+	return &pb.LifeCycleClient{
+		ID:              client.ID,
+		Name:            client.Name,
+		Host:            client.Host,
+		Prefix:          client.Prefix,
+		MaxRetries:      int64(client.MaxRetries),
+		RetryIntervalMs: int64(client.RetryIntervalMs),
+	}
+}
+
+func (s *LifeCycleService) encryptSecret(secret string) (string, error) {
+	if secret == "" {
+		return "", nil
+	}
+	if s.rsaCrypt == nil {
+		return "", fmt.Errorf("rsa key pair not provided")
+	}
+	return s.rsaCrypt.Encrypt(secret, encryption.Base64)
+}
+
+func (s *LifeCycleService) decryptSecret(secret string) (string, error) {
+	if secret == "" {
+		return "", nil
+	}
+	if s.rsaCrypt == nil {
+		return "", fmt.Errorf("rsa key pair not provided")
+	}
+	return s.rsaCrypt.Decrypt(secret, encryption.Base64)
+}
+
 func (s *LifeCycleService) loadLifecycleHookClient() error {
 	clients, err := s.dbClient.FindLifecycleHookClientList()
 	if err != nil {
@@ -72,18 +187,45 @@ func (s *LifeCycleService) loadLifecycleHookClient() error {
 
 	s.Lock()
 	for _, dbHookClient := range clients {
-		s.hookClientMap[dbHookClient.Name] = &pb.LifeCycleClient{
-			ID:     dbHookClient.ID,
-			Name:   dbHookClient.Name,
-			Host:   dbHookClient.Host,
-			Prefix: dbHookClient.Prefix,
+		secret, err := s.decryptSecret(dbHookClient.Secret)
+		if err != nil {
+			// never post unsigned requests to the client which requires signature
+			s.logger.Errorf("failed to decrypt secret of lifecycle hook client %s, skip it, err: %v", dbHookClient.Name, err)
+			continue
 		}
+		client := toPbClient(dbHookClient)
+		client.Secret = secret
+		s.hookClientMap[dbHookClient.Name] = client
 	}
 	s.Unlock()
 	return nil
 }
 
-func (s *LifeCycleService) PostLifecycleHookHttpClient(source string, req interface{}, resp interface{}) error {
+// gcDeliveries deletes delivery records created before retention periodically
+func (s *LifeCycleService) gcDeliveries(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.dbClient.DeleteLifecycleHookDeliveriesBefore(time.Now().Add(-retention))
+			if err != nil {
+				s.logger.Errorf("failed to gc lifecycle hook deliveries, err: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				s.logger.Infof("gc lifecycle hook deliveries, deleted: %d", deleted)
+			}
+		}
+	}
+}
+
+// PostLifecycleHookHttpClient post req to the client registered as source and decode response into resp.
+// Request is signed if client has secret, retried with exponential backoff on network error or 5xx
+// until delivery.Timeout is used up, and the final outcome is recorded as a delivery of the pipeline.
+func (s *LifeCycleService) PostLifecycleHookHttpClient(source string, delivery Delivery, req interface{}, resp interface{}) error {
 
 	s.logger.Debugf("postLifecycleHookHttpClient source: %v, request: %v", source, req)
 
@@ -94,22 +236,85 @@ func (s *LifeCycleService) PostLifecycleHookHttpClient(source string, req interf
 		return fmt.Errorf("not find this source: %v client", source)
 	}
 
-	var httpClient = httpclient.New(
-		httpclient.WithTimeout(time.Second, time.Second*5),
-	)
-	var buffer bytes.Buffer
-	r, err := httpClient.Post(client.Host).
-		Header(httputil.InternalHeader, "pipeline_lifecycle_hook").
-		Path(client.Prefix + "/actions/lifecycle").
-		JSONBody(&req).
-		Do().
-		Body(&buffer)
+	body, err := json.Marshal(req)
 	if err != nil {
 		return apierrors.ErrInvoke.InternalError(err)
 	}
+	deliveryID := uuid.New()
+	path := client.Prefix + "/actions/lifecycle"
+
+	timeout := delivery.Timeout
+	if timeout <= 0 {
+		timeout = defaultDeliveryTimeout
+	}
+	begin := time.Now()
+	deadline := begin.Add(timeout)
+
+	var (
+		buffer    bytes.Buffer
+		retryable bool
+		record    *dbclient.PipelineLifecycleHookDelivery
+	)
+	for attempt := 1; ; attempt++ {
+		buffer.Reset()
+		attemptTimeout := time.Until(deadline)
+		if attemptTimeout > maxAttemptTimeout {
+			attemptTimeout = maxAttemptTimeout
+		}
+		httpClient := httpclient.New(
+			httpclient.WithTimeout(time.Second, attemptTimeout),
+		)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request := httpClient.Post(client.Host).
+			Header(httputil.InternalHeader, "pipeline_lifecycle_hook").
+			Header("Content-Type", "application/json").
+			Header(HeaderDeliveryID, deliveryID).
+			Path(path).
+			RawBody(bytes.NewReader(body))
+		if client.Secret != "" {
+			request.Header(HeaderTimestamp, timestamp).
+				Header(HeaderSignature, signaturePrefix+Sign(client.Secret, timestamp, body))
+		}
+		r, postErr := request.Do().Body(&buffer)
 
-	if !r.IsOK() {
-		return apierrors.ErrInvoke.InternalError(fmt.Errorf("request pipeline lifecycle hook failed httpcode: %v, body: %s", r.StatusCode(), buffer.String()))
+		record = &dbclient.PipelineLifecycleHookDelivery{
+			DeliveryID: deliveryID,
+			PipelineID: delivery.PipelineID,
+			ClientName: client.Name,
+			Hook:       delivery.Hook,
+			URL:        client.Host + path,
+			Attempt:    attempt,
+			Request:    truncateBody(string(body)),
+			Response:   truncateBody(buffer.String()),
+			LatencyMs:  time.Since(begin).Milliseconds(),
+		}
+		switch {
+		case postErr != nil:
+			err, retryable = apierrors.ErrInvoke.InternalError(postErr), true
+		case !r.IsOK():
+			record.ResponseStatus = r.StatusCode()
+			err = apierrors.ErrInvoke.InternalError(fmt.Errorf("request pipeline lifecycle hook failed httpcode: %v, body: %s", r.StatusCode(), buffer.String()))
+			retryable = r.StatusCode() >= 500 || r.StatusCode() == 429
+		default:
+			record.ResponseStatus = r.StatusCode()
+			err, retryable = nil, false
+		}
+		if err == nil || !retryable || attempt > int(client.MaxRetries) {
+			break
+		}
+		// give up if next attempt can not start before deadline
+		interval := retryInterval(time.Duration(client.RetryIntervalMs)*time.Millisecond, attempt)
+		if time.Now().Add(interval).After(deadline) {
+			break
+		}
+		time.Sleep(interval)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	s.recordDelivery(record)
+	if err != nil {
+		return err
 	}
 
 	err = json.NewDecoder(&buffer).Decode(resp)
@@ -120,3 +325,49 @@ func (s *LifeCycleService) PostLifecycleHookHttpClient(source string, req interf
 	s.logger.Debugf("postLifecycleHookHttpClient response: %v", buffer.String())
 	return nil
 }
+
+// recordDelivery failure should not block the hook itself
+func (s *LifeCycleService) recordDelivery(record *dbclient.PipelineLifecycleHookDelivery) {
+	if s.dbClient == nil {
+		return
+	}
+	if err := s.dbClient.CreateLifecycleHookDelivery(record); err != nil {
+		s.logger.Warnf("failed to record lifecycle hook delivery, deliveryID: %s, attempt: %d, err: %v", record.DeliveryID, record.Attempt, err)
+	}
+}
+
+// truncateBody keeps the head of body in delivery record
+func truncateBody(body string) string {
+	if len(body) <= maxRecordedBodySize {
+		return body
+	}
+	return body[:maxRecordedBodySize] + "...(truncated)"
+}
+
+// Sign return hex encoded HMAC-SHA256 of timestamp + "." + body,
+// clients can verify the request by calculating it again with the same secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryInterval return base * 2^(attempt-1), but not greater than maxRetryInterval
+func retryInterval(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		base = defaultRetryInterval
+	}
+	interval := base
+	for i := 1; i < attempt; i++ {
+		interval *= 2
+		if interval >= maxRetryInterval {
+			return maxRetryInterval
+		}
+	}
+	if interval > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return interval
+}
@@ -15,14 +15,24 @@
 package lifecycle_hook_client
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"bou.ke/monkey"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/erda-project/erda-infra/base/logs/logrusx"
+
 	"github.com/erda-project/erda-proto-go/core/pipeline/lifecycle_hook_client/pb"
 	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
+	"github.com/erda-project/erda/pkg/crypto/encryption"
 )
 
 func Test_loadLifecycleHookClient(t *testing.T) {
@@ -72,3 +82,144 @@ func Test_loadLifecycleHookClient(t *testing.T) {
 		s.hookClientMap = map[string]*pb.LifeCycleClient{}
 	}
 }
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"hook":"before-run-check"}`)
+	sign := Sign("secret", "1660000000", body)
+	assert.Len(t, sign, 64)
+	assert.Equal(t, sign, Sign("secret", "1660000000", body))
+	assert.NotEqual(t, sign, Sign("other", "1660000000", body))
+	assert.NotEqual(t, sign, Sign("secret", "1660000001", body))
+	assert.NotEqual(t, sign, Sign("secret", "1660000000", []byte(`{}`)))
+}
+
+func TestRetryInterval(t *testing.T) {
+	assert.Equal(t, defaultRetryInterval, retryInterval(0, 1))
+	assert.Equal(t, 2*defaultRetryInterval, retryInterval(0, 2))
+	assert.Equal(t, 100*time.Millisecond, retryInterval(100*time.Millisecond, 1))
+	assert.Equal(t, 400*time.Millisecond, retryInterval(100*time.Millisecond, 3))
+	assert.Equal(t, maxRetryInterval, retryInterval(time.Second, 10))
+	assert.Equal(t, maxRetryInterval, retryInterval(time.Minute, 1))
+}
+
+func TestPostLifecycleHookHttpClient(t *testing.T) {
+	var (
+		attempts    int
+		deliveryIDs = map[string]struct{}{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		timestamp := r.Header.Get(HeaderTimestamp)
+		assert.Equal(t, "/api/fdp/actions/lifecycle", r.URL.Path)
+		assert.Equal(t, signaturePrefix+Sign("secret", timestamp, body), r.Header.Get(HeaderSignature))
+		deliveryIDs[r.Header.Get(HeaderDeliveryID)] = struct{}{}
+		if attempts < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{"checkResult":"success"}}`))
+	}))
+	defer server.Close()
+
+	s := &LifeCycleService{
+		logger: logrusx.New(),
+		hookClientMap: map[string]*pb.LifeCycleClient{
+			"FDP": {
+				Name:            "FDP",
+				Host:            strings.TrimPrefix(server.URL, "http://"),
+				Prefix:          "/api/fdp",
+				Secret:          "secret",
+				MaxRetries:      2,
+				RetryIntervalMs: 1,
+			},
+		},
+	}
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			CheckResult string `json:"checkResult"`
+		} `json:"data"`
+	}
+	err := s.PostLifecycleHookHttpClient("FDP", Delivery{PipelineID: 1, Hook: "before-run-check"}, json.RawMessage(`{"hook":"before-run-check"}`), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, deliveryIDs, 1)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "success", resp.Data.CheckResult)
+
+	// no more retry after max retries
+	attempts = -10
+	err = s.PostLifecycleHookHttpClient("FDP", Delivery{PipelineID: 1}, map[string]string{}, &resp)
+	assert.Error(t, err)
+	assert.Equal(t, -7, attempts)
+
+	err = s.PostLifecycleHookHttpClient("not-exist", Delivery{}, nil, &resp)
+	assert.Error(t, err)
+}
+
+func TestPostLifecycleHookHttpClient_Timeout(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := &LifeCycleService{
+		logger: logrusx.New(),
+		hookClientMap: map[string]*pb.LifeCycleClient{
+			"FDP": {
+				Name:            "FDP",
+				Host:            strings.TrimPrefix(server.URL, "http://"),
+				MaxRetries:      maxRetries,
+				RetryIntervalMs: 200,
+			},
+		},
+	}
+	// 200ms, 400ms, 800ms intervals, the third retry can not start in 1s
+	begin := time.Now()
+	err := s.PostLifecycleHookHttpClient("FDP", Delivery{PipelineID: 1, Timeout: time.Second}, map[string]string{}, &struct{}{})
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.True(t, time.Since(begin) < time.Second)
+}
+
+func TestTruncateBody(t *testing.T) {
+	assert.Equal(t, "{}", truncateBody("{}"))
+	truncated := truncateBody(strings.Repeat("a", maxRecordedBodySize+1))
+	assert.True(t, strings.HasPrefix(truncated, strings.Repeat("a", maxRecordedBodySize)))
+	assert.True(t, strings.HasSuffix(truncated, "...(truncated)"))
+}
+
+func TestEncryptSecret(t *testing.T) {
+	publicKey, privateKey, err := encryption.GenRsaKey(2048)
+	assert.NoError(t, err)
+	s := &LifeCycleService{
+		rsaCrypt: encryption.NewRSAScrypt(encryption.RSASecret{
+			PublicKey:          base64.StdEncoding.EncodeToString(publicKey),
+			PublicKeyDataType:  encryption.Base64,
+			PrivateKey:         base64.StdEncoding.EncodeToString(privateKey),
+			PrivateKeyDataType: encryption.Base64,
+			PrivateKeyType:     encryption.PKCS1,
+		}),
+	}
+
+	encrypted, err := s.encryptSecret("secret")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "secret", encrypted)
+	decrypted, err := s.decryptSecret(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+
+	// secret of max length still fits the column after encrypted
+	encrypted, err = s.encryptSecret(strings.Repeat("s", maxSecretLength))
+	assert.NoError(t, err)
+	assert.True(t, len(encrypted) <= 1024)
+
+	encrypted, err = s.encryptSecret("")
+	assert.NoError(t, err)
+	assert.Empty(t, encrypted)
+	_, err = (&LifeCycleService{}).encryptSecret("secret")
+	assert.Error(t, err)
+}
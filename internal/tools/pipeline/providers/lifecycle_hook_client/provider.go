@@ -15,16 +15,24 @@
 package lifecycle_hook_client
 
 import (
+	"context"
+	"time"
+
 	"github.com/erda-project/erda-infra/base/logs"
 	"github.com/erda-project/erda-infra/base/servicehub"
 	"github.com/erda-project/erda-infra/pkg/transport"
 	"github.com/erda-project/erda-infra/providers/mysqlxorm"
 	"github.com/erda-project/erda-proto-go/core/pipeline/lifecycle_hook_client/pb"
+	"github.com/erda-project/erda/internal/tools/pipeline/conf"
 	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
 	"github.com/erda-project/erda/pkg/common/apis"
+	"github.com/erda-project/erda/pkg/crypto/encryption"
 )
 
 type config struct {
+	// default 7 days
+	DeliveryRetention  time.Duration `file:"delivery_retention" env:"PIPELINE_LIFECYCLE_HOOK_DELIVERY_RETENTION" default:"168h"`
+	DeliveryGCInterval time.Duration `file:"delivery_gc_interval" default:"1h"`
 }
 
 // +provider
@@ -42,6 +50,13 @@ func (p *provider) Init(ctx servicehub.Context) error {
 		logger:        p.Log,
 		dbClient:      &dbclient.Client{Engine: p.MySQL.DB()},
 		hookClientMap: map[string]*pb.LifeCycleClient{},
+		rsaCrypt: encryption.NewRSAScrypt(encryption.RSASecret{
+			PublicKey:          conf.CmsBase64EncodedRsaPublicKey(),
+			PublicKeyDataType:  encryption.Base64,
+			PrivateKey:         conf.CmsBase64EncodedRsaPrivateKey(),
+			PrivateKeyDataType: encryption.Base64,
+			PrivateKeyType:     encryption.PKCS1,
+		}),
 	}
 	if p.Register != nil {
 		pb.RegisterLifeCycleServiceImp(p.Register, p.lifeCycleService, apis.Options())
@@ -52,6 +67,11 @@ func (p *provider) Init(ctx servicehub.Context) error {
 	return nil
 }
 
+func (p *provider) Run(ctx context.Context) error {
+	p.lifeCycleService.gcDeliveries(ctx, p.Cfg.DeliveryRetention, p.Cfg.DeliveryGCInterval)
+	return nil
+}
+
 func (p *provider) Provide(ctx servicehub.DependencyContext, args ...interface{}) interface{} {
 	switch {
 	case ctx.Service() == "erda.core.pipeline.lifecycle_hook_client.LifeCycleService" || ctx.Type() == pb.LifeCycleServiceServerType() || ctx.Type() == pb.LifeCycleServiceHandlerType():
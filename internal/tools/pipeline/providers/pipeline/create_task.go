@@ -195,12 +195,20 @@ func (s *pipelineService) calculateTaskRunAfter(action *pipelineyml.Action) []st
 
 // judgeTaskExecutor judge task executor by action info
 func (s *pipelineService) judgeTaskExecutor(task *spec.PipelineTask, actionSpec *apistructs.ActionSpec) (spec.PipelineTaskExecutorKind, spec.PipelineTaskExecutorName) {
+	// manual approval is handled by wait executor, no container needed
+	if task.Type == apistructs.ActionTypeManualApproval {
+		return spec.PipelineTaskExecutorKindWait, spec.PipelineTaskExecutorNameWaitDefault
+	}
 	if actionSpec == nil ||
 		actionSpec.Executor == nil ||
 		len(actionSpec.Executor.Kind) <= 0 ||
 		len(actionSpec.Executor.Name) <= 0 ||
 		!spec.PipelineTaskExecutorKind(actionSpec.Executor.Kind).Check() ||
 		!spec.PipelineTaskExecutorName(actionSpec.Executor.Name).Check() {
+		// run tasks as local process on bare host, such as edge and dev setups
+		if conf.LocalExecutorAsDefault() {
+			return spec.PipelineTaskExecutorKindLocal, spec.PipelineTaskExecutorNameLocalDefault
+		}
 		kind := spec.PipelineTaskExecutorKindK8sJob
 		if bigData, err := task.GetBigDataConf(); err == nil {
 			if bigData.FlinkConf != nil {
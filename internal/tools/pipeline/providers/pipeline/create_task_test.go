@@ -175,6 +175,20 @@ func TestPipelineSvc_judgeTaskExecutor(t *testing.T) {
 			want1:   spec.PipelineTaskExecutorName(fmt.Sprintf("%s-%s", spec.PipelineTaskExecutorNameK8sSparkDefault, "erda-op")),
 			wantErr: false,
 		},
+		{
+			name: "manual approval",
+			args: args{
+				action: &spec.PipelineTask{
+					Type: apistructs.ActionTypeManualApproval,
+					Extra: spec.PipelineTaskExtra{
+						ClusterName: "erda-op",
+					},
+				},
+			},
+			want:    spec.PipelineTaskExecutorKindWait,
+			want1:   spec.PipelineTaskExecutorNameWaitDefault,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -16,9 +16,11 @@ package pipeline
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -566,6 +568,11 @@ func (s *pipelineService) MakePipelineFromRequestV2(req *pb.PipelineCreateReques
 			})
 	}
 
+	// concurrency group
+	if err := setPipelineConcurrencyGroup(p, pipelineYml.Spec().Concurrency, labels); err != nil {
+		return nil, apierrors.ErrCreatePipeline.InvalidParameter(err)
+	}
+
 	// queue
 	if req.BindQueue != nil {
 		customPriority := req.BindQueue.Priority
@@ -586,6 +593,37 @@ func (s *pipelineService) MakePipelineFromRequestV2(req *pb.PipelineCreateReques
 	return p, nil
 }
 
+// setPipelineConcurrencyGroup render concurrency group and store the group key into labels for query.
+// Group key is scoped by pipeline source and application, so the same branch name of different applications won't conflict.
+func setPipelineConcurrencyGroup(p *spec.Pipeline, concurrency *pipelineyml.Concurrency, labels map[string]string) error {
+	if concurrency == nil {
+		return nil
+	}
+	group, err := pipelineyml.RenderConcurrencyGroup(concurrency.Group, map[string]string{
+		pipelineyml.ConcurrencyVarGitBranch:       labels[apistructs.LabelBranch],
+		pipelineyml.ConcurrencyVarGitCommit:       labels[apistructs.LabelCommit],
+		pipelineyml.ConcurrencyVarPipelineSource:  p.PipelineSource.String(),
+		pipelineyml.ConcurrencyVarPipelineYmlName: p.PipelineYmlName,
+	})
+	if err != nil {
+		return err
+	}
+	if group == "" {
+		return nil
+	}
+	key := strings.Join([]string{p.PipelineSource.String(), labels[apistructs.LabelAppID], group}, "/")
+	// label value is indexed, use digest instead if too long
+	if len(key) > maxSqlIndexLength {
+		key = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(key)))
+	}
+	p.Extra.ConcurrencyGroup = &spec.ConcurrencyGroup{
+		Key:              key,
+		CancelInProgress: concurrency.CancelInProgress,
+	}
+	p.Labels[apistructs.LabelPipelineConcurrencyGroup] = key
+	return nil
+}
+
 // 非定时触发的，如果有定时配置，需要插入或更新 pipeline_crons enable 配置
 // 不管是定时还是非定时，只要定时配置是空的，就将pipeline_crons disable
 func (s *pipelineService) UpdatePipelineCron(p *spec.Pipeline, cronStartFrom *timestamppb.Timestamp, configManageNamespaces []string, cronCompensator *pipelineyml.CronCompensator) error {
@@ -494,3 +494,39 @@ func Test_tryGetOrgName(t *testing.T) {
 		})
 	}
 }
+
+func Test_setPipelineConcurrencyGroup(t *testing.T) {
+	newPipeline := func() *spec.Pipeline {
+		return &spec.Pipeline{
+			PipelineBase: spec.PipelineBase{
+				PipelineSource:  apistructs.PipelineSourceDice,
+				PipelineYmlName: "pipeline.yml",
+			},
+			Labels: map[string]string{},
+		}
+	}
+	labels := map[string]string{
+		apistructs.LabelAppID:  "1",
+		apistructs.LabelBranch: "master",
+	}
+
+	// no concurrency
+	p := newPipeline()
+	assert.NoError(t, setPipelineConcurrencyGroup(p, nil, labels))
+	assert.Nil(t, p.Extra.ConcurrencyGroup)
+
+	// render group
+	p = newPipeline()
+	err := setPipelineConcurrencyGroup(p, &pipelineyml.Concurrency{Group: "deploy-${{ git.branch }}", CancelInProgress: true}, labels)
+	assert.NoError(t, err)
+	assert.Equal(t, "dice/1/deploy-master", p.Extra.ConcurrencyGroup.Key)
+	assert.True(t, p.Extra.ConcurrencyGroup.CancelInProgress)
+	assert.Equal(t, "dice/1/deploy-master", p.Labels[apistructs.LabelPipelineConcurrencyGroup])
+
+	// too long group use digest
+	p = newPipeline()
+	err = setPipelineConcurrencyGroup(p, &pipelineyml.Concurrency{Group: strings.Repeat("g", maxSqlIndexLength)}, labels)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(p.Extra.ConcurrencyGroup.Key, "sha256:"))
+	assert.True(t, len(p.Extra.ConcurrencyGroup.Key) <= maxSqlIndexLength)
+}
@@ -221,6 +221,7 @@ func (s *pipelineService) Detail(pipelineID uint64) (*pb.PipelineDetailDTO, erro
 
 	// no need to display secret
 	p.Snapshot.Secrets = nil
+	p.Snapshot.ExternalSecrets = nil
 
 	var pc *common.Cron
 	// CronExpr 不为空，则 cron 必须存在
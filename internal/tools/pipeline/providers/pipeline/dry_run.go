@@ -0,0 +1,275 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonpb "github.com/erda-project/erda-proto-go/common/pb"
+	"github.com/erda-project/erda-proto-go/core/pipeline/pipeline/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/cms"
+	"github.com/erda-project/erda/internal/tools/pipeline/services/apierrors"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/common/apis"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
+)
+
+// PipelineDryRun return the resolved execution plan of pipeline yml, no pipeline, task or container will be created.
+func (s *pipelineService) PipelineDryRun(ctx context.Context, req *pb.PipelineDryRunRequest) (*pb.PipelineDryRunResponse, error) {
+	identityInfo := apis.GetIdentityInfo(ctx)
+	if req.UserID == "" && identityInfo != nil {
+		req.UserID = identityInfo.UserID
+	}
+	if req.InternalClient == "" && identityInfo != nil {
+		req.InternalClient = identityInfo.InternalClient
+	}
+	if req.PipelineYml == "" {
+		return nil, apierrors.ErrDryRunPipeline.MissingParameter("pipelineYml")
+	}
+	if req.PipelineSource == "" {
+		return nil, apierrors.ErrDryRunPipeline.MissingParameter("pipelineSource")
+	}
+
+	if err := s.checkDryRunPermission(identityInfo, req.ConfigManageNamespaces); err != nil {
+		return nil, err
+	}
+
+	// secret 的值在 plan 中会被渲染为掩码，只返回缺失的 secret 名字
+	secrets, err := s.fetchDryRunSecrets(ctx, req)
+	if err != nil {
+		return nil, apierrors.ErrDryRunPipeline.InternalError(err)
+	}
+	plan, err := pipelineyml.MakePlan([]byte(req.PipelineYml), s.makeDryRunSnippetLoader(req.Labels),
+		pipelineyml.WithEnvs(req.Envs),
+		pipelineyml.WithSecrets(secrets),
+		pipelineyml.WithRunParams(s.ToPipelineRunParamsWithValue(req.RunParams)),
+	)
+	if err != nil {
+		return nil, apierrors.ErrParsePipelineYml.InvalidParameter(err)
+	}
+	data, err := convertPipelinePlan(plan)
+	if err != nil {
+		return nil, apierrors.ErrDryRunPipeline.InternalError(err)
+	}
+	return &pb.PipelineDryRunResponse{Data: data}, nil
+}
+
+// checkDryRunPermission check user has permission of the apps which config manage namespaces belong to,
+// namespaces whose owner can not be recognized are only allowed for internal client.
+func (s *pipelineService) checkDryRunPermission(identityInfo *commonpb.IdentityInfo, namespaces []string) error {
+	if identityInfo == nil || identityInfo.InternalClient != "" {
+		return nil
+	}
+	checkedAppIDs := make(map[uint64]struct{})
+	for _, ns := range namespaces {
+		appID, ok := getConfigNamespaceAppID(ns)
+		if !ok {
+			return apierrors.ErrCheckPermission.AccessDenied()
+		}
+		if _, ok := checkedAppIDs[appID]; ok {
+			continue
+		}
+		if err := s.permission.CheckApp(identityInfo, appID, apistructs.GetAction); err != nil {
+			return err
+		}
+		checkedAppIDs[appID] = struct{}{}
+	}
+	return nil
+}
+
+// getConfigNamespaceAppID parse app id from config manage namespace, like:
+// pipeline-secrets-app-{appID}-{branchPrefix} or app-{appID}-{workspace}
+func getConfigNamespaceAppID(ns string) (uint64, bool) {
+	var rest string
+	switch {
+	case strings.HasPrefix(ns, cms.PipelineAppConfigNameSpacePrefix+"-"):
+		rest = strings.TrimPrefix(ns, cms.PipelineAppConfigNameSpacePrefix+"-")
+	case strings.HasPrefix(ns, "app-"):
+		rest = strings.TrimPrefix(ns, "app-")
+	default:
+		return 0, false
+	}
+	idx := strings.Index(rest, "-")
+	if idx <= 0 || idx == len(rest)-1 {
+		return 0, false
+	}
+	appID, err := strconv.ParseUint(rest[:idx], 10, 64)
+	if err != nil || appID == 0 {
+		return 0, false
+	}
+	return appID, true
+}
+
+// fetchDryRunSecrets fetch secrets in the same way as run, but pipeline only exists in memory
+func (s *pipelineService) fetchDryRunSecrets(ctx context.Context, req *pb.PipelineDryRunRequest) (map[string]string, error) {
+	p := &spec.Pipeline{
+		PipelineBase: spec.PipelineBase{
+			PipelineSource:  apistructs.PipelineSource(req.PipelineSource),
+			PipelineYmlName: req.PipelineYmlName,
+			ClusterName:     req.ClusterName,
+		},
+		PipelineExtra: spec.PipelineExtra{
+			Extra: spec.PipelineExtraInfo{
+				ConfigManageNamespaces: req.ConfigManageNamespaces,
+			},
+		},
+		Labels: req.Labels,
+	}
+	secrets := make(map[string]string)
+	if len(p.GetConfigManageNamespaces()) > 0 {
+		cmsSecrets, _, holdOnKeys, _, err := s.secret.FetchSecrets(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		secrets = cmsSecrets
+		// 平台 secrets 依赖集群信息
+		if req.ClusterName != "" {
+			platformSecrets, err := s.secret.FetchPlatformSecrets(ctx, p, holdOnKeys)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range platformSecrets {
+				if _, ok := secrets[k]; !ok {
+					secrets[k] = v
+				}
+			}
+		}
+	}
+	for k, v := range req.Secrets {
+		secrets[k] = v
+	}
+	return secrets, nil
+}
+
+// makeDryRunSnippetLoader load snippet yml in the same way as creating snippet pipeline
+func (s *pipelineService) makeDryRunSnippetLoader(labels map[string]string) pipelineyml.SnippetYmlLoader {
+	return func(alias string, config pipelineyml.SnippetConfig) ([]byte, error) {
+		config = pipelineyml.HandleSnippetConfigLabel(&config, labels)
+		query := &pb.SnippetDetailQuery{
+			Source: config.Source,
+			Name:   config.Name,
+			Labels: config.Labels,
+		}
+		ymls, err := s.HandleQueryPipelineYamlBySnippetConfigs([]*pb.SnippetDetailQuery{query})
+		if err != nil {
+			return nil, err
+		}
+		yml, ok := ymls[s.ConvertSnippetConfig2String(query)]
+		if !ok {
+			return nil, fmt.Errorf("not find snippet %s yml", s.ConvertSnippetConfig2String(query))
+		}
+		return []byte(yml), nil
+	}
+}
+
+func convertPipelinePlan(plan *pipelineyml.Plan) (*pb.PipelinePlan, error) {
+	if plan == nil {
+		return nil, nil
+	}
+	result := &pb.PipelinePlan{
+		Name:           plan.Name,
+		Version:        plan.Version,
+		Envs:           plan.Envs,
+		Params:         plan.Params,
+		MissingParams:  plan.MissingParams,
+		MissingSecrets: plan.MissingSecrets,
+		Warns:          plan.Warns,
+		Cron:           plan.Cron,
+		CronTimezone:   plan.CronTimezone,
+	}
+	for _, nextCronTime := range plan.NextCronTimes {
+		result.NextCronTimes = append(result.NextCronTimes, timestamppb.New(nextCronTime))
+	}
+	for _, stage := range plan.Stages {
+		pbStage := &pb.PipelinePlanStage{}
+		for _, task := range stage.Tasks {
+			pbTask, err := convertPipelinePlanTask(task)
+			if err != nil {
+				return nil, err
+			}
+			pbStage.Tasks = append(pbStage.Tasks, pbTask)
+		}
+		result.Stages = append(result.Stages, pbStage)
+	}
+	return result, nil
+}
+
+func convertPipelinePlanTask(task *pipelineyml.PlanTask) (*pb.PipelinePlanTask, error) {
+	result := &pb.PipelinePlanTask{
+		Alias:   task.Alias,
+		Type:    task.Type,
+		Version: task.Version,
+		Image:   task.Image,
+		Needs:   task.Needs,
+		Timeout: task.Timeout,
+		Disable: task.Disable,
+		Resources: &pb.PipelinePlanTaskResources{
+			Cpu:     task.Resources.CPU,
+			MaxCPU:  task.Resources.MaxCPU,
+			Mem:     int64(task.Resources.Mem),
+			Disk:    int64(task.Resources.Disk),
+			Network: task.Resources.Network,
+		},
+		IfExpr:                 task.If,
+		Condition:              string(task.Condition),
+		ConditionDesc:          task.ConditionDesc,
+		UnresolvedPlaceholders: task.UnresolvedPlaceholders,
+		SnippetError:           task.SnippetError,
+	}
+	var err error
+	if result.Params, err = toPlanValue(task.Params); err != nil {
+		return nil, err
+	}
+	if result.Commands, err = toPlanValue(task.Commands); err != nil {
+		return nil, err
+	}
+	if task.Policy != nil {
+		result.PolicyType = string(task.Policy.Type)
+		if result.RetryPolicy, err = toPlanValue(task.Policy.Retry); err != nil {
+			return nil, err
+		}
+	}
+	if result.Loop, err = toPlanValue(task.Loop); err != nil {
+		return nil, err
+	}
+	if result.Snippet, err = convertPipelinePlan(task.Snippet); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// toPlanValue convert value to structpb.Value by json, nil value returns nil
+func toPlanValue(v interface{}) (*structpb.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var i interface{}
+	if err := json.Unmarshal(b, &i); err != nil {
+		return nil, err
+	}
+	if i == nil {
+		return nil, nil
+	}
+	return structpb.NewValue(i)
+}
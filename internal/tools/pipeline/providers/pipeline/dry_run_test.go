@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
+)
+
+func Test_convertPipelinePlan(t *testing.T) {
+	plan := &pipelineyml.Plan{
+		Version:        "1.1",
+		Params:         map[string]string{"branch": "master"},
+		MissingSecrets: []string{"git.token"},
+		Stages: []*pipelineyml.PlanStage{
+			{
+				Tasks: []*pipelineyml.PlanTask{
+					{
+						Alias:     "build",
+						Type:      "custom-script",
+						Needs:     []string{"repo"},
+						Params:    map[string]interface{}{"count": 1},
+						Commands:  []interface{}{"echo master"},
+						Resources: pipelineyml.Resources{CPU: 0.5, Mem: 1024},
+						Policy: &pipelineyml.Policy{
+							Type:  apistructs.RetryPolicyType,
+							Retry: &apistructs.TaskRetryPolicy{MaxAttempts: 3},
+						},
+						If:        "${{ 1 == 1 }}",
+						Condition: pipelineyml.PlanConditionTrue,
+						Snippet:   &pipelineyml.Plan{Version: "1.1"},
+					},
+				},
+			},
+		},
+	}
+	result, err := convertPipelinePlan(plan)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"git.token"}, result.MissingSecrets)
+	task := result.Stages[0].Tasks[0]
+	assert.Equal(t, "build", task.Alias)
+	assert.Equal(t, float64(1), task.Params.GetStructValue().Fields["count"].GetNumberValue())
+	assert.Equal(t, "echo master", task.Commands.GetListValue().Values[0].GetStringValue())
+	assert.Equal(t, int64(1024), task.Resources.Mem)
+	assert.Equal(t, string(apistructs.RetryPolicyType), task.PolicyType)
+	assert.Equal(t, float64(3), task.RetryPolicy.GetStructValue().Fields["max_attempts"].GetNumberValue())
+	assert.Nil(t, task.Loop)
+	assert.Equal(t, "true", task.Condition)
+	assert.Equal(t, "1.1", task.Snippet.Version)
+}
+
+func Test_getConfigNamespaceAppID(t *testing.T) {
+	tests := []struct {
+		ns     string
+		appID  uint64
+		wantOk bool
+	}{
+		{ns: "pipeline-secrets-app-1-default", appID: 1, wantOk: true},
+		{ns: "pipeline-secrets-app-12-feature", appID: 12, wantOk: true},
+		{ns: "app-3-dev", appID: 3, wantOk: true},
+		{ns: "app-3", wantOk: false},
+		{ns: "app-x-dev", wantOk: false},
+		{ns: "project-1-dev", wantOk: false},
+		{ns: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		appID, ok := getConfigNamespaceAppID(tt.ns)
+		assert.Equal(t, tt.wantOk, ok, tt.ns)
+		assert.Equal(t, tt.appID, appID, tt.ns)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgepipeline_register"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgereporter"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/permission"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/resource"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/run"
@@ -50,4 +51,5 @@ type pipelineService struct {
 	cache        cache.Interface
 	permission   permission.Interface
 	cancel       cancel.Interface
+	trigger      pipelinetrigger.Interface
 }
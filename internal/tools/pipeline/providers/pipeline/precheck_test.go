@@ -30,6 +30,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/actionmgr"
 	resourcemgr "github.com/erda-project/erda/internal/tools/pipeline/providers/resource"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/secret"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
 	"github.com/erda-project/erda/pkg/parser/diceyml"
 	"github.com/erda-project/erda/pkg/parser/pipelineyml"
@@ -62,6 +63,14 @@ func (m *mockSecret) FetchPlatformSecrets(ctx context.Context, p *spec.Pipeline,
 	return nil, nil
 }
 
+func (m *mockSecret) FetchExternalSecrets(ctx context.Context, p *spec.Pipeline) (map[string]string, error) {
+	return nil, nil
+}
+
+func (m *mockSecret) RegisterBackend(backend secret.Backend) error {
+	return nil
+}
+
 func (m *mockActionAgent) MakeActionTypeVersion(action *pipelineyml.Action) string {
 	return fmt.Sprintf("%s@%s", action.Alias, action.Version)
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgepipeline_register"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgereporter"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/permission"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/resource"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/run"
@@ -66,6 +67,7 @@ type provider struct {
 	Cache        cache.Interface
 	Permission   permission.Interface
 	Cancel       cancel.Interface
+	Trigger      pipelinetrigger.Interface
 }
 
 func (p *provider) Init(ctx servicehub.Context) error {
@@ -94,6 +96,7 @@ func (p *provider) Init(ctx servicehub.Context) error {
 		cache:        p.Cache,
 		permission:   p.Permission,
 		cancel:       p.Cancel,
+		trigger:      p.Trigger,
 	}
 	if p.Register != nil {
 		pb.RegisterPipelineServiceImp(p.Register, p.pipelineService, apis.Options())
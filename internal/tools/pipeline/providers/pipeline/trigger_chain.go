@@ -0,0 +1,33 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+
+	"github.com/erda-project/erda-proto-go/core/pipeline/pipeline/pb"
+	"github.com/erda-project/erda/internal/tools/pipeline/services/apierrors"
+)
+
+func (s *pipelineService) PipelineTriggerChain(ctx context.Context, req *pb.PipelineTriggerChainRequest) (*pb.PipelineTriggerChainResponse, error) {
+	if req.PipelineID == 0 {
+		return nil, apierrors.ErrGetTriggerChain.MissingParameter("pipelineID")
+	}
+	chain, err := s.trigger.GetTriggerChain(ctx, req.PipelineID)
+	if err != nil {
+		return nil, apierrors.ErrGetTriggerChain.InternalError(err)
+	}
+	return &pb.PipelineTriggerChainResponse{Data: chain}, nil
+}
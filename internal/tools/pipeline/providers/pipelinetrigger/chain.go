@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelinetrigger
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pipelinepb "github.com/erda-project/erda-proto-go/core/pipeline/pipeline/pb"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger/db"
+	"github.com/erda-project/erda/pkg/strutil"
+)
+
+func (p *provider) GetTriggerChain(ctx context.Context, pipelineID uint64) (*pipelinepb.PipelineTriggerChain, error) {
+	chain := &pipelinepb.PipelineTriggerChain{}
+
+	// upstreams, records[i] created the downstream of records[i].UpstreamPipelineID
+	var records []db.PipelineTriggerRecord
+	current := pipelineID
+	for len(records) < p.Cfg.MaxChainDepth {
+		record, has, err := p.triggerDBClient.GetUpstreamRecord(current)
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			break
+		}
+		records = append(records, *record)
+		current = record.UpstreamPipelineID
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		var createdBy *db.PipelineTriggerRecord
+		if i+1 < len(records) {
+			createdBy = &records[i+1]
+		}
+		node, err := p.makeChainNode(records[i].UpstreamPipelineID, createdBy)
+		if err != nil {
+			return nil, err
+		}
+		chain.Upstreams = append(chain.Upstreams, node)
+	}
+
+	// downstreams, breadth first
+	visited := map[uint64]struct{}{pipelineID: {}}
+	queue := []uint64{pipelineID}
+	for len(queue) > 0 {
+		upstreamID := queue[0]
+		queue = queue[1:]
+		downstreamRecords, err := p.triggerDBClient.ListDownstreamRecords(upstreamID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range downstreamRecords {
+			record := downstreamRecords[i]
+			node, err := p.makeChainNode(record.DownstreamPipelineID, &record)
+			if err != nil {
+				return nil, err
+			}
+			chain.Downstreams = append(chain.Downstreams, node)
+			if record.DownstreamPipelineID == 0 {
+				continue
+			}
+			if _, ok := visited[record.DownstreamPipelineID]; ok {
+				continue
+			}
+			visited[record.DownstreamPipelineID] = struct{}{}
+			queue = append(queue, record.DownstreamPipelineID)
+		}
+	}
+
+	return chain, nil
+}
+
+// makeChainNode make node by pipeline and the record which created it, pipelineID is 0 if trigger failed.
+func (p *provider) makeChainNode(pipelineID uint64, createdBy *db.PipelineTriggerRecord) (*pipelinepb.PipelineTriggerChainNode, error) {
+	node := &pipelinepb.PipelineTriggerChainNode{PipelineID: pipelineID}
+	if pipelineID > 0 {
+		base, has, err := p.dbClient.GetPipelineBase(pipelineID)
+		if err != nil {
+			return nil, err
+		}
+		if has {
+			node.PipelineSource = base.PipelineSource.String()
+			node.PipelineYmlName = base.PipelineYmlName
+			node.Status = base.Status.String()
+			node.DefinitionID = base.PipelineDefinitionID
+		}
+	}
+	if createdBy != nil {
+		if node.DefinitionID == "" {
+			node.DefinitionID = createdBy.DownstreamDefinitionID
+		}
+		node.UpstreamPipelineID = createdBy.UpstreamPipelineID
+		node.Depth = int64(createdBy.Depth)
+		node.TriggerStatus = string(createdBy.Status)
+		node.Error = createdBy.Error
+		node.TimeCreated = timestamppb.New(createdBy.TimeCreated)
+		node.Params = make(map[string]string, len(createdBy.Params))
+		for k, v := range createdBy.Params {
+			node.Params[k] = strutil.String(v)
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/erda-project/erda-infra/providers/mysqlxorm"
+)
+
+// PipelineUpstreamTrigger 下游流水线定义对上游流水线的订阅，由 on.pipeline 生成
+type PipelineUpstreamTrigger struct {
+	ID                   uint64            `json:"id" xorm:"pk autoincr"`
+	PipelineDefinitionID string            `json:"pipelineDefinitionID"`
+	ProjectID            uint64            `json:"projectID"`
+	UpstreamSource       string            `json:"upstreamSource"`
+	UpstreamYmlName      string            `json:"upstreamYmlName"`
+	Branches             []string          `json:"branches" xorm:"json"`
+	Statuses             []string          `json:"statuses" xorm:"json"`
+	Params               map[string]string `json:"params" xorm:"json"`
+	TimeCreated          time.Time         `json:"timeCreated" xorm:"created_at created"`
+	TimeUpdated          time.Time         `json:"timeUpdated" xorm:"updated_at updated"`
+}
+
+func (PipelineUpstreamTrigger) TableName() string {
+	return "pipeline_upstream_triggers"
+}
+
+// ReplaceUpstreamTriggers replace all triggers of the definition.
+func (client *Client) ReplaceUpstreamTriggers(definitionID string, triggers []PipelineUpstreamTrigger, ops ...mysqlxorm.SessionOption) error {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	if _, err := session.Where("pipeline_definition_id = ?", definitionID).Delete(new(PipelineUpstreamTrigger)); err != nil {
+		return err
+	}
+	if len(triggers) == 0 {
+		return nil
+	}
+	_, err := session.Insert(&triggers)
+	return err
+}
+
+// ListUpstreamTriggersByDefinitionID list triggers declared by the definition.
+func (client *Client) ListUpstreamTriggersByDefinitionID(definitionID string, ops ...mysqlxorm.SessionOption) ([]PipelineUpstreamTrigger, error) {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	var triggers []PipelineUpstreamTrigger
+	err := session.Where("pipeline_definition_id = ?", definitionID).Asc("id").Find(&triggers)
+	return triggers, err
+}
+
+// ListUpstreamTriggers list triggers which may be matched by the upstream pipeline.
+func (client *Client) ListUpstreamTriggers(upstreamSource string, projectID uint64, ops ...mysqlxorm.SessionOption) ([]PipelineUpstreamTrigger, error) {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	var triggers []PipelineUpstreamTrigger
+	err := session.Where("upstream_source = ? AND project_id = ?", upstreamSource, projectID).Find(&triggers)
+	return triggers, err
+}
+
+type TriggerRecordStatus string
+
+const (
+	// TriggerRecordStatusPending record is created before the downstream pipeline,
+	// stays pending if the process exits before the downstream pipeline is created
+	TriggerRecordStatusPending TriggerRecordStatus = "Pending"
+	TriggerRecordStatusCreated TriggerRecordStatus = "Created"
+	TriggerRecordStatusFailed  TriggerRecordStatus = "Failed"
+)
+
+// mysqlErrDupEntry ER_DUP_ENTRY
+const mysqlErrDupEntry = 1062
+
+// PipelineTriggerRecord 一次上游触发下游的记录，用于展示上下游链路
+type PipelineTriggerRecord struct {
+	ID                     uint64                 `json:"id" xorm:"pk autoincr"`
+	TriggerID              uint64                 `json:"triggerID"`
+	UpstreamPipelineID     uint64                 `json:"upstreamPipelineID"`
+	DownstreamPipelineID   uint64                 `json:"downstreamPipelineID"`
+	DownstreamDefinitionID string                 `json:"downstreamDefinitionID"`
+	Depth                  int                    `json:"depth"`
+	Status                 TriggerRecordStatus    `json:"status"`
+	Params                 map[string]interface{} `json:"params" xorm:"json"`
+	Error                  string                 `json:"error"`
+	TimeCreated            time.Time              `json:"timeCreated" xorm:"created_at created"`
+}
+
+func (PipelineTriggerRecord) TableName() string {
+	return "pipeline_trigger_records"
+}
+
+// CreateTriggerRecordIfNotExist create the record, return false if the upstream pipeline has already triggered the definition.
+// Unique key (downstream_definition_id, upstream_pipeline_id) makes sure concurrent or redone teardowns trigger at most once.
+func (client *Client) CreateTriggerRecordIfNotExist(record *PipelineTriggerRecord, ops ...mysqlxorm.SessionOption) (bool, error) {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	if _, err := session.InsertOne(record); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateTriggerRecord update result of the record.
+func (client *Client) UpdateTriggerRecord(record *PipelineTriggerRecord, ops ...mysqlxorm.SessionOption) error {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	_, err := session.ID(record.ID).Cols("downstream_pipeline_id", "status", "params", "error").Update(record)
+	return err
+}
+
+// GetUpstreamRecord get the record which created the downstream pipeline.
+func (client *Client) GetUpstreamRecord(downstreamPipelineID uint64, ops ...mysqlxorm.SessionOption) (*PipelineTriggerRecord, bool, error) {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	var record PipelineTriggerRecord
+	has, err := session.Where("downstream_pipeline_id = ?", downstreamPipelineID).Get(&record)
+	if err != nil || !has {
+		return nil, has, err
+	}
+	return &record, true, nil
+}
+
+// ListDownstreamRecords list records fired by the upstream pipeline.
+func (client *Client) ListDownstreamRecords(upstreamPipelineID uint64, ops ...mysqlxorm.SessionOption) ([]PipelineTriggerRecord, error) {
+	session := client.NewSession(ops...)
+	defer session.Close()
+
+	var records []PipelineTriggerRecord
+	err := session.Where("upstream_pipeline_id = ?", upstreamPipelineID).Asc("id").Find(&records)
+	return records, err
+}
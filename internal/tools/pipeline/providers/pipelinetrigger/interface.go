@@ -0,0 +1,42 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelinetrigger
+
+import (
+	"context"
+
+	pipelinepb "github.com/erda-project/erda-proto-go/core/pipeline/pipeline/pb"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+type PipelineFunc struct {
+	CreatePipeline CreatePipelineFunc
+	RunPipeline    RunPipelineFunc
+}
+
+type CreatePipelineFunc func(ctx context.Context, req *pipelinepb.PipelineCreateRequestV2) (*spec.Pipeline, error)
+type RunPipelineFunc func(ctx context.Context, req *pipelinepb.PipelineRunRequest) (*spec.Pipeline, error)
+
+type Interface interface {
+	// SyncDefinitionTriggers refresh the on.pipeline triggers by the latest pipeline yml of the definition.
+	SyncDefinitionTriggers(ctx context.Context, definitionID string) error
+	// RemoveDefinitionTriggers remove the on.pipeline triggers of the deleted definition.
+	RemoveDefinitionTriggers(ctx context.Context, definitionID string) error
+	// TriggerDownstream create downstream pipelines which subscribe the done pipeline.
+	TriggerDownstream(ctx context.Context, p *spec.Pipeline)
+	// GetTriggerChain return the upstream and downstream pipelines of the pipeline.
+	GetTriggerChain(ctx context.Context, pipelineID uint64) (*pipelinepb.PipelineTriggerChain, error)
+	WithPipelineFunc(pipelineFunc PipelineFunc)
+}
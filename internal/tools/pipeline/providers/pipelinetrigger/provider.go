@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelinetrigger
+
+import (
+	"reflect"
+
+	"github.com/erda-project/erda-infra/base/logs"
+	"github.com/erda-project/erda-infra/base/servicehub"
+	"github.com/erda-project/erda-infra/providers/mysqlxorm"
+	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
+	definitiondb "github.com/erda-project/erda/internal/tools/pipeline/providers/definition/db"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger/db"
+	sourcedb "github.com/erda-project/erda/internal/tools/pipeline/providers/source/db"
+)
+
+type config struct {
+	// MaxChainDepth limit the depth of pipeline trigger chain, avoid endless loop such as A -> B -> A
+	MaxChainDepth int `file:"max_chain_depth" default:"10"`
+}
+
+// +provider
+type provider struct {
+	Log   logs.Logger
+	Cfg   *config
+	MySQL mysqlxorm.Interface `autowired:"mysql-xorm"`
+
+	pipelineFunc       PipelineFunc
+	dbClient           *dbclient.Client
+	triggerDBClient    *db.Client
+	definitionDBClient *definitiondb.Client
+	sourceDBClient     *sourcedb.Client
+}
+
+func (p *provider) WithPipelineFunc(pipelineFunc PipelineFunc) {
+	p.pipelineFunc = pipelineFunc
+}
+
+func (p *provider) Init(ctx servicehub.Context) error {
+	p.dbClient = &dbclient.Client{Engine: p.MySQL.DB()}
+	p.triggerDBClient = &db.Client{Interface: p.MySQL}
+	p.definitionDBClient = &definitiondb.Client{Interface: p.MySQL}
+	p.sourceDBClient = &sourcedb.Client{Interface: p.MySQL}
+	return nil
+}
+
+func init() {
+	servicehub.Register("pipeline-trigger", &servicehub.Spec{
+		Services:    []string{"pipeline-trigger"},
+		Types:       []reflect.Type{reflect.TypeOf((*Interface)(nil)).Elem()},
+		Description: "trigger downstream pipelines declared by on.pipeline",
+		ConfigFunc:  func() interface{} { return &config{} },
+		Creator:     func() servicehub.Provider { return &provider{} },
+	})
+}
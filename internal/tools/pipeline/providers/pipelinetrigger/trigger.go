@@ -0,0 +1,384 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelinetrigger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	pipelinepb "github.com/erda-project/erda-proto-go/core/pipeline/pipeline/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/pkg/diceworkspace"
+	definitiondb "github.com/erda-project/erda/internal/tools/pipeline/providers/definition/db"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger/db"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
+	"github.com/erda-project/erda/pkg/strutil"
+)
+
+const internalClient = "system-pipeline-trigger"
+
+// downstreamDefinition is what the downstream pipeline is built from: the create request saved in definition extra
+// and the pipeline yml of the definition source, rather than the last run of the definition.
+type downstreamDefinition struct {
+	definition  *definitiondb.PipelineDefinition
+	createReq   *pipelinepb.PipelineCreateRequestV2
+	runParams   apistructs.PipelineRunParamsWithValue
+	pipelineYml string
+	spec        *pipelineyml.Spec
+}
+
+func (p *provider) getDownstreamDefinition(definitionID string) (*downstreamDefinition, error) {
+	definition, err := p.definitionDBClient.GetPipelineDefinition(definitionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline definition, err: %v", err)
+	}
+	extra, err := p.definitionDBClient.GetPipelineDefinitionExtraByDefinitionID(definitionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline definition extra, err: %v", err)
+	}
+	if extra == nil || extra.Extra.CreateRequest == nil {
+		return nil, fmt.Errorf("pipeline definition has no create request")
+	}
+	source, err := p.sourceDBClient.GetPipelineSource(definition.PipelineSourceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline source, err: %v", err)
+	}
+	pipelineYml, err := pipelineyml.New([]byte(source.PipelineYml))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline yml, err: %v", err)
+	}
+	d := &downstreamDefinition{
+		definition:  definition,
+		createReq:   extra.Extra.CreateRequest,
+		pipelineYml: source.PipelineYml,
+		spec:        pipelineYml.Spec(),
+	}
+	// run params of the last manual run
+	for _, rp := range extra.Extra.RunParams {
+		if rp == nil {
+			continue
+		}
+		d.runParams = append(d.runParams, apistructs.PipelineRunParamWithValue{
+			PipelineRunParam: apistructs.PipelineRunParam{Name: rp.Name, Value: rp.Value.AsInterface()},
+		})
+	}
+	return d, nil
+}
+
+func (d *downstreamDefinition) projectID() uint64 {
+	projectID, _ := strconv.ParseUint(d.createReq.Labels[apistructs.LabelProjectID], 10, 64)
+	return projectID
+}
+
+func (p *provider) SyncDefinitionTriggers(ctx context.Context, definitionID string) error {
+	d, err := p.getDownstreamDefinition(definitionID)
+	if err != nil {
+		return err
+	}
+	triggers := makeUpstreamTriggers(definitionID, d.projectID(), d.createReq.PipelineSource, d.spec)
+
+	existTriggers, err := p.triggerDBClient.ListUpstreamTriggersByDefinitionID(definitionID)
+	if err != nil {
+		return err
+	}
+	if isSameUpstreamTriggers(existTriggers, triggers) {
+		return nil
+	}
+	return p.triggerDBClient.ReplaceUpstreamTriggers(definitionID, triggers)
+}
+
+func (p *provider) RemoveDefinitionTriggers(ctx context.Context, definitionID string) error {
+	return p.triggerDBClient.ReplaceUpstreamTriggers(definitionID, nil)
+}
+
+// makeUpstreamTriggers convert on.pipeline of the downstream definition to triggers
+func makeUpstreamTriggers(definitionID string, projectID uint64, pipelineSource string, s *pipelineyml.Spec) []db.PipelineUpstreamTrigger {
+	if s.On == nil {
+		return nil
+	}
+	var triggers []db.PipelineUpstreamTrigger
+	for _, upstream := range s.On.Pipeline {
+		if upstream == nil {
+			continue
+		}
+		source := upstream.Source
+		if source == "" {
+			source = pipelineSource
+		}
+		triggers = append(triggers, db.PipelineUpstreamTrigger{
+			PipelineDefinitionID: definitionID,
+			ProjectID:            projectID,
+			UpstreamSource:       source,
+			UpstreamYmlName:      upstream.Name,
+			Branches:             upstream.Branches,
+			Statuses:             upstream.GetStatus(),
+			Params:               upstream.Params,
+		})
+	}
+	return triggers
+}
+
+func isSameUpstreamTriggers(a, b []db.PipelineUpstreamTrigger) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].PipelineDefinitionID != b[i].PipelineDefinitionID ||
+			a[i].ProjectID != b[i].ProjectID ||
+			a[i].UpstreamSource != b[i].UpstreamSource ||
+			a[i].UpstreamYmlName != b[i].UpstreamYmlName ||
+			!isSameOrBothEmpty(a[i].Branches, b[i].Branches) ||
+			!isSameOrBothEmpty(a[i].Statuses, b[i].Statuses) ||
+			!isSameOrBothEmpty(a[i].Params, b[i].Params) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSameOrBothEmpty treat nil and empty as the same, because json column may be stored as null
+func isSameOrBothEmpty(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Len() == 0 && vb.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (p *provider) TriggerDownstream(ctx context.Context, pipeline *spec.Pipeline) {
+	if p.pipelineFunc.CreatePipeline == nil || p.pipelineFunc.RunPipeline == nil || pipeline.IsSnippet || !pipeline.Status.IsEndStatus() {
+		return
+	}
+	triggers, err := p.triggerDBClient.ListUpstreamTriggers(pipeline.PipelineSource.String(), getProjectID(pipeline))
+	if err != nil {
+		p.Log.Errorf("failed to list upstream triggers, pipelineID: %d, err: %v", pipeline.ID, err)
+		return
+	}
+	var matched []db.PipelineUpstreamTrigger
+	for _, trigger := range triggers {
+		// pipeline can not trigger itself directly
+		if trigger.PipelineDefinitionID == pipeline.PipelineDefinitionID {
+			continue
+		}
+		if matchUpstreamTrigger(trigger, pipeline.PipelineYmlName, pipeline.GetLabel(apistructs.LabelBranch), pipeline.Status.String()) {
+			matched = append(matched, trigger)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	depth := 1
+	upstreamRecord, has, err := p.triggerDBClient.GetUpstreamRecord(pipeline.ID)
+	if err != nil {
+		p.Log.Errorf("failed to get upstream record, pipelineID: %d, err: %v", pipeline.ID, err)
+		return
+	}
+	if has {
+		depth = upstreamRecord.Depth + 1
+	}
+	if depth > p.Cfg.MaxChainDepth {
+		p.Log.Warnf("skip trigger downstream pipelines, depth of trigger chain exceeds %d, pipelineID: %d", p.Cfg.MaxChainDepth, pipeline.ID)
+		return
+	}
+
+	tasks, err := p.dbClient.ListPipelineTasksByPipelineID(pipeline.ID)
+	if err != nil {
+		p.Log.Errorf("failed to list pipeline tasks, pipelineID: %d, err: %v", pipeline.ID, err)
+		return
+	}
+	var taskPtrs []*spec.PipelineTask
+	for i := range tasks {
+		taskPtrs = append(taskPtrs, &tasks[i])
+	}
+	outputs := make(map[string]interface{})
+	for _, output := range pipeline.CalculateOutputValues(taskPtrs) {
+		outputs[output.Name] = output.Value
+	}
+
+	triggeredDefinitions := make(map[string]struct{})
+	for _, trigger := range matched {
+		if _, ok := triggeredDefinitions[trigger.PipelineDefinitionID]; ok {
+			continue
+		}
+		triggeredDefinitions[trigger.PipelineDefinitionID] = struct{}{}
+		// record first, so that the definition is triggered at most once even if teardown is redone after restart
+		record := &db.PipelineTriggerRecord{
+			TriggerID:              trigger.ID,
+			UpstreamPipelineID:     pipeline.ID,
+			DownstreamDefinitionID: trigger.PipelineDefinitionID,
+			Depth:                  depth,
+			Status:                 db.TriggerRecordStatusPending,
+		}
+		created, err := p.triggerDBClient.CreateTriggerRecordIfNotExist(record)
+		if err != nil {
+			p.Log.Errorf("failed to create pipeline trigger record, upstreamPipelineID: %d, definitionID: %s, err: %v",
+				pipeline.ID, trigger.PipelineDefinitionID, err)
+			continue
+		}
+		if !created {
+			continue
+		}
+		if err := p.triggerOne(ctx, pipeline, trigger, outputs, record); err != nil {
+			p.Log.Errorf("failed to trigger downstream pipeline, upstreamPipelineID: %d, definitionID: %s, err: %v",
+				pipeline.ID, trigger.PipelineDefinitionID, err)
+			record.Status = db.TriggerRecordStatusFailed
+			record.Error = err.Error()
+		} else {
+			record.Status = db.TriggerRecordStatusCreated
+		}
+		if err := p.triggerDBClient.UpdateTriggerRecord(record); err != nil {
+			p.Log.Errorf("failed to update pipeline trigger record, upstreamPipelineID: %d, definitionID: %s, err: %v",
+				pipeline.ID, trigger.PipelineDefinitionID, err)
+		}
+	}
+}
+
+// triggerOne create the downstream pipeline from its definition and run it.
+func (p *provider) triggerOne(ctx context.Context, upstream *spec.Pipeline, trigger db.PipelineUpstreamTrigger,
+	outputs map[string]interface{}, record *db.PipelineTriggerRecord) error {
+	d, err := p.getDownstreamDefinition(trigger.PipelineDefinitionID)
+	if err != nil {
+		return err
+	}
+	runParams, err := makeRunParams(d.spec.Params, d.runParams, trigger.Params, outputs)
+	if err != nil {
+		return err
+	}
+	record.Params = make(map[string]interface{}, len(runParams))
+	for _, rp := range runParams {
+		record.Params[rp.Name] = rp.Value
+	}
+	pbRunParams, err := runParams.ToPipelineRunParamsPB()
+	if err != nil {
+		return err
+	}
+
+	req := proto.Clone(d.createReq).(*pipelinepb.PipelineCreateRequestV2)
+	normalLabels := make(map[string]string, len(req.NormalLabels)+3)
+	for k, v := range req.NormalLabels {
+		normalLabels[k] = v
+	}
+	normalLabels[apistructs.LabelPipelineTriggerMode] = apistructs.PipelineTriggerModePipeline.String()
+	normalLabels[apistructs.LabelPipelineType] = apistructs.PipelineTypeNormal.String()
+	normalLabels[apistructs.LabelUpstreamPipelineID] = strconv.FormatUint(upstream.ID, 10)
+	req.NormalLabels = normalLabels
+	req.PipelineYml = d.pipelineYml
+	req.RunParams = pbRunParams
+	req.AutoRunAtOnce = false
+	req.AutoStartCron = false
+	req.InternalClient = internalClient
+	req.DefinitionID = d.definition.ID
+	if req.UserID == "" {
+		req.UserID = d.definition.Creator
+	}
+
+	downstream, err := p.pipelineFunc.CreatePipeline(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline, err: %v", err)
+	}
+	// save the downstream pipeline before run, so that it can be found even if the run is interrupted
+	record.DownstreamPipelineID = downstream.ID
+	if err := p.triggerDBClient.UpdateTriggerRecord(record); err != nil {
+		return fmt.Errorf("failed to update pipeline trigger record, err: %v", err)
+	}
+	if _, err := p.pipelineFunc.RunPipeline(ctx, &pipelinepb.PipelineRunRequest{
+		PipelineID:        downstream.ID,
+		UserID:            req.UserID,
+		InternalClient:    internalClient,
+		PipelineRunParams: pbRunParams,
+	}); err != nil {
+		return fmt.Errorf("failed to run pipeline %d, err: %v", downstream.ID, err)
+	}
+	p.Log.Infof("upstream pipeline %d triggered downstream pipeline %d, definitionID: %s",
+		upstream.ID, downstream.ID, trigger.PipelineDefinitionID)
+	return nil
+}
+
+// matchUpstreamTrigger check whether the done upstream pipeline matches the trigger.
+// ymlName matches if equal or the trigger name is the path suffix, such as pipeline.yml matches 1/DEV/master/pipeline.yml.
+func matchUpstreamTrigger(trigger db.PipelineUpstreamTrigger, ymlName, branch, status string) bool {
+	if trigger.UpstreamYmlName != ymlName && !strings.HasSuffix(ymlName, "/"+strings.TrimPrefix(trigger.UpstreamYmlName, "/")) {
+		return false
+	}
+	if len(trigger.Branches) > 0 && !diceworkspace.IsRefPatternMatch(branch, trigger.Branches) {
+		return false
+	}
+	statuses := trigger.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{apistructs.PipelineStatusSuccess.String()}
+	}
+	return strutil.Exist(statuses, status)
+}
+
+// makeRunParams use run params of the last manual run as base, and override the mapped ones by upstream outputs.
+// Values of mapped params are converted to the declared types.
+func makeRunParams(declaredParams []*pipelineyml.PipelineParam, baseParams apistructs.PipelineRunParamsWithValue,
+	mapping map[string]string, outputs map[string]interface{}) (apistructs.PipelineRunParamsWithValue, error) {
+	paramTypes := make(map[string]string, len(declaredParams))
+	for _, param := range declaredParams {
+		if param == nil {
+			continue
+		}
+		paramTypes[param.Name] = param.Type
+	}
+
+	var result apistructs.PipelineRunParamsWithValue
+	for _, rp := range baseParams {
+		if _, ok := mapping[rp.Name]; ok {
+			continue
+		}
+		if _, ok := paramTypes[rp.Name]; !ok {
+			continue
+		}
+		result = append(result, apistructs.PipelineRunParamWithValue{PipelineRunParam: rp.PipelineRunParam})
+	}
+	paramNames := make([]string, 0, len(mapping))
+	for paramName := range mapping {
+		paramNames = append(paramNames, paramName)
+	}
+	sort.Strings(paramNames)
+	for _, paramName := range paramNames {
+		outputName := mapping[paramName]
+		paramType, ok := paramTypes[paramName]
+		if !ok {
+			return nil, fmt.Errorf("param %s is not declared in params", paramName)
+		}
+		outputValue, ok := outputs[outputName]
+		if !ok || outputValue == nil {
+			return nil, fmt.Errorf("output %s of upstream pipeline is empty", outputName)
+		}
+		value, err := pipelineyml.ConvertParamValue(paramType, outputValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert output %s to param %s, err: %v", outputName, paramName, err)
+		}
+		result = append(result, apistructs.PipelineRunParamWithValue{
+			PipelineRunParam: apistructs.PipelineRunParam{Name: paramName, Value: value},
+		})
+	}
+	return result, nil
+}
+
+func getProjectID(pipeline *spec.Pipeline) uint64 {
+	projectID, _ := strconv.ParseUint(pipeline.GetLabel(apistructs.LabelProjectID), 10, 64)
+	return projectID
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelinetrigger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger/db"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
+)
+
+func Test_matchUpstreamTrigger(t *testing.T) {
+	trigger := db.PipelineUpstreamTrigger{
+		UpstreamYmlName: "pipeline.yml",
+		Branches:        []string{"master", "release/*"},
+	}
+	assert.True(t, matchUpstreamTrigger(trigger, "1/DEV/master/pipeline.yml", "master", "Success"))
+	assert.True(t, matchUpstreamTrigger(trigger, "pipeline.yml", "release/1.0", "Success"))
+	assert.False(t, matchUpstreamTrigger(trigger, "1/DEV/master/deploy-pipeline.yml", "master", "Success"))
+	assert.False(t, matchUpstreamTrigger(trigger, "1/DEV/develop/pipeline.yml", "develop", "Success"))
+	assert.False(t, matchUpstreamTrigger(trigger, "pipeline.yml", "master", "Failed"))
+
+	trigger.Branches = nil
+	trigger.Statuses = []string{"Failed", "Timeout"}
+	assert.True(t, matchUpstreamTrigger(trigger, "pipeline.yml", "develop", "Failed"))
+	assert.False(t, matchUpstreamTrigger(trigger, "pipeline.yml", "develop", "Success"))
+}
+
+func Test_makeRunParams(t *testing.T) {
+	declared := []*pipelineyml.PipelineParam{
+		{Name: "image", Type: apistructs.PipelineParamStringType},
+		{Name: "replicas", Type: apistructs.PipelineParamIntType},
+		{Name: "debug", Type: apistructs.PipelineParamBoolType},
+	}
+	base := apistructs.PipelineRunParamsWithValue{
+		{PipelineRunParam: apistructs.PipelineRunParam{Name: "image", Value: "old"}},
+		{PipelineRunParam: apistructs.PipelineRunParam{Name: "debug", Value: true}, TrueValue: true},
+		{PipelineRunParam: apistructs.PipelineRunParam{Name: "removed", Value: "x"}},
+	}
+	outputs := map[string]interface{}{"image": "registry/app:v2", "count": "3"}
+
+	result, err := makeRunParams(declared, base, map[string]string{"image": "image", "replicas": "count"}, outputs)
+	assert.NoError(t, err)
+	values := make(map[string]interface{})
+	for _, rp := range result {
+		values[rp.Name] = rp.Value
+	}
+	assert.Equal(t, map[string]interface{}{"image": "registry/app:v2", "replicas": 3, "debug": true}, values)
+
+	_, err = makeRunParams(declared, base, map[string]string{"image": "missing"}, outputs)
+	assert.Error(t, err)
+
+	_, err = makeRunParams(declared, base, map[string]string{"debug": "image"}, outputs)
+	assert.Error(t, err)
+
+	_, err = makeRunParams(declared, base, map[string]string{"undeclared": "image"}, outputs)
+	assert.Error(t, err)
+}
+
+func Test_makeUpstreamTriggers(t *testing.T) {
+	s := &pipelineyml.Spec{
+		On: &pipelineyml.TriggerConfig{Pipeline: []*pipelineyml.UpstreamPipelineTrigger{
+			{Name: "build.yml", Params: map[string]string{"image": "image"}},
+			{Source: "other", Name: "test.yml", Status: []string{"Failed"}},
+		}},
+	}
+	triggers := makeUpstreamTriggers("def-b", 2, apistructs.PipelineSourceDice.String(), s)
+	assert.Equal(t, 2, len(triggers))
+	assert.Equal(t, "def-b", triggers[0].PipelineDefinitionID)
+	assert.Equal(t, "dice", triggers[0].UpstreamSource)
+	assert.Equal(t, uint64(2), triggers[0].ProjectID)
+	assert.Equal(t, []string{"Success"}, triggers[0].Statuses)
+	assert.Equal(t, "other", triggers[1].UpstreamSource)
+	assert.Equal(t, []string{"Failed"}, triggers[1].Statuses)
+
+	assert.True(t, isSameUpstreamTriggers(triggers, makeUpstreamTriggers("def-b", 2, apistructs.PipelineSourceDice.String(), s)))
+	assert.False(t, isSameUpstreamTriggers(triggers, triggers[:1]))
+	assert.True(t, isSameUpstreamTriggers(nil, makeUpstreamTriggers("def-b", 2, apistructs.PipelineSourceDice.String(), &pipelineyml.Spec{})))
+
+	stored := append([]db.PipelineUpstreamTrigger{}, triggers...)
+	stored[1].Params = map[string]string{}
+	assert.True(t, isSameUpstreamTriggers(stored, triggers))
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda-proto-go/core/pipeline/queue/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/queue"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+// makeConcurrencyGroupQueue make a virtual queue for pipelines of the same concurrency group which don't bind queue.
+// Only one pipeline can be processing at the same time, others are pending in strict order.
+func makeConcurrencyGroupQueue(p *spec.Pipeline, groupKey string) *pb.Queue {
+	return &pb.Queue{
+		Name:           queue.MakeConcurrencyGroupQueueID(groupKey),
+		PipelineSource: p.PipelineSource.String(),
+		ClusterName:    p.ClusterName,
+		Mode:           apistructs.PipelineQueueModeStrict.String(),
+		Priority:       apistructs.PipelineQueueDefaultPriority,
+		Concurrency:    1,
+	}
+}
+
+// addPipelineIntoConcurrencyGroupQueue add pipeline into concurrency group queue, create queue if not exist.
+func (mgr *defaultManager) addPipelineIntoConcurrencyGroupQueue(p *spec.Pipeline, groupKey string, popCh chan struct{}) {
+	mgr.qLock.Lock()
+	defer mgr.qLock.Unlock()
+
+	queueID := queue.MakeConcurrencyGroupQueueID(groupKey)
+	q, ok := mgr.queueByID[queueID]
+	if !ok {
+		newQueue := queue.New(makeConcurrencyGroupQueue(p, groupKey), queue.WithDBClient(mgr.dbClient), queue.WithConcurrencyGroup(groupKey))
+		mgr.queueByID[queueID] = newQueue
+		qStopCh := make(chan struct{})
+		mgr.queueStopChanByID[queueID] = qStopCh
+		newQueue.Start(qStopCh)
+		q = newQueue
+	}
+	q.AddPipelineIntoQueue(p, popCh)
+}
+
+// removeConcurrencyGroupQueueIfEmpty remove and stop concurrency group queue if no pipeline inside.
+func (mgr *defaultManager) removeConcurrencyGroupQueueIfEmpty(groupKey string) {
+	mgr.qLock.Lock()
+	defer mgr.qLock.Unlock()
+
+	queueID := queue.MakeConcurrencyGroupQueueID(groupKey)
+	q, ok := mgr.queueByID[queueID]
+	if !ok {
+		return
+	}
+	usage := q.Usage()
+	if usage.ProcessingCount > 0 || usage.PendingCount > 0 {
+		return
+	}
+	delete(mgr.queueByID, queueID)
+	if stopCh, ok := mgr.queueStopChanByID[queueID]; ok {
+		go func(ch chan struct{}) {
+			defer func() { recover() }()
+			ch <- struct{}{}
+		}(stopCh)
+		delete(mgr.queueStopChanByID, queueID)
+	}
+	logrus.Infof("%s: remove empty concurrency group queue: %s", defaultQueueManagerLogPrefix, queueID)
+}
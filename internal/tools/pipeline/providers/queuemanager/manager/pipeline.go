@@ -53,6 +53,12 @@ func (mgr *defaultManager) PutPipelineIntoQueue(pipelineID uint64) (<-chan struc
 	// query pipeline queue detail
 	pq := mgr.ensureQueryPipelineQueueDetail(p)
 	if pq == nil {
+		// pipeline doesn't bind queue but has concurrency group, queue behind other pipelines of the group
+		if groupKey, ok := p.GetConcurrencyGroupKey(); ok {
+			mgr.ensureUpdatePipelineStatusToQueue(p)
+			mgr.addPipelineIntoConcurrencyGroupQueue(p, groupKey, popCh)
+			return popCh, false, nil
+		}
 		// pipeline doesn't bind queue, can reconcile directly
 		go func() {
 			popCh <- struct{}{}
@@ -128,18 +134,9 @@ func (mgr *defaultManager) ensureQueryPipelineQueueDetail(p *spec.Pipeline) *pb.
 		}
 
 		// update pipeline status to Queue
-		if p.Status == apistructs.PipelineStatusQueue || p.Status.AfterPipelineQueue() {
-			// no need update, already at queue or later status
-			return true, nil
-		}
-		// do update status and emit event
-		if err := mgr.dbClient.UpdatePipelineBaseStatus(p.ID, apistructs.PipelineStatusQueue); err != nil {
-			err = fmt.Errorf("failed to update pipeline status to Queue, err: %v", err)
-			rlog.PErrorf(p.ID, err.Error())
+		if err := mgr.updatePipelineStatusToQueue(p); err != nil {
 			return false, err
 		}
-		p.Status = apistructs.PipelineStatusQueue
-		events.EmitPipelineInstanceEvent(p, p.GetRunUserID())
 
 		return true, nil
 	})
@@ -150,6 +147,32 @@ func (mgr *defaultManager) ensureQueryPipelineQueueDetail(p *spec.Pipeline) *pb.
 	return pq
 }
 
+// ensureUpdatePipelineStatusToQueue update pipeline status to Queue until success.
+func (mgr *defaultManager) ensureUpdatePipelineStatusToQueue(p *spec.Pipeline) {
+	_ = loop.New(loop.WithDeclineLimit(time.Second*10), loop.WithDeclineRatio(2)).Do(func() (abort bool, err error) {
+		if err := mgr.updatePipelineStatusToQueue(p); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+func (mgr *defaultManager) updatePipelineStatusToQueue(p *spec.Pipeline) error {
+	if p.Status == apistructs.PipelineStatusQueue || p.Status.AfterPipelineQueue() {
+		// no need update, already at queue or later status
+		return nil
+	}
+	// do update status and emit event
+	if err := mgr.dbClient.UpdatePipelineBaseStatus(p.ID, apistructs.PipelineStatusQueue); err != nil {
+		err = fmt.Errorf("failed to update pipeline status to Queue, err: %v", err)
+		rlog.PErrorf(p.ID, err.Error())
+		return err
+	}
+	p.Status = apistructs.PipelineStatusQueue
+	events.EmitPipelineInstanceEvent(p, p.GetRunUserID())
+	return nil
+}
+
 func (mgr *defaultManager) BatchUpdatePipelinePriorityInQueue(pq *pb.Queue, pipelineIDs []uint64) error {
 	mgr.qLock.RLock()
 	defer mgr.qLock.RUnlock()
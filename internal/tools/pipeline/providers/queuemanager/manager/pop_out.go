@@ -21,6 +21,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/queue"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
 )
 
@@ -34,12 +35,21 @@ func (mgr *defaultManager) PopOutPipelineFromQueue(pipelineID uint64) {
 
 	relatedQueueID, ok := p.GetPipelineQueueID()
 	if !ok {
+		// pipeline without bound queue may be in concurrency group queue
+		if groupKey, ok := p.GetConcurrencyGroupKey(); ok {
+			mgr.popOutPipelineFromQueueByID(p, queue.MakeConcurrencyGroupQueueID(groupKey))
+			mgr.removeConcurrencyGroupQueueIfEmpty(groupKey)
+		}
 		return
 	}
 
+	mgr.popOutPipelineFromQueueByID(p, strconv.FormatUint(relatedQueueID, 10))
+}
+
+func (mgr *defaultManager) popOutPipelineFromQueueByID(p *spec.Pipeline, queueID string) {
 	mgr.qLock.RLock()
 	defer mgr.qLock.RUnlock()
-	q := mgr.queueByID[strconv.FormatUint(relatedQueueID, 10)]
+	q := mgr.queueByID[queueID]
 	if q == nil {
 		return
 	}
@@ -279,6 +279,36 @@ func TestPipelineQueueCreateRequest_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		// fair share
+		{
+			name: "valid fair share labels",
+			fields: fields{
+				Name:             validName,
+				PipelineSource:   validSource,
+				ClusterName:      validClusterName,
+				ScheduleStrategy: validStrategy,
+				Priority:         validPriority,
+				Labels: map[string]string{
+					apistructs.PipelineQueueLabelFairShareGroupBy:            "app",
+					apistructs.PipelineQueueLabelFairShareWeightPrefix + "1": "3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid fair share weight",
+			fields: fields{
+				Name:             validName,
+				PipelineSource:   validSource,
+				ClusterName:      validClusterName,
+				ScheduleStrategy: validStrategy,
+				Priority:         validPriority,
+				Labels: map[string]string{
+					apistructs.PipelineQueueLabelFairShareWeightPrefix + "1": "-1",
+				},
+			},
+			wantErr: true,
+		},
 	}
 	p := &provider{}
 	for _, tt := range tests {
@@ -18,5 +18,6 @@
 - priority queue
 - enhanced queue based on priority queue
 - throttler for pipeline based on enhanced queue
+- weighted fair share order across projects or apps
 */
 package queue
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fairshare
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+const defaultWeight int64 = 1
+
+// Config is the fair share config of queue.
+type Config struct {
+	// GroupBy decides pipelines are grouped by project or app
+	GroupBy apistructs.PipelineQueueFairShareGroupBy
+	// DefaultWeight is the weight of group without declared weight
+	DefaultWeight int64
+	// Weights is the declared weight of group, key is project id or app id
+	Weights map[string]int64
+	// AgingFactor is the share decreased per waiting minute of the earliest pending pipeline of group,
+	// so that group with low weight won't starve
+	AgingFactor float64
+}
+
+// ParseConfig parse fair share config from queue labels.
+func ParseConfig(labels map[string]string) (Config, error) {
+	cfg := Config{
+		GroupBy:       apistructs.PipelineQueueFairShareGroupByProject,
+		DefaultWeight: defaultWeight,
+		Weights:       make(map[string]int64),
+	}
+	for k, v := range labels {
+		switch {
+		case k == apistructs.PipelineQueueLabelFairShareGroupBy:
+			groupBy := apistructs.PipelineQueueFairShareGroupBy(v)
+			if !groupBy.IsValid() {
+				return cfg, fmt.Errorf("invalid fair share group by: %s", v)
+			}
+			cfg.GroupBy = groupBy
+		case k == apistructs.PipelineQueueLabelFairShareAgingFactor:
+			agingFactor, err := strconv.ParseFloat(v, 64)
+			if err != nil || agingFactor < 0 {
+				return cfg, fmt.Errorf("invalid fair share aging factor: %s, must >= 0", v)
+			}
+			cfg.AgingFactor = agingFactor
+		case k == apistructs.PipelineQueueLabelFairShareDefaultWeight:
+			weight, err := parseWeight(v)
+			if err != nil {
+				return cfg, err
+			}
+			cfg.DefaultWeight = weight
+		case strings.HasPrefix(k, apistructs.PipelineQueueLabelFairShareWeightPrefix):
+			group := strings.TrimPrefix(k, apistructs.PipelineQueueLabelFairShareWeightPrefix)
+			if group == "" {
+				return cfg, fmt.Errorf("missing group of fair share weight label: %s", k)
+			}
+			weight, err := parseWeight(v)
+			if err != nil {
+				return cfg, err
+			}
+			cfg.Weights[group] = weight
+		}
+	}
+	return cfg, nil
+}
+
+func parseWeight(v string) (int64, error) {
+	weight, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || weight <= 0 {
+		return 0, fmt.Errorf("invalid fair share weight: %s, must > 0", v)
+	}
+	return weight, nil
+}
+
+// WeightOf return weight of the group.
+func (c Config) WeightOf(group string) int64 {
+	if weight, ok := c.Weights[group]; ok {
+		return weight
+	}
+	if c.DefaultWeight > 0 {
+		return c.DefaultWeight
+	}
+	return defaultWeight
+}
+
+// GroupLabelKey return the pipeline label key to get group.
+func (c Config) GroupLabelKey() string {
+	if c.GroupBy == apistructs.PipelineQueueFairShareGroupByApp {
+		return apistructs.LabelAppID
+	}
+	return apistructs.LabelProjectID
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fairshare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, apistructs.PipelineQueueFairShareGroupByProject, cfg.GroupBy)
+	assert.Equal(t, int64(1), cfg.WeightOf("1"))
+	assert.Equal(t, apistructs.LabelProjectID, cfg.GroupLabelKey())
+
+	cfg, err = ParseConfig(map[string]string{
+		apistructs.PipelineQueueLabelFairShareGroupBy:             "app",
+		apistructs.PipelineQueueLabelFairShareAgingFactor:         "0.5",
+		apistructs.PipelineQueueLabelFairShareDefaultWeight:       "2",
+		apistructs.PipelineQueueLabelFairShareWeightPrefix + "10": "5",
+		"other": "value",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, apistructs.PipelineQueueFairShareGroupByApp, cfg.GroupBy)
+	assert.Equal(t, 0.5, cfg.AgingFactor)
+	assert.Equal(t, int64(5), cfg.WeightOf("10"))
+	assert.Equal(t, int64(2), cfg.WeightOf("11"))
+	assert.Equal(t, apistructs.LabelAppID, cfg.GroupLabelKey())
+
+	invalidLabels := []map[string]string{
+		{apistructs.PipelineQueueLabelFairShareGroupBy: "org"},
+		{apistructs.PipelineQueueLabelFairShareAgingFactor: "-1"},
+		{apistructs.PipelineQueueLabelFairShareDefaultWeight: "0"},
+		{apistructs.PipelineQueueLabelFairShareWeightPrefix + "1": "a"},
+		{apistructs.PipelineQueueLabelFairShareWeightPrefix: "1"},
+	}
+	for _, labels := range invalidLabels {
+		_, err := ParseConfig(labels)
+		assert.Error(t, err, labels)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fairshare
+
+import (
+	"sort"
+	"time"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/pkg/queue/priorityqueue"
+)
+
+// Candidate is a pending item with its group.
+type Candidate struct {
+	Item  priorityqueue.Item
+	Group string
+}
+
+// Order return pending items in weighted fair share order.
+//
+// Items of the same group keep the input order (priority order of pending queue).
+// Across groups, the group with the smallest virtual share goes first:
+//
+//	share = (processing + already ordered) / weight - agingFactor * waiting minutes of group head
+//
+// Ties are broken by head priority, head creation time and group name, so the order is deterministic for the same now.
+func (c Config) Order(candidates []Candidate, processingByGroup map[string]int64, now time.Time) []priorityqueue.Item {
+	itemsByGroup := make(map[string][]priorityqueue.Item)
+	var groups []string
+	for _, candidate := range candidates {
+		if _, ok := itemsByGroup[candidate.Group]; !ok {
+			groups = append(groups, candidate.Group)
+		}
+		itemsByGroup[candidate.Group] = append(itemsByGroup[candidate.Group], candidate.Item)
+	}
+	sort.Strings(groups)
+
+	orderedByGroup := make(map[string]int64, len(groups))
+	ordered := make([]priorityqueue.Item, 0, len(candidates))
+	for len(ordered) < len(candidates) {
+		var (
+			bestGroup string
+			bestShare float64
+			found     bool
+		)
+		for _, group := range groups {
+			items := itemsByGroup[group]
+			if len(items) == 0 {
+				continue
+			}
+			share := c.virtualShare(group, processingByGroup[group]+orderedByGroup[group], items[0], now)
+			if !found || share < bestShare || (share == bestShare && headHasHigherOrder(items[0], itemsByGroup[bestGroup][0])) {
+				bestGroup, bestShare, found = group, share, true
+			}
+		}
+		ordered = append(ordered, itemsByGroup[bestGroup][0])
+		itemsByGroup[bestGroup] = itemsByGroup[bestGroup][1:]
+		orderedByGroup[bestGroup]++
+	}
+	return ordered
+}
+
+func (c Config) virtualShare(group string, occupied int64, head priorityqueue.Item, now time.Time) float64 {
+	share := float64(occupied) / float64(c.WeightOf(group))
+	if c.AgingFactor > 0 {
+		if waiting := now.Sub(head.CreationTime()); waiting > 0 {
+			share -= c.AgingFactor * waiting.Minutes()
+		}
+	}
+	return share
+}
+
+// headHasHigherOrder is same as priority queue, groups are already sorted by name
+func headHasHigherOrder(left, right priorityqueue.Item) bool {
+	if left.Priority() != right.Priority() {
+		return left.Priority() > right.Priority()
+	}
+	return left.CreationTime().Before(right.CreationTime())
+}
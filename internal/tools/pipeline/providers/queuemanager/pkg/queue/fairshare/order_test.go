@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fairshare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/pkg/queue/priorityqueue"
+)
+
+func orderedKeys(items []priorityqueue.Item) []string {
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, item.Key())
+	}
+	return keys
+}
+
+func TestConfig_Order(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	// project a pushes many pipelines before project b
+	candidates := []Candidate{
+		{Item: priorityqueue.NewItem("a1", 10, now), Group: "a"},
+		{Item: priorityqueue.NewItem("a2", 10, now.Add(time.Second)), Group: "a"},
+		{Item: priorityqueue.NewItem("a3", 10, now.Add(2*time.Second)), Group: "a"},
+		{Item: priorityqueue.NewItem("a4", 10, now.Add(3*time.Second)), Group: "a"},
+		{Item: priorityqueue.NewItem("b1", 10, now.Add(4*time.Second)), Group: "b"},
+		{Item: priorityqueue.NewItem("b2", 10, now.Add(5*time.Second)), Group: "b"},
+	}
+
+	// same weight, round robin
+	cfg := Config{}
+	assert.Equal(t, []string{"a1", "b1", "a2", "b2", "a3", "a4"}, orderedKeys(cfg.Order(candidates, nil, now)))
+
+	// processing pipelines are counted
+	assert.Equal(t, []string{"b1", "b2", "a1", "a2", "a3", "a4"},
+		orderedKeys(cfg.Order(candidates, map[string]int64{"a": 2}, now)))
+
+	// weighted
+	cfg = Config{Weights: map[string]int64{"a": 2}}
+	assert.Equal(t, []string{"a1", "b1", "a2", "a3", "b2", "a4"}, orderedKeys(cfg.Order(candidates, nil, now)))
+
+	// deterministic
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, orderedKeys(cfg.Order(candidates, nil, now)), orderedKeys(cfg.Order(candidates, nil, now)))
+	}
+}
+
+func TestConfig_OrderWithAging(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	candidates := []Candidate{
+		{Item: priorityqueue.NewItem("a1", 10, now.Add(-10*time.Minute)), Group: "a"},
+		{Item: priorityqueue.NewItem("b1", 10, now), Group: "b"},
+	}
+	processing := map[string]int64{"a": 3}
+	cfg := Config{}
+	assert.Equal(t, []string{"b1", "a1"}, orderedKeys(cfg.Order(candidates, processing, now)))
+
+	// a1 waits long enough
+	cfg = Config{AgingFactor: 0.5}
+	assert.Equal(t, []string{"a1", "b1"}, orderedKeys(cfg.Order(candidates, processing, now)))
+}
+
+func TestConfig_Stats(t *testing.T) {
+	cfg := Config{Weights: map[string]int64{"a": 3}}
+	stats := cfg.Stats(map[string]int64{"a": 1, "b": 3, "c": 0}, map[string]int64{"a": 5})
+	assert.Equal(t, []GroupStat{
+		{Group: "a", Weight: 3, ProcessingCount: 1, PendingCount: 5, ExpectedShare: 0.75, ActualShare: 0.25},
+		{Group: "b", Weight: 1, ProcessingCount: 3, ExpectedShare: 0.25, ActualShare: 0.75},
+	}, stats)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fairshare
+
+import "sort"
+
+// GroupStat is the fairness metrics of one group.
+type GroupStat struct {
+	Group           string
+	Weight          int64
+	ProcessingCount int64
+	PendingCount    int64
+	// ExpectedShare is weight / total weight of active groups
+	ExpectedShare float64
+	// ActualShare is processing count / total processing count
+	ActualShare float64
+}
+
+// Stats return fairness metrics of active groups, which have processing or pending items, sorted by group.
+func (c Config) Stats(processingByGroup, pendingByGroup map[string]int64) []GroupStat {
+	groupSet := make(map[string]struct{})
+	var totalProcessing int64
+	for group, count := range processingByGroup {
+		if count > 0 {
+			groupSet[group] = struct{}{}
+			totalProcessing += count
+		}
+	}
+	for group, count := range pendingByGroup {
+		if count > 0 {
+			groupSet[group] = struct{}{}
+		}
+	}
+	groups := make([]string, 0, len(groupSet))
+	var totalWeight int64
+	for group := range groupSet {
+		groups = append(groups, group)
+		totalWeight += c.WeightOf(group)
+	}
+	sort.Strings(groups)
+
+	stats := make([]GroupStat, 0, len(groups))
+	for _, group := range groups {
+		stat := GroupStat{
+			Group:           group,
+			Weight:          c.WeightOf(group),
+			ProcessingCount: processingByGroup[group],
+			PendingCount:    pendingByGroup[group],
+		}
+		if totalWeight > 0 {
+			stat.ExpectedShare = float64(stat.Weight) / float64(totalWeight)
+		}
+		if totalProcessing > 0 {
+			stat.ActualShare = float64(stat.ProcessingCount) / float64(totalProcessing)
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/erda-project/erda-proto-go/core/pipeline/queue/pb"
 	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/pkg/queue/fairshare"
 	"github.com/erda-project/erda/internal/tools/pipeline/services/apierrors"
 	"github.com/erda-project/erda/pkg/strutil"
 )
@@ -129,6 +130,10 @@ func (p *provider) ValidateQueueCreateRequest(req *pb.QueueCreateRequest) error
 	if req.MaxMemoryMB < 0 {
 		return fmt.Errorf("max memory(MB) must >= 0")
 	}
+	// fair share config
+	if _, err := fairshare.ParseConfig(req.Labels); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -142,6 +147,14 @@ func (p *provider) ValidateQueueUpdateRequest(req *pb.QueueUpdateRequest) error
 	if req.PipelineSource != "" {
 		return fmt.Errorf("cannot change queue's source")
 	}
+	// mode
+	if req.Mode != "" && !apistructs.PipelineQueueMode(req.Mode).IsValid() {
+		return fmt.Errorf("invalid mode: %s", req.Mode)
+	}
+	// fair share config
+	if _, err := fairshare.ParseConfig(req.Labels); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -24,6 +24,8 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
 )
 
+const concurrencyGroupQueueIDPrefix = "concurrency-group:"
+
 // defaultQueue is used to implement Queue.
 type defaultQueue struct {
 	// pq is original pipeline queue.
@@ -41,6 +43,9 @@ type defaultQueue struct {
 	// dbClient
 	dbClient *dbclient.Client
 
+	// concurrencyGroupKey is set when queue is a virtual queue of concurrency group
+	concurrencyGroupKey string
+
 	lock sync.RWMutex
 
 	// started represents queue started handle process
@@ -81,10 +86,30 @@ func WithDBClient(dbClient *dbclient.Client) Option {
 	}
 }
 
+// WithConcurrencyGroup make queue as a virtual queue of concurrency group.
+func WithConcurrencyGroup(groupKey string) Option {
+	return func(q *defaultQueue) {
+		q.concurrencyGroupKey = groupKey
+	}
+}
+
 func (q *defaultQueue) ID() string {
+	if q.concurrencyGroupKey != "" {
+		return MakeConcurrencyGroupQueueID(q.concurrencyGroupKey)
+	}
 	return strconv.FormatUint(q.pq.ID, 10)
 }
 
+// MakeConcurrencyGroupQueueID return the id of concurrency group queue, which won't conflict with real queue id.
+func MakeConcurrencyGroupQueueID(groupKey string) string {
+	return concurrencyGroupQueueIDPrefix + groupKey
+}
+
+// IsConcurrencyGroupQueue return true if queue is a virtual queue of concurrency group.
+func (q *defaultQueue) IsConcurrencyGroupQueue() bool {
+	return q.concurrencyGroupKey != ""
+}
+
 func (q *defaultQueue) needReRangePendingQueue() bool {
 	q.lock.RLock()
 	defer q.lock.RUnlock()
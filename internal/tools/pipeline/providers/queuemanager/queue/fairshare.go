@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda-proto-go/core/pipeline/queue/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/pkg/queue/fairshare"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/pkg/queue/priorityqueue"
+)
+
+func (q *defaultQueue) isFairShareMode() bool {
+	return q.pq.Mode == apistructs.PipelineQueueModeFairShare.String()
+}
+
+// fairShareConfig parse config from queue labels, labels are already validated when create or update queue.
+func (q *defaultQueue) fairShareConfig() fairshare.Config {
+	cfg, err := fairshare.ParseConfig(q.pq.Labels)
+	if err != nil {
+		logrus.Warnf("queueManager: queueID: %s, queueName: %s, invalid fair share config, use default, err: %v", q.ID(), q.pq.Name, err)
+	}
+	return cfg
+}
+
+// getItemFairShareGroup return project or app of the pipeline, lock outside.
+func (q *defaultQueue) getItemFairShareGroup(item priorityqueue.Item, cfg fairshare.Config) string {
+	p := q.pipelineCaches[parsePipelineIDFromQueueItem(item)]
+	if p == nil {
+		return ""
+	}
+	return p.GetLabel(cfg.GroupLabelKey())
+}
+
+// rangePending range pending items by queue mode.
+// For fair share mode, items are ordered before range, so items added during range will be handled at next range.
+func (q *defaultQueue) rangePending(f func(item priorityqueue.Item) (stopRange bool)) {
+	if !q.isFairShareMode() {
+		q.eq.PendingQueue().Range(f)
+		return
+	}
+	for _, item := range q.orderPendingByFairShare(time.Now()) {
+		if f(item) {
+			return
+		}
+	}
+}
+
+func (q *defaultQueue) orderPendingByFairShare(now time.Time) []priorityqueue.Item {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	cfg := q.fairShareConfig()
+	processingByGroup := make(map[string]int64)
+	q.eq.ProcessingQueue().Range(func(item priorityqueue.Item) (stopRange bool) {
+		processingByGroup[q.getItemFairShareGroup(item, cfg)]++
+		return false
+	})
+	var candidates []fairshare.Candidate
+	q.eq.PendingQueue().Range(func(item priorityqueue.Item) (stopRange bool) {
+		candidates = append(candidates, fairshare.Candidate{Item: item, Group: q.getItemFairShareGroup(item, cfg)})
+		return false
+	})
+	return cfg.Order(candidates, processingByGroup, now)
+}
+
+// fairShareUsage return fairness metrics of queue, lock outside.
+func (q *defaultQueue) fairShareUsage() []*pb.QueueFairShareItem {
+	cfg := q.fairShareConfig()
+	processingByGroup := make(map[string]int64)
+	q.eq.ProcessingQueue().Range(func(item priorityqueue.Item) (stopRange bool) {
+		processingByGroup[q.getItemFairShareGroup(item, cfg)]++
+		return false
+	})
+	pendingByGroup := make(map[string]int64)
+	q.eq.PendingQueue().Range(func(item priorityqueue.Item) (stopRange bool) {
+		pendingByGroup[q.getItemFairShareGroup(item, cfg)]++
+		return false
+	})
+	var details []*pb.QueueFairShareItem
+	for _, stat := range cfg.Stats(processingByGroup, pendingByGroup) {
+		details = append(details, &pb.QueueFairShareItem{
+			Group:           stat.Group,
+			Weight:          stat.Weight,
+			ProcessingCount: stat.ProcessingCount,
+			PendingCount:    stat.PendingCount,
+			ExpectedShare:   stat.ExpectedShare,
+			ActualShare:     stat.ActualShare,
+		})
+	}
+	return details
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda-proto-go/core/pipeline/queue/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+func TestFairShareOrderAndUsage(t *testing.T) {
+	q := New(&pb.Queue{
+		ID:          1,
+		Mode:        apistructs.PipelineQueueModeFairShare.String(),
+		Concurrency: 10,
+		Labels:      map[string]string{apistructs.PipelineQueueLabelFairShareWeightPrefix + "2": "2"},
+	})
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	addPipeline := func(id uint64, projectID string, created time.Time) {
+		p := &spec.Pipeline{
+			PipelineBase:  spec.PipelineBase{ID: id, Status: apistructs.PipelineStatusQueue, TimeCreated: &created},
+			PipelineExtra: spec.PipelineExtra{NormalLabels: map[string]string{apistructs.LabelProjectID: projectID}},
+		}
+		q.pipelineCaches[p.ID] = p
+		q.eq.Add(makeItemKey(p), 10, created)
+	}
+	// project 1 pushes pipelines first
+	addPipeline(1, "1", now)
+	addPipeline(2, "1", now.Add(time.Second))
+	addPipeline(3, "1", now.Add(2*time.Second))
+	addPipeline(4, "2", now.Add(3*time.Second))
+	addPipeline(5, "2", now.Add(4*time.Second))
+
+	var keys []string
+	for _, item := range q.orderPendingByFairShare(now) {
+		keys = append(keys, item.Key())
+	}
+	assert.Equal(t, []string{"1", "4", "5", "2", "3"}, keys)
+
+	usage := q.Usage()
+	assert.Equal(t, 2, len(usage.FairShareDetails))
+	assert.Equal(t, "1", usage.FairShareDetails[0].Group)
+	assert.Equal(t, int64(3), usage.FairShareDetails[0].PendingCount)
+	assert.Equal(t, int64(2), usage.FairShareDetails[1].Weight)
+}
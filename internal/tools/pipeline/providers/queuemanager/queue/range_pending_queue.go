@@ -63,7 +63,8 @@ func (q *defaultQueue) RangePendingQueue() {
 		}
 	}()
 	// TODO: query items every cycle instead of using original passed range, support items priority swap
-	q.eq.PendingQueue().Range(func(item priorityqueue.Item) (stopRange bool) {
+	// items are ordered by fair share if queue mode is fair share
+	q.rangePending(func(item priorityqueue.Item) (stopRange bool) {
 		// fast reRange
 		defer func() {
 			if q.needReRangePendingQueue() {
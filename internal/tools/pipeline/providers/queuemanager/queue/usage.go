@@ -74,7 +74,7 @@ func (q *defaultQueue) Usage() pb.QueueUsage {
 		return false
 	})
 
-	return pb.QueueUsage{
+	usage := pb.QueueUsage{
 		InUseCPU:          inUseCPU,
 		InUseMemoryMB:     inUseMemoryMB,
 		RemainingCPU:      numeral.SubFloat64(q.pq.MaxCPU, inUseCPU),
@@ -84,4 +84,11 @@ func (q *defaultQueue) Usage() pb.QueueUsage {
 		ProcessingDetails: processingDetails,
 		PendingDetails:    pendingDetails,
 	}
+
+	// fairness
+	if q.isFairShareMode() {
+		usage.FairShareDetails = q.fairShareUsage()
+	}
+
+	return usage
 }
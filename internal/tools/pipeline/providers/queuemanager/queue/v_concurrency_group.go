@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"fmt"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/pkg/queue/priorityqueue"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/types"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+// ValidateConcurrencyGroup only one pipeline of the same concurrency group can be processing at the same time.
+func (q *defaultQueue) ValidateConcurrencyGroup(tryPopP *spec.Pipeline) apistructs.PipelineQueueValidateResult {
+	groupKey, ok := tryPopP.GetConcurrencyGroupKey()
+	if !ok {
+		return types.SuccessValidateResult
+	}
+	var processingPipelineID uint64
+	q.eq.ProcessingQueue().Range(func(item priorityqueue.Item) (stopRange bool) {
+		pipelineID := parsePipelineIDFromQueueItem(item)
+		existP := q.pipelineCaches[pipelineID]
+		if existP == nil || existP.ID == tryPopP.ID {
+			return false
+		}
+		if existGroupKey, ok := existP.GetConcurrencyGroupKey(); ok && existGroupKey == groupKey {
+			processingPipelineID = pipelineID
+			return true
+		}
+		return false
+	})
+	if processingPipelineID > 0 {
+		return apistructs.PipelineQueueValidateResult{
+			Success: false,
+			Reason: fmt.Sprintf("Concurrency group is occupied, group: %s, processing pipeline: %d",
+				groupKey, processingPipelineID),
+		}
+	}
+	return types.SuccessValidateResult
+}
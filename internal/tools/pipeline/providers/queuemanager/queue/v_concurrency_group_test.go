@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda-proto-go/core/pipeline/queue/pb"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/queuemanager/pkg/queue/priorityqueue"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+func TestValidateConcurrencyGroup(t *testing.T) {
+	makePipeline := func(id uint64, groupKey string) *spec.Pipeline {
+		p := &spec.Pipeline{PipelineBase: spec.PipelineBase{ID: id}}
+		if groupKey != "" {
+			p.Extra.ConcurrencyGroup = &spec.ConcurrencyGroup{Key: groupKey}
+		}
+		return p
+	}
+	q := New(&pb.Queue{ID: 1, Concurrency: 10})
+	processing := makePipeline(1, "dice/1/deploy-master")
+	q.pipelineCaches[processing.ID] = processing
+	q.eq.ProcessingQueue().Add(priorityqueue.NewItem(makeItemKey(processing), 10, time.Now()))
+
+	assert.True(t, q.ValidateConcurrencyGroup(makePipeline(2, "")).Success)
+	assert.True(t, q.ValidateConcurrencyGroup(makePipeline(3, "dice/1/deploy-develop")).Success)
+	assert.True(t, q.ValidateConcurrencyGroup(processing).Success)
+	assert.False(t, q.ValidateConcurrencyGroup(makePipeline(4, "dice/1/deploy-master")).Success)
+}
+
+func TestConcurrencyGroupQueueID(t *testing.T) {
+	q := New(&pb.Queue{ID: 1}, WithConcurrencyGroup("dice/1/deploy-master"))
+	assert.True(t, q.IsConcurrencyGroupQueue())
+	assert.Equal(t, "concurrency-group:dice/1/deploy-master", q.ID())
+
+	q = New(&pb.Queue{ID: 1})
+	assert.False(t, q.IsConcurrencyGroupQueue())
+	assert.Equal(t, "1", q.ID())
+}
@@ -26,11 +26,18 @@ func (q *defaultQueue) validatePipeline(p *spec.Pipeline) apistructs.PipelineQue
 	if result.IsFailed() {
 		return result
 	}
-	// free resources
-	result = q.ValidateFreeResources(p)
+	// concurrency group
+	result = q.ValidateConcurrencyGroup(p)
 	if result.IsFailed() {
 		return result
 	}
+	// free resources, concurrency group queue doesn't limit resources
+	if !q.IsConcurrencyGroupQueue() {
+		result = q.ValidateFreeResources(p)
+		if result.IsFailed() {
+			return result
+		}
+	}
 
 	// default result
 	return types.SuccessValidateResult
@@ -25,6 +25,7 @@ type Queue interface {
 	Start(stopCh chan struct{})
 	ID() string
 	IsStrictMode() bool
+	IsConcurrencyGroupQueue() bool
 	Usage() pb.QueueUsage
 	Update(pq *pb.Queue)
 	RangePendingQueue()
@@ -33,6 +33,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/cron/compensator"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgepipeline_register"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgereporter"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/reconciler/rutil"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/reconciler/schedulabletask"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/resourcegc"
@@ -72,6 +73,7 @@ type defaultPipelineReconciler struct {
 	st              schedulabletask.Interface
 	resourceGC      resourcegc.Interface
 	cronCompensator compensator.Interface
+	pipelineTrigger pipelinetrigger.Interface
 	cache           cache.Interface
 	r               *provider
 	edgeReporter    edgereporter.Interface
@@ -175,6 +177,7 @@ func (pr *defaultPipelineReconciler) GetTasksCanBeConcurrentlyScheduled(ctx cont
 	if err != nil {
 		return nil, err
 	}
+	schedulableTasks = pr.limitMatrixTasksParallelism(allTasks, schedulableTasks)
 	var filteredTasks []*spec.PipelineTask
 	for _, task := range schedulableTasks {
 		_, onProcessing := pr.processingTasks.LoadOrStore(task.Name, struct{}{})
@@ -214,6 +217,7 @@ func (pr *defaultPipelineReconciler) ReconcileOneSchedulableTask(ctx context.Con
 	go metrics.TaskGaugeProcessingAdd(*task, 1)
 	tr.ReconcileOneTaskUntilDone(ctx, p, task)
 	pr.releaseTaskAfterReconciled(ctx, p, task)
+	pr.cancelRunningMatrixSiblings(ctx, p, task)
 	go metrics.TaskGaugeProcessingAdd(*task, -1)
 	pr.chanToTriggerNextLoop <- struct{}{}
 }
@@ -277,6 +281,8 @@ func (pr *defaultPipelineReconciler) TeardownAfterReconcileDone(ctx context.Cont
 
 	// cron compensator
 	pr.cronCompensator.PipelineCronCompensate(ctx, p.ID)
+	// on.pipeline triggers
+	pr.pipelineTrigger.TriggerDownstream(ctx, p)
 	// resource gc
 	pr.resourceGC.WaitGC(p.Extra.Namespace, p.ID, p.GetResourceGCTTL())
 	// clear pipeline cache
@@ -19,7 +19,10 @@ import (
 
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/commonutil/statusutil"
+	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor"
+	"github.com/erda-project/erda/internal/tools/pipeline/pipengine/actionexecutor/types"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
 )
 
 // updateCalculatedPipelineStatusForTaskUseField by:
@@ -107,3 +110,106 @@ func (pr *defaultPipelineReconciler) setTotalTaskNumberBeforeReconcilePipeline(c
 	pr.setTotalTaskNumber(len(allTasks))
 	return nil
 }
+
+// limitMatrixTasksParallelism filter schedulable tasks to make sure the number of processing tasks
+// expanded from the same matrix action not exceeds strategy max-parallel.
+func (pr *defaultPipelineReconciler) limitMatrixTasksParallelism(allTasks, schedulableTasks []*spec.PipelineTask) []*spec.PipelineTask {
+	schedulableTaskMap := make(map[string]struct{}, len(schedulableTasks))
+	for _, task := range schedulableTasks {
+		schedulableTaskMap[task.NodeName()] = struct{}{}
+	}
+
+	// count processing tasks of each matrix group
+	processingGroupNum := make(map[pipelineyml.ActionAlias]int)
+	for _, task := range allTasks {
+		strategy := task.Extra.Action.Strategy
+		if strategy == nil || strategy.MaxParallel <= 0 {
+			continue
+		}
+		if _, onProcessing := pr.processingTasks.Load(task.NodeName()); onProcessing {
+			processingGroupNum[strategy.Group]++
+		}
+	}
+
+	// keep the order of allTasks, so expanded tasks are scheduled by index
+	var result []*spec.PipelineTask
+	for _, task := range allTasks {
+		if _, ok := schedulableTaskMap[task.NodeName()]; !ok {
+			continue
+		}
+		strategy := task.Extra.Action.Strategy
+		if strategy == nil || strategy.MaxParallel <= 0 {
+			result = append(result, task)
+			continue
+		}
+		if _, onProcessing := pr.processingTasks.Load(task.NodeName()); onProcessing {
+			result = append(result, task)
+			continue
+		}
+		if processingGroupNum[strategy.Group] >= strategy.MaxParallel {
+			continue
+		}
+		processingGroupNum[strategy.Group]++
+		result = append(result, task)
+	}
+	return result
+}
+
+// listProcessedTasks return all reconciled tasks.
+func (pr *defaultPipelineReconciler) listProcessedTasks() []*spec.PipelineTask {
+	var tasks []*spec.PipelineTask
+	pr.processedTasks.Range(func(key, value interface{}) bool {
+		if t, ok := value.(*spec.PipelineTask); ok {
+			tasks = append(tasks, t)
+		}
+		return true
+	})
+	return tasks
+}
+
+// isFailedOnlyInMatrixGroup return whether all failed tasks are expanded from the same matrix action.
+// failures of other tasks, such as upstream tasks, still make tasks of the group no-need-by-system.
+func isFailedOnlyInMatrixGroup(tasks []*spec.PipelineTask, group pipelineyml.ActionAlias) bool {
+	for _, task := range tasks {
+		if !task.Status.IsFailedStatus() {
+			continue
+		}
+		strategy := task.Extra.Action.Strategy
+		if strategy == nil || strategy.Group != group {
+			return false
+		}
+	}
+	return true
+}
+
+// cancelRunningMatrixSiblings cancel running tasks expanded from the same matrix action when fail-fast is enabled,
+// tasks not scheduled yet will be set to no-need-by-system by if expression judgement.
+func (pr *defaultPipelineReconciler) cancelRunningMatrixSiblings(ctx context.Context, p *spec.Pipeline, failedTask *spec.PipelineTask) {
+	strategy := failedTask.Extra.Action.Strategy
+	if strategy == nil || !strategy.IsFailFast() || !failedTask.Status.IsFailedStatus() {
+		return
+	}
+	allTasks, err := pr.r.YmlTaskMergeDBTasks(p)
+	if err != nil {
+		pr.log.Errorf("failed to get tasks to cancel matrix siblings, pipelineID: %d, taskName: %s, err: %v", p.ID, failedTask.Name, err)
+		return
+	}
+	for _, task := range allTasks {
+		if task.Name == failedTask.Name || task.Status != apistructs.PipelineStatusRunning {
+			continue
+		}
+		if task.Extra.Action.Strategy == nil || task.Extra.Action.Strategy.Group != strategy.Group {
+			continue
+		}
+		executor, err := actionexecutor.GetManager().Get(types.Name(task.GetExecutorName()))
+		if err != nil {
+			pr.log.Errorf("failed to get executor to cancel matrix sibling, pipelineID: %d, taskID: %d, taskName: %s, err: %v", p.ID, task.ID, task.Name, err)
+			continue
+		}
+		if _, err := executor.Cancel(ctx, task); err != nil {
+			pr.log.Errorf("failed to cancel matrix sibling, pipelineID: %d, taskID: %d, taskName: %s, err: %v", p.ID, task.ID, task.Name, err)
+			continue
+		}
+		pr.log.Infof("matrix task %s failed with fail-fast, canceled sibling task, pipelineID: %d, taskID: %d, taskName: %s", failedTask.Name, p.ID, task.ID, task.Name)
+	}
+}
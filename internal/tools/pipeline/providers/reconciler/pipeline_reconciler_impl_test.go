@@ -24,6 +24,7 @@ import (
 
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml"
 )
 
 func Test_defaultPipelineReconciler_setTotalTaskNumberBeforeReconcilePipeline(t *testing.T) {
@@ -206,3 +207,78 @@ func Test_defaultPipelineReconciler_calculateNewStatusByReconciledTasks(t *testi
 		t.Fatalf("should be success")
 	}
 }
+
+func Test_defaultPipelineReconciler_limitMatrixTasksParallelism(t *testing.T) {
+	newMatrixTask := func(name string) *spec.PipelineTask {
+		task := &spec.PipelineTask{Name: name}
+		task.Extra.Action.Strategy = &pipelineyml.Strategy{MaxParallel: 2, Group: "test"}
+		return task
+	}
+	allTasks := []*spec.PipelineTask{
+		{Name: "repo"},
+		newMatrixTask("test-1"),
+		newMatrixTask("test-2"),
+		newMatrixTask("test-3"),
+		newMatrixTask("test-4"),
+	}
+	pr := &defaultPipelineReconciler{}
+
+	// nothing processing, only the first two matrix tasks can be scheduled
+	result := pr.limitMatrixTasksParallelism(allTasks, allTasks)
+	var names []string
+	for _, task := range result {
+		names = append(names, task.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"repo", "test-1", "test-2"}) {
+		t.Fatalf("should schedule repo, test-1 and test-2, actually %v", names)
+	}
+
+	// test-1 is processing, only one more matrix task can be scheduled
+	pr.processingTasks.Store("test-1", struct{}{})
+	result = pr.limitMatrixTasksParallelism(allTasks, allTasks[2:])
+	names = nil
+	for _, task := range result {
+		names = append(names, task.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"test-2"}) {
+		t.Fatalf("should only schedule test-2, actually %v", names)
+	}
+}
+
+func Test_isFailedOnlyInMatrixGroup(t *testing.T) {
+	newTask := func(status apistructs.PipelineStatus, group pipelineyml.ActionAlias) *spec.PipelineTask {
+		task := &spec.PipelineTask{Status: status}
+		if group != "" {
+			task.Extra.Action.Strategy = &pipelineyml.Strategy{Group: group}
+		}
+		return task
+	}
+	tests := []struct {
+		name  string
+		tasks []*spec.PipelineTask
+		want  bool
+	}{
+		{
+			name:  "failed in the same group",
+			tasks: []*spec.PipelineTask{newTask(apistructs.PipelineStatusSuccess, ""), newTask(apistructs.PipelineStatusFailed, "test")},
+			want:  true,
+		},
+		{
+			name:  "upstream failed",
+			tasks: []*spec.PipelineTask{newTask(apistructs.PipelineStatusFailed, ""), newTask(apistructs.PipelineStatusFailed, "test")},
+			want:  false,
+		},
+		{
+			name:  "failed in another group",
+			tasks: []*spec.PipelineTask{newTask(apistructs.PipelineStatusFailed, "build")},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFailedOnlyInMatrixGroup(tt.tasks, "test"); got != tt.want {
+				t.Fatalf("isFailedOnlyInMatrixGroup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
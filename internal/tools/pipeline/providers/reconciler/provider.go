@@ -32,6 +32,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgepipeline_register"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/edgereporter"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/leaderworker"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/reconciler/taskpolicy"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/resourcegc"
 )
@@ -48,6 +49,7 @@ type provider struct {
 	EdgeRegister    edgepipeline_register.Interface
 	ResourceGC      resourcegc.Interface
 	CronCompensator compensator.Interface
+	PipelineTrigger pipelinetrigger.Interface
 	EdgeReporter    edgereporter.Interface
 	ActionMgr       actionmgr.Interface
 	ActionAgentSvc  actionagent.Interface
@@ -69,6 +69,7 @@ func (r *provider) generatePipelineReconcilerForEachPipelineID() *defaultPipelin
 		st:                         &schedulabletask.DagImpl{},
 		resourceGC:                 r.ResourceGC,
 		cronCompensator:            r.CronCompensator,
+		pipelineTrigger:            r.PipelineTrigger,
 		cache:                      r.Cache,
 		r:                          r,
 		dbClient:                   r.dbClient,
@@ -18,7 +18,6 @@ import (
 	"context"
 	"fmt"
 	"regexp"
-	"strings"
 
 	"github.com/sirupsen/logrus"
 
@@ -163,36 +162,7 @@ func (tr *defaultTaskReconciler) handleParentSnippetTaskOutputs(snippetPipeline
 
 // calculatePipelineOutputs 计算 pipeline
 func (tr *defaultTaskReconciler) calculateAndUpdatePipelineOutputValues(p *spec.Pipeline, tasks []*spec.PipelineTask) ([]apistructs.PipelineOutputWithValue, error) {
-	// 所有任务的输出
-	allTaskOutputs := make(map[string]map[string]interface{})
-	for _, task := range tasks {
-		for _, meta := range task.GetMetadata() {
-			if allTaskOutputs[task.Name] == nil {
-				allTaskOutputs[task.Name] = make(map[string]interface{})
-			}
-			allTaskOutputs[task.Name][meta.Name] = meta.Value
-		}
-	}
-
-	// 根据定义塞入流水线级别的输出
-	var outputValues []apistructs.PipelineOutputWithValue
-	for _, define := range p.Extra.DefinedOutputs {
-		// handle ref v1
-		reffedTask, reffedKey, err := parsePipelineOutputRef(define.Ref)
-		if err == nil {
-			reffedValue := allTaskOutputs[reffedTask][reffedKey]
-			outputWithValue := apistructs.PipelineOutputWithValue{PipelineOutput: define, Value: reffedValue}
-			outputValues = append(outputValues, outputWithValue)
-		}
-
-		// handle ref v2
-		reffedTask, reffedKey, err = parsePipelineOutputRefV2(define.Ref)
-		if err == nil {
-			reffedValue := allTaskOutputs[reffedTask][reffedKey]
-			outputWithValue := apistructs.PipelineOutputWithValue{PipelineOutput: define, Value: reffedValue}
-			outputValues = append(outputValues, outputWithValue)
-		}
-	}
+	outputValues := p.CalculateOutputValues(tasks)
 
 	// update pipeline outputs
 	p.Snapshot.OutputValues = outputValues
@@ -203,34 +173,6 @@ func (tr *defaultTaskReconciler) calculateAndUpdatePipelineOutputValues(p *spec.
 	return outputValues, nil
 }
 
-// parsePipelineOutputRef 解析 pipeline 的 output ref 表达式
-func parsePipelineOutputRef(ref string) (string, string, error) {
-	ref = strings.TrimSpace(ref)
-	ref = strings.TrimPrefix(ref, "${")
-	ref = strings.TrimSuffix(ref, "}")
-	ss := strings.SplitN(ref, ":", 3)
-	if len(ss) < 3 {
-		return "", "", fmt.Errorf("invalid ref: %s", ref)
-	}
-	if ss[1] != "OUTPUT" {
-		return "", "", fmt.Errorf("ref is not output, ref: %s", ref)
-	}
-	return ss[0], ss[2], nil
-}
-
-// parsePipelineOutputRefV2 解析 pipeline 的 output ref 表达式
-// ${{ outputs.xxx.key }}
-func parsePipelineOutputRefV2(ref string) (string, string, error) {
-	ref = strings.TrimSpace(ref)
-	ref = strings.TrimPrefix(ref, "${{ outputs.")
-	ref = strings.TrimSuffix(ref, " }}")
-	ss := strings.SplitN(ref, ".", 2)
-	if len(ss) < 2 {
-		return "", "", fmt.Errorf("invalid ref: %s", ref)
-	}
-	return ss[0], ss[1], nil
-}
-
 // copyParentPipelineRunInfo 从父流水线拷贝执行信息
 func (tr *defaultTaskReconciler) copyParentPipelineRunInfo(snippetPipeline *spec.Pipeline, session ...dbclient.SessionOption) error {
 	// 从根流水线拷贝执行信息到嵌套流水线
@@ -241,6 +183,9 @@ func (tr *defaultTaskReconciler) copyParentPipelineRunInfo(snippetPipeline *spec
 	}
 	snippetPipeline.Snapshot.PlatformSecrets = rootPipeline.Snapshot.PlatformSecrets
 	snippetPipeline.Snapshot.Secrets = rootPipeline.Snapshot.Secrets
+	// external secrets are resolved by root pipeline yml, snippet can only reference the same ones
+	snippetPipeline.Snapshot.ExternalSecrets = rootPipeline.Snapshot.ExternalSecrets
+	snippetPipeline.Snapshot.EncryptSecretKeys = rootPipeline.Snapshot.EncryptSecretKeys
 	snippetPipeline.Snapshot.Envs = rootPipeline.Snapshot.Envs
 
 	// 处理 runParams，嵌套流水线的 runParams 即为 parentSnippetTask 的 params，已经在创建时存入，其中占位符需要被替换
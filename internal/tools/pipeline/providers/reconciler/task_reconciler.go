@@ -432,7 +432,8 @@ func (tr *defaultTaskReconciler) judgeIfExpression(ctx context.Context, p *spec.
 			needSetToNoNeedBySystem = true
 		}
 		// failed but not stopByUser -> check if expression
-		if task.Extra.Action.If == "" {
+		// matrix task with fail-fast disabled should still run if only tasks of the same matrix failed
+		if task.Extra.Action.If == "" && !tr.canRunAfterMatrixFailure(task) {
 			needSetToNoNeedBySystem = true
 		}
 		if !needSetToNoNeedBySystem {
@@ -448,6 +449,15 @@ func (tr *defaultTaskReconciler) judgeIfExpression(ctx context.Context, p *spec.
 	return nil
 }
 
+// canRunAfterMatrixFailure return whether task expanded from matrix with fail-fast disabled can still run.
+func (tr *defaultTaskReconciler) canRunAfterMatrixFailure(task *spec.PipelineTask) bool {
+	strategy := task.Extra.Action.Strategy
+	if strategy == nil || strategy.IsFailFast() {
+		return false
+	}
+	return isFailedOnlyInMatrixGroup(tr.pr.listProcessedTasks(), strategy.Group)
+}
+
 // overwriteTaskWithLatest overwrite current task with latest
 // the same as taskrun.fetchlatesttask, use one later when refactored
 func (tr *defaultTaskReconciler) overwriteTaskWithLatest(task *spec.PipelineTask) error {
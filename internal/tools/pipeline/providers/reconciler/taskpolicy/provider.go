@@ -45,6 +45,7 @@ func (p *provider) Init(ctx servicehub.Context) error {
 		apistructs.TryLatestSuccessResultPolicyType: tryLastSuccessResult{p: p},
 		apistructs.NewRunPolicyType:                 newRun{p: p},
 		apistructs.TryLatestResultPolicyType:        tryLastResult{p: p},
+		apistructs.RetryPolicyType:                  retry{p: p},
 	}
 	return nil
 }
@@ -0,0 +1,36 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskpolicy
+
+import (
+	"context"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+// retry runs the task as new-run, the failed task is re-run by task reconciler.
+type retry struct {
+	p *provider
+}
+
+func (r retry) AdaptPolicy(ctx context.Context, task *spec.PipelineTask) error {
+	retryPolicy := task.Extra.Action.Policy.Retry.WithDefaults()
+	task.Extra.CurrentPolicy = apistructs.Policy{
+		Type:  apistructs.RetryPolicyType,
+		Retry: &retryPolicy,
+	}
+	return nil
+}
@@ -115,6 +115,10 @@ func (tr *TaskRun) waitOp(itr TaskOp, o *Elem) (result error) {
 				// append err loop
 				errs = append(errs, fmt.Sprintf("%v", err))
 			}
+			// retry
+			if err := tr.handleTaskRetry(); err != nil {
+				errs = append(errs, fmt.Sprintf("%v", err))
+			}
 
 			if len(errs) > 0 {
 				result = errors.Errorf("failed to %s task, err: %s", itr.Op(), strutil.Join(errs, "\n", true))
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskrun
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/task_uuid"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/taskerror"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/reconciler/rlog"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/loop"
+	"github.com/erda-project/erda/pkg/metadata"
+)
+
+var (
+	// keywords of error messages reported by executors
+	oomFailureKeywords       = []string{"OOMKilled", "OOM(Out Of Memory)"}
+	imagePullFailureKeywords = []string{"ImagePullBackOff", "ErrImagePull", "拉取镜像失败", "无效的镜像名"}
+)
+
+// handleTaskRetry re-run the failed task if retry policy declared and matched
+func (tr *TaskRun) handleTaskRetry() error {
+	if !tr.Task.Status.IsFailedStatus() || tr.Task.Status.IsStopByUser() || tr.Task.Status.IsNoNeedBySystem() ||
+		tr.Task.Status == apistructs.PipelineStatusAnalyzeFailed {
+		return nil
+	}
+	if tr.Task.IsSnippet {
+		return nil
+	}
+	policy := tr.Task.Extra.Action.Policy
+	if policy == nil || policy.Type != apistructs.RetryPolicyType {
+		return nil
+	}
+	retryPolicy := policy.Retry.WithDefaults()
+
+	// the first run is also an attempt
+	attempt := len(tr.Task.Extra.RetryAttempts) + 1
+	if attempt >= retryPolicy.MaxAttempts {
+		rlog.TDebugf(tr.P.ID, tr.Task.ID, "retry reached max attempts %d, stop retry", retryPolicy.MaxAttempts)
+		return nil
+	}
+
+	// task result is not kept in memory, use the latest one in db
+	var resultErrors taskerror.OrderedErrors
+	var meta metadata.Metadata
+	if latestTask, err := tr.DBClient.GetPipelineTask(tr.Task.ID); err != nil {
+		rlog.TWarnf(tr.P.ID, tr.Task.ID, "failed to get task result for retry, err: %v", err)
+	} else if latestTask.Result != nil {
+		resultErrors = latestTask.Result.Errors
+		meta = latestTask.Result.Metadata
+	}
+	errMsgs := getTaskErrorMsgs(tr.Task.Inspect.Errors, resultErrors)
+	failureClass, exitCode := classifyTaskFailure(tr.Task.Status, errMsgs, meta)
+	if !retryPolicy.Match(failureClass, exitCode) {
+		rlog.TDebugf(tr.P.ID, tr.Task.ID, "failure class %s (exit code: %d) not matched by retry policy, skip retry", failureClass, exitCode)
+		return nil
+	}
+
+	tr.Task.Extra.RetryAttempts = append(tr.Task.Extra.RetryAttempts, apistructs.PipelineTaskRetryAttempt{
+		Attempt:      attempt,
+		UUID:         task_uuid.MakeJobID(tr.Task),
+		Status:       tr.Task.Status,
+		FailureClass: failureClass,
+		ExitCode:     exitCode,
+		Errors:       errMsgs,
+		TimeBegin:    tr.Task.TimeBegin,
+		TimeEnd:      tr.Task.TimeEnd,
+		CostTimeSec:  tr.Task.CostTimeSec,
+	})
+	rlog.TWarnf(tr.P.ID, tr.Task.ID, "task failed with class %s at attempt %d, retry it", failureClass, attempt)
+
+	tr.resetTaskForRetry(retryPolicy)
+	return nil
+}
+
+func (tr *TaskRun) resetTaskForRetry(retryPolicy apistructs.TaskRetryPolicy) {
+	// Calculate sleep time
+	interval := loop.New(
+		loop.WithInterval(time.Second*time.Duration(retryPolicy.IntervalSec)),
+		loop.WithDeclineRatio(retryPolicy.DeclineRatio),
+		loop.WithDeclineLimit(time.Second*time.Duration(retryPolicy.DeclineLimitSec)),
+	).CalculateInterval(uint64(len(tr.Task.Extra.RetryAttempts)))
+	rlog.TDebugf(tr.P.ID, tr.Task.ID, "sleep %s before retry", interval.String())
+	time.Sleep(interval)
+
+	// reset task status
+	tr.Task.Status = apistructs.PipelineStatusAnalyzed
+	tr.Task.CostTimeSec = -1
+	tr.Task.QueueTimeSec = -1
+	tr.Task.Extra.TimeBeginQueue = time.Time{}
+	tr.Task.Extra.TimeEndQueue = time.Time{}
+	tr.Task.TimeEnd = time.Time{}
+	// errors are recorded in retry attempts
+	tr.Task.Inspect.Errors = taskerror.OrderedErrors{}
+	// reset volume
+	tr.Task.Context = spec.PipelineTaskContext{}
+	tr.Task.Extra.Volumes = nil
+	// reset tr flag
+	tr.FakeTimeout = false
+	tr.QuitQueueTimeout = false
+	tr.QuitWaitTimeout = false
+	tr.StopQueueLoop = false
+	tr.StopWaitLoop = false
+
+	tr.cleanTaskResult()
+}
+
+func getTaskErrorMsgs(errs ...taskerror.OrderedErrors) []string {
+	var msgs []string
+	for _, orderedErrors := range errs {
+		for _, err := range orderedErrors {
+			msgs = append(msgs, err.Msg)
+		}
+	}
+	return msgs
+}
+
+// classifyTaskFailure returns the failure class and exit code of the failed task
+func classifyTaskFailure(status apistructs.PipelineStatus, errMsgs []string, meta metadata.Metadata) (apistructs.TaskFailureClass, int) {
+	if status == apistructs.PipelineStatusTimeout {
+		return apistructs.TaskFailureClassTimeout, 0
+	}
+	if containsAnyKeyword(errMsgs, oomFailureKeywords) {
+		return apistructs.TaskFailureClassOOM, 0
+	}
+	if containsAnyKeyword(errMsgs, imagePullFailureKeywords) {
+		return apistructs.TaskFailureClassImagePull, 0
+	}
+	for _, field := range meta {
+		if field.Name != apistructs.ActionCallbackExitCode {
+			continue
+		}
+		if exitCode, err := strconv.Atoi(field.Value); err == nil && exitCode != 0 {
+			return apistructs.TaskFailureClassExitCode, exitCode
+		}
+	}
+	return apistructs.TaskFailureClassUnknown, 0
+}
+
+func containsAnyKeyword(msgs []string, keywords []string) bool {
+	for _, msg := range msgs {
+		for _, keyword := range keywords {
+			if strings.Contains(msg, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
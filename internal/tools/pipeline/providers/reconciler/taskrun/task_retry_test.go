@@ -0,0 +1,73 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskrun
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/pkg/metadata"
+)
+
+func Test_classifyTaskFailure(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       apistructs.PipelineStatus
+		errMsgs      []string
+		meta         metadata.Metadata
+		wantClass    apistructs.TaskFailureClass
+		wantExitCode int
+	}{
+		{
+			name:      "timeout",
+			status:    apistructs.PipelineStatusTimeout,
+			wantClass: apistructs.TaskFailureClassTimeout,
+		},
+		{
+			name:      "oom",
+			status:    apistructs.PipelineStatusFailed,
+			errMsgs:   []string{"OOM(Out Of Memory)故障，请合理配置内存配额"},
+			wantClass: apistructs.TaskFailureClassOOM,
+		},
+		{
+			name:      "image pull",
+			status:    apistructs.PipelineStatusFailed,
+			errMsgs:   []string{"failed to run job", "Back-off pulling image: ImagePullBackOff"},
+			wantClass: apistructs.TaskFailureClassImagePull,
+		},
+		{
+			name:         "exit code",
+			status:       apistructs.PipelineStatusFailed,
+			meta:         metadata.Metadata{{Name: apistructs.ActionCallbackExitCode, Value: "137"}},
+			wantClass:    apistructs.TaskFailureClassExitCode,
+			wantExitCode: 137,
+		},
+		{
+			name:      "unknown",
+			status:    apistructs.PipelineStatusError,
+			errMsgs:   []string{"failed to create job"},
+			wantClass: apistructs.TaskFailureClassUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, exitCode := classifyTaskFailure(tt.status, tt.errMsgs, tt.meta)
+			assert.Equal(t, tt.wantClass, class)
+			assert.Equal(t, tt.wantExitCode, exitCode)
+		})
+	}
+}
@@ -162,6 +162,10 @@ func (pre *prepare) makeTaskRun() (needRetry bool, err error) {
 	for k, v := range p.Snapshot.PlatformSecrets { // platformSecrets 的优先级更高
 		allSecrets[k] = v
 	}
+	// external secrets are keyed by reference, used to render ${{ secrets.scheme://path#key }}
+	for ref, v := range p.Snapshot.ExternalSecrets {
+		allSecrets[ref] = v
+	}
 	for fileName, fileUUID := range p.Snapshot.CmsDiceFiles {
 		// cmsDiceFiles 生成容器内的路径
 		// ((a.cert)) -> /.pipeline/container/cms/dice_files/a.cert
@@ -250,6 +254,10 @@ func (pre *prepare) makeTaskRun() (needRetry bool, err error) {
 		}
 		task.Extra.PrivateEnvs[newK] = v
 	}
+	// external secrets -> envs, so that agent can mask them by EncryptSecretKeys
+	for ref, v := range p.Snapshot.ExternalSecrets {
+		task.Extra.PrivateEnvs[env.GenExternalSecretEnvKey(ref)] = v
+	}
 	// action agent envs -> envs
 	for k, v := range agentDiceYmlJob.Envs {
 		// enable agent debug mode at dice.yml envs.
@@ -622,7 +630,8 @@ func contextVolumes(context spec.PipelineTaskContext) []metadata.MetadataField {
 
 func (pre *prepare) generateOpenapiTokenForPullBootstrapInfo(task *spec.PipelineTask) error {
 
-	if task.Type == apistructs.ActionTypeWait || task.Type == apistructs.ActionTypeAPITest || task.Type == apistructs.ActionTypeSnippet {
+	if task.Type == apistructs.ActionTypeWait || task.Type == apistructs.ActionTypeManualApproval ||
+		task.Type == apistructs.ActionTypeAPITest || task.Type == apistructs.ActionTypeSnippet {
 		return nil
 	}
 
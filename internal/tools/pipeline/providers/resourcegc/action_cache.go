@@ -0,0 +1,51 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegc
+
+import (
+	"context"
+	"time"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/actioncache"
+)
+
+// gcActionCaches periodically remove expired keyed action caches under ActionCacheRootDir
+func (r *provider) gcActionCaches(ctx context.Context) {
+	if r.Cfg.ActionCacheRootDir == "" {
+		return
+	}
+	r.doActionCacheGC(time.Now())
+
+	ticker := time.NewTicker(r.Cfg.ActionCacheGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.doActionCacheGC(time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *provider) doActionCacheGC(now time.Time) {
+	removed, err := actioncache.GCRoot(r.Cfg.ActionCacheRootDir, now, r.Cfg.ActionCacheDefaultTTL)
+	for _, entry := range removed {
+		r.Log.Infof("action cache gc: removed expired cache %s", entry)
+	}
+	if err != nil {
+		r.Log.Errorf("action cache gc: failed to gc %s, err: %v", r.Cfg.ActionCacheRootDir, err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda-infra/base/logs/logrusx"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/actioncache"
+)
+
+func TestDoActionCacheGC(t *testing.T) {
+	root, err := ioutil.TempDir("", "action-cache-gc")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	now := time.Now()
+	cacheDir := filepath.Join(root, "1", "2", "hash")
+	for _, e := range []actioncache.Entry{
+		{Key: "keep", CreatedAt: now, LastUsedAt: now},
+		{Key: "expired", CreatedAt: now, LastUsedAt: now.Add(-8 * 24 * time.Hour)},
+	} {
+		assert.NoError(t, os.MkdirAll(actioncache.EntriesDir(cacheDir), 0755))
+		assert.NoError(t, ioutil.WriteFile(actioncache.TarPath(cacheDir, e.Key), []byte("tar"), 0644))
+		assert.NoError(t, actioncache.WriteEntry(cacheDir, e))
+	}
+
+	r := &provider{
+		Cfg: &config{ActionCacheRootDir: root, ActionCacheDefaultTTL: 7 * 24 * time.Hour},
+		Log: logrusx.New(),
+	}
+	r.doActionCacheGC(now)
+
+	entries, err := actioncache.ListEntries(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "keep", entries[0].Key)
+}
@@ -17,6 +17,7 @@ package resourcegc
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/erda-project/erda-infra/base/logs"
 	"github.com/erda-project/erda-infra/base/servicehub"
@@ -28,7 +29,14 @@ import (
 	"github.com/erda-project/erda/pkg/jsonstore/etcd"
 )
 
-type config struct{}
+type config struct {
+	// ActionCacheRootDir is the action cache root dir mounted into pipeline, such as /netdata/actions/caches
+	// empty means action cache gc is disabled
+	ActionCacheRootDir    string        `file:"action_cache_root_dir" env:"ACTION_CACHE_ROOT_DIR"`
+	ActionCacheGCInterval time.Duration `file:"action_cache_gc_interval" env:"ACTION_CACHE_GC_INTERVAL" default:"1h"`
+	// ActionCacheDefaultTTL is used for cache entries without ttl, default 7 days
+	ActionCacheDefaultTTL time.Duration `file:"action_cache_default_ttl" env:"ACTION_CACHE_DEFAULT_TTL" default:"168h"`
+}
 
 type provider struct {
 	js       jsonstore.JsonStore
@@ -62,6 +70,7 @@ func (r *provider) Run(ctx context.Context) error {
 	// gc
 	r.LW.OnLeader(r.listenGC)
 	r.LW.OnLeader(r.compensateGCNamespaces)
+	r.LW.OnLeader(r.gcActionCaches)
 	return nil
 }
 
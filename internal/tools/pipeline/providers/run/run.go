@@ -29,6 +29,7 @@ import (
 	"github.com/erda-project/erda/internal/tools/pipeline/aop"
 	"github.com/erda-project/erda/internal/tools/pipeline/aop/aoptypes"
 	"github.com/erda-project/erda/internal/tools/pipeline/pkg/container_provider"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/env"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/definition/db"
 	"github.com/erda-project/erda/internal/tools/pipeline/services/apierrors"
 	"github.com/erda-project/erda/internal/tools/pipeline/spec"
@@ -70,6 +71,16 @@ func (s *provider) RunOnePipeline(ctx context.Context, req *pipelinepb.PipelineR
 			return nil, err
 		}
 	}
+	// 同一并发组下正在运行的流水线会被取消，否则排队等待
+	if p.Extra.ConcurrencyGroup != nil && p.Extra.ConcurrencyGroup.CancelInProgress {
+		err := s.Cancel.CancelInProgressPipelinesOfConcurrencyGroup(ctx, &p, &commonpb.IdentityInfo{
+			UserID:         req.UserID,
+			InternalClient: req.InternalClient,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	p.Extra.ConfigManageNamespaces = append(p.Extra.ConfigManageNamespaces, req.ConfigManageNamespaces...)
 
@@ -100,6 +111,16 @@ func (s *provider) RunOnePipeline(ctx context.Context, req *pipelinepb.PipelineR
 		return nil, apierrors.ErrRunPipeline.InternalError(err)
 	}
 
+	// fetch external secrets, always resolved at run time and not cached
+	externalSecrets, err := s.Secret.FetchExternalSecrets(ctx, &p)
+	if err != nil {
+		return nil, apierrors.ErrRunPipeline.InternalError(err)
+	}
+	// external secrets are injected as envs, mask them in logs like encrypted configs
+	for ref := range externalSecrets {
+		encryptSecretKeys = append(encryptSecretKeys, env.GenExternalSecretEnvKey(ref))
+	}
+
 	for k, v := range req.Secrets {
 		secrets[k] = v
 	}
@@ -127,6 +148,7 @@ func (s *provider) RunOnePipeline(ctx context.Context, req *pipelinepb.PipelineR
 	p.Snapshot.PipelineYml = p.PipelineYml
 	p.Snapshot.Secrets = secrets
 	p.Snapshot.PlatformSecrets = platformSecrets
+	p.Snapshot.ExternalSecrets = externalSecrets
 	p.Snapshot.CmsDiceFiles = cmsDiceFiles
 	// pipeline 运行时的参数
 	runParams, err := getRealRunParams(req.PipelineRunParams, p.PipelineYml)
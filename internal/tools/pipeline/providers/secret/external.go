@@ -0,0 +1,220 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/expression"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml/pexpr"
+)
+
+// Backend resolves external secrets referenced in pipeline yml, such as:
+//
+//	${{ secrets.vault://kv/app#password }}
+//	${{ secrets.k8s://namespace/name#key }}
+type Backend interface {
+	// Scheme return the scheme handled by this backend, such as vault, k8s, kms and file.
+	Scheme() string
+	// Resolve return values of keys stored at path, every key must be found.
+	Resolve(ctx context.Context, p *spec.Pipeline, path string, keys []string) (map[string]string, error)
+}
+
+// ExternalSecretRef is the reference of an external secret: <scheme>://<path>#<key>
+type ExternalSecretRef struct {
+	Scheme string
+	Path   string
+	Key    string
+}
+
+func (r ExternalSecretRef) String() string {
+	return fmt.Sprintf("%s://%s#%s", r.Scheme, r.Path, r.Key)
+}
+
+// ParseExternalSecretRef parse reference like vault://kv/app#password
+func ParseExternalSecretRef(ref string) (ExternalSecretRef, error) {
+	var r ExternalSecretRef
+	schemeIdx := strings.Index(ref, "://")
+	keyIdx := strings.LastIndex(ref, "#")
+	if schemeIdx <= 0 || keyIdx < schemeIdx+len("://") {
+		return r, fmt.Errorf("invalid external secret %q, must be <scheme>://<path>#<key>", ref)
+	}
+	r.Scheme = ref[:schemeIdx]
+	r.Path = ref[schemeIdx+len("://") : keyIdx]
+	r.Key = ref[keyIdx+1:]
+	if r.Path == "" || r.Key == "" {
+		return r, fmt.Errorf("invalid external secret %q, path and key are required", ref)
+	}
+	return r, nil
+}
+
+// FindExternalSecretRefs return deduplicated external secret references in pipeline yml
+func FindExternalSecretRefs(pipelineYml string) ([]ExternalSecretRef, error) {
+	var refs []ExternalSecretRef
+	found := make(map[string]struct{})
+	for _, subs := range pexpr.PhRe.FindAllStringSubmatch(pipelineYml, -1) {
+		if !strings.HasPrefix(subs[1], expression.Secrets+".") {
+			continue
+		}
+		refStr := strings.TrimPrefix(subs[1], expression.Secrets+".")
+		if _, ok := found[refStr]; ok {
+			continue
+		}
+		found[refStr] = struct{}{}
+		ref, err := ParseExternalSecretRef(refStr)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// RegisterBackend register an external secret backend, backend with same scheme will be replaced.
+func (s *provider) RegisterBackend(backend Backend) error {
+	if backend == nil || backend.Scheme() == "" {
+		return fmt.Errorf("external secret backend scheme is required")
+	}
+	s.backendsLock.Lock()
+	defer s.backendsLock.Unlock()
+	if s.backends == nil {
+		s.backends = make(map[string]Backend)
+	}
+	s.backends[backend.Scheme()] = backend
+	return nil
+}
+
+func (s *provider) getBackend(scheme string) Backend {
+	s.backendsLock.RLock()
+	defer s.backendsLock.RUnlock()
+	return s.backends[scheme]
+}
+
+// FetchExternalSecrets resolve all external secrets referenced in pipeline yml.
+// The key of result is the reference, such as vault://kv/app#password.
+func (s *provider) FetchExternalSecrets(ctx context.Context, p *spec.Pipeline) (map[string]string, error) {
+	refs, err := FindExternalSecretRefs(p.PipelineYml)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(refs))
+	if len(refs) == 0 {
+		return result, nil
+	}
+
+	// group keys by scheme and path, so every path is only fetched once
+	type location struct{ scheme, path string }
+	keysByLocation := make(map[location][]string)
+	var locations []location
+	for _, ref := range refs {
+		loc := location{scheme: ref.Scheme, path: ref.Path}
+		if _, ok := keysByLocation[loc]; !ok {
+			locations = append(locations, loc)
+		}
+		keysByLocation[loc] = append(keysByLocation[loc], ref.Key)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].scheme != locations[j].scheme {
+			return locations[i].scheme < locations[j].scheme
+		}
+		return locations[i].path < locations[j].path
+	})
+
+	for _, loc := range locations {
+		backend := s.getBackend(loc.scheme)
+		if backend == nil {
+			return nil, fmt.Errorf("external secret backend %q not found", loc.scheme)
+		}
+		keys := keysByLocation[loc]
+		values, err := backend.Resolve(ctx, p, loc.path, keys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve external secrets from %s://%s, err: %v", loc.scheme, loc.path, err)
+		}
+		for _, key := range keys {
+			value, ok := values[key]
+			if !ok {
+				return nil, fmt.Errorf("external secret not found: %s", ExternalSecretRef{Scheme: loc.scheme, Path: loc.path, Key: key})
+			}
+			result[ExternalSecretRef{Scheme: loc.scheme, Path: loc.path, Key: key}.String()] = value
+		}
+	}
+	return result, nil
+}
+
+// secretScope is the org, project and app which pipeline belongs to, backends only resolve secrets
+// at locations rendered from templates by the scope, such as secret/data/{orgID}/{projectID}
+type secretScope struct {
+	OrgID     string
+	ProjectID string
+	AppID     string
+}
+
+func getSecretScope(p *spec.Pipeline) (secretScope, error) {
+	var scope secretScope
+	if p == nil {
+		return scope, fmt.Errorf("pipeline is required to resolve external secrets")
+	}
+	scope.OrgID = p.GetLabel(apistructs.LabelOrgID)
+	scope.ProjectID = p.GetLabel(apistructs.LabelProjectID)
+	scope.AppID = p.GetLabel(apistructs.LabelAppID)
+	if scope.OrgID == "" || scope.ProjectID == "" {
+		return scope, fmt.Errorf("external secrets are only available for pipelines of project")
+	}
+	for _, id := range []string{scope.OrgID, scope.ProjectID, scope.AppID} {
+		if id == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return scope, fmt.Errorf("invalid scope id %q of pipeline", id)
+		}
+	}
+	return scope, nil
+}
+
+// render replace {orgID}, {projectID} and {appID} in tpl
+func (s secretScope) render(tpl string) (string, error) {
+	if strings.Contains(tpl, "{appID}") && s.AppID == "" {
+		return "", fmt.Errorf("%q requires pipeline of application", tpl)
+	}
+	return strings.NewReplacer("{orgID}", s.OrgID, "{projectID}", s.ProjectID, "{appID}", s.AppID).Replace(tpl), nil
+}
+
+// checkScopedTemplate make sure tpl is rendered differently for each project,
+// so that pipelines cannot read secrets of other projects
+func checkScopedTemplate(tpl string) error {
+	if !strings.Contains(tpl, "{projectID}") && !strings.Contains(tpl, "{appID}") {
+		return fmt.Errorf("template %q must contain {projectID} or {appID}", tpl)
+	}
+	return nil
+}
+
+// pickKeys return values of keys from data, used by backends which fetch the whole path at once
+func pickKeys(data map[string]string, keys []string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, ok := data[key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", key)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+)
+
+// fileBackend reads secrets from yaml or json files under the root dir of pipeline's project,
+// which is rendered from rootDirTemplate such as /secrets/{orgID}/{projectID}, mainly used for tests.
+//
+//	${{ secrets.file://app/db.yaml#password }} -> key password of /secrets/1/2/app/db.yaml
+type fileBackend struct {
+	rootDirTemplate string
+}
+
+func newFileBackend(rootDirTemplate string) (*fileBackend, error) {
+	if err := checkScopedTemplate(rootDirTemplate); err != nil {
+		return nil, err
+	}
+	return &fileBackend{rootDirTemplate: rootDirTemplate}, nil
+}
+
+func (b *fileBackend) Scheme() string { return "file" }
+
+func (b *fileBackend) Resolve(ctx context.Context, p *spec.Pipeline, path string, keys []string) (map[string]string, error) {
+	scope, err := getSecretScope(p)
+	if err != nil {
+		return nil, err
+	}
+	rootDir, err := scope.render(b.rootDirTemplate)
+	if err != nil {
+		return nil, err
+	}
+	rootDir = filepath.Clean(rootDir)
+	fullPath := filepath.Join(rootDir, filepath.Clean("/"+path))
+	if !strings.HasPrefix(fullPath, rootDir+string(filepath.Separator)) {
+		return nil, fmt.Errorf("invalid path %q", path)
+	}
+	content, err := ioutil.ReadFile(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]string)
+	if err := yaml.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse %q, err: %v", path, err)
+	}
+	return pickKeys(data, keys)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/k8sclient"
+	"github.com/erda-project/erda/pkg/strutil"
+)
+
+// k8sBackend reads kubernetes secrets from the cluster which pipeline runs on,
+// only namespaces rendered from namespaceTemplates by pipeline's project can be read,
+// e.g. with template project-{projectID}-secrets:
+//
+//	${{ secrets.k8s://project-2-secrets/name#key }}
+type k8sBackend struct {
+	namespaceTemplates []string
+	// getClient is replaceable for tests
+	getClient func(clusterName string) (kubernetes.Interface, error)
+}
+
+func newK8sBackend(namespaceTemplates []string) (*k8sBackend, error) {
+	for _, tpl := range namespaceTemplates {
+		if err := checkScopedTemplate(tpl); err != nil {
+			return nil, fmt.Errorf("invalid k8s secret namespace template, err: %v", err)
+		}
+	}
+	return &k8sBackend{
+		namespaceTemplates: namespaceTemplates,
+		getClient: func(clusterName string) (kubernetes.Interface, error) {
+			client, err := k8sclient.New(clusterName, k8sclient.WithTimeout(10*time.Second), k8sclient.WithPreferredToUseInClusterConfig())
+			if err != nil {
+				return nil, err
+			}
+			return client.ClientSet, nil
+		},
+	}, nil
+}
+
+func (b *k8sBackend) Scheme() string { return "k8s" }
+
+func (b *k8sBackend) Resolve(ctx context.Context, p *spec.Pipeline, path string, keys []string) (map[string]string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid path %q, must be <namespace>/<name>", path)
+	}
+	namespace, name := parts[0], parts[1]
+	allowed, err := b.allowedNamespaces(p)
+	if err != nil {
+		return nil, err
+	}
+	if !strutil.Exist(allowed, namespace) {
+		return nil, fmt.Errorf("namespace %q is not allowed to read secrets", namespace)
+	}
+	cs, err := b.getClient(p.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := cs.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		data[k] = v
+	}
+	return pickKeys(data, keys)
+}
+
+// allowedNamespaces render namespace templates by the project of pipeline,
+// templates using {appID} are skipped if pipeline doesn't belong to an app
+func (b *k8sBackend) allowedNamespaces(p *spec.Pipeline) ([]string, error) {
+	scope, err := getSecretScope(p)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make([]string, 0, len(b.namespaceTemplates))
+	for _, tpl := range b.namespaceTemplates {
+		if scope.AppID == "" && strings.Contains(tpl, "{appID}") {
+			continue
+		}
+		namespace, err := scope.render(tpl)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, nil
+}
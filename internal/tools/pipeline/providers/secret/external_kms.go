@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+type kmsDecrypter interface {
+	KMSDescribeKey(req apistructs.KMSDescribeKeyRequest) (*kmstypes.DescribeKeyResponse, error)
+	KMSDecrypt(req apistructs.KMSDecryptRequest) (*kmstypes.DecryptResponse, error)
+}
+
+// kmsBackend decrypts ciphertext by platform kms, path is the key id and key is the base64 ciphertext.
+// Only keys belonging to the org and project of pipeline can be used.
+//
+//	${{ secrets.kms://<keyID>#<ciphertextBase64> }}
+type kmsBackend struct {
+	kms kmsDecrypter
+}
+
+func (b *kmsBackend) Scheme() string { return "kms" }
+
+func (b *kmsBackend) Resolve(ctx context.Context, p *spec.Pipeline, path string, keys []string) (map[string]string, error) {
+	scope, err := getSecretScope(p)
+	if err != nil {
+		return nil, err
+	}
+	key, err := b.kms.KMSDescribeKey(apistructs.KMSDescribeKeyRequest{
+		DescribeKeyRequest: kmstypes.DescribeKeyRequest{KeyID: path},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if key.KeyMetadata.OrgID != scope.OrgID {
+		return nil, fmt.Errorf("kms key %q doesn't belong to org %s", path, scope.OrgID)
+	}
+	if key.KeyMetadata.ProjectID != scope.ProjectID {
+		return nil, fmt.Errorf("kms key %q doesn't belong to project %s", path, scope.ProjectID)
+	}
+	result := make(map[string]string, len(keys))
+	for _, ciphertext := range keys {
+		resp, err := b.kms.KMSDecrypt(apistructs.KMSDecryptRequest{
+			DecryptRequest: kmstypes.DecryptRequest{
+				KeyID:            path,
+				CiphertextBase64: ciphertext,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := base64.StdEncoding.DecodeString(resp.PlaintextBase64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode plaintext, err: %v", err)
+		}
+		result[ciphertext] = string(plaintext)
+	}
+	return result, nil
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+func TestParseExternalSecretRef(t *testing.T) {
+	ref, err := ParseExternalSecretRef("vault://secret/data/app#password")
+	assert.NoError(t, err)
+	assert.Equal(t, ExternalSecretRef{Scheme: "vault", Path: "secret/data/app", Key: "password"}, ref)
+	assert.Equal(t, "vault://secret/data/app#password", ref.String())
+
+	for _, invalid := range []string{"password", "vault://secret", "://secret#key", "vault://#key", "vault://secret#"} {
+		_, err := ParseExternalSecretRef(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFindExternalSecretRefs(t *testing.T) {
+	refs, err := FindExternalSecretRefs(`
+envs:
+  A: ${{ secrets.vault://secret/data/app#password }}
+  B: ${{ secrets.vault://secret/data/app#password }}
+  C: ${{ configs.user }}
+  D: ${{ secrets.k8s://default/app#token }}
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []ExternalSecretRef{
+		{Scheme: "vault", Path: "secret/data/app", Key: "password"},
+		{Scheme: "k8s", Path: "default/app", Key: "token"},
+	}, refs)
+
+	_, err = FindExternalSecretRefs(`${{ secrets.password }}`)
+	assert.Error(t, err)
+}
+
+func TestFetchExternalSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "2", "app"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "2", "app", "db.yaml"), []byte("user: erda\npassword: \"123456\"\n"), 0644))
+
+	s := &provider{}
+	file, err := newFileBackend(filepath.Join(dir, "{projectID}"))
+	assert.NoError(t, err)
+	assert.NoError(t, s.RegisterBackend(file))
+	assert.Error(t, s.RegisterBackend(nil))
+
+	p := newScopedPipeline("1", "2", "")
+	p.PipelineExtra = spec.PipelineExtra{PipelineYml: `
+envs:
+  USER: ${{ secrets.file://app/db.yaml#user }}
+  PASSWORD: ${{ secrets.file://app/db.yaml#password }}
+`}
+	secrets, err := s.FetchExternalSecrets(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"file://app/db.yaml#user":     "erda",
+		"file://app/db.yaml#password": "123456",
+	}, secrets)
+
+	// no reference
+	secrets, err = s.FetchExternalSecrets(context.Background(), &spec.Pipeline{})
+	assert.NoError(t, err)
+	assert.Empty(t, secrets)
+
+	// key not found
+	p.PipelineYml = `${{ secrets.file://app/db.yaml#token }}`
+	_, err = s.FetchExternalSecrets(context.Background(), p)
+	assert.Error(t, err)
+
+	// files of other projects are not visible
+	other := newScopedPipeline("1", "3", "")
+	other.PipelineYml = `${{ secrets.file://app/db.yaml#user }}`
+	_, err = s.FetchExternalSecrets(context.Background(), other)
+	assert.Error(t, err)
+
+	// backend not found
+	p.PipelineYml = `${{ secrets.vault://secret/data/app#token }}`
+	_, err = s.FetchExternalSecrets(context.Background(), p)
+	assert.Error(t, err)
+}
+
+func TestFileBackend_PathEscape(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outside := filepath.Join(filepath.Dir(dir), filepath.Base(dir)+".yaml")
+	assert.NoError(t, ioutil.WriteFile(outside, []byte("key: value\n"), 0644))
+	defer os.Remove(outside)
+
+	b, err := newFileBackend(filepath.Join(dir, "{projectID}"))
+	assert.NoError(t, err)
+	p := newScopedPipeline("1", "2", "")
+	_, err = b.Resolve(context.Background(), p, "../../"+filepath.Base(outside), []string{"key"})
+	assert.Error(t, err)
+	_, err = b.Resolve(context.Background(), nil, "app.yaml", []string{"key"})
+	assert.Error(t, err)
+
+	_, err = newFileBackend(dir)
+	assert.Error(t, err)
+}
+
+func newScopedPipeline(orgID, projectID, appID string) *spec.Pipeline {
+	return &spec.Pipeline{
+		PipelineBase: spec.PipelineBase{ClusterName: "dev"},
+		Labels: map[string]string{
+			apistructs.LabelOrgID:     orgID,
+			apistructs.LabelProjectID: projectID,
+			apistructs.LabelAppID:     appID,
+		},
+	}
+}
+
+func TestGetSecretScope(t *testing.T) {
+	scope, err := getSecretScope(newScopedPipeline("1", "2", "3"))
+	assert.NoError(t, err)
+	assert.Equal(t, secretScope{OrgID: "1", ProjectID: "2", AppID: "3"}, scope)
+
+	for _, p := range []*spec.Pipeline{nil, {}, newScopedPipeline("1", "", ""), newScopedPipeline("1", "../2", "")} {
+		_, err := getSecretScope(p)
+		assert.Error(t, err)
+	}
+
+	_, err = secretScope{OrgID: "1", ProjectID: "2"}.render("ns-{appID}")
+	assert.Error(t, err)
+	assert.Error(t, checkScopedTemplate("secret/data/{orgID}"))
+}
+
+func TestVaultBackend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/auth/token/create/project-2" && r.Method == http.MethodPost && r.Header.Get("X-Vault-Token") == "token":
+			fmt.Fprint(w, `{"auth":{"client_token":"project-2-token"}}`)
+			return
+		case r.Header.Get("X-Vault-Token") != "token" && r.Header.Get("X-Vault-Token") != "project-2-token":
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/1/2/app":
+			fmt.Fprint(w, `{"data":{"data":{"password":"123456","port":3306},"metadata":{"version":1}}}`)
+		case "/v1/kv/1/2/app":
+			fmt.Fprint(w, `{"data":{"password":"abcdef"}}`)
+		case "/v1/secret/data/1/3/app", "/v1/sys/mounts", "/v1/auth/token/lookup-self":
+			fmt.Fprint(w, `{"data":{"password":"leaked"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := newScopedPipeline("1", "2", "")
+	b, err := newVaultBackend(server.URL, "token", "", "secret/data/{orgID}/{projectID}", "")
+	assert.NoError(t, err)
+	values, err := b.Resolve(context.Background(), p, "secret/data/1/2/app", []string{"password", "port"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "123456", "port": "3306"}, values)
+
+	_, err = b.Resolve(context.Background(), p, "secret/data/1/2/not/exist", []string{"password"})
+	assert.Error(t, err)
+
+	// paths outside the project
+	for _, path := range []string{
+		"secret/data/1/3/app",
+		"secret/data/1/2/../3/app",
+		"secret/data/1/2/./app",
+		"secret/data/1/2app",
+		"sys/mounts",
+		"auth/token/lookup-self",
+		"secret/data/1/2/app?version=1",
+	} {
+		_, err := b.Resolve(context.Background(), p, path, []string{"password"})
+		assert.Error(t, err, path)
+	}
+	_, err = b.Resolve(context.Background(), nil, "secret/data/1/2/app", []string{"password"})
+	assert.Error(t, err)
+	_, err = b.Resolve(context.Background(), newScopedPipeline("1", "3", ""), "secret/data/1/2/app", []string{"password"})
+	assert.Error(t, err)
+
+	kv1, err := newVaultBackend(server.URL, "token", "", "kv/{orgID}/{projectID}", "project-{projectID}")
+	assert.NoError(t, err)
+	values, err = kv1.Resolve(context.Background(), p, "kv/1/2/app", []string{"password"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "abcdef"}, values)
+
+	wrong, err := newVaultBackend(server.URL, "wrong", "", "kv/{orgID}/{projectID}", "")
+	assert.NoError(t, err)
+	_, err = wrong.Resolve(context.Background(), p, "kv/1/2/app", []string{"password"})
+	assert.Error(t, err)
+
+	// role of other projects doesn't exist
+	_, err = kv1.Resolve(context.Background(), newScopedPipeline("1", "3", ""), "kv/1/3/app", []string{"password"})
+	assert.Error(t, err)
+
+	// platform-wide prefix or role
+	_, err = newVaultBackend(server.URL, "token", "", "secret/data", "")
+	assert.Error(t, err)
+	_, err = newVaultBackend(server.URL, "token", "", "secret/data/{projectID}", "pipeline")
+	assert.Error(t, err)
+}
+
+func TestK8sBackend(t *testing.T) {
+	cs := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "project-2-secrets"},
+		Data:       map[string][]byte{"token": []byte("123456")},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "project-3-secrets"},
+		Data:       map[string][]byte{"token": []byte("abcdef")},
+	})
+	b, err := newK8sBackend([]string{"project-{projectID}-secrets", "app-{appID}-secrets"})
+	assert.NoError(t, err)
+	b.getClient = func(clusterName string) (kubernetes.Interface, error) { return cs, nil }
+	p := newScopedPipeline("1", "2", "")
+
+	values, err := b.Resolve(context.Background(), p, "project-2-secrets/app", []string{"token"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"token": "123456"}, values)
+
+	for _, path := range []string{"project-3-secrets/app", "kube-system/app", "app-/app", "app"} {
+		_, err := b.Resolve(context.Background(), p, path, []string{"token"})
+		assert.Error(t, err, path)
+	}
+	_, err = b.Resolve(context.Background(), nil, "project-2-secrets/app", []string{"token"})
+	assert.Error(t, err)
+
+	_, err = newK8sBackend([]string{"default"})
+	assert.Error(t, err)
+}
+
+type kmsMock struct{}
+
+func (m kmsMock) KMSDescribeKey(req apistructs.KMSDescribeKeyRequest) (*kmstypes.DescribeKeyResponse, error) {
+	switch req.KeyID {
+	case "key":
+		return &kmstypes.DescribeKeyResponse{KeyMetadata: kmstypes.KeyMetadata{KeyID: req.KeyID, OrgID: "1", ProjectID: "2"}}, nil
+	case "other-org":
+		return &kmstypes.DescribeKeyResponse{KeyMetadata: kmstypes.KeyMetadata{KeyID: req.KeyID, OrgID: "2", ProjectID: "2"}}, nil
+	case "other-project":
+		return &kmstypes.DescribeKeyResponse{KeyMetadata: kmstypes.KeyMetadata{KeyID: req.KeyID, OrgID: "1", ProjectID: "3"}}, nil
+	case "org-only":
+		return &kmstypes.DescribeKeyResponse{KeyMetadata: kmstypes.KeyMetadata{KeyID: req.KeyID, OrgID: "1"}}, nil
+	default:
+		return nil, fmt.Errorf("key not found")
+	}
+}
+
+func (m kmsMock) KMSDecrypt(req apistructs.KMSDecryptRequest) (*kmstypes.DecryptResponse, error) {
+	return &kmstypes.DecryptResponse{PlaintextBase64: req.CiphertextBase64}, nil
+}
+
+func TestKmsBackend(t *testing.T) {
+	b := &kmsBackend{kms: kmsMock{}}
+	p := newScopedPipeline("1", "2", "")
+	ciphertext := base64.StdEncoding.EncodeToString([]byte("123456"))
+	values, err := b.Resolve(context.Background(), p, "key", []string{ciphertext})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{ciphertext: "123456"}, values)
+
+	for _, keyID := range []string{"other", "other-org", "other-project", "org-only"} {
+		_, err = b.Resolve(context.Background(), p, keyID, []string{ciphertext})
+		assert.Error(t, err, keyID)
+	}
+	_, err = b.Resolve(context.Background(), nil, "key", []string{ciphertext})
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/http/httpclient"
+)
+
+// vaultBackend reads secrets from HashiCorp Vault KV secrets engine, path is the api path without /v1/.
+// Only paths under the prefix of pipeline's project can be read, e.g. with prefix secret/data/{orgID}/{projectID}:
+//
+//	${{ secrets.vault://secret/data/1/2/app#password }} -> kv v2, mount secret
+type vaultBackend struct {
+	address   string
+	token     string
+	namespace string
+	// pathPrefix template of the path prefix which pipeline can read
+	pathPrefix string
+	// role template of the token role, if set, secrets are read by a token created with the role of pipeline's project,
+	// whose policies are expected to grant access to the path prefix only
+	role string
+	hc   *httpclient.HTTPClient
+}
+
+func newVaultBackend(address, token, namespace, pathPrefix, role string) (*vaultBackend, error) {
+	if err := checkScopedTemplate(pathPrefix); err != nil {
+		return nil, fmt.Errorf("invalid vault path prefix, err: %v", err)
+	}
+	if role != "" {
+		if err := checkScopedTemplate(role); err != nil {
+			return nil, fmt.Errorf("invalid vault role, err: %v", err)
+		}
+	}
+	return &vaultBackend{
+		address:    address,
+		token:      token,
+		namespace:  namespace,
+		pathPrefix: pathPrefix,
+		role:       role,
+		hc:         httpclient.New(httpclient.WithTimeout(time.Second, time.Second*10)),
+	}, nil
+}
+
+func (b *vaultBackend) Scheme() string { return "vault" }
+
+func (b *vaultBackend) Resolve(ctx context.Context, p *spec.Pipeline, path string, keys []string) (map[string]string, error) {
+	scope, err := getSecretScope(p)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := scope.render(b.pathPrefix)
+	if err != nil {
+		return nil, err
+	}
+	path, err = checkVaultPath(path, prefix)
+	if err != nil {
+		return nil, err
+	}
+	token := b.token
+	if b.role != "" {
+		if token, err = b.createScopedToken(scope); err != nil {
+			return nil, err
+		}
+	}
+
+	var body bytes.Buffer
+	resp, err := b.newRequest(b.hc.Get(b.address).Path("/v1/"+path), token).Do().Body(&body)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsOK() {
+		return nil, fmt.Errorf("vault response status: %d, body: %s", resp.StatusCode(), body.String())
+	}
+	data, err := parseVaultKV(body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return pickKeys(data, keys)
+}
+
+func (b *vaultBackend) newRequest(req *httpclient.Request, token string) *httpclient.Request {
+	req.Header("X-Vault-Token", token)
+	if b.namespace != "" {
+		req.Header("X-Vault-Namespace", b.namespace)
+	}
+	return req
+}
+
+// createScopedToken create a short-lived token by the token role of project
+func (b *vaultBackend) createScopedToken(scope secretScope) (string, error) {
+	role, err := scope.render(b.role)
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(role, "/?#%") {
+		return "", fmt.Errorf("invalid vault role %q", role)
+	}
+	var body bytes.Buffer
+	resp, err := b.newRequest(b.hc.Post(b.address).Path("/v1/auth/token/create/"+role), b.token).
+		JSONBody(map[string]interface{}{"ttl": "1m", "num_uses": 1, "no_parent": false}).
+		Do().Body(&body)
+	if err != nil {
+		return "", err
+	}
+	if !resp.IsOK() {
+		return "", fmt.Errorf("failed to create vault token of role %s, status: %d, body: %s", role, resp.StatusCode(), body.String())
+	}
+	var result struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := json.Unmarshal(body.Bytes(), &result); err != nil {
+		return "", fmt.Errorf("failed to parse vault token response, err: %v", err)
+	}
+	if result.Auth.ClientToken == "" {
+		return "", fmt.Errorf("empty vault token of role %s", role)
+	}
+	return result.Auth.ClientToken, nil
+}
+
+// checkVaultPath return the cleaned path if it is under prefix,
+// paths like sys/..., auth/token/lookup-self or containing .. are rejected
+func checkVaultPath(path, prefix string) (string, error) {
+	path = strings.Trim(path, "/")
+	if strings.ContainsAny(path, "?#%\\") {
+		return "", fmt.Errorf("invalid vault path %q", path)
+	}
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", fmt.Errorf("invalid vault path %q", path)
+		}
+	}
+	prefix = strings.Trim(prefix, "/")
+	if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+		return "", fmt.Errorf("vault path %q is not allowed, must be under %q", path, prefix)
+	}
+	return path, nil
+}
+
+// parseVaultKV parse response of kv v1 ({"data":{...}}) and kv v2 ({"data":{"data":{...},"metadata":{...}}})
+func parseVaultKV(body []byte) (map[string]string, error) {
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("failed to parse vault response, err: %v", err)
+	}
+	data := secret.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, isV2 := data["metadata"]; isV2 {
+			data = inner
+		}
+	}
+	result := make(map[string]string, len(data))
+	for k, v := range data {
+		switch value := v.(type) {
+		case string:
+			result[k] = value
+		case nil:
+			result[k] = ""
+		default:
+			b, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = string(b)
+		}
+	}
+	return result, nil
+}
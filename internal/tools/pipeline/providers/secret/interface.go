@@ -23,4 +23,7 @@ import (
 type Interface interface {
 	FetchSecrets(ctx context.Context, p *spec.Pipeline) (secrets, cmsDiceFiles map[string]string, holdOnKeys, encryptSecretKeys []string, err error)
 	FetchPlatformSecrets(ctx context.Context, p *spec.Pipeline, ignoreKeys []string) (map[string]string, error)
+	// FetchExternalSecrets resolve secrets referenced as ${{ secrets.<scheme>://<path>#<key> }} from registered backends
+	FetchExternalSecrets(ctx context.Context, p *spec.Pipeline) (map[string]string, error)
+	RegisterBackend(backend Backend) error
 }
@@ -17,11 +17,13 @@ package secret
 import (
 	"context"
 	"reflect"
+	"sync"
 
 	"github.com/erda-project/erda-infra/base/logs"
 	"github.com/erda-project/erda-infra/base/servicehub"
 	"github.com/erda-project/erda-infra/providers/mysqlxorm"
 	cmspb "github.com/erda-project/erda-proto-go/core/pipeline/cms/pb"
+	"github.com/erda-project/erda/bundle"
 	"github.com/erda-project/erda/internal/core/org"
 	"github.com/erda-project/erda/internal/tools/pipeline/dbclient"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/clusterinfo"
@@ -29,6 +31,16 @@ import (
 
 type config struct {
 	DefaultDiceArch string `file:"default_dice_arch" env:"DEFAULT_DICE_ARCH" default:"amd64"`
+
+	// external secret backends, vault and file backend are only enabled when configured
+	// backends are scoped by pipeline's project, templates support placeholders {orgID}, {projectID} and {appID}
+	VaultAddress                string   `file:"vault_address" env:"PIPELINE_SECRET_VAULT_ADDRESS"`
+	VaultToken                  string   `file:"vault_token" env:"PIPELINE_SECRET_VAULT_TOKEN"`
+	VaultNamespace              string   `file:"vault_namespace" env:"PIPELINE_SECRET_VAULT_NAMESPACE"`
+	VaultPathPrefix             string   `file:"vault_path_prefix" env:"PIPELINE_SECRET_VAULT_PATH_PREFIX" default:"secret/data/{orgID}/{projectID}"`
+	VaultRole                   string   `file:"vault_role" env:"PIPELINE_SECRET_VAULT_ROLE"`
+	K8sSecretNamespaceTemplates []string `file:"k8s_secret_namespace_templates" env:"PIPELINE_SECRET_K8S_NAMESPACE_TEMPLATES"` // env support comma-seperated string
+	FileBackendDir              string   `file:"file_backend_dir" env:"PIPELINE_SECRET_FILE_BACKEND_DIR"`                      // such as /secrets/{orgID}/{projectID}
 }
 
 type provider struct {
@@ -41,10 +53,46 @@ type provider struct {
 
 	dbClient *dbclient.Client
 	Org      org.ClientInterface
+
+	backends     map[string]Backend
+	backendsLock sync.RWMutex
 }
 
 func (s *provider) Init(ctx servicehub.Context) error {
 	s.dbClient = &dbclient.Client{Engine: s.MySQL.DB()}
+	return s.registerDefaultBackends()
+}
+
+func (s *provider) registerDefaultBackends() error {
+	backends := []Backend{
+		&kmsBackend{kms: bundle.New(bundle.WithKMS())},
+	}
+	if len(s.Cfg.K8sSecretNamespaceTemplates) > 0 {
+		k8s, err := newK8sBackend(s.Cfg.K8sSecretNamespaceTemplates)
+		if err != nil {
+			return err
+		}
+		backends = append(backends, k8s)
+	}
+	if s.Cfg.VaultAddress != "" {
+		vault, err := newVaultBackend(s.Cfg.VaultAddress, s.Cfg.VaultToken, s.Cfg.VaultNamespace, s.Cfg.VaultPathPrefix, s.Cfg.VaultRole)
+		if err != nil {
+			return err
+		}
+		backends = append(backends, vault)
+	}
+	if s.Cfg.FileBackendDir != "" {
+		file, err := newFileBackend(s.Cfg.FileBackendDir)
+		if err != nil {
+			return err
+		}
+		backends = append(backends, file)
+	}
+	for _, backend := range backends {
+		if err := s.RegisterBackend(backend); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
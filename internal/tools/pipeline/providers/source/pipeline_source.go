@@ -23,11 +23,34 @@ import (
 
 	"github.com/erda-project/erda-infra/providers/mysqlxorm"
 	"github.com/erda-project/erda-proto-go/core/pipeline/source/pb"
+	definitiondb "github.com/erda-project/erda/internal/tools/pipeline/providers/definition/db"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/source/db"
 )
 
 type pipelineSource struct {
-	dbClient *db.Client
+	dbClient           *db.Client
+	definitionDBClient *definitiondb.Client
+	trigger            pipelinetrigger.Interface
+}
+
+// syncTriggers refresh on.pipeline triggers of the definition which uses the source,
+// failure doesn't block changes of the source
+func (p pipelineSource) syncTriggers(ctx context.Context, sourceID string) {
+	if p.trigger == nil {
+		return
+	}
+	definition, has, err := p.definitionDBClient.GetPipelineDefinitionBySourceID(sourceID)
+	if err != nil {
+		logrus.Errorf("failed to get pipeline definition by source, sourceID: %s, err: %v", sourceID, err)
+		return
+	}
+	if !has {
+		return
+	}
+	if err := p.trigger.SyncDefinitionTriggers(ctx, definition.ID); err != nil {
+		logrus.Errorf("failed to sync on.pipeline triggers, definitionID: %s, err: %v", definition.ID, err)
+	}
 }
 
 func (p pipelineSource) Create(ctx context.Context, request *pb.PipelineSourceCreateRequest) (*pb.PipelineSourceCreateResponse, error) {
@@ -84,6 +107,9 @@ func (p pipelineSource) Update(ctx context.Context, request *pb.PipelineSourceUp
 	if err != nil {
 		return nil, err
 	}
+	if request.PipelineYml != "" {
+		p.syncTriggers(ctx, request.PipelineSourceID)
+	}
 	return &pb.PipelineSourceUpdateResponse{
 		PipelineSource: source.Convert(),
 	}, nil
@@ -19,6 +19,8 @@ import (
 	"github.com/erda-project/erda-infra/pkg/transport"
 	"github.com/erda-project/erda-infra/providers/mysqlxorm"
 	"github.com/erda-project/erda-proto-go/core/pipeline/source/pb"
+	definitiondb "github.com/erda-project/erda/internal/tools/pipeline/providers/definition/db"
+	"github.com/erda-project/erda/internal/tools/pipeline/providers/pipelinetrigger"
 	"github.com/erda-project/erda/internal/tools/pipeline/providers/source/db"
 	"github.com/erda-project/erda/pkg/common/apis"
 )
@@ -29,14 +31,17 @@ type config struct {
 // +provider
 type Provider struct {
 	Cfg            *config
-	MySQL          mysqlxorm.Interface `autowired:"mysql-xorm"`
-	Register       transport.Register  `autowired:"service-register" required:"true"`
+	MySQL          mysqlxorm.Interface       `autowired:"mysql-xorm"`
+	Register       transport.Register        `autowired:"service-register" required:"true"`
+	Trigger        pipelinetrigger.Interface `autowired:"pipeline-trigger" optional:"true"`
 	pipelineSource *pipelineSource
 }
 
 func (p *Provider) Init(ctx servicehub.Context) error {
 	p.pipelineSource = &pipelineSource{
-		dbClient: &db.Client{Interface: p.MySQL},
+		dbClient:           &db.Client{Interface: p.MySQL},
+		definitionDBClient: &definitiondb.Client{Interface: p.MySQL},
+		trigger:            p.Trigger,
 	}
 	if p.Register != nil {
 		pb.RegisterSourceServiceImp(p.Register, p.pipelineSource, apis.Options())
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/erda-project/erda-proto-go/core/pipeline/task/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/approval"
+	"github.com/erda-project/erda/internal/tools/pipeline/services/apierrors"
+	"github.com/erda-project/erda/internal/tools/pipeline/spec"
+	"github.com/erda-project/erda/pkg/common/apis"
+)
+
+// PipelineTaskApprove approve or reject a running manual-approval task, the decision is appended to the comment trail on task,
+// and the task is finished by wait executor once the approval is decided.
+func (s *taskService) PipelineTaskApprove(ctx context.Context, req *pb.PipelineTaskApproveRequest) (*pb.PipelineTaskApproveResponse, error) {
+	userID := apis.GetUserID(ctx)
+	if userID == "" {
+		return nil, apierrors.ErrApprovePipelineTask.NotLogin()
+	}
+	decision := approval.Outcome(req.Decision)
+	if !decision.Valid() {
+		return nil, apierrors.ErrApprovePipelineTask.InvalidParameter("decision should be approved or rejected")
+	}
+	p, err := s.pipelineSvc.Detail(req.PipelineID)
+	if err != nil {
+		return nil, apierrors.ErrApprovePipelineTask.InternalError(err)
+	}
+	task, err := s.TaskDetail(req.TaskID)
+	if err != nil {
+		return nil, apierrors.ErrApprovePipelineTask.InternalError(err)
+	}
+	if task.PipelineID != p.ID {
+		return nil, apierrors.ErrApprovePipelineTask.InvalidParameter("task not belong to pipeline")
+	}
+	if task.Type != apistructs.ActionTypeManualApproval {
+		return nil, apierrors.ErrApprovePipelineTask.InvalidParameter("task is not a manual approval task")
+	}
+	if task.Status != apistructs.PipelineStatusRunning {
+		return nil, apierrors.ErrApprovePipelineTask.InvalidState("task is not waiting for approval")
+	}
+	cfg, err := approval.ParseConfig(task.Extra.Action.Params)
+	if err != nil {
+		return nil, apierrors.ErrApprovePipelineTask.InvalidParameter(err)
+	}
+	var roles []string
+	if len(cfg.ApproverRoles) > 0 {
+		if roles, err = s.listUserRoles(userID, p.Labels); err != nil {
+			return nil, apierrors.ErrApprovePipelineTask.InternalError(err)
+		}
+	}
+	if !cfg.CanApprove(userID, roles) {
+		return nil, apierrors.ErrApprovePipelineTask.AccessDenied()
+	}
+
+	updated, err := s.dbClient.UpdatePipelineTaskExtraWithLock(task.ID, func(t *spec.PipelineTask) error {
+		if t.Extra.ManualApproval == nil {
+			t.Extra.ManualApproval = &approval.State{}
+		}
+		return t.Extra.ManualApproval.Add(approval.Record{
+			UserID:   userID,
+			Decision: decision,
+			Comment:  req.Comment,
+		})
+	})
+	if err != nil {
+		return nil, apierrors.ErrApprovePipelineTask.InvalidState(err.Error())
+	}
+	return &pb.PipelineTaskApproveResponse{Data: convertApproval(cfg, updated.Extra.ManualApproval)}, nil
+}
+
+// listUserRoles return user's roles in project and application of pipeline
+func (s *taskService) listUserRoles(userID string, labels map[string]string) ([]string, error) {
+	var roles []string
+	scopes := []struct {
+		typ apistructs.ScopeType
+		id  string
+	}{
+		{apistructs.ProjectScope, labels[apistructs.LabelProjectID]},
+		{apistructs.AppScope, labels[apistructs.LabelAppID]},
+	}
+	for _, scope := range scopes {
+		scopeID, err := strconv.ParseUint(scope.id, 10, 64)
+		if err != nil {
+			continue
+		}
+		members, err := s.bdl.GetMemberByUserAndScope(scope.typ, userID, scopeID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			roles = append(roles, member.Roles...)
+		}
+	}
+	return roles, nil
+}
+
+func convertApproval(cfg *approval.Config, state *approval.State) *pb.PipelineTaskApproval {
+	// outcome is evaluated without timeout, timeout is handled by executor
+	outcome, _ := approval.Evaluate(cfg, state, time.Time{}, time.Now())
+	result := &pb.PipelineTaskApproval{
+		Outcome:           string(outcome),
+		RequiredApprovals: int64(cfg.RequiredApprovals),
+	}
+	if state == nil {
+		return result
+	}
+	for _, record := range state.Records {
+		result.Records = append(result.Records, &pb.PipelineTaskApprovalRecord{
+			UserID:   record.UserID,
+			Decision: string(record.Decision),
+			Comment:  record.Comment,
+			Time:     timestamppb.New(record.Time),
+		})
+	}
+	return result
+}
@@ -49,12 +49,15 @@ var (
 	ErrParsePipelineContext  = err("ErrParsePipelineContext", "解析流水线上下文失败")
 	ErrStatisticPipeline     = err("ErrStatisticPipeline", "统计 pipeline 失败")
 	ErrTaskView              = err("ErrTaskView", "获取 pipeline 视图失败")
+	ErrGetTriggerChain       = err("ErrGetTriggerChain", "获取流水线触发链路失败")
 	ErrSelectPipelineByLabel = err("ErrErrSelectPipelineByLabel", "根据 label 过滤流水线失败")
 	ErrListPipelineTasks     = err("ErrListPipelineTasks", "获取 pipeline 任务列表失败")
 	ErrGetPipelineTaskDetail = err("ErrGetPipelineTaskDetail", "获取 pipeline 任务详情失败")
 	ErrGetTaskBootstrapInfo  = err("ErrGetPipelineTaskBootstrapInfo", "获取任务启动信息失败")
+	ErrApprovePipelineTask   = err("ErrApprovePipelineTask", "审批流水线任务失败")
 	ErrGetPipelineOutputs    = err("ErrGetPipelineOutputs", "获取流水线输出失败")
 	ErrPreCheckPipeline      = err("ErrPreCheckPipeline", "流水线前置校验失败")
+	ErrDryRunPipeline        = err("ErrDryRunPipeline", "预览流水线执行计划失败")
 	ErrGetOpenapiOAuth2Token = err("ErrGetOpenapiOAuth2Token", "申请 openapi oauth2 token 失败")
 	ErrQuerySnippetYaml      = err("ErrQuerySnippetYaml", "查询嵌套流水线片段失败")
 
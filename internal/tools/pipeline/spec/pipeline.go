@@ -300,16 +300,28 @@ func (p *Pipeline) GetPipelineQueueID() (uint64, bool) {
 	return p.Extra.QueueInfo.QueueID, true
 }
 
+// GetConcurrencyGroupKey return pipeline concurrency group key if exist.
+func (p *Pipeline) GetConcurrencyGroupKey() (string, bool) {
+	if p.Extra.ConcurrencyGroup == nil || p.Extra.ConcurrencyGroup.Key == "" {
+		return "", false
+	}
+	return p.Extra.ConcurrencyGroup.Key, true
+}
+
 // GetPipelineAppliedResources return limited and min resource when pipeline run.
 func (p *Pipeline) GetPipelineAppliedResources() apistructs.PipelineAppliedResources {
 	return p.Snapshot.AppliedResources
 }
 
-// CanSkipRunningCheck if pipeline bind queue and EnqueueCondition is skip running, pipeline can skip limit running
+// CanSkipRunningCheck if pipeline bind queue and EnqueueCondition is skip running, or pipeline has concurrency group, pipeline can skip limit running
 func (p *Pipeline) CanSkipRunningCheck() bool {
 	if p.Extra.QueueInfo != nil && p.Extra.QueueInfo.EnqueueCondition.IsSkipAlreadyRunningLimit() {
 		return true
 	}
+	// pipelines in the same concurrency group are queued or canceled by group
+	if _, ok := p.GetConcurrencyGroupKey(); ok {
+		return true
+	}
 	return false
 }
 
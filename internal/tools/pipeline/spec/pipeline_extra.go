@@ -114,6 +114,8 @@ type PipelineExtraInfo struct {
 
 	QueueInfo *QueueInfo `json:"queueInfo,omitempty"`
 
+	ConcurrencyGroup *ConcurrencyGroup `json:"concurrencyGroup,omitempty"`
+
 	TaskOperates []*pipelinepb.PipelineTaskOperateRequest `json:"taskTaskOperates,omitempty"`
 
 	ContainerInstanceProvider *apistructs.ContainerInstanceProvider `json:"containerInstanceProvider,omitempty"`
@@ -129,12 +131,21 @@ type QueueInfo struct {
 	PriorityChangeHistory []int64 `json:"priorityChangeHistory,omitempty"`
 }
 
+// ConcurrencyGroup is the rendered concurrency group of pipeline.
+type ConcurrencyGroup struct {
+	// Key is the unique key of group, scoped by pipeline source and application
+	Key              string `json:"key"`
+	CancelInProgress bool   `json:"cancelInProgress"`
+}
+
 type Snapshot struct {
 	PipelineYml     string            `json:"pipeline_yml,omitempty"` // 对占位符进行渲染
 	Secrets         map[string]string `json:"secrets,omitempty"`
 	PlatformSecrets map[string]string `json:"platformSecrets,omitempty"`
 	CmsDiceFiles    map[string]string `json:"cmsDiceFiles,omitempty"`
 	Envs            map[string]string `json:"envs,omitempty"`
+	// ExternalSecrets key is reference like vault://path#key, value is resolved at run time
+	ExternalSecrets map[string]string `json:"externalSecrets,omitempty"`
 
 	AnalyzedCrossCluster *bool `json:"analyzedCrossCluster,omitempty"`
 
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+// CalculateOutputValues 根据流水线定义的 outputs 从 tasks 的 metadata 中计算流水线级别的输出
+func (p *Pipeline) CalculateOutputValues(tasks []*PipelineTask) []apistructs.PipelineOutputWithValue {
+	// 所有任务的输出
+	allTaskOutputs := make(map[string]map[string]interface{})
+	for _, task := range tasks {
+		for _, meta := range task.GetMetadata() {
+			if allTaskOutputs[task.Name] == nil {
+				allTaskOutputs[task.Name] = make(map[string]interface{})
+			}
+			allTaskOutputs[task.Name][meta.Name] = meta.Value
+		}
+	}
+
+	// 根据定义塞入流水线级别的输出
+	var outputValues []apistructs.PipelineOutputWithValue
+	for _, define := range p.Extra.DefinedOutputs {
+		// handle ref v1
+		reffedTask, reffedKey, err := parsePipelineOutputRef(define.Ref)
+		if err == nil {
+			reffedValue := allTaskOutputs[reffedTask][reffedKey]
+			outputWithValue := apistructs.PipelineOutputWithValue{PipelineOutput: define, Value: reffedValue}
+			outputValues = append(outputValues, outputWithValue)
+		}
+
+		// handle ref v2
+		reffedTask, reffedKey, err = parsePipelineOutputRefV2(define.Ref)
+		if err == nil {
+			reffedValue := allTaskOutputs[reffedTask][reffedKey]
+			outputWithValue := apistructs.PipelineOutputWithValue{PipelineOutput: define, Value: reffedValue}
+			outputValues = append(outputValues, outputWithValue)
+		}
+	}
+	return outputValues
+}
+
+// parsePipelineOutputRef 解析 pipeline 的 output ref 表达式
+func parsePipelineOutputRef(ref string) (string, string, error) {
+	ref = strings.TrimSpace(ref)
+	ref = strings.TrimPrefix(ref, "${")
+	ref = strings.TrimSuffix(ref, "}")
+	ss := strings.SplitN(ref, ":", 3)
+	if len(ss) < 3 {
+		return "", "", fmt.Errorf("invalid ref: %s", ref)
+	}
+	if ss[1] != "OUTPUT" {
+		return "", "", fmt.Errorf("ref is not output, ref: %s", ref)
+	}
+	return ss[0], ss[2], nil
+}
+
+// parsePipelineOutputRefV2 解析 pipeline 的 output ref 表达式
+// ${{ outputs.xxx.key }}
+func parsePipelineOutputRefV2(ref string) (string, string, error) {
+	ref = strings.TrimSpace(ref)
+	ref = strings.TrimPrefix(ref, "${{ outputs.")
+	ref = strings.TrimSuffix(ref, " }}")
+	ss := strings.SplitN(ref, ".", 2)
+	if len(ss) < 2 {
+		return "", "", fmt.Errorf("invalid ref: %s", ref)
+	}
+	return ss[0], ss[1], nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/taskresult"
+	"github.com/erda-project/erda/pkg/metadata"
+)
+
+func TestPipeline_CalculateOutputValues(t *testing.T) {
+	p := &Pipeline{
+		PipelineExtra: PipelineExtra{
+			Extra: PipelineExtraInfo{
+				DefinedOutputs: []apistructs.PipelineOutput{
+					{Name: "image", Ref: "${build:OUTPUT:image}"},
+					{Name: "version", Ref: "${{ outputs.build.version }}"},
+					{Name: "missing", Ref: "${{ outputs.test.result }}"},
+				},
+			},
+		},
+	}
+	tasks := []*PipelineTask{
+		{
+			Name: "build",
+			Result: &taskresult.Result{Metadata: metadata.Metadata{
+				{Name: "image", Value: "registry/app:v1"},
+				{Name: "version", Value: "1.0.0"},
+			}},
+		},
+		{Name: "test"},
+	}
+	outputValues := p.CalculateOutputValues(tasks)
+	assert.Equal(t, 3, len(outputValues))
+	assert.Equal(t, "registry/app:v1", outputValues[0].Value)
+	assert.Equal(t, "1.0.0", outputValues[1].Value)
+	assert.Nil(t, outputValues[2].Value)
+}
+
+func Test_parsePipelineOutputRef(t *testing.T) {
+	task, key, err := parsePipelineOutputRef("${build:OUTPUT:image}")
+	assert.NoError(t, err)
+	assert.Equal(t, "build", task)
+	assert.Equal(t, "image", key)
+
+	_, _, err = parsePipelineOutputRef("${build:INPUT:image}")
+	assert.Error(t, err)
+
+	task, key, err = parsePipelineOutputRefV2("${{ outputs.build.image }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "build", task)
+	assert.Equal(t, "image", key)
+}
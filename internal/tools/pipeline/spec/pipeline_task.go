@@ -25,6 +25,7 @@ import (
 	basepb "github.com/erda-project/erda-proto-go/core/pipeline/base/pb"
 	"github.com/erda-project/erda/apistructs"
 	"github.com/erda-project/erda/internal/tools/pipeline/conf"
+	"github.com/erda-project/erda/internal/tools/pipeline/pkg/approval"
 	"github.com/erda-project/erda/internal/tools/pipeline/pkg/taskerror"
 	"github.com/erda-project/erda/internal/tools/pipeline/pkg/taskinspect"
 	"github.com/erda-project/erda/internal/tools/pipeline/pkg/taskresult"
@@ -99,6 +100,24 @@ func (pt *PipelineTask) ConvertTaskContainer2PB() []*basepb.TaskContainer {
 	return res
 }
 
+func (pt *PipelineTask) ConvertRetryAttempts2PB() []*basepb.PipelineTaskRetryAttempt {
+	attempts := make([]*basepb.PipelineTaskRetryAttempt, 0, len(pt.Extra.RetryAttempts))
+	for _, attempt := range pt.Extra.RetryAttempts {
+		attempts = append(attempts, &basepb.PipelineTaskRetryAttempt{
+			Attempt:      int64(attempt.Attempt),
+			UUID:         attempt.UUID,
+			Status:       attempt.Status.String(),
+			FailureClass: string(attempt.FailureClass),
+			ExitCode:     int64(attempt.ExitCode),
+			Errors:       attempt.Errors,
+			TimeBegin:    timestamppb.New(attempt.TimeBegin),
+			TimeEnd:      timestamppb.New(attempt.TimeEnd),
+			CostTimeSec:  attempt.CostTimeSec,
+		})
+	}
+	return attempts
+}
+
 func (pt *PipelineTask) GetExecutorName() PipelineTaskExecutorName {
 	switch pt.ExecutorKind {
 	// PipelineTaskExecutorKindScheduler after 2.1 version, scheduler executor is deleted.
@@ -166,9 +185,13 @@ type PipelineTaskExtra struct {
 
 	CurrentPolicy apistructs.Policy `json:"currentPolicy"` // task execution strategy
 
+	RetryAttempts []apistructs.PipelineTaskRetryAttempt `json:"retryAttempts,omitempty"` // history of failed attempts retried by retry policy
+
 	ContainerInstanceProvider *apistructs.ContainerInstanceProvider `json:"containerInstanceProvider,omitempty"`
 
 	Breakpoint *basepb.Breakpoint `json:"breakpoint,omitempty"`
+
+	ManualApproval *approval.State `json:"manualApproval,omitempty"` // approve/reject comment trail of manual-approval task
 }
 
 type FlinkSparkConf struct {
@@ -214,7 +237,8 @@ var (
 	PipelineTaskExecutorKindK8sFlink  PipelineTaskExecutorKind = "K8SFLINK"
 	PipelineTaskExecutorKindK8sSpark  PipelineTaskExecutorKind = "K8SSPARK"
 	PipelineTaskExecutorKindDocker    PipelineTaskExecutorKind = "DOCKER"
-	PipelineTaskExecutorKindList                               = []PipelineTaskExecutorKind{PipelineTaskExecutorKindScheduler, PipelineTaskExecutorKindMemory, PipelineTaskExecutorKindAPITest, PipelineTaskExecutorKindWait, PipelineTaskExecutorKindK8sJob}
+	PipelineTaskExecutorKindLocal     PipelineTaskExecutorKind = "LOCAL"
+	PipelineTaskExecutorKindList                               = []PipelineTaskExecutorKind{PipelineTaskExecutorKindScheduler, PipelineTaskExecutorKindMemory, PipelineTaskExecutorKindAPITest, PipelineTaskExecutorKindWait, PipelineTaskExecutorKindK8sJob, PipelineTaskExecutorKindLocal}
 )
 
 func (that PipelineTaskExecutorKind) Check() bool {
@@ -252,6 +276,8 @@ func (that PipelineTaskExecutorKind) GetDefaultExecutorName() PipelineTaskExecut
 		return PipelineTaskExecutorNameK8sFlinkDefault
 	case PipelineTaskExecutorKindK8sSpark:
 		return PipelineTaskExecutorNameK8sSparkDefault
+	case PipelineTaskExecutorKindLocal:
+		return PipelineTaskExecutorNameLocalDefault
 	}
 	return PipelineTaskExecutorNameEmpty
 }
@@ -271,7 +297,8 @@ var (
 	PipelineTaskExecutorNameK8sFlinkDefault  PipelineTaskExecutorName = "k8s-flink"
 	PipelineTaskExecutorNameK8sSparkDefault  PipelineTaskExecutorName = "k8s-spark"
 	PipelineTaskExecutorNameDockerDefault    PipelineTaskExecutorName = "docker"
-	PipelineTaskExecutorNameList                                      = []PipelineTaskExecutorName{PipelineTaskExecutorNameEmpty, PipelineTaskExecutorNameSchedulerDefault, PipelineTaskExecutorNameAPITestDefault, PipelineTaskExecutorNameWaitDefault, PipelineTaskExecutorNameK8sJobDefault}
+	PipelineTaskExecutorNameLocalDefault     PipelineTaskExecutorName = "local"
+	PipelineTaskExecutorNameList                                      = []PipelineTaskExecutorName{PipelineTaskExecutorNameEmpty, PipelineTaskExecutorNameSchedulerDefault, PipelineTaskExecutorNameAPITestDefault, PipelineTaskExecutorNameWaitDefault, PipelineTaskExecutorNameK8sJobDefault, PipelineTaskExecutorNameLocalDefault}
 )
 
 func (that PipelineTaskExecutorName) Check() bool {
@@ -314,6 +341,7 @@ func (pt *PipelineTask) Convert2DTO() *apistructs.PipelineTaskDTO {
 			UUID:           pt.Extra.UUID,
 			AllowFailure:   pt.Extra.AllowFailure,
 			TaskContainers: pt.Extra.TaskContainers,
+			RetryAttempts:  pt.Extra.RetryAttempts,
 		},
 		Labels:       pt.Extra.Action.Labels,
 		CostTimeSec:  pt.CostTimeSec,
@@ -367,6 +395,7 @@ func (pt *PipelineTask) Convert2PB() *basepb.PipelineTaskDTO {
 			AllowFailure:   pt.Extra.AllowFailure,
 			TaskContainers: pt.ConvertTaskContainer2PB(),
 			Breakpoint:     pt.Extra.Breakpoint,
+			RetryAttempts:  pt.ConvertRetryAttempts2PB(),
 		},
 		Labels:       pt.Extra.Action.Labels,
 		CostTimeSec:  pt.CostTimeSec,
@@ -570,10 +599,14 @@ type ExecutorDoneChanData struct {
 }
 
 func (pt *PipelineTask) GenerateExecutorDoneChanDataVersion() string {
-	if pt.Extra.LoopOptions == nil {
-		return fmt.Sprintf("%s-%d", CtxExecutorChDataVersionPrefix, pt.ID)
+	version := fmt.Sprintf("%s-%d", CtxExecutorChDataVersionPrefix, pt.ID)
+	if pt.Extra.LoopOptions != nil {
+		version = fmt.Sprintf("%s-loop-%d", version, pt.Extra.LoopOptions.LoopedTimes)
+	}
+	if len(pt.Extra.RetryAttempts) > 0 {
+		version = fmt.Sprintf("%s-retry-%d", version, len(pt.Extra.RetryAttempts))
 	}
-	return fmt.Sprintf("%s-%d-loop-%d", CtxExecutorChDataVersionPrefix, pt.ID, pt.Extra.LoopOptions.LoopedTimes)
+	return version
 }
 
 func (pt *PipelineTask) CheckExecutorDoneChanDataVersion(actualVersion string) error {
@@ -182,6 +182,10 @@ func TestGenerateExecutorVersion(t *testing.T) {
 	}}
 	assert.Equal(t, normalTask.GenerateExecutorDoneChanDataVersion(), "executor-done-chan-data-version-1")
 	assert.Equal(t, loopTask.GenerateExecutorDoneChanDataVersion(), "executor-done-chan-data-version-1-loop-100")
+	retryTask := PipelineTask{ID: 1, Extra: PipelineTaskExtra{
+		RetryAttempts: []apistructs.PipelineTaskRetryAttempt{{Attempt: 1}, {Attempt: 2}},
+	}}
+	assert.Equal(t, retryTask.GenerateExecutorDoneChanDataVersion(), "executor-done-chan-data-version-1-retry-2")
 }
 
 func TestCheckExecutorVersion(t *testing.T) {
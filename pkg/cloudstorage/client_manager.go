@@ -16,7 +16,11 @@ package cloudstorage
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/minio/minio-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
@@ -27,10 +31,24 @@ import (
 type Client interface {
 	UploadFile(bucketName, objectName, file string) (string, error)
 	DownloadFile(bucketName, objectName string) ([]byte, error)
+	// GetObject return reader of the object without buffering it in memory, caller should close the reader
+	GetObject(bucketName, objectName string) (io.ReadCloser, error)
 	GetFileUrl(bucketName, objectName string) (string, error)
 	HealthCheck() error
 }
 
+// IsObjectNotExist reports whether err returned by Client means the object or bucket doesn't exist
+func IsObjectNotExist(err error) bool {
+	switch e := errors.Cause(err).(type) {
+	case oss.ServiceError:
+		return e.StatusCode == http.StatusNotFound || e.Code == "NoSuchKey" || e.Code == "NoSuchBucket"
+	case minio.ErrorResponse:
+		return e.StatusCode == http.StatusNotFound || e.Code == "NoSuchKey" || e.Code == "NoSuchBucket"
+	default:
+		return false
+	}
+}
+
 func New(endpoint, accessKey, secretKey string) (Client, error) {
 	var client Client
 	var err error
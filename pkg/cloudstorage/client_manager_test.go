@@ -14,6 +14,26 @@
 
 package cloudstorage
 
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/minio/minio-go"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsObjectNotExist(t *testing.T) {
+	assert.True(t, IsObjectNotExist(oss.ServiceError{Code: "NoSuchKey", StatusCode: http.StatusNotFound}))
+	assert.True(t, IsObjectNotExist(minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}))
+	assert.True(t, IsObjectNotExist(pkgerrors.Wrap(minio.ErrorResponse{Code: "NoSuchBucket"}, "get object")))
+	assert.False(t, IsObjectNotExist(minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}))
+	assert.False(t, IsObjectNotExist(errors.New("NoSuchKey")))
+	assert.False(t, IsObjectNotExist(nil))
+}
+
 //import (
 //	"testing"
 //
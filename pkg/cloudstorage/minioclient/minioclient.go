@@ -16,6 +16,7 @@ package minioclient
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -87,6 +88,19 @@ func (c *MinioClient) DownloadFile(bucketName, objectName string) ([]byte, error
 	return data, nil
 }
 
+func (c *MinioClient) GetObject(bucketName, objectName string) (io.ReadCloser, error) {
+	obj, err := c.client.GetObject(bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// minio GetObject doesn't request until read, stat to return error such as NoSuchKey at once
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		return nil, err
+	}
+	return obj, nil
+}
+
 func (c *MinioClient) GetFileUrl(bucketName, objectName string) (string, error) {
 	info, err := c.client.StatObject(bucketName, objectName, minio.StatObjectOptions{})
 	if err != nil {
@@ -86,6 +86,14 @@ func (c *OssClient) DownloadFile(bucketName, objectName string) ([]byte, error)
 	return data, nil
 }
 
+func (c *OssClient) GetObject(bucketName, objectName string) (io.ReadCloser, error) {
+	bucket, err := c.client.Bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return bucket.GetObject(objectName)
+}
+
 func (c *OssClient) GetFileUrl(bucketName, objectName string) (string, error) {
 	bucket, err := c.client.Bucket(bucketName)
 	if err != nil {
@@ -25,7 +25,6 @@ import (
 	"time"
 
 	v3 "github.com/coreos/etcd/clientv3"
-	"github.com/robfig/cron"
 	"github.com/sirupsen/logrus"
 
 	"github.com/erda-project/erda/internal/pkg/etcdclient"
@@ -185,10 +184,10 @@ func (c *Cron) AddJob(spec string, cmd Job, names ...string) error {
 	var name string
 	var schedule Schedule
 	var err error
-	if len(strings.Fields(spec)) == 5 {
-		schedule, err = cron.ParseStandard(spec)
+	if len(strings.Fields(spec)) == 5 || HasTimezone(spec) && len(strings.Fields(spec)) == 6 {
+		schedule, err = ParseStandard(spec)
 	} else {
-		schedule, err = cron.Parse(spec)
+		schedule, err = Parse(spec)
 	}
 	if err != nil {
 		return err
@@ -92,8 +92,34 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 	if len(spec) == 0 {
 		return nil, fmt.Errorf("Empty spec string")
 	}
+
+	// Extract timezone if present, e.g. "CRON_TZ=Asia/Shanghai 0 0 2 * * *"
+	var loc *time.Location
+	if HasTimezone(spec) {
+		i := strings.IndexAny(spec, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("Missing spec after timezone: %s", spec)
+		}
+		eq := strings.Index(spec, "=")
+		var err error
+		if loc, err = LoadLocation(spec[eq+1 : i]); err != nil {
+			return nil, err
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+	withLocation := func(schedule Schedule) Schedule {
+		if specSchedule, ok := schedule.(*SpecSchedule); ok && loc != nil {
+			specSchedule.Location = loc
+		}
+		return schedule
+	}
+
 	if spec[0] == '@' && p.options&Descriptor > 0 {
-		return parseDescriptor(spec)
+		schedule, err := parseDescriptor(spec)
+		if err != nil {
+			return nil, err
+		}
+		return withLocation(schedule), nil
 	}
 
 	// Figure out how many fields we need
@@ -141,14 +167,14 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 		return nil, err
 	}
 
-	return &SpecSchedule{
+	return withLocation(&SpecSchedule{
 		Second: second,
 		Minute: minute,
 		Hour:   hour,
 		Dom:    dayofmonth,
 		Month:  month,
 		Dow:    dayofweek,
-	}, nil
+	}), nil
 }
 
 func expandFields(fields []string, options ParseOption) []string {
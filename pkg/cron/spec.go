@@ -20,6 +20,10 @@ import "time"
 // traditional crontab specification. It is computed initially and stored as bit sets.
 type SpecSchedule struct {
 	Second, Minute, Hour, Dom, Month, Dow uint64
+
+	// Location is the time zone the schedule is evaluated in.
+	// If nil, the schedule is evaluated in the location of the given time.
+	Location *time.Location
 }
 
 // bounds provides a range of acceptable values (plus a map of name to value).
@@ -67,6 +71,71 @@ const (
 // Next returns the next time this schedule is activated, greater than the given
 // time.  If no time can be found to satisfy the schedule, return the zero time.
 func (s *SpecSchedule) Next(t time.Time) time.Time {
+	if s.Location == nil {
+		return s.next(t)
+	}
+	origLocation := t.Location()
+	// Schedules with fixed hours are matched against wall clock time, so that they run
+	// exactly once a day across daylight saving time transitions.
+	if s.Hour&starBit == 0 {
+		return s.nextWallClock(t.In(s.Location)).In(origLocation)
+	}
+	return s.next(t.In(s.Location)).In(origLocation)
+}
+
+// nextWallClock returns the next activation after t, computed on wall clock time in t's location.
+//   - a wall clock time skipped by a forward transition runs at the transition instant
+//   - a wall clock time repeated by a backward transition runs only at its first occurrence
+func (s *SpecSchedule) nextWallClock(t time.Time) time.Time {
+	loc := t.Location()
+	wall := toWallClock(t)
+	for {
+		wall = s.next(wall)
+		if wall.IsZero() {
+			return wall
+		}
+		if next := fromWallClock(wall, loc); next.After(t) {
+			return next
+		}
+	}
+}
+
+// toWallClock returns a UTC time with the same wall clock fields as t, which has no DST transitions.
+func toWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// fromWallClock returns the first instant in loc whose wall clock time is wall.
+// If the wall clock time is skipped by a forward transition, the transition instant is returned.
+func fromWallClock(wall time.Time, loc *time.Location) time.Time {
+	var first time.Time
+	// the offsets used in loc around wall
+	for _, d := range []time.Duration{-12 * time.Hour, 0, 12 * time.Hour} {
+		_, offset := wall.Add(d).In(loc).Zone()
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if toWallClock(candidate).Equal(wall) && (first.IsZero() || candidate.Before(first)) {
+			first = candidate
+		}
+	}
+	if !first.IsZero() {
+		return first
+	}
+
+	// wall clock time is skipped, find the transition instant by binary search
+	lo, hi := wall.Add(-24*time.Hour), wall.Add(24*time.Hour)
+	_, loOffset := lo.In(loc).Zone()
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, offset := mid.In(loc).Zone(); offset == loOffset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Second).In(loc)
+}
+
+func (s *SpecSchedule) next(t time.Time) time.Time {
 	// General approach:
 	// For Month, Day, Hour, Minute, Second:
 	// Check if the time value matches.  If yes, continue to the next field.
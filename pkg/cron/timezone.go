@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	timezonePrefix      = "CRON_TZ="
+	shortTimezonePrefix = "TZ="
+)
+
+// LoadLocation returns the location of the IANA time zone name, such as "Asia/Shanghai".
+// Empty name is not allowed, since time.LoadLocation treats it as UTC.
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return nil, fmt.Errorf("Empty timezone")
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid timezone %s: %v", timezone, err)
+	}
+	return loc, nil
+}
+
+// SpecWithTimezone prefixes spec with CRON_TZ=<timezone>, so that the schedule is evaluated in the time zone.
+// Spec is returned directly if timezone is empty or spec already has a timezone.
+func SpecWithTimezone(spec, timezone string) string {
+	if timezone == "" || spec == "" || HasTimezone(spec) {
+		return spec
+	}
+	return timezonePrefix + timezone + " " + spec
+}
+
+// HasTimezone returns whether spec is prefixed with CRON_TZ= or TZ=.
+func HasTimezone(spec string) bool {
+	return strings.HasPrefix(spec, timezonePrefix) || strings.HasPrefix(spec, shortTimezonePrefix)
+}
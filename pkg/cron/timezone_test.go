@@ -0,0 +1,92 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextInLocation(t *testing.T) {
+	runs := []struct {
+		from, spec string
+		expected   string
+	}{
+		{"2021-01-01T00:00:00Z", "CRON_TZ=Asia/Shanghai 0 0 2 * * *", "2021-01-01T18:00:00Z"},
+		{"2021-01-01T00:00:00Z", "TZ=Asia/Tokyo @daily", "2021-01-01T15:00:00Z"},
+		{"2021-01-01T00:00:00Z", "CRON_TZ=Asia/Kolkata 0 0 * * * *", "2021-01-01T00:30:00Z"},
+
+		// forward transition, 02:30 is skipped and runs at 03:00 EDT
+		{"2021-03-13T17:00:00Z", "CRON_TZ=America/New_York 0 30 2 * * *", "2021-03-14T07:00:00Z"},
+		{"2021-03-14T07:00:00Z", "CRON_TZ=America/New_York 0 30 2 * * *", "2021-03-15T06:30:00Z"},
+		{"2021-03-13T17:00:00Z", "CRON_TZ=America/New_York 0 30 3 * * *", "2021-03-14T07:30:00Z"},
+
+		// backward transition, 01:30 is repeated and only runs once
+		{"2021-11-06T17:00:00Z", "CRON_TZ=America/New_York 0 30 1 * * *", "2021-11-07T05:30:00Z"},
+		{"2021-11-07T05:30:00Z", "CRON_TZ=America/New_York 0 30 1 * * *", "2021-11-08T06:30:00Z"},
+		{"2021-11-07T06:10:00Z", "CRON_TZ=America/New_York 0 30 1 * * *", "2021-11-08T06:30:00Z"},
+		// schedules with every hour still run in the repeated hour
+		{"2021-11-07T05:30:00Z", "CRON_TZ=America/New_York 0 0 * * * *", "2021-11-07T06:00:00Z"},
+		{"2021-11-07T06:00:00Z", "CRON_TZ=America/New_York 0 0 * * * *", "2021-11-07T07:00:00Z"},
+	}
+	for _, c := range runs {
+		sched, err := Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		from, _ := time.Parse(time.RFC3339, c.from)
+		expected, _ := time.Parse(time.RFC3339, c.expected)
+		actual := sched.Next(from)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.from, c.spec, expected, actual)
+		}
+		if actual.Location() != from.Location() {
+			t.Errorf("%s, \"%s\": expected location %v, actual %v", c.from, c.spec, from.Location(), actual.Location())
+		}
+	}
+}
+
+func TestParseTimezoneErrors(t *testing.T) {
+	invalidSpecs := []string{
+		"CRON_TZ=Mars/Olympus 0 0 2 * * *",
+		"CRON_TZ=Asia/Shanghai",
+		"TZ= 0 0 2 * * *",
+	}
+	for _, spec := range invalidSpecs {
+		if _, err := Parse(spec); err == nil {
+			t.Error("expected an error parsing: ", spec)
+		}
+	}
+}
+
+func TestSpecWithTimezone(t *testing.T) {
+	tests := []struct {
+		spec, timezone, expected string
+	}{
+		{"0 2 * * *", "Asia/Shanghai", "CRON_TZ=Asia/Shanghai 0 2 * * *"},
+		{"0 2 * * *", "", "0 2 * * *"},
+		{"TZ=UTC 0 2 * * *", "Asia/Shanghai", "TZ=UTC 0 2 * * *"},
+		{"", "Asia/Shanghai", ""},
+	}
+	for _, c := range tests {
+		if actual := SpecWithTimezone(c.spec, c.timezone); actual != c.expected {
+			t.Errorf("(expected) %s != %s (actual)", c.expected, actual)
+		}
+	}
+	if _, err := ParseStandard(SpecWithTimezone("0 2 * * *", "Europe/Berlin")); err != nil {
+		t.Error(err)
+	}
+}
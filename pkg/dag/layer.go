@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Layers 按拓扑顺序将 DAG 的节点分层返回.
+// 每个节点位于其所有前置节点所在层之后的第一层，同一层内的节点之间没有依赖关系，可以并行执行；
+// 层内节点名按字典序排列，保证结果稳定。
+func (g *DAG) Layers() ([][]string, error) {
+	inDegrees := make(map[string]int, len(g.Nodes))
+	var current []string
+	for name, node := range g.Nodes {
+		inDegrees[name] = len(node.prevNodes)
+		if len(node.prevNodes) == 0 {
+			current = append(current, name)
+		}
+	}
+
+	var layers [][]string
+	var visitedNum int
+	for len(current) > 0 {
+		sort.Strings(current)
+		layers = append(layers, current)
+		visitedNum += len(current)
+
+		var next []string
+		for _, name := range current {
+			for _, nextNode := range g.Nodes[name].nextNodes {
+				inDegrees[nextNode.name]--
+				if inDegrees[nextNode.name] == 0 {
+					next = append(next, nextNode.name)
+				}
+			}
+		}
+		current = next
+	}
+
+	if visitedNum != len(g.Nodes) {
+		var inCycle []string
+		for name, degree := range inDegrees {
+			if degree > 0 {
+				inCycle = append(inCycle, name)
+			}
+		}
+		sort.Strings(inCycle)
+		return nil, errors.Errorf("cycle detected among nodes: %v", inCycle)
+	}
+
+	return layers, nil
+}
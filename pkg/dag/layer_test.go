@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayers(t *testing.T) {
+
+	//   a    b   c
+	//   | \ /
+	//   x  y
+	//   |  |
+	//   z  |
+	//    \ |
+	//      w
+
+	a := &MyNode{name: "a"}
+	b := &MyNode{name: "b"}
+	c := &MyNode{name: "c"}
+	x := &MyNode{name: "x", runAfter: []string{"a"}}
+	y := &MyNode{name: "y", runAfter: []string{"a", "b"}}
+	z := &MyNode{name: "z", runAfter: []string{"x"}}
+	w := &MyNode{name: "w", runAfter: []string{"z", "y"}}
+
+	g, err := New([]NamedNode{w, z, y, x, c, b, a})
+	assert.NoError(t, err)
+	layers, err := g.Layers()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"x", "y"}, {"z"}, {"w"}}, layers)
+}
+
+func TestLayers_Cycle(t *testing.T) {
+	a := &MyNode{name: "a", runAfter: []string{"c"}}
+	b := &MyNode{name: "b", runAfter: []string{"a"}}
+	c := &MyNode{name: "c", runAfter: []string{"b"}}
+	d := &MyNode{name: "d"}
+
+	g, err := New([]NamedNode{a, b, c, d}, WithAllowNotCheckCycle(true))
+	assert.NoError(t, err)
+	_, err = g.Layers()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[a b c]")
+}
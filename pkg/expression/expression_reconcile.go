@@ -41,9 +41,11 @@ const (
 	Params       = "params"
 	Globals      = "globals"
 	Configs      = "configs"
+	Secrets      = "secrets"
 	Base64Decode = "base64-decode"
 	TriggerLabel = "triggers"
 	I18n         = "i18n"
+	Matrix       = "matrix"
 )
 
 const (
@@ -20,4 +20,5 @@ import (
 
 	// stores
 	_ "github.com/erda-project/erda/pkg/kms/stores/etcd"
+	_ "github.com/erda-project/erda/pkg/kms/stores/mysql"
 )
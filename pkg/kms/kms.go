@@ -33,6 +33,7 @@ type Manager struct {
 
 	storeFactory map[kmstypes.StoreKind]kmstypes.StoreCreateFn
 	stores       map[kmstypes.StoreKind]kmstypes.Store
+	storeLock    sync.Mutex
 
 	pluginCtx context.Context
 	storeCtx  context.Context
@@ -78,11 +79,10 @@ func (m *Manager) initialize(ops ...Option) error {
 		}
 
 		// store
+		// stores are created lazily when used, because each store requires its own configs,
+		// e.g. etcd store requires ETCD_ENDPOINTS, which is not necessary when use mysql store
 		m.storeFactory = kmstypes.StoreFactory
 		m.stores = make(map[kmstypes.StoreKind]kmstypes.Store)
-		for kind, createFn := range m.storeFactory {
-			m.stores[kind] = createFn(m.storeCtx)
-		}
 	})
 	return nil
 }
@@ -105,9 +105,20 @@ func (m *Manager) GetPlugin(pluginKind kmstypes.PluginKind, storeKind kmstypes.S
 }
 
 func (m *Manager) GetStore(storeKind kmstypes.StoreKind) (kmstypes.Store, error) {
-	store, ok := m.stores[storeKind]
-	if !ok || store == nil {
+	m.storeLock.Lock()
+	defer m.storeLock.Unlock()
+
+	if store, ok := m.stores[storeKind]; ok && store != nil {
+		return store, nil
+	}
+	createFn, ok := m.storeFactory[storeKind]
+	if !ok {
 		return nil, fmt.Errorf("not found store kind: %s", storeKind)
 	}
+	store := createFn(m.storeCtx)
+	if store == nil {
+		return nil, fmt.Errorf("failed to create store, kind: %s", storeKind)
+	}
+	m.stores[storeKind] = store
 	return store, nil
 }
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmscrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// pssOptions salt length equals to hash length, which is the most compatible choice
+var pssOptions = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+
+// GenEcdsaP256Key return publicKey, privateKey, error
+func GenEcdsaP256Key() ([]byte, []byte, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateStream, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKeyBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "private key",
+		Bytes: privateStream,
+	})
+	publicStream, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKeyBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "public key",
+		Bytes: publicStream,
+	})
+	return publicKeyBytes, privateKeyBytes, nil
+}
+
+// SignRsaPss signs SHA-256 digest by RSA private key in pem format (PKCS1), using RSASSA-PSS
+func SignRsaPss(privateKeyPem []byte, digest []byte) ([]byte, error) {
+	block, _ := pem.Decode(privateKeyPem)
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key pem")
+	}
+	privateKey, err := ParsePrivateKey(block.Bytes, PKCS1)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.SignPSS(rand.Reader, privateKey, crypto.SHA256, digest, pssOptions)
+}
+
+// VerifyRsaPss verifies RSASSA-PSS signature of SHA-256 digest by RSA public key in pem format (PKIX)
+func VerifyRsaPss(publicKeyPem []byte, digest, signature []byte) error {
+	publicKey, err := parsePublicKey(publicKeyPem)
+	if err != nil {
+		return err
+	}
+	rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("not rsa public key")
+	}
+	return rsa.VerifyPSS(rsaPublicKey, crypto.SHA256, digest, signature, pssOptions)
+}
+
+// SignEcdsa signs SHA-256 digest by ECDSA private key in pem format (SEC1), signature is ASN.1 DER encoded
+func SignEcdsa(privateKeyPem []byte, digest []byte) ([]byte, error) {
+	block, _ := pem.Decode(privateKeyPem)
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key pem")
+	}
+	privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return ecdsa.SignASN1(rand.Reader, privateKey, digest)
+}
+
+// VerifyEcdsa verifies ASN.1 DER encoded ECDSA signature of SHA-256 digest by public key in pem format (PKIX)
+func VerifyEcdsa(publicKeyPem []byte, digest, signature []byte) error {
+	publicKey, err := parsePublicKey(publicKeyPem)
+	if err != nil {
+		return err
+	}
+	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("not ecdsa public key")
+	}
+	if !ecdsa.VerifyASN1(ecdsaPublicKey, digest, signature) {
+		return fmt.Errorf("ecdsa verification error")
+	}
+	return nil
+}
+
+func parsePublicKey(publicKeyPem []byte) (interface{}, error) {
+	block, _ := pem.Decode(publicKeyPem)
+	if block == nil {
+		return nil, fmt.Errorf("invalid public key pem")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
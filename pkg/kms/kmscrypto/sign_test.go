@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmscrypto
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerifyRsaPss(t *testing.T) {
+	publicKey, privateKey, err := GenRsaKey(2048)
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("release artifact"))
+
+	signature, err := SignRsaPss(privateKey, digest[:])
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyRsaPss(publicKey, digest[:], signature))
+
+	tampered := sha256.Sum256([]byte("tampered artifact"))
+	assert.Error(t, VerifyRsaPss(publicKey, tampered[:], signature))
+}
+
+func TestSignVerifyEcdsa(t *testing.T) {
+	publicKey, privateKey, err := GenEcdsaP256Key()
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("webhook payload"))
+
+	signature, err := SignEcdsa(privateKey, digest[:])
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyEcdsa(publicKey, digest[:], signature))
+
+	tampered := sha256.Sum256([]byte("tampered payload"))
+	assert.Error(t, VerifyEcdsa(publicKey, tampered[:], signature))
+
+	// public key type mismatch
+	rsaPublicKey, _, err := GenRsaKey(2048)
+	assert.NoError(t, err)
+	assert.Error(t, VerifyEcdsa(rsaPublicKey, digest[:], signature))
+}
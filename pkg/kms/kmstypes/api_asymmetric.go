@@ -14,19 +14,158 @@
 
 package kmstypes
 
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+type (
+	SigningAlgorithm string
+	MessageType      string
+)
+
+const (
+	// SigningAlgorithm_RSASSA_PSS_SHA_256 used by RSA_2048/RSA_3072/RSA_4096 key spec
+	SigningAlgorithm_RSASSA_PSS_SHA_256 SigningAlgorithm = "RSASSA_PSS_SHA_256"
+	// SigningAlgorithm_ECDSA_SHA_256 used by EC_P256 key spec
+	SigningAlgorithm_ECDSA_SHA_256 SigningAlgorithm = "ECDSA_SHA_256"
+
+	// EncryptionAlgorithm_RSAES_PKCS1_V1_5 is the algorithm to encrypt data by public key for AsymmetricDecrypt
+	EncryptionAlgorithm_RSAES_PKCS1_V1_5 = "RSAES_PKCS1_V1_5"
+
+	// MessageType_RAW means message is the raw data, kms will calculate the SHA-256 digest
+	MessageType_RAW MessageType = "RAW"
+	// MessageType_DIGEST means message is the SHA-256 digest of raw data, used when data is too large
+	MessageType_DIGEST MessageType = "DIGEST"
+)
+
 type AsymmetricDecryptRequest struct {
-	KeyID            string `json:"keyID,omitempty"`
-	CiphertextBase64 []byte `json:"ciphertextBase64,omitempty"`
+	KeyID string `json:"keyID,omitempty"`
+	// KeyVersionID is the version of public key used to encrypt data, default is primary key version.
+	KeyVersionID string `json:"keyVersionID,omitempty"`
+	// The data encrypted by public key with RSAES_PKCS1_V1_5.
+	// A base64-encoded string.
+	CiphertextBase64 string `json:"ciphertextBase64,omitempty"`
+}
+
+func (req *AsymmetricDecryptRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	if len(req.CiphertextBase64) == 0 {
+		return fmt.Errorf("missing ciphertextBase64")
+	}
+	if _, err := base64.StdEncoding.DecodeString(req.CiphertextBase64); err != nil {
+		return fmt.Errorf("cannot decode base64 ciphertext, err: %v", err)
+	}
+	return nil
 }
+
 type AsymmetricDecryptResponse struct {
-	PlaintextBase64 []byte `json:"plaintextBase64,omitempty"`
+	KeyID           string `json:"keyID,omitempty"`
+	KeyVersionID    string `json:"keyVersionID,omitempty"`
+	PlaintextBase64 string `json:"plaintextBase64,omitempty"`
 }
 
 type GetPublicKeyRequest struct {
 	KeyID string `json:"keyID,omitempty"`
+	// KeyVersionID default is primary key version
+	KeyVersionID string `json:"keyVersionID,omitempty"`
+}
+
+func (req *GetPublicKeyRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	return nil
 }
 
 type PublicKey struct {
-	Pem       string `json:"pem,omitempty"`
+	KeyID        string `json:"keyID,omitempty"`
+	KeyVersionID string `json:"keyVersionID,omitempty"`
+	Pem          string `json:"pem,omitempty"`
+	// Algorithm is the encryption or signing algorithm can be used with the public key
 	Algorithm string `json:"algorithm,omitempty"`
 }
+
+type SignRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+	// The message or message digest to sign. Must be no larger than 4KiB.
+	// A base64-encoded string.
+	MessageBase64 string `json:"messageBase64,omitempty"`
+	// MessageType is RAW or DIGEST, default is RAW
+	MessageType MessageType `json:"messageType,omitempty"`
+	// SigningAlgorithm default is decided by key spec
+	SigningAlgorithm SigningAlgorithm `json:"signingAlgorithm,omitempty"`
+}
+
+func (req *SignRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	return validateMessage(req.MessageBase64, &req.MessageType)
+}
+
+type SignResponse struct {
+	KeyID string `json:"keyID,omitempty"`
+	// KeyVersionID is the version of private key used to sign, should be passed to Verify after key rotated
+	KeyVersionID     string           `json:"keyVersionID,omitempty"`
+	SigningAlgorithm SigningAlgorithm `json:"signingAlgorithm,omitempty"`
+	// The signature.
+	// A base64-encoded string.
+	SignatureBase64 string `json:"signatureBase64,omitempty"`
+}
+
+type VerifyRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+	// KeyVersionID default is primary key version
+	KeyVersionID     string           `json:"keyVersionID,omitempty"`
+	MessageBase64    string           `json:"messageBase64,omitempty"`
+	MessageType      MessageType      `json:"messageType,omitempty"`
+	SigningAlgorithm SigningAlgorithm `json:"signingAlgorithm,omitempty"`
+	SignatureBase64  string           `json:"signatureBase64,omitempty"`
+}
+
+func (req *VerifyRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	if len(req.SignatureBase64) == 0 {
+		return fmt.Errorf("missing signatureBase64")
+	}
+	if _, err := base64.StdEncoding.DecodeString(req.SignatureBase64); err != nil {
+		return fmt.Errorf("cannot decode base64 signature, err: %v", err)
+	}
+	return validateMessage(req.MessageBase64, &req.MessageType)
+}
+
+type VerifyResponse struct {
+	KeyID            string           `json:"keyID,omitempty"`
+	KeyVersionID     string           `json:"keyVersionID,omitempty"`
+	SigningAlgorithm SigningAlgorithm `json:"signingAlgorithm,omitempty"`
+	SignatureValid   bool             `json:"signatureValid"`
+}
+
+func validateMessage(messageBase64 string, messageType *MessageType) error {
+	if len(messageBase64) == 0 {
+		return fmt.Errorf("missing messageBase64")
+	}
+	message, err := base64.StdEncoding.DecodeString(messageBase64)
+	if err != nil {
+		return fmt.Errorf("cannot decode base64 message, err: %v", err)
+	}
+	switch *messageType {
+	case "":
+		*messageType = MessageType_RAW
+	case MessageType_RAW, MessageType_DIGEST:
+	default:
+		return fmt.Errorf("invalid messageType: %s", *messageType)
+	}
+	if len(message) > 4*1024 {
+		return fmt.Errorf("message is larger than 4KiB, please sign the SHA-256 digest with messageType %s", MessageType_DIGEST)
+	}
+	if *messageType == MessageType_DIGEST && len(message) != 32 {
+		return fmt.Errorf("invalid SHA-256 digest length: %d", len(message))
+	}
+	return nil
+}
@@ -35,6 +35,7 @@ const (
 	CustomerMasterKeySpec_ASYMMETRIC_RSA_2048 CustomerMasterKeySpec = "RSA_2048"
 	CustomerMasterKeySpec_ASYMMETRIC_RSA_3072 CustomerMasterKeySpec = "RSA_3072"
 	CustomerMasterKeySpec_ASYMMETRIC_RSA_4096 CustomerMasterKeySpec = "RSA_4096"
+	CustomerMasterKeySpec_ASYMMETRIC_EC_P256  CustomerMasterKeySpec = "EC_P256" // ECDSA P-256 ; only for SIGN_VERIFY
 
 	KeyUsage_ENCRYPT_DECRYPT KeyUsage = "ENCRYPT_DECRYPT"
 	KeyUsage_SIGN_VERIFY     KeyUsage = "SIGN_VERIFY"
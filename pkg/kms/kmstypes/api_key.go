@@ -14,7 +14,10 @@
 
 package kmstypes
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type (
 	CustomerMasterKeySpec string
@@ -25,13 +28,49 @@ type (
 func (s *CustomerMasterKeySpec) IsValid() bool {
 	switch *s {
 	case CustomerMasterKeySpec_SYMMETRIC_DEFAULT, CustomerMasterKeySpec_ASYMMETRIC_RSA_2048,
-		CustomerMasterKeySpec_ASYMMETRIC_RSA_3072, CustomerMasterKeySpec_ASYMMETRIC_RSA_4096:
+		CustomerMasterKeySpec_ASYMMETRIC_RSA_3072, CustomerMasterKeySpec_ASYMMETRIC_RSA_4096,
+		CustomerMasterKeySpec_ASYMMETRIC_EC_P256:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsRSA return whether key spec is one of RSA_2048/RSA_3072/RSA_4096
+func (s *CustomerMasterKeySpec) IsRSA() bool {
+	switch *s {
+	case CustomerMasterKeySpec_ASYMMETRIC_RSA_2048, CustomerMasterKeySpec_ASYMMETRIC_RSA_3072,
+		CustomerMasterKeySpec_ASYMMETRIC_RSA_4096:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportKeyUsage return whether the key spec can be used for the key usage
+func (s *CustomerMasterKeySpec) SupportKeyUsage(usage KeyUsage) bool {
+	switch usage {
+	case KeyUsage_ENCRYPT_DECRYPT:
+		return *s == CustomerMasterKeySpec_SYMMETRIC_DEFAULT || s.IsRSA()
+	case KeyUsage_SIGN_VERIFY:
+		return *s == CustomerMasterKeySpec_ASYMMETRIC_EC_P256 || s.IsRSA()
+	default:
+		return false
+	}
+}
+
+// DefaultSigningAlgorithm return the signing algorithm of key spec, empty if key spec cannot sign
+func (s *CustomerMasterKeySpec) DefaultSigningAlgorithm() SigningAlgorithm {
+	switch {
+	case s.IsRSA():
+		return SigningAlgorithm_RSASSA_PSS_SHA_256
+	case *s == CustomerMasterKeySpec_ASYMMETRIC_EC_P256:
+		return SigningAlgorithm_ECDSA_SHA_256
+	default:
+		return ""
+	}
+}
+
 type (
 	KeyMetadata struct {
 		KeyID                 string                `json:"keyID,omitempty"`
@@ -40,6 +79,15 @@ type (
 		KeyUsage              KeyUsage              `json:"keyUsage,omitempty"`
 		KeyState              KeyState              `json:"keyState,omitempty"`
 		Description           string                `json:"description,omitempty"`
+		// OrgID is the org which the key belongs to, empty if the key is not owned by any org
+		OrgID string `json:"orgID,omitempty"`
+		// ProjectID is the project which the key belongs to, empty if the key is not owned by any project
+		ProjectID string `json:"projectID,omitempty"`
+		// RotationIntervalDays is the automatic rotation period, 0 means automatic rotation is disabled
+		RotationIntervalDays int        `json:"rotationIntervalDays,omitempty"`
+		NextRotationAt       *time.Time `json:"nextRotationAt,omitempty"`
+		// DeletionAt is the time when key will be deleted, only valid for key in PendingDeletion state
+		DeletionAt *time.Time `json:"deletionAt,omitempty"`
 	}
 
 	KeyListEntry struct {
@@ -52,6 +100,12 @@ type CreateKeyRequest struct {
 	CustomerMasterKeySpec CustomerMasterKeySpec `json:"customerMasterKeySpec,omitempty"`
 	KeyUsage              KeyUsage              `json:"keyUsage,omitempty"`
 	Description           string                `json:"description,omitempty"`
+	// OrgID the org which the key belongs to, resources of other orgs are not allowed to use the key
+	OrgID string `json:"orgID,omitempty"`
+	// ProjectID the project which the key belongs to, resources of other projects are not allowed to use the key
+	ProjectID string `json:"projectID,omitempty"`
+	// RotationIntervalDays enable automatic rotation if set
+	RotationIntervalDays int `json:"rotationIntervalDays,omitempty"`
 }
 
 func (req *CreateKeyRequest) ValidateRequest() error {
@@ -64,6 +118,11 @@ func (req *CreateKeyRequest) ValidateRequest() error {
 	if req.KeyUsage == "" {
 		req.KeyUsage = KeyUsage_ENCRYPT_DECRYPT
 	}
+	if req.RotationIntervalDays != 0 {
+		if err := ValidateRotationIntervalDays(req.RotationIntervalDays); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
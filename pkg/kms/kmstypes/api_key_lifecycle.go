@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmstypes
+
+import (
+	"fmt"
+	"time"
+)
+
+const Day = 24 * time.Hour
+
+const (
+	MinRotationIntervalDays = 7
+	MaxRotationIntervalDays = 3650
+
+	MinPendingWindowInDays     = 7
+	MaxPendingWindowInDays     = 30
+	DefaultPendingWindowInDays = 30
+)
+
+func ValidateRotationIntervalDays(days int) error {
+	if days < MinRotationIntervalDays || days > MaxRotationIntervalDays {
+		return fmt.Errorf("invalid rotationIntervalDays: %d, must be between %d and %d",
+			days, MinRotationIntervalDays, MaxRotationIntervalDays)
+	}
+	return nil
+}
+
+// CheckKeyEnabled only Enabled key can be used in cryptographic operations
+func CheckKeyEnabled(keyInfo KeyInfo) error {
+	if keyInfo.GetKeyState() != KeyStateEnabled {
+		return fmt.Errorf("key %s is %s, expect: %s", keyInfo.GetKeyID(), keyInfo.GetKeyState(), KeyStateEnabled)
+	}
+	return nil
+}
+
+// IsRotationDue return true if automatic rotation of Enabled key is due
+func IsRotationDue(keyInfo KeyInfo, now time.Time) bool {
+	if keyInfo.GetKeyState() != KeyStateEnabled || keyInfo.GetRotationInterval() <= 0 {
+		return false
+	}
+	nextRotationAt := keyInfo.GetNextRotationAt()
+	return nextRotationAt != nil && !now.Before(*nextRotationAt)
+}
+
+// IsDeletionDue return true if the waiting period of PendingDeletion key is over
+func IsDeletionDue(keyInfo KeyInfo, now time.Time) bool {
+	if keyInfo.GetKeyState() != KeyStatePendingDeletion {
+		return false
+	}
+	deletionAt := keyInfo.GetDeletionAt()
+	return deletionAt != nil && !now.Before(*deletionAt)
+}
+
+type EnableKeyRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+}
+
+func (req *EnableKeyRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	return nil
+}
+
+type DisableKeyRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+}
+
+func (req *DisableKeyRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	return nil
+}
+
+type CancelKeyDeletionRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+}
+
+func (req *CancelKeyDeletionRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	return nil
+}
+
+type ScheduleKeyDeletionRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+	// PendingWindowInDays is the waiting period before key is deleted, default is 30 days.
+	// Key can be restored by CancelKeyDeletion during the waiting period.
+	PendingWindowInDays int `json:"pendingWindowInDays,omitempty"`
+}
+
+func (req *ScheduleKeyDeletionRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	if req.PendingWindowInDays == 0 {
+		req.PendingWindowInDays = DefaultPendingWindowInDays
+	}
+	if req.PendingWindowInDays < MinPendingWindowInDays || req.PendingWindowInDays > MaxPendingWindowInDays {
+		return fmt.Errorf("invalid pendingWindowInDays: %d, must be between %d and %d",
+			req.PendingWindowInDays, MinPendingWindowInDays, MaxPendingWindowInDays)
+	}
+	return nil
+}
+
+type UpdateRotationPolicyRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+	// RotationIntervalDays is the automatic rotation period, 0 means disable automatic rotation
+	RotationIntervalDays int `json:"rotationIntervalDays"`
+}
+
+func (req *UpdateRotationPolicyRequest) ValidateRequest() error {
+	if req.KeyID == "" {
+		return fmt.Errorf("missing keyID")
+	}
+	if req.RotationIntervalDays == 0 {
+		return nil
+	}
+	return ValidateRotationIntervalDays(req.RotationIntervalDays)
+}
+
+// KeyLifecycleResponse is the response of key lifecycle operations, contains the latest metadata of key
+type KeyLifecycleResponse struct {
+	KeyMetadata KeyMetadata `json:"keyMetadata,omitempty"`
+}
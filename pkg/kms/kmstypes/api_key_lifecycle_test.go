@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmstypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRotationDue(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	key := &Key{KeyState: KeyStateEnabled, RotationInterval: 90 * Day, NextRotationAt: &past}
+	assert.True(t, IsRotationDue(key, now))
+
+	key.KeyState = KeyStateDisabled
+	assert.False(t, IsRotationDue(key, now))
+
+	key.KeyState = KeyStateEnabled
+	key.RotationInterval = 0
+	assert.False(t, IsRotationDue(key, now))
+
+	future := now.Add(time.Minute)
+	key.RotationInterval = 90 * Day
+	key.NextRotationAt = &future
+	assert.False(t, IsRotationDue(key, now))
+}
+
+func TestIsDeletionDue(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	key := &Key{KeyState: KeyStatePendingDeletion, DeletionAt: &past}
+	assert.True(t, IsDeletionDue(key, now))
+
+	key.KeyState = KeyStateDisabled
+	assert.False(t, IsDeletionDue(key, now))
+
+	future := now.Add(time.Minute)
+	key.KeyState = KeyStatePendingDeletion
+	key.DeletionAt = &future
+	assert.False(t, IsDeletionDue(key, now))
+}
+
+func TestScheduleKeyDeletionRequest_ValidateRequest(t *testing.T) {
+	req := ScheduleKeyDeletionRequest{KeyID: "key"}
+	assert.NoError(t, req.ValidateRequest())
+	assert.Equal(t, DefaultPendingWindowInDays, req.PendingWindowInDays)
+
+	req.PendingWindowInDays = 1
+	assert.Error(t, req.ValidateRequest())
+}
+
+func TestUpdateRotationPolicyRequest_ValidateRequest(t *testing.T) {
+	assert.NoError(t, (&UpdateRotationPolicyRequest{KeyID: "key"}).ValidateRequest())
+	assert.NoError(t, (&UpdateRotationPolicyRequest{KeyID: "key", RotationIntervalDays: 90}).ValidateRequest())
+	assert.Error(t, (&UpdateRotationPolicyRequest{KeyID: "key", RotationIntervalDays: 1}).ValidateRequest())
+	assert.Error(t, (&UpdateRotationPolicyRequest{RotationIntervalDays: 90}).ValidateRequest())
+}
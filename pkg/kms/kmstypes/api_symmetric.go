@@ -69,3 +69,40 @@ func (req *DecryptRequest) ValidateRequest() error {
 type DecryptResponse struct {
 	PlaintextBase64 string `json:"plaintextBase64,omitempty"`
 }
+
+type ReEncryptRequest struct {
+	// Required. The ciphertext produced by Encrypt or GenerateDataKey of source key.
+	// A base64-encoded string.
+	CiphertextBase64 string `json:"ciphertextBase64,omitempty"`
+	// Required. The key used to encrypt the ciphertext.
+	SourceKeyID string `json:"sourceKeyID,omitempty"`
+	// Optional. The key used to re-encrypt the data, default is SourceKeyID.
+	// Ciphertext is always re-encrypted by the primary key version of destination key.
+	DestinationKeyID string `json:"destinationKeyID,omitempty"`
+}
+
+func (req *ReEncryptRequest) ValidateRequest() error {
+	if req.SourceKeyID == "" {
+		return fmt.Errorf("missing sourceKeyID")
+	}
+	if req.DestinationKeyID == "" {
+		req.DestinationKeyID = req.SourceKeyID
+	}
+	if len(req.CiphertextBase64) == 0 {
+		return fmt.Errorf("missing ciphertextBase64")
+	}
+	if _, err := base64.StdEncoding.DecodeString(req.CiphertextBase64); err != nil {
+		return fmt.Errorf("cannot decode base64 ciphertext, err: %v", err)
+	}
+	return nil
+}
+
+type ReEncryptResponse struct {
+	SourceKeyID        string `json:"sourceKeyID,omitempty"`
+	SourceKeyVersionID string `json:"sourceKeyVersionID,omitempty"`
+	KeyID              string `json:"keyID,omitempty"`
+	KeyVersionID       string `json:"keyVersionID,omitempty"`
+	// The re-encrypted data.
+	// A base64-encoded string.
+	CiphertextBase64 string `json:"ciphertextBase64,omitempty"`
+}
@@ -41,6 +41,23 @@ type KeyInfo interface {
 	GetDescription() string
 	SetDescription(string)
 
+	// GetOrgID return the org which the key belongs to, empty if the key is not owned by any org
+	GetOrgID() string
+	SetOrgID(string)
+
+	// GetProjectID return the project which the key belongs to, empty if the key is not owned by any project
+	GetProjectID() string
+	SetProjectID(string)
+
+	// rotation policy, zero RotationInterval means automatic rotation is disabled
+	GetRotationInterval() time.Duration
+	SetRotationInterval(time.Duration)
+	GetNextRotationAt() *time.Time
+	SetNextRotationAt(*time.Time)
+	// GetDeletionAt return the time when key in PendingDeletion state will be deleted
+	GetDeletionAt() *time.Time
+	SetDeletionAt(*time.Time)
+
 	GetCreatedAt() *time.Time
 	SetCreatedAt(time.Time)
 	GetUpdatedAt() *time.Time
@@ -55,6 +72,11 @@ func GetKeyMetadata(keyInfo KeyInfo) KeyMetadata {
 		KeyUsage:              keyInfo.GetKeyUsage(),
 		KeyState:              keyInfo.GetKeyState(),
 		Description:           keyInfo.GetDescription(),
+		OrgID:                 keyInfo.GetOrgID(),
+		ProjectID:             keyInfo.GetProjectID(),
+		RotationIntervalDays:  int(keyInfo.GetRotationInterval() / Day),
+		NextRotationAt:        keyInfo.GetNextRotationAt(),
+		DeletionAt:            keyInfo.GetDeletionAt(),
 	}
 }
 
@@ -109,6 +131,11 @@ type Key struct {
 	KeyUsage          KeyUsage              `json:"keyUsage,omitempty"`
 	KeyState          KeyState              `json:"keyState,omitempty"`
 	Description       string                `json:"description,omitempty"`
+	OrgID             string                `json:"orgID,omitempty"`
+	ProjectID         string                `json:"projectID,omitempty"`
+	RotationInterval  time.Duration         `json:"rotationInterval,omitempty"`
+	NextRotationAt    *time.Time            `json:"nextRotationAt,omitempty"`
+	DeletionAt        *time.Time            `json:"deletionAt,omitempty"`
 	CreatedAt         *time.Time            `json:"createdAt,omitempty"`
 	UpdatedAt         *time.Time            `json:"updatedAt,omitempty"`
 }
@@ -126,6 +153,16 @@ func (k *Key) GetKeyState() KeyState                 { return k.KeyState }
 func (k *Key) SetKeyState(state KeyState)            { k.KeyState = state }
 func (k *Key) GetDescription() string                { return k.Description }
 func (k *Key) SetDescription(desc string)            { k.Description = desc }
+func (k *Key) GetOrgID() string                      { return k.OrgID }
+func (k *Key) SetOrgID(orgID string)                 { k.OrgID = orgID }
+func (k *Key) GetProjectID() string                  { return k.ProjectID }
+func (k *Key) SetProjectID(projectID string)         { k.ProjectID = projectID }
+func (k *Key) GetRotationInterval() time.Duration    { return k.RotationInterval }
+func (k *Key) SetRotationInterval(d time.Duration)   { k.RotationInterval = d }
+func (k *Key) GetNextRotationAt() *time.Time         { return k.NextRotationAt }
+func (k *Key) SetNextRotationAt(t *time.Time)        { k.NextRotationAt = t }
+func (k *Key) GetDeletionAt() *time.Time             { return k.DeletionAt }
+func (k *Key) SetDeletionAt(t *time.Time)            { k.DeletionAt = t }
 func (k *Key) GetCreatedAt() *time.Time              { return k.CreatedAt }
 func (k *Key) SetCreatedAt(t time.Time)              { k.CreatedAt = &t }
 func (k *Key) GetUpdatedAt() *time.Time              { return k.UpdatedAt }
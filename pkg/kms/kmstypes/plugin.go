@@ -23,6 +23,7 @@ type Plugin interface {
 	Kind() PluginKind
 	SetStore(Store)
 	BasePlugin
+	KeyLifecyclePlugin
 	SymmetricPlugin
 	AsymmetricPlugin
 }
@@ -39,6 +40,23 @@ type BasePlugin interface {
 // 1. 调用 Encrypt 进行加密
 // 解密流程：
 // 1. 调用 Decrypt 进行解密
+// KeyLifecyclePlugin manage key state and rotation policy
+// 密钥状态流转：
+// Enabled <-> Disabled
+// Enabled/Disabled -> PendingDeletion (ScheduleKeyDeletion) -> 等待期结束后由后台任务删除
+// PendingDeletion -> Disabled (CancelKeyDeletion)
+// 只有 Enabled 状态的密钥可以用于加解密、签名等密码运算
+type KeyLifecyclePlugin interface {
+	EnableKey(ctx context.Context, req *EnableKeyRequest) (*KeyLifecycleResponse, error)
+	DisableKey(ctx context.Context, req *DisableKeyRequest) (*KeyLifecycleResponse, error)
+	// ScheduleKeyDeletion mark key as PendingDeletion, key will be deleted after the waiting period
+	ScheduleKeyDeletion(ctx context.Context, req *ScheduleKeyDeletionRequest) (*KeyLifecycleResponse, error)
+	// CancelKeyDeletion cancel deletion during the waiting period, key will be Disabled
+	CancelKeyDeletion(ctx context.Context, req *CancelKeyDeletionRequest) (*KeyLifecycleResponse, error)
+	// UpdateRotationPolicy set or disable automatic rotation period of key
+	UpdateRotationPolicy(ctx context.Context, req *UpdateRotationPolicyRequest) (*KeyLifecycleResponse, error)
+}
+
 type SymmetricPlugin interface {
 	Encrypt(ctx context.Context, req *EncryptRequest) (*EncryptResponse, error)
 	Decrypt(ctx context.Context, req *DecryptRequest) (*DecryptResponse, error)
@@ -54,6 +72,9 @@ type SymmetricPlugin interface {
 	GenerateDataKey(ctx context.Context, req *GenerateDataKeyRequest) (*GenerateDataKeyResponse, error)
 	// RotateKeyVersion rotate key version for CMK manually, old key version still can be used to decrypt old data
 	RotateKeyVersion(ctx context.Context, req *RotateKeyVersionRequest) (*RotateKeyVersionResponse, error)
+	// ReEncrypt decrypt ciphertext and encrypt it by the primary key version of destination key inside KMS,
+	// plaintext is never exposed. Usually used to migrate old ciphertext to new key version after rotation.
+	ReEncrypt(ctx context.Context, req *ReEncryptRequest) (*ReEncryptResponse, error)
 }
 
 // AsymmetricPlugin 非对称加密插件
@@ -63,9 +84,17 @@ type SymmetricPlugin interface {
 // 3. 存储加密后的数据以及密钥版本
 // 解密流程：
 // 1. 调用 AsymmetricDecrypt，传入密文和 解密
+// 签名流程：
+// 1. 调用 Sign，使用私钥对消息或消息摘要签名，私钥不离开 KMS
+// 验签流程：
+// 1. 调用 Verify 验签，或调用 GetPublicKey 获取公钥后在本地验签
 type AsymmetricPlugin interface {
 	GetPublicKey(ctx context.Context, req *GetPublicKeyRequest) (*PublicKey, error)
 	// AsymmetricDecrypt decrypts data that was encrypted with a public key retrieved from GetPublicKey
 	// corresponding to a CryptoKeyVersion with CryptoKey.purpose ASYMMETRIC_DECRYPT.
 	AsymmetricDecrypt(ctx context.Context, req *AsymmetricDecryptRequest) (*AsymmetricDecryptResponse, error)
+	// Sign signs message by the private key of primary key version, key usage must be SIGN_VERIFY
+	Sign(ctx context.Context, req *SignRequest) (*SignResponse, error)
+	// Verify verifies signature by the public key of specified key version
+	Verify(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error)
 }
@@ -14,6 +14,8 @@
 
 package kmstypes
 
+import "time"
+
 // Store the key information storage interface
 type Store interface {
 	// PluginKind is key store type
@@ -28,7 +30,15 @@ type Store interface {
 	// ListByKind use plugin type to list CMKs
 	ListKeysByKind(kind PluginKind) ([]string, error)
 
-	// DeleteByKeyID use keyID to delete CMK
+	// UpdateKey update CMK metadata, such as state, rotation policy and deletion time.
+	// Key versions are not changed, use RotateKeyVersion instead.
+	UpdateKey(info KeyInfo) error
+
+	// ClaimKeyRotation set next rotation time of CMK to newNextRotationAt only if it's still expectedNextRotationAt,
+	// return false if the rotation is already claimed by others, so only one replica rotates the key.
+	ClaimKeyRotation(keyID string, expectedNextRotationAt, newNextRotationAt time.Time) (bool, error)
+
+	// DeleteByKeyID use keyID to delete CMK and all its key versions
 	DeleteByKeyID(keyID string) error
 
 	// GetKeyVersion use keyID and keyVersionID to find keyVersion
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+// RunKeyLifecycleWorker execute key lifecycle periodically until ctx done
+func (m *Manager) RunKeyLifecycleWorker(ctx context.Context, storeKind kmstypes.StoreKind, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := m.ExecuteKeyLifecycle(ctx, storeKind, time.Now()); err != nil {
+			logrus.Errorf("[alert] failed to execute kms key lifecycle, err: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExecuteKeyLifecycle rotate keys whose rotation time is due, and delete keys whose deletion waiting period is over
+func (m *Manager) ExecuteKeyLifecycle(ctx context.Context, storeKind kmstypes.StoreKind, now time.Time) error {
+	store, err := m.GetStore(storeKind)
+	if err != nil {
+		return err
+	}
+	var failedKeyIDs []string
+	for pluginKind := range m.plugins {
+		plugin, err := m.GetPlugin(pluginKind, storeKind)
+		if err != nil {
+			return err
+		}
+		keyIDs, err := store.ListKeysByKind(pluginKind)
+		if err != nil {
+			return fmt.Errorf("failed to list keys, plugin: %s, err: %v", pluginKind, err)
+		}
+		for _, keyID := range keyIDs {
+			if err := executeKeyLifecycle(ctx, plugin, store, keyID, now); err != nil {
+				logrus.Errorf("failed to execute kms key lifecycle, keyID: %s, err: %v", keyID, err)
+				failedKeyIDs = append(failedKeyIDs, keyID)
+			}
+		}
+	}
+	if len(failedKeyIDs) > 0 {
+		return fmt.Errorf("failed keys: %v", failedKeyIDs)
+	}
+	return nil
+}
+
+func executeKeyLifecycle(ctx context.Context, plugin kmstypes.Plugin, store kmstypes.Store, keyID string, now time.Time) error {
+	keyInfo, err := store.GetKey(keyID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case kmstypes.IsDeletionDue(keyInfo, now):
+		if err := store.DeleteByKeyID(keyID); err != nil {
+			return fmt.Errorf("failed to delete key, err: %v", err)
+		}
+		logrus.Infof("kms key deleted after waiting period, keyID: %s", keyID)
+	case kmstypes.IsRotationDue(keyInfo, now):
+		// worker runs on every replica, claim the rotation by compare-and-set next rotation time firstly
+		expectedNextRotationAt := *keyInfo.GetNextRotationAt()
+		// stores may keep time in seconds only, use the same precision so that the rollback below matches
+		claimedNextRotationAt := now.Add(keyInfo.GetRotationInterval()).Truncate(time.Second)
+		claimed, err := store.ClaimKeyRotation(keyID, expectedNextRotationAt, claimedNextRotationAt)
+		if err != nil {
+			return fmt.Errorf("failed to claim key rotation, err: %v", err)
+		}
+		if !claimed {
+			logrus.Infof("kms key rotation is claimed by others, skip, keyID: %s", keyID)
+			return nil
+		}
+		resp, err := plugin.RotateKeyVersion(ctx, &kmstypes.RotateKeyVersionRequest{KeyID: keyID})
+		if err != nil {
+			// give back the claim, so the key will be rotated in next loop
+			if _, rollbackErr := store.ClaimKeyRotation(keyID, claimedNextRotationAt, expectedNextRotationAt); rollbackErr != nil {
+				logrus.Errorf("failed to rollback key rotation claim, keyID: %s, err: %v", keyID, rollbackErr)
+			}
+			return fmt.Errorf("failed to rotate key, err: %v", err)
+		}
+		logrus.Infof("kms key rotated automatically, keyID: %s, primaryKeyVersionID: %s",
+			keyID, resp.KeyMetadata.PrimaryKeyVersionID)
+	}
+	return nil
+}
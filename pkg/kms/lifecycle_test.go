@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+type claimStore struct {
+	kmstypes.Store
+	key *kmstypes.Key
+}
+
+func (s *claimStore) GetKey(keyID string) (kmstypes.KeyInfo, error) {
+	cp := *s.key
+	return &cp, nil
+}
+
+func (s *claimStore) ClaimKeyRotation(keyID string, expectedNextRotationAt, newNextRotationAt time.Time) (bool, error) {
+	if s.key.NextRotationAt == nil || !s.key.NextRotationAt.Equal(expectedNextRotationAt) {
+		return false, nil
+	}
+	s.key.NextRotationAt = &newNextRotationAt
+	return true, nil
+}
+
+type countPlugin struct {
+	kmstypes.Plugin
+	rotated int
+	err     error
+}
+
+func (p *countPlugin) RotateKeyVersion(ctx context.Context, req *kmstypes.RotateKeyVersionRequest) (*kmstypes.RotateKeyVersionResponse, error) {
+	p.rotated++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &kmstypes.RotateKeyVersionResponse{}, nil
+}
+
+func Test_executeKeyLifecycle_rotateOnce(t *testing.T) {
+	now := time.Now()
+	due := now.Add(-time.Minute)
+	store := &claimStore{key: &kmstypes.Key{
+		KeyID:            "key",
+		KeyState:         kmstypes.KeyStateEnabled,
+		RotationInterval: time.Hour,
+		NextRotationAt:   &due,
+	}}
+	plugin := &countPlugin{}
+
+	// both replicas read the key before any rotation
+	keyInfo, err := store.GetKey("key")
+	assert.NoError(t, err)
+	assert.True(t, kmstypes.IsRotationDue(keyInfo, now))
+
+	assert.NoError(t, executeKeyLifecycle(context.Background(), plugin, store, "key", now))
+	// the other replica claims with the stale next rotation time and skips
+	claimed, err := store.ClaimKeyRotation("key", due, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, executeKeyLifecycle(context.Background(), plugin, store, "key", now))
+	assert.Equal(t, 1, plugin.rotated)
+	assert.True(t, store.key.NextRotationAt.Equal(now.Add(time.Hour).Truncate(time.Second)))
+}
+
+func Test_executeKeyLifecycle_rollbackClaim(t *testing.T) {
+	now := time.Date(2026, 10, 18, 8, 0, 0, 123456789, time.UTC)
+	due := now.Add(-time.Minute).Truncate(time.Second)
+	store := &claimStore{key: &kmstypes.Key{
+		KeyID:            "key",
+		KeyState:         kmstypes.KeyStateEnabled,
+		RotationInterval: time.Hour,
+		NextRotationAt:   &due,
+	}}
+	plugin := &countPlugin{err: fmt.Errorf("plugin unavailable")}
+
+	assert.Error(t, executeKeyLifecycle(context.Background(), plugin, store, "key", now))
+	// claim is given back, key is rotated in next loop
+	assert.True(t, store.key.NextRotationAt.Equal(due))
+	plugin.err = nil
+	assert.NoError(t, executeKeyLifecycle(context.Background(), plugin, store, "key", now))
+	assert.Equal(t, 2, plugin.rotated)
+}
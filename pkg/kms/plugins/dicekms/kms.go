@@ -16,10 +16,12 @@ package dicekms
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -69,8 +71,8 @@ func (d *Dice) CreateKey(ctx context.Context, req *kmstypes.CreateKeyRequest) (*
 	}
 
 	// key usage
-	if req.KeyUsage != kmstypes.KeyUsage_ENCRYPT_DECRYPT {
-		return nil, fmt.Errorf("not supported key usage: %s", req.KeyUsage)
+	if !req.CustomerMasterKeySpec.SupportKeyUsage(req.KeyUsage) {
+		return nil, fmt.Errorf("not supported key usage: %s for key spec: %s", req.KeyUsage, req.CustomerMasterKeySpec)
 	}
 
 	// write key to store
@@ -90,6 +92,10 @@ func (d *Dice) CreateKey(ctx context.Context, req *kmstypes.CreateKeyRequest) (*
 		if err := d.fillRsaKeyVersionByBits(&primaryKeyVersion, 2048); err != nil {
 			return nil, err
 		}
+	case kmstypes.CustomerMasterKeySpec_ASYMMETRIC_EC_P256:
+		if err := d.fillEcdsaP256KeyVersion(&primaryKeyVersion); err != nil {
+			return nil, err
+		}
 	case kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT:
 		symmetricKeyBytes, err := kmscrypto.GenerateAes256Key()
 		if err != nil {
@@ -106,6 +112,13 @@ func (d *Dice) CreateKey(ctx context.Context, req *kmstypes.CreateKeyRequest) (*
 		KeyUsage:          req.KeyUsage,
 		KeyState:          kmstypes.KeyStateEnabled,
 		Description:       req.Description,
+		OrgID:             req.OrgID,
+		ProjectID:         req.ProjectID,
+	}
+	if req.RotationIntervalDays > 0 {
+		key.RotationInterval = time.Duration(req.RotationIntervalDays) * kmstypes.Day
+		nextRotationAt := time.Now().Add(key.RotationInterval)
+		key.NextRotationAt = &nextRotationAt
 	}
 	err := d.store.CreateKey(&key)
 	if err != nil {
@@ -158,8 +171,28 @@ func (d *Dice) Encrypt(ctx context.Context, req *kmstypes.EncryptRequest) (*kmst
 	if err != nil {
 		return nil, err
 	}
+	if err := kmstypes.CheckKeyEnabled(keyInfo); err != nil {
+		return nil, err
+	}
+	if keyInfo.GetKeyUsage() != kmstypes.KeyUsage_ENCRYPT_DECRYPT {
+		return nil, fmt.Errorf("key usage is %s, cannot be used to encrypt", keyInfo.GetKeyUsage())
+	}
 
 	// encrypt
+	wrappedCiphertextBase64, err := d.encrypt(keyInfo, plaintextBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmstypes.EncryptResponse{
+		KeyID:            req.KeyID,
+		CiphertextBase64: wrappedCiphertextBase64,
+	}, nil
+
+}
+
+// encrypt plaintext by primary key version, and prefix append keyVersionID into ciphertext
+func (d *Dice) encrypt(keyInfo kmstypes.KeyInfo, plaintextBytes []byte) (string, error) {
 	var ciphertext []byte
 	switch keyInfo.GetKeySpec() {
 	case kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_4096, kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_2048,
@@ -173,7 +206,7 @@ func (d *Dice) Encrypt(ctx context.Context, req *kmstypes.EncryptRequest) (*kmst
 		})
 		encryptedV, err := rsaCrypt.Encrypt(string(plaintextBytes), kmscrypto.String)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		ciphertext = []byte(encryptedV)
 	case kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT:
@@ -182,30 +215,24 @@ func (d *Dice) Encrypt(ctx context.Context, req *kmstypes.EncryptRequest) (*kmst
 		}
 		additionalDataJSON, err := json.Marshal(&additionalData)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		symmetricKeyBytes, err := base64.StdEncoding.DecodeString(keyInfo.GetPrimaryKeyVersion().GetSymmetricKeyBase64())
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		ciphertext, err = kmscrypto.AesGcmEncrypt(symmetricKeyBytes, plaintextBytes, additionalDataJSON)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 	}
 	// prefix append keyVersionID into ciphertext
 	keyVersionIDPrefix, err := kmscrypto.PrefixAppend000Length([]byte(keyInfo.GetPrimaryKeyVersion().GetVersionID()))
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	wrappedCiphertextBytes := append(keyVersionIDPrefix, ciphertext...)
-	wrappedCiphertextBase64 := base64.StdEncoding.EncodeToString(wrappedCiphertextBytes)
-
-	return &kmstypes.EncryptResponse{
-		KeyID:            req.KeyID,
-		CiphertextBase64: wrappedCiphertextBase64,
-	}, nil
-
+	return base64.StdEncoding.EncodeToString(wrappedCiphertextBytes), nil
 }
 
 func (d *Dice) Decrypt(ctx context.Context, req *kmstypes.DecryptRequest) (resp *kmstypes.DecryptResponse, err error) {
@@ -216,6 +243,9 @@ func (d *Dice) Decrypt(ctx context.Context, req *kmstypes.DecryptRequest) (resp
 	if kerr != nil {
 		return nil, kerr
 	}
+	if kerr := kmstypes.CheckKeyEnabled(keyInfo); kerr != nil {
+		return nil, kerr
+	}
 
 	defer func() {
 		// not expose concrete error to frontend, log err and return `broken ciphertext`
@@ -226,22 +256,35 @@ func (d *Dice) Decrypt(ctx context.Context, req *kmstypes.DecryptRequest) (resp
 		}
 	}()
 
-	ciphertextBytes, cerr := base64.StdEncoding.DecodeString(req.CiphertextBase64)
-	if cerr != nil {
+	plaintextBytes, _, err := d.decrypt(keyInfo, req.CiphertextBase64)
+	if err != nil {
 		return nil, err
 	}
+	plaintextBase64 := base64.StdEncoding.EncodeToString(plaintextBytes)
+
+	resp = &kmstypes.DecryptResponse{PlaintextBase64: plaintextBase64}
+
+	return resp, nil
+}
+
+// decrypt unwrap keyVersionID from ciphertext, and decrypt ciphertext by that key version
+func (d *Dice) decrypt(keyInfo kmstypes.KeyInfo, ciphertextBase64 string) ([]byte, string, error) {
+	ciphertextBytes, err := base64.StdEncoding.DecodeString(ciphertextBase64)
+	if err != nil {
+		return nil, "", err
+	}
 
 	// unwrap ciphertext
 	keyVersionIDBytes, ciphertext, err := kmscrypto.PrefixUnAppend000Length(ciphertextBytes)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	keyVersionID := string(keyVersionIDBytes)
 
 	// get keyVersionID info
 	keyVersionInfo, err := d.store.GetKeyVersion(keyInfo.GetKeyID(), keyVersionID)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// decrypt ciphertext
@@ -250,39 +293,34 @@ func (d *Dice) Decrypt(ctx context.Context, req *kmstypes.DecryptRequest) (resp
 	case kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_4096, kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_2048,
 		kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_3072:
 		rsaCrypt := kmscrypto.NewRSACrypt(kmscrypto.RSASecret{
-			PublicKey:          keyInfo.GetPrimaryKeyVersion().GetPublicKeyBase64(),
+			PublicKey:          keyVersionInfo.GetPublicKeyBase64(),
 			PublicKeyDataType:  kmscrypto.Base64,
-			PrivateKey:         keyInfo.GetPrimaryKeyVersion().GetPrivateKeyBase64(),
+			PrivateKey:         keyVersionInfo.GetPrivateKeyBase64(),
 			PrivateKeyDataType: kmscrypto.Base64,
 			PrivateKeyType:     kmscrypto.PKCS1,
 		})
 		plaintext, err := rsaCrypt.Decrypt(string(ciphertext), kmscrypto.String)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		plaintextBytes = []byte(plaintext)
 	case kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT:
 		symmetricKey, err := base64.StdEncoding.DecodeString(keyVersionInfo.GetSymmetricKeyBase64())
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		additionalDataJSON, err := json.Marshal(&additionalData{KeyID: keyInfo.GetKeyID()})
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		plaintextBytes, err = kmscrypto.AesGcmDecrypt(symmetricKey, ciphertext, additionalDataJSON)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
+	default:
+		return nil, "", fmt.Errorf("key spec %s cannot be used to decrypt", keyInfo.GetKeySpec())
 	}
-	if err != nil {
-		return nil, err
-	}
-	plaintextBase64 := base64.StdEncoding.EncodeToString(plaintextBytes)
-
-	resp = &kmstypes.DecryptResponse{PlaintextBase64: plaintextBase64}
-
-	return resp, nil
+	return plaintextBytes, keyVersionID, nil
 }
 
 func (d *Dice) GenerateDataKey(ctx context.Context, req *kmstypes.GenerateDataKeyRequest) (*kmstypes.GenerateDataKeyResponse, error) {
@@ -318,11 +356,60 @@ func (d *Dice) GenerateDataKey(ctx context.Context, req *kmstypes.GenerateDataKe
 	return &resp, nil
 }
 
+func (d *Dice) ReEncrypt(ctx context.Context, req *kmstypes.ReEncryptRequest) (*kmstypes.ReEncryptResponse, error) {
+	// source key
+	sourceKeyInfo, err := d.store.GetKey(req.SourceKeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := kmstypes.CheckKeyEnabled(sourceKeyInfo); err != nil {
+		return nil, err
+	}
+	// destination key
+	destinationKeyID := req.DestinationKeyID
+	if destinationKeyID == "" {
+		destinationKeyID = req.SourceKeyID
+	}
+	destinationKeyInfo, err := d.store.GetKey(destinationKeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := kmstypes.CheckKeyEnabled(destinationKeyInfo); err != nil {
+		return nil, err
+	}
+	if destinationKeyInfo.GetKeyUsage() != kmstypes.KeyUsage_ENCRYPT_DECRYPT {
+		return nil, fmt.Errorf("key usage is %s, cannot be used to encrypt", destinationKeyInfo.GetKeyUsage())
+	}
+
+	// decrypt and encrypt inside kms, plaintext is never returned
+	plaintextBytes, sourceKeyVersionID, err := d.decrypt(sourceKeyInfo, req.CiphertextBase64)
+	if err != nil {
+		// not expose concrete error to frontend, log err and return `broken ciphertext`
+		log.WithTraceID(ctx).Errorf("parse ciphertext failed, err: %v", err)
+		return nil, fmt.Errorf("broken ciphertext")
+	}
+	ciphertextBase64, err := d.encrypt(destinationKeyInfo, plaintextBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmstypes.ReEncryptResponse{
+		SourceKeyID:        sourceKeyInfo.GetKeyID(),
+		SourceKeyVersionID: sourceKeyVersionID,
+		KeyID:              destinationKeyInfo.GetKeyID(),
+		KeyVersionID:       destinationKeyInfo.GetPrimaryKeyVersion().GetVersionID(),
+		CiphertextBase64:   ciphertextBase64,
+	}, nil
+}
+
 func (d *Dice) RotateKeyVersion(ctx context.Context, req *kmstypes.RotateKeyVersionRequest) (*kmstypes.RotateKeyVersionResponse, error) {
 	keyInfo, err := d.store.GetKey(req.KeyID)
 	if err != nil {
 		return nil, err
 	}
+	if err := kmstypes.CheckKeyEnabled(keyInfo); err != nil {
+		return nil, err
+	}
 	newKeyVersion := kmstypes.KeyVersion{
 		VersionID: uuid.UUID(),
 	}
@@ -339,6 +426,10 @@ func (d *Dice) RotateKeyVersion(ctx context.Context, req *kmstypes.RotateKeyVers
 		if err := d.fillRsaKeyVersionByBits(&newKeyVersion, 2048); err != nil {
 			return nil, err
 		}
+	case kmstypes.CustomerMasterKeySpec_ASYMMETRIC_EC_P256:
+		if err := d.fillEcdsaP256KeyVersion(&newKeyVersion); err != nil {
+			return nil, err
+		}
 	case kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT:
 		symmetricKey, err := kmscrypto.GenerateAes256Key()
 		if err != nil {
@@ -358,6 +449,15 @@ func (d *Dice) RotateKeyVersion(ctx context.Context, req *kmstypes.RotateKeyVers
 		return nil, err
 	}
 
+	// both manual and automatic rotation reset the next rotation time
+	if interval := keyInfo.GetRotationInterval(); interval > 0 {
+		nextRotationAt := time.Now().Add(interval)
+		keyInfo.SetNextRotationAt(&nextRotationAt)
+		if err := d.store.UpdateKey(keyInfo); err != nil {
+			return nil, fmt.Errorf("failed to update next rotation time, err: %v", err)
+		}
+	}
+
 	resp := kmstypes.RotateKeyVersionResponse{KeyMetadata: kmstypes.GetKeyMetadata(keyInfo)}
 	return &resp, nil
 }
@@ -374,10 +474,217 @@ func (d *Dice) fillRsaKeyVersionByBits(keyVersion *kmstypes.KeyVersion, bits int
 	return nil
 }
 
+func (d *Dice) fillEcdsaP256KeyVersion(keyVersion *kmstypes.KeyVersion) error {
+	publicKey, privateKey, err := kmscrypto.GenEcdsaP256Key()
+	if err != nil {
+		return fmt.Errorf("failed to create ecdsa key pair, err: %v", err)
+	}
+	keyVersion.PublicKeyBase64 = base64.StdEncoding.EncodeToString(publicKey)
+	keyVersion.PrivateKeyBase64 = base64.StdEncoding.EncodeToString(privateKey)
+	return nil
+}
+
+// getAsymmetricKeyVersion return the specified key version, or primary key version if versionID is empty
+func (d *Dice) getAsymmetricKeyVersion(keyInfo kmstypes.KeyInfo, versionID string) (kmstypes.KeyVersionInfo, error) {
+	spec := keyInfo.GetKeySpec()
+	if !spec.IsRSA() && spec != kmstypes.CustomerMasterKeySpec_ASYMMETRIC_EC_P256 {
+		return nil, fmt.Errorf("key spec %s is not asymmetric", spec)
+	}
+	if versionID == "" || versionID == keyInfo.GetPrimaryKeyVersion().GetVersionID() {
+		return keyInfo.GetPrimaryKeyVersion(), nil
+	}
+	return d.store.GetKeyVersion(keyInfo.GetKeyID(), versionID)
+}
+
 func (d *Dice) GetPublicKey(ctx context.Context, req *kmstypes.GetPublicKeyRequest) (*kmstypes.PublicKey, error) {
-	panic("implement me")
+	keyInfo, err := d.store.GetKey(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := kmstypes.CheckKeyEnabled(keyInfo); err != nil {
+		return nil, err
+	}
+	keyVersion, err := d.getAsymmetricKeyVersion(keyInfo, req.KeyVersionID)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyPem, err := base64.StdEncoding.DecodeString(keyVersion.GetPublicKeyBase64())
+	if err != nil {
+		return nil, err
+	}
+	algorithm := kmstypes.EncryptionAlgorithm_RSAES_PKCS1_V1_5
+	if keyInfo.GetKeyUsage() == kmstypes.KeyUsage_SIGN_VERIFY {
+		spec := keyInfo.GetKeySpec()
+		algorithm = string(spec.DefaultSigningAlgorithm())
+	}
+	return &kmstypes.PublicKey{
+		KeyID:        keyInfo.GetKeyID(),
+		KeyVersionID: keyVersion.GetVersionID(),
+		Pem:          string(publicKeyPem),
+		Algorithm:    algorithm,
+	}, nil
+}
+
+func (d *Dice) AsymmetricDecrypt(ctx context.Context, req *kmstypes.AsymmetricDecryptRequest) (resp *kmstypes.AsymmetricDecryptResponse, err error) {
+	keyInfo, kerr := d.store.GetKey(req.KeyID)
+	if kerr != nil {
+		return nil, kerr
+	}
+	if kerr := kmstypes.CheckKeyEnabled(keyInfo); kerr != nil {
+		return nil, kerr
+	}
+	spec := keyInfo.GetKeySpec()
+	if !spec.IsRSA() || keyInfo.GetKeyUsage() != kmstypes.KeyUsage_ENCRYPT_DECRYPT {
+		return nil, fmt.Errorf("key spec %s with usage %s cannot be used to decrypt asymmetrically", spec, keyInfo.GetKeyUsage())
+	}
+	keyVersion, kerr := d.getAsymmetricKeyVersion(keyInfo, req.KeyVersionID)
+	if kerr != nil {
+		return nil, kerr
+	}
+
+	defer func() {
+		// not expose concrete error to frontend, log err and return `broken ciphertext`
+		if err != nil {
+			log.WithTraceID(ctx).Errorf("asymmetric decrypt failed, err: %v", err)
+			resp = nil
+			err = fmt.Errorf("broken ciphertext")
+		}
+	}()
+
+	rsaCrypt := kmscrypto.NewRSACrypt(kmscrypto.RSASecret{
+		PrivateKey:         keyVersion.GetPrivateKeyBase64(),
+		PrivateKeyDataType: kmscrypto.Base64,
+		PrivateKeyType:     kmscrypto.PKCS1,
+	})
+	plaintext, err := rsaCrypt.Decrypt(req.CiphertextBase64, kmscrypto.Base64)
+	if err != nil {
+		return nil, err
+	}
+	return &kmstypes.AsymmetricDecryptResponse{
+		KeyID:           keyInfo.GetKeyID(),
+		KeyVersionID:    keyVersion.GetVersionID(),
+		PlaintextBase64: base64.StdEncoding.EncodeToString([]byte(plaintext)),
+	}, nil
+}
+
+func (d *Dice) Sign(ctx context.Context, req *kmstypes.SignRequest) (*kmstypes.SignResponse, error) {
+	keyInfo, err := d.store.GetKey(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := kmstypes.CheckKeyEnabled(keyInfo); err != nil {
+		return nil, err
+	}
+	algorithm, err := checkSigningKey(keyInfo, req.SigningAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	keyVersion := keyInfo.GetPrimaryKeyVersion()
+	digest, err := messageDigest(req.MessageBase64, req.MessageType)
+	if err != nil {
+		return nil, err
+	}
+	privateKeyPem, err := base64.StdEncoding.DecodeString(keyVersion.GetPrivateKeyBase64())
+	if err != nil {
+		return nil, err
+	}
+
+	var signature []byte
+	switch algorithm {
+	case kmstypes.SigningAlgorithm_RSASSA_PSS_SHA_256:
+		signature, err = kmscrypto.SignRsaPss(privateKeyPem, digest)
+	case kmstypes.SigningAlgorithm_ECDSA_SHA_256:
+		signature, err = kmscrypto.SignEcdsa(privateKeyPem, digest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign, err: %v", err)
+	}
+
+	return &kmstypes.SignResponse{
+		KeyID:            keyInfo.GetKeyID(),
+		KeyVersionID:     keyVersion.GetVersionID(),
+		SigningAlgorithm: algorithm,
+		SignatureBase64:  base64.StdEncoding.EncodeToString(signature),
+	}, nil
 }
 
-func (d *Dice) AsymmetricDecrypt(ctx context.Context, req *kmstypes.AsymmetricDecryptRequest) (*kmstypes.AsymmetricDecryptResponse, error) {
-	panic("implement me")
+func (d *Dice) Verify(ctx context.Context, req *kmstypes.VerifyRequest) (*kmstypes.VerifyResponse, error) {
+	keyInfo, err := d.store.GetKey(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := kmstypes.CheckKeyEnabled(keyInfo); err != nil {
+		return nil, err
+	}
+	algorithm, err := checkSigningKey(keyInfo, req.SigningAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	keyVersion, err := d.getAsymmetricKeyVersion(keyInfo, req.KeyVersionID)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := messageDigest(req.MessageBase64, req.MessageType)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyPem, err := base64.StdEncoding.DecodeString(keyVersion.GetPublicKeyBase64())
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.StdEncoding.DecodeString(req.SignatureBase64)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifyErr error
+	switch algorithm {
+	case kmstypes.SigningAlgorithm_RSASSA_PSS_SHA_256:
+		verifyErr = kmscrypto.VerifyRsaPss(publicKeyPem, digest, signature)
+	case kmstypes.SigningAlgorithm_ECDSA_SHA_256:
+		verifyErr = kmscrypto.VerifyEcdsa(publicKeyPem, digest, signature)
+	}
+	if verifyErr != nil {
+		log.WithTraceID(ctx).Warnf("signature is invalid, keyID: %s, keyVersionID: %s, err: %v",
+			keyInfo.GetKeyID(), keyVersion.GetVersionID(), verifyErr)
+	}
+
+	return &kmstypes.VerifyResponse{
+		KeyID:            keyInfo.GetKeyID(),
+		KeyVersionID:     keyVersion.GetVersionID(),
+		SigningAlgorithm: algorithm,
+		SignatureValid:   verifyErr == nil,
+	}, nil
+}
+
+// checkSigningKey check key can be used to sign, and return the signing algorithm
+func checkSigningKey(keyInfo kmstypes.KeyInfo, algorithm kmstypes.SigningAlgorithm) (kmstypes.SigningAlgorithm, error) {
+	if keyInfo.GetKeyUsage() != kmstypes.KeyUsage_SIGN_VERIFY {
+		return "", fmt.Errorf("key usage is %s, expect: %s", keyInfo.GetKeyUsage(), kmstypes.KeyUsage_SIGN_VERIFY)
+	}
+	spec := keyInfo.GetKeySpec()
+	expected := spec.DefaultSigningAlgorithm()
+	if expected == "" {
+		return "", fmt.Errorf("key spec %s cannot be used to sign", spec)
+	}
+	if algorithm != "" && algorithm != expected {
+		return "", fmt.Errorf("signing algorithm %s is not supported by key spec %s, expect: %s", algorithm, spec, expected)
+	}
+	return expected, nil
+}
+
+// messageDigest return SHA-256 digest of message
+func messageDigest(messageBase64 string, messageType kmstypes.MessageType) ([]byte, error) {
+	message, err := base64.StdEncoding.DecodeString(messageBase64)
+	if err != nil {
+		return nil, err
+	}
+	if messageType == kmstypes.MessageType_DIGEST {
+		if len(message) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 digest length: %d", len(message))
+		}
+		return message, nil
+	}
+	sum := sha256.Sum256(message)
+	return sum[:], nil
 }
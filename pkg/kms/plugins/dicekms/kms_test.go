@@ -0,0 +1,323 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dicekms
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+// memStore is an in-memory kmstypes.Store for test
+type memStore struct {
+	keys     map[string]*kmstypes.Key
+	versions map[string]map[string]kmstypes.KeyVersion
+}
+
+func newMemStore() *memStore {
+	return &memStore{keys: map[string]*kmstypes.Key{}, versions: map[string]map[string]kmstypes.KeyVersion{}}
+}
+
+func (s *memStore) GetKind() kmstypes.StoreKind { return "MEMORY" }
+
+func (s *memStore) CreateKey(info kmstypes.KeyInfo) error {
+	key := *info.(*kmstypes.Key)
+	now := time.Now()
+	key.PrimaryKeyVersion.CreatedAt = &now
+	s.keys[key.KeyID] = &key
+	s.versions[key.KeyID] = map[string]kmstypes.KeyVersion{key.PrimaryKeyVersion.VersionID: key.PrimaryKeyVersion}
+	return nil
+}
+
+func (s *memStore) GetKey(keyID string) (kmstypes.KeyInfo, error) {
+	key, ok := s.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key not found")
+	}
+	cp := *key
+	return &cp, nil
+}
+
+func (s *memStore) ListKeysByKind(kind kmstypes.PluginKind) ([]string, error) {
+	var ids []string
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *memStore) UpdateKey(info kmstypes.KeyInfo) error {
+	key, ok := s.keys[info.GetKeyID()]
+	if !ok {
+		return fmt.Errorf("key not found")
+	}
+	key.KeyState = info.GetKeyState()
+	key.RotationInterval = info.GetRotationInterval()
+	key.NextRotationAt = info.GetNextRotationAt()
+	key.DeletionAt = info.GetDeletionAt()
+	return nil
+}
+
+func (s *memStore) ClaimKeyRotation(keyID string, expectedNextRotationAt, newNextRotationAt time.Time) (bool, error) {
+	key, ok := s.keys[keyID]
+	if !ok {
+		return false, fmt.Errorf("key not found")
+	}
+	if key.NextRotationAt == nil || !key.NextRotationAt.Equal(expectedNextRotationAt) {
+		return false, nil
+	}
+	key.NextRotationAt = &newNextRotationAt
+	return true, nil
+}
+
+func (s *memStore) DeleteByKeyID(keyID string) error {
+	delete(s.keys, keyID)
+	delete(s.versions, keyID)
+	return nil
+}
+
+func (s *memStore) GetKeyVersion(keyID, keyVersionID string) (kmstypes.KeyVersionInfo, error) {
+	version, ok := s.versions[keyID][keyVersionID]
+	if !ok {
+		return nil, fmt.Errorf("key version not found")
+	}
+	return &version, nil
+}
+
+func (s *memStore) RotateKeyVersion(keyID string, newKeyVersionInfo kmstypes.KeyVersionInfo) (kmstypes.KeyVersionInfo, error) {
+	version := *newKeyVersionInfo.(*kmstypes.KeyVersion)
+	now := time.Now()
+	version.CreatedAt = &now
+	s.versions[keyID][version.VersionID] = version
+	s.keys[keyID].PrimaryKeyVersion = version
+	return &version, nil
+}
+
+func newTestDice() *Dice {
+	d := &Dice{}
+	d.SetStore(newMemStore())
+	return d
+}
+
+func createKey(t *testing.T, d *Dice, spec kmstypes.CustomerMasterKeySpec, usage kmstypes.KeyUsage) string {
+	resp, err := d.CreateKey(context.Background(), &kmstypes.CreateKeyRequest{
+		PluginKind:            kmstypes.PluginKind_DICE_KMS,
+		CustomerMasterKeySpec: spec,
+		KeyUsage:              usage,
+	})
+	assert.NoError(t, err)
+	return resp.KeyMetadata.KeyID
+}
+
+func TestDice_CreateKey_KeyUsage(t *testing.T) {
+	d := newTestDice()
+	invalids := []struct {
+		spec  kmstypes.CustomerMasterKeySpec
+		usage kmstypes.KeyUsage
+	}{
+		{kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT, kmstypes.KeyUsage_SIGN_VERIFY},
+		{kmstypes.CustomerMasterKeySpec_ASYMMETRIC_EC_P256, kmstypes.KeyUsage_ENCRYPT_DECRYPT},
+	}
+	for _, invalid := range invalids {
+		_, err := d.CreateKey(context.Background(), &kmstypes.CreateKeyRequest{
+			PluginKind:            kmstypes.PluginKind_DICE_KMS,
+			CustomerMasterKeySpec: invalid.spec,
+			KeyUsage:              invalid.usage,
+		})
+		assert.Error(t, err)
+	}
+}
+
+func TestDice_CreateKey_Scope(t *testing.T) {
+	d := newTestDice()
+	ctx := context.Background()
+	resp, err := d.CreateKey(ctx, &kmstypes.CreateKeyRequest{
+		PluginKind:            kmstypes.PluginKind_DICE_KMS,
+		CustomerMasterKeySpec: kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT,
+		KeyUsage:              kmstypes.KeyUsage_ENCRYPT_DECRYPT,
+		OrgID:                 "1",
+		ProjectID:             "2",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", resp.KeyMetadata.OrgID)
+	assert.Equal(t, "2", resp.KeyMetadata.ProjectID)
+
+	describeResp, err := d.DescribeKey(ctx, &kmstypes.DescribeKeyRequest{KeyID: resp.KeyMetadata.KeyID})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", describeResp.KeyMetadata.OrgID)
+	assert.Equal(t, "2", describeResp.KeyMetadata.ProjectID)
+}
+
+func TestDice_GetPublicKeyAndAsymmetricDecrypt(t *testing.T) {
+	d := newTestDice()
+	ctx := context.Background()
+	keyID := createKey(t, d, kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_2048, kmstypes.KeyUsage_ENCRYPT_DECRYPT)
+
+	publicKey, err := d.GetPublicKey(ctx, &kmstypes.GetPublicKeyRequest{KeyID: keyID})
+	assert.NoError(t, err)
+	assert.Equal(t, kmstypes.EncryptionAlgorithm_RSAES_PKCS1_V1_5, publicKey.Algorithm)
+
+	// encrypt locally by public key
+	block, _ := pem.Decode([]byte(publicKey.Pem))
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	assert.NoError(t, err)
+	ciphertext, err := rsa.EncryptPKCS1v15(rand.Reader, pub.(*rsa.PublicKey), []byte("hello"))
+	assert.NoError(t, err)
+
+	// rotate, old version still can decrypt
+	_, err = d.RotateKeyVersion(ctx, &kmstypes.RotateKeyVersionRequest{KeyID: keyID})
+	assert.NoError(t, err)
+
+	decryptResp, err := d.AsymmetricDecrypt(ctx, &kmstypes.AsymmetricDecryptRequest{
+		KeyID:            keyID,
+		KeyVersionID:     publicKey.KeyVersionID,
+		CiphertextBase64: base64.StdEncoding.EncodeToString(ciphertext),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hello")), decryptResp.PlaintextBase64)
+
+	// primary key version is rotated, cannot decrypt
+	_, err = d.AsymmetricDecrypt(ctx, &kmstypes.AsymmetricDecryptRequest{
+		KeyID:            keyID,
+		CiphertextBase64: base64.StdEncoding.EncodeToString(ciphertext),
+	})
+	assert.EqualError(t, err, "broken ciphertext")
+}
+
+func TestDice_SignVerify(t *testing.T) {
+	ctx := context.Background()
+	for _, spec := range []kmstypes.CustomerMasterKeySpec{
+		kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_2048,
+		kmstypes.CustomerMasterKeySpec_ASYMMETRIC_EC_P256,
+	} {
+		t.Run(string(spec), func(t *testing.T) {
+			d := newTestDice()
+			keyID := createKey(t, d, spec, kmstypes.KeyUsage_SIGN_VERIFY)
+			message := base64.StdEncoding.EncodeToString([]byte("release artifact"))
+
+			signResp, err := d.Sign(ctx, &kmstypes.SignRequest{KeyID: keyID, MessageBase64: message})
+			assert.NoError(t, err)
+			assert.Equal(t, spec.DefaultSigningAlgorithm(), signResp.SigningAlgorithm)
+
+			// verify by digest
+			digest := sha256.Sum256([]byte("release artifact"))
+			verifyResp, err := d.Verify(ctx, &kmstypes.VerifyRequest{
+				KeyID:           keyID,
+				MessageBase64:   base64.StdEncoding.EncodeToString(digest[:]),
+				MessageType:     kmstypes.MessageType_DIGEST,
+				SignatureBase64: signResp.SignatureBase64,
+			})
+			assert.NoError(t, err)
+			assert.True(t, verifyResp.SignatureValid)
+
+			// tampered message
+			verifyResp, err = d.Verify(ctx, &kmstypes.VerifyRequest{
+				KeyID:           keyID,
+				MessageBase64:   base64.StdEncoding.EncodeToString([]byte("tampered")),
+				SignatureBase64: signResp.SignatureBase64,
+			})
+			assert.NoError(t, err)
+			assert.False(t, verifyResp.SignatureValid)
+
+			// after rotation, old signature is verified by its key version
+			_, err = d.RotateKeyVersion(ctx, &kmstypes.RotateKeyVersionRequest{KeyID: keyID})
+			assert.NoError(t, err)
+			verifyResp, err = d.Verify(ctx, &kmstypes.VerifyRequest{
+				KeyID:           keyID,
+				KeyVersionID:    signResp.KeyVersionID,
+				MessageBase64:   message,
+				SignatureBase64: signResp.SignatureBase64,
+			})
+			assert.NoError(t, err)
+			assert.True(t, verifyResp.SignatureValid)
+
+			// key for encryption cannot sign
+			encKeyID := createKey(t, d, kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_2048, kmstypes.KeyUsage_ENCRYPT_DECRYPT)
+			_, err = d.Sign(ctx, &kmstypes.SignRequest{KeyID: encKeyID, MessageBase64: message})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDice_ReEncrypt(t *testing.T) {
+	ctx := context.Background()
+	for _, spec := range []kmstypes.CustomerMasterKeySpec{
+		kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT,
+		kmstypes.CustomerMasterKeySpec_ASYMMETRIC_RSA_2048,
+	} {
+		t.Run(string(spec), func(t *testing.T) {
+			d := newTestDice()
+			keyID := createKey(t, d, spec, kmstypes.KeyUsage_ENCRYPT_DECRYPT)
+			plaintext := base64.StdEncoding.EncodeToString([]byte("hello"))
+
+			encryptResp, err := d.Encrypt(ctx, &kmstypes.EncryptRequest{KeyID: keyID, PlaintextBase64: plaintext})
+			assert.NoError(t, err)
+			oldKeyInfo, err := d.store.GetKey(keyID)
+			assert.NoError(t, err)
+			oldVersionID := oldKeyInfo.GetPrimaryKeyVersion().GetVersionID()
+
+			rotateResp, err := d.RotateKeyVersion(ctx, &kmstypes.RotateKeyVersionRequest{KeyID: keyID})
+			assert.NoError(t, err)
+
+			// old ciphertext still can be decrypted by old key version
+			decryptResp, err := d.Decrypt(ctx, &kmstypes.DecryptRequest{KeyID: keyID, CiphertextBase64: encryptResp.CiphertextBase64})
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, decryptResp.PlaintextBase64)
+
+			// re-encrypt to primary key version
+			reEncryptResp, err := d.ReEncrypt(ctx, &kmstypes.ReEncryptRequest{
+				SourceKeyID:      keyID,
+				CiphertextBase64: encryptResp.CiphertextBase64,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, oldVersionID, reEncryptResp.SourceKeyVersionID)
+			assert.Equal(t, rotateResp.KeyMetadata.PrimaryKeyVersionID, reEncryptResp.KeyVersionID)
+			decryptResp, err = d.Decrypt(ctx, &kmstypes.DecryptRequest{KeyID: keyID, CiphertextBase64: reEncryptResp.CiphertextBase64})
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, decryptResp.PlaintextBase64)
+
+			// re-encrypt to another key
+			destKeyID := createKey(t, d, kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT, kmstypes.KeyUsage_ENCRYPT_DECRYPT)
+			reEncryptResp, err = d.ReEncrypt(ctx, &kmstypes.ReEncryptRequest{
+				SourceKeyID:      keyID,
+				DestinationKeyID: destKeyID,
+				CiphertextBase64: encryptResp.CiphertextBase64,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, destKeyID, reEncryptResp.KeyID)
+			decryptResp, err = d.Decrypt(ctx, &kmstypes.DecryptRequest{KeyID: destKeyID, CiphertextBase64: reEncryptResp.CiphertextBase64})
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, decryptResp.PlaintextBase64)
+
+			// broken ciphertext
+			_, err = d.ReEncrypt(ctx, &kmstypes.ReEncryptRequest{
+				SourceKeyID:      destKeyID,
+				CiphertextBase64: encryptResp.CiphertextBase64,
+			})
+			assert.EqualError(t, err, "broken ciphertext")
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dicekms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+func (d *Dice) EnableKey(ctx context.Context, req *kmstypes.EnableKeyRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	return d.updateKey(req.KeyID, func(keyInfo kmstypes.KeyInfo) error {
+		switch keyInfo.GetKeyState() {
+		case kmstypes.KeyStateEnabled, kmstypes.KeyStateDisabled:
+			keyInfo.SetKeyState(kmstypes.KeyStateEnabled)
+			return nil
+		case kmstypes.KeyStatePendingDeletion:
+			return fmt.Errorf("key is pending deletion, please cancel key deletion first")
+		default:
+			return fmt.Errorf("key is %s, cannot be enabled", keyInfo.GetKeyState())
+		}
+	})
+}
+
+func (d *Dice) DisableKey(ctx context.Context, req *kmstypes.DisableKeyRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	return d.updateKey(req.KeyID, func(keyInfo kmstypes.KeyInfo) error {
+		switch keyInfo.GetKeyState() {
+		case kmstypes.KeyStateEnabled, kmstypes.KeyStateDisabled:
+			keyInfo.SetKeyState(kmstypes.KeyStateDisabled)
+			return nil
+		default:
+			return fmt.Errorf("key is %s, cannot be disabled", keyInfo.GetKeyState())
+		}
+	})
+}
+
+func (d *Dice) ScheduleKeyDeletion(ctx context.Context, req *kmstypes.ScheduleKeyDeletionRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	pendingWindowInDays := req.PendingWindowInDays
+	if pendingWindowInDays == 0 {
+		pendingWindowInDays = kmstypes.DefaultPendingWindowInDays
+	}
+	return d.updateKey(req.KeyID, func(keyInfo kmstypes.KeyInfo) error {
+		switch keyInfo.GetKeyState() {
+		case kmstypes.KeyStateEnabled, kmstypes.KeyStateDisabled:
+			deletionAt := time.Now().Add(time.Duration(pendingWindowInDays) * kmstypes.Day)
+			keyInfo.SetKeyState(kmstypes.KeyStatePendingDeletion)
+			keyInfo.SetDeletionAt(&deletionAt)
+			return nil
+		default:
+			return fmt.Errorf("key is %s, cannot schedule deletion", keyInfo.GetKeyState())
+		}
+	})
+}
+
+func (d *Dice) CancelKeyDeletion(ctx context.Context, req *kmstypes.CancelKeyDeletionRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	return d.updateKey(req.KeyID, func(keyInfo kmstypes.KeyInfo) error {
+		if keyInfo.GetKeyState() != kmstypes.KeyStatePendingDeletion {
+			return fmt.Errorf("key is %s, not pending deletion", keyInfo.GetKeyState())
+		}
+		// key is disabled after cancel deletion, enable it explicitly if needed
+		keyInfo.SetKeyState(kmstypes.KeyStateDisabled)
+		keyInfo.SetDeletionAt(nil)
+		return nil
+	})
+}
+
+func (d *Dice) UpdateRotationPolicy(ctx context.Context, req *kmstypes.UpdateRotationPolicyRequest) (*kmstypes.KeyLifecycleResponse, error) {
+	return d.updateKey(req.KeyID, func(keyInfo kmstypes.KeyInfo) error {
+		if keyInfo.GetKeyState() == kmstypes.KeyStatePendingDeletion {
+			return fmt.Errorf("key is pending deletion, cannot update rotation policy")
+		}
+		if req.RotationIntervalDays == 0 {
+			keyInfo.SetRotationInterval(0)
+			keyInfo.SetNextRotationAt(nil)
+			return nil
+		}
+		interval := time.Duration(req.RotationIntervalDays) * kmstypes.Day
+		// next rotation is calculated from the creation of primary key version,
+		// so key which has not been rotated for longer than the new interval will be rotated soon
+		lastRotatedAt := time.Now()
+		if createdAt := keyInfo.GetPrimaryKeyVersion().GetCreatedAt(); createdAt != nil && !createdAt.IsZero() {
+			lastRotatedAt = *createdAt
+		}
+		nextRotationAt := lastRotatedAt.Add(interval)
+		keyInfo.SetRotationInterval(interval)
+		keyInfo.SetNextRotationAt(&nextRotationAt)
+		return nil
+	})
+}
+
+func (d *Dice) updateKey(keyID string, updateFunc func(keyInfo kmstypes.KeyInfo) error) (*kmstypes.KeyLifecycleResponse, error) {
+	keyInfo, err := d.store.GetKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateFunc(keyInfo); err != nil {
+		return nil, err
+	}
+	if err := d.store.UpdateKey(keyInfo); err != nil {
+		return nil, fmt.Errorf("failed to update key in store, err: %v", err)
+	}
+	return &kmstypes.KeyLifecycleResponse{KeyMetadata: kmstypes.GetKeyMetadata(keyInfo)}, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dicekms
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+func TestDice_KeyState(t *testing.T) {
+	d := newTestDice()
+	ctx := context.Background()
+	keyID := createKey(t, d, kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT, kmstypes.KeyUsage_ENCRYPT_DECRYPT)
+	plaintext := base64.StdEncoding.EncodeToString([]byte("hello"))
+
+	encryptResp, err := d.Encrypt(ctx, &kmstypes.EncryptRequest{KeyID: keyID, PlaintextBase64: plaintext})
+	assert.NoError(t, err)
+
+	// disabled key cannot be used
+	resp, err := d.DisableKey(ctx, &kmstypes.DisableKeyRequest{KeyID: keyID})
+	assert.NoError(t, err)
+	assert.Equal(t, kmstypes.KeyStateDisabled, resp.KeyMetadata.KeyState)
+	_, err = d.Encrypt(ctx, &kmstypes.EncryptRequest{KeyID: keyID, PlaintextBase64: plaintext})
+	assert.Error(t, err)
+	_, err = d.Decrypt(ctx, &kmstypes.DecryptRequest{KeyID: keyID, CiphertextBase64: encryptResp.CiphertextBase64})
+	assert.Error(t, err)
+	_, err = d.RotateKeyVersion(ctx, &kmstypes.RotateKeyVersionRequest{KeyID: keyID})
+	assert.Error(t, err)
+
+	// enable again
+	resp, err = d.EnableKey(ctx, &kmstypes.EnableKeyRequest{KeyID: keyID})
+	assert.NoError(t, err)
+	assert.Equal(t, kmstypes.KeyStateEnabled, resp.KeyMetadata.KeyState)
+	decryptResp, err := d.Decrypt(ctx, &kmstypes.DecryptRequest{KeyID: keyID, CiphertextBase64: encryptResp.CiphertextBase64})
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decryptResp.PlaintextBase64)
+
+	// schedule deletion
+	resp, err = d.ScheduleKeyDeletion(ctx, &kmstypes.ScheduleKeyDeletionRequest{KeyID: keyID, PendingWindowInDays: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, kmstypes.KeyStatePendingDeletion, resp.KeyMetadata.KeyState)
+	assert.True(t, resp.KeyMetadata.DeletionAt.After(time.Now().Add(6*kmstypes.Day)))
+	_, err = d.Encrypt(ctx, &kmstypes.EncryptRequest{KeyID: keyID, PlaintextBase64: plaintext})
+	assert.Error(t, err)
+	_, err = d.EnableKey(ctx, &kmstypes.EnableKeyRequest{KeyID: keyID})
+	assert.Error(t, err)
+	_, err = d.ScheduleKeyDeletion(ctx, &kmstypes.ScheduleKeyDeletionRequest{KeyID: keyID})
+	assert.Error(t, err)
+
+	// cancel deletion, key is disabled
+	resp, err = d.CancelKeyDeletion(ctx, &kmstypes.CancelKeyDeletionRequest{KeyID: keyID})
+	assert.NoError(t, err)
+	assert.Equal(t, kmstypes.KeyStateDisabled, resp.KeyMetadata.KeyState)
+	assert.Nil(t, resp.KeyMetadata.DeletionAt)
+	_, err = d.CancelKeyDeletion(ctx, &kmstypes.CancelKeyDeletionRequest{KeyID: keyID})
+	assert.Error(t, err)
+}
+
+func TestDice_RotationPolicy(t *testing.T) {
+	d := newTestDice()
+	ctx := context.Background()
+
+	createResp, err := d.CreateKey(ctx, &kmstypes.CreateKeyRequest{
+		PluginKind:            kmstypes.PluginKind_DICE_KMS,
+		CustomerMasterKeySpec: kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT,
+		KeyUsage:              kmstypes.KeyUsage_ENCRYPT_DECRYPT,
+		RotationIntervalDays:  90,
+	})
+	assert.NoError(t, err)
+	keyID := createResp.KeyMetadata.KeyID
+	assert.Equal(t, 90, createResp.KeyMetadata.RotationIntervalDays)
+	assert.True(t, createResp.KeyMetadata.NextRotationAt.After(time.Now().Add(89*kmstypes.Day)))
+
+	// shorten rotation interval
+	resp, err := d.UpdateRotationPolicy(ctx, &kmstypes.UpdateRotationPolicyRequest{KeyID: keyID, RotationIntervalDays: 30})
+	assert.NoError(t, err)
+	assert.Equal(t, 30, resp.KeyMetadata.RotationIntervalDays)
+	nextRotationAt := *resp.KeyMetadata.NextRotationAt
+	assert.True(t, nextRotationAt.Before(time.Now().Add(31*kmstypes.Day)))
+
+	keyInfo, err := d.store.GetKey(keyID)
+	assert.NoError(t, err)
+	assert.False(t, kmstypes.IsRotationDue(keyInfo, time.Now()))
+	assert.True(t, kmstypes.IsRotationDue(keyInfo, nextRotationAt))
+
+	// rotation resets next rotation time
+	time.Sleep(time.Millisecond)
+	rotateResp, err := d.RotateKeyVersion(ctx, &kmstypes.RotateKeyVersionRequest{KeyID: keyID})
+	assert.NoError(t, err)
+	assert.True(t, rotateResp.KeyMetadata.NextRotationAt.After(nextRotationAt))
+
+	// disable automatic rotation
+	resp, err = d.UpdateRotationPolicy(ctx, &kmstypes.UpdateRotationPolicyRequest{KeyID: keyID})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.KeyMetadata.RotationIntervalDays)
+	assert.Nil(t, resp.KeyMetadata.NextRotationAt)
+}
@@ -107,6 +107,11 @@ func (s *Store) CreateKey(keyInfo kmstypes.KeyInfo) error {
 		KeyUsage:          keyInfo.GetKeyUsage(),
 		KeyState:          keyInfo.GetKeyState(),
 		Description:       keyInfo.GetDescription(),
+		OrgID:             keyInfo.GetOrgID(),
+		ProjectID:         keyInfo.GetProjectID(),
+		RotationInterval:  keyInfo.GetRotationInterval(),
+		NextRotationAt:    keyInfo.GetNextRotationAt(),
+		DeletionAt:        keyInfo.GetDeletionAt(),
 		CreatedAt:         &now,
 		UpdatedAt:         &now,
 	}
@@ -155,18 +160,97 @@ func (s *Store) ListKeysByKind(kind kmstypes.PluginKind) ([]string, error) {
 		return nil, err
 	}
 	var keys []string
-	prefix := makeEtcdPluginKindPrefix(kind)
+	// value of relation key is the main key: /dice/kms/cmk/<keyID>
+	prefix := makeEtcdKeyID("")
 	for _, v := range values {
 		keys = append(keys, strings.TrimPrefix(string(v.Value), prefix))
 	}
 	return keys, nil
 }
 
+func (s *Store) UpdateKey(keyInfo kmstypes.KeyInfo) error {
+	ctx := context.Background()
+	key, err := getKeyFromEtcd(ctx, keyInfo.GetKeyID(), s.etcdClient)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return fmt.Errorf("key not exist")
+		}
+		return fmt.Errorf("get key from etcd failed, err: %v", err)
+	}
+	// only metadata can be updated, key versions are changed by RotateKeyVersion
+	key.KeyState = keyInfo.GetKeyState()
+	key.Description = keyInfo.GetDescription()
+	key.RotationInterval = keyInfo.GetRotationInterval()
+	key.NextRotationAt = keyInfo.GetNextRotationAt()
+	key.DeletionAt = keyInfo.GetDeletionAt()
+	key.SetUpdatedAt(time.Now())
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return s.etcdClient.Put(ctx, makeEtcdKeyID(key.KeyID), string(keyJSON))
+}
+
+func (s *Store) ClaimKeyRotation(keyID string, expectedNextRotationAt, newNextRotationAt time.Time) (bool, error) {
+	ctx := context.Background()
+	etcdKey := makeEtcdKeyID(keyID)
+	getResp, err := s.etcdClient.GetClient().Get(ctx, etcdKey)
+	if err != nil {
+		return false, fmt.Errorf("get key from etcd failed, err: %v", err)
+	}
+	if len(getResp.Kvs) == 0 {
+		return false, fmt.Errorf("key not exist")
+	}
+	var key kmstypes.Key
+	if err := json.Unmarshal(getResp.Kvs[0].Value, &key); err != nil {
+		return false, err
+	}
+	if key.NextRotationAt == nil || !key.NextRotationAt.Equal(expectedNextRotationAt) {
+		return false, nil
+	}
+	key.NextRotationAt = &newNextRotationAt
+	key.SetUpdatedAt(time.Now())
+	keyJSON, err := json.Marshal(&key)
+	if err != nil {
+		return false, err
+	}
+	// compare mod revision to make sure key is not changed by others after get
+	txnResp, err := s.etcdClient.GetClient().Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(etcdKey), "=", getResp.Kvs[0].ModRevision)).
+		Then(clientv3.OpPut(etcdKey, string(keyJSON))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return txnResp.Succeeded, nil
+}
+
 func (s *Store) DeleteByKeyID(keyID string) error {
 	ctx := context.Background()
-	_, err := s.etcdClient.Remove(ctx, makeEtcdKeyID(keyID))
-	// TODO delete relation key
-	return err
+	key, err := getKeyFromEtcd(ctx, keyID, s.etcdClient)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil
+		}
+		return err
+	}
+	resp, err := s.etcdClient.GetClient().Txn(ctx).
+		Then(
+			// key versions
+			clientv3.OpDelete(makeEtcdKeyVersionID(keyID, ""), clientv3.WithPrefix()),
+			// 引用：插件类型
+			clientv3.OpDelete(makeEtcdKeyIDUnderPlugin(keyID, key.GetPluginKind())),
+			// CMK
+			clientv3.OpDelete(makeEtcdKeyID(keyID)),
+		).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("failed to delete data from etcd when delete key")
+	}
+	return nil
 }
 
 func (s *Store) GetKeyVersion(keyID, keyVersionID string) (kmstypes.KeyVersionInfo, error) {
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"time"
+
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+// zeroTime is used as `null` for datetime columns which are not null
+var zeroTime = time.Date(1970, 1, 1, 0, 0, 0, 0, time.Local)
+
+// KmsKey is the CMK metadata
+type KmsKey struct {
+	ID                  string `gorm:"primary_key"`
+	PluginKind          string
+	PrimaryKeyVersionID string
+	KeySpec             string
+	KeyUsage            string
+	KeyState            string
+	Description         string
+	OrgID               string
+	ProjectID           string
+	RotationIntervalSec int64
+	NextRotationAt      time.Time
+	DeletionAt          time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (KmsKey) TableName() string {
+	return "kms_keys"
+}
+
+// KmsKeyVersion is the key material of one CMK version
+type KmsKeyVersion struct {
+	ID                 string `gorm:"primary_key"`
+	KeyID              string
+	SymmetricKeyBase64 string
+	PublicKeyBase64    string
+	PrivateKeyBase64   string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (KmsKeyVersion) TableName() string {
+	return "kms_key_versions"
+}
+
+func (v *KmsKeyVersion) ToKeyVersion() *kmstypes.KeyVersion {
+	return &kmstypes.KeyVersion{
+		VersionID:          v.ID,
+		SymmetricKeyBase64: v.SymmetricKeyBase64,
+		PublicKeyBase64:    v.PublicKeyBase64,
+		PrivateKeyBase64:   v.PrivateKeyBase64,
+		CreatedAt:          &v.CreatedAt,
+		UpdatedAt:          &v.UpdatedAt,
+	}
+}
+
+func (k *KmsKey) ToKey(primaryKeyVersion *KmsKeyVersion) *kmstypes.Key {
+	key := kmstypes.Key{
+		PluginKind:       kmstypes.PluginKind(k.PluginKind),
+		KeyID:            k.ID,
+		KeySpec:          kmstypes.CustomerMasterKeySpec(k.KeySpec),
+		KeyUsage:         kmstypes.KeyUsage(k.KeyUsage),
+		KeyState:         kmstypes.KeyState(k.KeyState),
+		Description:      k.Description,
+		OrgID:            k.OrgID,
+		ProjectID:        k.ProjectID,
+		RotationInterval: time.Duration(k.RotationIntervalSec) * time.Second,
+		NextRotationAt:   fromZeroTime(k.NextRotationAt),
+		DeletionAt:       fromZeroTime(k.DeletionAt),
+		CreatedAt:        &k.CreatedAt,
+		UpdatedAt:        &k.UpdatedAt,
+	}
+	if primaryKeyVersion != nil {
+		key.PrimaryKeyVersion = *primaryKeyVersion.ToKeyVersion()
+	}
+	return &key
+}
+
+func newKmsKeyVersion(keyID string, version kmstypes.KeyVersionInfo) *KmsKeyVersion {
+	return &KmsKeyVersion{
+		ID:                 version.GetVersionID(),
+		KeyID:              keyID,
+		SymmetricKeyBase64: version.GetSymmetricKeyBase64(),
+		PublicKeyBase64:    version.GetPublicKeyBase64(),
+		PrivateKeyBase64:   version.GetPrivateKeyBase64(),
+	}
+}
+
+// toZeroTime also truncates t to seconds, columns are DATETIME without fractional seconds,
+// otherwise mysql rounds the value and compare-and-set by time never matches.
+func toZeroTime(t *time.Time) time.Time {
+	if t == nil || t.IsZero() {
+		return zeroTime
+	}
+	return t.Truncate(time.Second)
+}
+
+func fromZeroTime(t time.Time) *time.Time {
+	if t.IsZero() || !t.After(zeroTime) {
+		return nil
+	}
+	return &t
+}
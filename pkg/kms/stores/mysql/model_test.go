@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+func TestZeroTime(t *testing.T) {
+	assert.Equal(t, zeroTime, toZeroTime(nil))
+	assert.Nil(t, fromZeroTime(zeroTime))
+	assert.Nil(t, fromZeroTime(time.Time{}))
+
+	now := time.Now()
+	assert.Equal(t, now.Truncate(time.Second), toZeroTime(&now))
+	assert.Equal(t, now, *fromZeroTime(now))
+}
+
+func TestKmsKey_ToKey(t *testing.T) {
+	now := time.Now()
+	key := KmsKey{
+		ID:                  "key",
+		PluginKind:          string(kmstypes.PluginKind_DICE_KMS),
+		PrimaryKeyVersionID: "v2",
+		KeySpec:             string(kmstypes.CustomerMasterKeySpec_SYMMETRIC_DEFAULT),
+		KeyUsage:            string(kmstypes.KeyUsage_ENCRYPT_DECRYPT),
+		KeyState:            string(kmstypes.KeyStateEnabled),
+		OrgID:               "1",
+		ProjectID:           "2",
+		RotationIntervalSec: int64(90 * kmstypes.Day / time.Second),
+		NextRotationAt:      now,
+		DeletionAt:          zeroTime,
+	}
+	keyInfo := key.ToKey(&KmsKeyVersion{ID: "v2", KeyID: "key", SymmetricKeyBase64: "c2VjcmV0"})
+	assert.Equal(t, "v2", keyInfo.GetPrimaryKeyVersion().GetVersionID())
+	assert.Equal(t, "c2VjcmV0", keyInfo.GetPrimaryKeyVersion().GetSymmetricKeyBase64())
+	assert.Equal(t, 90*kmstypes.Day, keyInfo.GetRotationInterval())
+	assert.Equal(t, now, *keyInfo.GetNextRotationAt())
+	assert.Nil(t, keyInfo.GetDeletionAt())
+	assert.Equal(t, "1", keyInfo.GetOrgID())
+	assert.Equal(t, "2", keyInfo.GetProjectID())
+
+	version := newKmsKeyVersion("key", &keyInfo.PrimaryKeyVersion)
+	assert.Equal(t, "v2", version.ID)
+	assert.Equal(t, "key", version.KeyID)
+}
@@ -13,3 +13,170 @@
 // limitations under the License.
 
 package mysql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda/pkg/database/dbengine"
+	"github.com/erda-project/erda/pkg/kms/kmstypes"
+)
+
+type Store struct {
+	db *dbengine.DBEngine
+}
+
+func init() {
+	logrus.Infof("begin register mysql store createFn to factory...")
+	err := kmstypes.RegisterStore(kmstypes.StoreKind_MYSQL, func(ctx context.Context) kmstypes.Store {
+		// mysql configs are loaded from envs, see dbengine.Conf
+		db, err := dbengine.Open()
+		if err != nil {
+			panic(fmt.Errorf("failed to init mysql client, err: %v", err))
+		}
+		if err := db.Ping(); err != nil {
+			panic(fmt.Errorf("failed to ping mysql, err: %v", err))
+		}
+		return &Store{db: db}
+	})
+	if err != nil {
+		logrus.Errorf("[alert] failed to register mysql store createFn to factory, err: %v", err)
+		os.Exit(1)
+	}
+}
+
+func (s *Store) GetKind() kmstypes.StoreKind {
+	return kmstypes.StoreKind_MYSQL
+}
+
+func (s *Store) CreateKey(keyInfo kmstypes.KeyInfo) error {
+	if err := kmstypes.CheckKeyForCreate(keyInfo); err != nil {
+		return err
+	}
+	key := KmsKey{
+		ID:                  keyInfo.GetKeyID(),
+		PluginKind:          string(keyInfo.GetPluginKind()),
+		PrimaryKeyVersionID: keyInfo.GetPrimaryKeyVersion().GetVersionID(),
+		KeySpec:             string(keyInfo.GetKeySpec()),
+		KeyUsage:            string(keyInfo.GetKeyUsage()),
+		KeyState:            string(keyInfo.GetKeyState()),
+		Description:         keyInfo.GetDescription(),
+		OrgID:               keyInfo.GetOrgID(),
+		ProjectID:           keyInfo.GetProjectID(),
+		RotationIntervalSec: int64(keyInfo.GetRotationInterval() / time.Second),
+		NextRotationAt:      toZeroTime(keyInfo.GetNextRotationAt()),
+		DeletionAt:          toZeroTime(keyInfo.GetDeletionAt()),
+	}
+	keyVersion := newKmsKeyVersion(keyInfo.GetKeyID(), keyInfo.GetPrimaryKeyVersion())
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&key).Error; err != nil {
+			return fmt.Errorf("failed to create key, err: %v", err)
+		}
+		if err := tx.Create(keyVersion).Error; err != nil {
+			return fmt.Errorf("failed to create key version, err: %v", err)
+		}
+		return nil
+	})
+}
+
+func (s *Store) GetKey(keyID string) (kmstypes.KeyInfo, error) {
+	var key KmsKey
+	if err := s.db.Where("id = ?", keyID).First(&key).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, fmt.Errorf("key not exist")
+		}
+		return nil, fmt.Errorf("get key from mysql failed, err: %v", err)
+	}
+	var primaryKeyVersion KmsKeyVersion
+	if err := s.db.Where("id = ? AND key_id = ?", key.PrimaryKeyVersionID, keyID).First(&primaryKeyVersion).Error; err != nil {
+		return nil, fmt.Errorf("get primary key version from mysql failed, err: %v", err)
+	}
+	return key.ToKey(&primaryKeyVersion), nil
+}
+
+func (s *Store) ListKeysByKind(kind kmstypes.PluginKind) ([]string, error) {
+	var keyIDs []string
+	if err := s.db.Model(&KmsKey{}).Where("plugin_kind = ?", kind).Pluck("id", &keyIDs).Error; err != nil {
+		return nil, err
+	}
+	return keyIDs, nil
+}
+
+func (s *Store) UpdateKey(keyInfo kmstypes.KeyInfo) error {
+	// only metadata can be updated, key versions are changed by RotateKeyVersion
+	result := s.db.Model(&KmsKey{}).Where("id = ?", keyInfo.GetKeyID()).Updates(map[string]interface{}{
+		"key_state":             string(keyInfo.GetKeyState()),
+		"description":           keyInfo.GetDescription(),
+		"rotation_interval_sec": int64(keyInfo.GetRotationInterval() / time.Second),
+		"next_rotation_at":      toZeroTime(keyInfo.GetNextRotationAt()),
+		"deletion_at":           toZeroTime(keyInfo.GetDeletionAt()),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update key, err: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("key not exist")
+	}
+	return nil
+}
+
+func (s *Store) ClaimKeyRotation(keyID string, expectedNextRotationAt, newNextRotationAt time.Time) (bool, error) {
+	result := s.db.Model(&KmsKey{}).
+		Where("id = ? AND next_rotation_at = ?", keyID, toZeroTime(&expectedNextRotationAt)).
+		Update("next_rotation_at", toZeroTime(&newNextRotationAt))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim key rotation, err: %v", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (s *Store) DeleteByKeyID(keyID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("key_id = ?", keyID).Delete(&KmsKeyVersion{}).Error; err != nil {
+			return fmt.Errorf("failed to delete key versions, err: %v", err)
+		}
+		if err := tx.Where("id = ?", keyID).Delete(&KmsKey{}).Error; err != nil {
+			return fmt.Errorf("failed to delete key, err: %v", err)
+		}
+		return nil
+	})
+}
+
+func (s *Store) GetKeyVersion(keyID, keyVersionID string) (kmstypes.KeyVersionInfo, error) {
+	var keyVersion KmsKeyVersion
+	if err := s.db.Where("id = ? AND key_id = ?", keyVersionID, keyID).First(&keyVersion).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, fmt.Errorf("key version not exist")
+		}
+		return nil, err
+	}
+	return keyVersion.ToKeyVersion(), nil
+}
+
+func (s *Store) RotateKeyVersion(keyID string, newKeyVersionInfo kmstypes.KeyVersionInfo) (kmstypes.KeyVersionInfo, error) {
+	keyVersion := newKmsKeyVersion(keyID, newKeyVersionInfo)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(keyVersion).Error; err != nil {
+			return fmt.Errorf("failed to create key version, err: %v", err)
+		}
+		// update CMK PrimaryKeyVersion
+		result := tx.Model(&KmsKey{}).Where("id = ?", keyID).Update("primary_key_version_id", keyVersion.ID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update primary key version, err: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("key not exist")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keyVersion.ToKeyVersion(), nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/pkg/database/dbengine"
+)
+
+func TestStore_ClaimKeyRotationRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	gdb, err := gorm.Open("mysql", db)
+	assert.NoError(t, err)
+	store := &Store{db: &dbengine.DBEngine{DB: gdb}}
+
+	// stored value has no fractional seconds
+	expected := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	claimed := time.Date(2026, 11, 17, 8, 0, 1, 123456789, time.UTC)
+
+	// claim
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `kms_keys` SET `next_rotation_at` = \\?").
+		WithArgs(claimed.Truncate(time.Second), sqlmock.AnyArg(), "key", expected).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	ok, err := store.ClaimKeyRotation("key", expected, claimed)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// rollback with the nanosecond value must match the second-truncated stored one
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `kms_keys` SET `next_rotation_at` = \\?").
+		WithArgs(expected, sqlmock.AnyArg(), "key", claimed.Truncate(time.Second)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	ok, err = store.ClaimKeyRotation("key", claimed, expected)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// claimed by others
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `kms_keys` SET `next_rotation_at` = \\?").
+		WithArgs(claimed.Truncate(time.Second), sqlmock.AnyArg(), "key", expected).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	ok, err = store.ClaimKeyRotation("key", expected, claimed)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
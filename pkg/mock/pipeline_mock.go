@@ -191,6 +191,26 @@ func (mr *MockPipelineServiceClientMockRecorder) PipelineDetail(ctx, in interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineDetail", reflect.TypeOf((*MockPipelineServiceClient)(nil).PipelineDetail), varargs...)
 }
 
+// PipelineDryRun mocks base method.
+func (m *MockPipelineServiceClient) PipelineDryRun(ctx context.Context, in *pb.PipelineDryRunRequest, opts ...grpc.CallOption) (*pb.PipelineDryRunResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PipelineDryRun", varargs...)
+	ret0, _ := ret[0].(*pb.PipelineDryRunResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelineDryRun indicates an expected call of PipelineDryRun.
+func (mr *MockPipelineServiceClientMockRecorder) PipelineDryRun(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineDryRun", reflect.TypeOf((*MockPipelineServiceClient)(nil).PipelineDryRun), varargs...)
+}
+
 // PipelineOperate mocks base method.
 func (m *MockPipelineServiceClient) PipelineOperate(ctx context.Context, in *pb.PipelineOperateRequest, opts ...grpc.CallOption) (*pb.PipelineOperateResponse, error) {
 	m.ctrl.T.Helper()
@@ -331,6 +351,26 @@ func (mr *MockPipelineServiceClientMockRecorder) PipelineTaskView(ctx, in interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineTaskView", reflect.TypeOf((*MockPipelineServiceClient)(nil).PipelineTaskView), varargs...)
 }
 
+// PipelineTriggerChain mocks base method.
+func (m *MockPipelineServiceClient) PipelineTriggerChain(ctx context.Context, in *pb.PipelineTriggerChainRequest, opts ...grpc.CallOption) (*pb.PipelineTriggerChainResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PipelineTriggerChain", varargs...)
+	ret0, _ := ret[0].(*pb.PipelineTriggerChainResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelineTriggerChain indicates an expected call of PipelineTriggerChain.
+func (mr *MockPipelineServiceClientMockRecorder) PipelineTriggerChain(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineTriggerChain", reflect.TypeOf((*MockPipelineServiceClient)(nil).PipelineTriggerChain), varargs...)
+}
+
 // QueryPipelineSnippet mocks base method.
 func (m *MockPipelineServiceClient) QueryPipelineSnippet(ctx context.Context, in *pb.PipelineSnippetQueryRequest, opts ...grpc.CallOption) (*pb.PipelineSnippetQueryResponse, error) {
 	m.ctrl.T.Helper()
@@ -479,6 +519,21 @@ func (mr *MockPipelineServiceServerMockRecorder) PipelineDetail(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineDetail", reflect.TypeOf((*MockPipelineServiceServer)(nil).PipelineDetail), arg0, arg1)
 }
 
+// PipelineDryRun mocks base method.
+func (m *MockPipelineServiceServer) PipelineDryRun(arg0 context.Context, arg1 *pb.PipelineDryRunRequest) (*pb.PipelineDryRunResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PipelineDryRun", arg0, arg1)
+	ret0, _ := ret[0].(*pb.PipelineDryRunResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelineDryRun indicates an expected call of PipelineDryRun.
+func (mr *MockPipelineServiceServerMockRecorder) PipelineDryRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineDryRun", reflect.TypeOf((*MockPipelineServiceServer)(nil).PipelineDryRun), arg0, arg1)
+}
+
 // PipelineOperate mocks base method.
 func (m *MockPipelineServiceServer) PipelineOperate(arg0 context.Context, arg1 *pb.PipelineOperateRequest) (*pb.PipelineOperateResponse, error) {
 	m.ctrl.T.Helper()
@@ -584,6 +639,21 @@ func (mr *MockPipelineServiceServerMockRecorder) PipelineTaskView(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineTaskView", reflect.TypeOf((*MockPipelineServiceServer)(nil).PipelineTaskView), arg0, arg1)
 }
 
+// PipelineTriggerChain mocks base method.
+func (m *MockPipelineServiceServer) PipelineTriggerChain(arg0 context.Context, arg1 *pb.PipelineTriggerChainRequest) (*pb.PipelineTriggerChainResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PipelineTriggerChain", arg0, arg1)
+	ret0, _ := ret[0].(*pb.PipelineTriggerChainResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelineTriggerChain indicates an expected call of PipelineTriggerChain.
+func (mr *MockPipelineServiceServerMockRecorder) PipelineTriggerChain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineTriggerChain", reflect.TypeOf((*MockPipelineServiceServer)(nil).PipelineTriggerChain), arg0, arg1)
+}
+
 // QueryPipelineSnippet mocks base method.
 func (m *MockPipelineServiceServer) QueryPipelineSnippet(arg0 context.Context, arg1 *pb.PipelineSnippetQueryRequest) (*pb.PipelineSnippetQueryResponse, error) {
 	m.ctrl.T.Helper()
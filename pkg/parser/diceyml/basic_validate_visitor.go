@@ -15,18 +15,23 @@
 package diceyml
 
 import (
+	"fmt"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type BasicValidateVisitor struct {
 	DefaultVisitor
 	currentAddOn  string
 	collectErrors ValidateError
+	// collectWarnings are problems which can not be checked statically, such as content of image
+	collectWarnings []string
 }
 
 func NewBasicValidateVisitor() DiceYmlVisitor {
@@ -196,6 +201,56 @@ func (o *BasicValidateVisitor) VisitDeployments(v DiceYmlVisitor, obj *Deploymen
 	if obj.Policies != "" && obj.Policies != "shuffle" && obj.Policies != "affinity" && obj.Policies != "unique" {
 		o.collectErrors[yamlHeaderRegexWithUpperHeader([]string{o.currentService, "deployments"}, "policies")] = errors.Wrap(invalidPolicy, o.currentService)
 	}
+
+	header := []string{o.currentService, "deployments"}
+	// kubernetes allows max_surge above 100%, e.g. 200% starts all new pods at once
+	maxSurge, surgeOK := intOrPercentValue(obj.MaxSurge, false)
+	if !surgeOK {
+		o.collectErrors[yamlHeaderRegexWithUpperHeader(header, "max_surge")] = errors.Wrap(invalidRollingUpdate, o.currentService)
+	}
+	maxUnavailable, unavailableOK := intOrPercentValue(obj.MaxUnavailable, true)
+	if !unavailableOK {
+		o.collectErrors[yamlHeaderRegexWithUpperHeader(header, "max_unavailable")] = errors.Wrap(invalidRollingUpdate, o.currentService)
+	}
+	if surgeOK && unavailableOK && obj.MaxSurge != nil && obj.MaxUnavailable != nil && maxSurge == 0 && maxUnavailable == 0 {
+		o.collectErrors[yamlHeaderRegexWithUpperHeader(header, "max_unavailable")] = errors.Wrap(zeroRollingUpdate, o.currentService)
+	}
+
+	// a min_available not less than replicas forbids any voluntary disruption, such as node drain
+	minAvailable, ok := intOrPercentValue(obj.MinAvailable, true)
+	if !ok || (obj.MinAvailable != nil && obj.MinAvailable.Type == intstr.Int && obj.Replicas > 0 && minAvailable >= obj.Replicas) {
+		o.collectErrors[yamlHeaderRegexWithUpperHeader(header, "min_available")] = errors.Wrap(invalidMinAvailable, o.currentService)
+	}
+
+	topologies := map[string]struct{}{}
+	for _, spread := range obj.TopologySpread {
+		_, duplicated := topologies[spread.Topology]
+		topologies[spread.Topology] = struct{}{}
+		if duplicated || (spread.Topology != TopologyZone && spread.Topology != TopologyNode) || spread.MaxSkew < 0 ||
+			(spread.WhenUnsatisfiable != "" && spread.WhenUnsatisfiable != WhenUnsatisfiableScheduleAnyway &&
+				spread.WhenUnsatisfiable != WhenUnsatisfiableDoNotSchedule) {
+			o.collectErrors[yamlHeaderRegexWithUpperHeader(header, "topology_spread")] = errors.Wrap(invalidTopologySpread, o.currentService)
+		}
+	}
+}
+
+// intOrPercentValue return the value of a number or percentage, percentage must not be negative,
+// and must not exceed 100% if capped
+func intOrPercentValue(v *intstr.IntOrString, capped bool) (int, bool) {
+	if v == nil {
+		return 0, true
+	}
+	if v.Type == intstr.Int {
+		return v.IntValue(), v.IntValue() >= 0
+	}
+	if !strings.HasSuffix(v.StrVal, "%") {
+		return 0, false
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(v.StrVal, "%"))
+	if err != nil || percent < 0 || (capped && percent > 100) {
+		return 0, false
+	}
+	return percent, true
 }
 
 func (o *BasicValidateVisitor) VisitAddOns(v DiceYmlVisitor, obj *AddOns) {
@@ -215,8 +270,68 @@ func (o *BasicValidateVisitor) VisitAddOn(v DiceYmlVisitor, obj *AddOn) {
 	}
 }
 
+func (o *BasicValidateVisitor) VisitHealthCheck(v DiceYmlVisitor, obj *HealthCheck) {
+	if o.currentService == "" {
+		return
+	}
+	header := []string{o.currentService, "health_check"}
+	types := obj.CheckTypes()
+	// http and exec can be set at the same time for compatibility, http is preferred
+	if len(types) > 1 && (obj.TCP != nil || obj.GRPC != nil) {
+		o.collectErrors[yamlHeaderRegexWithUpperHeader([]string{o.currentService}, "health_check")] = errors.Wrap(multipleHealthCheckTypes, o.currentService)
+	}
+	o.validateCheckPorts(header, obj.TCP, obj.GRPC)
+
+	probes := []struct {
+		name  string
+		probe *Probe
+	}{{"readiness", obj.Readiness}, {"liveness", obj.Liveness}, {"startup", obj.Startup}}
+	for _, p := range probes {
+		if p.probe == nil {
+			continue
+		}
+		probeHeader := []string{o.currentService, "health_check", p.name}
+		probeTypes := p.probe.CheckTypes()
+		if len(probeTypes) > 1 {
+			o.collectErrors[yamlHeaderRegexWithUpperHeader(header, p.name)] = errors.Wrap(multipleHealthCheckTypes, o.currentService+":["+p.name+"]")
+		}
+		if len(probeTypes) == 0 && len(types) == 0 {
+			o.collectErrors[yamlHeaderRegexWithUpperHeader(header, p.name)] = errors.Wrap(emptyProbeCheck, o.currentService+":["+p.name+"]")
+		}
+		o.validateCheckPorts(probeHeader, p.probe.TCP, p.probe.GRPC)
+		if p.probe.InitialDelaySeconds < 0 || p.probe.PeriodSeconds < 0 || p.probe.TimeoutSeconds < 0 ||
+			p.probe.SuccessThreshold < 0 || p.probe.FailureThreshold < 0 {
+			o.collectErrors[yamlHeaderRegexWithUpperHeader(header, p.name)] = errors.Wrap(invalidProbeSetting, o.currentService+":["+p.name+"]")
+		}
+		// kubernetes requires successThreshold of liveness and startup probe to be 1
+		if p.name != "readiness" && p.probe.SuccessThreshold > 1 {
+			o.collectErrors[yamlHeaderRegexWithUpperHeader(probeHeader, "success_threshold")] = errors.Wrap(invalidProbeSuccessThreshold, o.currentService+":["+p.name+"]")
+		}
+	}
+}
+
+func (o *BasicValidateVisitor) validateCheckPorts(header []string, tcp *TCPCheck, grpc *GRPCCheck) {
+	if tcp != nil && (tcp.Port <= 0 || tcp.Port > 65535) {
+		o.collectErrors[yamlHeaderRegexWithUpperHeader(header, "tcp")] = errors.Wrap(invalidHealthCheckPort, o.currentService)
+	}
+	if grpc != nil && (grpc.Port <= 0 || grpc.Port > 65535) {
+		o.collectErrors[yamlHeaderRegexWithUpperHeader(header, "grpc")] = errors.Wrap(invalidHealthCheckPort, o.currentService)
+	}
+	if grpc != nil {
+		o.collectWarnings = append(o.collectWarnings, fmt.Sprintf("%s: grpc check is executed by %s in container, "+
+			"make sure it exists in PATH of image, otherwise the check always fails", strings.Join(header, "."), GRPCHealthProbeBin))
+	}
+}
+
 func BasicValidate(obj *Object) ValidateError {
 	visitor := NewBasicValidateVisitor()
 	obj.Accept(visitor)
 	return visitor.(*BasicValidateVisitor).collectErrors
 }
+
+// BasicValidateWarnings return warnings which don't block deployment but may cause runtime failure.
+func BasicValidateWarnings(obj *Object) []string {
+	visitor := NewBasicValidateVisitor()
+	obj.Accept(visitor)
+	return visitor.(*BasicValidateVisitor).collectWarnings
+}
@@ -86,3 +86,123 @@ func TestBasicValidate(t *testing.T) {
 	assert.Equal(t, 6, len(es), "%v", es)
 
 }
+
+var basic_validate_healthcheck_yml = `version: 2.0
+services:
+  grpc-server:
+    ports:
+    - 9090
+    resources:
+      cpu: 0.1
+      mem: 256
+    health_check:
+      grpc:
+        port: 9090
+        service: helloworld.Greeter
+      startup:
+        failure_threshold: 30
+        period_seconds: 10
+      liveness:
+        tcp:
+          port: 9090
+        initial_delay_seconds: 10
+  invalid-server:
+    ports:
+    - 8080
+    resources:
+      cpu: 0.1
+      mem: 256
+    health_check:
+      tcp:
+        port: 0
+      readiness:
+        http:
+          port: 8080
+          path: /health
+        grpc:
+          port: 8080
+      liveness:
+        success_threshold: 2
+        failure_threshold: -1
+  no-check-server:
+    ports:
+    - 8080
+    resources:
+      cpu: 0.1
+      mem: 256
+    health_check:
+      readiness:
+        period_seconds: 5
+`
+
+func TestBasicValidate_HealthCheck(t *testing.T) {
+	d, err := New([]byte(basic_validate_healthcheck_yml), false)
+	assert.Nil(t, err)
+	es := BasicValidate(d.Obj())
+	// invalid-server: tcp port, readiness multiple types, liveness negative setting, liveness success_threshold
+	// no-check-server: readiness without check
+	assert.Equal(t, 5, len(es), "%v", es)
+	for _, err := range es {
+		assert.NotContains(t, err.Error(), "grpc-server")
+	}
+
+	warnings := d.Warnings()
+	assert.Equal(t, 2, len(warnings), "%v", warnings)
+	assert.Contains(t, warnings[0], "grpc_health_probe")
+}
+
+var basic_validate_deployments_yml = `version: 2.0
+services:
+  web:
+    ports:
+    - 8080
+    resources:
+      cpu: 0.1
+      mem: 256
+    deployments:
+      replicas: 3
+      max_surge: 25%
+      max_unavailable: 0
+      min_available: 2
+      topology_spread:
+      - topology: zone
+        max_skew: 1
+        when_unsatisfiable: do_not_schedule
+      - topology: node
+  invalid-web:
+    ports:
+    - 8080
+    resources:
+      cpu: 0.1
+      mem: 256
+    deployments:
+      replicas: 2
+      max_surge: 0
+      max_unavailable: 0%
+      min_available: 2
+      topology_spread:
+      - topology: rack
+  invalid-percent-web:
+    ports:
+    - 8080
+    resources:
+      cpu: 0.1
+      mem: 256
+    deployments:
+      replicas: 2
+      max_surge: 200%
+      max_unavailable: 120%
+      min_available: -1
+`
+
+func TestBasicValidate_Deployments(t *testing.T) {
+	d, err := New([]byte(basic_validate_deployments_yml), false)
+	assert.Nil(t, err)
+	es := BasicValidate(d.Obj())
+	// invalid-web: zero rolling update, min_available not less than replicas, unknown topology
+	// invalid-percent-web: max_unavailable percentage, negative min_available, max_surge above 100% is allowed
+	assert.Equal(t, 5, len(es), "%v", es)
+	for _, err := range es {
+		assert.NotRegexp(t, "^web:", err.Error())
+	}
+}
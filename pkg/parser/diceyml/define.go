@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/yaml"
 
 	"github.com/erda-project/erda/pkg/strutil"
@@ -649,6 +650,14 @@ type SideCar struct {
 type HealthCheck struct {
 	HTTP *HTTPCheck `yaml:"http,omitempty" json:"http,omitempty"`
 	Exec *ExecCheck `yaml:"exec,omitempty" json:"exec,omitempty"`
+	TCP  *TCPCheck  `yaml:"tcp,omitempty" json:"tcp,omitempty"`
+	GRPC *GRPCCheck `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+
+	// Readiness, Liveness and Startup are independent probes,
+	// the check above is used for both readiness and liveness if they are not set
+	Readiness *Probe `yaml:"readiness,omitempty" json:"readiness,omitempty"`
+	Liveness  *Probe `yaml:"liveness,omitempty" json:"liveness,omitempty"`
+	Startup   *Probe `yaml:"startup,omitempty" json:"startup,omitempty"`
 }
 
 type HTTPCheck struct {
@@ -662,6 +671,66 @@ type ExecCheck struct {
 	Duration int    `yaml:"duration,omitempty" json:"duration,omitempty"`
 }
 
+type TCPCheck struct {
+	Port     int `yaml:"port,omitempty" json:"port,omitempty"`
+	Duration int `yaml:"duration,omitempty" json:"duration,omitempty"`
+}
+
+// GRPCHealthProbeBin is the binary which executes grpc check in container
+const GRPCHealthProbeBin = "grpc_health_probe"
+
+// GRPCCheck use standard grpc.health.v1.Health/Check
+// The check is executed by grpc_health_probe (https://github.com/grpc-ecosystem/grpc-health-probe) in container,
+// the binary must exist in PATH of image, otherwise the check always fails and the container keeps restarting.
+type GRPCCheck struct {
+	Port int `yaml:"port,omitempty" json:"port,omitempty"`
+	// Service is the service name passed to grpc.health.v1.HealthCheckRequest, empty means the whole server
+	Service  string `yaml:"service,omitempty" json:"service,omitempty"`
+	Duration int    `yaml:"duration,omitempty" json:"duration,omitempty"`
+}
+
+// Probe is one of readiness, liveness and startup probe.
+// If none of http, exec, tcp and grpc is set, the check defined in health_check is used.
+type Probe struct {
+	HTTP *HTTPCheck `yaml:"http,omitempty" json:"http,omitempty"`
+	Exec *ExecCheck `yaml:"exec,omitempty" json:"exec,omitempty"`
+	TCP  *TCPCheck  `yaml:"tcp,omitempty" json:"tcp,omitempty"`
+	GRPC *GRPCCheck `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+
+	InitialDelaySeconds int `yaml:"initial_delay_seconds,omitempty" json:"initial_delay_seconds,omitempty"`
+	PeriodSeconds       int `yaml:"period_seconds,omitempty" json:"period_seconds,omitempty"`
+	TimeoutSeconds      int `yaml:"timeout_seconds,omitempty" json:"timeout_seconds,omitempty"`
+	SuccessThreshold    int `yaml:"success_threshold,omitempty" json:"success_threshold,omitempty"`
+	FailureThreshold    int `yaml:"failure_threshold,omitempty" json:"failure_threshold,omitempty"`
+}
+
+// CheckTypes return types of check which are set, such as http, exec, tcp and grpc
+func (hc *HealthCheck) CheckTypes() []string {
+	return checkTypes(hc.HTTP, hc.Exec, hc.TCP, hc.GRPC)
+}
+
+// CheckTypes return types of check which are set, such as http, exec, tcp and grpc
+func (p *Probe) CheckTypes() []string {
+	return checkTypes(p.HTTP, p.Exec, p.TCP, p.GRPC)
+}
+
+func checkTypes(http *HTTPCheck, exec *ExecCheck, tcp *TCPCheck, grpc *GRPCCheck) []string {
+	var types []string
+	if http != nil && (http.Port != 0 || http.Path != "") {
+		types = append(types, "http")
+	}
+	if exec != nil && exec.Cmd != "" {
+		types = append(types, "exec")
+	}
+	if tcp != nil {
+		types = append(types, "tcp")
+	}
+	if grpc != nil {
+		types = append(types, "grpc")
+	}
+	return types
+}
+
 type Resources struct {
 	CPU                      float64           `yaml:"cpu,omitempty" json:"cpu"`
 	Mem                      int               `yaml:"mem,omitempty" json:"mem"`
@@ -682,6 +751,30 @@ type Deployments struct {
 	// Selectors available selectors:
 	// [location]
 	Selectors Selectors `yaml:"selectors,omitempty" json:"selectors,omitempty"`
+	// MaxSurge, MaxUnavailable rolling update strategy, a number or a percentage such as "25%"
+	MaxSurge       *intstr.IntOrString `yaml:"max_surge,omitempty" json:"max_surge,omitempty"`
+	MaxUnavailable *intstr.IntOrString `yaml:"max_unavailable,omitempty" json:"max_unavailable,omitempty"`
+	// MinAvailable the pod disruption budget, a number or a percentage such as "50%"
+	MinAvailable *intstr.IntOrString `yaml:"min_available,omitempty" json:"min_available,omitempty"`
+	// TopologySpread spread replicas across zones or nodes
+	TopologySpread []TopologySpread `yaml:"topology_spread,omitempty" json:"topology_spread,omitempty"`
+}
+
+const (
+	TopologyZone = "zone"
+	TopologyNode = "node"
+
+	WhenUnsatisfiableScheduleAnyway = "schedule_anyway"
+	WhenUnsatisfiableDoNotSchedule  = "do_not_schedule"
+)
+
+type TopologySpread struct {
+	// Topology available: zone, node
+	Topology string `yaml:"topology" json:"topology"`
+	// MaxSkew the max difference of replicas between two topology domains, default 1
+	MaxSkew int `yaml:"max_skew,omitempty" json:"max_skew,omitempty"`
+	// WhenUnsatisfiable available: schedule_anyway(default), do_not_schedule
+	WhenUnsatisfiable string `yaml:"when_unsatisfiable,omitempty" json:"when_unsatisfiable,omitempty"`
 }
 
 type TrafficSecurity struct {
@@ -155,6 +155,11 @@ func (d *DiceYaml) Validate() ValidateError {
 	return verr
 }
 
+// Warnings return problems which don't fail validation but may cause runtime failure.
+func (d *DiceYaml) Warnings() []string {
+	return BasicValidateWarnings(d.obj)
+}
+
 func (d *DiceYaml) Obj() *Object {
 	return CopyObj(d.obj)
 }
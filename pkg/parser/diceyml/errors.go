@@ -15,33 +15,42 @@
 package diceyml
 
 var (
-	notfoundJob                = errortype("not found job in yaml")
-	notfoundService            = errortype("not found service in yaml")
-	invalidService             = errortype("invalid service defined in yaml")
-	emptyServiceJobList        = errortype("empty service and job list")
-	notfoundVersion            = errortype("not found version in yaml")
-	invalidReplicas            = errortype("invalid replicas defined in yaml")
-	invalidPolicy              = errortype("invalid policy defined in yaml")
-	invalidCPU                 = errortype("invalid cpu defined in yaml")
-	invalidMaxCPU              = errortype("invalid max cpu defined in yaml")
-	invalidMaxMem              = errortype("invalid max mem defined in yaml")
-	invalidMem                 = errortype("invalid memory defined in yaml")
-	invalidDisk                = errortype("invalid disk defined in yaml")
-	invalidNetworkMode         = errortype("invalid network mode defined in yaml, must be 'container' or 'host'")
-	invalidBindHostPath        = errortype("invalid binds hostpath, must be absolute path")
-	invalidBindContainerPath   = errortype("invalid binds containerpath, must be absolute path")
-	invalidBindType            = errortype("invalid bind type")
-	invalidPort                = errortype("invalid port defined in yaml")
-	invalidExpose              = errortype("invalid expose defined in yaml")
-	invalidVolume              = errortype("invalid volume defined in yaml")
-	invalidAddonPlan           = errortype("invalid addon plan in yaml")
-	invalidImage               = errortype("invalid image defined in yaml")
-	invalidTrafficSecurityMode = errortype("invalid traffic security mode in yaml, must be 'https'")
-	emptyEndpointDomain        = errortype("empty domain in endpoints")
-	invalidEndpointDomain      = errortype("invalid domain in endpoints")
-	invalidEndpointPath        = errortype("invalid path in endpoints, must start with '/'")
-	invalidEmptyDir            = errortype("invalid emptydir_size defined in yaml")
-	invalidEphemeralStorage    = errortype("invalid ephemeral_storage_size defined in yaml, at least set 1")
+	notfoundJob                  = errortype("not found job in yaml")
+	notfoundService              = errortype("not found service in yaml")
+	invalidService               = errortype("invalid service defined in yaml")
+	emptyServiceJobList          = errortype("empty service and job list")
+	notfoundVersion              = errortype("not found version in yaml")
+	invalidReplicas              = errortype("invalid replicas defined in yaml")
+	invalidPolicy                = errortype("invalid policy defined in yaml")
+	invalidCPU                   = errortype("invalid cpu defined in yaml")
+	invalidMaxCPU                = errortype("invalid max cpu defined in yaml")
+	invalidMaxMem                = errortype("invalid max mem defined in yaml")
+	invalidMem                   = errortype("invalid memory defined in yaml")
+	invalidDisk                  = errortype("invalid disk defined in yaml")
+	invalidNetworkMode           = errortype("invalid network mode defined in yaml, must be 'container' or 'host'")
+	invalidBindHostPath          = errortype("invalid binds hostpath, must be absolute path")
+	invalidBindContainerPath     = errortype("invalid binds containerpath, must be absolute path")
+	invalidBindType              = errortype("invalid bind type")
+	invalidPort                  = errortype("invalid port defined in yaml")
+	invalidExpose                = errortype("invalid expose defined in yaml")
+	invalidVolume                = errortype("invalid volume defined in yaml")
+	invalidAddonPlan             = errortype("invalid addon plan in yaml")
+	invalidImage                 = errortype("invalid image defined in yaml")
+	invalidTrafficSecurityMode   = errortype("invalid traffic security mode in yaml, must be 'https'")
+	emptyEndpointDomain          = errortype("empty domain in endpoints")
+	invalidEndpointDomain        = errortype("invalid domain in endpoints")
+	invalidEndpointPath          = errortype("invalid path in endpoints, must start with '/'")
+	invalidEmptyDir              = errortype("invalid emptydir_size defined in yaml")
+	invalidEphemeralStorage      = errortype("invalid ephemeral_storage_size defined in yaml, at least set 1")
+	invalidHealthCheckPort       = errortype("invalid health_check port defined in yaml")
+	multipleHealthCheckTypes     = errortype("only one of http, exec, tcp and grpc can be set in health_check")
+	emptyProbeCheck              = errortype("no check defined in probe and health_check")
+	invalidProbeSetting          = errortype("invalid probe setting defined in yaml, must not be negative")
+	invalidProbeSuccessThreshold = errortype("success_threshold of liveness and startup probe must be 1")
+	invalidRollingUpdate         = errortype("invalid max_surge or max_unavailable defined in yaml, must be a non-negative number or percentage")
+	zeroRollingUpdate            = errortype("max_surge and max_unavailable can not both be 0")
+	invalidMinAvailable          = errortype("invalid min_available defined in yaml, must be a non-negative number or percentage less than replicas")
+	invalidTopologySpread        = errortype("invalid topology_spread defined in yaml")
 )
 
 type errortype string
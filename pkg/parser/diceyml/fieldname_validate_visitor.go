@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -148,13 +149,24 @@ func (o *FieldnameValidateVisitor) VisitDeployments(v DiceYmlVisitor, obj *Deplo
 	for k := range dep {
 		switch i := k.(type) {
 		case string:
-			if !contain(i, []string{"workload", "replicas", "policies", "labels", "selectors"}) {
-				o.collectErrors[yamlHeaderRegexWithUpperHeader([]string{o.currentServiceName, "deployments"}, i)] = fmt.Errorf("[%s]/[deployments] field '%s' not one of [replicas, policies, labels, selectors]", o.currentServiceName, i)
+			if !contain(i, []string{"workload", "replicas", "policies", "labels", "selectors", "max_surge", "max_unavailable", "min_available", "topology_spread"}) {
+				o.collectErrors[yamlHeaderRegexWithUpperHeader([]string{o.currentServiceName, "deployments"}, i)] = fmt.Errorf("[%s]/[deployments] field '%s' not one of [replicas, policies, labels, selectors, max_surge, max_unavailable, min_available, topology_spread]", o.currentServiceName, i)
 			}
 		default:
 			o.collectErrors[yamlHeaderRegex("_"+strconv.Itoa(len(o.collectErrors)))] = fmt.Errorf("[%s]/[deployments] %v not string type", o.currentServiceName, k)
 		}
 	}
+
+	spreads, ok := dep["topology_spread"].([]interface{})
+	if !ok {
+		return
+	}
+	for _, spread := range spreads {
+		if m, ok := spread.(map[interface{}]interface{}); ok {
+			o.validateFieldnames([]string{o.currentServiceName, "deployments", "topology_spread"}, m,
+				[]string{"topology", "max_skew", "when_unsatisfiable"})
+		}
+	}
 }
 
 func (o *FieldnameValidateVisitor) VisitHealthCheck(v DiceYmlVisitor, obj *HealthCheck) {
@@ -162,14 +174,49 @@ func (o *FieldnameValidateVisitor) VisitHealthCheck(v DiceYmlVisitor, obj *Healt
 	if !ok {
 		return
 	}
-	for k := range hc {
+	o.validateFieldnames([]string{o.currentServiceName, "health_check"}, hc,
+		[]string{"http", "exec", "tcp", "grpc", "readiness", "liveness", "startup"})
+	o.validateCheckFieldnames([]string{o.currentServiceName, "health_check"}, hc)
+
+	// http and exec of health_check are validated by VisitHTTPCheck and VisitExecCheck
+	for _, name := range []string{"readiness", "liveness", "startup"} {
+		probe, ok := hc[name].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		header := []string{o.currentServiceName, "health_check", name}
+		o.validateFieldnames(header, probe, []string{"http", "exec", "tcp", "grpc", "initial_delay_seconds",
+			"period_seconds", "timeout_seconds", "success_threshold", "failure_threshold"})
+		o.validateCheckFieldnames(header, probe)
+		if http, ok := probe["http"].(map[interface{}]interface{}); ok {
+			o.validateFieldnames(append(header, "http"), http, []string{"port", "path", "duration"})
+		}
+		if exec, ok := probe["exec"].(map[interface{}]interface{}); ok {
+			o.validateFieldnames(append(header, "exec"), exec, []string{"cmd", "duration"})
+		}
+	}
+}
+
+// validateCheckFieldnames validate fields of tcp and grpc check
+func (o *FieldnameValidateVisitor) validateCheckFieldnames(header []string, check map[interface{}]interface{}) {
+	if tcp, ok := check["tcp"].(map[interface{}]interface{}); ok {
+		o.validateFieldnames(append(header, "tcp"), tcp, []string{"port", "duration"})
+	}
+	if grpc, ok := check["grpc"].(map[interface{}]interface{}); ok {
+		o.validateFieldnames(append(header, "grpc"), grpc, []string{"port", "service", "duration"})
+	}
+}
+
+func (o *FieldnameValidateVisitor) validateFieldnames(header []string, m map[interface{}]interface{}, fields []string) {
+	path := "[" + strings.Join(header[1:], "]/[") + "]"
+	for k := range m {
 		switch i := k.(type) {
 		case string:
-			if !contain(i, []string{"http", "exec"}) {
-				o.collectErrors[yamlHeaderRegexWithUpperHeader([]string{o.currentServiceName, "health_check"}, i)] = fmt.Errorf("[%s]/[health_check] field '%s' not one of [http, exec]", o.currentServiceName, i)
+			if !contain(i, fields) {
+				o.collectErrors[yamlHeaderRegexWithUpperHeader(header, i)] = fmt.Errorf("[%s]/%s field '%s' not one of [%s]", header[0], path, i, strings.Join(fields, ", "))
 			}
 		default:
-			o.collectErrors[yamlHeaderRegex("_"+strconv.Itoa(len(o.collectErrors)))] = fmt.Errorf("[%s]/[health_check] %v not string type", o.currentServiceName, k)
+			o.collectErrors[yamlHeaderRegex("_"+strconv.Itoa(len(o.collectErrors)))] = fmt.Errorf("[%s]/%s %v not string type", header[0], path, k)
 		}
 	}
 }
@@ -74,3 +74,58 @@ func TestFieldnameValidate(t *testing.T) {
 	es := FieldnameValidate(d.Obj(), []byte(fieldname_validate_yml))
 	assert.Equal(t, 4, len(es))
 }
+
+var fieldname_validate_healthcheck_yml = `version: 2.0
+services:
+  grpc-server:
+    ports:
+    - 9090
+    resources:
+      cpu: 0.1
+      mem: 256
+    health_check:
+      grpc:
+        port: 9090
+        services: helloworld.Greeter	# err: services
+      readiness:
+        tcp:
+          port: 9090
+          path: /health					# err: path
+        initial_delay: 10				# err: initial_delay
+      startup:
+        failure_threshold: 30
+        exec:
+          cmd: echo 1
+`
+
+func TestFieldnameValidate_HealthCheck(t *testing.T) {
+	d, err := New([]byte(fieldname_validate_healthcheck_yml), false)
+	assert.Nil(t, err)
+	es := FieldnameValidate(d.Obj(), []byte(fieldname_validate_healthcheck_yml))
+	assert.Equal(t, 3, len(es), "%v", es)
+}
+
+var fieldname_validate_deployments_yml = `version: 2.0
+services:
+  web:
+    ports:
+    - 8080
+    resources:
+      cpu: 0.1
+      mem: 256
+    deployments:
+      replicas: 2
+      max_surge: 1
+      min_available: 50%
+      max_unavailables: 1					# err: max_unavailables
+      topology_spread:
+      - topology: zone
+        skew: 1								# err: skew
+`
+
+func TestFieldnameValidate_Deployments(t *testing.T) {
+	d, err := New([]byte(fieldname_validate_deployments_yml), false)
+	assert.Nil(t, err)
+	es := FieldnameValidate(d.Obj(), []byte(fieldname_validate_deployments_yml))
+	assert.Equal(t, 2, len(es), "%v", es)
+}
@@ -102,6 +102,19 @@ func (o *MergeEnvVisitor) VisitDeployments(v DiceYmlVisitor, obj *Deployments) {
 	}
 	overrideIfNotZero(o.envObj.Services[o.currentService].Deployments.Policies, &obj.Policies)
 	overrideIfNotZero(o.envObj.Services[o.currentService].Deployments.Labels, &obj.Labels)
+	envDeployments := o.envObj.Services[o.currentService].Deployments
+	if envDeployments.MaxSurge != nil {
+		obj.MaxSurge = envDeployments.MaxSurge
+	}
+	if envDeployments.MaxUnavailable != nil {
+		obj.MaxUnavailable = envDeployments.MaxUnavailable
+	}
+	if envDeployments.MinAvailable != nil {
+		obj.MinAvailable = envDeployments.MinAvailable
+	}
+	if len(envDeployments.TopologySpread) > 0 {
+		obj.TopologySpread = envDeployments.TopologySpread
+	}
 }
 
 func (o *MergeEnvVisitor) VisitHTTPCheck(v DiceYmlVisitor, obj *HTTPCheck) {
@@ -133,6 +146,32 @@ func (o *MergeEnvVisitor) VisitExecCheck(v DiceYmlVisitor, obj *ExecCheck) {
 	overrideIfNotZero(o.envObj.Services[o.currentService].HealthCheck.Exec.Duration, &obj.Duration)
 }
 
+func (o *MergeEnvVisitor) VisitHealthCheck(v DiceYmlVisitor, obj *HealthCheck) {
+	if o.currentService == "" {
+		panic("should not be empty")
+	}
+	if s, ok := o.envObj.Services[o.currentService]; !ok || s == nil {
+		return
+	}
+	// http and exec are merged by VisitHTTPCheck and VisitExecCheck
+	envHealthCheck := o.envObj.Services[o.currentService].HealthCheck
+	if envHealthCheck.TCP != nil {
+		obj.TCP = envHealthCheck.TCP
+	}
+	if envHealthCheck.GRPC != nil {
+		obj.GRPC = envHealthCheck.GRPC
+	}
+	if envHealthCheck.Readiness != nil {
+		obj.Readiness = envHealthCheck.Readiness
+	}
+	if envHealthCheck.Liveness != nil {
+		obj.Liveness = envHealthCheck.Liveness
+	}
+	if envHealthCheck.Startup != nil {
+		obj.Startup = envHealthCheck.Startup
+	}
+}
+
 func (o *MergeEnvVisitor) VisitAddOns(v DiceYmlVisitor, obj *AddOns) {
 	if len(o.envObj.AddOns) == 0 {
 		return
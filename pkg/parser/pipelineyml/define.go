@@ -27,6 +27,7 @@ import (
 )
 
 const (
+	Version1dot2 = "1.2"
 	Version1dot1 = "1.1"
 	Version1dot0 = "1.0"
 	Version1     = "1"
@@ -45,10 +46,19 @@ type Spec struct {
 	Envs map[string]string `yaml:"envs,omitempty"`
 
 	Cron            string           `yaml:"cron,omitempty"`
+	CronTimezone    string           `yaml:"cron_timezone,omitempty"` // IANA 时区，例如 Asia/Shanghai，为空时使用服务端时区
 	CronCompensator *CronCompensator `yaml:"cron_compensator,omitempty"`
 
+	// Concurrency 声明流水线的并发组，同一并发组内同时只会运行一条流水线
+	Concurrency *Concurrency `yaml:"concurrency,omitempty"`
+
 	Stages []*Stage `yaml:"stages"`
 
+	// Actions 从 1.2 版本开始支持，与 stages 二选一。
+	// 声明 actions 时，不再需要 stages，依赖关系完全通过 action 的 needs 声明，
+	// parser 会根据 needs 将 actions 分层转换为 stages。
+	Actions []typedActionMap `yaml:"actions,omitempty"`
+
 	Params []*PipelineParam `yaml:"params,omitempty"` // 流水线输入
 
 	Outputs []*PipelineOutput `yaml:"outputs,omitempty"` // 流水线输出
@@ -64,6 +74,15 @@ type Spec struct {
 	// allActions represents all actions from all stages
 	allActions map[ActionAlias]*indexedAction
 
+	// stagesFromActions represents stages are generated from actions by needs
+	stagesFromActions bool
+
+	// matrixExpanded represents actions with matrix strategy are expanded
+	matrixExpanded bool
+
+	// notFoundSecrets represents secrets referenced by yml but not provided
+	notFoundSecrets []string
+
 	// defines the breakpoint config for tasks on global pipeline
 	Breakpoint *pb.Breakpoint `yaml:"breakpoint,omitempty"`
 }
@@ -97,8 +116,9 @@ type StorageConfig struct {
 }
 
 type TriggerConfig struct {
-	Push  *PushTrigger  `yaml:"push,omitempty"`
-	Merge *MergeTrigger `yaml:"merge,omitempty"`
+	Push     *PushTrigger               `yaml:"push,omitempty"`
+	Merge    *MergeTrigger              `yaml:"merge,omitempty"`
+	Pipeline []*UpstreamPipelineTrigger `yaml:"pipeline,omitempty"`
 }
 
 type PushTrigger struct {
@@ -110,6 +130,20 @@ type MergeTrigger struct {
 	Branches []string `yaml:"branches,omitempty"`
 }
 
+// UpstreamPipelineTrigger 上游流水线结束后触发当前流水线
+type UpstreamPipelineTrigger struct {
+	// Source 上游流水线来源，为空时与当前流水线相同
+	Source string `yaml:"source,omitempty"`
+	// Name 上游流水线 pipelineYmlName，支持只写末尾路径，如 pipeline.yml
+	Name string `yaml:"name,omitempty"`
+	// Branches 上游流水线分支，为空时不限制
+	Branches []string `yaml:"branches,omitempty"`
+	// Status 上游流水线结束状态，为空时只在成功时触发
+	Status []string `yaml:"status,omitempty"`
+	// Params 当前流水线参数名 -> 上游流水线 output 名
+	Params map[string]string `yaml:"params,omitempty"`
+}
+
 type indexedAction struct {
 	*Action
 	stageIndex int
@@ -162,27 +196,31 @@ type Action struct {
 
 	Policy *Policy `yaml:"policy,omitempty"` // action execution strategy
 
+	Strategy *Strategy `yaml:"strategy,omitempty"` // matrix 展开策略
+
 	SnippetConfig *SnippetConfig `yaml:"snippet_config,omitempty"` // snippet 类型的 action 的配置
 
 	If string `yaml:"if,omitempty"` // 条件执行
 
 	Disable bool `yaml:"disable,omitempty"` // make task disable or enable
 
-	// TODO 在未来版本中，可能去除 stage，依赖关系则必须通过 Needs 来声明。
-	// 目前不开放给用户使用。由 parser 自动赋值。
-	// Needs 显式声明依赖的 actions。隐式依赖关系是下一个 stage 依赖之前所有 stage 里的 action。
+	// Needs 显式声明依赖的 actions，从 1.2 版本开始开放给用户使用。
+	// 隐式依赖关系是下一个 stage 依赖之前所有 stage 里的 action，由 parser 自动赋值。
 	// Needs 可以绕开 stage 限制，以 DAG 方式声明依赖关系。
 	// Needs 一旦声明，只包含声明的值，不会注入其他依赖。
-	Needs []ActionAlias `yaml:"-"`
+	Needs []ActionAlias `yaml:"needs,omitempty"`
 
 	// TODO 该字段目前是兼容字段。
 	// 在 1.1 版本中，Needs = NeedNamespaces
 	// 在 1.0 版本中，Needs <= NeedNamespaces
 	// 目前不开放给用户使用。由 parser 自动赋值。
 	// NeedNamespaces 显式声明依赖的 namespaces。隐式依赖关系是下一个 stage 依赖之前所有 stage 的 namespaces。
-	// NeedNamespaces 一旦声明，只包含声明的值，不会注入其他依赖。
+	// 声明了 Needs 时，NeedNamespaces 为 Needs 中所有 action 的 namespaces。
 	NeedNamespaces []string `yaml:"-"`
 
+	// needsInjected 表示 Needs 是由 parser 根据 stage 顺序注入的，而不是用户声明的
+	needsInjected bool
+
 	// TODO 该字段目前是兼容字段，在未来版本中可以通过该字段扩展上下文。
 	// 目前不开放给用户使用。由 parser 自动赋值。
 	// Namespaces 显式声明 action 的命名空间，每个命名空间在流水线上下文目录下是唯一的，可以是目录或者文件。
@@ -194,8 +232,51 @@ type Action struct {
 	Breakpoint *pb.Breakpoint `yaml:"breakpoint,omitempty"`
 }
 
+// Concurrency declares the concurrency group of pipeline.
+type Concurrency struct {
+	// Group 并发组，支持表达式，例如：${{ git.branch }}
+	Group string `yaml:"group"`
+	// CancelInProgress 为 true 时，新流水线运行前取消同一并发组内运行中及排队中的流水线；
+	// 为 false 时，新流水线在同一并发组内排队等待
+	CancelInProgress bool `yaml:"cancel-in-progress,omitempty"`
+}
+
 type Policy struct {
 	Type apistructs.PolicyType `yaml:"type,omitempty"`
+	// Retry 仅在 type 为 retry 时生效，未声明的字段使用默认值
+	Retry *apistructs.TaskRetryPolicy `yaml:"retry,omitempty"`
+}
+
+// Validate validates policy of action.
+func (p *Policy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.Retry != nil && p.Type != apistructs.RetryPolicyType {
+		return errors.Errorf("policy retry is only supported by policy type %s", apistructs.RetryPolicyType)
+	}
+	return p.Retry.Validate()
+}
+
+// Strategy expands one action into tasks over the cartesian product of matrix values.
+type Strategy struct {
+	// Matrix 声明展开的维度及取值，展开后的 task alias 为 {alias}-{index}，index 从 1 开始
+	Matrix map[string][]interface{} `yaml:"matrix,omitempty"`
+	// MaxParallel 同一 matrix 下最大并行数，0 表示不限制
+	MaxParallel int `yaml:"max-parallel,omitempty"`
+	// FailFast 同一 matrix 下某个 task 失败后，是否不再调度其他 task，默认为 true
+	FailFast *bool `yaml:"fail-fast,omitempty"`
+
+	// 以下字段由 parser 展开 matrix 时自动赋值
+	// Group 展开后的 task 所属的原始 action alias
+	Group ActionAlias `yaml:"group,omitempty"`
+	// Values 展开后的 task 对应的 matrix 取值
+	Values map[string]interface{} `yaml:"values,omitempty"`
+}
+
+// IsFailFast return whether fail fast, default is true.
+func (s *Strategy) IsFailFast() bool {
+	return s == nil || s.FailFast == nil || *s.FailFast
 }
 
 type SnippetConfig struct {
@@ -237,8 +318,13 @@ type ActionCache struct {
 	// 缓存生成的 key 或者是用户指定的 key
 	// 用户指定的话 需要 {{basePath}}/路径/{{endPath}} 来自定义 key
 	// 用户没有指定 key 有一定的生成规则, 具体生成规则看 prepare.go 的 setActionCacheStorageAndBinds 方法
+	// 不以 {{basePath}} 开头的 key 按内容寻址存储，支持 ${{ hashFiles('go.sum', '**/package-lock.json') }} 表达式
 	Key  string `yaml:"key,omitempty"`
 	Path string `yaml:"path,omitempty"` // 指定那个目录被缓存, 只能是由 / 开始的绝对路径
+	// key 未精确命中时，按顺序使用前缀匹配最新的缓存
+	RestoreKeys []string `yaml:"restore_keys,omitempty"`
+	MaxSizeMB   int64    `yaml:"max_size_mb,omitempty"` // 单个 key 的缓存大小上限，超过则不保存
+	TTL         string   `yaml:"ttl,omitempty"`         // 缓存最后一次使用后的保留时间，例如 168h
 }
 
 type ActionType string
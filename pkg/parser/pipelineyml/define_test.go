@@ -231,3 +231,45 @@ func TestConvert2PB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(hook.Labels))
 }
+
+func TestPolicy_Validate(t *testing.T) {
+	y, err := New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          policy:
+            type: retry
+            retry:
+              max_attempts: 3
+              on: [oom, image-pull]
+`))
+	assert.NoError(t, err)
+	action, err := GetAction(y.Spec(), "custom-script")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, action.Policy.Retry.MaxAttempts)
+
+	_, err = New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          policy:
+            type: new-run
+            retry:
+              max_attempts: 3
+`))
+	assert.Error(t, err)
+
+	_, err = New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          policy:
+            type: retry
+            retry:
+              on: [disk]
+`))
+	assert.Error(t, err)
+}
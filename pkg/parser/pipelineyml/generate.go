@@ -25,6 +25,7 @@ import (
 // GenerateYml 根据 spec 重新生成 yaml 文本，一般用于对 spec 进行调整后重新生成 yaml 文本
 func GenerateYml(s *Spec) ([]byte, error) {
 	polishNamespaces(s)
+	defer hideInjectedNeeds(s)()
 	var newYmlBuf bytes.Buffer
 	encoder := yaml.NewEncoder(&newYmlBuf)
 	encoder.SetIndent(1)
@@ -48,3 +49,26 @@ func polishNamespaces(s *Spec) {
 		}
 	}
 }
+
+// hideInjectedNeeds 隐藏 parser 根据 stage 顺序注入的 needs，只保留用户声明的 needs，
+// 否则重新生成的 yaml 在调整 stage 顺序后，注入的 needs 会覆盖 stage 的依赖关系。
+// 返回恢复注入的 needs 的函数。
+func hideInjectedNeeds(s *Spec) (restore func()) {
+	injected := make(map[*Action][]ActionAlias)
+	for _, stage := range s.Stages {
+		for _, typedAction := range stage.Actions {
+			for _, action := range typedAction {
+				if action == nil || !action.needsInjected {
+					continue
+				}
+				injected[action] = action.Needs
+				action.Needs = nil
+			}
+		}
+	}
+	return func() {
+		for action, needs := range injected {
+			action.Needs = needs
+		}
+	}
+}
@@ -32,6 +32,7 @@ func ConvertGraphPipelineYmlContent(data []byte) ([]byte, error) {
 	s.Version = frontendYmlSpec.Version
 	s.Envs = frontendYmlSpec.Envs
 	s.Cron = frontendYmlSpec.Cron
+	s.CronTimezone = frontendYmlSpec.CronTimezone
 	if frontendYmlSpec.CronCompensator != nil {
 		s.CronCompensator = &CronCompensator{
 			Enable:               frontendYmlSpec.CronCompensator.Enable,
@@ -77,17 +78,35 @@ func ConvertGraphPipelineYmlContent(data []byte) ([]byte, error) {
 
 			for _, cache := range frontendAction.Caches {
 				maps[ActionType(frontendAction.Type)].Caches = append(maps[ActionType(frontendAction.Type)].Caches, ActionCache{
-					Key:  cache.Key,
-					Path: cache.Path,
+					Key:         cache.Key,
+					Path:        cache.Path,
+					RestoreKeys: cache.RestoreKeys,
+					MaxSizeMB:   cache.MaxSizeMB,
+					TTL:         cache.TTL,
 				})
 			}
 
 			if frontendAction.Policy != nil {
 				maps[ActionType(frontendAction.Type)].Policy = &Policy{
-					Type: frontendAction.Policy.Type,
+					Type:  frontendAction.Policy.Type,
+					Retry: frontendAction.Policy.Retry,
 				}
 			}
 
+			if frontendAction.Strategy != nil {
+				maps[ActionType(frontendAction.Type)].Strategy = &Strategy{
+					Matrix:      frontendAction.Strategy.Matrix,
+					MaxParallel: frontendAction.Strategy.MaxParallel,
+					FailFast:    frontendAction.Strategy.FailFast,
+					Group:       ActionAlias(frontendAction.Strategy.Group),
+					Values:      frontendAction.Strategy.Values,
+				}
+			}
+
+			for _, need := range frontendAction.Needs {
+				maps[ActionType(frontendAction.Type)].Needs = append(maps[ActionType(frontendAction.Type)].Needs, ActionAlias(need))
+			}
+
 			actions = append(actions, maps)
 		}
 		s.Stages = append(s.Stages, &Stage{Actions: actions})
@@ -151,7 +170,8 @@ func ConvertToGraphPipelineYml(data []byte) (*pb.PipelineYml, error) {
 	if pipelineYml.Spec().On != nil {
 		merge := pipelineYml.Spec().On.Merge
 		push := pipelineYml.Spec().On.Push
-		if merge != nil || push != nil {
+		upstreams := pipelineYml.Spec().On.Pipeline
+		if merge != nil || push != nil || len(upstreams) > 0 {
 			on = &pb.TriggerConfig{}
 			if merge != nil {
 				var branches []string
@@ -173,6 +193,18 @@ func ConvertToGraphPipelineYml(data []byte) (*pb.PipelineYml, error) {
 					Tags:     tags,
 				}
 			}
+			for _, upstream := range upstreams {
+				if upstream == nil {
+					continue
+				}
+				on.Pipeline = append(on.Pipeline, &pb.UpstreamPipelineTrigger{
+					Source:   upstream.Source,
+					Name:     upstream.Name,
+					Branches: upstream.Branches,
+					Status:   upstream.Status,
+					Params:   upstream.Params,
+				})
+			}
 		}
 	}
 
@@ -189,14 +221,15 @@ func ConvertToGraphPipelineYml(data []byte) (*pb.PipelineYml, error) {
 	}
 
 	result := &pb.PipelineYml{
-		Version:     pipelineYml.Spec().Version,
-		Envs:        pipelineYml.Spec().Envs,
-		Cron:        pipelineYml.Spec().Cron,
-		NeedUpgrade: pipelineYml.needUpgrade,
-		Params:      pipelineParams,
-		Outputs:     pipelineOutputs,
-		On:          on,
-		Triggers:    pipelineYml.Spec().Triggers,
+		Version:      pipelineYml.Spec().Version,
+		Envs:         pipelineYml.Spec().Envs,
+		Cron:         pipelineYml.Spec().Cron,
+		CronTimezone: pipelineYml.Spec().CronTimezone,
+		NeedUpgrade:  pipelineYml.needUpgrade,
+		Params:       pipelineParams,
+		Outputs:      pipelineOutputs,
+		On:           on,
+		Triggers:     pipelineYml.Spec().Triggers,
 		CronCompensator: func() *pb.CronCompensator {
 			if pipelineYml.Spec().CronCompensator == nil {
 				return nil
@@ -259,8 +292,11 @@ func ConvertToGraphPipelineYml(data []byte) (*pb.PipelineYml, error) {
 					var resultActionCaches []apistructs.ActionCache
 					for _, v := range caches {
 						resultActionCaches = append(resultActionCaches, apistructs.ActionCache{
-							Path: v.Path,
-							Key:  v.Key,
+							Path:        v.Path,
+							Key:         v.Key,
+							RestoreKeys: v.RestoreKeys,
+							MaxSizeMB:   v.MaxSizeMB,
+							TTL:         v.TTL,
 						})
 					}
 					resultAction.Caches = resultActionCaches
@@ -272,9 +308,23 @@ func ConvertToGraphPipelineYml(data []byte) (*pb.PipelineYml, error) {
 
 				if action.Policy != nil {
 					resultAction.Policy = &apistructs.Policy{
-						Type: action.Policy.Type,
+						Type:  action.Policy.Type,
+						Retry: action.Policy.Retry,
 					}
 				}
+				if action.Strategy != nil {
+					resultAction.Strategy = &apistructs.ActionStrategy{
+						Matrix:      action.Strategy.Matrix,
+						MaxParallel: action.Strategy.MaxParallel,
+						FailFast:    action.Strategy.FailFast,
+						Group:       action.Strategy.Group.String(),
+						Values:      action.Strategy.Values,
+					}
+				}
+				// only declared needs are shown, injected needs can be calculated by stages
+				if !action.needsInjected && len(action.Needs) > 0 {
+					resultAction.Needs = aliasesToStrings(action.Needs)
+				}
 				structValue, err := resultAction.Convert2StructValue()
 				if err != nil {
 					return nil, err
@@ -59,7 +59,7 @@ func (y *PipelineYml) parse(b []byte, errs ...string) (err error) {
 
 	// 1) 以 Spec 结构解析
 	// 2) 尝试以 apistructs.PipelineYml 结构解析，该结构用户前端图形化展示
-	case Version1dot1:
+	case Version1dot1, Version1dot2:
 
 		// ParseSpec
 		decoder := yaml.NewDecoder(bytes.NewBuffer(b))
@@ -67,7 +67,7 @@ func (y *PipelineYml) parse(b []byte, errs ...string) (err error) {
 		if err := decoder.Decode(&y.s); err == nil {
 			return nil
 		} else {
-			errs = append(errs, errors.Errorf("parsed by 1.1 spec, err: %v", err).Error())
+			errs = append(errs, errors.Errorf("parsed by %s spec, err: %v", version, err).Error())
 		}
 
 		// Parse apistructs.PipelineYml
@@ -76,7 +76,7 @@ func (y *PipelineYml) parse(b []byte, errs ...string) (err error) {
 			y.data = convertedPipelineYmlContent
 			return y.parse(convertedPipelineYmlContent, errs...)
 		} else {
-			errs = append(errs, errors.Errorf("parsed by 1.1 spec(apistructs), err: %v", err).Error())
+			errs = append(errs, errors.Errorf("parsed by %s spec(apistructs), err: %v", version, err).Error())
 			return err
 		}
 
@@ -91,6 +91,6 @@ func (y *PipelineYml) parse(b []byte, errs ...string) (err error) {
 		return nil
 
 	default:
-		return errors.Errorf("invalid version: %s, currently support: 1.0, 1.1", version)
+		return errors.Errorf("invalid version: %s, currently support: 1.0, 1.1, 1.2", version)
 	}
 }
@@ -98,6 +98,21 @@ func New(b []byte, ops ...Option) (_ *PipelineYml, err error) {
 	}
 
 	y.s.Accept(NewVersionVisitor())
+	// needsVisitor 校验用户声明的 needs；若声明的是 actions，则按 needs 分层转换为 stages，
+	// 并重新生成 yaml 文本，保证后续基于文本渲染的 visitor 看到的都是 stages
+	// matrixVisitor 将声明了 strategy.matrix 的 action 展开为多个 action，同样需要重新生成 yaml 文本
+	errNum := len(y.s.errs)
+	y.s.Accept(NewNeedsVisitor())
+	y.s.Accept(NewMatrixVisitor())
+	if len(y.s.errs) > errNum {
+		return nil, y.s.mergeErrors()
+	}
+	if y.s.stagesFromActions || y.s.matrixExpanded {
+		y.data, err = GenerateYml(y.s)
+		if err != nil {
+			panic(err)
+		}
+	}
 	y.s.Accept(NewEnvVisitor(y.envs))
 	// secretVisitor 需要在 stageVisitor 之前执行，先执行文本替换，再按需 JSON(params)
 	// 否则，若先执行 stageVisitor 并 JSON(params)，然后再文本替换，替换后的 json 可能是无效的
@@ -156,6 +171,8 @@ func New(b []byte, ops ...Option) (_ *PipelineYml, err error) {
 
 	y.s.Accept(NewCronVisitor())
 	y.s.Accept(NewTimeoutVisitor())
+	y.s.Accept(NewConcurrencyVisitor())
+	y.s.Accept(NewUpstreamPipelineTriggerVisitor())
 
 	if len(y.aliasToCheckRefOp) > 0 {
 		y.s.Accept(NewRefOpVisitor(y.aliasToCheckRefOp, y.refs, y.outputs, y.allowMissingCustomScriptOutputs, y.globalSnippetConfigLabels))
@@ -265,6 +282,11 @@ func (y *PipelineYml) Warns() []string {
 	return y.s.warns
 }
 
+// NotFoundSecrets return secrets referenced by yml but not provided, only available when WithSecrets.
+func (y *PipelineYml) NotFoundSecrets() []string {
+	return y.s.notFoundSecrets
+}
+
 func (y *PipelineYml) NeedUpgrade() bool {
 	return y.needUpgrade
 }
@@ -0,0 +1,305 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/pkg/expression"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml/pexpr"
+	"github.com/erda-project/erda/pkg/strutil"
+)
+
+// PlanCondition represents the result of action `if` condition evaluated when plan.
+type PlanCondition string
+
+const (
+	PlanConditionNone    PlanCondition = ""        // 未声明条件，task 会执行
+	PlanConditionTrue    PlanCondition = "true"    // 条件成立，task 会执行
+	PlanConditionFalse   PlanCondition = "false"   // 条件不成立，task 会被跳过
+	PlanConditionInvalid PlanCondition = "invalid" // 条件表达式错误，task 会失败
+	PlanConditionUnknown PlanCondition = "unknown" // 条件依赖 outputs 等运行时的值，无法提前计算
+)
+
+// maxPlanSnippetDepth 嵌套 snippet 展开的最大层数，避免循环引用
+const maxPlanSnippetDepth = 5
+
+// PlanSecretMask 计划中 secret 的值统一渲染为掩码，plan 只暴露 secret 的名字
+const PlanSecretMask = "******"
+
+// SnippetYmlLoader loads pipeline yml content of snippet action.
+type SnippetYmlLoader func(alias string, config SnippetConfig) ([]byte, error)
+
+// Plan is the fully resolved execution plan of pipeline yml.
+type Plan struct {
+	Name    string            `json:"name,omitempty"`
+	Version string            `json:"version"`
+	Envs    map[string]string `json:"envs,omitempty"`
+	// Params 合并了运行时输入和默认值后的流水线入参
+	Params map[string]string `json:"params,omitempty"`
+	// MissingParams 必填但没有输入值和默认值的入参
+	MissingParams []string `json:"missingParams,omitempty"`
+	// MissingSecrets 引用但不存在的 secrets
+	MissingSecrets []string `json:"missingSecrets,omitempty"`
+	// Cron 定时配置及在 CronTimezone 时区下接下来的触发时间
+	Cron          string       `json:"cron,omitempty"`
+	CronTimezone  string       `json:"cronTimezone,omitempty"`
+	NextCronTimes []time.Time  `json:"nextCronTimes,omitempty"`
+	Stages        []*PlanStage `json:"stages"`
+	Warns         []string     `json:"warns,omitempty"`
+}
+
+type PlanStage struct {
+	Tasks []*PlanTask `json:"tasks"`
+}
+
+type PlanTask struct {
+	Alias     string                       `json:"alias"`
+	Type      string                       `json:"type"`
+	Version   string                       `json:"version,omitempty"`
+	Image     string                       `json:"image,omitempty"`
+	Needs     []string                     `json:"needs,omitempty"`
+	Params    map[string]interface{}       `json:"params,omitempty"`
+	Commands  interface{}                  `json:"commands,omitempty"`
+	Timeout   int64                        `json:"timeout,omitempty"`
+	Disable   bool                         `json:"disable,omitempty"`
+	Resources Resources                    `json:"resources"`
+	Policy    *Policy                      `json:"policy,omitempty"`
+	Loop      *apistructs.PipelineTaskLoop `json:"loop,omitempty"`
+
+	If            string        `json:"if,omitempty"`
+	Condition     PlanCondition `json:"condition,omitempty"`
+	ConditionDesc string        `json:"conditionDesc,omitempty"`
+
+	// UnresolvedPlaceholders 运行时才能渲染的占位符，例如 ${{ outputs.xxx.key }}
+	UnresolvedPlaceholders []string `json:"unresolvedPlaceholders,omitempty"`
+
+	// Snippet 展开后的嵌套流水线计划
+	Snippet      *Plan  `json:"snippet,omitempty"`
+	SnippetError string `json:"snippetError,omitempty"`
+}
+
+// MakePlan parses pipeline yml with options and returns the resolved execution plan without running anything.
+// Snippet actions are expanded recursively by loader; they are left unexpanded if loader is nil.
+func MakePlan(b []byte, loader SnippetYmlLoader, ops ...Option) (*Plan, error) {
+	return makePlan(b, loader, 0, ops...)
+}
+
+func makePlan(b []byte, loader SnippetYmlLoader, depth int, ops ...Option) (*Plan, error) {
+	// 收集 options 中传入的 envs、secrets 和运行时入参
+	opt := PipelineYml{actionTypeMapping: make(map[string]string)}
+	for _, op := range ops {
+		op(&opt)
+	}
+
+	// 先解析一次获取声明的入参，计算默认值
+	declared, err := New(b)
+	if err != nil {
+		return nil, err
+	}
+	params, missingParams := mergePlanParams(declared.Spec().Params, opt.runParams)
+
+	// secrets 只用于判断是否存在，值统一替换为掩码，避免明文出现在渲染结果中
+	secrets := make(map[string]string, len(opt.secrets))
+	for k := range opt.secrets {
+		secrets[k] = PlanSecretMask
+	}
+	var runParams []apistructs.PipelineRunParamWithValue
+	for name, value := range params {
+		runParams = append(runParams, apistructs.PipelineRunParamWithValue{PipelineRunParam: apistructs.PipelineRunParam{Name: name, Value: value}})
+	}
+	y, err := New(b, append(ops, WithSecrets(secrets), WithRunParams(runParams))...)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := Plan{
+		Name:           y.Spec().Name,
+		Version:        y.Spec().Version,
+		Envs:           y.Spec().Envs,
+		Params:         make(map[string]string, len(params)),
+		MissingParams:  missingParams,
+		MissingSecrets: y.NotFoundSecrets(),
+		Cron:           y.Spec().Cron,
+		CronTimezone:   y.Spec().CronTimezone,
+		Warns:          y.Warns(),
+	}
+	if plan.Cron != "" {
+		plan.NextCronTimes, _ = ListNextCronTime(plan.Cron, WithCronTimezone(plan.CronTimezone))
+	}
+	// 可以提前渲染的占位符: params 和 configs
+	// conditionParams 不包含 configs，依赖 secret 的条件无法提前计算
+	placeholderParams := make(map[string]string, len(params)+len(secrets))
+	conditionParams := make(map[string]string, len(params))
+	for name, value := range params {
+		plan.Params[name] = paramValueToString(value)
+		placeholderParams[expression.Params+"."+name] = plan.Params[name]
+		conditionParams[expression.Params+"."+name] = plan.Params[name]
+	}
+	for k, v := range secrets {
+		placeholderParams[expression.Configs+"."+k] = v
+	}
+
+	for _, stage := range y.Spec().Stages {
+		planStage := &PlanStage{}
+		for _, typedAction := range stage.Actions {
+			for _, action := range typedAction {
+				planStage.Tasks = append(planStage.Tasks, makePlanTask(action, placeholderParams, conditionParams, loader, depth, opt.envs, secrets))
+			}
+		}
+		plan.Stages = append(plan.Stages, planStage)
+	}
+	return &plan, nil
+}
+
+func makePlanTask(action *Action, placeholderParams, conditionParams map[string]string, loader SnippetYmlLoader, depth int,
+	envs, secrets map[string]string) *PlanTask {
+	task := &PlanTask{
+		Alias:     action.Alias.String(),
+		Type:      action.Type.String(),
+		Version:   action.Version,
+		Needs:     aliasesToStrings(action.Needs),
+		Timeout:   action.Timeout,
+		Disable:   action.Disable,
+		Resources: action.Resources,
+		Policy:    action.Policy,
+		Loop:      action.Loop,
+		If:        action.If,
+	}
+	var unresolved []string
+	task.Image, _ = renderPlanValue(action.Image, placeholderParams, &unresolved).(string)
+	if action.Params != nil {
+		task.Params, _ = renderPlanValue(action.Params, placeholderParams, &unresolved).(map[string]interface{})
+	}
+	task.Commands = renderPlanValue(action.Commands, placeholderParams, &unresolved)
+	task.UnresolvedPlaceholders = strutil.DedupSlice(unresolved)
+	task.Condition, task.ConditionDesc = evalPlanCondition(action.If, conditionParams)
+
+	// 展开 snippet，入参为 snippet action 的 params
+	if !action.Type.IsSnippet() || action.SnippetConfig == nil || loader == nil {
+		return task
+	}
+	if depth >= maxPlanSnippetDepth {
+		task.SnippetError = fmt.Sprintf("snippet depth exceeds %d", maxPlanSnippetDepth)
+		return task
+	}
+	snippetYml, err := loader(task.Alias, *action.SnippetConfig)
+	if err != nil {
+		task.SnippetError = err.Error()
+		return task
+	}
+	var snippetRunParams []apistructs.PipelineRunParamWithValue
+	for name, value := range task.Params {
+		snippetRunParams = append(snippetRunParams, apistructs.PipelineRunParamWithValue{PipelineRunParam: apistructs.PipelineRunParam{Name: name, Value: value}})
+	}
+	task.Snippet, err = makePlan(snippetYml, loader, depth+1, WithEnvs(envs), WithSecrets(secrets), WithRunParams(snippetRunParams))
+	if err != nil {
+		task.SnippetError = err.Error()
+	}
+	return task
+}
+
+// mergePlanParams 合并运行时入参和声明的默认值，返回合并后的入参和缺失的必填入参
+func mergePlanParams(declared []*PipelineParam, runParams []apistructs.PipelineRunParam) (map[string]interface{}, []string) {
+	params := make(map[string]interface{})
+	for _, rp := range runParams {
+		params[rp.Name] = rp.Value
+	}
+	var missing []string
+	for _, param := range declared {
+		if param == nil {
+			continue
+		}
+		if v, ok := params[param.Name]; ok && v != nil {
+			continue
+		}
+		if param.Default != nil {
+			params[param.Name] = param.Default
+			continue
+		}
+		if param.Required {
+			missing = append(missing, param.Name)
+		}
+	}
+	sort.Strings(missing)
+	return params, missing
+}
+
+// renderPlanValue 递归渲染 value 中可以提前确定的占位符，无法渲染的占位符记录到 unresolved
+func renderPlanValue(value interface{}, placeholderParams map[string]string, unresolved *[]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		rendered, phs := renderPlanString(v, placeholderParams)
+		*unresolved = append(*unresolved, phs...)
+		return rendered
+	case map[string]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			r[k] = renderPlanValue(item, placeholderParams, unresolved)
+		}
+		return r
+	case map[interface{}]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			r[fmt.Sprintf("%v", k)] = renderPlanValue(item, placeholderParams, unresolved)
+		}
+		return r
+	case []interface{}:
+		r := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			r = append(r, renderPlanValue(item, placeholderParams, unresolved))
+		}
+		return r
+	default:
+		return value
+	}
+}
+
+// renderPlanString 使用 placeholderParams 渲染 ${{ }} 占位符，返回渲染结果和无法渲染的占位符
+func renderPlanString(s string, placeholderParams map[string]string) (string, []string) {
+	var unresolved []string
+	rendered := strutil.ReplaceAllStringSubmatchFunc(pexpr.PhRe, s, func(subs []string) string {
+		if v, ok := placeholderParams[subs[1]]; ok {
+			return v
+		}
+		unresolved = append(unresolved, subs[0])
+		return subs[0]
+	})
+	return rendered, unresolved
+}
+
+// evalPlanCondition 计算 action 的 if 条件，条件依赖运行时的值时返回 unknown
+func evalPlanCondition(condition string, placeholderParams map[string]string) (PlanCondition, string) {
+	if condition == "" {
+		return PlanConditionNone, ""
+	}
+	inner := expression.ReplacePlaceholder(strings.TrimSpace(condition))
+	rendered, unresolved := renderPlanString(inner, placeholderParams)
+	if len(unresolved) > 0 {
+		return PlanConditionUnknown, fmt.Sprintf("depends on runtime values: %s", strings.Join(strutil.DedupSlice(unresolved), ", "))
+	}
+	sign := expression.Reconcile(rendered)
+	if sign.Err != nil {
+		return PlanConditionInvalid, sign.Err.Error()
+	}
+	if sign.Sign == expression.TaskJumpOver {
+		return PlanConditionFalse, sign.Msg
+	}
+	return PlanConditionTrue, ""
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+func TestMakePlan(t *testing.T) {
+	ymlContent := []byte(`
+version: "1.1"
+params:
+  - name: branch
+    default: master
+  - name: env
+    required: true
+stages:
+  - stage:
+      - git-checkout:
+          alias: repo
+          params:
+            branch: ${{ params.branch }}
+            password: ((git.password))
+            token: ((git.token))
+  - stage:
+      - custom-script:
+          alias: build
+          if: ${{ '${{ params.branch }}' == 'master' }}
+          commands:
+            - echo ${{ outputs.repo.commit }}
+      - custom-script:
+          alias: skipped
+          if: ${{ '${{ params.branch }}' == 'develop' }}
+      - custom-script:
+          alias: unknown
+          if: ${{ '${{ outputs.repo.commit }}' == 'abc' }}
+      - snippet:
+          alias: deploy
+          params:
+            target: ${{ params.branch }}
+          snippet_config:
+            name: deploy.yml
+            source: local
+`)
+	snippetYml := []byte(`
+version: "1.1"
+params:
+  - name: target
+stages:
+  - stage:
+      - custom-script:
+          alias: echo
+          commands:
+            - echo ${{ params.target }}
+`)
+	loader := func(alias string, config SnippetConfig) ([]byte, error) {
+		if config.Name != "deploy.yml" {
+			return nil, errors.New("not found")
+		}
+		return snippetYml, nil
+	}
+
+	plan, err := MakePlan(ymlContent, loader, WithSecrets(map[string]string{"git.password": "123456"}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"branch": "master"}, plan.Params)
+	assert.Equal(t, []string{"env"}, plan.MissingParams)
+	assert.Equal(t, []string{"git.token"}, plan.MissingSecrets)
+	assert.Equal(t, 2, len(plan.Stages))
+
+	repo := plan.Stages[0].Tasks[0]
+	assert.Equal(t, "repo", repo.Alias)
+	assert.Equal(t, "git-checkout", repo.Type)
+	assert.Equal(t, "master", repo.Params["branch"])
+	assert.Equal(t, PlanSecretMask, repo.Params["password"])
+	assert.Equal(t, PlanConditionNone, repo.Condition)
+
+	tasks := make(map[string]*PlanTask)
+	for _, task := range plan.Stages[1].Tasks {
+		tasks[task.Alias] = task
+	}
+	assert.Equal(t, PlanConditionTrue, tasks["build"].Condition)
+	assert.Equal(t, []string{"repo"}, tasks["build"].Needs)
+	assert.Equal(t, []string{"${{ outputs.repo.commit }}"}, tasks["build"].UnresolvedPlaceholders)
+	assert.Equal(t, PlanConditionFalse, tasks["skipped"].Condition)
+	assert.Equal(t, PlanConditionUnknown, tasks["unknown"].Condition)
+
+	deploy := tasks["deploy"]
+	assert.Empty(t, deploy.SnippetError)
+	if assert.NotNil(t, deploy.Snippet) {
+		assert.Equal(t, map[string]string{"target": "master"}, deploy.Snippet.Params)
+		assert.Equal(t, []interface{}{"echo master"}, deploy.Snippet.Stages[0].Tasks[0].Commands)
+	}
+}
+
+func TestMakePlanWithoutLoader(t *testing.T) {
+	plan, err := MakePlan([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - snippet:
+          alias: deploy
+          snippet_config:
+            name: deploy.yml
+            source: local
+`), nil, WithRunParams([]apistructs.PipelineRunParamWithValue{{PipelineRunParam: apistructs.PipelineRunParam{Name: "a", Value: 1}}}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1"}, plan.Params)
+	assert.Nil(t, plan.Stages[0].Tasks[0].Snippet)
+	assert.Empty(t, plan.MissingSecrets)
+}
+
+func TestMakePlanWithCronTimezone(t *testing.T) {
+	plan, err := MakePlan([]byte(`
+version: "1.1"
+cron: "0 2 * * *"
+cron_timezone: Asia/Shanghai
+stages:
+  - stage:
+      - custom-script:
+          alias: a
+`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Shanghai", plan.CronTimezone)
+	assert.Equal(t, defaultListNextScheduleCount, len(plan.NextCronTimes))
+	for _, nextTime := range plan.NextCronTimes {
+		assert.Equal(t, 2, nextTime.Hour())
+		assert.Equal(t, "Asia/Shanghai", nextTime.Location().String())
+	}
+}
+
+func TestFindNotFoundSecrets(t *testing.T) {
+	data := []byte(`a: ((a))
+b: ((b))
+c: ${{ configs.c }}
+d: ${{ configs.d }}
+e: ${{ params.e }}`)
+	assert.Equal(t, []string{"b", "d"}, FindNotFoundSecrets(data, map[string]string{"a": "1", "c": "3"}))
+}
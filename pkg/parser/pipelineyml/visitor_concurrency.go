@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/erda-project/erda/pkg/parser/pipelineyml/pexpr"
+	"github.com/erda-project/erda/pkg/strutil"
+)
+
+// variables can be used in concurrency group
+const (
+	ConcurrencyVarGitBranch       = "git.branch"
+	ConcurrencyVarGitCommit       = "git.commit"
+	ConcurrencyVarPipelineSource  = "pipeline.source"
+	ConcurrencyVarPipelineYmlName = "pipeline.ymlName"
+)
+
+var supportedConcurrencyVars = []string{
+	ConcurrencyVarGitBranch,
+	ConcurrencyVarGitCommit,
+	ConcurrencyVarPipelineSource,
+	ConcurrencyVarPipelineYmlName,
+}
+
+// ConcurrencyVisitor validates the concurrency group.
+type ConcurrencyVisitor struct{}
+
+func NewConcurrencyVisitor() *ConcurrencyVisitor {
+	return &ConcurrencyVisitor{}
+}
+
+func (v *ConcurrencyVisitor) Visit(s *Spec) {
+	if s.Concurrency == nil {
+		return
+	}
+	if err := validateConcurrencyGroup(s.Concurrency.Group); err != nil {
+		s.appendError(err)
+	}
+}
+
+func validateConcurrencyGroup(group string) error {
+	if strings.TrimSpace(group) == "" {
+		return errors.New("concurrency group cannot be empty")
+	}
+	if invalidPhs := pexpr.FindInvalidPlaceholders(group); len(invalidPhs) > 0 {
+		return errors.Errorf("invalid concurrency group, found invalid placeholders: %s (must match: %s)",
+			strings.Join(invalidPhs, ", "), pexpr.PhRe.String())
+	}
+	for _, subs := range pexpr.PhRe.FindAllStringSubmatch(group, -1) {
+		if !strutil.Exist(supportedConcurrencyVars, subs[1]) {
+			return errors.Errorf("invalid concurrency group, unsupported variable: %s (supported: %s)",
+				subs[1], strings.Join(supportedConcurrencyVars, ", "))
+		}
+	}
+	return nil
+}
+
+// RenderConcurrencyGroup renders variables in concurrency group, missing variables are rendered as empty string.
+func RenderConcurrencyGroup(group string, vars map[string]string) (string, error) {
+	if err := validateConcurrencyGroup(group); err != nil {
+		return "", err
+	}
+	rendered := strutil.ReplaceAllStringSubmatchFunc(pexpr.PhRe, group, func(subs []string) string {
+		return vars[subs[1]]
+	})
+	return strings.TrimSpace(rendered), nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyVisitor_Visit(t *testing.T) {
+	y, err := New([]byte(`
+version: "1.1"
+concurrency:
+  group: build-${{ git.branch }}
+  cancel-in-progress: true
+stages:
+  - stage:
+      - git-checkout:
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "build-${{ git.branch }}", y.Spec().Concurrency.Group)
+	assert.True(t, y.Spec().Concurrency.CancelInProgress)
+
+	_, err = New([]byte(`
+version: "1.1"
+concurrency:
+  group: ${{ params.branch }}
+stages:
+  - stage:
+      - git-checkout:
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported variable: params.branch")
+
+	_, err = New([]byte(`
+version: "1.1"
+concurrency:
+  cancel-in-progress: true
+stages:
+  - stage:
+      - git-checkout:
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "concurrency group cannot be empty")
+}
+
+func TestRenderConcurrencyGroup(t *testing.T) {
+	rendered, err := RenderConcurrencyGroup("${{ pipeline.ymlName }}-${{ git.branch }}", map[string]string{
+		ConcurrencyVarGitBranch:       "feature/a",
+		ConcurrencyVarPipelineYmlName: "pipeline.yml",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "pipeline.yml-feature/a", rendered)
+
+	_, err = RenderConcurrencyGroup("${{git.branch}}", nil)
+	assert.Error(t, err)
+}
@@ -37,6 +37,8 @@ type CronVisitor struct {
 	cronStartTime *time.Time
 	cronEndTime   *time.Time
 	count         int
+	// timezone is used when spec doesn't declare cron_timezone
+	timezone string
 
 	// result
 	nextTimes []time.Time
@@ -71,6 +73,13 @@ func WithListNextScheduleCount(count int) CronVisitorOption {
 	}
 }
 
+// WithCronTimezone specify the IANA time zone to evaluate cron expr in, cron_timezone in spec takes precedence.
+func WithCronTimezone(timezone string) CronVisitorOption {
+	return func(v *CronVisitor) {
+		v.timezone = timezone
+	}
+}
+
 func (v *CronVisitor) Visit(s *Spec) {
 	if s.Cron == "" {
 		s.CronCompensator = nil
@@ -82,18 +91,30 @@ func (v *CronVisitor) Visit(s *Spec) {
 
 	var (
 		schedule cron.Schedule
+		location *time.Location
 		err      error
 	)
 
+	timezone := s.CronTimezone
+	if timezone == "" {
+		timezone = v.timezone
+	}
+	if timezone != "" {
+		if location, err = cron.LoadLocation(timezone); err != nil {
+			s.appendError(err)
+			return
+		}
+	}
+
 	switch fields := strings.Fields(s.Cron); len(fields) {
 	case 7:
 		fieldsWithoutYear := fields[:len(fields)-1]
 		s.Cron = strings.Join(fieldsWithoutYear, " ")
 		fallthrough
 	case 6:
-		schedule, err = cron.Parse(s.Cron)
+		schedule, err = cron.Parse(cron.SpecWithTimezone(s.Cron, timezone))
 	default:
-		schedule, err = cron.ParseStandard(s.Cron)
+		schedule, err = cron.ParseStandard(cron.SpecWithTimezone(s.Cron, timezone))
 	}
 	if err != nil {
 		s.appendError(err)
@@ -117,6 +138,9 @@ func (v *CronVisitor) Visit(s *Spec) {
 		if v.cronEndTime != nil && (*v.cronEndTime).Before(nextTime) {
 			break
 		}
+		if location != nil {
+			nextTime = nextTime.In(location)
+		}
 		v.nextTimes = append(v.nextTimes, nextTime)
 		scheduleFrom = nextTime
 	}
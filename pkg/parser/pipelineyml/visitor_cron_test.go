@@ -103,3 +103,28 @@ func TestListNextCronTime2(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, len(nextTimes) == 9)
 }
+
+func TestListNextCronTimeWithTimezone(t *testing.T) {
+	cronStartTime := time.Date(2021, 3, 13, 0, 0, 0, 0, time.UTC)
+	nextTimes, err := ListNextCronTime("30 2 * * *",
+		WithCronStartEndTime(&cronStartTime, nil),
+		WithListNextScheduleCount(3),
+		WithCronTimezone("America/New_York"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(nextTimes))
+	// 2021-03-14 02:30 doesn't exist in New York, runs at 03:00 EDT
+	assert.Equal(t, "2021-03-13T02:30:00-05:00", nextTimes[0].Format(time.RFC3339))
+	assert.Equal(t, "2021-03-14T03:00:00-04:00", nextTimes[1].Format(time.RFC3339))
+	assert.Equal(t, "2021-03-15T02:30:00-04:00", nextTimes[2].Format(time.RFC3339))
+
+	// cron_timezone in spec takes precedence
+	s := Spec{Cron: "0 9 * * *", CronTimezone: "Asia/Shanghai"}
+	v := NewCronVisitor(WithCronStartEndTime(&cronStartTime, nil), WithListNextScheduleCount(1), WithCronTimezone("UTC"))
+	s.Accept(v)
+	assert.NoError(t, s.mergeErrors())
+	assert.Equal(t, "2021-03-13T09:00:00+08:00", v.nextTimes[0].Format(time.RFC3339))
+
+	_, err = ListNextCronTime("0 9 * * *", WithCronTimezone("Mars/Olympus"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/erda-project/erda/pkg/expression"
+)
+
+const (
+	// MatrixMaxCombinations is the max number of tasks one matrix action can be expanded to
+	MatrixMaxCombinations = 256
+	// MatrixParamPrefix is the prefix of params injected by matrix values, params will be passed to envs as ACTION_MATRIX_{KEY}
+	MatrixParamPrefix = "matrix_"
+)
+
+var matrixKeyRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// MatrixVisitor expands actions with strategy.matrix into tasks.
+type MatrixVisitor struct{}
+
+func NewMatrixVisitor() *MatrixVisitor {
+	return &MatrixVisitor{}
+}
+
+func (v *MatrixVisitor) Visit(s *Spec) {
+	// expandedAliases: original alias -> expanded aliases
+	expandedAliases := make(map[ActionAlias][]ActionAlias)
+
+	for stageIndex, stage := range s.Stages {
+		var newActions []typedActionMap
+		for _, typedActionMap := range stage.Actions {
+			if len(typedActionMap) != 1 {
+				newActions = append(newActions, typedActionMap)
+				continue
+			}
+			for actionType, action := range typedActionMap {
+				if action == nil || action.Strategy == nil || len(action.Strategy.Matrix) == 0 {
+					newActions = append(newActions, typedActionMap)
+					continue
+				}
+				alias := getDefaultAlias(actionType, action)
+				expanded, err := expandMatrixAction(actionType, alias, action)
+				if err != nil {
+					s.appendError(err, stageIndex, alias)
+					return
+				}
+				for _, expandedAction := range expanded {
+					newActions = append(newActions, typedActionMap{actionType: expandedAction})
+					expandedAliases[alias] = append(expandedAliases[alias], expandedAction.Alias)
+				}
+			}
+		}
+		stage.Actions = newActions
+	}
+
+	if len(expandedAliases) == 0 {
+		return
+	}
+	s.matrixExpanded = true
+
+	// needs of matrix action -> needs of all expanded actions
+	s.LoopStagesActions(func(stage int, action *Action) {
+		if action == nil || len(action.Needs) == 0 {
+			return
+		}
+		var needs []ActionAlias
+		for _, need := range action.Needs {
+			if aliases, ok := expandedAliases[need]; ok {
+				needs = append(needs, aliases...)
+				continue
+			}
+			needs = append(needs, need)
+		}
+		action.Needs = needs
+	})
+
+	// outputs of matrix action are produced by expanded actions, the original alias doesn't exist anymore
+	s.LoopStagesActions(func(stage int, action *Action) {
+		if action == nil {
+			return
+		}
+		for _, ref := range findMatrixOutputRefs(action, expandedAliases) {
+			s.appendError(errors.Errorf("cannot refer outputs of matrix action %q, refer one of expanded actions %v instead",
+				ref, expandedAliases[ref]), stage, action.Alias)
+		}
+	})
+}
+
+// findMatrixOutputRefs returns matrix aliases referred by outputs in action's params, commands and if,
+// both ${{ outputs.alias.key }} and ${alias:OUTPUT:key} are checked.
+func findMatrixOutputRefs(action *Action, expandedAliases map[ActionAlias][]ActionAlias) []ActionAlias {
+	var texts []string
+	for _, v := range []interface{}{action.Params, action.Commands} {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			continue
+		}
+		texts = append(texts, string(b))
+	}
+	texts = append(texts, action.If)
+
+	found := make(map[ActionAlias]struct{})
+	var refs []ActionAlias
+	addRef := func(alias string) {
+		ref := ActionAlias(alias)
+		if _, ok := expandedAliases[ref]; !ok {
+			return
+		}
+		if _, ok := found[ref]; ok {
+			return
+		}
+		found[ref] = struct{}{}
+		refs = append(refs, ref)
+	}
+	for _, text := range texts {
+		for _, sub := range expression.Re.FindAllStringSubmatch(text, -1) {
+			ss := strings.SplitN(strings.Trim(sub[1], " "), ".", 3)
+			if len(ss) == 3 && ss[0] == expression.Outputs {
+				addRef(ss[1])
+			}
+		}
+		for _, sub := range expression.OldRe.FindAllStringSubmatch(text, -1) {
+			ss := strings.SplitN(sub[1], ":", 3)
+			if len(ss) == 3 && ss[1] == RefOpOutput {
+				addRef(ss[0])
+			}
+		}
+	}
+	return refs
+}
+
+// expandMatrixAction expands action over the cartesian product of matrix values.
+func expandMatrixAction(actionType ActionType, alias ActionAlias, action *Action) ([]*Action, error) {
+	strategy := action.Strategy
+	if strategy.MaxParallel < 0 {
+		return nil, errors.Errorf("invalid strategy max-parallel: %d", strategy.MaxParallel)
+	}
+
+	keys := make([]string, 0, len(strategy.Matrix))
+	for key, values := range strategy.Matrix {
+		if !matrixKeyRegex.MatchString(key) {
+			return nil, errors.Errorf("invalid matrix key: %s, regex: %s", key, matrixKeyRegex.String())
+		}
+		if len(values) == 0 {
+			return nil, errors.Errorf("matrix key %q doesn't have any values", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	combinations := []map[string]interface{}{{}}
+	for _, key := range keys {
+		var next []map[string]interface{}
+		for _, combination := range combinations {
+			for _, value := range strategy.Matrix[key] {
+				newCombination := make(map[string]interface{}, len(combination)+1)
+				for k, v := range combination {
+					newCombination[k] = v
+				}
+				newCombination[key] = value
+				next = append(next, newCombination)
+			}
+		}
+		if len(next) > MatrixMaxCombinations {
+			return nil, errors.Errorf("too many matrix combinations, max: %d", MatrixMaxCombinations)
+		}
+		combinations = next
+	}
+
+	// use yaml node as template to render ${{ matrix.xxx }}, values are substituted into scalar nodes
+	// instead of yaml text, so that values like `a: b` or `[x]` can not change the structure of action
+	template := *action
+	template.Strategy = nil
+	templateBytes, err := yaml.Marshal(&template)
+	if err != nil {
+		return nil, errors.Errorf("failed to marshal matrix action, err: %v", err)
+	}
+
+	expanded := make([]*Action, 0, len(combinations))
+	for i, combination := range combinations {
+		var node yaml.Node
+		if err := yaml.Unmarshal(templateBytes, &node); err != nil {
+			return nil, errors.Errorf("failed to unmarshal matrix action, err: %v", err)
+		}
+		if err := renderMatrixNode(&node, combination); err != nil {
+			return nil, errors.Errorf("failed to render matrix action, values: %v, err: %v", combination, err)
+		}
+		var expandedAction Action
+		if err := node.Decode(&expandedAction); err != nil {
+			return nil, errors.Errorf("failed to render matrix action, values: %v, err: %v", combination, err)
+		}
+		expandedAction.Type = actionType
+		expandedAction.Alias = ActionAlias(fmt.Sprintf("%s-%d", alias, i+1))
+		if expandedAction.Params == nil {
+			expandedAction.Params = make(map[string]interface{})
+		}
+		for key, value := range combination {
+			paramKey := MatrixParamPrefix + key
+			if _, ok := expandedAction.Params[paramKey]; !ok {
+				expandedAction.Params[paramKey] = value
+			}
+		}
+		expandedAction.Strategy = &Strategy{
+			MaxParallel: strategy.MaxParallel,
+			FailFast:    strategy.FailFast,
+			Group:       alias,
+			Values:      combination,
+		}
+		expanded = append(expanded, &expandedAction)
+	}
+	return expanded, nil
+}
+
+// renderMatrixNode replaces ${{ matrix.xxx }} in all scalar nodes.
+// A scalar which is exactly one placeholder is replaced by the typed value,
+// otherwise the placeholder is replaced as part of the string and the scalar is kept as string.
+func renderMatrixNode(node *yaml.Node, values map[string]interface{}) error {
+	if node.Kind != yaml.ScalarNode {
+		for _, child := range node.Content {
+			if err := renderMatrixNode(child, values); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !strings.Contains(node.Value, expression.LeftPlaceholder) {
+		return nil
+	}
+	for key, value := range values {
+		placeholder := fmt.Sprintf("%s %s.%s %s", expression.LeftPlaceholder, expression.Matrix, key, expression.RightPlaceholder)
+		if node.Value == placeholder {
+			var valueNode yaml.Node
+			if err := valueNode.Encode(value); err != nil {
+				return err
+			}
+			*node = valueNode
+			return nil
+		}
+		if strings.Contains(node.Value, placeholder) {
+			node.Value = strings.ReplaceAll(node.Value, placeholder, fmt.Sprintf("%v", value))
+			node.Tag = "!!str"
+		}
+	}
+	return nil
+}
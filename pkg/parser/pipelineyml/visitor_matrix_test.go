@@ -0,0 +1,172 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrixVisitor_Visit(t *testing.T) {
+	y, err := New([]byte(`
+version: "1.2"
+actions:
+  - git-checkout:
+      alias: repo
+  - custom-script:
+      alias: test
+      needs: [repo]
+      image: golang:${{ matrix.go }}
+      commands:
+        - go test ./... -shard=${{ matrix.shard }}
+      strategy:
+        matrix:
+          go: ["1.18", "1.19"]
+          shard: [1, 2, 3]
+        max-parallel: 2
+        fail-fast: false
+  - release:
+      needs: [test]
+`))
+	assert.NoError(t, err)
+
+	actions := ListAction(y.Spec())
+	assert.Equal(t, 8, len(actions))
+
+	// keys are sorted, so go is the outer loop
+	test4, err := GetAction(y.Spec(), "test-4")
+	assert.NoError(t, err)
+	assert.Equal(t, "golang:1.19", test4.Image)
+	assert.Equal(t, []interface{}{"go test ./... -shard=1"}, test4.Commands)
+	assert.Equal(t, "1.19", test4.Params["matrix_go"])
+	assert.Equal(t, ActionAlias("test"), test4.Strategy.Group)
+	assert.Equal(t, 2, test4.Strategy.MaxParallel)
+	assert.False(t, test4.Strategy.IsFailFast())
+	assert.Equal(t, []ActionAlias{"repo"}, test4.Needs)
+
+	release, err := GetAction(y.Spec(), "release")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []ActionAlias{"test-1", "test-2", "test-3", "test-4", "test-5", "test-6"}, release.Needs)
+
+	// expanded yaml is not expanded again
+	b, err := GenerateYml(y.Spec())
+	assert.NoError(t, err)
+	y2, err := New(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, len(ListAction(y2.Spec())))
+}
+
+func TestMatrixVisitor_Invalid(t *testing.T) {
+	_, err := New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          strategy:
+            matrix:
+              go: []
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `matrix key "go" doesn't have any values`)
+}
+
+func TestMatrixVisitor_ValuesAreNotRenderedAsYaml(t *testing.T) {
+	y, err := New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          alias: test
+          commands:
+            - echo ${{ matrix.msg }}
+          params:
+            count: ${{ matrix.count }}
+          strategy:
+            matrix:
+              msg: ["a: b", "[x]\nimage: evil"]
+              count: [1]
+`))
+	assert.NoError(t, err)
+
+	test1, err := GetAction(y.Spec(), "test-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"echo a: b"}, test1.Commands)
+	assert.Equal(t, 1, test1.Params["count"])
+	test2, err := GetAction(y.Spec(), "test-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"echo [x]\nimage: evil"}, test2.Commands)
+	assert.Equal(t, "", test2.Image)
+}
+
+func TestMatrixVisitor_RejectOutputsOfMatrixAlias(t *testing.T) {
+	_, err := New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          alias: build
+          commands:
+            - echo ${{ matrix.os }}
+          strategy:
+            matrix:
+              os: [linux, darwin]
+  - stage:
+      - custom-script:
+          alias: upload
+          commands:
+            - echo ${{ outputs.build.artifact }}
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot refer outputs of matrix action "build"`)
+	assert.Contains(t, err.Error(), `build-1 build-2`)
+
+	_, err = New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          alias: build
+          strategy:
+            matrix:
+              os: [linux, darwin]
+  - stage:
+      - custom-script:
+          alias: upload
+          params:
+            artifact: ${build:OUTPUT:artifact}
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot refer outputs of matrix action "build"`)
+
+	// refer to expanded action is allowed
+	y, err := New([]byte(`
+version: "1.1"
+stages:
+  - stage:
+      - custom-script:
+          alias: build
+          strategy:
+            matrix:
+              os: [linux, darwin]
+  - stage:
+      - custom-script:
+          alias: upload
+          commands:
+            - echo ${{ outputs.build-1.artifact }}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(ListAction(y.Spec())))
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/erda-project/erda/pkg/dag"
+)
+
+// NeedsVisitor validates user declared needs and converts actions to stages by needs.
+type NeedsVisitor struct{}
+
+func NewNeedsVisitor() *NeedsVisitor {
+	return &NeedsVisitor{}
+}
+
+// needsNode is the dag node of an action, prevNodes are the effective needs of the action.
+type needsNode struct {
+	alias     ActionAlias
+	prevNodes []string
+}
+
+func (n *needsNode) NodeName() string {
+	return n.alias.String()
+}
+
+func (n *needsNode) PrevNodeNames() []string {
+	return n.prevNodes
+}
+
+func (v *NeedsVisitor) Visit(s *Spec) {
+	if s.Version != Version1dot2 {
+		if len(s.Actions) > 0 {
+			s.appendError(errors.Errorf("actions is only supported since version %s", Version1dot2))
+		}
+		s.LoopStagesActions(func(stage int, action *Action) {
+			if action != nil && len(action.Needs) > 0 {
+				s.appendError(errors.Errorf("needs is only supported since version %s", Version1dot2), stage, action.Alias)
+			}
+		})
+		return
+	}
+
+	if len(s.Actions) > 0 {
+		if len(s.Stages) > 0 {
+			s.appendError(errors.New("stages and actions cannot be declared at the same time"))
+			return
+		}
+		v.convertActionsToStages(s)
+		return
+	}
+
+	v.validateStagesNeeds(s)
+}
+
+// convertActionsToStages layers actions by needs, actions in the same stage have no dependency on each other.
+func (v *NeedsVisitor) convertActionsToStages(s *Spec) {
+	actions := make(map[ActionAlias]typedActionMap, len(s.Actions))
+	var nodes []*needsNode
+	for actionIndex, typedActionMap := range s.Actions {
+		if len(typedActionMap) != 1 {
+			s.appendError(errors.Errorf("actionNum %d: must declare exactly one action", actionIndex+1))
+			return
+		}
+		for actionType, action := range typedActionMap {
+			if action == nil {
+				action = &Action{}
+				typedActionMap[actionType] = action
+			}
+			alias := getDefaultAlias(actionType, action)
+			if _, ok := actions[alias]; ok {
+				s.appendError(errors.Errorf("action name %q is duplicated", alias))
+				return
+			}
+			actions[alias] = typedActionMap
+			nodes = append(nodes, &needsNode{alias: alias, prevNodes: aliasesToStrings(action.Needs)})
+		}
+	}
+
+	layers, err := layerNeedsNodes(nodes)
+	if err != nil {
+		s.appendError(err)
+		return
+	}
+
+	stages := make([]*Stage, 0, len(layers))
+	for _, layer := range layers {
+		stage := &Stage{}
+		for _, alias := range layer {
+			stage.Actions = append(stage.Actions, actions[ActionAlias(alias)])
+		}
+		stages = append(stages, stage)
+	}
+	s.Stages = stages
+	s.Actions = nil
+	s.stagesFromActions = true
+}
+
+// validateStagesNeeds validates needs declared in stages.
+// Actions without needs depend on all actions from previous stages.
+func (v *NeedsVisitor) validateStagesNeeds(s *Spec) {
+	var nodes []*needsNode
+	var previousStagesAliases []string
+	for _, stage := range s.Stages {
+		var stageAliases []string
+		for _, typedActionMap := range stage.Actions {
+			for actionType, action := range typedActionMap {
+				if action == nil {
+					action = &Action{}
+				}
+				alias := getDefaultAlias(actionType, action)
+				prevNodes := aliasesToStrings(action.Needs)
+				if len(action.Needs) == 0 {
+					prevNodes = append([]string{}, previousStagesAliases...)
+				}
+				nodes = append(nodes, &needsNode{alias: alias, prevNodes: prevNodes})
+				stageAliases = append(stageAliases, alias.String())
+			}
+		}
+		previousStagesAliases = append(previousStagesAliases, stageAliases...)
+	}
+	// duplicated aliases are reported by stage visitor
+	existAliases := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		if _, ok := existAliases[node.NodeName()]; ok {
+			return
+		}
+		existAliases[node.NodeName()] = struct{}{}
+	}
+	if _, err := layerNeedsNodes(nodes); err != nil {
+		s.appendError(err)
+	}
+}
+
+// layerNeedsNodes checks missing needs and cycles, and returns the layered aliases.
+func layerNeedsNodes(nodes []*needsNode) ([][]string, error) {
+	existAliases := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		existAliases[node.NodeName()] = struct{}{}
+	}
+	namedNodes := make([]dag.NamedNode, 0, len(nodes))
+	for _, node := range nodes {
+		for _, need := range node.prevNodes {
+			if need == node.NodeName() {
+				return nil, errors.Errorf("invalid needs: action %q cannot need itself", node.alias)
+			}
+			if _, ok := existAliases[need]; !ok {
+				return nil, errors.Errorf("invalid needs: action %q needs a nonexistent action %q", node.alias, need)
+			}
+		}
+		namedNodes = append(namedNodes, node)
+	}
+	g, err := dag.New(namedNodes, dag.WithAllowNotCheckCycle(true))
+	if err != nil {
+		return nil, errors.Errorf("invalid needs: %v", err)
+	}
+	layers, err := g.Layers()
+	if err != nil {
+		return nil, errors.Errorf("invalid needs: %v", err)
+	}
+	return layers, nil
+}
+
+func getDefaultAlias(actionType ActionType, action *Action) ActionAlias {
+	if action.Alias != "" {
+		return action.Alias
+	}
+	return ActionAlias(actionType)
+}
+
+func aliasesToStrings(aliases []ActionAlias) []string {
+	r := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		r = append(r, alias.String())
+	}
+	return r
+}
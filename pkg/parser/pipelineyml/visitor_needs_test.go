@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeedsVisitor_Actions(t *testing.T) {
+	y, err := New([]byte(`
+version: "1.2"
+actions:
+  - release:
+      needs: [build, test]
+  - git-checkout:
+      alias: repo
+  - golang:
+      alias: build
+      needs: [repo]
+  - golang:
+      alias: test
+      needs: [repo]
+`))
+	assert.NoError(t, err)
+
+	stages := y.Spec().ToSimplePipelineYmlActionSlice()
+	assert.Equal(t, 3, len(stages))
+	assert.Equal(t, "repo", stages[0][0].Alias)
+	assert.Equal(t, "build", stages[1][0].Alias)
+	assert.Equal(t, "test", stages[1][1].Alias)
+	assert.Equal(t, "release", stages[2][0].Alias)
+
+	release, err := GetAction(y.Spec(), "release")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []ActionAlias{"build", "test"}, release.Needs)
+	assert.ElementsMatch(t, []string{"build", "test"}, release.NeedNamespaces)
+
+	// generated yaml keeps the declared needs and can be parsed again
+	b, err := GenerateYml(y.Spec())
+	assert.NoError(t, err)
+	y2, err := New(b)
+	assert.NoError(t, err)
+	release2, err := GetAction(y2.Spec(), "release")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []ActionAlias{"build", "test"}, release2.Needs)
+}
+
+func TestNeedsVisitor_Stages(t *testing.T) {
+	y, err := New([]byte(`
+version: "1.2"
+stages:
+  - stage:
+      - git-checkout:
+          alias: repo
+      - custom-script:
+          alias: lint
+  - stage:
+      - golang:
+          alias: build
+          needs: [repo]
+  - stage:
+      - release:
+`))
+	assert.NoError(t, err)
+
+	build, err := GetAction(y.Spec(), "build")
+	assert.NoError(t, err)
+	assert.Equal(t, []ActionAlias{"repo"}, build.Needs)
+
+	// action without needs depends on all actions from previous stages
+	release, err := GetAction(y.Spec(), "release")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []ActionAlias{"repo", "lint", "build"}, release.Needs)
+
+	// injected needs are not generated
+	b, err := GenerateYml(y.Spec())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), "needs:"))
+}
+
+func TestNeedsVisitor_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		yml    string
+		errMsg string
+	}{
+		{
+			name: "needs before 1.2",
+			yml: `
+version: "1.1"
+stages:
+  - stage:
+      - git-checkout:
+          needs: [a]
+`,
+			errMsg: "needs is only supported since version 1.2",
+		},
+		{
+			name: "missing alias",
+			yml: `
+version: "1.2"
+actions:
+  - git-checkout:
+  - golang:
+      needs: [repo]
+`,
+			errMsg: `action "golang" needs a nonexistent action "repo"`,
+		},
+		{
+			name: "cycle",
+			yml: `
+version: "1.2"
+actions:
+  - git-checkout:
+      needs: [release]
+  - golang:
+      needs: [git-checkout]
+  - release:
+      needs: [golang]
+`,
+			errMsg: "cycle detected among nodes: [git-checkout golang release]",
+		},
+		{
+			name: "cycle with stages",
+			yml: `
+version: "1.2"
+stages:
+  - stage:
+      - git-checkout:
+          needs: [release]
+  - stage:
+      - release:
+`,
+			errMsg: "cycle detected",
+		},
+		{
+			name: "both stages and actions",
+			yml: `
+version: "1.2"
+stages:
+  - stage:
+      - git-checkout:
+actions:
+  - release:
+`,
+			errMsg: "stages and actions cannot be declared at the same time",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New([]byte(tc.yml))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
@@ -53,24 +53,8 @@ func (v *ParamsVisitor) Visit(s *Spec) {
 
 func ReplacePipelineParams(pipeline string, params map[string]interface{}) string {
 	for k, v := range params {
-		replaceStr := ""
-		switch v.(type) {
-		case int:
-			replaceStr = strconv.Itoa(v.(int))
-		case float64:
-			replaceStr = strconv.FormatFloat(v.(float64), 'f', -1, 64)
-		case float32:
-			replaceStr = strconv.FormatFloat(v.(float64), 'f', -1, 32)
-		case bool:
-			replaceStr = strconv.FormatBool(v.(bool))
-		case string:
-			replaceStr = v.(string)
-		default:
-			replaceStr = fmt.Sprintf("%v", v)
-		}
-
 		// generate random params before replace
-		replaceStr = expression.ReplaceRandomParams(replaceStr)
+		replaceStr := expression.ReplaceRandomParams(paramValueToString(v))
 
 		// 替换老的
 		pipeline = strings.ReplaceAll(pipeline, fmt.Sprintf("%s%s.%s%s", expression.OldLeftPlaceholder, expression.Params, k, expression.OldRightPlaceholder), replaceStr)
@@ -80,3 +64,21 @@ func ReplacePipelineParams(pipeline string, params map[string]interface{}) strin
 	}
 	return pipeline
 }
+
+// paramValueToString 将参数值转换为替换时使用的字符串
+func paramValueToString(v interface{}) string {
+	switch v.(type) {
+	case int:
+		return strconv.Itoa(v.(int))
+	case float64:
+		return strconv.FormatFloat(v.(float64), 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v.(float32)), 'f', -1, 32)
+	case bool:
+		return strconv.FormatBool(v.(bool))
+	case string:
+		return v.(string)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -108,8 +108,10 @@ func RenderSecrets(input []byte, secrets map[string]string) ([]byte, error) {
 	})
 
 	// replace ${{ configs.key }}
+	// replace ${{ secrets.scheme://path#key }}, key of external secret is the reference itself
 	for k, v := range secrets {
 		replaced = strings.ReplaceAll(replaced, expression.LeftPlaceholder+" "+expression.Configs+"."+k+" "+expression.RightPlaceholder, expression.ReplaceRandomParams(v))
+		replaced = strings.ReplaceAll(replaced, expression.LeftPlaceholder+" "+expression.Secrets+"."+k+" "+expression.RightPlaceholder, v)
 	}
 
 	return []byte(replaced), nil
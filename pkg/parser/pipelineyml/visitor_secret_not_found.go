@@ -14,6 +14,14 @@
 
 package pipelineyml
 
+import (
+	"sort"
+	"strings"
+
+	"github.com/erda-project/erda/pkg/expression"
+	"github.com/erda-project/erda/pkg/parser/pipelineyml/pexpr"
+)
+
 type SecretNotFoundSecret struct {
 	data    []byte
 	secrets map[string]string
@@ -31,4 +39,31 @@ func (v *SecretNotFoundSecret) Visit(s *Spec) {
 		s.appendError(err)
 		return
 	}
+	s.notFoundSecrets = FindNotFoundSecrets(v.data, v.secrets)
+}
+
+// FindNotFoundSecrets 返回 data 中引用但 secrets 中不存在的 key，包括 ((key)) 和 ${{ configs.key }} 两种写法，结果去重且有序
+func FindNotFoundSecrets(data []byte, secrets map[string]string) []string {
+	notFound := make(map[string]struct{})
+	for _, wrappedSec := range validSecretRegexp.FindAllString(string(data), -1) {
+		key := unwrapSecret(wrappedSec)
+		if _, ok := secrets[key]; !ok {
+			notFound[key] = struct{}{}
+		}
+	}
+	for _, subs := range pexpr.PhRe.FindAllStringSubmatch(string(data), -1) {
+		if !strings.HasPrefix(subs[1], expression.Configs+".") {
+			continue
+		}
+		key := strings.TrimPrefix(subs[1], expression.Configs+".")
+		if _, ok := secrets[key]; !ok {
+			notFound[key] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(notFound))
+	for key := range notFound {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
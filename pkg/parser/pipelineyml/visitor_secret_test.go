@@ -43,6 +43,16 @@ envs:
 	assert.Error(t, s.mergeErrors())
 }
 
+func TestRenderSecrets_ExternalSecrets(t *testing.T) {
+	input := []byte("password: ${{ secrets.vault://kv/app#password }}, user: ${{ configs.user }}, other: ${{ secrets.vault://kv/app#other }}")
+	output, err := RenderSecrets(input, map[string]string{
+		"vault://kv/app#password": "123456",
+		"user":                    "erda",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "password: 123456, user: erda, other: ${{ secrets.vault://kv/app#other }}", string(output))
+}
+
 //func TestRenderSecrets(t *testing.T) {
 //	input := []byte("((a))((b))((c))")
 //	secret := map[string]string{
@@ -96,6 +96,11 @@ func (v *StageVisitor) Visit(s *Spec) {
 
 				action.Type = actionType
 
+				// policy
+				if err := action.Policy.Validate(); err != nil {
+					s.appendError(err, stageIndex, action.Alias)
+				}
+
 				// params
 				action.noNullParams()
 				action.markParamsValueType()
@@ -105,14 +110,13 @@ func (v *StageVisitor) Visit(s *Spec) {
 					}
 				}
 
-				// needs
-				if len(action.Needs) == 0 {
+				// needs and needNamespaces
+				// 未声明 needs 时，注入之前所有 stage 的 actions 和 namespaces；
+				// 声明了 needs 时，needNamespaces 在所有 action 遍历完成后计算
+				if len(action.Needs) == 0 || action.needsInjected {
 					action.Needs = toList(availableActions)
-				}
-
-				// needNamespaces
-				if len(action.NeedNamespaces) == 0 {
 					action.NeedNamespaces = toListStr(availableNamespaces)
+					action.needsInjected = true
 				}
 
 				// namespaces
@@ -142,6 +146,20 @@ func (v *StageVisitor) Visit(s *Spec) {
 			availableActions[action] = struct{}{}
 		}
 	}
+
+	// needNamespaces of declared needs
+	for _, action := range s.allActions {
+		if action.needsInjected {
+			continue
+		}
+		var needNamespaces []string
+		for _, need := range action.Needs {
+			if needAction, ok := s.allActions[need]; ok {
+				needNamespaces = append(needNamespaces, needAction.Namespaces...)
+			}
+		}
+		action.NeedNamespaces = strutil.DedupSlice(needNamespaces)
+	}
 }
 
 // flatParams 将 params 的 value (包括复杂结构体) 转换为 json(string)
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+// UpstreamPipelineTriggerVisitor validates on.pipeline triggers.
+type UpstreamPipelineTriggerVisitor struct{}
+
+func NewUpstreamPipelineTriggerVisitor() *UpstreamPipelineTriggerVisitor {
+	return &UpstreamPipelineTriggerVisitor{}
+}
+
+func (v *UpstreamPipelineTriggerVisitor) Visit(s *Spec) {
+	if s.On == nil || len(s.On.Pipeline) == 0 {
+		return
+	}
+	declaredParams := make(map[string]struct{}, len(s.Params))
+	for _, param := range s.Params {
+		if param == nil {
+			continue
+		}
+		declaredParams[param.Name] = struct{}{}
+	}
+	for i, trigger := range s.On.Pipeline {
+		if err := validateUpstreamPipelineTrigger(trigger, declaredParams); err != nil {
+			s.appendError(errors.Errorf("invalid on.pipeline[%d], err: %v", i, err))
+		}
+	}
+}
+
+func validateUpstreamPipelineTrigger(trigger *UpstreamPipelineTrigger, declaredParams map[string]struct{}) error {
+	if trigger == nil {
+		return errors.New("trigger cannot be empty")
+	}
+	if strings.TrimSpace(trigger.Name) == "" {
+		return errors.New("name cannot be empty")
+	}
+	for _, status := range trigger.Status {
+		if !apistructs.PipelineStatus(status).IsEndStatus() {
+			return errors.Errorf("status %s is not an end status", status)
+		}
+	}
+	for paramName, outputName := range trigger.Params {
+		if _, ok := declaredParams[paramName]; !ok {
+			return errors.Errorf("param %s is not declared in params", paramName)
+		}
+		if strings.TrimSpace(outputName) == "" {
+			return errors.Errorf("output of param %s cannot be empty", paramName)
+		}
+	}
+	return nil
+}
+
+// GetStatus return the upstream end statuses which can trigger, default is Success.
+func (t *UpstreamPipelineTrigger) GetStatus() []string {
+	if len(t.Status) == 0 {
+		return []string{apistructs.PipelineStatusSuccess.String()}
+	}
+	return t.Status
+}
+
+// ConvertParamValue convert value to the declared param type, used when value comes from outside the yml,
+// such as outputs of upstream pipeline.
+func ConvertParamValue(paramType string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, errors.New("value is empty")
+	}
+	switch paramType {
+	case apistructs.PipelineParamIntType:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, errors.Errorf("value %v is not an integer", v)
+			}
+			return int(v), nil
+		default:
+			i, err := strconv.Atoi(strings.TrimSpace(paramValueToString(v)))
+			if err != nil {
+				return nil, errors.Errorf("value %v is not an integer", v)
+			}
+			return i, nil
+		}
+	case apistructs.PipelineParamBoolType:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(paramValueToString(value)))
+		if err != nil {
+			return nil, errors.Errorf("value %v is not a boolean", value)
+		}
+		return b, nil
+	default:
+		return paramValueToString(value), nil
+	}
+}
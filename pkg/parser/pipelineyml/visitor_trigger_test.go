@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelineyml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpstreamPipelineTriggerVisitor_Visit(t *testing.T) {
+	y, err := New([]byte(`
+version: "1.1"
+params:
+  - name: image
+    type: string
+  - name: replicas
+    type: int
+on:
+  pipeline:
+    - name: build/pipeline.yml
+      branches:
+        - master
+        - release/*
+      status:
+        - Success
+        - Failed
+      params:
+        image: image
+        replicas: replicas
+stages:
+  - stage:
+      - git-checkout:
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(y.Spec().On.Pipeline))
+	trigger := y.Spec().On.Pipeline[0]
+	assert.Equal(t, "build/pipeline.yml", trigger.Name)
+	assert.Equal(t, []string{"master", "release/*"}, trigger.Branches)
+	assert.Equal(t, []string{"Success", "Failed"}, trigger.GetStatus())
+	assert.Equal(t, "image", trigger.Params["image"])
+
+	_, err = New([]byte(`
+version: "1.1"
+on:
+  pipeline:
+    - name: build/pipeline.yml
+      params:
+        image: image
+stages:
+  - stage:
+      - git-checkout:
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "param image is not declared in params")
+
+	_, err = New([]byte(`
+version: "1.1"
+on:
+  pipeline:
+    - name: build/pipeline.yml
+      status:
+        - Running
+stages:
+  - stage:
+      - git-checkout:
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status Running is not an end status")
+
+	_, err = New([]byte(`
+version: "1.1"
+on:
+  pipeline:
+    - branches:
+        - master
+stages:
+  - stage:
+      - git-checkout:
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "name cannot be empty")
+}
+
+func TestUpstreamPipelineTrigger_GetStatus(t *testing.T) {
+	trigger := &UpstreamPipelineTrigger{}
+	assert.Equal(t, []string{"Success"}, trigger.GetStatus())
+}
+
+func TestConvertParamValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		paramType string
+		value     interface{}
+		want      interface{}
+		wantErr   bool
+	}{
+		{name: "int from string", paramType: "int", value: " 3 ", want: 3},
+		{name: "int from float", paramType: "int", value: float64(2), want: 2},
+		{name: "int from decimal float", paramType: "int", value: 2.5, wantErr: true},
+		{name: "int from invalid string", paramType: "int", value: "a", wantErr: true},
+		{name: "bool from string", paramType: "boolean", value: "true", want: true},
+		{name: "bool from bool", paramType: "boolean", value: false, want: false},
+		{name: "bool from invalid string", paramType: "boolean", value: "yes", wantErr: true},
+		{name: "string from int", paramType: "string", value: 1, want: "1"},
+		{name: "untyped", paramType: "", value: "a", want: "a"},
+		{name: "nil value", paramType: "string", value: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertParamValue(tt.paramType, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -29,10 +29,10 @@ func (v *VersionVisitor) Visit(s *Spec) {
 	switch s.Version {
 	case "":
 		s.appendError(errors.New("no version"))
-	case Version1dot1:
+	case Version1dot1, Version1dot2:
 		return
 	default:
-		s.appendError(errors.Errorf("invalid version: %s, only support 1.1", s.Version))
+		s.appendError(errors.Errorf("invalid version: %s, only support 1.1, 1.2", s.Version))
 	}
 }
 
@@ -25,6 +25,8 @@ func TestVersionVisitor_Visit(t *testing.T) {
 		`version: 1.1`,
 		`version: '1.1'`,
 		`version: "1.1"`,
+		`version: 1.2`,
+		`version: "1.2"`,
 	}
 	for _, tc := range validVersionTestCases {
 		y, err := New([]byte(tc))
@@ -38,7 +40,7 @@ func TestVersionVisitor_Visit(t *testing.T) {
 	}
 
 	invalidVersionTestCases := []string{
-		`version: 1.2`,
+		`version: 1.3`,
 		`version: 2`,
 		`version: 1.1.alpha`,
 		`version: "1`,
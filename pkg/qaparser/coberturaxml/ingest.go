@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coberturaxml
+
+import (
+	"encoding/xml"
+	"path"
+	"regexp"
+	"strconv"
+
+	"github.com/erda-project/erda/pkg/qaparser"
+)
+
+// Coverage the root of cobertura xml report
+type Coverage struct {
+	XMLName  xml.Name  `xml:"coverage"`
+	Sources  []string  `xml:"sources>source"`
+	Packages []Package `xml:"packages>package"`
+}
+
+type Package struct {
+	Name    string  `xml:"name,attr"`
+	Classes []Class `xml:"classes>class"`
+}
+
+type Class struct {
+	Name     string `xml:"name,attr"`
+	Filename string `xml:"filename,attr"`
+	Lines    []Line `xml:"lines>line"`
+}
+
+type Line struct {
+	Number            int    `xml:"number,attr"`
+	Hits              int64  `xml:"hits,attr"`
+	Branch            bool   `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr"`
+}
+
+// conditionCoverageRegexp matches condition coverage like "50% (1/2)"
+var conditionCoverageRegexp = regexp.MustCompile(`\((\d+)/(\d+)\)`)
+
+// Ingest will parse the given cobertura xml data and return the coverage of each source file,
+// classes of the same file (e.g. inner classes of java) are merged.
+func Ingest(data []byte) ([]qaparser.FileCoverage, error) {
+	var coverage Coverage
+	if err := xml.Unmarshal(data, &coverage); err != nil {
+		return nil, err
+	}
+
+	var (
+		files     []qaparser.FileCoverage
+		fileIndex = make(map[string]int)
+	)
+	for _, pkg := range coverage.Packages {
+		for _, class := range pkg.Classes {
+			filename := class.Filename
+			if filename == "" {
+				filename = path.Join(pkg.Name, class.Name)
+			}
+			idx, ok := fileIndex[filename]
+			if !ok {
+				idx = len(files)
+				fileIndex[filename] = idx
+				files = append(files, qaparser.FileCoverage{Filename: filename})
+			}
+			ingestLines(&files[idx], class.Lines)
+		}
+	}
+	return files, nil
+}
+
+func ingestLines(f *qaparser.FileCoverage, lines []Line) {
+	for _, line := range lines {
+		if line.Hits > 0 {
+			f.LinesCovered++
+		} else {
+			f.LinesMissed++
+		}
+		if !line.Branch {
+			continue
+		}
+		matches := conditionCoverageRegexp.FindStringSubmatch(line.ConditionCoverage)
+		if len(matches) != 3 {
+			continue
+		}
+		covered, _ := strconv.Atoi(matches[1])
+		total, _ := strconv.Atoi(matches[2])
+		if total >= covered {
+			f.BranchesCovered += covered
+			f.BranchesMissed += total - covered
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coberturaxml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngest(t *testing.T) {
+	demo := `<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage version="6.4" timestamp="1652174418412" lines-valid="5" lines-covered="3" line-rate="0.6" branches-covered="1" branches-valid="2" branch-rate="0.5" complexity="0">
+	<sources>
+		<source>/builds/demo</source>
+	</sources>
+	<packages>
+		<package name="app" line-rate="0.6" branch-rate="0.5" complexity="0">
+			<classes>
+				<class name="views.py" filename="app/views.py" complexity="0" line-rate="0.6667" branch-rate="0.5">
+					<methods/>
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="3" branch="true" condition-coverage="50% (1/2)" missing-branches="4"/>
+						<line number="4" hits="0"/>
+					</lines>
+				</class>
+				<class name="views.py$Inner" filename="app/views.py" complexity="0" line-rate="0.5" branch-rate="0">
+					<lines>
+						<line number="10" hits="2"/>
+						<line number="11" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`
+	files, err := Ingest([]byte(demo))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(files))
+	assert.Equal(t, "app/views.py", files[0].Filename)
+	assert.Equal(t, 3, files[0].LinesCovered)
+	assert.Equal(t, 2, files[0].LinesMissed)
+	assert.Equal(t, 1, files[0].BranchesCovered)
+	assert.Equal(t, 1, files[0].BranchesMissed)
+}
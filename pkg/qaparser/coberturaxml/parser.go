@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coberturaxml
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/erda-project/erda-proto-go/dop/qa/unittest/pb"
+	"github.com/erda-project/erda/pkg/cloudstorage"
+	"github.com/erda-project/erda/pkg/qaparser"
+	"github.com/erda-project/erda/pkg/qaparser/types"
+)
+
+// CoberturaParser parses the cobertura xml coverage reports, e.g. generated by coverage.py and gocover-cobertura
+type CoberturaParser struct {
+}
+
+func init() {
+	logrus.Info("register Cobertura Parser to manager")
+	(CoberturaParser{}).Register()
+}
+
+func (c CoberturaParser) Register() {
+	qaparser.RegisterCoverage(c, types.Cobertura)
+}
+
+// parse coverage report to code coverage tree
+// 1. get file from cloud storage
+// 2. parse
+func (CoberturaParser) ParseCoverage(endpoint, ak, sk, bucket, objectName string) ([]*pb.CodeCoverageNode, error) {
+	client, err := cloudstorage.New(endpoint, ak, sk)
+	if err != nil {
+		return nil, errors.Wrap(err, "get cloud storage client")
+	}
+
+	byteArray, err := client.DownloadFile(bucket, objectName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "download filename=%s", objectName)
+	}
+
+	files, err := Ingest(byteArray)
+	if err != nil {
+		return nil, err
+	}
+	nodes, _ := qaparser.ConvertCoverage(qaparser.NewCodeTestReport(objectName, files))
+	return nodes, nil
+}
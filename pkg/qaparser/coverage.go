@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qaparser
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/erda-project/erda-proto-go/dop/qa/unittest/pb"
+	"github.com/erda-project/erda/apistructs"
+)
+
+// FileCoverage line and branch coverage of a source file
+type FileCoverage struct {
+	// Filename path of the source file, separated by "/"
+	Filename        string
+	LinesCovered    int
+	LinesMissed     int
+	BranchesCovered int
+	BranchesMissed  int
+}
+
+// NewCodeTestReport aggregates the coverage of files by directory as packages of jacoco report,
+// so that the coverage of all languages is shown in the same tree
+func NewCodeTestReport(name string, files []FileCoverage) apistructs.CodeTestReport {
+	report := apistructs.CodeTestReport{Name: name, ProjectName: name}
+	if len(files) == 0 {
+		return report
+	}
+
+	packages := make(map[string]*apistructs.ReportPackage)
+	total := fileCounters{
+		lines:    apistructs.ReportCounter{Type: apistructs.LineCounter},
+		branches: apistructs.ReportCounter{Type: apistructs.BranchCounter},
+		classes:  apistructs.ReportCounter{Type: apistructs.ClassCounter},
+	}
+	for _, f := range files {
+		filename := strings.TrimPrefix(path.Clean(strings.ReplaceAll(f.Filename, "\\", "/")), "/")
+		dir := path.Dir(filename)
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &apistructs.ReportPackage{Name: dir}
+			packages[dir] = pkg
+		}
+		counters := newFileCounters(f)
+		pkg.Classes = append(pkg.Classes, apistructs.ReportClass{
+			Name:           strings.TrimSuffix(filename, path.Ext(filename)),
+			SourceFilename: path.Base(filename),
+			Counters:       counters.toReport(),
+		})
+		pkg.Counters = mergeCounters(pkg.Counters, counters.toReport())
+		total.add(counters)
+	}
+
+	dirs := make([]string, 0, len(packages))
+	for dir := range packages {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		report.Packages = append(report.Packages, *packages[dir])
+	}
+	report.Counters = total.toReport()
+	return report
+}
+
+// ConvertCoverage converts the report to the code coverage tree, returns also the line coverage in percent
+func ConvertCoverage(report apistructs.CodeTestReport) ([]*pb.CodeCoverageNode, float64) {
+	nodes, coverage := apistructs.ConvertReportToTree(report)
+	return convertCoverageNodes(nodes), coverage
+}
+
+func convertCoverageNodes(nodes []*apistructs.CodeCoverageNode) []*pb.CodeCoverageNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	result := make([]*pb.CodeCoverageNode, 0, len(nodes))
+	for _, node := range nodes {
+		value := make([]float32, 0, len(node.Value))
+		for _, v := range node.Value {
+			value = append(value, float32(v))
+		}
+		result = append(result, &pb.CodeCoverageNode{
+			Value:    value,
+			Name:     node.Name,
+			Path:     node.Path,
+			Tooltip:  &pb.ToolTip{Formatter: node.ToolTip.Formatter},
+			Children: convertCoverageNodes(node.Nodes),
+		})
+	}
+	return result
+}
+
+type fileCounters struct {
+	lines, branches, classes apistructs.ReportCounter
+}
+
+func newFileCounters(f FileCoverage) fileCounters {
+	c := fileCounters{
+		lines:    apistructs.ReportCounter{Type: apistructs.LineCounter, Covered: f.LinesCovered, Missed: f.LinesMissed},
+		branches: apistructs.ReportCounter{Type: apistructs.BranchCounter, Covered: f.BranchesCovered, Missed: f.BranchesMissed},
+		classes:  apistructs.ReportCounter{Type: apistructs.ClassCounter},
+	}
+	// a source file is taken as a class, which is covered if any line is covered
+	if f.LinesCovered > 0 {
+		c.classes.Covered = 1
+	} else {
+		c.classes.Missed = 1
+	}
+	return c
+}
+
+func (c *fileCounters) add(o fileCounters) {
+	c.lines.Covered += o.lines.Covered
+	c.lines.Missed += o.lines.Missed
+	c.branches.Covered += o.branches.Covered
+	c.branches.Missed += o.branches.Missed
+	c.classes.Covered += o.classes.Covered
+	c.classes.Missed += o.classes.Missed
+}
+
+func (c fileCounters) toReport() []apistructs.ReportCounter {
+	return []apistructs.ReportCounter{c.lines, c.branches, c.classes}
+}
+
+func mergeCounters(dst, src []apistructs.ReportCounter) []apistructs.ReportCounter {
+	if len(dst) == 0 {
+		return append([]apistructs.ReportCounter{}, src...)
+	}
+	for _, s := range src {
+		for i := range dst {
+			if dst[i].Type == s.Type {
+				dst[i].Covered += s.Covered
+				dst[i].Missed += s.Missed
+			}
+		}
+	}
+	return dst
+}
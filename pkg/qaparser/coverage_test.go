@@ -0,0 +1,51 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qaparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+func TestNewCodeTestReport(t *testing.T) {
+	report := NewCodeTestReport("demo", []FileCoverage{
+		{Filename: "/src/app/main.go", LinesCovered: 8, LinesMissed: 2, BranchesCovered: 1, BranchesMissed: 1},
+		{Filename: "src/app/util.go", LinesMissed: 10},
+		{Filename: "src/api/api.go", LinesCovered: 5, LinesMissed: 5},
+	})
+	assert.Equal(t, "demo", report.ProjectName)
+	assert.Equal(t, 2, len(report.Packages))
+	assert.Equal(t, "src/api", report.Packages[0].Name)
+	assert.Equal(t, "src/app", report.Packages[1].Name)
+	assert.Equal(t, 2, len(report.Packages[1].Classes))
+	assert.Equal(t, "main.go", report.Packages[1].Classes[0].SourceFilename)
+
+	counters := map[apistructs.CounterType]apistructs.ReportCounter{}
+	for _, c := range report.Counters {
+		counters[c.Type] = c
+	}
+	assert.Equal(t, apistructs.ReportCounter{Type: apistructs.LineCounter, Covered: 13, Missed: 17}, counters[apistructs.LineCounter])
+	assert.Equal(t, apistructs.ReportCounter{Type: apistructs.BranchCounter, Covered: 1, Missed: 1}, counters[apistructs.BranchCounter])
+	assert.Equal(t, apistructs.ReportCounter{Type: apistructs.ClassCounter, Covered: 2, Missed: 1}, counters[apistructs.ClassCounter])
+
+	nodes, coverage := ConvertCoverage(report)
+	assert.Equal(t, 1, len(nodes))
+	assert.Equal(t, float64(43.33), coverage)
+	assert.Equal(t, "demo", nodes[0].Name)
+	assert.Equal(t, 2, len(nodes[0].Children))
+}
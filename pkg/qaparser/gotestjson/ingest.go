@@ -0,0 +1,198 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erda-project/erda-proto-go/dop/qa/unittest/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/pkg/qaparser"
+)
+
+// Event is the json event of `go test -json`, see `go doc test2json`
+type Event struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+const (
+	actionPass   = "pass"
+	actionFail   = "fail"
+	actionSkip   = "skip"
+	actionOutput = "output"
+)
+
+type packageResult struct {
+	suite  *pb.TestSuite
+	tests  map[string]*pb.Test
+	output map[string]*strings.Builder
+	// status of the package itself, a failed package without failed tests is a build or setup failure
+	status  string
+	elapsed float64
+}
+
+// Ingest will parse the given `go test -json` output and return one test suite for each package.
+// Lines which are not json events (e.g. build errors printed to stderr) are ignored.
+func Ingest(data []byte) ([]*pb.TestSuite, error) {
+	var (
+		packages = make(map[string]*packageResult)
+		names    []string
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Package == "" {
+			continue
+		}
+
+		pkg, ok := packages[ev.Package]
+		if !ok {
+			pkg = &packageResult{
+				suite:  &pb.TestSuite{Name: ev.Package, Package: ev.Package},
+				tests:  make(map[string]*pb.Test),
+				output: make(map[string]*strings.Builder),
+			}
+			packages[ev.Package] = pkg
+			names = append(names, ev.Package)
+		}
+		pkg.handle(ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	suites := make([]*pb.TestSuite, 0, len(names))
+	for _, name := range names {
+		if suite := packages[name].build(); len(suite.Tests) > 0 {
+			suites = append(suites, suite)
+		}
+	}
+	return suites, nil
+}
+
+func (p *packageResult) handle(ev Event) {
+	if ev.Action == actionOutput {
+		out, ok := p.output[ev.Test]
+		if !ok {
+			out = &strings.Builder{}
+			p.output[ev.Test] = out
+		}
+		out.WriteString(ev.Output)
+		return
+	}
+
+	if ev.Test == "" {
+		switch ev.Action {
+		case actionPass, actionFail, actionSkip:
+			p.status = ev.Action
+			p.elapsed = ev.Elapsed
+		}
+		return
+	}
+
+	test, ok := p.tests[ev.Test]
+	if !ok {
+		test = &pb.Test{
+			Name:      ev.Test,
+			Classname: ev.Package,
+		}
+		p.tests[ev.Test] = test
+		p.suite.Tests = append(p.suite.Tests, test)
+	}
+	switch ev.Action {
+	case actionPass:
+		test.Status = string(apistructs.TestStatusPassed)
+	case actionFail:
+		test.Status = string(apistructs.TestStatusFailed)
+	case actionSkip:
+		test.Status = string(apistructs.TestStatusSkipped)
+	default:
+		return
+	}
+	test.Duration = int64(time.Duration(ev.Elapsed * float64(time.Second)))
+}
+
+func (p *packageResult) build() *pb.TestSuite {
+	var failed bool
+	for _, test := range p.suite.Tests {
+		output := p.outputOf(test.Name)
+		switch test.Status {
+		case string(apistructs.TestStatusFailed):
+			failed = true
+			test.Error = &pb.TestError{Message: firstLine(output), Body: output}
+		case string(apistructs.TestStatusSkipped):
+			test.Error = &pb.TestError{Message: firstLine(output)}
+		case "":
+			// no result event, the test binary exited (panic or timeout) while running
+			failed = true
+			test.Status = string(apistructs.TestStatusError)
+			test.Error = &pb.TestError{Message: "test did not complete", Body: output}
+		default:
+			test.Stdout = output
+		}
+	}
+
+	pkgOutput := p.outputOf("")
+	if p.status == actionFail && !failed {
+		// build failure, TestMain failure or a panic outside of tests
+		p.suite.Tests = append(p.suite.Tests, &pb.Test{
+			Name:      p.suite.Name,
+			Classname: p.suite.Name,
+			Duration:  int64(time.Duration(p.elapsed * float64(time.Second))),
+			Status:    string(apistructs.TestStatusError),
+			Error:     &pb.TestError{Message: firstLine(pkgOutput), Body: pkgOutput},
+		})
+	}
+	p.suite.Stdout = pkgOutput
+
+	(&qaparser.Suite{TestSuite: p.suite}).Aggregate()
+	return p.suite
+}
+
+func (p *packageResult) outputOf(test string) string {
+	if out, ok := p.output[test]; ok {
+		return out.String()
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		// skip the lines printed by testing itself, e.g. "=== RUN   TestX", "--- FAIL: TestX (0.00s)"
+		if line == "" || strings.HasPrefix(line, "===") || strings.HasPrefix(line, "---") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
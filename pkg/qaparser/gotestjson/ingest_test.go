@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotestjson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+func TestIngest(t *testing.T) {
+	demo := `{"Action":"start","Package":"example.com/a"}
+{"Action":"run","Package":"example.com/a","Test":"TestOK"}
+{"Action":"output","Package":"example.com/a","Test":"TestOK","Output":"=== RUN   TestOK\n"}
+{"Action":"output","Package":"example.com/a","Test":"TestOK","Output":"--- PASS: TestOK (0.50s)\n"}
+{"Action":"pass","Package":"example.com/a","Test":"TestOK","Elapsed":0.5}
+{"Action":"run","Package":"example.com/a","Test":"TestFail"}
+{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"=== RUN   TestFail\n"}
+{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"    a_test.go:10: expected 1, got 2\n"}
+{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"--- FAIL: TestFail (0.00s)\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestFail","Elapsed":0}
+{"Action":"run","Package":"example.com/a","Test":"TestSkip"}
+{"Action":"output","Package":"example.com/a","Test":"TestSkip","Output":"    a_test.go:20: flaky on ci\n"}
+{"Action":"skip","Package":"example.com/a","Test":"TestSkip","Elapsed":0}
+{"Action":"output","Package":"example.com/a","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/a","Elapsed":0.6}
+# example.com/b
+b.go:3:1: syntax error
+{"Action":"output","Package":"example.com/b","Output":"FAIL\texample.com/b [build failed]\n"}
+{"Action":"fail","Package":"example.com/b","Elapsed":0}
+{"Action":"output","Package":"example.com/c","Output":"?   \texample.com/c\t[no test files]\n"}
+{"Action":"skip","Package":"example.com/c","Elapsed":0}
+`
+	suites, err := Ingest([]byte(demo))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(suites))
+
+	a := suites[0]
+	assert.Equal(t, "example.com/a", a.Name)
+	assert.Equal(t, int64(3), a.Totals.Tests)
+	assert.Equal(t, int64(1), a.Totals.Statuses[string(apistructs.TestStatusPassed)])
+	assert.Equal(t, int64(1), a.Totals.Statuses[string(apistructs.TestStatusFailed)])
+	assert.Equal(t, int64(1), a.Totals.Statuses[string(apistructs.TestStatusSkipped)])
+	assert.Equal(t, int64(500*time.Millisecond), a.Tests[0].Duration)
+	assert.Equal(t, "a_test.go:10: expected 1, got 2", a.Tests[1].Error.Message)
+	assert.Equal(t, "a_test.go:20: flaky on ci", a.Tests[2].Error.Message)
+
+	b := suites[1]
+	assert.Equal(t, int64(1), b.Totals.Tests)
+	assert.Equal(t, string(apistructs.TestStatusError), b.Tests[0].Status)
+	assert.Equal(t, "FAIL\texample.com/b [build failed]", b.Tests[0].Error.Message)
+}
+
+func TestIngestIncompleteTest(t *testing.T) {
+	demo := `{"Action":"run","Package":"example.com/a","Test":"TestHang"}
+{"Action":"output","Package":"example.com/a","Test":"TestHang","Output":"panic: test timed out after 10m0s\n"}
+{"Action":"fail","Package":"example.com/a","Elapsed":600}
+`
+	suites, err := Ingest([]byte(demo))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(suites))
+	assert.Equal(t, int64(1), suites[0].Totals.Tests)
+	assert.Equal(t, string(apistructs.TestStatusError), suites[0].Tests[0].Status)
+	assert.Contains(t, suites[0].Tests[0].Error.Body, "timed out")
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package junitxml
+
+import (
+	"github.com/erda-project/erda-proto-go/dop/qa/unittest/pb"
+	"github.com/erda-project/erda/apistructs"
+	"github.com/erda-project/erda/pkg/qaparser"
+)
+
+// Ingest will parse the given junit xml data and return a slice of all contained test suites.
+// Nested test suites are flattened, their names are joined with "/".
+func Ingest(data []byte) ([]*pb.TestSuite, error) {
+	nodes, err := qaparser.NodeParse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var suites []*pb.TestSuite
+	findSuites(nodes, "", &suites)
+	return suites, nil
+}
+
+// findSuites performs a depth-first search through the XML document, and
+// ingests all "testsuite" tags including the nested ones.
+func findSuites(nodes []qaparser.XmlNode, prefix string, suites *[]*pb.TestSuite) {
+	for _, node := range nodes {
+		switch node.XMLName.Local {
+		case "testsuite":
+			suite := ingestSuite(node, prefix)
+			if len(suite.Tests) > 0 {
+				*suites = append(*suites, suite)
+			}
+			findSuites(node.Nodes, suite.Name+"/", suites)
+		default:
+			findSuites(node.Nodes, prefix, suites)
+		}
+	}
+}
+
+func ingestSuite(root qaparser.XmlNode, prefix string) *pb.TestSuite {
+	suite := &pb.TestSuite{
+		Name:    prefix + root.Attr("name"),
+		Package: root.Attr("package"),
+	}
+	for _, key := range []string{"hostname", "timestamp", "file"} {
+		if v := root.Attr(key); v != "" {
+			if suite.Extra == nil {
+				suite.Extra = make(map[string]string)
+			}
+			suite.Extra[key] = v
+		}
+	}
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "testcase":
+			test := ingestTestcase(node)
+			if test.Classname == "" {
+				test.Classname = suite.Name
+			}
+			suite.Tests = append(suite.Tests, test)
+		case "properties":
+			suite.Properties = ingestProperties(node)
+		case "system-out":
+			suite.Stdout = string(node.Content)
+		case "system-err":
+			suite.Stderr = string(node.Content)
+		}
+	}
+
+	(&qaparser.Suite{TestSuite: suite}).Aggregate()
+	return suite
+}
+
+func ingestProperties(root qaparser.XmlNode) map[string]string {
+	props := make(map[string]string, len(root.Nodes))
+	for _, node := range root.Nodes {
+		if node.XMLName.Local == "property" {
+			value := node.Attr("value")
+			if value == "" {
+				value = string(node.Content)
+			}
+			props[node.Attr("name")] = value
+		}
+	}
+	return props
+}
+
+func ingestTestcase(root qaparser.XmlNode) *pb.Test {
+	test := &pb.Test{
+		Name:      root.Attr("name"),
+		Classname: root.Attr("classname"),
+		Duration:  int64(qaparser.ParseDuration(root.Attr("time"))),
+		Status:    string(apistructs.TestStatusPassed),
+	}
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "skipped":
+			test.Status = string(apistructs.TestStatusSkipped)
+			if node.Attr("message") != "" || len(node.Content) > 0 {
+				test.Error = ingestError(node)
+			}
+		case "failure":
+			test.Error = ingestError(node)
+			test.Status = string(apistructs.TestStatusFailed)
+		case "error":
+			test.Error = ingestError(node)
+			test.Status = string(apistructs.TestStatusError)
+		case "system-out":
+			test.Stdout = string(node.Content)
+		case "system-err":
+			test.Stderr = string(node.Content)
+		}
+		// flakyFailure, flakyError, rerunFailure and rerunError are retries of a passed or failed test,
+		// the final status is determined by the tags above
+	}
+
+	return test
+}
+
+func ingestError(root qaparser.XmlNode) *pb.TestError {
+	return &pb.TestError{
+		Body:    string(root.Content),
+		Type:    root.Attr("type"),
+		Message: root.Attr("message"),
+	}
+}
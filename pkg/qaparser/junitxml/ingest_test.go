@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package junitxml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda/apistructs"
+)
+
+func TestIngestPytest(t *testing.T) {
+	demo := `<?xml version="1.0" encoding="utf-8"?>
+<testsuites>
+  <testsuite name="pytest" errors="1" failures="1" skipped="1" tests="4" time="0.052" timestamp="2022-05-10T10:00:00" hostname="runner">
+    <testcase classname="tests.test_app" name="test_ok" time="0.001" />
+    <testcase classname="tests.test_app" name="test_fail" time="0.020">
+      <failure message="AssertionError: assert 1 == 2">def test_fail():
+&gt;       assert 1 == 2</failure>
+    </testcase>
+    <testcase classname="tests.test_app" name="test_skip" time="0.000">
+      <skipped type="pytest.skip" message="not ready">tests/test_app.py:10: not ready</skipped>
+    </testcase>
+    <testcase classname="tests.test_app" name="test_setup" time="0.003">
+      <error message="failed on setup with &quot;fixture 'db' not found&quot;" />
+    </testcase>
+  </testsuite>
+</testsuites>`
+	suites, err := Ingest([]byte(demo))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(suites))
+
+	suite := suites[0]
+	assert.Equal(t, "pytest", suite.Name)
+	assert.Equal(t, "runner", suite.Extra["hostname"])
+	assert.Equal(t, int64(4), suite.Totals.Tests)
+	assert.Equal(t, int64(1), suite.Totals.Statuses[string(apistructs.TestStatusPassed)])
+	assert.Equal(t, int64(1), suite.Totals.Statuses[string(apistructs.TestStatusFailed)])
+	assert.Equal(t, int64(1), suite.Totals.Statuses[string(apistructs.TestStatusSkipped)])
+	assert.Equal(t, int64(1), suite.Totals.Statuses[string(apistructs.TestStatusError)])
+
+	assert.Equal(t, int64(20*time.Millisecond), suite.Tests[1].Duration)
+	assert.Equal(t, "AssertionError: assert 1 == 2", suite.Tests[1].Error.Message)
+	assert.Contains(t, suite.Tests[1].Error.Body, ">       assert 1 == 2")
+	assert.Equal(t, "not ready", suite.Tests[2].Error.Message)
+}
+
+func TestIngestNestedSuites(t *testing.T) {
+	demo := `<testsuites name="jest tests" tests="3">
+  <testsuite name="api" tests="1">
+    <testcase name="returns 200" time="0.5"></testcase>
+    <testsuite name="auth" tests="2">
+      <testcase classname="auth login" name="logs in" time="0.1"/>
+      <testcase classname="auth login" name="rejects" time="0.1"><failure>expected 401</failure></testcase>
+    </testsuite>
+  </testsuite>
+  <testsuite name="empty" tests="0"></testsuite>
+</testsuites>`
+	suites, err := Ingest([]byte(demo))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(suites))
+
+	assert.Equal(t, "api", suites[0].Name)
+	assert.Equal(t, int64(1), suites[0].Totals.Tests)
+	assert.Equal(t, "api", suites[0].Tests[0].Classname, "classname defaults to suite name")
+
+	assert.Equal(t, "api/auth", suites[1].Name)
+	assert.Equal(t, int64(2), suites[1].Totals.Tests)
+	assert.Equal(t, string(apistructs.TestStatusFailed), suites[1].Tests[1].Status)
+	assert.Equal(t, "expected 401", suites[1].Tests[1].Error.Body)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lcov
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/erda-project/erda/pkg/qaparser"
+)
+
+// record the coverage of a source file between "SF:" and "end_of_record"
+type record struct {
+	filename string
+	// lines hits of each line, the same line may be reported by several functions
+	lines map[int]int64
+	// branches taken of each branch
+	branches map[string]bool
+}
+
+// Ingest will parse the given lcov tracefile and return the coverage of each source file,
+// records of the same file (e.g. merged tracefiles) are merged.
+func Ingest(data []byte) ([]qaparser.FileCoverage, error) {
+	var (
+		records []*record
+		index   = make(map[string]*record)
+		current *record
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "end_of_record" {
+			current = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if key == "SF" {
+			current, ok = index[value]
+			if !ok {
+				current = &record{filename: value, lines: make(map[int]int64), branches: make(map[string]bool)}
+				index[value] = current
+				records = append(records, current)
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		fields := strings.Split(value, ",")
+		switch key {
+		case "DA":
+			// DA:<line number>,<execution count>[,<checksum>]
+			if len(fields) < 2 {
+				continue
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				continue
+			}
+			hits, _ := strconv.ParseFloat(fields[1], 64)
+			current.lines[number] += int64(hits)
+		case "BRDA":
+			// BRDA:<line number>,<block number>,<branch number>,<taken>, taken is "-" if never executed
+			if len(fields) < 4 {
+				continue
+			}
+			id := strings.Join(fields[:3], ",")
+			taken, _ := strconv.ParseFloat(fields[3], 64)
+			current.branches[id] = current.branches[id] || taken > 0
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	files := make([]qaparser.FileCoverage, 0, len(records))
+	for _, r := range records {
+		f := qaparser.FileCoverage{Filename: r.filename}
+		for _, hits := range r.lines {
+			if hits > 0 {
+				f.LinesCovered++
+			} else {
+				f.LinesMissed++
+			}
+		}
+		for _, taken := range r.branches {
+			if taken {
+				f.BranchesCovered++
+			} else {
+				f.BranchesMissed++
+			}
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
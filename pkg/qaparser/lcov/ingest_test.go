@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lcov
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngest(t *testing.T) {
+	demo := `TN:
+SF:src/app.js
+FN:1,main
+FNDA:1,main
+DA:1,1
+DA:2,0
+DA:3,5
+BRDA:3,0,0,2
+BRDA:3,0,1,-
+LF:3
+LH:2
+BRF:2
+BRH:1
+end_of_record
+SF:src/util/math.js
+DA:1,0
+end_of_record
+SF:src/app.js
+DA:2,1
+BRDA:3,0,1,1
+end_of_record
+`
+	files, err := Ingest([]byte(demo))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(files))
+
+	assert.Equal(t, "src/app.js", files[0].Filename)
+	assert.Equal(t, 3, files[0].LinesCovered, "records of the same file are merged")
+	assert.Equal(t, 0, files[0].LinesMissed)
+	assert.Equal(t, 2, files[0].BranchesCovered)
+	assert.Equal(t, 0, files[0].BranchesMissed)
+
+	assert.Equal(t, "src/util/math.js", files[1].Filename)
+	assert.Equal(t, 0, files[1].LinesCovered)
+	assert.Equal(t, 1, files[1].LinesMissed)
+}
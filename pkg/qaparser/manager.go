@@ -29,8 +29,18 @@ type Parser interface {
 	Register()
 }
 
+// CoverageParser parses the coverage report to the code coverage tree
+type CoverageParser interface {
+	ParseCoverage(endpoint, ak, sk, bucket, objectName string) ([]*pb.CodeCoverageNode, error)
+	Register()
+}
+
 type Manager struct {
 	parsers map[types.TestParserType]Parser
+	// fallbackParsers are used if no parser is registered with the type,
+	// e.g. junit reports are parsed by surefire parser if junit parser is not imported
+	fallbackParsers map[types.TestParserType]Parser
+	coverageParsers map[types.TestParserType]CoverageParser
 }
 
 func GetManager() *Manager {
@@ -39,18 +49,24 @@ func GetManager() *Manager {
 
 func init() {
 	m = Manager{
-		parsers: map[types.TestParserType]Parser{},
+		parsers:         map[types.TestParserType]Parser{},
+		fallbackParsers: map[types.TestParserType]Parser{},
+		coverageParsers: map[types.TestParserType]CoverageParser{},
 	}
 
 	logrus.Info(">>> init parser manager finished <<<")
 }
 
 func (m *Manager) GetParser(t types.TestParserType) Parser {
-
-	if _, ok := m.parsers[t]; !ok {
-		logrus.Errorf(">>> not found test type=%s <<<", t)
+	if p, ok := m.parsers[t]; ok {
+		return p
+	}
+	if p, ok := m.fallbackParsers[t]; ok {
+		logrus.Infof(">>> use fallback parser of test type=%s <<<", t)
+		return p
 	}
-	return m.parsers[t]
+	logrus.Errorf(">>> not found test type=%s <<<", t)
+	return nil
 }
 
 func Register(p Parser, types ...types.TestParserType) error {
@@ -64,3 +80,35 @@ func Register(p Parser, types ...types.TestParserType) error {
 
 	return nil
 }
+
+// RegisterFallback registers the parser used for types only if no parser is registered by Register
+func RegisterFallback(p Parser, types ...types.TestParserType) error {
+	for _, t := range types {
+		if _, ok := m.fallbackParsers[t]; ok {
+			return errors.Errorf("duplicated fallback type=%s", t.TPValue())
+		}
+		logrus.Infof(">>> register fallback type=%s to parser manager success <<<", t)
+		m.fallbackParsers[t] = p
+	}
+
+	return nil
+}
+
+func (m *Manager) GetCoverageParser(t types.TestParserType) CoverageParser {
+	if _, ok := m.coverageParsers[t]; !ok {
+		logrus.Errorf(">>> not found coverage type=%s <<<", t)
+	}
+	return m.coverageParsers[t]
+}
+
+func RegisterCoverage(p CoverageParser, types ...types.TestParserType) error {
+	for _, t := range types {
+		if _, ok := m.coverageParsers[t]; ok {
+			return errors.Errorf("duplicated coverage type=%s", t.TPValue())
+		}
+		logrus.Infof(">>> register coverage type=%s to parser manager success <<<", t)
+		m.coverageParsers[t] = p
+	}
+
+	return nil
+}
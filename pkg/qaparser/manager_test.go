@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Terminus, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qaparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/erda-project/erda-proto-go/dop/qa/unittest/pb"
+	"github.com/erda-project/erda/pkg/qaparser/types"
+)
+
+type fakeParser struct {
+	name string
+}
+
+func (fakeParser) Parse(endpoint, ak, sk, bucket, objectName string) ([]*pb.TestSuite, error) {
+	return nil, nil
+}
+
+func (fakeParser) Register() {}
+
+func TestManager_GetParser(t *testing.T) {
+	tp := types.TestParserType("FAKE")
+
+	assert.Nil(t, GetManager().GetParser(tp))
+
+	assert.NoError(t, RegisterFallback(fakeParser{name: "fallback"}, tp))
+	assert.Error(t, RegisterFallback(fakeParser{name: "fallback"}, tp))
+	assert.Equal(t, fakeParser{name: "fallback"}, GetManager().GetParser(tp))
+
+	// the registered parser takes precedence over the fallback one, regardless of the order of registration
+	assert.NoError(t, Register(fakeParser{name: "primary"}, tp))
+	assert.Equal(t, fakeParser{name: "primary"}, GetManager().GetParser(tp))
+}
@@ -36,7 +36,9 @@ func init() {
 }
 
 func (d DefaultParser) Register() {
-	qaparser.Register(d, types.Default, types.JUnit)
+	qaparser.Register(d, types.Default)
+	// junit reports are parsed by junitxml parser if it is imported
+	qaparser.RegisterFallback(d, types.JUnit)
 }
 
 func (DefaultParser) Parse(endpoint, ak, sk, bucket, objectName string) ([]*pb.TestSuite, error) {
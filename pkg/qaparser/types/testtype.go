@@ -22,8 +22,18 @@ const (
 	Default TestParserType = "DEFAULT"
 	// 使用 testng 插件 org.testng.reporters.XMLReporter 生成的格式进行解析, 需要在 pom.xml 中配置该 reporter
 	NGTest TestParserType = "NGTEST"
-	// 使用 junit 生成的 xml 格式进行解析
+	// 使用通用 junit xml 格式进行解析, 支持 pytest、jest-junit、gotestsum 等生成的报告
 	JUnit TestParserType = "JUNIT"
+	// 使用 go test -json 输出的事件流进行解析
+	GoTestJSON TestParserType = "GOTEST_JSON"
+)
+
+// 覆盖率报告格式
+const (
+	// 使用 cobertura xml 格式进行解析, 如 coverage.py、gocover-cobertura 生成的 coverage.xml
+	Cobertura TestParserType = "COBERTURA"
+	// 使用 lcov tracefile 格式进行解析, 如 jest、c8 生成的 lcov.info
+	LCOV TestParserType = "LCOV"
 )
 
 func (t TestParserType) TPValue() string {
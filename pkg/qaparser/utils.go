@@ -24,6 +24,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -186,3 +187,16 @@ func NodeParse(data []byte) ([]XmlNode, error) {
 
 	return root.Nodes, nil
 }
+
+// ParseDuration parses the duration attribute of test reports, which is seconds in decimal
+// (e.g. "0.012") or a go duration string (e.g. "12ms")
+func ParseDuration(t string) time.Duration {
+	t = strings.ReplaceAll(strings.TrimSpace(t), ",", "")
+	if s, err := strconv.ParseFloat(t, 64); err == nil {
+		return time.Duration(s*1000000) * time.Microsecond
+	}
+	if d, err := time.ParseDuration(t); err == nil {
+		return d
+	}
+	return 0
+}